@@ -0,0 +1,10 @@
+//go:build !windows
+
+package main
+
+import "net"
+
+// dialPipe connects to the pipe that the client listens on, which is a Unix socket.
+func dialPipe(name string) (net.Conn, error) {
+	return net.Dial("unix", name)
+}
@@ -0,0 +1,12 @@
+package main
+
+import (
+	"net"
+
+	"github.com/Microsoft/go-winio"
+)
+
+// dialPipe connects to the pipe that the client listens on, which is a Windows named pipe.
+func dialPipe(name string) (net.Conn, error) {
+	return winio.DialPipe(name, nil)
+}
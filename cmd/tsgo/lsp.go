@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"net"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/microsoft/typescript-go/internal/bundled"
 	"github.com/microsoft/typescript-go/internal/core"
@@ -14,21 +19,29 @@ import (
 	"github.com/microsoft/typescript-go/internal/vfs/osvfs"
 )
 
+// clientProcessPollInterval is how often the server checks whether the client process is alive.
+const clientProcessPollInterval = 2 * time.Second
+
 func runLSP(args []string) int {
 	flag := flag.NewFlagSet("lsp", flag.ContinueOnError)
 	stdio := flag.Bool("stdio", false, "use stdio for communication")
 	pprofDir := flag.String("pprofDir", "", "Generate pprof CPU/memory profiles to the given directory.")
-	pipe := flag.String("pipe", "", "use named pipe for communication")
-	_ = pipe
-	socket := flag.String("socket", "", "use socket for communication")
-	_ = socket
+	pipe := flag.String("pipe", "", "connect to the client's named pipe for communication")
+	socket := flag.String("socket", "", "listen for the client on a socket: a port, which binds to 127.0.0.1 only, a host:port, or the path of a Unix socket")
+	clientProcessID := flag.Int("clientProcessId", 0, "exit when the client process with the given id exits")
 	if err := flag.Parse(args); err != nil {
 		return 2
 	}
 
-	if !*stdio {
-		fmt.Fprintln(os.Stderr, "only stdio is supported")
-		return 1
+	transports := 0
+	for _, enabled := range []bool{*stdio, *pipe != "", *socket != ""} {
+		if enabled {
+			transports++
+		}
+	}
+	if transports != 1 {
+		fmt.Fprintln(os.Stderr, "exactly one of --stdio, --pipe or --socket must be given")
+		return 2
 	}
 
 	if *pprofDir != "" {
@@ -37,20 +50,91 @@ func runLSP(args []string) int {
 		defer profileSession.Stop()
 	}
 
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	if *clientProcessID != 0 {
+		go watchClientProcess(ctx, cancel, *clientProcessID)
+	}
+
+	var in io.Reader = os.Stdin
+	var out io.Writer = os.Stdout
+	if !*stdio {
+		var conn net.Conn
+		var err error
+		if *pipe != "" {
+			conn, err = dialPipe(*pipe)
+		} else {
+			conn, err = acceptClient(socketAddress(*socket))
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer conn.Close()
+		in, out = conn, conn
+	}
+
 	fs := bundled.WrapFS(osvfs.FS())
 	defaultLibraryPath := bundled.LibPath()
 
 	s := lsp.NewServer(&lsp.ServerOptions{
-		In:                 os.Stdin,
-		Out:                os.Stdout,
+		In:                 in,
+		Out:                out,
 		Err:                os.Stderr,
 		Cwd:                core.Must(os.Getwd()),
 		FS:                 fs,
 		DefaultLibraryPath: defaultLibraryPath,
 	})
 
-	if err := s.Run(); err != nil && !errors.Is(err, io.EOF) {
+	if err := s.Run(ctx); err != nil && !errors.Is(err, io.EOF) {
+		fmt.Fprintln(os.Stderr, err)
 		return 1
 	}
 	return 0
 }
+
+// socketAddress returns the network and address to listen on for --socket, which is a TCP port on
+// the loopback interface, a host and port, or the path of a Unix socket. A bare port only binds to
+// the loopback interface so that the server is not reachable from other machines unless asked.
+func socketAddress(socket string) (network string, address string) {
+	if _, err := strconv.ParseUint(socket, 10, 16); err == nil {
+		return "tcp", net.JoinHostPort("127.0.0.1", socket)
+	}
+	if _, _, err := net.SplitHostPort(socket); err == nil && !strings.ContainsAny(socket, `/\`) {
+		return "tcp", socket
+	}
+	return "unix", socket
+}
+
+// acceptClient listens on the given address until a client connects, and returns the connection.
+// There is only ever one client, so the listener is closed once it has connected.
+func acceptClient(network string, address string) (net.Conn, error) {
+	if network == "unix" && strings.HasPrefix(address, `\\.\pipe\`) {
+		return nil, fmt.Errorf("cannot listen on %s: use --pipe to connect to a Windows named pipe", address)
+	}
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	defer listener.Close()
+	fmt.Fprintf(os.Stderr, "listening for a client on %v\n", listener.Addr())
+	return listener.Accept()
+}
+
+// watchClientProcess cancels the server when the client process exits, so that the server does not
+// outlive a client that died without shutting it down.
+func watchClientProcess(ctx context.Context, cancel context.CancelCauseFunc, pid int) {
+	ticker := time.NewTicker(clientProcessPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !processExists(pid) {
+				cancel(fmt.Errorf("client process %d has exited", pid))
+				return
+			}
+		}
+	}
+}
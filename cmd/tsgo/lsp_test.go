@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSocketAddress(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		socket  string
+		network string
+		address string
+	}{
+		{"8080", "tcp", "127.0.0.1:8080"},
+		{"0", "tcp", "127.0.0.1:0"},
+		{"localhost:8080", "tcp", "localhost:8080"},
+		{"0.0.0.0:8080", "tcp", "0.0.0.0:8080"},
+		{":8080", "tcp", ":8080"},
+		{"[::1]:8080", "tcp", "[::1]:8080"},
+		{"65536", "unix", "65536"},
+		{"/tmp/tsgo.sock", "unix", "/tmp/tsgo.sock"},
+		{"tsgo.sock", "unix", "tsgo.sock"},
+		{"/tmp/host:8080", "unix", "/tmp/host:8080"},
+		{`C:\tmp\host:8080`, "unix", `C:\tmp\host:8080`},
+		{`\\.\pipe\tsgo`, "unix", `\\.\pipe\tsgo`},
+	}
+	for _, test := range tests {
+		t.Run(test.socket, func(t *testing.T) {
+			t.Parallel()
+			network, address := socketAddress(test.socket)
+			assert.Equal(t, network, test.network)
+			assert.Equal(t, address, test.address)
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/microsoft/typescript-go/internal/tspath"
 	"github.com/microsoft/typescript-go/internal/vfs"
 	"github.com/microsoft/typescript-go/internal/vfs/osvfs"
+	"github.com/microsoft/typescript-go/internal/vfs/vfswatch"
 )
 
 type osSys struct {
@@ -43,6 +44,10 @@ func (s *osSys) NewLine() string {
 	return s.newLine
 }
 
+func (s *osSys) NewFileWatcher(options *core.WatchOptions) vfswatch.Watcher {
+	return vfswatch.New(s.fs, options)
+}
+
 func (s *osSys) Writer() io.Writer {
 	return s.writer
 }
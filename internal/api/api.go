@@ -11,6 +11,7 @@ import (
 	"github.com/microsoft/typescript-go/internal/astnav"
 	"github.com/microsoft/typescript-go/internal/checker"
 	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/ls"
 	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
 	"github.com/microsoft/typescript-go/internal/project"
 	"github.com/microsoft/typescript-go/internal/tsoptions"
@@ -104,6 +105,10 @@ func (api *API) OnDiscoveredSymlink(info *project.ScriptInfo) {
 	// !!!
 }
 
+// OnProjectGraphUpdated implements ProjectHost. The API does not watch files.
+func (api *API) OnProjectGraphUpdated(p *project.Project) {
+}
+
 // Log implements ProjectHost.
 func (api *API) Log(s string) {
 	api.options.Logger.Info(s)
@@ -119,6 +124,16 @@ func (api *API) PositionEncoding() lsproto.PositionEncodingKind {
 	return lsproto.PositionEncodingKindUTF8
 }
 
+// FormatCodeSettings implements ProjectHost.
+func (api *API) FormatCodeSettings() *ls.FormatCodeSettings {
+	return ls.GetDefaultFormatCodeSettings(api.NewLine())
+}
+
+// UserPreferences implements ProjectHost.
+func (api *API) UserPreferences() *ls.UserPreferences {
+	return ls.GetDefaultUserPreferences()
+}
+
 func (api *API) HandleRequest(id int, method string, payload []byte) ([]byte, error) {
 	params, err := unmarshalPayload(method, payload)
 	if err != nil {
@@ -268,12 +268,7 @@ func (s *Server) handleConfigure(payload []byte) error {
 			return err
 		}
 	}
-	if params.LogFile != "" {
-		s.logger.SetFile(params.LogFile)
-	} else {
-		s.logger.SetFile("")
-	}
-	return nil
+	return s.logger.SetFile(params.LogFile)
 }
 
 func (s *Server) sendResponse(method string, result []byte) error {
@@ -5687,6 +5687,10 @@ func (node *AsExpression) propagateSubtreeFacts() SubtreeFacts {
 	return node.SubtreeFacts() & ^SubtreeExclusionsOuterExpression
 }
 
+func IsAsExpression(node *Node) bool {
+	return node.Kind == KindAsExpression
+}
+
 // SatisfiesExpression
 
 type SatisfiesExpression struct {
@@ -247,6 +247,10 @@ func IsTokenKind(token Kind) bool {
 	return KindFirstToken <= token && token <= KindLastToken
 }
 
+func IsJsxOpeningLikeElement(node *Node) bool {
+	return IsJsxOpeningElement(node) || IsJsxSelfClosingElement(node)
+}
+
 func IsAccessor(node *Node) bool {
 	return node.Kind == KindGetAccessor || node.Kind == KindSetAccessor
 }
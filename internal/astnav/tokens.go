@@ -246,3 +246,80 @@ func visitEachChildAndJSDoc(node *ast.Node, sourceFile *ast.SourceFile, visitor
 	}
 	node.VisitEachChild(visitor)
 }
+
+// FindPrecedingToken returns the last token that starts before the given position,
+// or nil if there is none. A token containing the position is also returned.
+// As with GetTokenAtPosition, tokens that are not stored in the AST are synthesized
+// with the scanner and cached on the source file.
+func FindPrecedingToken(sourceFile *ast.SourceFile, position int) *ast.Node {
+	return findPrecedingToken(sourceFile.AsNode(), sourceFile, position)
+}
+
+func findPrecedingToken(node *ast.Node, sourceFile *ast.SourceFile, position int) *ast.Node {
+	if ast.IsTokenKind(node.Kind) {
+		return node
+	}
+
+	// `prev` is the last child that ends at or before the position, and `containing`
+	// is the child whose first token starts before the position and that ends after it.
+	var prev, containing *ast.Node
+	visitNode := func(child *ast.Node, _ *ast.NodeVisitor) *ast.Node {
+		if child == nil || child.Flags&ast.NodeFlagsReparsed != 0 || containing != nil || child.End() == child.Pos() {
+			return child
+		}
+		if child.End() <= position {
+			prev = child
+		} else if scanner.GetTokenPosOfNode(child, sourceFile, false /*includeJSDoc*/) < position {
+			containing = child
+		}
+		return child
+	}
+	visitNodeList := func(nodeList *ast.NodeList, visitor *ast.NodeVisitor) *ast.NodeList {
+		if nodeList != nil {
+			for _, child := range nodeList.Nodes {
+				visitNode(child, visitor)
+			}
+		}
+		return nodeList
+	}
+	nodeVisitor := ast.NewNodeVisitor(core.Identity, nil, ast.NodeVisitorHooks{
+		VisitNode:  visitNode,
+		VisitToken: visitNode,
+		VisitNodes: visitNodeList,
+		VisitModifiers: func(modifiers *ast.ModifierList, visitor *ast.NodeVisitor) *ast.ModifierList {
+			if modifiers != nil {
+				visitNodeList(&modifiers.NodeList, visitor)
+			}
+			return modifiers
+		},
+	})
+	node.VisitEachChild(nodeVisitor)
+
+	if containing != nil {
+		if result := findPrecedingToken(containing, sourceFile, position); result != nil {
+			return result
+		}
+	}
+
+	// Tokens such as punctuation are not stored in the AST, so scan the gap between
+	// the previous child (or the start of the node) and the position for them.
+	left := node.Pos()
+	if prev != nil {
+		left = prev.End()
+	}
+	var lastKind ast.Kind
+	lastFullStart, lastEnd := -1, -1
+	s := scanner.GetScannerForSourceFile(sourceFile, left)
+	for s.Token() != ast.KindEndOfFile && s.TokenStart() < position && s.TokenStart() < node.End() {
+		lastKind, lastFullStart, lastEnd = s.Token(), s.TokenFullStart(), s.TokenEnd()
+		s.Scan()
+	}
+	if lastFullStart >= 0 && !(lastKind == ast.KindIdentifier || !ast.IsTokenKind(lastKind)) {
+		return sourceFile.GetOrCreateToken(lastKind, lastFullStart, lastEnd, node)
+	}
+
+	if prev != nil {
+		return findPrecedingToken(prev, sourceFile, prev.End())
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package checker
 
 import (
+	"context"
 	"fmt"
 	"iter"
 	"maps"
@@ -538,6 +539,8 @@ var nextCheckerID atomic.Uint32
 type Checker struct {
 	id                                         uint32
 	program                                    Program
+	ctx                                        context.Context
+	wasCanceled                                bool
 	host                                       Host
 	compilerOptions                            *core.CompilerOptions
 	files                                      []*ast.SourceFile
@@ -608,6 +611,7 @@ type Checker struct {
 	intersectionTypes                          map[string]*Type
 	diagnostics                                ast.DiagnosticsCollection
 	suggestionDiagnostics                      ast.DiagnosticsCollection
+	reportingUnusedAsSuggestions               bool
 	symbolPool                                 core.Pool[ast.Symbol]
 	signaturePool                              core.Pool[Signature]
 	indexInfoPool                              core.Pool[IndexInfo]
@@ -2034,13 +2038,23 @@ func (c *Checker) getSymbol(symbols ast.SymbolTable, name string, meaning ast.Sy
 	return nil
 }
 
-func (c *Checker) CheckSourceFile(sourceFile *ast.SourceFile) {
+// CheckSourceFile checks a file, stopping early if ctx is canceled. A checker that stopped early
+// has not reported all the errors of the file and will not, so it should be discarded; see
+// WasCanceled.
+func (c *Checker) CheckSourceFile(ctx context.Context, sourceFile *ast.SourceFile) {
 	if SkipTypeChecking(sourceFile, c.compilerOptions) {
 		return
 	}
+	c.ctx = ctx
+	defer func() { c.ctx = nil }()
 	c.checkSourceFile(sourceFile)
 }
 
+// WasCanceled reports whether the checker stopped checking a file early because it was canceled.
+func (c *Checker) WasCanceled() bool {
+	return c.wasCanceled
+}
+
 func (c *Checker) checkSourceFile(sourceFile *ast.SourceFile) {
 	links := c.sourceFileLinks.Get(sourceFile)
 	if !links.typeChecked {
@@ -2048,6 +2062,9 @@ func (c *Checker) checkSourceFile(sourceFile *ast.SourceFile) {
 		c.checkGrammarSourceFile(sourceFile)
 		c.renamedBindingElementsInTypes = nil
 		c.checkSourceElements(sourceFile.Statements.Nodes)
+		if c.wasCanceled {
+			return
+		}
 		c.checkDeferredNodes(sourceFile)
 		c.checkJSDocNodes(sourceFile)
 		if ast.IsExternalOrCommonJSModule(sourceFile) {
@@ -2083,8 +2100,18 @@ func (c *Checker) checkSourceElement(node *ast.Node) bool {
 }
 
 func (c *Checker) checkSourceElementWorker(node *ast.Node) {
-	// !!! Cancellation
 	kind := node.Kind
+	if c.ctx != nil {
+		// Only check for cancellation at a few kinds of declarations, as checking on every node
+		// would be too costly.
+		switch kind {
+		case ast.KindModuleDeclaration, ast.KindClassDeclaration, ast.KindInterfaceDeclaration, ast.KindFunctionDeclaration:
+			if c.wasCanceled || c.ctx.Err() != nil {
+				c.wasCanceled = true
+				return
+			}
+		}
+	}
 	if kind >= ast.KindFirstStatement && kind <= ast.KindLastStatement {
 		flowNode := node.FlowNodeData().FlowNode
 		if flowNode != nil && !c.isReachableFlowNode(flowNode) {
@@ -5308,8 +5335,44 @@ func isNotOverload(node *ast.Node) bool {
 	return !ast.IsFunctionDeclaration(node) && !ast.IsMethodDeclaration(node) || node.Body() != nil
 }
 
-func (c *Checker) collectLinkedAliases(node *ast.Node, setVisibility bool) {
-	// !!! Implement if declaration emit needs it
+func (c *Checker) collectLinkedAliases(node *ast.Node, setVisibility bool) []*ast.Node {
+	var exportSymbol *ast.Symbol
+	if node.Kind != ast.KindStringLiteral && node.Parent != nil && node.Parent.Kind == ast.KindExportAssignment {
+		exportSymbol = c.resolveName(node, node.Text(), ast.SymbolFlagsValue|ast.SymbolFlagsType|ast.SymbolFlagsNamespace|ast.SymbolFlagsAlias, nil /*nameNotFoundMessage*/, false /*isUse*/, false /*excludeGlobals*/)
+	} else if node.Parent != nil && node.Parent.Kind == ast.KindExportSpecifier {
+		exportSymbol = c.getTargetOfExportSpecifier(node.Parent, ast.SymbolFlagsValue|ast.SymbolFlagsType|ast.SymbolFlagsNamespace|ast.SymbolFlagsAlias, false /*dontResolveAlias*/)
+	}
+	if exportSymbol == nil {
+		return nil
+	}
+	var result []*ast.Node
+	visited := core.Set[*ast.Symbol]{}
+	visited.Add(exportSymbol)
+	var buildVisibleNodeList func(declarations []*ast.Node)
+	buildVisibleNodeList = func(declarations []*ast.Node) {
+		for _, declaration := range declarations {
+			resultNode := getAnyImportSyntax(declaration)
+			if resultNode == nil {
+				resultNode = declaration
+			}
+			if setVisibility {
+				c.nodeLinks.Get(declaration).isVisible = core.TSTrue
+			} else if !slices.Contains(result, resultNode) {
+				result = append(result, resultNode)
+			}
+			if ast.IsInternalModuleImportEqualsDeclaration(declaration) {
+				// Add the referenced top container visible
+				firstIdentifier := ast.GetFirstIdentifier(declaration.AsImportEqualsDeclaration().ModuleReference)
+				importSymbol := c.resolveName(declaration, firstIdentifier.Text(), ast.SymbolFlagsValue|ast.SymbolFlagsType|ast.SymbolFlagsNamespace, nil /*nameNotFoundMessage*/, false /*isUse*/, false /*excludeGlobals*/)
+				if importSymbol != nil && !visited.Has(importSymbol) {
+					visited.Add(importSymbol)
+					buildVisibleNodeList(importSymbol.Declarations)
+				}
+			}
+		}
+	}
+	buildVisibleNodeList(exportSymbol.Declarations)
+	return result
 }
 
 func (c *Checker) checkMissingDeclaration(node *ast.Node) {
@@ -6544,9 +6607,17 @@ func (c *Checker) reportUnusedVariable(location *ast.Node, diagnostic *ast.Diagn
 }
 
 func (c *Checker) reportUnused(location *ast.Node, kind UnusedKind, diagnostic *ast.Diagnostic) {
-	if location.Flags&(ast.NodeFlagsAmbient|ast.NodeFlagsThisNodeOrAnySubNodesHasError) == 0 &&
-		(kind == UnusedKindLocal && c.compilerOptions.NoUnusedLocals.IsTrue() ||
-			(kind == UnusedKindParameter && c.compilerOptions.NoUnusedParameters.IsTrue())) {
+	if location.Flags&(ast.NodeFlagsAmbient|ast.NodeFlagsThisNodeOrAnySubNodesHasError) != 0 {
+		return
+	}
+	isError := kind == UnusedKindLocal && c.compilerOptions.NoUnusedLocals.IsTrue() ||
+		kind == UnusedKindParameter && c.compilerOptions.NoUnusedParameters.IsTrue()
+	if c.reportingUnusedAsSuggestions {
+		// Declarations that are not reported as errors are reported as suggestions instead.
+		if !isError {
+			c.addErrorOrSuggestion(false /*isError*/, diagnostic)
+		}
+	} else if isError {
 		c.diagnostics.Add(diagnostic)
 	}
 }
@@ -13070,17 +13141,34 @@ func (c *Checker) getCannotFindNameDiagnosticForName(node *ast.Node) *diagnostic
 	}
 }
 
-func (c *Checker) GetDiagnostics(sourceFile *ast.SourceFile) []*ast.Diagnostic {
+func (c *Checker) GetDiagnostics(ctx context.Context, sourceFile *ast.SourceFile) []*ast.Diagnostic {
 	if sourceFile != nil {
-		c.CheckSourceFile(sourceFile)
+		c.CheckSourceFile(ctx, sourceFile)
 		return c.diagnostics.GetDiagnosticsForFile(sourceFile.FileName())
 	}
 	for _, file := range c.files {
-		c.CheckSourceFile(file)
+		c.CheckSourceFile(ctx, file)
 	}
 	return c.diagnostics.GetDiagnostics()
 }
 
+// GetSuggestionDiagnostics checks a file and returns its suggestions, which include the unused
+// declarations that the compiler options do not make errors.
+func (c *Checker) GetSuggestionDiagnostics(ctx context.Context, sourceFile *ast.SourceFile) []*ast.Diagnostic {
+	c.CheckSourceFile(ctx, sourceFile)
+	links := c.sourceFileLinks.Get(sourceFile)
+	if !links.typeChecked {
+		return nil
+	}
+	if !links.unusedSuggestionsChecked && !sourceFile.IsDeclarationFile {
+		links.unusedSuggestionsChecked = true
+		c.reportingUnusedAsSuggestions = true
+		c.checkUnusedIdentifiers(links.identifierCheckNodes)
+		c.reportingUnusedAsSuggestions = false
+	}
+	return c.suggestionDiagnostics.GetDiagnosticsForFile(sourceFile.FileName())
+}
+
 func (c *Checker) GetDiagnosticsWithoutCheck(sourceFile *ast.SourceFile) []*ast.Diagnostic {
 	return c.diagnostics.GetDiagnosticsForFile(sourceFile.FileName())
 }
@@ -29355,9 +29443,11 @@ func (c *Checker) getSymbolAtLocation(node *ast.Node, ignoreErrors bool) *ast.Sy
 	case ast.KindStringLiteral, ast.KindNoSubstitutionTemplateLiteral:
 		// 1). import x = require("./mo/*gotToDefinitionHere*/d")
 		// 2). External module name in an import declaration
+		// 3). require("./mod") in a JavaScript file, or import("./mod")
 		// 4). type A = import("./f/*gotToDefinitionHere*/oo")
 		if (ast.IsExternalModuleImportEqualsDeclaration(grandParent) && getExternalModuleImportEqualsDeclarationExpression(grandParent) == node) ||
-			((parent.Kind == ast.KindImportDeclaration || parent.Kind == ast.KindExportDeclaration) && parent.AsImportDeclaration().ModuleSpecifier == node) ||
+			((parent.Kind == ast.KindImportDeclaration || parent.Kind == ast.KindExportDeclaration) && ast.GetExternalModuleName(parent) == node) ||
+			(ast.IsInJSFile(node) && ast.IsRequireCall(parent, false /*requireStringLiteralLikeArgument*/) || ast.IsImportCall(parent)) ||
 			(ast.IsLiteralTypeNode(parent) && ast.IsLiteralImportTypeNode(grandParent) && grandParent.AsImportTypeNode().Argument == parent) {
 			return c.resolveExternalModuleName(node, node, ignoreErrors)
 		}
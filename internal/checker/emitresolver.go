@@ -1,11 +1,13 @@
 package checker
 
 import (
+	"slices"
 	"sync"
 
 	"github.com/microsoft/typescript-go/internal/ast"
 	"github.com/microsoft/typescript-go/internal/binder"
 	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/evaluator"
 	"github.com/microsoft/typescript-go/internal/printer"
 )
 
@@ -164,6 +166,219 @@ func (r *emitResolver) GetExternalModuleFileFromDeclaration(node *ast.Node) *ast
 	return nil
 }
 
+func (r *emitResolver) IsDeclarationVisible(node *ast.Node) bool {
+	if !ast.IsParseTreeNode(node) {
+		return false
+	}
+
+	r.checkerMu.Lock()
+	defer r.checkerMu.Unlock()
+
+	return r.checker.isDeclarationVisible(node)
+}
+
+func (r *emitResolver) IsSymbolAccessible(symbol *ast.Symbol, enclosingDeclaration *ast.Node, meaning ast.SymbolFlags, shouldComputeAliasToMakeVisible bool) printer.SymbolAccessibilityResult {
+	r.checkerMu.Lock()
+	defer r.checkerMu.Unlock()
+
+	return r.checker.isSymbolAccessible(symbol, enclosingDeclaration, meaning, shouldComputeAliasToMakeVisible)
+}
+
+func (r *emitResolver) IsEntityNameVisible(entityName *ast.Node, enclosingDeclaration *ast.Node) printer.SymbolAccessibilityResult {
+	if !ast.IsParseTreeNode(entityName) {
+		return printer.SymbolAccessibilityResult{Accessibility: printer.SymbolAccessibilityAccessible}
+	}
+
+	r.checkerMu.Lock()
+	defer r.checkerMu.Unlock()
+
+	return r.checker.isEntityNameVisible(entityName, enclosingDeclaration)
+}
+
+func (r *emitResolver) IsImplementationOfOverload(node *ast.SignatureDeclaration) bool {
+	if !ast.IsParseTreeNode(node) || ast.NodeIsMissing(node.Body()) || ast.IsGetAccessorDeclaration(node) || ast.IsSetAccessorDeclaration(node) {
+		return false
+	}
+
+	r.checkerMu.Lock()
+	defer r.checkerMu.Unlock()
+
+	c := r.checker
+	symbol := c.getSymbolOfDeclaration(node)
+	signaturesOfSymbol := c.getSignaturesOfSymbol(symbol)
+	// If this function body corresponds to function with multiple signature, it is implementation of overload
+	// e.g.: function foo(a: string): string;
+	//       function foo(a: number): number;
+	//       function foo(a: any) { // This is implementation of the overloads
+	//           return a;
+	//       }
+	return len(signaturesOfSymbol) > 1 ||
+		// If there is single signature for the symbol, it is overload if that signature isn't coming from the node
+		// e.g.: function foo(a: string): string;
+		//       function foo(a: any) { // This is implementation of the overloads
+		//           return a;
+		//       }
+		(len(signaturesOfSymbol) == 1 && signaturesOfSymbol[0].declaration != node)
+}
+
+func (r *emitResolver) IsOptionalParameter(node *ast.ParameterDeclarationNode) bool {
+	if !ast.IsParseTreeNode(node) {
+		return false
+	}
+
+	r.checkerMu.Lock()
+	defer r.checkerMu.Unlock()
+
+	return r.checker.isOptionalParameter(node)
+}
+
+func (c *Checker) isOptionalParameter(node *ast.Node) bool {
+	if node.AsParameterDeclaration().QuestionToken != nil || isJSDocOptionalParameter(node.AsParameterDeclaration()) {
+		return true
+	}
+	if node.Initializer() != nil {
+		signature := c.getSignatureFromDeclaration(node.Parent)
+		parameterIndex := slices.Index(node.Parent.Parameters(), node)
+		return parameterIndex >= c.getMinArgumentCountEx(signature, MinArgumentCountFlagsStrongArityForUntypedJS|MinArgumentCountFlagsVoidIsNonOptional)
+	}
+	return false
+}
+
+func (r *emitResolver) IsLiteralConstDeclaration(node *ast.Node) bool {
+	if !ast.IsParseTreeNode(node) {
+		return false
+	}
+
+	r.checkerMu.Lock()
+	defer r.checkerMu.Unlock()
+
+	return r.isLiteralConstDeclaration(node)
+}
+
+func (r *emitResolver) isLiteralConstDeclaration(node *ast.Node) bool {
+	c := r.checker
+	if isDeclarationReadonly(node) || ast.IsVariableDeclaration(node) && ast.IsVarConst(node) {
+		return isFreshLiteralType(c.getTypeOfSymbol(c.getSymbolOfDeclaration(node)))
+	}
+	return false
+}
+
+func (r *emitResolver) IsLateBound(node *ast.Node) bool {
+	if !ast.IsParseTreeNode(node) {
+		return false
+	}
+
+	r.checkerMu.Lock()
+	defer r.checkerMu.Unlock()
+
+	symbol := r.checker.getSymbolOfDeclaration(node)
+	return symbol != nil && symbol.CheckFlags&ast.CheckFlagsLate != 0
+}
+
+func (r *emitResolver) RequiresAddingImplicitUndefined(node *ast.Node, enclosingDeclaration *ast.Node) bool {
+	if !ast.IsParseTreeNode(node) || !ast.IsParameter(node) {
+		return false
+	}
+
+	r.checkerMu.Lock()
+	defer r.checkerMu.Unlock()
+
+	return r.requiresAddingImplicitUndefined(node)
+}
+
+func (r *emitResolver) requiresAddingImplicitUndefined(node *ast.Node) bool {
+	c := r.checker
+	// A required parameter with an initializer accepts undefined, which the emitted signature must spell out
+	if !c.strictNullChecks || node.Initializer() == nil || ast.IsParameterPropertyDeclaration(node, node.Parent) || c.isOptionalParameter(node) {
+		return false
+	}
+	return !c.containsUndefinedType(c.getTypeOfSymbol(c.getSymbolOfDeclaration(node)))
+}
+
+func (r *emitResolver) GetEnumMemberValue(node *ast.EnumMemberNode) evaluator.Result {
+	if !ast.IsParseTreeNode(node) {
+		return evaluator.Result{}
+	}
+
+	r.checkerMu.Lock()
+	defer r.checkerMu.Unlock()
+
+	return r.checker.getEnumMemberValue(node)
+}
+
+func (r *emitResolver) CreateTypeOfDeclaration(emitContext *printer.EmitContext, declaration *ast.Node, enclosingDeclaration *ast.Node, tracker printer.SymbolTracker) *ast.Node {
+	if !ast.IsParseTreeNode(declaration) {
+		return nil
+	}
+
+	r.checkerMu.Lock()
+	defer r.checkerMu.Unlock()
+
+	c := r.checker
+	symbol := c.getSymbolOfDeclaration(declaration)
+	if symbol == nil {
+		return nil
+	}
+	t := c.getWidenedLiteralType(c.getTypeOfSymbol(symbol))
+	if symbol.Flags&ast.SymbolFlagsAccessor != 0 && symbol.Flags&ast.SymbolFlagsGetAccessor == 0 && ast.IsSetAccessorDeclaration(declaration) {
+		t = c.getWidenedLiteralType(c.getWriteTypeOfSymbol(symbol))
+	}
+	if ast.IsParameter(declaration) && r.requiresAddingImplicitUndefined(declaration) {
+		t = c.getOptionalType(t, false /*isProperty*/)
+	}
+	return c.newNodeBuilder(emitContext, enclosingDeclaration, tracker).typeToTypeNode(t)
+}
+
+func (r *emitResolver) CreateReturnTypeOfSignatureDeclaration(emitContext *printer.EmitContext, signatureDeclaration *ast.Node, enclosingDeclaration *ast.Node, tracker printer.SymbolTracker) *ast.Node {
+	if !ast.IsParseTreeNode(signatureDeclaration) {
+		return nil
+	}
+
+	r.checkerMu.Lock()
+	defer r.checkerMu.Unlock()
+
+	c := r.checker
+	signature := c.getSignatureFromDeclaration(signatureDeclaration)
+	b := c.newNodeBuilder(emitContext, enclosingDeclaration, tracker)
+	if predicate := c.getTypePredicateOfSignature(signature); predicate != nil {
+		return b.typePredicateToTypePredicateNode(predicate)
+	}
+	return b.typeToTypeNode(c.getReturnTypeOfSignature(signature))
+}
+
+func (r *emitResolver) CreateTypeOfExpression(emitContext *printer.EmitContext, expression *ast.Node, enclosingDeclaration *ast.Node, tracker printer.SymbolTracker) *ast.Node {
+	if !ast.IsParseTreeNode(expression) {
+		return nil
+	}
+
+	r.checkerMu.Lock()
+	defer r.checkerMu.Unlock()
+
+	c := r.checker
+	t := c.getWidenedType(c.getRegularTypeOfExpression(expression))
+	return c.newNodeBuilder(emitContext, enclosingDeclaration, tracker).typeToTypeNode(t)
+}
+
+func (r *emitResolver) CreateLiteralConstValue(emitContext *printer.EmitContext, node *ast.Node, tracker printer.SymbolTracker) *ast.Node {
+	if !ast.IsParseTreeNode(node) {
+		return nil
+	}
+
+	r.checkerMu.Lock()
+	defer r.checkerMu.Unlock()
+
+	c := r.checker
+	t := c.getTypeOfSymbol(c.getSymbolOfDeclaration(node))
+	b := c.newNodeBuilder(emitContext, node, tracker)
+	if t.flags&TypeFlagsEnumLiteral != 0 && t.symbol != nil {
+		if parent := c.getParentOfSymbol(t.symbol); parent != nil {
+			b.trackSymbol(parent, ast.SymbolFlagsValue)
+			return b.f.NewPropertyAccessExpression(b.f.NewIdentifier(parent.Name), nil, b.f.NewIdentifier(t.symbol.Name), ast.NodeFlagsNone)
+		}
+	}
+	return b.valueToExpression(t.AsLiteralType().value)
+}
+
 func (r *emitResolver) getReferenceResolver() binder.ReferenceResolver {
 	if r.referenceResolver == nil {
 		r.referenceResolver = binder.NewReferenceResolver(r.checker.compilerOptions, binder.ReferenceResolverHooks{
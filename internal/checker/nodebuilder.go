@@ -0,0 +1,818 @@
+package checker
+
+import (
+	"strings"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/jsnum"
+	"github.com/microsoft/typescript-go/internal/printer"
+	"github.com/microsoft/typescript-go/internal/scanner"
+	"github.com/microsoft/typescript-go/internal/tspath"
+)
+
+// NodeBuilder synthesizes type nodes for types, in the same shape that Printer produces strings. Symbols
+// referenced by the synthesized nodes are named relative to the enclosing declaration, and their
+// accessibility is reported to the tracker.
+type NodeBuilder struct {
+	c                    *Checker
+	f                    *ast.NodeFactory
+	enclosingDeclaration *ast.Node
+	tracker              printer.SymbolTracker
+	visiting             core.Set[*Type]
+	depth                int32
+	extendsTypeDepth     int32
+	inTypeAlias          bool
+}
+
+func (c *Checker) newNodeBuilder(emitContext *printer.EmitContext, enclosingDeclaration *ast.Node, tracker printer.SymbolTracker) *NodeBuilder {
+	if emitContext == nil {
+		emitContext = printer.NewEmitContext()
+	}
+	return &NodeBuilder{c: c, f: emitContext.Factory, enclosingDeclaration: enclosingDeclaration, tracker: tracker}
+}
+
+func (b *NodeBuilder) typeToTypeNodeEx(t *Type, precedence ast.TypePrecedence) *ast.Node {
+	node := b.typeToTypeNode(t)
+	if b.c.getTypePrecedence(t) < precedence {
+		return b.f.NewParenthesizedTypeNode(node)
+	}
+	return node
+}
+
+func (b *NodeBuilder) typeToTypeNode(t *Type) *ast.Node {
+	if t.alias != nil && (!b.inTypeAlias || b.depth > 0) && b.isTypeSymbolAccessible(t.alias.symbol) {
+		return b.symbolToTypeNode(t.alias.symbol, ast.SymbolFlagsType, b.typesToTypeNodes(t.alias.typeArguments))
+	}
+	return b.typeToTypeNodeNoAlias(t)
+}
+
+func (b *NodeBuilder) typeToTypeNodeNoAlias(t *Type) *ast.Node {
+	b.depth++
+	defer func() { b.depth-- }()
+	switch {
+	case t.flags&TypeFlagsIntrinsic != 0:
+		return b.intrinsicTypeToTypeNode(t)
+	case t.flags&(TypeFlagsLiteral|TypeFlagsEnum) != 0:
+		return b.literalTypeToTypeNode(t)
+	case t.flags&TypeFlagsUniqueESSymbol != 0:
+		return b.uniqueESSymbolTypeToTypeNode(t)
+	case t.flags&TypeFlagsUnion != 0:
+		return b.unionTypeToTypeNode(t)
+	case t.flags&TypeFlagsIntersection != 0:
+		return b.f.NewIntersectionTypeNode(b.f.NewNodeList(core.Map(t.AsIntersectionType().types, func(t *Type) *ast.Node {
+			return b.typeToTypeNodeEx(t, ast.TypePrecedenceIntersection)
+		})))
+	case t.flags&TypeFlagsTypeParameter != 0:
+		return b.typeParameterToTypeNode(t)
+	case t.flags&TypeFlagsObject != 0:
+		return b.visitRecursive(t, (*NodeBuilder).objectTypeToTypeNode)
+	case t.flags&TypeFlagsIndex != 0:
+		return b.visitRecursive(t, func(b *NodeBuilder, t *Type) *ast.Node {
+			return b.f.NewTypeOperatorNode(ast.KindKeyOfKeyword, b.typeToTypeNodeEx(t.AsIndexType().target, ast.TypePrecedenceTypeOperator))
+		})
+	case t.flags&TypeFlagsIndexedAccess != 0:
+		return b.visitRecursive(t, func(b *NodeBuilder, t *Type) *ast.Node {
+			return b.f.NewIndexedAccessTypeNode(
+				b.typeToTypeNodeEx(t.AsIndexedAccessType().objectType, ast.TypePrecedencePostfix),
+				b.typeToTypeNode(t.AsIndexedAccessType().indexType))
+		})
+	case t.flags&TypeFlagsConditional != 0:
+		return b.visitRecursive(t, (*NodeBuilder).conditionalTypeToTypeNode)
+	case t.flags&TypeFlagsTemplateLiteral != 0:
+		return b.templateLiteralTypeToTypeNode(t)
+	case t.flags&TypeFlagsStringMapping != 0:
+		return b.f.NewTypeReferenceNode(b.f.NewIdentifier(t.symbol.Name), b.f.NewNodeList([]*ast.Node{b.typeToTypeNode(t.AsStringMappingType().target)}))
+	case t.flags&TypeFlagsSubstitution != 0:
+		if b.c.isNoInferType(t) {
+			if noInferSymbol := b.c.getGlobalNoInferSymbolOrNil(); noInferSymbol != nil {
+				return b.symbolToTypeNode(noInferSymbol, ast.SymbolFlagsType, b.typesToTypeNodes([]*Type{t.AsSubstitutionType().baseType}))
+			}
+		}
+		return b.typeToTypeNode(t.AsSubstitutionType().baseType)
+	}
+	return b.f.NewKeywordTypeNode(ast.KindAnyKeyword)
+}
+
+func (b *NodeBuilder) visitRecursive(t *Type, f func(*NodeBuilder, *Type) *ast.Node) *ast.Node {
+	if b.visiting.Has(t) || b.depth >= 100 {
+		// A type that references itself cannot be serialized structurally
+		if b.tracker != nil {
+			b.tracker.ReportCyclicStructureError()
+		}
+		return b.f.NewKeywordTypeNode(ast.KindAnyKeyword)
+	}
+	b.visiting.Add(t)
+	defer b.visiting.Delete(t)
+	return f(b, t)
+}
+
+func (b *NodeBuilder) typesToTypeNodes(types []*Type) *ast.NodeList {
+	if len(types) == 0 {
+		return nil
+	}
+	return b.f.NewNodeList(core.Map(types, b.typeToTypeNode))
+}
+
+func (b *NodeBuilder) intrinsicTypeToTypeNode(t *Type) *ast.Node {
+	switch t.AsIntrinsicType().intrinsicName {
+	case "unknown":
+		return b.f.NewKeywordTypeNode(ast.KindUnknownKeyword)
+	case "string":
+		return b.f.NewKeywordTypeNode(ast.KindStringKeyword)
+	case "number":
+		return b.f.NewKeywordTypeNode(ast.KindNumberKeyword)
+	case "bigint":
+		return b.f.NewKeywordTypeNode(ast.KindBigIntKeyword)
+	case "symbol":
+		return b.f.NewKeywordTypeNode(ast.KindSymbolKeyword)
+	case "void":
+		return b.f.NewKeywordTypeNode(ast.KindVoidKeyword)
+	case "undefined":
+		return b.f.NewKeywordTypeNode(ast.KindUndefinedKeyword)
+	case "null":
+		return b.f.NewLiteralTypeNode(b.f.NewKeywordExpression(ast.KindNullKeyword))
+	case "never":
+		return b.f.NewKeywordTypeNode(ast.KindNeverKeyword)
+	case "object":
+		return b.f.NewKeywordTypeNode(ast.KindObjectKeyword)
+	case "intrinsic":
+		return b.f.NewKeywordTypeNode(ast.KindIntrinsicKeyword)
+	}
+	return b.f.NewKeywordTypeNode(ast.KindAnyKeyword)
+}
+
+func (b *NodeBuilder) literalTypeToTypeNode(t *Type) *ast.Node {
+	if t.flags&(TypeFlagsEnumLiteral|TypeFlagsEnum) != 0 {
+		return b.enumLiteralToTypeNode(t)
+	}
+	return b.f.NewLiteralTypeNode(b.valueToExpression(t.AsLiteralType().value))
+}
+
+func (b *NodeBuilder) valueToExpression(value any) *ast.Node {
+	switch value := value.(type) {
+	case string:
+		return b.f.NewStringLiteral(value)
+	case jsnum.Number:
+		if value < 0 {
+			return b.f.NewPrefixUnaryExpression(ast.KindMinusToken, b.f.NewNumericLiteral((-value).String()))
+		}
+		return b.f.NewNumericLiteral(value.String())
+	case bool:
+		return b.f.NewKeywordExpression(core.IfElse(value, ast.KindTrueKeyword, ast.KindFalseKeyword))
+	case jsnum.PseudoBigInt:
+		if value.Negative {
+			value.Negative = false
+			return b.f.NewPrefixUnaryExpression(ast.KindMinusToken, b.f.NewBigIntLiteral(value.String()+"n"))
+		}
+		return b.f.NewBigIntLiteral(value.String() + "n")
+	}
+	return b.f.NewIdentifier("undefined")
+}
+
+func (b *NodeBuilder) enumLiteralToTypeNode(t *Type) *ast.Node {
+	if parent := b.c.getParentOfSymbol(t.symbol); parent != nil {
+		if b.c.getDeclaredTypeOfSymbol(parent) == t {
+			return b.symbolToTypeNode(parent, ast.SymbolFlagsType, nil)
+		}
+		parentNode := b.symbolToTypeNode(parent, ast.SymbolFlagsType, nil)
+		memberName := b.f.NewIdentifier(t.symbol.Name)
+		if !scanner.IsIdentifierText(t.symbol.Name, b.c.languageVersion) {
+			// Members with non-identifier names are referenced with an indexed access
+			return b.f.NewIndexedAccessTypeNode(b.f.NewTypeQueryNode(parentNode.AsTypeReferenceNode().TypeName, nil), b.f.NewLiteralTypeNode(b.f.NewStringLiteral(t.symbol.Name)))
+		}
+		if parentNode.Kind == ast.KindTypeReference {
+			return b.f.NewTypeReferenceNode(b.f.NewQualifiedName(parentNode.AsTypeReferenceNode().TypeName, memberName), nil)
+		}
+		if parentNode.Kind == ast.KindImportType {
+			n := parentNode.AsImportTypeNode()
+			qualifier := memberName
+			if n.Qualifier != nil {
+				qualifier = b.f.NewQualifiedName(n.Qualifier, memberName)
+			}
+			return b.f.NewImportTypeNode(n.IsTypeOf, n.Argument, n.Attributes, qualifier, nil)
+		}
+		return parentNode
+	}
+	return b.symbolToTypeNode(t.symbol, ast.SymbolFlagsType, nil)
+}
+
+func (b *NodeBuilder) uniqueESSymbolTypeToTypeNode(t *Type) *ast.Node {
+	if t.symbol != nil && t.symbol.ValueDeclaration != nil && b.isValueSymbolAccessible(t.symbol) {
+		return b.symbolToTypeNode(t.symbol, ast.SymbolFlagsValue, nil)
+	}
+	if b.tracker != nil {
+		b.tracker.ReportInaccessibleUniqueSymbolError()
+	}
+	return b.f.NewTypeOperatorNode(ast.KindUniqueKeyword, b.f.NewKeywordTypeNode(ast.KindSymbolKeyword))
+}
+
+func (b *NodeBuilder) unionTypeToTypeNode(t *Type) *ast.Node {
+	switch {
+	case t.flags&TypeFlagsBoolean != 0:
+		return b.f.NewKeywordTypeNode(ast.KindBooleanKeyword)
+	case t.flags&TypeFlagsEnumLiteral != 0 && t.symbol != nil:
+		return b.symbolToTypeNode(t.symbol, ast.SymbolFlagsType, nil)
+	}
+	u := t.AsUnionType()
+	if u.origin != nil {
+		return b.typeToTypeNode(u.origin)
+	}
+	types := b.c.formatUnionTypes(u.types)
+	if len(types) == 1 {
+		return b.typeToTypeNode(types[0])
+	}
+	return b.f.NewUnionTypeNode(b.f.NewNodeList(core.Map(types, func(t *Type) *ast.Node {
+		return b.typeToTypeNodeEx(t, ast.TypePrecedenceUnion)
+	})))
+}
+
+func (b *NodeBuilder) typeParameterToTypeNode(t *Type) *ast.Node {
+	switch {
+	case t.AsTypeParameter().isThisType:
+		if b.enclosingDeclaration != nil && ast.FindAncestor(b.enclosingDeclaration, ast.IsClassLike) == nil &&
+			ast.FindAncestor(b.enclosingDeclaration, ast.IsInterfaceDeclaration) == nil && b.tracker != nil {
+			b.tracker.ReportInaccessibleThisError()
+		}
+		return b.f.NewThisTypeNode()
+	case b.extendsTypeDepth > 0 && isInferTypeParameter(t):
+		return b.f.NewInferTypeNode(b.typeParameterToDeclaration(t))
+	case t.symbol != nil:
+		return b.f.NewTypeReferenceNode(b.f.NewIdentifier(t.symbol.Name), nil)
+	}
+	return b.f.NewKeywordTypeNode(ast.KindAnyKeyword)
+}
+
+func (b *NodeBuilder) typeParameterToDeclaration(t *Type) *ast.Node {
+	var modifiers *ast.ModifierList
+	if declaration := ast.GetDeclarationOfKind(t.symbol, ast.KindTypeParameter); declaration != nil && declaration.Modifiers() != nil {
+		modifiers = b.f.NewModifierList(core.Map(declaration.Modifiers().Nodes, func(m *ast.Node) *ast.Node {
+			return b.f.NewModifier(m.Kind)
+		}))
+	}
+	var constraint *ast.Node
+	if constraintType := b.c.getConstraintOfTypeParameter(t); constraintType != nil {
+		constraint = b.typeToTypeNode(constraintType)
+	}
+	var defaultType *ast.Node
+	if d := b.c.getDefaultFromTypeParameter(t); d != nil {
+		defaultType = b.typeToTypeNode(d)
+	}
+	return b.f.NewTypeParameterDeclaration(modifiers, b.f.NewIdentifier(t.symbol.Name), constraint, defaultType)
+}
+
+func (b *NodeBuilder) objectTypeToTypeNode(t *Type) *ast.Node {
+	switch {
+	case t.objectFlags&ObjectFlagsReference != 0 && t.symbol != nil && isReservedMemberName(t.symbol.Name) && t.objectFlags&ObjectFlagsClassOrInterface != 0:
+		// Class expression instance types have no name and are written structurally
+		return b.typeLiteralToTypeNode(t)
+	case t.objectFlags&ObjectFlagsReference != 0:
+		return b.typeReferenceToTypeNode(t)
+	case t.objectFlags&ObjectFlagsClassOrInterface != 0:
+		if isReservedMemberName(t.symbol.Name) {
+			return b.typeLiteralToTypeNode(t)
+		}
+		return b.symbolToTypeNode(t.symbol, ast.SymbolFlagsType, nil)
+	case b.c.isGenericMappedType(t) || t.objectFlags&ObjectFlagsMapped != 0 && t.AsMappedType().containsError:
+		return b.mappedTypeToTypeNode(t)
+	}
+	return b.anonymousTypeToTypeNode(t)
+}
+
+func (b *NodeBuilder) typeReferenceToTypeNode(t *Type) *ast.Node {
+	switch {
+	case b.c.isArrayType(t):
+		node := b.f.NewArrayTypeNode(b.typeToTypeNodeEx(b.c.getTypeArguments(t)[0], ast.TypePrecedencePostfix))
+		if t.AsTypeReference().target != b.c.globalArrayType {
+			return b.f.NewTypeOperatorNode(ast.KindReadonlyKeyword, node)
+		}
+		return node
+	case isTupleType(t):
+		return b.tupleTypeToTypeNode(t)
+	}
+	typeArguments := b.c.getTypeArguments(t)[:b.c.getTypeReferenceArity(t)]
+	return b.symbolToTypeNode(t.symbol, ast.SymbolFlagsType, b.typesToTypeNodes(typeArguments))
+}
+
+func (b *NodeBuilder) tupleTypeToTypeNode(t *Type) *ast.Node {
+	elementInfos := t.TargetTupleType().elementInfos
+	typeArguments := b.c.getTypeArguments(t)
+	elements := make([]*ast.Node, 0, len(elementInfos))
+	for i, info := range elementInfos {
+		elementType := typeArguments[i]
+		if info.flags&ElementFlagsOptional != 0 {
+			elementType = b.c.removeMissingType(elementType, true)
+		}
+		var element *ast.Node
+		if info.labeledDeclaration != nil {
+			var dotDotDotToken, questionToken *ast.Node
+			typeNode := b.typeToTypeNode(elementType)
+			if info.flags&ElementFlagsVariable != 0 {
+				dotDotDotToken = b.f.NewToken(ast.KindDotDotDotToken)
+				if info.flags&ElementFlagsRest != 0 {
+					typeNode = b.f.NewArrayTypeNode(b.typeToTypeNodeEx(elementType, ast.TypePrecedencePostfix))
+				}
+			}
+			if info.flags&ElementFlagsOptional != 0 {
+				questionToken = b.f.NewToken(ast.KindQuestionToken)
+			}
+			element = b.f.NewNamedTupleMember(dotDotDotToken, b.f.NewIdentifier(info.labeledDeclaration.Name().Text()), questionToken, typeNode)
+		} else {
+			switch {
+			case info.flags&ElementFlagsOptional != 0:
+				element = b.f.NewOptionalTypeNode(b.typeToTypeNodeEx(elementType, ast.TypePrecedencePostfix))
+			case info.flags&ElementFlagsRest != 0:
+				element = b.f.NewRestTypeNode(b.f.NewArrayTypeNode(b.typeToTypeNodeEx(elementType, ast.TypePrecedencePostfix)))
+			case info.flags&ElementFlagsVariadic != 0:
+				element = b.f.NewRestTypeNode(b.typeToTypeNode(elementType))
+			default:
+				element = b.typeToTypeNode(elementType)
+			}
+		}
+		elements = append(elements, element)
+	}
+	node := b.f.NewTupleTypeNode(b.f.NewNodeList(elements))
+	if t.TargetTupleType().readonly {
+		return b.f.NewTypeOperatorNode(ast.KindReadonlyKeyword, node)
+	}
+	return node
+}
+
+func (b *NodeBuilder) anonymousTypeToTypeNode(t *Type) *ast.Node {
+	symbol := t.symbol
+	if symbol != nil && len(symbol.Name) != 0 && !isReservedMemberName(symbol.Name) {
+		if symbol.Flags&(ast.SymbolFlagsClass|ast.SymbolFlagsEnum|ast.SymbolFlagsValueModule) != 0 && t == b.c.getTypeOfSymbol(symbol) && b.isValueSymbolAccessible(symbol) {
+			return b.symbolToTypeNode(symbol, ast.SymbolFlagsValue, nil)
+		}
+		if symbol.Flags&(ast.SymbolFlagsFunction|ast.SymbolFlagsMethod) != 0 && b.shouldWriteTypeOfFunctionSymbol(symbol) {
+			return b.symbolToTypeNode(symbol, ast.SymbolFlagsValue, nil)
+		}
+	}
+	return b.typeLiteralToTypeNode(t)
+}
+
+func (b *NodeBuilder) shouldWriteTypeOfFunctionSymbol(symbol *ast.Symbol) bool {
+	isStaticMethodSymbol := symbol.Flags&ast.SymbolFlagsMethod != 0 && core.Some(symbol.Declarations, ast.IsStatic)
+	isNonLocalFunctionSymbol := symbol.Flags&ast.SymbolFlagsFunction != 0 && (symbol.Parent != nil || core.Some(symbol.Declarations, func(d *ast.Node) bool {
+		return d.Parent.Kind == ast.KindSourceFile || d.Parent.Kind == ast.KindModuleBlock
+	}))
+	return (isStaticMethodSymbol || isNonLocalFunctionSymbol) && b.isValueSymbolAccessible(symbol)
+}
+
+func (b *NodeBuilder) typeLiteralToTypeNode(t *Type) *ast.Node {
+	props := b.c.getPropertiesOfObjectType(t)
+	callSignatures := b.c.getSignaturesOfType(t, SignatureKindCall)
+	constructSignatures := b.c.getSignaturesOfType(t, SignatureKindConstruct)
+	if len(props) == 0 && len(b.c.getIndexInfosOfType(t)) == 0 {
+		if len(callSignatures) == 1 && len(constructSignatures) == 0 {
+			return b.signatureToSignatureDeclaration(callSignatures[0], ast.KindFunctionType)
+		}
+		if len(callSignatures) == 0 && len(constructSignatures) == 1 {
+			return b.signatureToSignatureDeclaration(constructSignatures[0], ast.KindConstructorType)
+		}
+	}
+	var members []*ast.Node
+	for _, sig := range callSignatures {
+		members = append(members, b.signatureToSignatureDeclaration(sig, ast.KindCallSignature))
+	}
+	for _, sig := range constructSignatures {
+		if sig.flags&SignatureFlagsAbstract != 0 {
+			continue
+		}
+		members = append(members, b.signatureToSignatureDeclaration(sig, ast.KindConstructSignature))
+	}
+	for _, info := range b.c.getIndexInfosOfType(t) {
+		members = append(members, b.indexInfoToIndexSignatureDeclaration(info))
+	}
+	for _, prop := range props {
+		members = b.addPropertyToElementList(prop, members)
+	}
+	return b.f.NewTypeLiteralNode(b.f.NewNodeList(members))
+}
+
+func (b *NodeBuilder) indexInfoToIndexSignatureDeclaration(info *IndexInfo) *ast.Node {
+	name := getNameFromIndexInfo(info)
+	if name == "" {
+		name = "x"
+	}
+	parameter := b.f.NewParameterDeclaration(nil, nil, b.f.NewIdentifier(name), nil, b.typeToTypeNode(info.keyType), nil)
+	var modifiers *ast.ModifierList
+	if info.isReadonly {
+		modifiers = b.f.NewModifierList([]*ast.Node{b.f.NewModifier(ast.KindReadonlyKeyword)})
+	}
+	return b.f.NewIndexSignatureDeclaration(modifiers, b.f.NewNodeList([]*ast.Node{parameter}), b.typeToTypeNode(info.valueType))
+}
+
+func (b *NodeBuilder) addPropertyToElementList(prop *ast.Symbol, members []*ast.Node) []*ast.Node {
+	if prop.Flags&ast.SymbolFlagsPrototype != 0 {
+		return members
+	}
+	if getDeclarationModifierFlagsFromSymbol(prop)&(ast.ModifierFlagsPrivate|ast.ModifierFlagsProtected) != 0 {
+		if b.tracker != nil {
+			b.tracker.ReportPrivateInBaseOfClassExpression(prop.Name)
+		}
+		return members
+	}
+	if prop.ValueDeclaration != nil && ast.IsPrivateIdentifierClassElementDeclaration(prop.ValueDeclaration) {
+		return members
+	}
+	name := b.propertyNameToNode(prop)
+	if name == nil {
+		return members
+	}
+	var questionToken *ast.Node
+	if prop.Flags&ast.SymbolFlagsOptional != 0 {
+		questionToken = b.f.NewToken(ast.KindQuestionToken)
+	}
+	propType := b.c.getNonMissingTypeOfSymbol(prop)
+	if prop.Flags&(ast.SymbolFlagsFunction|ast.SymbolFlagsMethod) != 0 && len(b.c.getPropertiesOfObjectType(propType)) == 0 && !b.c.isReadonlySymbol(prop) {
+		if signatures := b.c.getSignaturesOfType(b.c.removeMissingType(propType, prop.Flags&ast.SymbolFlagsOptional != 0), SignatureKindCall); len(signatures) != 0 {
+			for _, sig := range signatures {
+				method := b.signatureToSignatureDeclaration(sig, ast.KindMethodSignature).AsMethodSignatureDeclaration()
+				members = append(members, b.f.UpdateMethodSignatureDeclaration(method, nil, name, questionToken, method.TypeParameters, method.Parameters, method.Type))
+				name = b.propertyNameToNode(prop)
+			}
+			return members
+		}
+	}
+	var modifiers *ast.ModifierList
+	if b.c.isReadonlySymbol(prop) {
+		modifiers = b.f.NewModifierList([]*ast.Node{b.f.NewModifier(ast.KindReadonlyKeyword)})
+	}
+	return append(members, b.f.NewPropertySignatureDeclaration(modifiers, name, questionToken, b.typeToTypeNode(propType), nil))
+}
+
+func (b *NodeBuilder) propertyNameToNode(prop *ast.Symbol) *ast.Node {
+	name := prop.Name
+	if isLateBoundName(name) {
+		// Late bound names are written using the computed property name of the declaration
+		if prop.ValueDeclaration != nil {
+			if declName := ast.GetNameOfDeclaration(prop.ValueDeclaration); declName != nil && ast.IsComputedPropertyName(declName) {
+				expression := declName.AsComputedPropertyName().Expression
+				if ast.IsEntityNameExpression(expression) {
+					if symbol := b.c.getSymbolAtLocation(ast.GetFirstIdentifier(expression), true /*ignoreErrors*/); symbol != nil {
+						b.trackSymbol(symbol, ast.SymbolFlagsValue)
+					}
+					return b.f.NewComputedPropertyName(b.entityNameExpressionToExpression(expression))
+				}
+			}
+		}
+		if b.tracker != nil {
+			b.tracker.ReportInaccessibleUniqueSymbolError()
+		}
+		return nil
+	}
+	if scanner.IsIdentifierText(name, b.c.languageVersion) {
+		return b.f.NewIdentifier(name)
+	}
+	if isNumericLiteralName(name) && !strings.HasPrefix(name, "-") {
+		return b.f.NewNumericLiteral(name)
+	}
+	return b.f.NewStringLiteral(name)
+}
+
+func (b *NodeBuilder) entityNameExpressionToExpression(node *ast.Node) *ast.Node {
+	if ast.IsPropertyAccessExpression(node) {
+		n := node.AsPropertyAccessExpression()
+		return b.f.NewPropertyAccessExpression(b.entityNameExpressionToExpression(n.Expression), nil, b.f.NewIdentifier(n.Name().Text()), ast.NodeFlagsNone)
+	}
+	return b.f.NewIdentifier(node.Text())
+}
+
+func (b *NodeBuilder) signatureToSignatureDeclaration(sig *Signature, kind ast.Kind) *ast.Node {
+	var typeParameters *ast.NodeList
+	if len(sig.typeParameters) != 0 {
+		typeParameters = b.f.NewNodeList(core.Map(sig.typeParameters, b.typeParameterToDeclaration))
+	}
+	var parameters []*ast.Node
+	if sig.thisParameter != nil {
+		parameters = append(parameters, b.f.NewParameterDeclaration(nil, nil, b.f.NewIdentifier("this"), nil, b.typeToTypeNode(b.c.getTypeOfSymbol(sig.thisParameter)), nil))
+	}
+	expandedParameters := b.c.GetExpandedParameters(sig)
+	// If the expanded parameter list had a variadic in a non-trailing position, don't expand it
+	sigParameters := core.IfElse(core.Some(expandedParameters, func(s *ast.Symbol) bool {
+		return s != expandedParameters[len(expandedParameters)-1] && s.CheckFlags&ast.CheckFlagsRestParameter != 0
+	}), sig.parameters, expandedParameters)
+	minArgumentCount := b.c.getMinArgumentCountEx(sig, MinArgumentCountFlagsVoidIsNonOptional)
+	for i, param := range sigParameters {
+		parameters = append(parameters, b.symbolToParameterDeclaration(param, i >= minArgumentCount))
+	}
+	var returnType *ast.Node
+	if pred := b.c.getTypePredicateOfSignature(sig); pred != nil {
+		returnType = b.typePredicateToTypePredicateNode(pred)
+	} else {
+		returnType = b.typeToTypeNode(b.c.getReturnTypeOfSignature(sig))
+	}
+	parameterList := b.f.NewNodeList(parameters)
+	switch kind {
+	case ast.KindCallSignature:
+		return b.f.NewCallSignatureDeclaration(typeParameters, parameterList, returnType)
+	case ast.KindConstructSignature:
+		return b.f.NewConstructSignatureDeclaration(typeParameters, parameterList, returnType)
+	case ast.KindMethodSignature:
+		return b.f.NewMethodSignatureDeclaration(nil, b.f.NewIdentifier(""), nil, typeParameters, parameterList, returnType)
+	case ast.KindConstructorType:
+		var modifiers *ast.ModifierList
+		if sig.flags&SignatureFlagsAbstract != 0 {
+			modifiers = b.f.NewModifierList([]*ast.Node{b.f.NewModifier(ast.KindAbstractKeyword)})
+		}
+		return b.f.NewConstructorTypeNode(modifiers, typeParameters, parameterList, returnType)
+	}
+	return b.f.NewFunctionTypeNode(typeParameters, parameterList, returnType)
+}
+
+func (b *NodeBuilder) symbolToParameterDeclaration(param *ast.Symbol, isOptional bool) *ast.Node {
+	declaration := param.ValueDeclaration
+	if declaration != nil && !ast.IsParameter(declaration) {
+		declaration = nil
+	}
+	var dotDotDotToken, questionToken *ast.Node
+	isRest := declaration != nil && isRestParameter(declaration) || param.CheckFlags&ast.CheckFlagsRestParameter != 0
+	if isRest {
+		dotDotDotToken = b.f.NewToken(ast.KindDotDotDotToken)
+	} else if isOptional {
+		questionToken = b.f.NewToken(ast.KindQuestionToken)
+	}
+	var name *ast.Node
+	if declaration != nil && ast.IsBindingPattern(declaration.Name()) {
+		name = b.cloneBindingName(declaration.Name())
+	} else {
+		name = b.f.NewIdentifier(param.Name)
+	}
+	var typeNode *ast.Node
+	if declaration != nil && declaration.Type() != nil {
+		typeNode = b.tryReuseExistingTypeNode(declaration.Type())
+	}
+	if typeNode == nil {
+		paramType := b.c.getTypeOfSymbol(param)
+		if isOptional && !isRest {
+			paramType = b.c.removeMissingType(paramType, true)
+		}
+		typeNode = b.typeToTypeNode(paramType)
+	}
+	return b.f.NewParameterDeclaration(nil, dotDotDotToken, name, questionToken, typeNode, nil)
+}
+
+// Clones a binding pattern for use in a signature, removing initializers.
+func (b *NodeBuilder) cloneBindingName(node *ast.Node) *ast.Node {
+	if ast.IsIdentifier(node) {
+		return b.f.NewIdentifier(node.Text())
+	}
+	elements := core.Map(node.AsBindingPattern().Elements.Nodes, func(element *ast.Node) *ast.Node {
+		if element.Kind == ast.KindOmittedExpression {
+			return b.f.NewOmittedExpression()
+		}
+		e := element.AsBindingElement()
+		var dotDotDotToken, propertyName *ast.Node
+		if e.DotDotDotToken != nil {
+			dotDotDotToken = b.f.NewToken(ast.KindDotDotDotToken)
+		}
+		if e.PropertyName != nil {
+			if ast.IsIdentifier(e.PropertyName) {
+				propertyName = b.f.NewIdentifier(e.PropertyName.Text())
+			} else {
+				propertyName = b.f.DeepCloneNode(e.PropertyName)
+			}
+		}
+		return b.f.NewBindingElement(dotDotDotToken, propertyName, b.cloneBindingName(e.Name()), nil)
+	})
+	return b.f.NewBindingPattern(node.Kind, b.f.NewNodeList(elements))
+}
+
+func (b *NodeBuilder) typePredicateToTypePredicateNode(pred *TypePredicate) *ast.Node {
+	var assertsModifier *ast.Node
+	if pred.kind == TypePredicateKindAssertsThis || pred.kind == TypePredicateKindAssertsIdentifier {
+		assertsModifier = b.f.NewToken(ast.KindAssertsKeyword)
+	}
+	var parameterName *ast.Node
+	if pred.kind == TypePredicateKindThis || pred.kind == TypePredicateKindAssertsThis {
+		parameterName = b.f.NewThisTypeNode()
+	} else {
+		parameterName = b.f.NewIdentifier(pred.parameterName)
+	}
+	var typeNode *ast.Node
+	if pred.t != nil {
+		typeNode = b.typeToTypeNode(pred.t)
+	}
+	return b.f.NewTypePredicateNode(assertsModifier, parameterName, typeNode)
+}
+
+func (b *NodeBuilder) conditionalTypeToTypeNode(t *Type) *ast.Node {
+	checkType := b.typeToTypeNodeEx(t.AsConditionalType().checkType, ast.TypePrecedenceConditional+1)
+	b.extendsTypeDepth++
+	extendsType := b.typeToTypeNodeEx(t.AsConditionalType().extendsType, ast.TypePrecedenceConditional+1)
+	b.extendsTypeDepth--
+	trueType := b.typeToTypeNode(b.c.getTrueTypeFromConditionalType(t))
+	falseType := b.typeToTypeNode(b.c.getFalseTypeFromConditionalType(t))
+	return b.f.NewConditionalTypeNode(checkType, extendsType, trueType, falseType)
+}
+
+func (b *NodeBuilder) templateLiteralTypeToTypeNode(t *Type) *ast.Node {
+	texts := t.AsTemplateLiteralType().texts
+	types := t.AsTemplateLiteralType().types
+	head := b.f.NewTemplateHead(texts[0], "", ast.TokenFlagsNone)
+	spans := make([]*ast.Node, len(types))
+	for i, t := range types {
+		var literal *ast.Node
+		if i == len(types)-1 {
+			literal = b.f.NewTemplateTail(texts[i+1], "", ast.TokenFlagsNone)
+		} else {
+			literal = b.f.NewTemplateMiddle(texts[i+1], "", ast.TokenFlagsNone)
+		}
+		spans[i] = b.f.NewTemplateLiteralTypeSpan(b.typeToTypeNode(t), literal)
+	}
+	return b.f.NewTemplateLiteralTypeNode(head, b.f.NewNodeList(spans))
+}
+
+func (b *NodeBuilder) mappedTypeToTypeNode(t *Type) *ast.Node {
+	d := t.AsMappedType().declaration
+	var readonlyToken, questionToken *ast.Node
+	if d.ReadonlyToken != nil {
+		readonlyToken = b.f.NewToken(d.ReadonlyToken.Kind)
+	}
+	if d.QuestionToken != nil {
+		questionToken = b.f.NewToken(d.QuestionToken.Kind)
+	}
+	typeParameter := b.c.getTypeParameterFromMappedType(t)
+	constraint := b.typeToTypeNode(b.c.getConstraintTypeFromMappedType(t))
+	typeParameterNode := b.f.NewTypeParameterDeclaration(nil, b.f.NewIdentifier(typeParameter.symbol.Name), constraint, nil)
+	var nameType *ast.Node
+	if n := b.c.getNameTypeFromMappedType(t); n != nil {
+		nameType = b.typeToTypeNode(n)
+	}
+	templateType := b.typeToTypeNode(b.c.getTemplateTypeFromMappedType(t))
+	return b.f.NewMappedTypeNode(readonlyToken, typeParameterNode, nameType, questionToken, templateType, nil)
+}
+
+// Returns a copy of an existing type annotation if every name it references resolves to the same symbol
+// from the enclosing declaration, or nil if the annotation cannot be reused.
+func (b *NodeBuilder) tryReuseExistingTypeNode(typeNode *ast.Node) *ast.Node {
+	if b.enclosingDeclaration == nil || ast.GetSourceFileOfNode(typeNode) != ast.GetSourceFileOfNode(b.enclosingDeclaration) {
+		return nil
+	}
+	var tracked []*ast.Symbol
+	var trackedMeanings []ast.SymbolFlags
+	canReuse := true
+	var visit func(node *ast.Node) bool
+	visit = func(node *ast.Node) bool {
+		var entityName *ast.Node
+		meaning := ast.SymbolFlagsType
+		switch node.Kind {
+		case ast.KindThisType, ast.KindTypePredicate, ast.KindInferType:
+			canReuse = false
+			return true
+		case ast.KindTypeReference:
+			entityName = node.AsTypeReferenceNode().TypeName
+		case ast.KindTypeQuery:
+			entityName = node.AsTypeQueryNode().ExprName
+			meaning = ast.SymbolFlagsValue
+		case ast.KindImportType:
+			canReuse = false
+			return true
+		}
+		if entityName != nil {
+			firstIdentifier := ast.GetFirstIdentifier(entityName)
+			if ast.IsThisIdentifier(firstIdentifier) {
+				canReuse = false
+				return true
+			}
+			symbolMeaning := core.IfElse(firstIdentifier == entityName, meaning, ast.SymbolFlagsNamespace|core.IfElse(meaning == ast.SymbolFlagsValue, ast.SymbolFlagsValue, 0))
+			original := b.c.resolveName(firstIdentifier, firstIdentifier.Text(), symbolMeaning, nil /*nameNotFoundMessage*/, false /*isUse*/, false /*excludeGlobals*/)
+			resolved := b.c.resolveName(b.enclosingDeclaration, firstIdentifier.Text(), symbolMeaning, nil /*nameNotFoundMessage*/, false /*isUse*/, false /*excludeGlobals*/)
+			if original == nil || original != resolved {
+				canReuse = false
+				return true
+			}
+			if original.Flags&ast.SymbolFlagsTypeParameter == 0 {
+				tracked = append(tracked, original)
+				trackedMeanings = append(trackedMeanings, symbolMeaning)
+			}
+		}
+		return node.ForEachChild(visit)
+	}
+	visit(typeNode)
+	if !canReuse {
+		return nil
+	}
+	for i, symbol := range tracked {
+		b.trackSymbol(symbol, trackedMeanings[i])
+	}
+	return b.f.DeepCloneNode(typeNode)
+}
+
+func (b *NodeBuilder) isTypeSymbolAccessible(symbol *ast.Symbol) bool {
+	if b.enclosingDeclaration == nil {
+		return true
+	}
+	return b.c.isSymbolAccessibleWorker(symbol, b.enclosingDeclaration, ast.SymbolFlagsType, false /*shouldComputeAliasesToMakeVisible*/, true /*allowModules*/).Accessibility == printer.SymbolAccessibilityAccessible
+}
+
+func (b *NodeBuilder) isValueSymbolAccessible(symbol *ast.Symbol) bool {
+	if b.enclosingDeclaration == nil {
+		return true
+	}
+	return b.c.isSymbolAccessibleWorker(symbol, b.enclosingDeclaration, ast.SymbolFlagsValue, false /*shouldComputeAliasesToMakeVisible*/, true /*allowModules*/).Accessibility == printer.SymbolAccessibilityAccessible
+}
+
+func (b *NodeBuilder) trackSymbol(symbol *ast.Symbol, meaning ast.SymbolFlags) {
+	if b.tracker != nil && b.enclosingDeclaration != nil {
+		b.tracker.TrackSymbolAccessibility(b.c.isSymbolAccessible(symbol, b.enclosingDeclaration, meaning, true /*shouldComputeAliasesToMakeVisible*/))
+	}
+}
+
+func (b *NodeBuilder) symbolToTypeNode(symbol *ast.Symbol, meaning ast.SymbolFlags, typeArguments *ast.NodeList) *ast.Node {
+	b.trackSymbol(symbol, meaning)
+	chain := b.getSymbolChain(symbol, meaning, true /*endOfChain*/)
+	isTypeOf := meaning == ast.SymbolFlagsValue
+	if root := chain[0]; core.Some(root.Declarations, hasNonGlobalAugmentationExternalModuleSymbol) {
+		if b.enclosingDeclaration == nil {
+			if len(chain) > 1 {
+				chain = chain[1:]
+			}
+		} else {
+			var qualifier *ast.Node
+			if len(chain) > 1 {
+				qualifier = b.symbolChainToEntityName(chain[1:])
+			}
+			argument := b.f.NewLiteralTypeNode(b.f.NewStringLiteral(b.getSpecifierForModuleSymbol(root)))
+			return b.f.NewImportTypeNode(isTypeOf, argument, nil, qualifier, typeArguments)
+		}
+	}
+	entityName := b.symbolChainToEntityName(chain)
+	if isTypeOf {
+		return b.f.NewTypeQueryNode(entityName, typeArguments)
+	}
+	return b.f.NewTypeReferenceNode(entityName, typeArguments)
+}
+
+func (b *NodeBuilder) getSymbolChain(symbol *ast.Symbol, meaning ast.SymbolFlags, endOfChain bool) []*ast.Symbol {
+	chain := b.c.getAccessibleSymbolChain(symbol, b.enclosingDeclaration, meaning)
+	if chain == nil {
+		// Go up and add our parent.
+		if parent := b.c.getParentOfSymbol(symbol); parent != nil {
+			if parentChain := b.getSymbolChain(parent, getQualifiedLeftMeaning(meaning), false /*endOfChain*/); parentChain != nil {
+				return append(parentChain, symbol)
+			}
+		}
+	}
+	if chain != nil {
+		return chain
+	}
+	// If this is the last part of outputting the symbol, always output. The cases this can happen are when this is the
+	// last part of the chain, or if the symbol is an external module/namespace
+	if endOfChain || symbol.Flags&(ast.SymbolFlagsTypeLiteral|ast.SymbolFlagsObjectLiteral) == 0 {
+		return []*ast.Symbol{symbol}
+	}
+	return nil
+}
+
+func (b *NodeBuilder) symbolChainToEntityName(chain []*ast.Symbol) *ast.Node {
+	var name *ast.Node
+	for _, symbol := range chain {
+		identifier := b.f.NewIdentifier(b.c.symbolToString(symbol))
+		if name == nil {
+			name = identifier
+		} else {
+			name = b.f.NewQualifiedName(name, identifier)
+		}
+	}
+	return name
+}
+
+func (b *NodeBuilder) getSpecifierForModuleSymbol(moduleSymbol *ast.Symbol) string {
+	if strings.HasPrefix(moduleSymbol.Name, "\"") {
+		return moduleSymbol.Name[1 : len(moduleSymbol.Name)-1]
+	}
+	file := ast.GetDeclarationOfKind(moduleSymbol, ast.KindSourceFile)
+	if file == nil {
+		return moduleSymbol.Name
+	}
+	enclosingFile := ast.GetSourceFileOfNode(b.enclosingDeclaration)
+	// Prefer a specifier the enclosing file already uses to import the module
+	for _, specifier := range enclosingFile.Imports {
+		if b.c.resolveExternalModuleName(specifier, specifier, true /*ignoreErrors*/) == moduleSymbol {
+			return specifier.Text()
+		}
+	}
+	fileName := file.AsSourceFile().FileName()
+	if index := strings.LastIndex(fileName, "/node_modules/"); index >= 0 {
+		specifier := tspath.RemoveFileExtension(fileName[index+len("/node_modules/"):])
+		specifier = strings.TrimSuffix(specifier, "/index")
+		if rest, ok := strings.CutPrefix(specifier, "@types/"); ok {
+			if scope, name, ok := strings.Cut(rest, "__"); ok {
+				rest = "@" + scope + "/" + name
+			}
+			specifier = rest
+		}
+		return specifier
+	}
+	specifier := tspath.RemoveFileExtension(tspath.GetRelativePathFromDirectory(
+		tspath.GetDirectoryPath(enclosingFile.FileName()),
+		fileName,
+		tspath.ComparePathsOptions{UseCaseSensitiveFileNames: true},
+	))
+	if !tspath.PathIsRelative(specifier) {
+		specifier = "./" + specifier
+	}
+	return specifier
+}
@@ -2,6 +2,7 @@ package checker
 
 import (
 	"strings"
+	"unicode/utf8"
 
 	"github.com/microsoft/typescript-go/internal/ast"
 	"github.com/microsoft/typescript-go/internal/core"
@@ -70,6 +71,26 @@ func (c *Checker) TypeToString(t *Type) string {
 	return c.typeToStringEx(t, nil, TypeFormatFlagsNone)
 }
 
+// TypeToStringEx prints a type. With TypeFormatFlagsInTypeAlias, a type alias is printed as
+// the type it names rather than by its own name.
+func (c *Checker) TypeToStringEx(t *Type, enclosingDeclaration *ast.Node, flags TypeFormatFlags) string {
+	return c.typeToStringEx(t, enclosingDeclaration, flags)
+}
+
+// TypeToStringTruncated is like TypeToStringEx, except that a type that prints longer than
+// maxLength is cut short with an ellipsis.
+func (c *Checker) TypeToStringTruncated(t *Type, enclosingDeclaration *ast.Node, flags TypeFormatFlags, maxLength int) string {
+	text := c.typeToStringEx(t, enclosingDeclaration, flags)
+	if len(text) <= maxLength {
+		return text
+	}
+	end := max(maxLength-len("..."), 0)
+	for end > 0 && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	return text[:end] + "..."
+}
+
 func (c *Checker) typeToStringEx(t *Type, enclosingDeclaration *ast.Node, flags TypeFormatFlags) string {
 	p := c.newPrinter(flags)
 	if flags&TypeFormatFlagsNoTypeReduction == 0 {
@@ -85,6 +106,58 @@ func (c *Checker) SourceFileWithTypes(sourceFile *ast.SourceFile) string {
 	return p.string()
 }
 
+// SignatureToString prints the type parameters, parameters and return type of a signature,
+// as in `<T>(x: T): void`.
+func (c *Checker) SignatureToString(s *Signature) string {
+	p := c.newPrinter(TypeFormatFlagsNone)
+	p.printSignature(s, ": ")
+	return p.string()
+}
+
+// TypeParametersToString prints the type parameters of a class, interface or type alias,
+// as in `<T extends U>`, or returns an empty string if it has none.
+func (c *Checker) TypeParametersToString(symbol *ast.Symbol) string {
+	typeParameters := c.getLocalTypeParametersOfClassOrInterfaceOrTypeAlias(symbol)
+	if len(typeParameters) == 0 {
+		return ""
+	}
+	p := c.newPrinter(TypeFormatFlagsNone)
+	p.print("<")
+	for i, tp := range typeParameters {
+		if i > 0 {
+			p.print(", ")
+		}
+		p.printTypeParameterAndConstraint(tp)
+	}
+	p.print(">")
+	return p.string()
+}
+
+// SignatureParts are the printed pieces of a signature, for showing its parameters one at a time.
+type SignatureParts struct {
+	TypeParameters []string
+	Parameters     []string
+	ReturnType     string
+}
+
+func (c *Checker) GetSignatureParts(s *Signature) SignatureParts {
+	var parts SignatureParts
+	for _, tp := range s.typeParameters {
+		p := c.newPrinter(TypeFormatFlagsNone)
+		p.printTypeParameterAndConstraint(tp)
+		parts.TypeParameters = append(parts.TypeParameters, p.string())
+	}
+	for i, param := range c.getParametersForPrinting(s) {
+		p := c.newPrinter(TypeFormatFlagsNone)
+		p.printParameter(s, param, i)
+		parts.Parameters = append(parts.Parameters, p.string())
+	}
+	p := c.newPrinter(TypeFormatFlagsNone)
+	p.printReturnType(s)
+	parts.ReturnType = p.string()
+	return parts
+}
+
 func (c *Checker) signatureToString(s *Signature) string {
 	p := c.newPrinter(TypeFormatFlagsNone)
 	if s.flags&SignatureFlagsConstruct != 0 {
@@ -94,6 +167,11 @@ func (c *Checker) signatureToString(s *Signature) string {
 	return p.string()
 }
 
+// TypePredicateToString prints a type predicate, as in `x is string` or `asserts this`.
+func (c *Checker) TypePredicateToString(t *TypePredicate) string {
+	return c.typePredicateToString(t)
+}
+
 func (c *Checker) typePredicateToString(t *TypePredicate) string {
 	p := c.newPrinter(TypeFormatFlagsNone)
 	p.printTypePredicate(t)
@@ -485,30 +563,41 @@ func (p *Printer) printSignature(sig *Signature, returnSeparator string) {
 		p.printType(p.c.getTypeOfSymbol(sig.thisParameter))
 		tail = true
 	}
-	expandedParameters := p.c.GetExpandedParameters(sig)
-	// If the expanded parameter list had a variadic in a non-trailing position, don't expand it
-	parameters := core.IfElse(core.Some(expandedParameters, func(s *ast.Symbol) bool {
-		return s != expandedParameters[len(expandedParameters)-1] && s.CheckFlags&ast.CheckFlagsRestParameter != 0
-	}), sig.parameters, expandedParameters)
-	for i, param := range parameters {
+	for i, param := range p.c.getParametersForPrinting(sig) {
 		if tail {
 			p.print(", ")
 		}
-		if param.ValueDeclaration != nil && isRestParameter(param.ValueDeclaration) || param.CheckFlags&ast.CheckFlagsRestParameter != 0 {
-			p.print("...")
-			p.printName(param)
-		} else {
-			p.printName(param)
-			if i >= p.c.getMinArgumentCountEx(sig, MinArgumentCountFlagsVoidIsNonOptional) {
-				p.print("?")
-			}
-		}
-		p.print(": ")
-		p.printType(p.c.getTypeOfSymbol(param))
+		p.printParameter(sig, param, i)
 		tail = true
 	}
 	p.print(")")
 	p.print(returnSeparator)
+	p.printReturnType(sig)
+}
+
+func (c *Checker) getParametersForPrinting(sig *Signature) []*ast.Symbol {
+	expandedParameters := c.GetExpandedParameters(sig)
+	// If the expanded parameter list had a variadic in a non-trailing position, don't expand it
+	return core.IfElse(core.Some(expandedParameters, func(s *ast.Symbol) bool {
+		return s != expandedParameters[len(expandedParameters)-1] && s.CheckFlags&ast.CheckFlagsRestParameter != 0
+	}), sig.parameters, expandedParameters)
+}
+
+func (p *Printer) printParameter(sig *Signature, param *ast.Symbol, index int) {
+	if param.ValueDeclaration != nil && isRestParameter(param.ValueDeclaration) || param.CheckFlags&ast.CheckFlagsRestParameter != 0 {
+		p.print("...")
+		p.printName(param)
+	} else {
+		p.printName(param)
+		if index >= p.c.getMinArgumentCountEx(sig, MinArgumentCountFlagsVoidIsNonOptional) {
+			p.print("?")
+		}
+	}
+	p.print(": ")
+	p.printType(p.c.getTypeOfSymbol(param))
+}
+
+func (p *Printer) printReturnType(sig *Signature) {
 	if pred := p.c.getTypePredicateOfSignature(sig); pred != nil {
 		p.printTypePredicate(pred)
 	} else {
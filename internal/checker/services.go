@@ -0,0 +1,401 @@
+package checker
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/core"
+)
+
+// This file contains the checker entry points used by the language service. They are thin
+// wrappers around internal checker functionality and must not report any new diagnostics.
+
+func (c *Checker) GetSymbolsInScope(location *ast.Node, meaning ast.SymbolFlags) []*ast.Symbol {
+	if location.Flags&ast.NodeFlagsInWithStatement != 0 {
+		// We cannot answer semantic questions within a with block, do not proceed any further
+		return nil
+	}
+
+	symbols := make(ast.SymbolTable)
+	isStaticSymbol := false
+
+	// Copy the given symbol into symbol tables if the symbol has the given meaning
+	// and it doesn't already exists in the symbol table.
+	copySymbol := func(symbol *ast.Symbol, meaning ast.SymbolFlags) {
+		if getCombinedLocalAndExportSymbolFlags(symbol)&meaning != 0 {
+			id := symbol.Name
+			// We copy all symbols regardless of reserved names, those are
+			// filtered out when the result is converted to a slice below.
+			if _, ok := symbols[id]; !ok {
+				symbols[id] = symbol
+			}
+		}
+	}
+
+	copySymbols := func(source ast.SymbolTable, meaning ast.SymbolFlags) {
+		if meaning != 0 {
+			for _, symbol := range source {
+				copySymbol(symbol, meaning)
+			}
+		}
+	}
+
+	copyLocallyVisibleExportSymbols := func(source ast.SymbolTable, meaning ast.SymbolFlags) {
+		if meaning != 0 {
+			for _, symbol := range source {
+				// Similar condition as in `resolveNameHelper`
+				if ast.GetDeclarationOfKind(symbol, ast.KindExportSpecifier) == nil &&
+					ast.GetDeclarationOfKind(symbol, ast.KindNamespaceExport) == nil &&
+					symbol.Name != ast.InternalSymbolNameDefault {
+					copySymbol(symbol, meaning)
+				}
+			}
+		}
+	}
+
+	for location != nil {
+		if canHaveLocals(location) && location.Locals() != nil && !ast.IsGlobalSourceFile(location) {
+			copySymbols(location.Locals(), meaning)
+		}
+
+		switch location.Kind {
+		case ast.KindSourceFile:
+			if !ast.IsExternalModule(location.AsSourceFile()) {
+				break
+			}
+			fallthrough
+		case ast.KindModuleDeclaration:
+			copyLocallyVisibleExportSymbols(c.getSymbolOfDeclaration(location).Exports, meaning&ast.SymbolFlagsModuleMember)
+		case ast.KindEnumDeclaration:
+			copySymbols(c.getSymbolOfDeclaration(location).Exports, meaning&ast.SymbolFlagsEnumMember)
+		case ast.KindClassExpression:
+			if location.Name() != nil {
+				copySymbol(location.Symbol(), meaning)
+			}
+			// this fall-through is necessary because we would like to handle
+			// type parameter inside class expression similar to how we handle it in classDeclaration and interface Declaration.
+			fallthrough
+		case ast.KindClassDeclaration, ast.KindInterfaceDeclaration:
+			// If we didn't come from static member of class or interface,
+			// add the type parameters into the symbol table
+			// (type parameters of classDeclaration/classExpression and interface are in member property of the symbol.
+			// Note: that the memberFlags come from previous iteration.
+			if !isStaticSymbol {
+				copySymbols(c.getMembersOfSymbol(c.getSymbolOfDeclaration(location)), meaning&ast.SymbolFlagsType)
+			}
+		case ast.KindFunctionExpression:
+			if location.Name() != nil {
+				copySymbol(location.Symbol(), meaning)
+			}
+		}
+
+		if introducesArgumentsExoticObject(location) {
+			copySymbol(c.argumentsSymbol, meaning)
+		}
+
+		isStaticSymbol = ast.IsStatic(location)
+		location = location.Parent
+	}
+
+	copySymbols(c.globals, meaning)
+
+	result := make([]*ast.Symbol, 0, len(symbols))
+	for name, symbol := range symbols {
+		if !isReservedMemberName(name) {
+			result = append(result, symbol)
+		}
+	}
+	c.sortSymbols(result)
+	return result
+}
+
+func getCombinedLocalAndExportSymbolFlags(symbol *ast.Symbol) ast.SymbolFlags {
+	if symbol.ExportSymbol != nil {
+		return symbol.Flags | symbol.ExportSymbol.Flags
+	}
+	return symbol.Flags
+}
+
+func introducesArgumentsExoticObject(node *ast.Node) bool {
+	switch node.Kind {
+	case ast.KindMethodDeclaration, ast.KindMethodSignature, ast.KindConstructor, ast.KindGetAccessor,
+		ast.KindSetAccessor, ast.KindFunctionDeclaration, ast.KindFunctionExpression:
+		return true
+	}
+	return false
+}
+
+func (c *Checker) GetExportSymbolOfSymbol(symbol *ast.Symbol) *ast.Symbol {
+	if symbol.ExportSymbol != nil {
+		symbol = symbol.ExportSymbol
+	}
+	return c.getMergedSymbol(symbol)
+}
+
+func (c *Checker) GetApparentType(t *Type) *Type {
+	return c.getApparentType(t)
+}
+
+func (c *Checker) GetNonNullableType(t *Type) *Type {
+	return c.getNonNullableType(t)
+}
+
+func (c *Checker) GetPropertiesOfType(t *Type) []*ast.Symbol {
+	return c.getPropertiesOfType(t)
+}
+
+func (c *Checker) GetPropertyOfType(t *Type, name string) *ast.Symbol {
+	return c.getPropertyOfType(t, name)
+}
+
+func (c *Checker) IsValidPropertyAccessForCompletions(node *ast.Node, t *Type, property *ast.Symbol) bool {
+	return c.isValidPropertyAccessForCompletions(node, t, property)
+}
+
+func (c *Checker) GetExportsOfModule(moduleSymbol *ast.Symbol) []*ast.Symbol {
+	exports := c.getExportsOfModule(moduleSymbol)
+	result := make([]*ast.Symbol, 0, len(exports))
+	for name, symbol := range exports {
+		if !isReservedMemberName(name) {
+			result = append(result, symbol)
+		}
+	}
+	c.sortSymbols(result)
+	return result
+}
+
+func (c *Checker) GetContextualType(node *ast.Node, contextFlags ContextFlags) *Type {
+	return c.getContextualType(node, contextFlags)
+}
+
+func (c *Checker) GetShorthandAssignmentValueSymbol(node *ast.Node) *ast.Symbol {
+	if node != nil && ast.IsShorthandPropertyAssignment(node) {
+		return c.resolveEntityName(node.Name(), ast.SymbolFlagsValue|ast.SymbolFlagsAlias, true /*ignoreErrors*/, false /*dontResolveAlias*/, nil /*location*/)
+	}
+	return nil
+}
+
+// GetRootSymbols returns the declared symbols that a synthetic union or intersection property,
+// an instantiated symbol, or a spread or mapped property was derived from.
+func (c *Checker) GetRootSymbols(symbol *ast.Symbol) []*ast.Symbol {
+	roots := c.getImmediateRootSymbols(symbol)
+	if len(roots) == 0 {
+		return []*ast.Symbol{symbol}
+	}
+	var result []*ast.Symbol
+	for _, root := range roots {
+		result = append(result, c.GetRootSymbols(root)...)
+	}
+	return result
+}
+
+func (c *Checker) getImmediateRootSymbols(symbol *ast.Symbol) []*ast.Symbol {
+	if symbol.CheckFlags&ast.CheckFlagsSynthetic != 0 {
+		var result []*ast.Symbol
+		if links := c.valueSymbolLinks.TryGet(symbol); links != nil && links.containingType != nil {
+			for _, t := range links.containingType.Types() {
+				if prop := c.getPropertyOfType(t, symbol.Name); prop != nil {
+					result = append(result, prop)
+				}
+			}
+		}
+		return result
+	}
+	if symbol.Flags&ast.SymbolFlagsTransient != 0 {
+		if links := c.spreadLinks.TryGet(symbol); links != nil && links.leftSpread != nil {
+			return []*ast.Symbol{links.leftSpread, links.rightSpread}
+		}
+		if links := c.mappedSymbolLinks.TryGet(symbol); links != nil && links.syntheticOrigin != nil {
+			return []*ast.Symbol{links.syntheticOrigin}
+		}
+		var target *ast.Symbol
+		for next := symbol; ; {
+			links := c.valueSymbolLinks.TryGet(next)
+			if links == nil || links.target == nil {
+				break
+			}
+			target = links.target
+			next = target
+		}
+		if target != nil {
+			return []*ast.Symbol{target}
+		}
+	}
+	return nil
+}
+
+func (c *Checker) GetSignaturesOfType(t *Type, kind SignatureKind) []*Signature {
+	return c.getSignaturesOfType(t, kind)
+}
+
+func (c *Checker) GetDeclaredTypeOfSymbol(symbol *ast.Symbol) *Type {
+	return c.getDeclaredTypeOfSymbol(symbol)
+}
+
+func (c *Checker) GetResolvedSignature(node *ast.Node) *Signature {
+	return c.getResolvedSignature(node, nil /*candidatesOutArray*/, CheckModeNormal)
+}
+
+// GetConstantValue returns the value of an enum member, or nil if it is not a constant.
+func (c *Checker) GetConstantValue(node *ast.Node) any {
+	if ast.IsEnumMember(node) {
+		return c.getEnumMemberValue(node).Value
+	}
+	return nil
+}
+
+// GetResolvedSignatureForSignatureHelp resolves a call-like node as signature help sees it, along
+// with the candidate signatures that were considered. argumentCount is the number of arguments the
+// call appears to have while it is being written, which can be more than it has so far.
+func (c *Checker) GetResolvedSignatureForSignatureHelp(node *ast.Node, argumentCount int) (*Signature, []*Signature) {
+	var signature *Signature
+	var candidates []*Signature
+	c.runWithoutResolvedSignatureCaching(node, func() {
+		c.apparentArgumentCount = &argumentCount
+		signature = c.getResolvedSignature(node, &candidates, CheckModeIsForSignatureHelp)
+		c.apparentArgumentCount = nil
+	})
+	return signature, candidates
+}
+
+// runWithoutResolvedSignatureCaching runs fn with the resolved signatures of node and its enclosing
+// calls cleared, and restores them afterwards, so that resolution for signature help is not cached.
+func (c *Checker) runWithoutResolvedSignatureCaching(node *ast.Node, fn func()) {
+	type cachedSignature struct {
+		links     *SignatureLinks
+		signature *Signature
+	}
+	var cached []cachedSignature
+	for n := ast.FindAncestor(node, isCallLikeExpression); n != nil; n = ast.FindAncestor(n.Parent, isCallLikeExpression) {
+		links := c.signatureLinks.Get(n)
+		cached = append(cached, cachedSignature{links, links.resolvedSignature})
+		links.resolvedSignature = nil
+	}
+	fn()
+	for _, entry := range cached {
+		entry.links.resolvedSignature = entry.signature
+	}
+}
+
+func (c *Checker) GetSignatureFromDeclaration(declaration *ast.Node) *Signature {
+	return c.getSignatureFromDeclaration(declaration)
+}
+
+func (c *Checker) GetReturnTypeOfSignature(sig *Signature) *Type {
+	return c.getReturnTypeOfSignature(sig)
+}
+
+// GetTypePredicateOfSignature returns the declared or inferred type predicate of a signature, or
+// nil if it has none.
+func (c *Checker) GetTypePredicateOfSignature(sig *Signature) *TypePredicate {
+	return c.getTypePredicateOfSignature(sig)
+}
+
+// GetParameterIdentifierAtPosition returns the name of the parameter that receives the argument at
+// pos of a call to signature, which is a named tuple member when the signature has a tuple rest
+// parameter. isRest reports whether the name receives the remaining arguments of the call. It
+// returns nil if the parameter is not named by an identifier.
+func (c *Checker) GetParameterIdentifierAtPosition(sig *Signature, pos int) (name *ast.Node, isRest bool) {
+	paramCount := len(sig.parameters) - core.IfElse(signatureHasRestParameter(sig), 1, 0)
+	if pos < paramCount {
+		return getParameterDeclarationIdentifier(sig.parameters[pos]), false
+	}
+	if paramCount == len(sig.parameters) {
+		return nil, false
+	}
+	restParameter := sig.parameters[paramCount]
+	restIdentifier := getParameterDeclarationIdentifier(restParameter)
+	if restIdentifier == nil {
+		return nil, false
+	}
+	restType := c.getTypeOfSymbol(restParameter)
+	if isTupleType(restType) {
+		elementInfos := restType.TargetTupleType().elementInfos
+		index := pos - paramCount
+		if index >= len(elementInfos) {
+			return nil, false
+		}
+		declaration := elementInfos[index].labeledDeclaration
+		if declaration == nil || !ast.IsIdentifier(declaration.Name()) {
+			return nil, false
+		}
+		if ast.IsNamedTupleMember(declaration) {
+			return declaration.Name(), declaration.AsNamedTupleMember().DotDotDotToken != nil
+		}
+		return declaration.Name(), declaration.AsParameterDeclaration().DotDotDotToken != nil
+	}
+	if pos == paramCount {
+		return restIdentifier, true
+	}
+	return nil, false
+}
+
+func getParameterDeclarationIdentifier(symbol *ast.Symbol) *ast.Node {
+	if declaration := symbol.ValueDeclaration; declaration != nil && ast.IsParameter(declaration) && ast.IsIdentifier(declaration.Name()) {
+		return declaration.Name()
+	}
+	return nil
+}
+
+// GetRequiredTupleElementCount returns the number of required elements that a tuple type starts
+// with, or 0 if the type is not a tuple.
+func (c *Checker) GetRequiredTupleElementCount(t *Type) int {
+	if !isTupleType(t) {
+		return 0
+	}
+	elementInfos := t.TargetTupleType().elementInfos
+	for i, info := range elementInfos {
+		if info.flags&ElementFlagsRequired == 0 {
+			return i
+		}
+	}
+	return len(elementInfos)
+}
+
+func (c *Checker) GetTypeArguments(t *Type) []*Type {
+	return c.getTypeArguments(t)
+}
+
+func (c *Checker) IsArrayOrTupleType(t *Type) bool {
+	return c.isArrayOrTupleType(t)
+}
+
+func (c *Checker) GetSuggestedSymbolForNonexistentSymbol(location *ast.Node, name string, meaning ast.SymbolFlags) *ast.Symbol {
+	return c.getSuggestedSymbolForNonexistentSymbol(location, name, meaning)
+}
+
+func (c *Checker) GetSuggestedSymbolForNonexistentProperty(name *ast.Node, containingType *Type) *ast.Symbol {
+	return c.getSuggestedSymbolForNonexistentProperty(name, containingType)
+}
+
+func (c *Checker) GetSuggestedSymbolForNonexistentModule(name *ast.Node, targetModule *ast.Symbol) *ast.Symbol {
+	return c.getSuggestedSymbolForNonexistentModule(name, targetModule)
+}
+
+// GetPromisedTypeOfPromise returns the type that a promise-like type resolves to, or nil if the
+// type is not promise-like.
+func (c *Checker) GetPromisedTypeOfPromise(t *Type) *Type {
+	return c.getPromisedTypeOfPromise(t)
+}
+
+// GetBaseTypeOfLiteralType returns the primitive type of a literal type, as in number for 1,
+// and the widened form of any other type.
+func (c *Checker) GetBaseTypeOfLiteralType(t *Type) *Type {
+	return c.getWidenedType(c.getBaseTypeOfLiteralType(t))
+}
+
+// GetAmbientModules returns the symbols of the modules that global declarations declare by name,
+// as in declare module "name", sorted by name.
+func (c *Checker) GetAmbientModules() []*ast.Symbol {
+	var modules []*ast.Symbol
+	for name, symbol := range c.globals {
+		if len(name) >= 2 && strings.HasPrefix(name, "\"") && strings.HasSuffix(name, "\"") {
+			modules = append(modules, symbol)
+		}
+	}
+	slices.SortFunc(modules, func(a, b *ast.Symbol) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return modules
+}
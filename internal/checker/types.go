@@ -318,6 +318,7 @@ type NodeLinks struct {
 	flags                                NodeCheckFlags // Set of flags specific to Node
 	declarationRequiresScopeChange       core.Tristate  // Set by `useOuterVariableScopeInParameter` in checker when downlevel emit would change the name resolution scope inside of a parameter.
 	hasReportedStatementInAmbientContext bool           // Cache boolean if we report statements in ambient context
+	isVisible                            core.Tristate  // Is this node visible to declaration emit
 }
 
 type SymbolNodeLinks struct {
@@ -345,6 +346,7 @@ type AssertionLinks struct {
 
 type SourceFileLinks struct {
 	typeChecked               bool
+	unusedSuggestionsChecked  bool
 	deferredNodes             collections.OrderedSet[*ast.Node]
 	identifierCheckNodes      []*ast.Node
 	localJsxNamespace         string
@@ -546,6 +548,14 @@ func (t *Type) Flags() TypeFlags {
 	return t.flags
 }
 
+func (t *Type) Symbol() *ast.Symbol {
+	return t.symbol
+}
+
+func (t *Type) Alias() *TypeAlias {
+	return t.alias
+}
+
 // Casts for concrete struct types
 
 func (t *Type) AsIntrinsicType() *IntrinsicType             { return t.data.(*IntrinsicType) }
@@ -680,6 +690,8 @@ type LiteralType struct {
 	regularType *Type // Regular version of type
 }
 
+func (t *LiteralType) Value() any { return t.value }
+
 // UniqueESSymbolTypeData
 
 type UniqueESSymbolType struct {
@@ -1042,6 +1054,10 @@ type Signature struct {
 	composite                *CompositeSignature
 }
 
+func (s *Signature) Declaration() *ast.Node {
+	return s.declaration
+}
+
 type CompositeSignature struct {
 	isUnion    bool         // True for union, false for intersection
 	signatures []*Signature // Individual signatures
@@ -0,0 +1,413 @@
+package checker
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/printer"
+)
+
+func (c *Checker) isDeclarationVisible(node *ast.Node) bool {
+	if node == nil {
+		return false
+	}
+	links := c.nodeLinks.Get(node)
+	if links.isVisible == core.TSUnknown {
+		links.isVisible = core.IfElse(c.determineIfDeclarationIsVisible(node), core.TSTrue, core.TSFalse)
+	}
+	return links.isVisible == core.TSTrue
+}
+
+func (c *Checker) determineIfDeclarationIsVisible(node *ast.Node) bool {
+	switch node.Kind {
+	case ast.KindBindingElement:
+		return c.isDeclarationVisible(node.Parent.Parent)
+	case ast.KindVariableDeclaration:
+		name := node.Name()
+		if ast.IsBindingPattern(name) && len(name.AsBindingPattern().Elements.Nodes) == 0 {
+			// If the binding pattern is empty, this variable declaration is not visible
+			return false
+		}
+		fallthrough
+	case ast.KindModuleDeclaration,
+		ast.KindClassDeclaration,
+		ast.KindInterfaceDeclaration,
+		ast.KindTypeAliasDeclaration,
+		ast.KindFunctionDeclaration,
+		ast.KindEnumDeclaration,
+		ast.KindImportEqualsDeclaration:
+		// external module augmentation is always visible
+		if isExternalModuleAugmentation(node) {
+			return true
+		}
+		parent := getDeclarationContainer(node)
+		// If the node is not exported or it is not ambient module element (except import declaration)
+		if c.getCombinedModifierFlagsCached(node)&ast.ModifierFlagsExport == 0 &&
+			!(node.Kind != ast.KindImportEqualsDeclaration && parent.Kind != ast.KindSourceFile && parent.Flags&ast.NodeFlagsAmbient != 0) {
+			return ast.IsGlobalSourceFile(parent)
+		}
+		// Exported members/ambient module elements (exception import declaration) are visible if parent is visible
+		return c.isDeclarationVisible(parent)
+	case ast.KindPropertyDeclaration,
+		ast.KindPropertySignature,
+		ast.KindGetAccessor,
+		ast.KindSetAccessor,
+		ast.KindMethodDeclaration,
+		ast.KindMethodSignature:
+		if ast.HasSyntacticModifier(node, ast.ModifierFlagsPrivate|ast.ModifierFlagsProtected) {
+			// Private/protected properties/methods are not visible
+			return false
+		}
+		// Public properties/methods are visible if its parents are visible, so:
+		fallthrough
+	case ast.KindConstructor,
+		ast.KindConstructSignature,
+		ast.KindCallSignature,
+		ast.KindIndexSignature,
+		ast.KindParameter,
+		ast.KindModuleBlock,
+		ast.KindFunctionType,
+		ast.KindConstructorType,
+		ast.KindTypeLiteral,
+		ast.KindTypeReference,
+		ast.KindArrayType,
+		ast.KindTupleType,
+		ast.KindUnionType,
+		ast.KindIntersectionType,
+		ast.KindParenthesizedType,
+		ast.KindNamedTupleMember:
+		return c.isDeclarationVisible(node.Parent)
+	case ast.KindImportClause,
+		ast.KindNamespaceImport,
+		ast.KindImportSpecifier:
+		// Default binding, import specifier and namespace import is visible
+		// only on demand so by default it is not visible
+		return false
+	case ast.KindTypeParameter,
+		ast.KindSourceFile,
+		ast.KindNamespaceExportDeclaration:
+		// Type parameters are always visible
+		// Source file and namespace export are always visible
+		return true
+	case ast.KindExportAssignment:
+		// Export assignments do not create name bindings outside the module
+		return false
+	}
+	return false
+}
+
+func getDeclarationContainer(node *ast.Node) *ast.Node {
+	return ast.FindAncestor(ast.GetRootDeclaration(node), func(n *ast.Node) bool {
+		switch n.Kind {
+		case ast.KindVariableDeclaration,
+			ast.KindVariableDeclarationList,
+			ast.KindImportSpecifier,
+			ast.KindNamedImports,
+			ast.KindNamespaceImport,
+			ast.KindImportClause:
+			return false
+		}
+		return true
+	}).Parent
+}
+
+func getAnyImportSyntax(node *ast.Node) *ast.Node {
+	switch node.Kind {
+	case ast.KindImportEqualsDeclaration:
+		return node
+	case ast.KindImportClause:
+		return node.Parent
+	case ast.KindNamespaceImport:
+		return node.Parent.Parent
+	case ast.KindImportSpecifier:
+		return node.Parent.Parent.Parent
+	}
+	return nil
+}
+
+// Reports whether a statement is emitted in a declaration file only when a declaration that is
+// emitted references it.
+func IsLateVisibilityPaintedStatement(node *ast.Node) bool {
+	switch node.Kind {
+	case ast.KindImportDeclaration,
+		ast.KindImportEqualsDeclaration,
+		ast.KindVariableStatement,
+		ast.KindClassDeclaration,
+		ast.KindFunctionDeclaration,
+		ast.KindModuleDeclaration,
+		ast.KindTypeAliasDeclaration,
+		ast.KindInterfaceDeclaration,
+		ast.KindEnumDeclaration:
+		return true
+	}
+	return false
+}
+
+func hasNonGlobalAugmentationExternalModuleSymbol(declaration *ast.Node) bool {
+	return ast.IsModuleDeclaration(declaration) && declaration.Name().Kind == ast.KindStringLiteral || declaration.Kind == ast.KindSourceFile && ast.IsExternalOrCommonJSModule(declaration.AsSourceFile())
+}
+
+func hasExternalModuleSymbol(declaration *ast.Node) bool {
+	return ast.IsAmbientModule(declaration) || declaration.Kind == ast.KindSourceFile && ast.IsExternalOrCommonJSModule(declaration.AsSourceFile())
+}
+
+func getQualifiedLeftMeaning(rightMeaning ast.SymbolFlags) ast.SymbolFlags {
+	// If we are looking in value space, the parent meaning is value, other wise it is namespace
+	if rightMeaning == ast.SymbolFlagsValue {
+		return ast.SymbolFlagsValue
+	}
+	return ast.SymbolFlagsNamespace
+}
+
+func (c *Checker) getExternalModuleContainer(declaration *ast.Node) *ast.Symbol {
+	node := ast.FindAncestor(declaration, hasExternalModuleSymbol)
+	if node == nil {
+		return nil
+	}
+	return c.getSymbolOfDeclaration(node)
+}
+
+// Reports whether the symbol found by name resolution refers to the given symbol.
+func (c *Checker) isSameSymbolReference(resolved *ast.Symbol, symbol *ast.Symbol) bool {
+	if resolved == symbol || c.getMergedSymbol(resolved) == c.getMergedSymbol(symbol) {
+		return true
+	}
+	if c.getExportSymbolOfValueSymbolIfExported(resolved) == c.getMergedSymbol(symbol) {
+		return true
+	}
+	if resolved.Flags&ast.SymbolFlagsAlias != 0 {
+		target := c.resolveAlias(resolved)
+		return target == symbol || target == c.getMergedSymbol(symbol)
+	}
+	return false
+}
+
+// Returns the symbols through which the given symbol can be referenced from the enclosing declaration using a
+// simple name, or nil if the symbol is not directly in scope there. The returned chain contains a single symbol,
+// which is either the symbol itself or an alias (e.g. an import) that resolves to it.
+func (c *Checker) getAccessibleSymbolChain(symbol *ast.Symbol, enclosingDeclaration *ast.Node, meaning ast.SymbolFlags) []*ast.Symbol {
+	if symbol == nil || enclosingDeclaration == nil || isReservedMemberName(symbol.Name) || symbol.Flags&ast.SymbolFlagsTypeParameter != 0 && meaning&ast.SymbolFlagsValue != 0 && meaning&ast.SymbolFlagsType == 0 {
+		return nil
+	}
+	if resolved := c.resolveName(enclosingDeclaration, symbol.Name, meaning, nil /*nameNotFoundMessage*/, false /*isUse*/, false /*excludeGlobals*/); resolved != nil && c.isSameSymbolReference(resolved, symbol) {
+		return []*ast.Symbol{resolved}
+	}
+	// Look for an alias in an enclosing scope that refers to the symbol under a different name
+	for location := enclosingDeclaration; location != nil; location = location.Parent {
+		locals := location.Locals()
+		if len(locals) == 0 {
+			continue
+		}
+		for _, name := range slices.Sorted(maps.Keys(locals)) {
+			local := locals[name]
+			if local.Flags&ast.SymbolFlagsAlias == 0 || name == symbol.Name || isReservedMemberName(name) {
+				continue
+			}
+			if c.isSameSymbolReference(local, symbol) {
+				if resolved := c.resolveName(enclosingDeclaration, name, meaning, nil /*nameNotFoundMessage*/, false /*isUse*/, false /*excludeGlobals*/); resolved == local {
+					return []*ast.Symbol{local}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Checker) isSymbolAccessible(symbol *ast.Symbol, enclosingDeclaration *ast.Node, meaning ast.SymbolFlags, shouldComputeAliasesToMakeVisible bool) printer.SymbolAccessibilityResult {
+	return c.isSymbolAccessibleWorker(symbol, enclosingDeclaration, meaning, shouldComputeAliasesToMakeVisible, true /*allowModules*/)
+}
+
+func (c *Checker) isSymbolAccessibleWorker(symbol *ast.Symbol, enclosingDeclaration *ast.Node, meaning ast.SymbolFlags, shouldComputeAliasesToMakeVisible bool, allowModules bool) printer.SymbolAccessibilityResult {
+	if symbol != nil && enclosingDeclaration != nil {
+		if result := c.isAnySymbolAccessible([]*ast.Symbol{symbol}, enclosingDeclaration, symbol, meaning, shouldComputeAliasesToMakeVisible, allowModules); result != nil {
+			return *result
+		}
+		// This could be a symbol that is not exported in the external module
+		// or it could be a symbol from different external module that is not aliased and hence cannot be named
+		var symbolExternalModule *ast.Symbol
+		for _, declaration := range symbol.Declarations {
+			if symbolExternalModule = c.getExternalModuleContainer(declaration); symbolExternalModule != nil {
+				break
+			}
+		}
+		if symbolExternalModule != nil {
+			enclosingExternalModule := c.getExternalModuleContainer(enclosingDeclaration)
+			if symbolExternalModule != enclosingExternalModule {
+				// name from different external module that is not visible
+				result := printer.SymbolAccessibilityResult{
+					Accessibility:   printer.SymbolAccessibilityCannotBeNamed,
+					ErrorSymbolName: c.symbolToString(symbol),
+					ErrorModuleName: c.symbolToString(symbolExternalModule),
+				}
+				if ast.IsInJSFile(enclosingDeclaration) {
+					result.ErrorNode = enclosingDeclaration
+				}
+				return result
+			}
+		}
+		// Just a local name that is not accessible
+		return printer.SymbolAccessibilityResult{
+			Accessibility:   printer.SymbolAccessibilityNotAccessible,
+			ErrorSymbolName: c.symbolToString(symbol),
+		}
+	}
+	return printer.SymbolAccessibilityResult{Accessibility: printer.SymbolAccessibilityAccessible}
+}
+
+func (c *Checker) isAnySymbolAccessible(symbols []*ast.Symbol, enclosingDeclaration *ast.Node, initialSymbol *ast.Symbol, meaning ast.SymbolFlags, shouldComputeAliasesToMakeVisible bool, allowModules bool) *printer.SymbolAccessibilityResult {
+	if len(symbols) == 0 {
+		return nil
+	}
+	var hadAccessibleChain *ast.Symbol
+	earlyModuleBail := false
+	for _, symbol := range symbols {
+		// Symbol is accessible if it by itself is accessible
+		if chain := c.getAccessibleSymbolChain(symbol, enclosingDeclaration, meaning); chain != nil {
+			hadAccessibleChain = symbol
+			if result := c.hasVisibleDeclarations(chain[0], shouldComputeAliasesToMakeVisible); result != nil {
+				return result
+			}
+		}
+		if allowModules && core.Some(symbol.Declarations, hasNonGlobalAugmentationExternalModuleSymbol) {
+			if shouldComputeAliasesToMakeVisible {
+				earlyModuleBail = true
+				// Generally speaking, we want to use the aliases that already exist to refer to a module, if present
+				// In order to do so, we need to find those aliases in order to retain them in declaration emit; so
+				// if we are in declaration emit, we cannot use the fast path for module visibility until we've exhausted
+				// all other visibility options (in order to capture the possible aliases used to reference the module)
+				continue
+			}
+			// Any meaning of a module symbol is always accessible via an `import` type
+			return &printer.SymbolAccessibilityResult{Accessibility: printer.SymbolAccessibilityAccessible}
+		}
+		// If we haven't got the accessible symbol, it doesn't mean the symbol is actually inaccessible.
+		// It could be a qualified symbol and hence verify the path
+		// e.g.:
+		// module m {
+		//     export class c {
+		//     }
+		// }
+		// const x: typeof m.c
+		// In the above example when we start with checking if typeof m.c symbol is accessible,
+		// we are going to see if c can be accessed in scope directly.
+		// But it can't, hence the accessible is going to be undefined, but that doesn't mean m.c is inaccessible
+		// It is accessible if the parent m is accessible because then m.c can be accessed through qualification
+		var containers []*ast.Symbol
+		if parent := c.getParentOfSymbol(symbol); parent != nil {
+			containers = []*ast.Symbol{parent}
+		}
+		parentMeaning := meaning
+		if initialSymbol == symbol {
+			parentMeaning = getQualifiedLeftMeaning(meaning)
+		}
+		if parentResult := c.isAnySymbolAccessible(containers, enclosingDeclaration, initialSymbol, parentMeaning, shouldComputeAliasesToMakeVisible, allowModules); parentResult != nil {
+			return parentResult
+		}
+	}
+	if earlyModuleBail {
+		return &printer.SymbolAccessibilityResult{Accessibility: printer.SymbolAccessibilityAccessible}
+	}
+	if hadAccessibleChain != nil {
+		result := &printer.SymbolAccessibilityResult{
+			Accessibility:   printer.SymbolAccessibilityNotAccessible,
+			ErrorSymbolName: c.symbolToString(initialSymbol),
+		}
+		if hadAccessibleChain != initialSymbol {
+			result.ErrorModuleName = c.symbolToString(hadAccessibleChain)
+		}
+		return result
+	}
+	return nil
+}
+
+func (c *Checker) hasVisibleDeclarations(symbol *ast.Symbol, shouldComputeAliasToMakeVisible bool) *printer.SymbolAccessibilityResult {
+	var aliasesToMakeVisible []*ast.Node
+	addVisibleAlias := func(declaration *ast.Node, aliasingStatement *ast.Node) {
+		// Only lookup the alias statements if asked for
+		if shouldComputeAliasToMakeVisible {
+			c.nodeLinks.Get(declaration).isVisible = core.TSTrue
+			if !slices.Contains(aliasesToMakeVisible, aliasingStatement) {
+				aliasesToMakeVisible = append(aliasesToMakeVisible, aliasingStatement)
+			}
+		}
+	}
+	for _, declaration := range symbol.Declarations {
+		if ast.IsIdentifier(declaration) {
+			// Expando assignments are not declarations that need to be made visible
+			continue
+		}
+		if c.isDeclarationVisible(declaration) {
+			continue
+		}
+		// Mark the unexported alias as visible if its parent is visible
+		// because these kind of aliases can be used to name types in declaration file
+		if anyImportSyntax := getAnyImportSyntax(declaration); anyImportSyntax != nil &&
+			!ast.HasSyntacticModifier(anyImportSyntax, ast.ModifierFlagsExport) &&
+			c.isDeclarationVisible(anyImportSyntax.Parent) {
+			addVisibleAlias(declaration, anyImportSyntax)
+			continue
+		}
+		if ast.IsVariableDeclaration(declaration) && ast.IsVariableStatement(declaration.Parent.Parent) &&
+			!ast.HasSyntacticModifier(declaration.Parent.Parent, ast.ModifierFlagsExport) &&
+			c.isDeclarationVisible(declaration.Parent.Parent.Parent) {
+			addVisibleAlias(declaration, declaration.Parent.Parent)
+			continue
+		}
+		if IsLateVisibilityPaintedStatement(declaration) && // unexported top-level statement
+			!ast.HasSyntacticModifier(declaration, ast.ModifierFlagsExport) &&
+			c.isDeclarationVisible(declaration.Parent) {
+			addVisibleAlias(declaration, declaration)
+			continue
+		}
+		// Declaration is not visible
+		return nil
+	}
+	return &printer.SymbolAccessibilityResult{
+		Accessibility:        printer.SymbolAccessibilityAccessible,
+		AliasesToMakeVisible: aliasesToMakeVisible,
+	}
+}
+
+func (c *Checker) isEntityNameVisible(entityName *ast.Node, enclosingDeclaration *ast.Node) printer.SymbolAccessibilityResult {
+	// get symbol of the first identifier of the entityName
+	var meaning ast.SymbolFlags
+	switch {
+	case entityName.Parent.Kind == ast.KindTypeQuery,
+		entityName.Parent.Kind == ast.KindExpressionWithTypeArguments && !ast.IsPartOfTypeNode(entityName.Parent),
+		entityName.Parent.Kind == ast.KindComputedPropertyName:
+		// Typeof value
+		meaning = ast.SymbolFlagsValue | ast.SymbolFlagsExportValue
+	case entityName.Kind == ast.KindQualifiedName || entityName.Kind == ast.KindPropertyAccessExpression ||
+		entityName.Parent.Kind == ast.KindImportEqualsDeclaration:
+		// Left identifier from type reference or TypeAlias
+		// Entity name of the import declaration
+		meaning = ast.SymbolFlagsNamespace
+	default:
+		// Type Reference or TypeAlias entity = Identifier
+		meaning = ast.SymbolFlagsType
+	}
+	firstIdentifier := ast.GetFirstIdentifier(entityName)
+	symbol := c.resolveName(enclosingDeclaration, firstIdentifier.Text(), meaning, nil /*nameNotFoundMessage*/, false /*isUse*/, false /*excludeGlobals*/)
+	if symbol != nil && symbol.Flags&ast.SymbolFlagsTypeParameter != 0 && meaning&ast.SymbolFlagsType != 0 {
+		return printer.SymbolAccessibilityResult{Accessibility: printer.SymbolAccessibilityAccessible}
+	}
+	if symbol == nil && ast.IsThisIdentifier(firstIdentifier) {
+		container := ast.GetThisContainer(firstIdentifier, false /*includeArrowFunctions*/, false /*includeClassComputedPropertyName*/)
+		if c.isSymbolAccessible(c.getSymbolOfDeclaration(container), firstIdentifier, meaning, false /*shouldComputeAliasesToMakeVisible*/).Accessibility == printer.SymbolAccessibilityAccessible {
+			return printer.SymbolAccessibilityResult{Accessibility: printer.SymbolAccessibilityAccessible}
+		}
+	}
+	if symbol != nil {
+		// Verify if the symbol is accessible
+		if result := c.hasVisibleDeclarations(symbol, true /*shouldComputeAliasToMakeVisible*/); result != nil {
+			return *result
+		}
+	}
+	return printer.SymbolAccessibilityResult{
+		Accessibility:   printer.SymbolAccessibilityNotAccessible,
+		ErrorSymbolName: firstIdentifier.Text(),
+		ErrorNode:       firstIdentifier,
+	}
+}
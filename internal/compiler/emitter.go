@@ -3,6 +3,7 @@ package compiler
 import (
 	"encoding/base64"
 	"strings"
+	"sync"
 
 	"github.com/microsoft/typescript-go/internal/ast"
 	"github.com/microsoft/typescript-go/internal/binder"
@@ -12,6 +13,7 @@ import (
 	"github.com/microsoft/typescript-go/internal/sourcemap"
 	"github.com/microsoft/typescript-go/internal/stringutil"
 	"github.com/microsoft/typescript-go/internal/transformers"
+	"github.com/microsoft/typescript-go/internal/tsoptions"
 	"github.com/microsoft/typescript-go/internal/tspath"
 )
 
@@ -21,7 +23,6 @@ const (
 	emitAll emitOnly = iota
 	emitOnlyJs
 	emitOnlyDts
-	emitOnlyBuildInfo
 )
 
 type emitter struct {
@@ -40,7 +41,6 @@ func (e *emitter) emit() {
 	// !!! tracing
 	e.emitJSFile(e.sourceFile, e.paths.jsFilePath, e.paths.sourceMapFilePath)
 	e.emitDeclarationFile(e.sourceFile, e.paths.declarationFilePath, e.paths.declarationMapPath)
-	e.emitBuildInfo(e.paths.buildInfoPath)
 }
 
 func (e *emitter) getModuleTransformer(emitContext *printer.EmitContext, resolver binder.ReferenceResolver, sourceFileMetaDataProvider printer.SourceFileMetaDataProvider) *transformers.Transformer {
@@ -140,22 +140,109 @@ func (e *emitter) emitJSFile(sourceFile *ast.SourceFile, jsFilePath string, sour
 }
 
 func (e *emitter) emitDeclarationFile(sourceFile *ast.SourceFile, declarationFilePath string, declarationMapPath string) {
-	// !!!
+	options := e.host.Options()
+
+	if sourceFile == nil || e.emitOnly != emitAll && e.emitOnly != emitOnlyDts || len(declarationFilePath) == 0 {
+		return
+	}
+
+	if sourceFile.IsDeclarationFile || ast.IsJsonSourceFile(sourceFile) {
+		return
+	}
+
+	if options.NoEmit == core.TSTrue || e.host.IsEmitBlocked(declarationFilePath) {
+		return
+	}
+
+	emitContext := printer.NewEmitContext()
+	emitResolver := e.host.GetEmitResolver(sourceFile, false /*skipDiagnostics*/)
+	transformer := transformers.NewDeclarationTransformer(emitContext, options, emitResolver)
+	sourceFile = transformer.TransformSourceFile(sourceFile)
+
+	declarationDiagnostics := transformer.Diagnostics()
+	for _, diagnostic := range declarationDiagnostics {
+		e.emitterDiagnostics.Add(diagnostic)
+	}
+
+	if len(declarationDiagnostics) > 0 && options.NoEmitOnError.IsTrue() {
+		e.emitSkipped = true
+		return
+	}
+
+	// !!! outFile not implemented; its declarations would be bundled into a single file, so only the diagnostics
+	// are reported
+	if options.OutFile != "" {
+		return
+	}
+
+	printerOptions := printer.PrinterOptions{
+		RemoveComments:              options.RemoveComments.IsTrue(),
+		NewLine:                     options.NewLine,
+		NoEmitHelpers:               true,
+		SourceMap:                   options.DeclarationMap.IsTrue(),
+		OnlyPrintJSDocStyle:         true,
+		OmitBraceSourceMapPositions: true,
+	}
+
+	// create a printer to print the nodes
+	printer := printer.NewPrinter(printerOptions, printer.PrintHandlers{}, emitContext)
+
+	// Declaration maps are always written to a separate file, so neither of the `inline` options are passed through
+	mapOptions := &core.CompilerOptions{
+		SourceMap:  options.DeclarationMap,
+		SourceRoot: options.SourceRoot,
+		MapRoot:    options.MapRoot,
+	}
+	e.printSourceFileWithMapOptions(declarationFilePath, declarationMapPath, sourceFile, printer, mapOptions)
+
+	if e.emittedFilesList != nil {
+		e.emittedFilesList = append(e.emittedFilesList, declarationFilePath)
+		if declarationMapPath != "" {
+			e.emittedFilesList = append(e.emittedFilesList, declarationMapPath)
+		}
+	}
 }
 
-func (e *emitter) emitBuildInfo(buildInfoPath string) {
-	// !!!
+// getDeclarationDiagnostics runs the declaration transform over a source file without printing the result, returning
+// any diagnostics that declaration emit would report.
+func getDeclarationDiagnostics(host EmitHost, sourceFile *ast.SourceFile) []*ast.Diagnostic {
+	emitContext := printer.NewEmitContext()
+	emitResolver := host.GetEmitResolver(sourceFile, false /*skipDiagnostics*/)
+	transformer := transformers.NewDeclarationTransformer(emitContext, host.Options(), emitResolver)
+	transformer.TransformSourceFile(sourceFile)
+	return transformer.Diagnostics()
+}
+
+// getDeclarationText runs declaration emit over a source file and returns the printed declarations without writing
+// them anywhere.
+func getDeclarationText(host EmitHost, sourceFile *ast.SourceFile) string {
+	options := host.Options()
+	emitContext := printer.NewEmitContext()
+	emitResolver := host.GetEmitResolver(sourceFile, false /*skipDiagnostics*/)
+	transformer := transformers.NewDeclarationTransformer(emitContext, options, emitResolver)
+	sourceFile = transformer.TransformSourceFile(sourceFile)
+
+	printer := printer.NewPrinter(printer.PrinterOptions{
+		RemoveComments:      options.RemoveComments.IsTrue(),
+		NewLine:             options.NewLine,
+		NoEmitHelpers:       true,
+		OnlyPrintJSDocStyle: true,
+	}, printer.PrintHandlers{}, emitContext)
+	return printer.EmitSourceFile(sourceFile)
 }
 
 func (e *emitter) printSourceFile(jsFilePath string, sourceMapFilePath string, sourceFile *ast.SourceFile, printer *printer.Printer) bool {
-	// !!! sourceMapGenerator
+	return e.printSourceFileWithMapOptions(jsFilePath, sourceMapFilePath, sourceFile, printer, e.host.Options())
+}
+
+func (e *emitter) printSourceFileWithMapOptions(jsFilePath string, sourceMapFilePath string, sourceFile *ast.SourceFile, printer *printer.Printer, mapOptions *core.CompilerOptions) bool {
 	options := e.host.Options()
 	var sourceMapGenerator *sourcemap.Generator
-	if shouldEmitSourceMaps(options, sourceFile) {
+	if shouldEmitSourceMaps(mapOptions, sourceFile) {
 		sourceMapGenerator = sourcemap.NewGenerator(
 			tspath.GetBaseFileName(tspath.NormalizeSlashes(jsFilePath)),
-			getSourceRoot(options),
-			e.getSourceMapDirectory(options, jsFilePath, sourceFile),
+			getSourceRoot(mapOptions),
+			e.getSourceMapDirectory(mapOptions, jsFilePath, sourceFile),
 			tspath.ComparePathsOptions{
 				UseCaseSensitiveFileNames: e.host.UseCaseSensitiveFileNames(),
 				CurrentDirectory:          e.host.GetCurrentDirectory(),
@@ -179,7 +266,7 @@ func (e *emitter) printSourceFile(jsFilePath string, sourceMapFilePath string, s
 		}
 
 		sourceMappingURL := e.getSourceMappingURL(
-			options,
+			mapOptions,
 			sourceMapGenerator,
 			jsFilePath,
 			sourceMapFilePath,
@@ -342,8 +429,19 @@ func (e *emitter) getSourceMappingURL(mapOptions *core.CompilerOptions, sourceMa
 }
 
 func getDeclarationEmitOutputFilePath(file string, host EmitHost) string {
-	// !!!
-	return ""
+	options := host.Options()
+	outputDir := options.DeclarationDir
+	if len(outputDir) == 0 {
+		outputDir = options.OutDir
+	}
+	var path string
+	if len(outputDir) > 0 {
+		path = getSourceFilePathInNewDir(file, outputDir, host.GetCurrentDirectory(), host.CommonSourceDirectory(), host.UseCaseSensitiveFileNames())
+	} else {
+		path = file
+	}
+	declarationExtension := tspath.GetDeclarationEmitExtensionForPath(path)
+	return tspath.RemoveFileExtension(path) + declarationExtension
 }
 
 type outputPaths struct {
@@ -351,7 +449,6 @@ type outputPaths struct {
 	sourceMapFilePath   string
 	declarationFilePath string
 	declarationMapPath  string
-	buildInfoPath       string
 }
 
 func getOutputPathsFor(sourceFile *ast.SourceFile, host EmitHost, forceDtsEmit bool) *outputPaths {
@@ -434,3 +531,108 @@ func getSourceFilesToEmit(host EmitHost, targetSourceFile *ast.SourceFile, force
 		return sourceFileMayBeEmitted(sourceFile, host, forceDtsEmit)
 	})
 }
+
+func getCommonSourceDirectoryOfConfig(config *tsoptions.ParsedCommandLine, useCaseSensitiveFileNames bool) string {
+	options := config.CompilerOptions()
+	return getCommonSourceDirectory(
+		options,
+		core.Filter(config.FileNames(), func(file string) bool {
+			return !tspath.IsDeclarationFileName(file)
+		}),
+		tspath.GetDirectoryPath(options.ConfigFilePath),
+		useCaseSensitiveFileNames,
+	)
+}
+
+func getOutputPathWithoutChangingExtension(inputFileName string, outputDirectory string, commonSourceDirectory func() string, useCaseSensitiveFileNames bool) string {
+	if outputDirectory == "" {
+		return inputFileName
+	}
+	return tspath.ResolvePath(outputDirectory, tspath.GetRelativePathFromDirectory(commonSourceDirectory(), inputFileName, tspath.ComparePathsOptions{
+		UseCaseSensitiveFileNames: useCaseSensitiveFileNames,
+	}))
+}
+
+func getOutputDeclarationFileNameWorker(inputFileName string, options *core.CompilerOptions, commonSourceDirectory func() string, useCaseSensitiveFileNames bool) string {
+	outputDirectory := options.DeclarationDir
+	if outputDirectory == "" {
+		outputDirectory = options.OutDir
+	}
+	path := getOutputPathWithoutChangingExtension(inputFileName, outputDirectory, commonSourceDirectory, useCaseSensitiveFileNames)
+	return tspath.ChangeExtension(path, tspath.GetDeclarationEmitExtensionForPath(inputFileName))
+}
+
+func getOutputJSFileNameWorker(inputFileName string, options *core.CompilerOptions, commonSourceDirectory func() string, useCaseSensitiveFileNames bool) string {
+	path := getOutputPathWithoutChangingExtension(inputFileName, options.OutDir, commonSourceDirectory, useCaseSensitiveFileNames)
+	return tspath.ChangeExtension(path, core.GetOutputExtension(inputFileName, options.Jsx))
+}
+
+// GetOutputDeclarationFileName returns the declaration file a source file of the project is built to.
+func GetOutputDeclarationFileName(inputFileName string, config *tsoptions.ParsedCommandLine, useCaseSensitiveFileNames bool) string {
+	commonSourceDirectory := sync.OnceValue(func() string {
+		return getCommonSourceDirectoryOfConfig(config, useCaseSensitiveFileNames)
+	})
+	return getOutputDeclarationFileNameWorker(inputFileName, config.CompilerOptions(), commonSourceDirectory, useCaseSensitiveFileNames)
+}
+
+// GetTsBuildInfoEmitOutputFilePath returns the path of the .tsbuildinfo file written by an incremental compilation,
+// or "" if the options do not produce one.
+func GetTsBuildInfoEmitOutputFilePath(options *core.CompilerOptions) string {
+	if !options.IsIncremental() {
+		return ""
+	}
+	if options.TsBuildInfoFile != "" {
+		return options.TsBuildInfoFile
+	}
+	// !!! outFile not yet implemented, may be deprecated
+	if options.ConfigFilePath == "" {
+		return ""
+	}
+	configFileExtensionLess := tspath.RemoveFileExtension(options.ConfigFilePath)
+	buildInfoExtensionLess := configFileExtensionLess
+	if options.OutDir != "" {
+		if options.RootDir != "" {
+			buildInfoExtensionLess = tspath.ResolvePath(options.OutDir, tspath.GetRelativePathFromDirectory(options.RootDir, configFileExtensionLess, tspath.ComparePathsOptions{}))
+		} else {
+			buildInfoExtensionLess = tspath.CombinePaths(options.OutDir, tspath.GetBaseFileName(configFileExtensionLess))
+		}
+	}
+	return buildInfoExtensionLess + tspath.ExtensionTsBuildInfo
+}
+
+// GetAllProjectOutputs returns the names of all files written by a build of the project.
+func GetAllProjectOutputs(config *tsoptions.ParsedCommandLine, useCaseSensitiveFileNames bool) []string {
+	options := config.CompilerOptions()
+	commonSourceDirectory := sync.OnceValue(func() string {
+		return getCommonSourceDirectoryOfConfig(config, useCaseSensitiveFileNames)
+	})
+	var outputs []string
+	for _, inputFileName := range config.FileNames() {
+		// Declaration files are not emitted
+		if tspath.IsDeclarationFileName(inputFileName) {
+			continue
+		}
+		// !!! Should JSON input files be emitted
+		if tspath.FileExtensionIs(inputFileName, tspath.ExtensionJson) {
+			continue
+		}
+		if options.EmitDeclarationOnly != core.TSTrue {
+			js := getOutputJSFileNameWorker(inputFileName, options, commonSourceDirectory, useCaseSensitiveFileNames)
+			outputs = append(outputs, js)
+			if sourceMap := getSourceMapFilePath(js, options); sourceMap != "" {
+				outputs = append(outputs, sourceMap)
+			}
+		}
+		if options.GetEmitDeclarations() && options.OutFile == "" {
+			dts := getOutputDeclarationFileNameWorker(inputFileName, options, commonSourceDirectory, useCaseSensitiveFileNames)
+			outputs = append(outputs, dts)
+			if options.GetAreDeclarationMapsEnabled() {
+				outputs = append(outputs, dts+".map")
+			}
+		}
+	}
+	if buildInfo := GetTsBuildInfoEmitOutputFilePath(options); buildInfo != "" {
+		outputs = append(outputs, buildInfo)
+	}
+	return outputs
+}
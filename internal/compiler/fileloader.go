@@ -26,6 +26,9 @@ type fileLoader struct {
 	wg                  core.WorkGroup
 	supportedExtensions []string
 
+	// Maps source files of referenced projects to the declaration files they are built to
+	projectReferenceRedirects map[tspath.Path]string
+
 	tasksByFileName collections.SyncMap[string, *parseTask]
 	rootTasks       []*parseTask
 
@@ -42,6 +45,10 @@ type processedFiles struct {
 	sourceFileMetaDatas           map[tspath.Path]*ast.SourceFileMetaData
 	jsxRuntimeImportSpecifiers    map[tspath.Path]*jsxRuntimeImportSpecifier
 	importHelpersImportSpecifiers map[tspath.Path]*ast.Node
+	// Maps each file to the files it references through imports, reference directives and module augmentations
+	referencedFiles map[tspath.Path][]tspath.Path
+	// The lib files that were loaded from the default library path
+	libFilePaths map[tspath.Path]struct{}
 }
 
 type jsxRuntimeImportSpecifier struct {
@@ -56,6 +63,7 @@ func processAllProgramFiles(
 	resolver *module.Resolver,
 	rootFiles []string,
 	libs []string,
+	projectReferenceRedirects map[tspath.Path]string,
 ) processedFiles {
 	supportedExtensions := tsoptions.GetSupportedExtensions(compilerOptions, nil /*extraFileExtensions*/)
 	loader := fileLoader{
@@ -68,9 +76,10 @@ func processAllProgramFiles(
 			UseCaseSensitiveFileNames: host.FS().UseCaseSensitiveFileNames(),
 			CurrentDirectory:          host.GetCurrentDirectory(),
 		},
-		wg:                  core.NewWorkGroup(programOptions.SingleThreaded),
-		rootTasks:           make([]*parseTask, 0, len(rootFiles)+len(libs)),
-		supportedExtensions: core.Flatten(tsoptions.GetSupportedExtensionsWithJsonIfResolveJsonModule(compilerOptions, supportedExtensions)),
+		wg:                        core.NewWorkGroup(programOptions.SingleThreaded),
+		rootTasks:                 make([]*parseTask, 0, len(rootFiles)+len(libs)),
+		supportedExtensions:       core.Flatten(tsoptions.GetSupportedExtensionsWithJsonIfResolveJsonModule(compilerOptions, supportedExtensions)),
+		projectReferenceRedirects: projectReferenceRedirects,
 	}
 
 	loader.addRootTasks(rootFiles, false)
@@ -91,11 +100,14 @@ func processAllProgramFiles(
 	sourceFileMetaDatas := make(map[tspath.Path]*ast.SourceFileMetaData, totalFileCount)
 	var jsxRuntimeImportSpecifiers map[tspath.Path]*jsxRuntimeImportSpecifier
 	var importHelpersImportSpecifiers map[tspath.Path]*ast.Node
+	referencedFiles := make(map[tspath.Path][]tspath.Path, totalFileCount)
+	libFilePaths := make(map[tspath.Path]struct{}, libFileCount)
 
 	for task := range loader.collectTasks(loader.rootTasks) {
 		file := task.file
 		if task.isLib {
 			libFiles = append(libFiles, file)
+			libFilePaths[file.Path()] = struct{}{}
 		} else {
 			files = append(files, file)
 		}
@@ -114,6 +126,11 @@ func processAllProgramFiles(
 			}
 			importHelpersImportSpecifiers[path] = task.importHelpersImportSpecifier
 		}
+		for _, subTask := range task.subTasks {
+			if subTask.file != nil && subTask.file != file && !slices.Contains(referencedFiles[path], subTask.file.Path()) {
+				referencedFiles[path] = append(referencedFiles[path], subTask.file.Path())
+			}
+		}
 	}
 	loader.sortLibs(libFiles)
 
@@ -125,6 +142,8 @@ func processAllProgramFiles(
 		sourceFileMetaDatas:           sourceFileMetaDatas,
 		jsxRuntimeImportSpecifiers:    jsxRuntimeImportSpecifiers,
 		importHelpersImportSpecifiers: importHelpersImportSpecifiers,
+		referencedFiles:               referencedFiles,
+		libFilePaths:                  libFilePaths,
 	}
 }
 
@@ -363,6 +382,7 @@ func (p *fileLoader) resolveImportsAndModuleAugmentations(file *ast.SourceFile)
 		resolutionsInFile = make(module.ModeAwareCache[*module.ResolvedModule], len(resolutions))
 
 		for i, resolution := range resolutions {
+			resolution = p.redirectToProjectReferenceOutput(resolution)
 			resolvedFileName := resolution.ResolvedFileName
 			// TODO(ercornel): !!!: check if from node modules
 
@@ -398,6 +418,24 @@ func (p *fileLoader) resolveImportsAndModuleAugmentations(file *ast.SourceFile)
 	return toParse, resolutionsInFile, importHelpersImportSpecifier, jsxRuntimeImportSpecifier_
 }
 
+// redirectToProjectReferenceOutput replaces a resolution to a source file of a referenced project
+// with a resolution to the declaration file that source is built to.
+func (p *fileLoader) redirectToProjectReferenceOutput(resolution *module.ResolvedModule) *module.ResolvedModule {
+	if len(p.projectReferenceRedirects) == 0 || !resolution.IsResolved() {
+		return resolution
+	}
+	path := tspath.ToPath(resolution.ResolvedFileName, p.host.GetCurrentDirectory(), p.host.FS().UseCaseSensitiveFileNames())
+	output, ok := p.projectReferenceRedirects[path]
+	if !ok {
+		return resolution
+	}
+	redirected := *resolution
+	redirected.ResolvedFileName = output
+	redirected.Extension = tspath.GetDeclarationEmitExtensionForPath(resolution.ResolvedFileName)
+	redirected.ResolvedUsingTsExtension = false
+	return &redirected
+}
+
 func (p *fileLoader) resolveModuleNames(entries []*ast.Node, file *ast.SourceFile) []*module.ResolvedModule {
 	if len(entries) == 0 {
 		return nil
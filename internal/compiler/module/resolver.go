@@ -99,14 +99,14 @@ func newResolutionState(
 	case core.ModuleResolutionKindNode16:
 		state.features = NodeResolutionFeaturesNode16Default
 		state.esmMode = resolutionMode == core.ModuleKindESNext
-		state.conditions = getConditions(compilerOptions, resolutionMode)
+		state.conditions = GetConditions(compilerOptions, resolutionMode)
 	case core.ModuleResolutionKindNodeNext:
 		state.features = NodeResolutionFeaturesNodeNextDefault
 		state.esmMode = resolutionMode == core.ModuleKindESNext
-		state.conditions = getConditions(compilerOptions, resolutionMode)
+		state.conditions = GetConditions(compilerOptions, resolutionMode)
 	case core.ModuleResolutionKindBundler:
-		state.features = getNodeResolutionFeatures(compilerOptions)
-		state.conditions = getConditions(compilerOptions, resolutionMode)
+		state.features = GetNodeResolutionFeatures(compilerOptions)
+		state.conditions = GetConditions(compilerOptions, resolutionMode)
 	}
 	return state
 }
@@ -145,6 +145,11 @@ func (r *Resolver) GetPackageScopeForPath(directory string) *packagejson.InfoCac
 	return (&resolutionState{compilerOptions: r.compilerOptions, resolver: r}).getPackageScopeForPath(directory)
 }
 
+// GetPackageJsonInfo returns the package.json in a directory, or nil if there is none.
+func (r *Resolver) GetPackageJsonInfo(packageDirectory string) *packagejson.InfoCacheEntry {
+	return (&resolutionState{compilerOptions: r.compilerOptions, resolver: r}).getPackageJsonInfo(packageDirectory, false /*onlyRecordFailures*/)
+}
+
 func (r *Resolver) GetPackageJsonTypeIfApplicable(path string) string {
 	if tspath.FileExtensionIsOneOf(path, []string{tspath.ExtensionMts, tspath.ExtensionCts, tspath.ExtensionMjs, tspath.ExtensionCjs}) {
 		return ""
@@ -1051,7 +1056,7 @@ func (r *resolutionState) tryLoadModuleUsingPathsIfEligible() *resolved {
 	} else {
 		return continueSearching()
 	}
-	baseDirectory := getPathsBasePath(r.compilerOptions, r.resolver.host.GetCurrentDirectory())
+	baseDirectory := GetPathsBasePath(r.compilerOptions, r.resolver.host.GetCurrentDirectory())
 	pathPatterns := r.resolver.getParsedPatternsForPaths()
 	return r.tryLoadModuleUsingPaths(
 		r.extensions,
@@ -1628,10 +1633,16 @@ func (r *resolutionState) getPackageJSONPathField(fieldName string, field *packa
 }
 
 func (r *resolutionState) conditionMatches(condition string) bool {
-	if condition == "default" || slices.Contains(r.conditions, condition) {
+	return ConditionMatches(r.conditions, condition)
+}
+
+// ConditionMatches reports whether a condition of a package.json exports or imports map applies
+// when resolving with the given conditions.
+func ConditionMatches(conditions []string, condition string) bool {
+	if condition == "default" || slices.Contains(conditions, condition) {
 		return true
 	}
-	if !slices.Contains(r.conditions, "types") {
+	if !slices.Contains(conditions, "types") {
 		return false // only apply versioned types conditions if the types condition is applied
 	}
 	if !strings.HasPrefix(condition, "types@") {
@@ -1650,7 +1661,9 @@ func (r *resolutionState) getTraceFunc() func(string) {
 	return nil
 }
 
-func getConditions(options *core.CompilerOptions, resolutionMode core.ResolutionMode) []string {
+// GetConditions returns the conditions that package.json exports and imports are matched against
+// by imports in the given resolution mode.
+func GetConditions(options *core.CompilerOptions, resolutionMode core.ResolutionMode) []string {
 	moduleResolution := options.GetModuleResolutionKind()
 	if resolutionMode == core.ModuleKindNone && moduleResolution == core.ModuleResolutionKindBundler {
 		resolutionMode = core.ModuleKindESNext
@@ -1672,7 +1685,9 @@ func getConditions(options *core.CompilerOptions, resolutionMode core.Resolution
 	return conditions
 }
 
-func getNodeResolutionFeatures(options *core.CompilerOptions) NodeResolutionFeatures {
+// GetNodeResolutionFeatures returns the package.json features that the module resolution of the
+// given options supports.
+func GetNodeResolutionFeatures(options *core.CompilerOptions) NodeResolutionFeatures {
 	features := NodeResolutionFeaturesNone
 
 	switch options.GetModuleResolutionKind() {
@@ -1708,7 +1723,9 @@ func moveToNextDirectorySeparatorIfAvailable(path string, prevSeparatorIndex int
 	return nextSeparatorIndex + offset
 }
 
-func getPathsBasePath(options *core.CompilerOptions, currentDirectory string) string {
+// GetPathsBasePath returns the directory that the patterns of the paths option are relative to,
+// or "" if there are none.
+func GetPathsBasePath(options *core.CompilerOptions, currentDirectory string) string {
 	if options.Paths.Size() == 0 {
 		return ""
 	}
@@ -1,9 +1,11 @@
 package compiler
 
 import (
+	"context"
 	"fmt"
 	"slices"
 	"sync"
+	"sync/atomic"
 
 	"github.com/microsoft/typescript-go/internal/ast"
 	"github.com/microsoft/typescript-go/internal/binder"
@@ -26,18 +28,25 @@ type ProgramOptions struct {
 	Options                      *core.CompilerOptions
 	SingleThreaded               bool
 	ProjectReference             []core.ProjectReference
+	ConfigFile                   *tsoptions.TsConfigSourceFile
 	ConfigFileParsingDiagnostics []*ast.Diagnostic
 }
 
+var nextProgramID atomic.Uint32
+
 type Program struct {
+	id                           uint32
 	host                         CompilerHost
 	programOptions               ProgramOptions
 	compilerOptions              *core.CompilerOptions
 	configFileName               string
 	nodeModules                  map[string]*ast.SourceFile
-	checkers                     []*checker.Checker
 	checkersOnce                 sync.Once
+	checkersMu                   sync.RWMutex // guards checkers and checkersByFile once they are created
+	checkers                     []*checker.Checker
 	checkersByFile               map[*ast.SourceFile]*checker.Checker
+	idleCheckersMu               sync.Mutex
+	idleCheckers                 []*checker.Checker
 	currentDirectory             string
 	configFileParsingDiagnostics []*ast.Diagnostic
 
@@ -69,10 +78,15 @@ type Program struct {
 
 	// List of present unsupported extensions
 	unsupportedExtensions []string
+
+	resolvedProjectReferences []*tsoptions.ParsedCommandLine
+	// Maps source files of referenced projects to the declaration files they are built to
+	projectReferenceRedirects map[tspath.Path]string
+	programDiagnostics        []*ast.Diagnostic
 }
 
 func NewProgram(options ProgramOptions) *Program {
-	p := &Program{}
+	p := &Program{id: nextProgramID.Add(1)}
 	p.programOptions = options
 	p.compilerOptions = options.Options
 	p.configFileParsingDiagnostics = slices.Clip(options.ConfigFileParsingDiagnostics)
@@ -132,6 +146,10 @@ func NewProgram(options ProgramOptions) *Program {
 			// !!! merge? override? this?
 			rootFiles = parseConfigFileContent.FileNames()
 		}
+		if p.programOptions.ProjectReference == nil {
+			p.programOptions.ProjectReference = parseConfigFileContent.ProjectReferences()
+			p.programOptions.ConfigFile = tsConfigSourceFile
+		}
 	}
 
 	p.resolver = module.NewResolver(p.host, p.compilerOptions)
@@ -153,7 +171,10 @@ func NewProgram(options ProgramOptions) *Program {
 		}
 	}
 
-	p.processedFiles = processAllProgramFiles(p.host, p.programOptions, p.compilerOptions, p.resolver, rootFiles, libs)
+	p.programOptions.RootFiles = rootFiles
+	p.loadProjectReferences()
+
+	p.processedFiles = processAllProgramFiles(p.host, p.programOptions, p.compilerOptions, p.resolver, rootFiles, libs, p.projectReferenceRedirects)
 	p.filesByPath = make(map[tspath.Path]*ast.SourceFile, len(p.files))
 	for _, file := range p.files {
 		p.filesByPath[file.Path()] = file
@@ -171,18 +192,20 @@ func NewProgram(options ProgramOptions) *Program {
 
 func NewProgramFromParsedCommandLine(config *tsoptions.ParsedCommandLine, host CompilerHost) *Program {
 	programOptions := ProgramOptions{
-		RootFiles: config.FileNames(),
-		Options:   config.CompilerOptions(),
-		Host:      host,
-		// todo: ProjectReferences
+		RootFiles:                    config.FileNames(),
+		Options:                      config.CompilerOptions(),
+		Host:                         host,
+		ProjectReference:             config.ProjectReferences(),
+		ConfigFile:                   config.ConfigFile,
 		ConfigFileParsingDiagnostics: config.GetConfigFileParsingDiagnostics(),
 	}
 	return NewProgram(programOptions)
 }
 
-func (p *Program) SourceFiles() []*ast.SourceFile { return p.files }
-func (p *Program) Options() *core.CompilerOptions { return p.compilerOptions }
-func (p *Program) Host() CompilerHost             { return p.host }
+func (p *Program) SourceFiles() []*ast.SourceFile   { return p.files }
+func (p *Program) Options() *core.CompilerOptions   { return p.compilerOptions }
+func (p *Program) Host() CompilerHost               { return p.host }
+func (p *Program) ModuleResolver() *module.Resolver { return p.resolver }
 func (p *Program) GetConfigFileParsingDiagnostics() []*ast.Diagnostic {
 	return slices.Clip(p.configFileParsingDiagnostics)
 }
@@ -206,13 +229,31 @@ func (p *Program) BindSourceFiles() {
 	wg.RunAndWait()
 }
 
-func (p *Program) CheckSourceFiles() {
-	p.createCheckers()
+func (p *Program) CheckSourceFiles(ctx context.Context) {
+	checkers := p.GetTypeCheckers()
 	wg := core.NewWorkGroup(p.programOptions.SingleThreaded)
-	for index, checker := range p.checkers {
+	for index, checker := range checkers {
 		wg.Queue(func() {
-			for i := index; i < len(p.files); i += len(p.checkers) {
-				checker.CheckSourceFile(p.files[i])
+			for i := index; i < len(p.files); i += len(checkers) {
+				checker.CheckSourceFile(ctx, p.files[i])
+			}
+		})
+	}
+	wg.RunAndWait()
+}
+
+// CheckSourceFilesSubset checks only the given files of the program, spreading them over the checkers that own them.
+func (p *Program) CheckSourceFilesSubset(ctx context.Context, files []*ast.SourceFile) {
+	filesByChecker := make(map[*checker.Checker][]*ast.SourceFile, len(p.GetTypeCheckers()))
+	for _, file := range files {
+		fileChecker := p.GetTypeCheckerForFile(file)
+		filesByChecker[fileChecker] = append(filesByChecker[fileChecker], file)
+	}
+	wg := core.NewWorkGroup(p.programOptions.SingleThreaded)
+	for fileChecker, checkerFiles := range filesByChecker {
+		wg.Queue(func() {
+			for _, file := range checkerFiles {
+				fileChecker.CheckSourceFile(ctx, file)
 			}
 		})
 	}
@@ -221,32 +262,107 @@ func (p *Program) CheckSourceFiles() {
 
 func (p *Program) createCheckers() {
 	p.checkersOnce.Do(func() {
-		p.checkers = make([]*checker.Checker, core.IfElse(p.programOptions.SingleThreaded, 1, 4))
+		checkers := make([]*checker.Checker, core.IfElse(p.programOptions.SingleThreaded, 1, 4))
 		wg := core.NewWorkGroup(p.programOptions.SingleThreaded)
-		for i := range p.checkers {
+		for i := range checkers {
 			wg.Queue(func() {
-				p.checkers[i] = checker.NewChecker(p)
+				checkers[i] = checker.NewChecker(p)
 			})
 		}
 		wg.RunAndWait()
-		p.checkersByFile = make(map[*ast.SourceFile]*checker.Checker)
+		checkersByFile := make(map[*ast.SourceFile]*checker.Checker)
 		for i, file := range p.files {
-			p.checkersByFile[file] = p.checkers[i%len(p.checkers)]
+			checkersByFile[file] = checkers[i%len(checkers)]
 		}
+		p.checkersMu.Lock()
+		defer p.checkersMu.Unlock()
+		p.checkers = checkers
+		p.checkersByFile = checkersByFile
 	})
 }
 
+// ID identifies the program among all the programs that the process creates.
+func (p *Program) ID() uint32 {
+	return p.id
+}
+
 // Return the type checker associated with the program.
 func (p *Program) GetTypeChecker() *checker.Checker {
-	p.createCheckers()
 	// Just use the first (and possibly only) checker for checker requests. Such requests are likely
 	// to obtain types through multiple API calls and we want to ensure that those types are created
 	// by the same checker so they can interoperate.
-	return p.checkers[0]
+	return p.GetTypeCheckers()[0]
+}
+
+// replaceChecker replaces a checker that was canceled while it was checking a file with a new one,
+// since the old one will not report the errors that it skipped. Callers that already hold the old
+// checker keep using it, as the slice of checkers is copied rather than changed in place.
+func (p *Program) replaceChecker(old *checker.Checker) {
+	p.checkersMu.Lock()
+	defer p.checkersMu.Unlock()
+	index := slices.Index(p.checkers, old)
+	if index < 0 {
+		// Another caller has already replaced it
+		return
+	}
+	checkers := slices.Clone(p.checkers)
+	checkers[index] = checker.NewChecker(p)
+	for file, fileChecker := range p.checkersByFile {
+		if fileChecker == old {
+			p.checkersByFile[file] = checkers[index]
+		}
+	}
+	p.checkers = checkers
+}
+
+// replaceCanceledCheckers replaces the checkers that were canceled while they were checking files.
+func (p *Program) replaceCanceledCheckers() {
+	for _, fileChecker := range p.GetTypeCheckers() {
+		if fileChecker.WasCanceled() {
+			p.replaceChecker(fileChecker)
+		}
+	}
+}
+
+// maxIdleCheckers is the number of released checkers that a program keeps to hand out again. Each
+// one holds on to the types it has resolved, so more are not kept than the program checks files with.
+const maxIdleCheckers = 4
+
+// AcquireChecker returns a checker that only the caller uses until it calls release, unlike the
+// checkers of the program, so that callers such as the language server can use the program from
+// several goroutines at once. Released checkers are handed out again, as they keep the types that
+// they have resolved, unless they were canceled while checking a file.
+func (p *Program) AcquireChecker() (c *checker.Checker, release func()) {
+	p.idleCheckersMu.Lock()
+	if n := len(p.idleCheckers); n > 0 {
+		c = p.idleCheckers[n-1]
+		p.idleCheckers = p.idleCheckers[:n-1]
+	}
+	p.idleCheckersMu.Unlock()
+	if c == nil {
+		c = checker.NewChecker(p)
+	}
+	return c, func() {
+		if c.WasCanceled() {
+			return
+		}
+		p.idleCheckersMu.Lock()
+		defer p.idleCheckersMu.Unlock()
+		if len(p.idleCheckers) < maxIdleCheckers {
+			p.idleCheckers = append(p.idleCheckers, c)
+		}
+	}
 }
 
 func (p *Program) GetTypeCheckers() []*checker.Checker {
 	p.createCheckers()
+	return p.loadCheckers()
+}
+
+// loadCheckers returns the checkers of the program without creating them. The returned slice is never changed.
+func (p *Program) loadCheckers() []*checker.Checker {
+	p.checkersMu.RLock()
+	defer p.checkersMu.RUnlock()
 	return p.checkers
 }
 
@@ -256,6 +372,8 @@ func (p *Program) GetTypeCheckers() []*checker.Checker {
 // representations of types) should be obtained from checkers returned by this method.
 func (p *Program) GetTypeCheckerForFile(file *ast.SourceFile) *checker.Checker {
 	p.createCheckers()
+	p.checkersMu.RLock()
+	defer p.checkersMu.RUnlock()
 	return p.checkersByFile[file]
 }
 
@@ -268,34 +386,86 @@ func (p *Program) GetResolvedModule(file *ast.SourceFile, moduleReference string
 	return nil
 }
 
+// ForEachResolvedModule calls f with the result of every module resolution made for the imports
+// of the files of the program.
+func (p *Program) ForEachResolvedModule(f func(resolved *module.ResolvedModule)) {
+	for _, resolutions := range p.resolvedModules {
+		for _, resolved := range resolutions {
+			f(resolved)
+		}
+	}
+}
+
 func (p *Program) findSourceFile(candidate string, reason FileIncludeReason) *ast.SourceFile {
 	path := tspath.ToPath(candidate, p.host.GetCurrentDirectory(), p.host.FS().UseCaseSensitiveFileNames())
 	return p.filesByPath[path]
 }
 
 func (p *Program) GetSyntacticDiagnostics(sourceFile *ast.SourceFile) []*ast.Diagnostic {
-	return p.getDiagnosticsHelper(sourceFile, false /*ensureBound*/, false /*ensureChecked*/, p.getSyntacticDiagnosticsForFile)
+	return p.getDiagnosticsHelper(context.Background(), sourceFile, false /*ensureBound*/, false /*ensureChecked*/, p.getSyntacticDiagnosticsForFile)
 }
 
 func (p *Program) GetBindDiagnostics(sourceFile *ast.SourceFile) []*ast.Diagnostic {
-	return p.getDiagnosticsHelper(sourceFile, true /*ensureBound*/, false /*ensureChecked*/, p.getBindDiagnosticsForFile)
+	return p.getDiagnosticsHelper(context.Background(), sourceFile, true /*ensureBound*/, false /*ensureChecked*/, p.getBindDiagnosticsForFile)
+}
+
+// GetSemanticDiagnostics checks a file, or all files if sourceFile is nil, and returns their errors.
+// If ctx is canceled, checking stops early and the diagnostics are incomplete.
+func (p *Program) GetSemanticDiagnostics(ctx context.Context, sourceFile *ast.SourceFile) []*ast.Diagnostic {
+	return p.getDiagnosticsHelper(ctx, sourceFile, true /*ensureBound*/, true /*ensureChecked*/, func(sourceFile *ast.SourceFile) []*ast.Diagnostic {
+		return p.getSemanticDiagnosticsForFile(ctx, sourceFile)
+	})
+}
+
+// GetSuggestionDiagnostics returns the suggestions for a file, such as unused declarations and uses of
+// deprecated declarations.
+func (p *Program) GetSuggestionDiagnostics(ctx context.Context, sourceFile *ast.SourceFile) []*ast.Diagnostic {
+	return p.getDiagnosticsHelper(ctx, sourceFile, true /*ensureBound*/, true /*ensureChecked*/, func(sourceFile *ast.SourceFile) []*ast.Diagnostic {
+		return p.getSuggestionDiagnosticsForFile(ctx, sourceFile)
+	})
 }
 
-func (p *Program) GetSemanticDiagnostics(sourceFile *ast.SourceFile) []*ast.Diagnostic {
-	return p.getDiagnosticsHelper(sourceFile, true /*ensureBound*/, true /*ensureChecked*/, p.getSemanticDiagnosticsForFile)
+// GetSemanticDiagnosticsWithChecker is GetSemanticDiagnostics for a file that c checks, rather than
+// the checkers of the program. If ctx is canceled, checking stops early and no diagnostics are
+// returned.
+func (p *Program) GetSemanticDiagnosticsWithChecker(ctx context.Context, c *checker.Checker, sourceFile *ast.SourceFile) []*ast.Diagnostic {
+	if checker.SkipTypeChecking(sourceFile, p.compilerOptions) {
+		return nil
+	}
+	diags := slices.Concat(sourceFile.BindDiagnostics(), c.GetDiagnostics(ctx, sourceFile))
+	if c.WasCanceled() {
+		return nil
+	}
+	return SortAndDeduplicateDiagnostics(applyCommentDirectives(sourceFile, diags))
+}
+
+// GetSuggestionDiagnosticsWithChecker is GetSuggestionDiagnostics for a file that c checks, rather
+// than the checker of the program for the file.
+func (p *Program) GetSuggestionDiagnosticsWithChecker(ctx context.Context, c *checker.Checker, sourceFile *ast.SourceFile) []*ast.Diagnostic {
+	if checker.SkipTypeChecking(sourceFile, p.compilerOptions) {
+		return nil
+	}
+	diags := c.GetSuggestionDiagnostics(ctx, sourceFile)
+	if c.WasCanceled() {
+		return nil
+	}
+	return SortAndDeduplicateDiagnostics(diags)
+}
+
+func (p *Program) GetDeclarationDiagnostics(ctx context.Context, sourceFile *ast.SourceFile) []*ast.Diagnostic {
+	return p.getDiagnosticsHelper(ctx, sourceFile, true /*ensureBound*/, true /*ensureChecked*/, p.getDeclarationDiagnosticsForFile)
 }
 
 func (p *Program) GetGlobalDiagnostics() []*ast.Diagnostic {
-	p.createCheckers()
 	var globalDiagnostics []*ast.Diagnostic
-	for _, checker := range p.checkers {
+	for _, checker := range p.GetTypeCheckers() {
 		globalDiagnostics = append(globalDiagnostics, checker.GetGlobalDiagnostics()...)
 	}
 	return SortAndDeduplicateDiagnostics(globalDiagnostics)
 }
 
 func (p *Program) GetOptionsDiagnostics() []*ast.Diagnostic {
-	return SortAndDeduplicateDiagnostics(append(p.GetGlobalDiagnostics(), p.getOptionsDiagnosticsOfConfigFile()...))
+	return SortAndDeduplicateDiagnostics(slices.Concat(p.GetGlobalDiagnostics(), p.getOptionsDiagnosticsOfConfigFile(), p.programDiagnostics))
 }
 
 func (p *Program) getOptionsDiagnosticsOfConfigFile() []*ast.Diagnostic {
@@ -319,7 +489,35 @@ func (p *Program) getBindDiagnosticsForFile(sourceFile *ast.SourceFile) []*ast.D
 	return sourceFile.BindDiagnostics()
 }
 
-func (p *Program) getSemanticDiagnosticsForFile(sourceFile *ast.SourceFile) []*ast.Diagnostic {
+func (p *Program) getDeclarationDiagnosticsForFile(sourceFile *ast.SourceFile) []*ast.Diagnostic {
+	if sourceFile.IsDeclarationFile {
+		return nil
+	}
+	host := &emitHost{program: p}
+	return getDeclarationDiagnostics(host, sourceFile)
+}
+
+// GetDeclarationText returns the declarations that would be emitted for a source file, without writing them.
+func (p *Program) GetDeclarationText(sourceFile *ast.SourceFile) string {
+	binder.BindSourceFile(sourceFile, p.getSourceAffectingCompilerOptions())
+	host := &emitHost{program: p}
+	return getDeclarationText(host, sourceFile)
+}
+
+func (p *Program) getSuggestionDiagnosticsForFile(ctx context.Context, sourceFile *ast.SourceFile) []*ast.Diagnostic {
+	if checker.SkipTypeChecking(sourceFile, p.compilerOptions) {
+		return nil
+	}
+	fileChecker := p.GetTypeCheckerForFile(sourceFile)
+	diags := fileChecker.GetSuggestionDiagnostics(ctx, sourceFile)
+	if fileChecker.WasCanceled() {
+		p.replaceChecker(fileChecker)
+		return nil
+	}
+	return diags
+}
+
+func (p *Program) getSemanticDiagnosticsForFile(ctx context.Context, sourceFile *ast.SourceFile) []*ast.Diagnostic {
 	if checker.SkipTypeChecking(sourceFile, p.compilerOptions) {
 		return nil
 	}
@@ -332,13 +530,23 @@ func (p *Program) getSemanticDiagnosticsForFile(sourceFile *ast.SourceFile) []*a
 	diags := slices.Clip(sourceFile.BindDiagnostics())
 	// Ask for diags from all checkers; checking one file may add diagnostics to other files.
 	// These are deduplicated later.
-	for _, checker := range p.checkers {
+	for _, checker := range p.GetTypeCheckers() {
 		if sourceFile == nil || checker == fileChecker {
-			diags = append(diags, checker.GetDiagnostics(sourceFile)...)
+			diags = append(diags, checker.GetDiagnostics(ctx, sourceFile)...)
 		} else {
 			diags = append(diags, checker.GetDiagnosticsWithoutCheck(sourceFile)...)
 		}
 	}
+	if fileChecker != nil && fileChecker.WasCanceled() {
+		p.replaceChecker(fileChecker)
+		return nil
+	}
+	return applyCommentDirectives(sourceFile, diags)
+}
+
+// applyCommentDirectives removes the diagnostics that @ts-ignore and @ts-expect-error comments
+// suppress, and adds errors for the @ts-expect-error comments that suppress nothing.
+func applyCommentDirectives(sourceFile *ast.SourceFile, diags []*ast.Diagnostic) []*ast.Diagnostic {
 	if len(sourceFile.CommentDirectives) == 0 {
 		return diags
 	}
@@ -395,7 +603,7 @@ func SortAndDeduplicateDiagnostics(diagnostics []*ast.Diagnostic) []*ast.Diagnos
 	return slices.CompactFunc(result, ast.EqualDiagnostics)
 }
 
-func (p *Program) getDiagnosticsHelper(sourceFile *ast.SourceFile, ensureBound bool, ensureChecked bool, getDiagnostics func(*ast.SourceFile) []*ast.Diagnostic) []*ast.Diagnostic {
+func (p *Program) getDiagnosticsHelper(ctx context.Context, sourceFile *ast.SourceFile, ensureBound bool, ensureChecked bool, getDiagnostics func(*ast.SourceFile) []*ast.Diagnostic) []*ast.Diagnostic {
 	if sourceFile != nil {
 		if ensureBound {
 			binder.BindSourceFile(sourceFile, p.getSourceAffectingCompilerOptions())
@@ -406,7 +614,12 @@ func (p *Program) getDiagnosticsHelper(sourceFile *ast.SourceFile, ensureBound b
 		p.BindSourceFiles()
 	}
 	if ensureChecked {
-		p.CheckSourceFiles()
+		p.CheckSourceFiles(ctx)
+		if ctx.Err() != nil {
+			// Getting the diagnostics of each file would only find its checker canceled.
+			p.replaceCanceledCheckers()
+			return nil
+		}
 	}
 	var result []*ast.Diagnostic
 	for _, file := range p.files {
@@ -436,7 +649,7 @@ func (p *Program) SymbolCount() int {
 	for _, file := range p.files {
 		count += file.SymbolCount
 	}
-	for _, checker := range p.checkers {
+	for _, checker := range p.loadCheckers() {
 		count += int(checker.SymbolCount)
 	}
 	return count
@@ -444,7 +657,7 @@ func (p *Program) SymbolCount() int {
 
 func (p *Program) TypeCount() int {
 	var count int
-	for _, checker := range p.checkers {
+	for _, checker := range p.loadCheckers() {
 		count += int(checker.TypeCount)
 	}
 	return count
@@ -452,7 +665,7 @@ func (p *Program) TypeCount() int {
 
 func (p *Program) InstantiationCount() int {
 	var count int
-	for _, checker := range p.checkers {
+	for _, checker := range p.loadCheckers() {
 		count += int(checker.TotalInstantiationCount)
 	}
 	return count
@@ -550,10 +763,16 @@ func computeCommonSourceDirectoryOfFilenames(fileNames []string, currentDirector
 
 func getCommonSourceDirectory(options *core.CompilerOptions, files []string, currentDirectory string, useCaseSensitiveFileNames bool) string {
 	var commonSourceDirectory string
-	// !!! If a rootDir is specified use it as the commonSourceDirectory
-	// !!! Project compilations never infer their root from the input source paths
-
-	commonSourceDirectory = computeCommonSourceDirectoryOfFilenames(files, currentDirectory, useCaseSensitiveFileNames)
+	if options.RootDir != "" {
+		// If a rootDir is specified use it as the commonSourceDirectory
+		// !!! checkSourceFilesBelongToPath
+		commonSourceDirectory = tspath.GetNormalizedAbsolutePath(options.RootDir, currentDirectory)
+	} else if options.Composite.IsTrue() && options.ConfigFilePath != "" {
+		// Project compilations never infer their root from the input source paths
+		commonSourceDirectory = tspath.GetDirectoryPath(tspath.NormalizeSlashes(options.ConfigFilePath))
+	} else {
+		commonSourceDirectory = computeCommonSourceDirectoryOfFilenames(files, currentDirectory, useCaseSensitiveFileNames)
+	}
 
 	if len(commonSourceDirectory) > 0 {
 		// Make sure directory path ends with directory separator so this string can directly
@@ -656,6 +875,26 @@ func (p *Program) GetSourceFiles() []*ast.SourceFile {
 	return p.files
 }
 
+// IsSourceFileDefaultLibrary reports whether a file is one of the lib files that ship with the compiler.
+func (p *Program) IsSourceFileDefaultLibrary(file *ast.SourceFile) bool {
+	_, ok := p.libFilePaths[file.Path()]
+	return ok
+}
+
+// GetReferencedFiles returns the paths of the files that a file of the program imports or references.
+func (p *Program) GetReferencedFiles(path tspath.Path) []tspath.Path {
+	return p.referencedFiles[path]
+}
+
+// GetRootFileNames returns the names of the files the program was created from.
+func (p *Program) GetRootFileNames() []string {
+	return p.programOptions.RootFiles
+}
+
+func (p *Program) SingleThreaded() bool {
+	return p.programOptions.SingleThreaded
+}
+
 type FileIncludeKind int
 
 const (
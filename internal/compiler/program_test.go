@@ -1,9 +1,11 @@
 package compiler
 
 import (
+	"context"
 	"path/filepath"
 	"slices"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/microsoft/typescript-go/internal/bundled"
@@ -247,6 +249,98 @@ func TestProgram(t *testing.T) {
 	}
 }
 
+func newCheckerTestProgram(t *testing.T) *Program {
+	t.Helper()
+	if !bundled.Embedded {
+		t.Skip("bundled files are not embedded")
+	}
+	fs := bundled.WrapFS(vfstest.FromMap[any](nil, false /*useCaseSensitiveFileNames*/))
+	_ = fs.WriteFile("/src/index.ts", "function f(): string { return 1; }", false)
+	opts := core.CompilerOptions{Target: core.ScriptTargetESNext}
+	program := NewProgram(ProgramOptions{
+		RootFiles: []string{"/src/index.ts"},
+		Host:      NewCompilerHost(&opts, "/src", fs, bundled.LibPath()),
+		Options:   &opts,
+	})
+	program.BindSourceFiles()
+	return program
+}
+
+func TestCanceledSemanticDiagnostics(t *testing.T) {
+	t.Parallel()
+	program := newCheckerTestProgram(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Assert(t, program.GetSemanticDiagnostics(ctx, nil) == nil)
+	// The checkers that were canceled are replaced, so the errors that they skipped are reported.
+	assert.Equal(t, len(program.GetSemanticDiagnostics(context.Background(), nil)), 1)
+}
+
+func TestAcquireChecker(t *testing.T) {
+	t.Parallel()
+	program := newCheckerTestProgram(t)
+	file := program.GetSourceFile("/src/index.ts")
+
+	first, releaseFirst := program.AcquireChecker()
+	second, releaseSecond := program.AcquireChecker()
+	assert.Assert(t, first != second)
+	releaseFirst()
+	reused, releaseReused := program.AcquireChecker()
+	assert.Assert(t, reused == first)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Assert(t, program.GetSemanticDiagnosticsWithChecker(ctx, second, file) == nil)
+	releaseSecond()
+	releaseReused()
+	// A checker that was canceled is not handed out again.
+	c, release := program.AcquireChecker()
+	defer release()
+	assert.Assert(t, c == first)
+	assert.Equal(t, len(program.GetSemanticDiagnosticsWithChecker(context.Background(), c, file)), 1)
+}
+
+func TestReplaceCheckersWhileInUse(t *testing.T) {
+	t.Parallel()
+	program := newCheckerTestProgram(t)
+	file := program.GetSourceFile("/src/index.ts")
+
+	// Looking up checkers while canceled ones are replaced must not race, which -race checks.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		assert.Assert(t, program.GetSemanticDiagnostics(ctx, nil) == nil)
+	}()
+	go func() {
+		defer wg.Done()
+		for range 100 {
+			assert.Assert(t, program.GetTypeCheckerForFile(file) != nil)
+			assert.Assert(t, program.GetTypeChecker() != nil)
+		}
+	}()
+	wg.Wait()
+	assert.Equal(t, len(program.GetSemanticDiagnostics(context.Background(), nil)), 1)
+}
+
+func TestAcquireCheckerKeepsFewIdleCheckers(t *testing.T) {
+	t.Parallel()
+	program := newCheckerTestProgram(t)
+
+	var releases []func()
+	for range maxIdleCheckers + 2 {
+		_, release := program.AcquireChecker()
+		releases = append(releases, release)
+	}
+	for _, release := range releases {
+		release()
+	}
+	assert.Equal(t, len(program.idleCheckers), maxIdleCheckers)
+}
+
 func BenchmarkNewProgram(b *testing.B) {
 	if !bundled.Embedded {
 		// Without embedding, we'd need to read all of the lib files out from disk into the MapFS.
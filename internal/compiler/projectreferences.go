@@ -0,0 +1,91 @@
+package compiler
+
+import (
+	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/tsoptions"
+	"github.com/microsoft/typescript-go/internal/tspath"
+)
+
+// loadProjectReferences parses the configs of all projects the program references, directly or transitively,
+// and maps the source files of those projects to the declaration files they are built to. Imports of such
+// source files are redirected to their outputs, so referenced projects are consumed the way they were built.
+func (p *Program) loadProjectReferences() {
+	if len(p.programOptions.ProjectReference) == 0 {
+		return
+	}
+
+	useCaseSensitiveFileNames := p.host.FS().UseCaseSensitiveFileNames()
+	extendedConfigCache := map[tspath.Path]*tsoptions.ExtendedConfigCacheEntry{}
+	configs := map[tspath.Path]*tsoptions.ParsedCommandLine{}
+	p.projectReferenceRedirects = map[tspath.Path]string{}
+	var ownConfigPath tspath.Path
+	if p.compilerOptions.ConfigFilePath != "" {
+		ownConfigPath = tspath.ToPath(p.compilerOptions.ConfigFilePath, p.host.GetCurrentDirectory(), useCaseSensitiveFileNames)
+	}
+
+	var load func(references []core.ProjectReference, parentConfigFile *tsoptions.TsConfigSourceFile, hasInputs bool) []*tsoptions.ParsedCommandLine
+	load = func(references []core.ProjectReference, parentConfigFile *tsoptions.TsConfigSourceFile, hasInputs bool) []*tsoptions.ParsedCommandLine {
+		result := make([]*tsoptions.ParsedCommandLine, len(references))
+		for i, ref := range references {
+			configFileName := core.ResolveProjectReferencePath(ref)
+			path := tspath.ToPath(configFileName, p.host.GetCurrentDirectory(), useCaseSensitiveFileNames)
+			if path == ownConfigPath {
+				// A circular reference back to this program; its sources are never redirected
+				continue
+			}
+			config, seen := configs[path]
+			if !seen {
+				if p.host.FS().FileExists(configFileName) {
+					config, _ = tsoptions.GetParsedCommandLineOfConfigFile(configFileName, &core.CompilerOptions{}, p.host, extendedConfigCache)
+				}
+				// Store the config before visiting its references so circular references terminate
+				configs[path] = config
+				if config != nil {
+					p.addProjectReferenceRedirects(config)
+					load(config.ProjectReferences(), config.ConfigFile, len(config.FileNames()) != 0)
+				}
+			}
+			result[i] = config
+			p.verifyProjectReference(ref, config, i, parentConfigFile, hasInputs)
+		}
+		return result
+	}
+
+	p.resolvedProjectReferences = load(p.programOptions.ProjectReference, p.programOptions.ConfigFile, len(p.programOptions.RootFiles) != 0)
+}
+
+func (p *Program) addProjectReferenceRedirects(config *tsoptions.ParsedCommandLine) {
+	useCaseSensitiveFileNames := p.host.FS().UseCaseSensitiveFileNames()
+	for _, fileName := range config.FileNames() {
+		if tspath.IsDeclarationFileName(fileName) || tspath.FileExtensionIs(fileName, tspath.ExtensionJson) {
+			continue
+		}
+		path := tspath.ToPath(fileName, p.host.GetCurrentDirectory(), useCaseSensitiveFileNames)
+		p.projectReferenceRedirects[path] = GetOutputDeclarationFileName(fileName, config, useCaseSensitiveFileNames)
+	}
+}
+
+func (p *Program) verifyProjectReference(ref core.ProjectReference, config *tsoptions.ParsedCommandLine, index int, parentConfigFile *tsoptions.TsConfigSourceFile, hasInputs bool) {
+	if config == nil {
+		p.programDiagnostics = append(p.programDiagnostics, tsoptions.CreateDiagnosticForReference(parentConfigFile, index, diagnostics.File_0_not_found, ref.Path))
+		return
+	}
+	// ok to not have composite if the current program is container only
+	if !hasInputs {
+		return
+	}
+	options := config.CompilerOptions()
+	if !options.Composite.IsTrue() {
+		p.programDiagnostics = append(p.programDiagnostics, tsoptions.CreateDiagnosticForReference(parentConfigFile, index, diagnostics.Referenced_project_0_must_have_setting_composite_Colon_true, ref.Path))
+	}
+	if options.NoEmit.IsTrue() {
+		p.programDiagnostics = append(p.programDiagnostics, tsoptions.CreateDiagnosticForReference(parentConfigFile, index, diagnostics.Referenced_project_0_may_not_disable_emit, ref.Path))
+	}
+}
+
+// GetResolvedProjectReferences returns the parsed configs of the projects directly referenced by the program.
+// Entries for references whose config could not be found are nil.
+func (p *Program) GetResolvedProjectReferences() []*tsoptions.ParsedCommandLine {
+	return p.resolvedProjectReferences
+}
@@ -59,7 +59,7 @@ type CompilerOptions struct {
 	ModuleKind                                ModuleKind                                `json:"module,omitzero"`
 	ModuleResolution                          ModuleResolutionKind                      `json:"moduleResolution,omitzero"`
 	ModuleSuffixes                            []string                                  `json:"moduleSuffixes,omitzero"`
-	ModuleDetection                           ModuleDetectionKind                       `json:"moduleDetectionKind,omitzero"`
+	ModuleDetection                           ModuleDetectionKind                       `json:"moduleDetection,omitzero"`
 	NewLine                                   NewLineKind                               `json:"newLine,omitzero"`
 	NoEmit                                    Tristate                                  `json:"noEmit,omitzero"`
 	NoCheck                                   Tristate                                  `json:"noCheck,omitzero"`
@@ -252,13 +252,15 @@ func (options *CompilerOptions) GetEmitStandardClassFields() bool {
 }
 
 func (options *CompilerOptions) GetEmitDeclarations() bool {
-	// !!!
-	return false
+	return options.Declaration.IsTrue() || options.Composite.IsTrue()
+}
+
+func (options *CompilerOptions) IsIncremental() bool {
+	return options.Incremental.IsTrue() || options.Composite.IsTrue()
 }
 
 func (options *CompilerOptions) GetAreDeclarationMapsEnabled() bool {
-	// !!!
-	return false
+	return options.DeclarationMap.IsTrue() && options.GetEmitDeclarations()
 }
 
 func (options *CompilerOptions) HasJsonModuleEmitEnabled() bool {
@@ -1,7 +1,22 @@
 package core
 
+import "github.com/microsoft/typescript-go/internal/tspath"
+
 type ProjectReference struct {
 	Path         string
 	OriginalPath string
 	Circular     bool
 }
+
+// ResolveProjectReferencePath returns the config file of the referenced project; a reference may point at the
+// project's directory instead of its config file.
+func ResolveProjectReferencePath(ref ProjectReference) string {
+	return ResolveConfigFileNameOfProjectReference(ref.Path)
+}
+
+func ResolveConfigFileNameOfProjectReference(path string) string {
+	if tspath.FileExtensionIs(path, tspath.ExtensionJson) {
+		return path
+	}
+	return tspath.CombinePaths(path, "tsconfig.json")
+}
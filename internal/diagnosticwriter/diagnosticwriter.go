@@ -211,6 +211,11 @@ func writeWithStyleAndReset(output io.Writer, text string, formatStyle string) {
 	fmt.Fprint(output, resetEscapeSequence)
 }
 
+// WriteGreyAndReset writes text in grey, as used for the timestamps of status messages.
+func WriteGreyAndReset(output io.Writer, text string) {
+	writeWithStyleAndReset(output, text, foregroundColorEscapeGrey)
+}
+
 func WriteLocation(output io.Writer, file *ast.SourceFile, pos int, formatOpts *FormattingOptions, writeWithStyleAndReset FormattedWriter) {
 	firstLine, firstChar := scanner.GetLineAndCharacterOfPosition(file, pos)
 	var relativeFileName string
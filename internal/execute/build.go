@@ -0,0 +1,683 @@
+package execute
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/compiler"
+	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/incremental"
+	"github.com/microsoft/typescript-go/internal/tsoptions"
+	"github.com/microsoft/typescript-go/internal/tspath"
+)
+
+func isBuildCommand(args []string) bool {
+	if len(args) == 0 || !strings.HasPrefix(args[0], "-") {
+		return false
+	}
+	name := strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(args[0], "-"), "-"))
+	return name == strings.ToLower(tsoptions.TscBuildOption.Name) || name == tsoptions.TscBuildOption.ShortName
+}
+
+func executeBuild(sys System, cb cbType, buildCommand *tsoptions.ParsedBuildCommandLine) ExitStatus {
+	reportDiagnostic := createDiagnosticReporter(sys, buildCommand.CompilerOptions.Pretty)
+	// if buildCommand.CompilerOptions.Locale != nil
+
+	if len(buildCommand.Errors) > 0 {
+		for _, e := range buildCommand.Errors {
+			reportDiagnostic(e)
+		}
+		return ExitStatusDiagnosticsPresent_OutputsSkipped
+	}
+
+	if buildCommand.CompilerOptions.Help.IsTrue() {
+		printVersion(sys)
+		printBuildHelp(sys, tsoptions.BuildOpts)
+		return ExitStatusSuccess
+	}
+
+	if buildCommand.CompilerOptions.Watch.IsTrue() {
+		// !!! build in watch mode
+		return ExitStatusNotImplementedWatch
+	}
+
+	builder := newSolutionBuilder(sys, cb, buildCommand, reportDiagnostic)
+	if buildCommand.BuildOptions.Clean.IsTrue() {
+		return builder.clean()
+	}
+	return builder.build()
+}
+
+type solutionBuilder struct {
+	sys                 System
+	cb                  cbType
+	options             *core.BuildOptions
+	compilerOptions     *core.CompilerOptions
+	rootNames           []string
+	reportDiagnostic    diagnosticReporter
+	reportStatus        diagnosticReporter
+	comparePathsOptions tspath.ComparePathsOptions
+
+	extendedConfigCache map[tspath.Path]*tsoptions.ExtendedConfigCacheEntry
+	projects            map[tspath.Path]*buildProject
+}
+
+func newSolutionBuilder(sys System, cb cbType, buildCommand *tsoptions.ParsedBuildCommandLine, reportDiagnostic diagnosticReporter) *solutionBuilder {
+	b := &solutionBuilder{
+		sys:              sys,
+		cb:               cb,
+		options:          buildCommand.BuildOptions,
+		compilerOptions:  buildCommand.CompilerOptions,
+		reportDiagnostic: reportDiagnostic,
+		reportStatus:     createBuilderStatusReporter(sys, shouldBePretty(sys, buildCommand.CompilerOptions)),
+		comparePathsOptions: tspath.ComparePathsOptions{
+			CurrentDirectory:          sys.GetCurrentDirectory(),
+			UseCaseSensitiveFileNames: sys.FS().UseCaseSensitiveFileNames(),
+		},
+		extendedConfigCache: map[tspath.Path]*tsoptions.ExtendedConfigCacheEntry{},
+		projects:            map[tspath.Path]*buildProject{},
+	}
+	for _, project := range buildCommand.Projects {
+		b.rootNames = append(b.rootNames, core.ResolveConfigFileNameOfProjectReference(tspath.GetNormalizedAbsolutePath(project, sys.GetCurrentDirectory())))
+	}
+	return b
+}
+
+type buildResult int
+
+const (
+	// The project was up to date, only contains references, or was not built because of --dry
+	buildResultNone buildResult = iota
+	buildResultSuccess
+	buildResultErrors
+	// The project was not built because a dependency has errors and --stopBuildOnErrors is set
+	buildResultBlocked
+)
+
+type buildProject struct {
+	configFileName string
+	path           tspath.Path
+	// nil if the config file could not be read
+	config       *tsoptions.ParsedCommandLine
+	configErrors []*ast.Diagnostic
+
+	// The projects this project waits for; references that form an allowed cycle are not included
+	upstream []*buildProject
+	// The references corresponding to upstream
+	upstreamReferences []core.ProjectReference
+
+	// The below are set by the task building the project and may be read once done is closed
+	done                  chan struct{}
+	output                *bufferedSystem
+	program               *compiler.Program
+	result                buildResult
+	diagnostics           []*ast.Diagnostic
+	newestDeclarationTime time.Time
+}
+
+func (b *solutionBuilder) getProject(configFileName string) *buildProject {
+	path := tspath.ToPath(configFileName, b.comparePathsOptions.CurrentDirectory, b.comparePathsOptions.UseCaseSensitiveFileNames)
+	if project, ok := b.projects[path]; ok {
+		return project
+	}
+	project := &buildProject{
+		configFileName: configFileName,
+		path:           path,
+		done:           make(chan struct{}),
+	}
+	project.config, project.configErrors = tsoptions.GetParsedCommandLineOfConfigFile(configFileName, b.compilerOptions, b.sys, b.extendedConfigCache)
+	b.projects[path] = project
+	return project
+}
+
+// getBuildOrder sorts the projects reachable from the root projects topologically, so every project comes after
+// the projects it references. Cycles are reported unless the reference closing them is marked as circular.
+func (b *solutionBuilder) getBuildOrder() ([]*buildProject, []*ast.Diagnostic) {
+	var buildOrder []*buildProject
+	var circularDiagnostics []*ast.Diagnostic
+	temporaryMarks := core.Set[tspath.Path]{}
+	permanentMarks := core.Set[tspath.Path]{}
+	var circularityReportStack []string
+
+	var visit func(configFileName string, inCircularContext bool)
+	visit = func(configFileName string, inCircularContext bool) {
+		project := b.getProject(configFileName)
+		if permanentMarks.Has(project.path) {
+			return
+		}
+		if temporaryMarks.Has(project.path) {
+			if !inCircularContext {
+				circularDiagnostics = append(circularDiagnostics, ast.NewCompilerDiagnostic(diagnostics.Project_references_may_not_form_a_circular_graph_Cycle_detected_Colon_0, strings.Join(circularityReportStack, b.sys.NewLine())))
+			}
+			return
+		}
+		temporaryMarks.Add(project.path)
+		circularityReportStack = append(circularityReportStack, configFileName)
+		if project.config != nil {
+			for _, ref := range project.config.ProjectReferences() {
+				visit(core.ResolveProjectReferencePath(ref), inCircularContext || ref.Circular)
+			}
+		}
+		circularityReportStack = circularityReportStack[:len(circularityReportStack)-1]
+		permanentMarks.Add(project.path)
+		buildOrder = append(buildOrder, project)
+	}
+
+	for _, rootName := range b.rootNames {
+		visit(rootName, false)
+	}
+	return buildOrder, circularDiagnostics
+}
+
+func (b *solutionBuilder) reportBuildQueue(buildOrder []*buildProject) {
+	if b.options.Verbose.IsTrue() {
+		var projects strings.Builder
+		for _, project := range buildOrder {
+			projects.WriteString(b.sys.NewLine() + "    * " + b.relativeFileName(project.configFileName))
+		}
+		b.reportStatus(ast.NewCompilerDiagnostic(diagnostics.Projects_in_this_build_Colon_0, projects.String()))
+	}
+}
+
+func (b *solutionBuilder) build() ExitStatus {
+	buildOrder, circularDiagnostics := b.getBuildOrder()
+	b.reportBuildQueue(buildOrder)
+	reportErrorSummary := createReportErrorSummary(b.sys, b.compilerOptions)
+	if len(circularDiagnostics) != 0 {
+		for _, diagnostic := range circularDiagnostics {
+			b.reportDiagnostic(diagnostic)
+		}
+		reportErrorSummary(circularDiagnostics)
+		return ExitStatusProjectReferenceCycle_OutputsSkipped
+	}
+
+	// Each project waits only for the projects it references, so independent projects are built in parallel.
+	// Their output is buffered and written in build order.
+	order := make(map[*buildProject]int, len(buildOrder))
+	for i, project := range buildOrder {
+		order[project] = i
+		if project.config == nil {
+			continue
+		}
+		for _, ref := range project.config.ProjectReferences() {
+			upstream := b.getProject(core.ResolveProjectReferencePath(ref))
+			// A reference to a project later in the build order closes an allowed cycle
+			if j, ok := order[upstream]; ok && j < i {
+				project.upstream = append(project.upstream, upstream)
+				project.upstreamReferences = append(project.upstreamReferences, ref)
+			}
+		}
+	}
+
+	if b.options.SingleThreaded.IsTrue() {
+		// Upstream projects come first in the build order, so they are always done
+		for _, project := range buildOrder {
+			b.buildProject(project)
+			close(project.done)
+		}
+	} else {
+		// A project takes a slot only once its upstream projects are done, so waiting projects never hold one
+		slots := make(chan struct{}, runtime.GOMAXPROCS(0))
+		wg := core.NewWorkGroup(false /*singleThreaded*/)
+		for _, project := range buildOrder {
+			wg.Queue(func() {
+				for _, upstream := range project.upstream {
+					<-upstream.done
+				}
+				slots <- struct{}{}
+				b.buildProject(project)
+				<-slots
+				close(project.done)
+			})
+		}
+		wg.RunAndWait()
+	}
+
+	var allDiagnostics []*ast.Diagnostic
+	successfulProjects := 0
+	for _, project := range buildOrder {
+		project.output.flush(b.sys)
+		if b.cb != nil && project.program != nil {
+			b.cb(project.program)
+		}
+		allDiagnostics = append(allDiagnostics, project.diagnostics...)
+		if project.result == buildResultSuccess {
+			successfulProjects++
+		}
+	}
+
+	reportErrorSummary(allDiagnostics)
+	if len(allDiagnostics) == 0 {
+		return ExitStatusSuccess
+	}
+	if successfulProjects != 0 {
+		return ExitStatusDiagnosticsPresent_OutputsGenerated
+	}
+	return ExitStatusDiagnosticsPresent_OutputsSkipped
+}
+
+func (b *solutionBuilder) buildProject(project *buildProject) {
+	sys := &bufferedSystem{System: b.sys}
+	project.output = sys
+	reportDiagnostic := createDiagnosticReporter(sys, b.compilerOptions.Pretty)
+	reportStatus := createBuilderStatusReporter(sys, shouldBePretty(sys, b.compilerOptions))
+
+	if project.config == nil {
+		for _, diagnostic := range project.configErrors {
+			reportDiagnostic(diagnostic)
+		}
+		project.diagnostics = project.configErrors
+		project.result = buildResultErrors
+		return
+	}
+
+	status := b.getUpToDateStatus(project)
+	if b.options.Verbose.IsTrue() {
+		b.reportUpToDateStatus(reportStatus, project, status)
+	}
+
+	switch status.kind {
+	case upToDateStatusTypeContainerOnly:
+		return
+	case upToDateStatusTypeUpToDate:
+		if b.options.Dry.IsTrue() {
+			reportStatus(ast.NewCompilerDiagnostic(diagnostics.Project_0_is_up_to_date, b.relativeFileName(project.configFileName)))
+		}
+		project.newestDeclarationTime = b.getNewestDeclarationTime(project)
+		return
+	case upToDateStatusTypeUpstreamBlocked:
+		for _, diagnostic := range project.config.GetConfigFileParsingDiagnostics() {
+			reportDiagnostic(diagnostic)
+		}
+		project.diagnostics = project.config.GetConfigFileParsingDiagnostics()
+		if b.options.Verbose.IsTrue() {
+			message := diagnostics.Skipping_build_of_project_0_because_its_dependency_1_has_errors
+			if status.upstreamProjectBlocked {
+				message = diagnostics.Skipping_build_of_project_0_because_its_dependency_1_was_not_built
+			}
+			reportStatus(ast.NewCompilerDiagnostic(message, b.relativeFileName(project.configFileName), b.relativeFileName(status.upstreamProjectName)))
+		}
+		project.result = buildResultBlocked
+		return
+	}
+
+	if b.options.Dry.IsTrue() {
+		reportStatus(ast.NewCompilerDiagnostic(diagnostics.A_non_dry_build_would_build_project_0, b.relativeFileName(project.configFileName)))
+		return
+	}
+
+	if b.options.Verbose.IsTrue() {
+		reportStatus(ast.NewCompilerDiagnostic(diagnostics.Building_project_0, b.relativeFileName(project.configFileName)))
+	}
+
+	host := compiler.NewCompilerHost(project.config.CompilerOptions(), sys.GetCurrentDirectory(), sys.FS(), sys.DefaultLibraryPath())
+	program := compiler.NewProgram(compiler.ProgramOptions{
+		RootFiles:                    project.config.FileNames(),
+		Options:                      project.config.CompilerOptions(),
+		Host:                         host,
+		ProjectReference:             project.config.ProjectReferences(),
+		ConfigFile:                   project.config.ConfigFile,
+		ConfigFileParsingDiagnostics: project.config.GetConfigFileParsingDiagnostics(),
+		SingleThreaded:               b.options.SingleThreaded.IsTrue(),
+	})
+	var programToEmit programLike = program
+	if project.config.CompilerOptions().IsIncremental() {
+		// A forced build starts over without the state of the previous build
+		var oldBuildInfo *incremental.BuildInfo
+		if !b.options.Force.IsTrue() {
+			oldBuildInfo = readBuildInfo(sys, project.config.CompilerOptions())
+		}
+		programToEmit = incremental.NewProgram(program, oldBuildInfo)
+	}
+	project.diagnostics, _ = emitFilesAndGetDiagnostics(programToEmit)
+	for _, diagnostic := range project.diagnostics {
+		reportDiagnostic(diagnostic)
+	}
+	project.program = program
+
+	if len(project.diagnostics) == 0 {
+		project.result = buildResultSuccess
+	} else {
+		project.result = buildResultErrors
+	}
+	project.newestDeclarationTime = b.getNewestDeclarationTime(project)
+}
+
+func (b *solutionBuilder) clean() ExitStatus {
+	buildOrder, circularDiagnostics := b.getBuildOrder()
+	if len(circularDiagnostics) != 0 {
+		for _, diagnostic := range circularDiagnostics {
+			b.reportDiagnostic(diagnostic)
+		}
+		return ExitStatusProjectReferenceCycle_OutputsSkipped
+	}
+
+	var filesToDelete []string
+	var removeDiagnostics []*ast.Diagnostic
+	for _, project := range buildOrder {
+		if project.config == nil {
+			// File has gone missing; fine to ignore here
+			for _, diagnostic := range project.configErrors {
+				b.reportDiagnostic(diagnostic)
+			}
+			continue
+		}
+		inputFileNames := core.Set[tspath.Path]{}
+		for _, fileName := range project.config.FileNames() {
+			inputFileNames.Add(b.toPath(fileName))
+		}
+		for _, output := range compiler.GetAllProjectOutputs(project.config, b.comparePathsOptions.UseCaseSensitiveFileNames) {
+			// If output name is same as input file name, do not delete and ignore the error
+			if inputFileNames.Has(b.toPath(output)) {
+				continue
+			}
+			if b.sys.FS().FileExists(output) {
+				if b.options.Dry.IsTrue() {
+					filesToDelete = append(filesToDelete, output)
+				} else if err := b.sys.FS().Remove(output); err != nil {
+					diagnostic := ast.NewCompilerDiagnostic(diagnostics.Could_not_write_file_0_Colon_1, output, err.Error())
+					b.reportDiagnostic(diagnostic)
+					removeDiagnostics = append(removeDiagnostics, diagnostic)
+				}
+			}
+		}
+	}
+
+	if b.options.Dry.IsTrue() {
+		var files strings.Builder
+		for _, file := range filesToDelete {
+			files.WriteString(b.sys.NewLine() + " * " + file)
+		}
+		b.reportStatus(ast.NewCompilerDiagnostic(diagnostics.A_non_dry_build_would_delete_the_following_files_Colon_0, files.String()))
+	}
+	if len(removeDiagnostics) != 0 {
+		createReportErrorSummary(b.sys, b.compilerOptions)(removeDiagnostics)
+		return ExitStatusDiagnosticsPresent_OutputsSkipped
+	}
+	return ExitStatusSuccess
+}
+
+type upToDateStatusType int
+
+const (
+	upToDateStatusTypeUpToDate upToDateStatusType = iota
+	upToDateStatusTypeContainerOnly
+	upToDateStatusTypeForceBuild
+	upToDateStatusTypeUpstreamBlocked
+	upToDateStatusTypeErrorReadingFile
+	upToDateStatusTypeOutputMissing
+	upToDateStatusTypeNoOutputs
+	upToDateStatusTypeOutOfDateWithSelf
+	upToDateStatusTypeOutOfDateWithUpstream
+	upToDateStatusTypeTsVersionOutputOfDate
+	upToDateStatusTypeOutOfDateBuildInfoWithErrors
+	upToDateStatusTypeOutOfDateBuildInfoWithPendingEmit
+	upToDateStatusTypeOutOfDateOptions
+	upToDateStatusTypeOutOfDateRoots
+)
+
+type upToDateStatus struct {
+	kind upToDateStatusType
+	// The files and projects explaining the status, if it has them
+	inputFileName          string
+	outputFileName         string
+	upstreamProjectName    string
+	upstreamProjectBlocked bool
+	// The compiler version that wrote the .tsbuildinfo file
+	version string
+}
+
+// getUpToDateStatus compares the modification times of the project's inputs with those of its outputs. The
+// upstream projects of the project must have been processed already.
+func (b *solutionBuilder) getUpToDateStatus(project *buildProject) *upToDateStatus {
+	config := project.config
+	if len(config.FileNames()) == 0 && len(config.ProjectReferences()) != 0 {
+		return &upToDateStatus{kind: upToDateStatusTypeContainerOnly}
+	}
+
+	if b.options.StopBuildOnErrors.IsTrue() {
+		for i, upstream := range project.upstream {
+			if upstream.result == buildResultErrors || upstream.result == buildResultBlocked {
+				return &upToDateStatus{
+					kind:                   upToDateStatusTypeUpstreamBlocked,
+					upstreamProjectName:    project.upstreamReferences[i].Path,
+					upstreamProjectBlocked: upstream.result == buildResultBlocked,
+				}
+			}
+		}
+	}
+
+	if b.options.Force.IsTrue() {
+		return &upToDateStatus{kind: upToDateStatusTypeForceBuild}
+	}
+
+	if config.CompilerOptions().IsIncremental() {
+		return b.getUpToDateStatusFromBuildInfo(project)
+	}
+
+	var newestInputFileTime time.Time
+	var newestInputFileName string
+	for _, inputFile := range config.FileNames() {
+		info := b.sys.FS().Stat(inputFile)
+		if info == nil {
+			return &upToDateStatus{kind: upToDateStatusTypeErrorReadingFile, inputFileName: inputFile}
+		}
+		if newestInputFileName == "" || info.ModTime().After(newestInputFileTime) {
+			newestInputFileTime = info.ModTime()
+			newestInputFileName = inputFile
+		}
+	}
+
+	outputs := compiler.GetAllProjectOutputs(config, b.comparePathsOptions.UseCaseSensitiveFileNames)
+	if len(outputs) == 0 {
+		// !!! nothing records that a project without outputs was checked, so it is always rebuilt
+		return &upToDateStatus{kind: upToDateStatusTypeNoOutputs}
+	}
+
+	var oldestOutputFileTime time.Time
+	var oldestOutputFileName string
+	for _, output := range outputs {
+		info := b.sys.FS().Stat(output)
+		if info == nil {
+			return &upToDateStatus{kind: upToDateStatusTypeOutputMissing, outputFileName: output}
+		}
+		if oldestOutputFileName == "" || info.ModTime().Before(oldestOutputFileTime) {
+			oldestOutputFileTime = info.ModTime()
+			oldestOutputFileName = output
+		}
+	}
+
+	if newestInputFileTime.After(oldestOutputFileTime) {
+		return &upToDateStatus{kind: upToDateStatusTypeOutOfDateWithSelf, inputFileName: newestInputFileName, outputFileName: oldestOutputFileName}
+	}
+
+	// The config file and the configs it extends are inputs too
+	for _, configFile := range append([]string{project.configFileName}, config.ExtendedSourceFiles()...) {
+		info := b.sys.FS().Stat(configFile)
+		if info != nil && info.ModTime().After(oldestOutputFileTime) {
+			return &upToDateStatus{kind: upToDateStatusTypeOutOfDateWithSelf, inputFileName: configFile, outputFileName: oldestOutputFileName}
+		}
+	}
+
+	// Only the declaration files of upstream projects are consumed by this project
+	for i, upstream := range project.upstream {
+		if upstream.newestDeclarationTime.After(oldestOutputFileTime) {
+			return &upToDateStatus{kind: upToDateStatusTypeOutOfDateWithUpstream, outputFileName: oldestOutputFileName, upstreamProjectName: project.upstreamReferences[i].Path}
+		}
+	}
+
+	return &upToDateStatus{kind: upToDateStatusTypeUpToDate, inputFileName: newestInputFileName, outputFileName: oldestOutputFileName}
+}
+
+// getUpToDateStatusFromBuildInfo checks an incremental project against its .tsbuildinfo file. An incremental build
+// only emits the files affected by changes, so the other outputs of the project are not compared with its inputs.
+func (b *solutionBuilder) getUpToDateStatusFromBuildInfo(project *buildProject) *upToDateStatus {
+	config := project.config
+	buildInfoFileName := compiler.GetTsBuildInfoEmitOutputFilePath(config.CompilerOptions())
+	buildInfoStat := b.sys.FS().Stat(buildInfoFileName)
+	if buildInfoStat == nil {
+		return &upToDateStatus{kind: upToDateStatusTypeOutputMissing, outputFileName: buildInfoFileName}
+	}
+	buildInfoTime := buildInfoStat.ModTime()
+
+	buildInfo := incremental.ReadBuildInfo(b.sys.FS(), buildInfoFileName)
+	if buildInfo == nil {
+		return &upToDateStatus{kind: upToDateStatusTypeErrorReadingFile, inputFileName: buildInfoFileName}
+	}
+	if !buildInfo.IsValidVersion() {
+		return &upToDateStatus{kind: upToDateStatusTypeTsVersionOutputOfDate, version: buildInfo.Version}
+	}
+	if buildInfo.Errors {
+		return &upToDateStatus{kind: upToDateStatusTypeOutOfDateBuildInfoWithErrors, outputFileName: buildInfoFileName}
+	}
+	if !config.CompilerOptions().NoEmit.IsTrue() && len(buildInfo.AffectedFilesPendingEmit) != 0 {
+		return &upToDateStatus{kind: upToDateStatusTypeOutOfDateBuildInfoWithPendingEmit, outputFileName: buildInfoFileName}
+	}
+	if buildInfo.HasChangedOptions(config.CompilerOptions(), buildInfoFileName) {
+		return &upToDateStatus{kind: upToDateStatusTypeOutOfDateOptions, outputFileName: buildInfoFileName}
+	}
+
+	versions := buildInfo.GetFileVersions(buildInfoFileName, b.comparePathsOptions)
+	inputPaths := make(map[tspath.Path]struct{}, len(config.FileNames()))
+	var newestInputFileTime time.Time
+	var newestInputFileName string
+	for _, inputFile := range config.FileNames() {
+		info := b.sys.FS().Stat(inputFile)
+		if info == nil {
+			return &upToDateStatus{kind: upToDateStatusTypeErrorReadingFile, inputFileName: inputFile}
+		}
+		path := b.toPath(inputFile)
+		inputPaths[path] = struct{}{}
+		version, ok := versions[path]
+		if !ok {
+			return &upToDateStatus{kind: upToDateStatusTypeOutOfDateWithSelf, inputFileName: inputFile, outputFileName: buildInfoFileName}
+		}
+		// A file that was only touched since the last build is still up to date
+		if info.ModTime().After(buildInfoTime) {
+			text, ok := b.sys.FS().ReadFile(inputFile)
+			if !ok {
+				return &upToDateStatus{kind: upToDateStatusTypeErrorReadingFile, inputFileName: inputFile}
+			}
+			if incremental.ComputeHash(text) != version {
+				return &upToDateStatus{kind: upToDateStatusTypeOutOfDateWithSelf, inputFileName: inputFile, outputFileName: buildInfoFileName}
+			}
+		}
+		if newestInputFileName == "" || info.ModTime().After(newestInputFileTime) {
+			newestInputFileTime = info.ModTime()
+			newestInputFileName = inputFile
+		}
+	}
+
+	for _, rootFile := range buildInfo.GetRootFileNames(buildInfoFileName) {
+		if _, ok := inputPaths[b.toPath(rootFile)]; !ok {
+			return &upToDateStatus{kind: upToDateStatusTypeOutOfDateRoots, inputFileName: rootFile, outputFileName: buildInfoFileName}
+		}
+	}
+
+	// The config file and the configs it extends are inputs too
+	for _, configFile := range append([]string{project.configFileName}, config.ExtendedSourceFiles()...) {
+		info := b.sys.FS().Stat(configFile)
+		if info != nil && info.ModTime().After(buildInfoTime) {
+			return &upToDateStatus{kind: upToDateStatusTypeOutOfDateWithSelf, inputFileName: configFile, outputFileName: buildInfoFileName}
+		}
+	}
+
+	// Only the declaration files of upstream projects are consumed by this project
+	for i, upstream := range project.upstream {
+		if upstream.newestDeclarationTime.After(buildInfoTime) {
+			return &upToDateStatus{kind: upToDateStatusTypeOutOfDateWithUpstream, outputFileName: buildInfoFileName, upstreamProjectName: project.upstreamReferences[i].Path}
+		}
+	}
+
+	return &upToDateStatus{kind: upToDateStatusTypeUpToDate, inputFileName: newestInputFileName, outputFileName: buildInfoFileName}
+}
+
+func (b *solutionBuilder) reportUpToDateStatus(reportStatus diagnosticReporter, project *buildProject, status *upToDateStatus) {
+	configFileName := b.relativeFileName(project.configFileName)
+	switch status.kind {
+	case upToDateStatusTypeUpToDate:
+		reportStatus(ast.NewCompilerDiagnostic(diagnostics.Project_0_is_up_to_date_because_newest_input_1_is_older_than_output_2, configFileName, b.relativeFileName(status.inputFileName), b.relativeFileName(status.outputFileName)))
+	case upToDateStatusTypeForceBuild:
+		reportStatus(ast.NewCompilerDiagnostic(diagnostics.Project_0_is_being_forcibly_rebuilt, configFileName))
+	case upToDateStatusTypeUpstreamBlocked:
+		message := diagnostics.Project_0_can_t_be_built_because_its_dependency_1_has_errors
+		if status.upstreamProjectBlocked {
+			message = diagnostics.Project_0_can_t_be_built_because_its_dependency_1_was_not_built
+		}
+		reportStatus(ast.NewCompilerDiagnostic(message, configFileName, b.relativeFileName(status.upstreamProjectName)))
+	case upToDateStatusTypeErrorReadingFile:
+		reportStatus(ast.NewCompilerDiagnostic(diagnostics.Project_0_is_out_of_date_because_there_was_error_reading_file_1, configFileName, b.relativeFileName(status.inputFileName)))
+	case upToDateStatusTypeOutputMissing:
+		reportStatus(ast.NewCompilerDiagnostic(diagnostics.Project_0_is_out_of_date_because_output_file_1_does_not_exist, configFileName, b.relativeFileName(status.outputFileName)))
+	case upToDateStatusTypeOutOfDateWithSelf:
+		reportStatus(ast.NewCompilerDiagnostic(diagnostics.Project_0_is_out_of_date_because_output_1_is_older_than_input_2, configFileName, b.relativeFileName(status.outputFileName), b.relativeFileName(status.inputFileName)))
+	case upToDateStatusTypeOutOfDateWithUpstream:
+		reportStatus(ast.NewCompilerDiagnostic(diagnostics.Project_0_is_out_of_date_because_output_1_is_older_than_input_2, configFileName, b.relativeFileName(status.outputFileName), b.relativeFileName(status.upstreamProjectName)))
+	case upToDateStatusTypeTsVersionOutputOfDate:
+		reportStatus(ast.NewCompilerDiagnostic(diagnostics.Project_0_is_out_of_date_because_output_for_it_was_generated_with_version_1_that_differs_with_current_version_2, configFileName, status.version, core.Version))
+	case upToDateStatusTypeOutOfDateBuildInfoWithErrors:
+		reportStatus(ast.NewCompilerDiagnostic(diagnostics.Project_0_is_out_of_date_because_buildinfo_file_1_indicates_that_program_needs_to_report_errors, configFileName, b.relativeFileName(status.outputFileName)))
+	case upToDateStatusTypeOutOfDateBuildInfoWithPendingEmit:
+		reportStatus(ast.NewCompilerDiagnostic(diagnostics.Project_0_is_out_of_date_because_buildinfo_file_1_indicates_that_some_of_the_changes_were_not_emitted, configFileName, b.relativeFileName(status.outputFileName)))
+	case upToDateStatusTypeOutOfDateOptions:
+		reportStatus(ast.NewCompilerDiagnostic(diagnostics.Project_0_is_out_of_date_because_buildinfo_file_1_indicates_there_is_change_in_compilerOptions, configFileName, b.relativeFileName(status.outputFileName)))
+	case upToDateStatusTypeOutOfDateRoots:
+		reportStatus(ast.NewCompilerDiagnostic(diagnostics.Project_0_is_out_of_date_because_buildinfo_file_1_indicates_that_file_2_was_root_file_of_compilation_but_not_any_more, configFileName, b.relativeFileName(status.outputFileName), b.relativeFileName(status.inputFileName)))
+	}
+}
+
+func (b *solutionBuilder) getNewestDeclarationTime(project *buildProject) time.Time {
+	var newest time.Time
+	for _, output := range compiler.GetAllProjectOutputs(project.config, b.comparePathsOptions.UseCaseSensitiveFileNames) {
+		if !tspath.IsDeclarationFileName(output) {
+			continue
+		}
+		if info := b.sys.FS().Stat(output); info != nil && info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+	}
+	return newest
+}
+
+func (b *solutionBuilder) toPath(fileName string) tspath.Path {
+	return tspath.ToPath(fileName, b.comparePathsOptions.CurrentDirectory, b.comparePathsOptions.UseCaseSensitiveFileNames)
+}
+
+func (b *solutionBuilder) relativeFileName(fileName string) string {
+	return tspath.ConvertToRelativePath(fileName, b.comparePathsOptions)
+}
+
+// bufferedSystem holds on to everything written while a project is built, so that projects built in parallel
+// do not interleave their output.
+type bufferedSystem struct {
+	System
+	writer strings.Builder
+	writes []string
+}
+
+var _ System = (*bufferedSystem)(nil)
+
+func (s *bufferedSystem) Writer() io.Writer {
+	return &s.writer
+}
+
+func (s *bufferedSystem) EndWrite() {
+	s.writes = append(s.writes, s.writer.String())
+	s.writer.Reset()
+}
+
+func (s *bufferedSystem) flush(sys System) {
+	if s.writer.Len() != 0 {
+		s.EndWrite()
+	}
+	for _, write := range s.writes {
+		fmt.Fprint(sys.Writer(), write)
+		sys.EndWrite()
+	}
+	s.writes = nil
+}
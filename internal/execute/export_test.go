@@ -1,8 +1,8 @@
 package execute
 
 import (
-	"github.com/microsoft/typescript-go/internal/compiler"
 	"github.com/microsoft/typescript-go/internal/tsoptions"
+	"github.com/microsoft/typescript-go/internal/vfs/vfswatch"
 )
 
 func CommandLineTest(sys System, cb cbType, commandLineArgs []string) (*tsoptions.ParsedCommandLine, ExitStatus) {
@@ -11,21 +11,27 @@ func CommandLineTest(sys System, cb cbType, commandLineArgs []string) (*tsoption
 	return parsedCommandLine, e
 }
 
+func CommandLineBuildTest(sys System, cb cbType, commandLineArgs []string) (*tsoptions.ParsedBuildCommandLine, ExitStatus) {
+	buildCommand := tsoptions.ParseBuildCommandLine(commandLineArgs[1:], sys)
+	return buildCommand, executeBuild(sys, cb, buildCommand)
+}
+
+func IsBuildCommand(commandLineArgs []string) bool {
+	return isBuildCommand(commandLineArgs)
+}
+
 func CommandLineTestWatch(sys System, cb cbType, commandLineArgs []string) (*tsoptions.ParsedCommandLine, *watcher) {
 	parsedCommandLine := tsoptions.ParseCommandLine(commandLineArgs, sys)
 	_, w := executeCommandLineWorker(sys, cb, parsedCommandLine)
 	return parsedCommandLine, w
 }
 
+// RunWatchCycle compiles the program the first time it is called for a watcher, and afterwards compiles it again
+// if any of the changes polled since the previous call affect it.
 func RunWatchCycle(w *watcher) {
-	// this function should perform the same stuff as w.doCycle() without printing time-related output
-	if w.hasErrorsInTsConfig() {
-		// these are unrecoverable errors--report them and do not build
+	if !w.initialized {
+		w.initialize()
 		return
 	}
-	// todo: updateProgram()
-	w.program = compiler.NewProgramFromParsedCommandLine(w.options, w.host)
-	if w.hasBeenModified(w.program) {
-		w.compileAndEmit()
-	}
+	w.onFileChanges(w.fileWatcher.(*vfswatch.PollingWatcher).Poll())
 }
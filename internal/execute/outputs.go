@@ -15,13 +15,17 @@ import (
 	"github.com/microsoft/typescript-go/internal/tspath"
 )
 
+func getComparePathsOptionsOfSys(sys System) tspath.ComparePathsOptions {
+	return tspath.ComparePathsOptions{
+		CurrentDirectory:          sys.GetCurrentDirectory(),
+		UseCaseSensitiveFileNames: sys.FS().UseCaseSensitiveFileNames(),
+	}
+}
+
 func getFormatOptsOfSys(sys System) *diagnosticwriter.FormattingOptions {
 	return &diagnosticwriter.FormattingOptions{
-		NewLine: "\n",
-		ComparePathsOptions: tspath.ComparePathsOptions{
-			CurrentDirectory:          sys.GetCurrentDirectory(),
-			UseCaseSensitiveFileNames: sys.FS().UseCaseSensitiveFileNames(),
-		},
+		NewLine:             "\n",
+		ComparePathsOptions: getComparePathsOptionsOfSys(sys),
 	}
 }
 
@@ -41,6 +45,29 @@ func createDiagnosticReporter(sys System, pretty core.Tristate) diagnosticReport
 	}
 }
 
+// createBuilderStatusReporter reports the progress messages of a --build as timestamped lines.
+func createBuilderStatusReporter(sys System, pretty bool) diagnosticReporter {
+	return func(diagnostic *ast.Diagnostic) {
+		timestamp := sys.Now().Format("3:04:05 PM")
+		if pretty {
+			fmt.Fprint(sys.Writer(), "[")
+			diagnosticwriter.WriteGreyAndReset(sys.Writer(), timestamp)
+			fmt.Fprint(sys.Writer(), "] ")
+		} else {
+			fmt.Fprint(sys.Writer(), timestamp, " - ")
+		}
+		diagnosticwriter.WriteFlattenedDiagnosticMessage(sys.Writer(), diagnostic, sys.NewLine())
+		fmt.Fprint(sys.Writer(), sys.NewLine(), sys.NewLine())
+		sys.EndWrite()
+	}
+}
+
+// createWatchStatusReporter reports the progress messages of watch mode as timestamped lines.
+func createWatchStatusReporter(sys System, options *core.CompilerOptions) diagnosticReporter {
+	// !!! clear the screen before each compilation unless preserveWatchOutput is set
+	return createBuilderStatusReporter(sys, shouldBePretty(sys, options))
+}
+
 func shouldBePretty(sys System, options *core.CompilerOptions) bool {
 	if options == nil || options.Pretty.IsTrueOrUnknown() {
 		// todo: return defaultIsPretty(sys);
@@ -89,6 +116,22 @@ func printVersion(sys System) {
 	sys.EndWrite()
 }
 
+func showConfig(sys System, config *collections.OrderedMap[string, any]) {
+	fmt.Fprint(sys.Writer(), core.Must(core.StringifyJson(config, "", "    ")), sys.NewLine())
+	sys.EndWrite()
+}
+
+func printConfigFileCreated(sys System, compilerOptionsDiffValue string) {
+	output := []string{sys.NewLine()}
+	output = append(output, getHeader(sys, "Created a new tsconfig.json with:")...)
+	output = append(output, compilerOptionsDiffValue+sys.NewLine()+sys.NewLine())
+	output = append(output, "You can learn more at https://aka.ms/tsconfig"+sys.NewLine())
+	for _, chunk := range output {
+		fmt.Fprint(sys.Writer(), chunk)
+	}
+	sys.EndWrite()
+}
+
 func printHelp(sys System, commandLine *tsoptions.ParsedCommandLine) {
 	if commandLine.CompilerOptions().All.IsFalseOrUnknown() {
 		printEasyHelp(sys, getOptionsForHelp(commandLine))
@@ -97,6 +140,22 @@ func printHelp(sys System, commandLine *tsoptions.ParsedCommandLine) {
 	}
 }
 
+func printBuildHelp(sys System, buildOptions []*tsoptions.CommandLineOption) {
+	var output []string
+	msg := diagnostics.X_tsc_Colon_The_TypeScript_Compiler.Format() + " - " + diagnostics.Version_0.Format(core.Version)
+	output = append(output, getHeader(sys, msg)...)
+	before := diagnostics.Using_build_b_will_make_tsc_behave_more_like_a_build_orchestrator_than_a_compiler_This_is_used_to_trigger_building_composite_projects_which_you_can_learn_more_about_at_0.Format("https://aka.ms/tsc-composite-builds")
+	options := core.Filter(buildOptions, func(option *tsoptions.CommandLineOption) bool {
+		return option != &tsoptions.TscBuildOption
+	})
+	output = append(output, generateSectionOptionsOutput(sys, diagnostics.BUILD_OPTIONS.Format(), options /*subCategory*/, false, &before, nil)...)
+
+	for _, chunk := range output {
+		fmt.Fprint(sys.Writer(), chunk)
+	}
+	sys.EndWrite()
+}
+
 func getOptionsForHelp(commandLine *tsoptions.ParsedCommandLine) []*tsoptions.CommandLineOption {
 	// Sort our options by their names, (e.g. "--noImplicitAny" comes before "--watch")
 	opts := slices.Clone(tsoptions.OptionsDeclarations)
@@ -4,7 +4,9 @@ import (
 	"io"
 	"time"
 
+	"github.com/microsoft/typescript-go/internal/core"
 	"github.com/microsoft/typescript-go/internal/vfs"
+	"github.com/microsoft/typescript-go/internal/vfs/vfswatch"
 )
 
 type System interface {
@@ -15,6 +17,8 @@ type System interface {
 	DefaultLibraryPath() string
 	GetCurrentDirectory() string
 	NewLine() string // #241 eventually we want to use "\n"
+	// NewFileWatcher creates a watcher for changes to the files of FS.
+	NewFileWatcher(options *core.WatchOptions) vfswatch.Watcher
 }
 
 type ExitStatus int
@@ -27,5 +31,4 @@ const (
 	ExitStatusProjectReferenceCycle_OutputsSkipped ExitStatus = 4
 	ExitStatusNotImplemented                       ExitStatus = 5
 	ExitStatusNotImplementedWatch                  ExitStatus = 6
-	ExitStatusNotImplementedIncremental            ExitStatus = 7
 )
@@ -11,8 +11,10 @@ import (
 	"time"
 
 	"github.com/microsoft/typescript-go/internal/bundled"
+	"github.com/microsoft/typescript-go/internal/core"
 	"github.com/microsoft/typescript-go/internal/vfs"
 	"github.com/microsoft/typescript-go/internal/vfs/vfstest"
+	"github.com/microsoft/typescript-go/internal/vfs/vfswatch"
 )
 
 type FileMap map[string]string
@@ -21,16 +23,22 @@ func newTestSys(fileOrFolderList FileMap, cwd string, args ...string) *testSys {
 	if cwd == "" {
 		cwd = "/home/src/workspaces/project"
 	}
-	return &testSys{
-		fs:                 bundled.WrapFS(vfstest.FromMap(fileOrFolderList, true /*useCaseSensitiveFileNames*/)),
+	sys := &testSys{
 		defaultLibraryPath: bundled.LibPath(),
 		cwd:                cwd,
 		files:              slices.Collect(maps.Keys(fileOrFolderList)),
 		output:             []string{},
 		currentWrite:       &strings.Builder{},
+		now:                testStartTime,
 	}
+	sys.fs = bundled.WrapFS(vfstest.FromMapWithClock(fileOrFolderList, true /*useCaseSensitiveFileNames*/, sys))
+	return sys
 }
 
+// testStartTime is the time every test starts at, so timestamps in the output and the order of
+// file modification times are the same on every run.
+var testStartTime = time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)
+
 type testSys struct {
 	// todo: original has write to output as a string[] because the separations are needed for baselining
 	output         []string
@@ -41,6 +49,7 @@ type testSys struct {
 	defaultLibraryPath string
 	cwd                string
 	files              []string
+	now                time.Time
 }
 
 func (s *testSys) IsTestDone() bool {
@@ -49,8 +58,13 @@ func (s *testSys) IsTestDone() bool {
 }
 
 func (s *testSys) Now() time.Time {
-	// todo: make a "test time" structure
-	return time.Now()
+	return s.now
+}
+
+// advanceTime moves the clock of the test forward, so files written afterwards are newer than
+// everything written so far.
+func (s *testSys) advanceTime(d time.Duration) {
+	s.now = s.now.Add(d)
 }
 
 func (s *testSys) FS() vfs.FS {
@@ -69,6 +83,11 @@ func (s *testSys) NewLine() string {
 	return "\n"
 }
 
+func (s *testSys) NewFileWatcher(options *core.WatchOptions) vfswatch.Watcher {
+	// Tests poll for changes explicitly, so that edits are seen at a known point
+	return vfswatch.NewPollingWatcher(s.fs, options)
+}
+
 func (s *testSys) Writer() io.Writer {
 	return s.currentWrite
 }
@@ -126,13 +145,10 @@ func (s *testSys) baselineFSwithDiff(baseline io.Writer) {
 	if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		panic("walkdir error during diff: " + err.Error())
 	}
-	for path, oldDirContents := range s.serializedDiff {
-		if s.FS().FileExists(path) {
-			_, ok := s.FS().ReadFile(path)
-			if !ok {
-				// report deleted
-				reportFSEntryDiff(baseline, oldDirContents, "", path)
-			}
+	for _, path := range slices.Sorted(maps.Keys(s.serializedDiff)) {
+		if _, ok := snap[path]; !ok {
+			// report deleted
+			reportFSEntryDiff(baseline, s.serializedDiff[path], "", path)
 		}
 	}
 	s.serializedDiff = snap
@@ -156,3 +172,18 @@ func (s *testSys) printOutputs(baseline io.Writer) {
 	// todo sanitize sys output
 	fmt.Fprint(baseline, strings.Join(s.output, "\n"))
 }
+
+// removeFailingFS is a file system that cannot remove files, as when they are in use by another process.
+type removeFailingFS struct {
+	vfs.FS
+}
+
+func (fs removeFailingFS) Remove(path string) error {
+	return errors.New("the file is in use")
+}
+
+// withFailingRemove makes every removal of a file in the test system fail.
+func withFailingRemove(sys *testSys) *testSys {
+	sys.fs = removeFailingFS{sys.fs}
+	return sys
+}
@@ -0,0 +1,160 @@
+package execute_test
+
+import (
+	"testing"
+
+	"github.com/microsoft/typescript-go/internal/bundled"
+	"github.com/microsoft/typescript-go/internal/execute"
+)
+
+func solutionSysFiles(sharedIndex string) FileMap {
+	return FileMap{
+		"/home/src/workspaces/solution/shared/index.ts": sharedIndex,
+		"/home/src/workspaces/solution/shared/tsconfig.json": `{
+	"compilerOptions": {
+		"composite": true,
+	},
+}`,
+		"/home/src/workspaces/solution/app/index.ts": `import { greet } from "../shared";
+export const message = greet("world");`,
+		"/home/src/workspaces/solution/app/tsconfig.json": `{
+	"compilerOptions": {
+		"composite": true,
+	},
+	"references": [
+		{ "path": "../shared" },
+	],
+}`,
+		"/home/src/workspaces/solution/tsconfig.json": `{
+	"files": [],
+	"references": [
+		{ "path": "shared" },
+		{ "path": "app" },
+	],
+}`,
+	}
+}
+
+const sharedIndexText = "export function greet(name: string) { return `Hello, ${name}`; }"
+
+func TestBuildSolution(t *testing.T) {
+	t.Parallel()
+	if !bundled.Embedded {
+		// Without embedding, we'd need to read all of the lib files out from disk into the MapFS.
+		// Just skip this for now.
+		t.Skip("bundled files are not embedded")
+	}
+
+	//nolint:errcheck
+	cases := []tscInput{{
+		subScenario:     "builds referenced projects in order",
+		sys:             newTestSys(solutionSysFiles(sharedIndexText), "/home/src/workspaces/solution"),
+		commandLineArgs: []string{"--b", "--verbose"},
+		edits: []*testTscEdit{
+			newTscEdit("no change", nil),
+			newTscEdit("change upstream project", func(sys execute.System) {
+				sys.FS().WriteFile("/home/src/workspaces/solution/shared/index.ts", sharedIndexText+"\nexport const x = 10;", false)
+			}),
+			{
+				caption:         "force",
+				commandLineArgs: []string{"--b", "--verbose", "--force"},
+			},
+			newTscEdit("change config of downstream project", func(sys execute.System) {
+				sys.FS().WriteFile("/home/src/workspaces/solution/app/tsconfig.json", `{
+	"compilerOptions": {
+		"composite": true,
+		"declarationMap": true,
+	},
+	"references": [
+		{ "path": "../shared" },
+	],
+}`, false)
+			}),
+		},
+	}, {
+		subScenario:     "dry",
+		sys:             newTestSys(solutionSysFiles(sharedIndexText), "/home/src/workspaces/solution"),
+		commandLineArgs: []string{"--b", "--dry"},
+		edits: []*testTscEdit{
+			{
+				caption:         "build",
+				commandLineArgs: []string{"--b"},
+			},
+			newTscEdit("no change", nil),
+		},
+	}, {
+		subScenario:     "clean",
+		sys:             newTestSys(solutionSysFiles(sharedIndexText), "/home/src/workspaces/solution"),
+		commandLineArgs: []string{"--b"},
+		edits: []*testTscEdit{
+			{
+				caption:         "clean dry",
+				commandLineArgs: []string{"--b", "--clean", "--dry"},
+			},
+			{
+				caption:         "clean",
+				commandLineArgs: []string{"--b", "--clean"},
+			},
+		},
+	}, {
+		subScenario:     "clean reports files that cannot be deleted",
+		sys:             withFailingRemove(newTestSys(solutionSysFiles(sharedIndexText), "/home/src/workspaces/solution")),
+		commandLineArgs: []string{"--b"},
+		edits: []*testTscEdit{
+			{
+				caption:         "clean",
+				commandLineArgs: []string{"--b", "--clean"},
+			},
+		},
+	}, {
+		subScenario:     "builds a single project",
+		sys:             newTestSys(solutionSysFiles(sharedIndexText), "/home/src/workspaces/solution"),
+		commandLineArgs: []string{"--b", "shared", "--verbose"},
+	}, {
+		subScenario:     "builds referenced projects in order singleThreaded",
+		sys:             newTestSys(solutionSysFiles(sharedIndexText), "/home/src/workspaces/solution"),
+		commandLineArgs: []string{"--b", "--verbose", "--singleThreaded"},
+	}, {
+		subScenario:     "upstream project has errors",
+		sys:             newTestSys(solutionSysFiles("export function greet(name: string): number { return `Hello, ${name}`; }"), "/home/src/workspaces/solution"),
+		commandLineArgs: []string{"--b", "--verbose"},
+	}, {
+		subScenario:     "upstream project has errors with stopBuildOnErrors",
+		sys:             newTestSys(solutionSysFiles("export function greet(name: string): number { return `Hello, ${name}`; }"), "/home/src/workspaces/solution"),
+		commandLineArgs: []string{"--b", "--verbose", "--stopBuildOnErrors"},
+	}, {
+		subScenario: "reference to missing project",
+		sys: newTestSys(FileMap{
+			"/home/src/workspaces/solution/tsconfig.json": `{
+	"files": [],
+	"references": [
+		{ "path": "missing" },
+	],
+}`,
+		}, "/home/src/workspaces/solution"),
+		commandLineArgs: []string{"--b"},
+	}, {
+		subScenario: "circular references",
+		sys: newTestSys(FileMap{
+			"/home/src/workspaces/solution/a/index.ts": "export const a = 1;",
+			"/home/src/workspaces/solution/a/tsconfig.json": `{
+	"compilerOptions": { "composite": true },
+	"references": [{ "path": "../b" }],
+}`,
+			"/home/src/workspaces/solution/b/index.ts": "export const b = 1;",
+			"/home/src/workspaces/solution/b/tsconfig.json": `{
+	"compilerOptions": { "composite": true },
+	"references": [{ "path": "../a" }],
+}`,
+		}, "/home/src/workspaces/solution"),
+		commandLineArgs: []string{"--b", "a", "--verbose"},
+	}, {
+		subScenario:     "options that cannot be combined",
+		sys:             newTestSys(solutionSysFiles(sharedIndexText), "/home/src/workspaces/solution"),
+		commandLineArgs: []string{"--b", "--clean", "--force"},
+	}}
+
+	for _, c := range cases {
+		c.verify(t, "solution")
+	}
+}
@@ -1,12 +1,14 @@
 package execute
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/microsoft/typescript-go/internal/ast"
 	"github.com/microsoft/typescript-go/internal/compiler"
 	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
 	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/incremental"
 	"github.com/microsoft/typescript-go/internal/tsoptions"
 	"github.com/microsoft/typescript-go/internal/tspath"
 )
@@ -14,6 +16,9 @@ import (
 type cbType = func(p any) any
 
 func CommandLine(sys System, cb cbType, commandLineArgs []string) ExitStatus {
+	if isBuildCommand(commandLineArgs) {
+		return executeBuild(sys, cb, tsoptions.ParseBuildCommandLine(commandLineArgs[1:], sys))
+	}
 	parsedCommandLine := tsoptions.ParseCommandLine(commandLineArgs, sys)
 	e, watcher := executeCommandLineWorker(sys, cb, parsedCommandLine)
 	if watcher == nil {
@@ -35,7 +40,8 @@ func executeCommandLineWorker(sys System, cb cbType, commandLine *tsoptions.Pars
 	}
 
 	if commandLine.CompilerOptions().Init.IsTrue() {
-		return ExitStatusNotImplemented, nil
+		writeConfigFile(sys, reportDiagnostic, commandLine.CompilerOptions(), commandLine.FileNames())
+		return ExitStatusSuccess, nil
 	}
 
 	if commandLine.CompilerOptions().Version.IsTrue() {
@@ -92,7 +98,7 @@ func executeCommandLineWorker(sys System, cb cbType, commandLine *tsoptions.Pars
 
 	if configFileName != "" {
 		extendedConfigCache := map[tspath.Path]*tsoptions.ExtendedConfigCacheEntry{}
-		configParseResult, errors := getParsedCommandLineOfConfigFile(configFileName, compilerOptionsFromCommandLine, sys, extendedConfigCache)
+		configParseResult, errors := tsoptions.GetParsedCommandLineOfConfigFile(configFileName, compilerOptionsFromCommandLine, sys, extendedConfigCache)
 		if len(errors) != 0 {
 			// these are unrecoverable errors--exit to report them as diagnostics
 			for _, e := range errors {
@@ -101,13 +107,19 @@ func executeCommandLineWorker(sys System, cb cbType, commandLine *tsoptions.Pars
 			return ExitStatusDiagnosticsPresent_OutputsGenerated, nil
 		}
 		if compilerOptionsFromCommandLine.ShowConfig.IsTrue() {
-			return ExitStatusNotImplemented, nil
+			showConfig(sys, tsoptions.ConvertToTSConfig(configParseResult, configFileName, getComparePathsOptionsOfSys(sys)))
+			return ExitStatusSuccess, nil
 		}
 		// updateReportDiagnostic
 		if isWatchSet(configParseResult.CompilerOptions()) {
-			return ExitStatusSuccess, createWatcher(sys, configParseResult, reportDiagnostic)
+			return ExitStatusSuccess, createWatcher(sys, configParseResult, commandLine, reportDiagnostic)
 		} else if isIncrementalCompilation(configParseResult.CompilerOptions()) {
-			return ExitStatusNotImplementedIncremental, nil
+			return performIncrementalCompilation(
+				sys,
+				cb,
+				configParseResult,
+				reportDiagnostic,
+			), nil
 		}
 		return performCompilation(
 			sys,
@@ -117,14 +129,21 @@ func executeCommandLineWorker(sys System, cb cbType, commandLine *tsoptions.Pars
 		), nil
 	} else {
 		if compilerOptionsFromCommandLine.ShowConfig.IsTrue() {
-			return ExitStatusNotImplemented, nil
+			configFileName := tspath.CombinePaths(sys.GetCurrentDirectory(), "tsconfig.json")
+			showConfig(sys, tsoptions.ConvertToTSConfig(commandLine, configFileName, getComparePathsOptionsOfSys(sys)))
+			return ExitStatusSuccess, nil
 		}
 		// todo update reportDiagnostic
 		if isWatchSet(compilerOptionsFromCommandLine) {
 			// !!! reportWatchModeWithoutSysSupport
-			return ExitStatusSuccess, createWatcher(sys, commandLine, reportDiagnostic)
+			return ExitStatusSuccess, createWatcher(sys, commandLine, commandLine, reportDiagnostic)
 		} else if isIncrementalCompilation(compilerOptionsFromCommandLine) {
-			return ExitStatusNotImplementedIncremental, nil
+			return performIncrementalCompilation(
+				sys,
+				cb,
+				commandLine,
+				reportDiagnostic,
+			), nil
 		}
 	}
 	return performCompilation(
@@ -149,45 +168,56 @@ func findConfigFile(searchPath string, fileExists func(string) bool, configName
 	return result
 }
 
-// Reads the config file and reports errors. Exits if the config file cannot be found
-func getParsedCommandLineOfConfigFile(configFileName string, options *core.CompilerOptions, sys System, extendedConfigCache map[tspath.Path]*tsoptions.ExtendedConfigCacheEntry) (*tsoptions.ParsedCommandLine, []*ast.Diagnostic) {
-	errors := []*ast.Diagnostic{}
-	configFileText, errors := tsoptions.TryReadFile(configFileName, sys.FS().ReadFile, errors)
-	if len(errors) > 0 {
-		// these are unrecoverable errors--exit to report them as diagnostics
-		return nil, errors
+// writeConfigFile writes a tsconfig.json with the options of the command line to the current directory, unless
+// there already is one.
+func writeConfigFile(sys System, reportDiagnostic diagnosticReporter, options *core.CompilerOptions, fileNames []string) {
+	configFileName := tspath.NormalizePath(tspath.CombinePaths(sys.GetCurrentDirectory(), "tsconfig.json"))
+	if sys.FS().FileExists(configFileName) {
+		reportDiagnostic(ast.NewCompilerDiagnostic(diagnostics.A_tsconfig_json_file_is_already_defined_at_Colon_0, configFileName))
+		return
 	}
-
-	cwd := sys.GetCurrentDirectory()
-	tsConfigSourceFile := tsoptions.NewTsconfigSourceFileFromFilePath(configFileName, tspath.ToPath(configFileName, cwd, sys.FS().UseCaseSensitiveFileNames()), configFileText)
-	// tsConfigSourceFile.resolvedPath = tsConfigSourceFile.FileName()
-	// tsConfigSourceFile.originalFileName = tsConfigSourceFile.FileName()
-	return tsoptions.ParseJsonSourceFileConfigFileContent(
-		tsConfigSourceFile,
-		sys,
-		tspath.GetNormalizedAbsolutePath(tspath.GetDirectoryPath(configFileName), cwd),
-		options,
-		tspath.GetNormalizedAbsolutePath(configFileName, cwd),
-		nil,
-		nil,
-		extendedConfigCache,
-	), nil
+	comparePathsOptions := getComparePathsOptionsOfSys(sys)
+	text := tsoptions.GenerateTSConfig(options, fileNames, configFileName, comparePathsOptions, sys.NewLine())
+	if err := sys.FS().WriteFile(configFileName, text, false /*writeByteOrderMark*/); err != nil {
+		reportDiagnostic(ast.NewCompilerDiagnostic(diagnostics.Could_not_write_file_0_Colon_1, configFileName, err.Error()))
+		return
+	}
+	printConfigFileCreated(sys, tsoptions.GetCompilerOptionsDiffValue(options, configFileName, comparePathsOptions, sys.NewLine()))
 }
 
 func performCompilation(sys System, cb cbType, config *tsoptions.ParsedCommandLine, reportDiagnostic diagnosticReporter) ExitStatus {
 	host := compiler.NewCompilerHost(config.CompilerOptions(), sys.GetCurrentDirectory(), sys.FS(), sys.DefaultLibraryPath())
 	// todo: cache, statistics, tracing
 	program := compiler.NewProgramFromParsedCommandLine(config, host)
+	return emitAndReportStatistics(sys, cb, program, program, reportDiagnostic)
+}
+
+func performIncrementalCompilation(sys System, cb cbType, config *tsoptions.ParsedCommandLine, reportDiagnostic diagnosticReporter) ExitStatus {
+	host := compiler.NewCompilerHost(config.CompilerOptions(), sys.GetCurrentDirectory(), sys.FS(), sys.DefaultLibraryPath())
+	program := compiler.NewProgramFromParsedCommandLine(config, host)
+	incrementalProgram := incremental.NewProgram(program, readBuildInfo(sys, config.CompilerOptions()))
+	return emitAndReportStatistics(sys, cb, incrementalProgram, program, reportDiagnostic)
+}
+
+// readBuildInfo reads the .tsbuildinfo file written by the previous incremental compilation, if there is one.
+func readBuildInfo(sys System, options *core.CompilerOptions) *incremental.BuildInfo {
+	buildInfoFileName := compiler.GetTsBuildInfoEmitOutputFilePath(options)
+	if buildInfoFileName == "" {
+		return nil
+	}
+	return incremental.ReadBuildInfo(sys.FS(), buildInfoFileName)
+}
 
+func emitAndReportStatistics(sys System, cb cbType, program programLike, underlyingProgram *compiler.Program, reportDiagnostic diagnosticReporter) ExitStatus {
 	diagnostics, emitResult, exitStatus := compileAndEmit(sys, program, reportDiagnostic)
 	if exitStatus != ExitStatusSuccess {
 		// compile exited early
 		return exitStatus
 	}
 
-	reportStatistics(sys, program)
+	reportStatistics(sys, underlyingProgram)
 	if cb != nil {
-		cb(program)
+		cb(underlyingProgram)
 	}
 
 	if emitResult.EmitSkipped && diagnostics != nil && len(diagnostics) > 0 {
@@ -198,9 +228,52 @@ func performCompilation(sys System, cb cbType, config *tsoptions.ParsedCommandLi
 	return ExitStatusSuccess
 }
 
-func compileAndEmit(sys System, program *compiler.Program, reportDiagnostic diagnosticReporter) ([]*ast.Diagnostic, *compiler.EmitResult, ExitStatus) {
+func compileAndEmit(sys System, program programLike, reportDiagnostic diagnosticReporter) ([]*ast.Diagnostic, *compiler.EmitResult, ExitStatus) {
 	// todo: check if third return needed after execute is fully implemented
 
+	allDiagnostics, emitResult := emitFilesAndReportErrors(sys, program, reportDiagnostic)
+	createReportErrorSummary(sys, program.Options())(allDiagnostics)
+	return allDiagnostics, emitResult, ExitStatusSuccess
+}
+
+// emitFilesAndReportErrors emits the program, and reports its diagnostics and the files emitted.
+func emitFilesAndReportErrors(sys System, program programLike, reportDiagnostic diagnosticReporter) ([]*ast.Diagnostic, *compiler.EmitResult) {
+	allDiagnostics, emitResult := emitFilesAndGetDiagnostics(program)
+	for _, diagnostic := range allDiagnostics {
+		reportDiagnostic(diagnostic)
+	}
+
+	// !!! if (write)
+	if sys.Writer() != nil {
+		for _, file := range emitResult.EmittedFiles {
+			fmt.Fprint(sys.Writer(), "TSFILE: ", tspath.GetNormalizedAbsolutePath(file, sys.GetCurrentDirectory()))
+		}
+		// todo: listFiles(program, sys.Writer())
+	}
+	return allDiagnostics, emitResult
+}
+
+// programLike is the part of a program needed to check and emit it, implemented both by compiler.Program and by
+// incremental.Program.
+type programLike interface {
+	Options() *core.CompilerOptions
+	GetConfigFileParsingDiagnostics() []*ast.Diagnostic
+	GetSyntacticDiagnostics(sourceFile *ast.SourceFile) []*ast.Diagnostic
+	GetOptionsDiagnostics() []*ast.Diagnostic
+	GetGlobalDiagnostics() []*ast.Diagnostic
+	GetSemanticDiagnostics(ctx context.Context, sourceFile *ast.SourceFile) []*ast.Diagnostic
+	GetDeclarationDiagnostics(ctx context.Context, sourceFile *ast.SourceFile) []*ast.Diagnostic
+	Emit(options compiler.EmitOptions) *compiler.EmitResult
+}
+
+var (
+	_ programLike = (*compiler.Program)(nil)
+	_ programLike = (*incremental.Program)(nil)
+)
+
+// emitFilesAndGetDiagnostics emits the program and returns the sorted diagnostics of the config file, the
+// program and the emit.
+func emitFilesAndGetDiagnostics(program programLike) ([]*ast.Diagnostic, *compiler.EmitResult) {
 	options := program.Options()
 	allDiagnostics := program.GetConfigFileParsingDiagnostics()
 
@@ -214,12 +287,10 @@ func compileAndEmit(sys System, program *compiler.Program, reportDiagnostic diag
 		}
 	}
 	if len(diagnostics) == 0 {
-		diagnostics = append(diagnostics, program.GetSemanticDiagnostics(nil)...)
+		diagnostics = append(diagnostics, program.GetSemanticDiagnostics(context.Background(), nil)...)
 	}
-	// TODO: declaration diagnostics
-	if len(diagnostics) == 0 && options.NoEmit == core.TSTrue && (options.Declaration.IsTrue() && options.Composite.IsTrue()) {
-		return nil, nil, ExitStatusNotImplemented
-		// addRange(allDiagnostics, program.getDeclarationDiagnostics(/*sourceFile*/ undefined, cancellationToken));
+	if len(diagnostics) == 0 && options.NoEmit == core.TSTrue && options.GetEmitDeclarations() {
+		diagnostics = append(diagnostics, program.GetDeclarationDiagnostics(context.Background(), nil)...)
 	}
 
 	emitResult := &compiler.EmitResult{EmitSkipped: true, Diagnostics: []*ast.Diagnostic{}}
@@ -230,31 +301,13 @@ func compileAndEmit(sys System, program *compiler.Program, reportDiagnostic diag
 	diagnostics = append(diagnostics, emitResult.Diagnostics...)
 
 	allDiagnostics = append(allDiagnostics, diagnostics...)
-	allDiagnostics = compiler.SortAndDeduplicateDiagnostics(allDiagnostics)
-	for _, diagnostic := range allDiagnostics {
-		reportDiagnostic(diagnostic)
-	}
-
-	// !!! if (write)
-	if sys.Writer() != nil {
-		for _, file := range emitResult.EmittedFiles {
-			fmt.Fprint(sys.Writer(), "TSFILE: ", tspath.GetNormalizedAbsolutePath(file, sys.GetCurrentDirectory()))
-		}
-		// todo: listFiles(program, sys.Writer())
-	}
-
-	createReportErrorSummary(sys, program.Options())(allDiagnostics)
-	return allDiagnostics, emitResult, ExitStatusSuccess
+	return compiler.SortAndDeduplicateDiagnostics(allDiagnostics), emitResult
 }
 
-// func isBuildCommand(args []string) bool {
-// 	return len(args) > 0 && args[0] == "build"
-// }
-
 func isWatchSet(options *core.CompilerOptions) bool {
 	return options.Watch.IsTrue()
 }
 
 func isIncrementalCompilation(options *core.CompilerOptions) bool {
-	return options.Incremental.IsTrue()
+	return options.IsIncremental()
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/microsoft/typescript-go/internal/bundled"
+	"github.com/microsoft/typescript-go/internal/execute"
 )
 
 func TestTsc(t *testing.T) {
@@ -50,6 +51,11 @@ func TestTsc(t *testing.T) {
 			sys:             newTestSys(nil, ""),
 			commandLineArgs: []string{"--verbose", "--build"},
 		},
+		{
+			subScenario:     "singleThreaded without build",
+			sys:             newTestSys(nil, ""),
+			commandLineArgs: []string{"--singleThreaded"},
+		},
 		{
 			subScenario:     "help",
 			sys:             newTestSys(nil, ""),
@@ -122,6 +128,88 @@ func TestTsc(t *testing.T) {
 	}
 }
 
+func TestShowConfig(t *testing.T) {
+	t.Parallel()
+	if !bundled.Embedded {
+		// Without embedding, we'd need to read all of the lib files out from disk into the MapFS.
+		// Just skip this for now.
+		t.Skip("bundled files are not embedded")
+	}
+
+	cases := []tscInput{{
+		subScenario: "with tsconfig",
+		sys: newTestSys(FileMap{
+			"/home/src/workspaces/project/tsconfig.json": `{
+	"compilerOptions": {
+		"composite": true,
+		"strict": true,
+		"module": "nodenext",
+		"outDir": "dist",
+		"rootDirs": ["src", "generated"],
+		"lib": ["es2020", "dom"],
+	},
+	"include": ["src"],
+	"references": [{ "path": "../shared" }],
+	"compileOnSave": true,
+}`,
+			"/home/src/workspaces/project/src/index.ts":   `export const a = 1;`,
+			"/home/src/workspaces/project/src/util.ts":    `export const b = 2;`,
+			"/home/src/workspaces/shared/tsconfig.json":   `{ "compilerOptions": { "composite": true } }`,
+			"/home/src/workspaces/shared/index.ts":        `export const c = 3;`,
+			"/home/src/workspaces/project/other/other.ts": `export const d = 4;`,
+		}, ""),
+		commandLineArgs: []string{"--showConfig"},
+	}, {
+		subScenario: "with command line options overriding tsconfig",
+		sys: newTestSys(FileMap{
+			"/home/src/workspaces/project/tsconfig.json": `{ "compilerOptions": { "target": "es2015", "declaration": true } }`,
+			"/home/src/workspaces/project/first.ts":      `export const a = 1;`,
+		}, ""),
+		commandLineArgs: []string{"--showConfig", "--target", "esnext", "--declarationDir", "types", "--listFiles"},
+	}, {
+		subScenario: "without tsconfig",
+		sys: newTestSys(FileMap{
+			"/home/src/workspaces/project/first.ts": `export const a = 1;`,
+		}, ""),
+		commandLineArgs: []string{"--showConfig", "--module", "esnext", "--watchFile", "fixedPollingInterval", "first.ts"},
+	}}
+
+	for _, c := range cases {
+		c.verify(t, "showConfig")
+	}
+}
+
+func TestInit(t *testing.T) {
+	t.Parallel()
+	if !bundled.Embedded {
+		// Without embedding, we'd need to read all of the lib files out from disk into the MapFS.
+		// Just skip this for now.
+		t.Skip("bundled files are not embedded")
+	}
+
+	cases := []tscInput{{
+		subScenario:     "default options",
+		sys:             newTestSys(nil, ""),
+		commandLineArgs: []string{"--init"},
+	}, {
+		subScenario: "with command line options and files",
+		sys: newTestSys(FileMap{
+			"/home/src/workspaces/project/first.ts": `export const a = 1;`,
+		}, ""),
+		commandLineArgs: []string{"--init", "--target", "es2020", "--lib", "es2015,dom", "--outDir", "dist", "--strict", "false", "--noUnusedLocals", "first.ts"},
+	}, {
+		subScenario: "when tsconfig already exists",
+		sys: newTestSys(FileMap{
+			"/home/src/workspaces/project/tsconfig.json": `{}`,
+		}, ""),
+		commandLineArgs: []string{"--init"},
+	}}
+
+	for _, c := range cases {
+		c.verify(t, "init")
+	}
+}
+
 func TestNoEmit(t *testing.T) {
 	t.Parallel()
 	if !bundled.Embedded {
@@ -252,3 +340,134 @@ func TestExtends(t *testing.T) {
 		c.verify(t, "extends")
 	}
 }
+
+func TestDeclarationEmit(t *testing.T) {
+	t.Parallel()
+	if !bundled.Embedded {
+		// Without embedding, we'd need to read all of the lib files out from disk into the MapFS.
+		// Just skip this for now.
+		t.Skip("bundled files are not embedded")
+	}
+
+	declarationSysFiles := FileMap{
+		"/home/src/workspaces/project/src/index.ts": `export function add(a: number, b = 1) {
+	return a + b;
+}
+export class Point {
+	constructor(public x: number, public y: number) {}
+	scale(factor: number) {
+		return new Point(this.x * factor, this.y * factor);
+	}
+}`,
+		"/home/src/workspaces/project/tsconfig.json": `{
+	"compilerOptions": {
+		"declaration": true,
+		"outDir": "dist",
+		"declarationDir": "types",
+	},
+}`,
+	}
+
+	cases := []tscInput{{
+		subScenario:     "declarationDir",
+		sys:             newTestSys(declarationSysFiles, ""),
+		commandLineArgs: []string{},
+	}, {
+		subScenario:     "emitDeclarationOnly",
+		sys:             newTestSys(declarationSysFiles, ""),
+		commandLineArgs: []string{"--emitDeclarationOnly"},
+	}, {
+		subScenario:     "declarationMap",
+		sys:             newTestSys(declarationSysFiles, ""),
+		commandLineArgs: []string{"--declarationMap"},
+	}}
+
+	for _, c := range cases {
+		c.verify(t, "declarationEmit")
+	}
+}
+
+func TestIncremental(t *testing.T) {
+	t.Parallel()
+	if !bundled.Embedded {
+		// Without embedding, we'd need to read all of the lib files out from disk into the MapFS.
+		// Just skip this for now.
+		t.Skip("bundled files are not embedded")
+	}
+
+	incrementalSysFiles := FileMap{
+		"/home/src/workspaces/project/tsconfig.json": `{
+	"compilerOptions": {
+		"incremental": true,
+		"declaration": true,
+	},
+}`,
+		"/home/src/workspaces/project/a.ts": `export function a() { return 1; }`,
+		"/home/src/workspaces/project/b.ts": `import { a } from "./a";
+export const b = a();`,
+		"/home/src/workspaces/project/c.ts": `export const c = "c";`,
+	}
+
+	//nolint:errcheck
+	cases := []tscInput{{
+		subScenario:     "changes to files and their dependencies",
+		sys:             newTestSys(incrementalSysFiles, ""),
+		commandLineArgs: []string{},
+		edits: []*testTscEdit{
+			newTscEdit("no change", nil),
+			newTscEdit("change body of file without changing its declarations", func(sys execute.System) {
+				sys.FS().WriteFile("/home/src/workspaces/project/a.ts", `export function a() { return 2; }`, false)
+			}),
+			newTscEdit("change declarations of file", func(sys execute.System) {
+				sys.FS().WriteFile("/home/src/workspaces/project/a.ts", `export function a() { return "a"; }`, false)
+			}),
+			newTscEdit("introduce error", func(sys execute.System) {
+				sys.FS().WriteFile("/home/src/workspaces/project/c.ts", `export const c: number = "c";`, false)
+			}),
+			newTscEdit("no change with error", nil),
+			newTscEdit("fix error", func(sys execute.System) {
+				sys.FS().WriteFile("/home/src/workspaces/project/c.ts", `export const c = "c";`, false)
+			}),
+			{
+				caption:         "change emit options",
+				commandLineArgs: []string{"--removeComments"},
+			},
+		},
+	}, {
+		subScenario: "with syntax error",
+		sys: newTestSys(FileMap{
+			"/home/src/workspaces/project/tsconfig.json": `{
+	"compilerOptions": {
+		"incremental": true,
+		"outDir": "dist",
+	},
+}`,
+			"/home/src/workspaces/project/a.ts": `export const a = 1;`,
+			"/home/src/workspaces/project/b.ts": `export const b: string = 1;
+export const x = (;`,
+		}, ""),
+		commandLineArgs: []string{},
+		edits: []*testTscEdit{
+			newTscEdit("fix syntax error", func(sys execute.System) {
+				sys.FS().WriteFile("/home/src/workspaces/project/b.ts", `export const b: string = 1;`, false)
+			}),
+			newTscEdit("no change", nil),
+		},
+	}, {
+		subScenario: "with tsBuildInfoFile",
+		sys: newTestSys(FileMap{
+			"/home/src/workspaces/project/tsconfig.json": `{
+	"compilerOptions": {
+		"incremental": true,
+		"tsBuildInfoFile": "cache/project.tsbuildinfo",
+	},
+}`,
+			"/home/src/workspaces/project/a.ts": `export const a = 1;`,
+		}, ""),
+		commandLineArgs: []string{},
+	}}
+
+	for _, c := range cases {
+		c.verify(t, "incremental")
+	}
+}
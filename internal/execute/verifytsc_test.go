@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/microsoft/typescript-go/internal/execute"
 	"github.com/microsoft/typescript-go/internal/testutil/baseline"
@@ -24,6 +25,9 @@ type tscInput struct {
 
 	// for watch tests
 	data map[string]string
+
+	// run in order after the initial compile, each with its own section in the baseline
+	edits []*testTscEdit
 }
 
 func (test *tscInput) verify(t *testing.T, scenario string) {
@@ -34,31 +38,61 @@ func (test *tscInput) verify(t *testing.T, scenario string) {
 			t.Parallel()
 			// initial test tsc compile
 			baselineBuilder := test.startBaseline()
+			test.run(baselineBuilder, test.commandLineArgs)
+
+			for _, edit := range test.edits {
+				baselineBuilder.WriteString("\n\nEdit:: " + edit.caption + "\n")
+				// edits happen after the previous compile, so anything they write is newer than its outputs
+				test.sys.advanceTime(time.Minute)
+				if edit.edit != nil {
+					edit.edit(test.sys)
+				}
+				commandLineArgs := edit.commandLineArgs
+				if len(commandLineArgs) == 0 {
+					commandLineArgs = test.commandLineArgs
+				}
+				test.run(baselineBuilder, commandLineArgs)
+			}
 
-			parsedCommandLine, exit := execute.CommandLineTest(test.sys, nil, test.commandLineArgs)
-			baselineBuilder.WriteString("ExitStatus:: " + fmt.Sprint(exit))
-
-			compilerOptionsString, _ := json.MarshalIndent(parsedCommandLine.CompilerOptions(), "", "    ")
-			baselineBuilder.WriteString("\n\nCompilerOptions::")
-			baselineBuilder.Write(compilerOptionsString)
-
-			test.sys.serializeState(baselineBuilder)
 			options, name := test.getBaselineName(scenario, false, "")
 			baseline.Run(t, name, baselineBuilder.String(), options)
 		})
 	})
 }
 
+func (test *tscInput) run(baselineBuilder *strings.Builder, commandLineArgs []string) {
+	if execute.IsBuildCommand(commandLineArgs) {
+		buildCommand, exit := execute.CommandLineBuildTest(test.sys, nil, commandLineArgs)
+		baselineBuilder.WriteString("ExitStatus:: " + fmt.Sprint(exit))
+
+		buildOptionsString, _ := json.MarshalIndent(buildCommand.BuildOptions, "", "    ")
+		baselineBuilder.WriteString("\n\nBuildOptions::")
+		baselineBuilder.Write(buildOptionsString)
+
+		compilerOptionsString, _ := json.MarshalIndent(buildCommand.CompilerOptions, "", "    ")
+		baselineBuilder.WriteString("\n\nCompilerOptions::")
+		baselineBuilder.Write(compilerOptionsString)
+	} else {
+		parsedCommandLine, exit := execute.CommandLineTest(test.sys, nil, commandLineArgs)
+		baselineBuilder.WriteString("ExitStatus:: " + fmt.Sprint(exit))
+
+		compilerOptionsString, _ := json.MarshalIndent(parsedCommandLine.CompilerOptions(), "", "    ")
+		baselineBuilder.WriteString("\n\nCompilerOptions::")
+		baselineBuilder.Write(compilerOptionsString)
+	}
+
+	test.sys.serializeState(baselineBuilder)
+}
+
 func (test *tscInput) getTestName(scenario string) string {
 	return "tsc " + strings.Join(test.commandLineArgs, " ") + " " + scenario + ":: " + test.subScenario
 }
 
 func (test *tscInput) getBaselineName(scenario string, watch bool, suffix string) (baseline.Options, string) {
 	commandName := "tsc"
-	// todo build
-	// if isBuildCommand(v.data.commandLineArgs) {
-	// 	commandName = "tsbuild"
-	// }
+	if execute.IsBuildCommand(test.commandLineArgs) {
+		commandName = "tsbuild"
+	}
 	w := ""
 	if watch {
 		w = "Watch"
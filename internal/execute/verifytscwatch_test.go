@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/microsoft/typescript-go/internal/bundled"
 	"github.com/microsoft/typescript-go/internal/execute"
@@ -31,6 +32,8 @@ func verifyWatch(t *testing.T, test *tscInput, scenario string, edits []*testTsc
 			test.sys.serializeState(baselineBuilder)
 
 			for _, do := range edits {
+				// edits happen after the previous compile, so the watcher sees the files they write as modified
+				test.sys.advanceTime(time.Minute)
 				do.edit(test.sys)
 				baselineBuilder.WriteString("\n\nEdit:: " + do.caption + "\n")
 
@@ -86,10 +89,10 @@ func noEmitWatchTestInput(
 		"aText":        aText,
 	}
 	return &tscInput{
-		subScenario,
-		commandLineArgs,
-		sys,
-		data,
+		subScenario:     subScenario,
+		commandLineArgs: commandLineArgs,
+		sys:             sys,
+		data:            data,
 	}
 }
 
@@ -1,40 +1,57 @@
 package execute
 
 import (
-	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
-	"github.com/microsoft/typescript-go/internal/compiler"
+	"github.com/microsoft/typescript-go/internal/vfs/vfswatch"
 )
 
+// watchDebounceDelay is how long the watcher waits for more changes after a file changes before compiling again,
+// since saving a file or switching branches usually changes several files in quick succession.
+const watchDebounceDelay = 250 * time.Millisecond
+
 func start(w *watcher) ExitStatus {
-	if w.configFileName == "" {
-		w.host = compiler.NewCompilerHost(w.options.CompilerOptions(), w.sys.GetCurrentDirectory(), w.sys.FS(), w.sys.DefaultLibraryPath())
-	}
-	watchInterval := 1000 * time.Millisecond
-	if w.options.ParsedConfig.WatchOptions != nil {
-		watchInterval = time.Duration(*w.options.ParsedConfig.WatchOptions.Interval) * time.Millisecond
-	}
-	for {
-		w.doCycle()
-		time.Sleep(watchInterval)
-	}
-}
+	// Watch mode ends when the process is interrupted, which closes the watches of the operating system
+	defer w.fileWatcher.Close()
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupted)
 
-func (w *watcher) doCycle() {
-	if w.hasErrorsInTsConfig() {
-		// these are unrecoverable errors--report them and do not build
-		return
-	}
-	// updateProgram()
-	w.program = compiler.NewProgramFromParsedCommandLine(w.options, w.host)
-	if w.hasBeenModified(w.program) {
-		fmt.Fprint(w.sys.Writer(), "build starting at ", w.sys.Now(), w.sys.NewLine())
-		timeStart := w.sys.Now()
-		w.compileAndEmit()
-		fmt.Fprint(w.sys.Writer(), "build finished in ", w.sys.Now().Sub(timeStart), w.sys.NewLine())
-	} else {
-		// print something???
-		// fmt.Fprint(w.sys.Writer(), "no changes detected at ", w.sys.Now(), w.sys.NewLine())
+	w.initialize()
+	events := w.fileWatcher.Events()
+	for {
+		var event vfswatch.Event
+		var ok bool
+		select {
+		case event, ok = <-events:
+		case <-interrupted:
+			return ExitStatusSuccess
+		}
+		if !ok {
+			return ExitStatusSuccess
+		}
+		changes := []vfswatch.Event{event}
+		timer := time.NewTimer(watchDebounceDelay)
+	collect:
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					break collect
+				}
+				changes = append(changes, event)
+				timer.Reset(watchDebounceDelay)
+			case <-timer.C:
+				break collect
+			case <-interrupted:
+				timer.Stop()
+				return ExitStatusSuccess
+			}
+		}
+		timer.Stop()
+		w.onFileChanges(changes)
 	}
 }
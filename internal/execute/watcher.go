@@ -1,89 +1,248 @@
 package execute
 
 import (
-	"time"
+	"maps"
+	"slices"
+	"strings"
 
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/bundled"
+	"github.com/microsoft/typescript-go/internal/collections"
 	"github.com/microsoft/typescript-go/internal/compiler"
+	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
 	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/incremental"
 	"github.com/microsoft/typescript-go/internal/tsoptions"
 	"github.com/microsoft/typescript-go/internal/tspath"
+	"github.com/microsoft/typescript-go/internal/vfs/vfswatch"
 )
 
 type watcher struct {
-	sys              System
-	configFileName   string
-	options          *tsoptions.ParsedCommandLine
-	reportDiagnostic diagnosticReporter
-
-	host           compiler.CompilerHost
-	program        *compiler.Program
-	prevModified   map[string]time.Time
-	configModified bool
+	sys            System
+	configFileName string
+	// The options given on the command line, which override those of the config file whenever it is reloaded
+	compilerOptionsFromCommandLine *core.CompilerOptions
+	options                        *tsoptions.ParsedCommandLine
+	reportDiagnostic               diagnosticReporter
+	reportWatchStatus              diagnosticReporter
+
+	host        *watchCompilerHost
+	program     *compiler.Program
+	fileWatcher vfswatch.Watcher
+	// The files and directories being watched; directories are mapped to whether they are watched recursively
+	watchedFiles       core.Set[string]
+	watchedDirectories map[string]bool
+	configFiles        core.Set[string]
+
+	initialized   bool
+	configChanged bool
 }
 
-func createWatcher(sys System, configParseResult *tsoptions.ParsedCommandLine, reportDiagnostic diagnosticReporter) *watcher {
-	return &watcher{
-		sys:              sys,
-		configFileName:   configParseResult.ConfigFile.SourceFile.FileName(),
-		options:          configParseResult,
-		reportDiagnostic: reportDiagnostic,
-		// reportWatchStatus: createWatchStatusReporter(sys, configParseResult.CompilerOptions().Pretty),
+func createWatcher(sys System, configParseResult *tsoptions.ParsedCommandLine, commandLine *tsoptions.ParsedCommandLine, reportDiagnostic diagnosticReporter) *watcher {
+	w := &watcher{
+		sys:                            sys,
+		compilerOptionsFromCommandLine: commandLine.CompilerOptions(),
+		options:                        configParseResult,
+		reportDiagnostic:               reportDiagnostic,
+		reportWatchStatus:              createWatchStatusReporter(sys, configParseResult.CompilerOptions()),
+		fileWatcher:                    sys.NewFileWatcher(commandLine.ParsedConfig.WatchOptions),
+		watchedDirectories:             make(map[string]bool),
 	}
+	if configParseResult.ConfigFile != nil {
+		w.configFileName = configParseResult.ConfigFile.SourceFile.FileName()
+	}
+	w.host = newWatchCompilerHost(sys, configParseResult.CompilerOptions())
+	return w
 }
 
-func (w *watcher) compileAndEmit() {
-	// !!! output/error reporting is currently the same as non-watch mode
-	// diagnostics, emitResult, exitStatus :=
-	compileAndEmit(w.sys, w.program, w.reportDiagnostic)
+// initialize compiles the program for the first time and starts watching its files.
+func (w *watcher) initialize() {
+	w.initialized = true
+	w.reportWatchStatus(ast.NewCompilerDiagnostic(diagnostics.Starting_compilation_in_watch_mode))
+	w.compile()
 }
 
-func (w *watcher) hasErrorsInTsConfig() bool {
-	// only need to check and reparse tsconfig options/update host if we are watching a config file
-	if w.configFileName != "" {
-		extendedConfigCache := map[tspath.Path]*tsoptions.ExtendedConfigCacheEntry{}
-		// !!! need to check that this merges compileroptions correctly. This differs from non-watch, since we allow overriding of previous options
-		configParseResult, errors := getParsedCommandLineOfConfigFile(w.configFileName, &core.CompilerOptions{}, w.sys, extendedConfigCache)
-		if len(errors) > 0 {
-			for _, e := range errors {
-				w.reportDiagnostic(e)
-			}
-			return true
+// onFileChanges compiles the program again if any of the changes affect it.
+func (w *watcher) onFileChanges(events []vfswatch.Event) {
+	changed := false
+	rootsMayHaveChanged := false
+	for _, event := range events {
+		w.host.invalidate(event.Path)
+		switch {
+		case w.configFiles.Has(event.Path):
+			w.configChanged = true
+			changed = true
+		case w.watchedFiles.Has(event.Path):
+			changed = true
+		case event.Kind != vfswatch.EventKindChanged:
+			// A file was added to or removed from a directory that files of the program are included from
+			rootsMayHaveChanged = true
 		}
-		if w.options.CompilerOptions() != configParseResult.CompilerOptions() {
-			w.configModified = true
+	}
+	if rootsMayHaveChanged && !w.configChanged && w.haveRootFilesChanged() {
+		w.configChanged = true
+		changed = true
+	}
+	if !changed {
+		return
+	}
+	w.reportWatchStatus(ast.NewCompilerDiagnostic(diagnostics.File_change_detected_Starting_incremental_compilation))
+	w.compile()
+}
+
+func (w *watcher) haveRootFilesChanged() bool {
+	if w.configFileName == "" {
+		return false
+	}
+	configParseResult, errors := w.parseConfigFile()
+	return len(errors) != 0 || !slices.Equal(configParseResult.FileNames(), w.options.FileNames())
+}
+
+func (w *watcher) compile() {
+	if w.configChanged {
+		w.configChanged = false
+		if !w.reloadConfigFile() {
+			w.updateWatches()
+			return
 		}
-		w.options = configParseResult
-		w.host = compiler.NewCompilerHost(w.options.CompilerOptions(), w.sys.GetCurrentDirectory(), w.sys.FS(), w.sys.DefaultLibraryPath())
 	}
-	return false
+
+	w.program = compiler.NewProgramFromParsedCommandLine(w.options, w.host)
+	var program programLike = w.program
+	if w.options.CompilerOptions().IsIncremental() {
+		program = incremental.NewProgram(w.program, readBuildInfo(w.sys, w.options.CompilerOptions()))
+	}
+	diagnostics, _ := emitFilesAndReportErrors(w.sys, program, w.reportDiagnostic)
+	w.reportWatchErrorSummary(diagnostics)
+	w.updateWatches()
+}
+
+func (w *watcher) parseConfigFile() (*tsoptions.ParsedCommandLine, []*ast.Diagnostic) {
+	extendedConfigCache := map[tspath.Path]*tsoptions.ExtendedConfigCacheEntry{}
+	return tsoptions.GetParsedCommandLineOfConfigFile(w.configFileName, w.compilerOptionsFromCommandLine, w.sys, extendedConfigCache)
 }
 
-func (w *watcher) hasBeenModified(program *compiler.Program) bool {
-	// checks watcher's snapshot against program file modified times
-	currState := map[string]time.Time{}
-	filesModified := w.configModified
-	for _, sourceFile := range program.SourceFiles() {
-		fileName := sourceFile.FileName()
-		s := w.sys.FS().Stat(fileName)
-		if s == nil {
-			// do nothing; if file is in program.SourceFiles() but is not found when calling Stat, file has been very recently deleted.
-			// deleted files are handled outside of this loop
-			continue
+// reloadConfigFile parses the config file again, returning false if it has errors that prevent compiling the
+// program.
+func (w *watcher) reloadConfigFile() bool {
+	configParseResult, errors := w.parseConfigFile()
+	if len(errors) > 0 {
+		// these are unrecoverable errors--report them and do not build
+		for _, e := range errors {
+			w.reportDiagnostic(e)
 		}
-		currState[fileName] = s.ModTime()
-		if !filesModified {
-			if currState[fileName] != w.prevModified[fileName] {
-				filesModified = true
+		w.reportWatchErrorSummary(errors)
+		return false
+	}
+	if *configParseResult.CompilerOptions().SourceFileAffecting() != *w.options.CompilerOptions().SourceFileAffecting() {
+		// Source files parsed and bound with the old options cannot be reused
+		w.host = newWatchCompilerHost(w.sys, configParseResult.CompilerOptions())
+	} else {
+		w.host.CompilerHost = compiler.NewCompilerHost(configParseResult.CompilerOptions(), w.sys.GetCurrentDirectory(), w.sys.FS(), w.sys.DefaultLibraryPath())
+	}
+	w.options = configParseResult
+	return true
+}
+
+func (w *watcher) reportWatchErrorSummary(allDiagnostics []*ast.Diagnostic) {
+	errorCount := core.CountWhere(allDiagnostics, func(diagnostic *ast.Diagnostic) bool {
+		return diagnostic.Category() == diagnostics.CategoryError
+	})
+	if errorCount == 1 {
+		w.reportWatchStatus(ast.NewCompilerDiagnostic(diagnostics.Found_1_error_Watching_for_file_changes))
+	} else {
+		w.reportWatchStatus(ast.NewCompilerDiagnostic(diagnostics.Found_0_errors_Watching_for_file_changes, errorCount))
+	}
+}
+
+// updateWatches watches the config files, the files of the program and the directories its root files are
+// included from, and stops watching everything else.
+func (w *watcher) updateWatches() {
+	var configFiles core.Set[string]
+	if w.configFileName != "" {
+		configFiles.Add(w.configFileName)
+		for _, fileName := range w.options.ExtendedSourceFiles() {
+			configFiles.Add(fileName)
+		}
+	}
+	var files core.Set[string]
+	for fileName := range configFiles.Keys() {
+		files.Add(fileName)
+	}
+	if w.program != nil {
+		for _, file := range w.program.GetSourceFiles() {
+			// Files embedded in the executable never change
+			if bundled.Embedded && strings.HasPrefix(file.FileName(), bundled.LibPath()) {
+				continue
 			}
-			delete(w.prevModified, fileName)
+			files.Add(file.FileName())
+		}
+	}
+	directories := w.options.WildcardDirectories(w.sys.FS().UseCaseSensitiveFileNames())
+
+	for _, fileName := range slices.Sorted(maps.Keys(w.watchedFiles.Keys())) {
+		if !files.Has(fileName) {
+			w.fileWatcher.Unwatch(fileName)
+			w.watchedFiles.Delete(fileName)
+		}
+	}
+	for _, fileName := range slices.Sorted(maps.Keys(files.Keys())) {
+		if !w.watchedFiles.Has(fileName) {
+			w.fileWatcher.WatchFile(fileName)
+			w.watchedFiles.Add(fileName)
+		}
+	}
+	for _, directoryName := range slices.Sorted(maps.Keys(w.watchedDirectories)) {
+		if recursive, ok := directories[directoryName]; !ok || recursive != w.watchedDirectories[directoryName] {
+			w.fileWatcher.Unwatch(directoryName)
+			delete(w.watchedDirectories, directoryName)
+		}
+	}
+	for _, directoryName := range slices.Sorted(maps.Keys(directories)) {
+		if _, ok := w.watchedDirectories[directoryName]; !ok {
+			w.fileWatcher.WatchDirectory(directoryName, directories[directoryName])
+			w.watchedDirectories[directoryName] = directories[directoryName]
 		}
 	}
-	if len(w.prevModified) > 0 {
-		filesModified = true
+	w.configFiles = configFiles
+}
+
+// watchCompilerHost keeps the source files parsed by earlier compilations, so that only the files that changed
+// since are parsed again.
+type watchCompilerHost struct {
+	compiler.CompilerHost
+	currentDirectory          string
+	useCaseSensitiveFileNames bool
+	sourceFiles               collections.SyncMap[tspath.Path, *watchedSourceFile]
+}
+
+type watchedSourceFile struct {
+	file            *ast.SourceFile
+	languageVersion core.ScriptTarget
+}
+
+var _ compiler.CompilerHost = (*watchCompilerHost)(nil)
+
+func newWatchCompilerHost(sys System, options *core.CompilerOptions) *watchCompilerHost {
+	return &watchCompilerHost{
+		CompilerHost:              compiler.NewCompilerHost(options, sys.GetCurrentDirectory(), sys.FS(), sys.DefaultLibraryPath()),
+		currentDirectory:          sys.GetCurrentDirectory(),
+		useCaseSensitiveFileNames: sys.FS().UseCaseSensitiveFileNames(),
+	}
+}
+
+func (h *watchCompilerHost) GetSourceFile(fileName string, path tspath.Path, languageVersion core.ScriptTarget) *ast.SourceFile {
+	if cached, ok := h.sourceFiles.Load(path); ok && cached.languageVersion == languageVersion && cached.file.FileName() == fileName {
+		return cached.file
+	}
+	file := h.CompilerHost.GetSourceFile(fileName, path, languageVersion)
+	if file != nil {
+		h.sourceFiles.Store(path, &watchedSourceFile{file: file, languageVersion: languageVersion})
 	}
-	w.prevModified = currState
+	return file
+}
 
-	// reset state for next cycle
-	w.configModified = false
-	return filesModified
+// invalidate forgets the source file for a file that changed.
+func (h *watchCompilerHost) invalidate(fileName string) {
+	h.sourceFiles.Delete(tspath.ToPath(fileName, h.currentDirectory, h.useCaseSensitiveFileNames))
 }
@@ -0,0 +1,189 @@
+package incremental
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+	"github.com/microsoft/typescript-go/internal/collections"
+	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/tsoptions"
+	"github.com/microsoft/typescript-go/internal/tspath"
+	"github.com/microsoft/typescript-go/internal/vfs"
+)
+
+// BuildInfoFileId is the 1-based index of a file in BuildInfo.FileNames.
+type BuildInfoFileId int
+
+// BuildInfo is the state of an incremental compilation, as saved in a .tsbuildinfo file.
+type BuildInfo struct {
+	Version string `json:"version,omitzero"`
+	// Whether the program had errors that have to be reported again by the next build
+	Errors bool `json:"errors,omitzero"`
+	// File names are relative to the directory of the .tsbuildinfo file
+	Root                       []string                                        `json:"root,omitzero"`
+	FileNames                  []string                                        `json:"fileNames,omitzero"`
+	FileInfos                  []*BuildInfoFileInfo                            `json:"fileInfos,omitzero"`
+	Options                    *collections.OrderedMap[string, jsontext.Value] `json:"options,omitzero"`
+	ReferencedMap              []*BuildInfoReferencedFiles                     `json:"referencedMap,omitzero"`
+	SemanticDiagnosticsPerFile []*BuildInfoDiagnosticsOfFile                   `json:"semanticDiagnosticsPerFile,omitzero"`
+	EmitDiagnosticsPerFile     []*BuildInfoDiagnosticsOfFile                   `json:"emitDiagnosticsPerFile,omitzero"`
+	// Files that still have to be checked, such as when syntax errors prevented checking the program
+	CheckPending             []BuildInfoFileId `json:"checkPending,omitzero"`
+	AffectedFilesPendingEmit []BuildInfoFileId `json:"affectedFilesPendingEmit,omitzero"`
+}
+
+type BuildInfoFileInfo struct {
+	// Hash of the file's text
+	Version string `json:"version,omitzero"`
+	// Hash of the file's declarations, when it has been computed and differs from the version
+	Signature          string `json:"signature,omitzero"`
+	AffectsGlobalScope bool   `json:"affectsGlobalScope,omitzero"`
+}
+
+type BuildInfoReferencedFiles struct {
+	File       BuildInfoFileId   `json:"file"`
+	References []BuildInfoFileId `json:"references"`
+}
+
+type BuildInfoDiagnosticsOfFile struct {
+	File        BuildInfoFileId        `json:"file"`
+	Diagnostics []*BuildInfoDiagnostic `json:"diagnostics"`
+}
+
+type BuildInfoDiagnostic struct {
+	// The file of a related information, if it is not the file the diagnostic belongs to
+	File               BuildInfoFileId        `json:"file,omitzero"`
+	NoFile             bool                   `json:"noFile,omitzero"`
+	Pos                int                    `json:"pos"`
+	End                int                    `json:"end"`
+	Code               int32                  `json:"code"`
+	Category           diagnostics.Category   `json:"category"`
+	Message            string                 `json:"message"`
+	MessageChain       []*BuildInfoDiagnostic `json:"messageChain,omitzero"`
+	RelatedInformation []*BuildInfoDiagnostic `json:"relatedInformation,omitzero"`
+}
+
+// ReadBuildInfo reads the .tsbuildinfo file at the given path, returning nil if it does not exist or cannot be
+// parsed.
+func ReadBuildInfo(fs vfs.FS, buildInfoFileName string) *BuildInfo {
+	text, ok := fs.ReadFile(buildInfoFileName)
+	if !ok {
+		return nil
+	}
+	var buildInfo BuildInfo
+	if err := json.Unmarshal([]byte(text), &buildInfo); err != nil {
+		return nil
+	}
+	return &buildInfo
+}
+
+// IsValidVersion reports whether the build info was written by this version of the compiler.
+func (b *BuildInfo) IsValidVersion() bool {
+	return b.Version == core.Version
+}
+
+// HasChangedOptions reports whether the options that affect the build info differ from the ones the build info was
+// written with.
+func (b *BuildInfo) HasChangedOptions(options *core.CompilerOptions, buildInfoFileName string) bool {
+	current := getBuildInfoOptions(options, tspath.GetDirectoryPath(buildInfoFileName))
+	return !optionsEqual(b.Options, current, func(option *tsoptions.CommandLineOption) bool { return option.AffectsBuildInfo })
+}
+
+// GetFileVersions returns the hash of the text of each file recorded in the build info, keyed by file path.
+func (b *BuildInfo) GetFileVersions(buildInfoFileName string, comparePathsOptions tspath.ComparePathsOptions) map[tspath.Path]string {
+	buildInfoDirectory := tspath.GetDirectoryPath(buildInfoFileName)
+	versions := make(map[tspath.Path]string, len(b.FileNames))
+	for i, fileName := range b.FileNames {
+		if i < len(b.FileInfos) {
+			path := tspath.ToPath(fileName, buildInfoDirectory, comparePathsOptions.UseCaseSensitiveFileNames)
+			versions[path] = b.FileInfos[i].Version
+		}
+	}
+	return versions
+}
+
+// GetRootFileNames returns the absolute names of the root files of the program that wrote the build info.
+func (b *BuildInfo) GetRootFileNames(buildInfoFileName string) []string {
+	buildInfoDirectory := tspath.GetDirectoryPath(buildInfoFileName)
+	return core.Map(b.Root, func(fileName string) string {
+		return tspath.GetNormalizedAbsolutePath(fileName, buildInfoDirectory)
+	})
+}
+
+// ComputeHash returns the hash used to tell whether the text of a file changed between compilations.
+func ComputeHash(text string) string {
+	hash := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(hash[:])
+}
+
+// getBuildInfoOptions returns the options that affect the build info, in declaration order, with file paths made
+// relative to the directory of the build info.
+func getBuildInfoOptions(options *core.CompilerOptions, buildInfoDirectory string) *collections.OrderedMap[string, jsontext.Value] {
+	data, err := json.Marshal(options)
+	if err != nil {
+		panic(err)
+	}
+	var values map[string]jsontext.Value
+	if err := json.Unmarshal(data, &values); err != nil {
+		panic(err)
+	}
+
+	result := &collections.OrderedMap[string, jsontext.Value]{}
+	for _, option := range tsoptions.OptionsDeclarations {
+		value, ok := values[option.Name]
+		if !ok || !option.AffectsBuildInfo || result.Has(option.Name) {
+			continue
+		}
+		if option.IsFilePath() {
+			value = relativeOptionValue(value, buildInfoDirectory)
+		}
+		result.Set(option.Name, value)
+	}
+	return result
+}
+
+func relativeOptionValue(value jsontext.Value, buildInfoDirectory string) jsontext.Value {
+	comparePathsOptions := tspath.ComparePathsOptions{CurrentDirectory: buildInfoDirectory}
+	var fileName string
+	if json.Unmarshal(value, &fileName) == nil {
+		return marshalValue(tspath.GetRelativePathFromDirectory(buildInfoDirectory, fileName, comparePathsOptions))
+	}
+	var fileNames []string
+	if json.Unmarshal(value, &fileNames) == nil {
+		return marshalValue(core.Map(fileNames, func(fileName string) string {
+			return tspath.GetRelativePathFromDirectory(buildInfoDirectory, fileName, comparePathsOptions)
+		}))
+	}
+	return value
+}
+
+func marshalValue(value any) jsontext.Value {
+	data, err := json.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// optionsEqual compares the values of the options selected by the filter.
+func optionsEqual(old *collections.OrderedMap[string, jsontext.Value], current *collections.OrderedMap[string, jsontext.Value], filter func(option *tsoptions.CommandLineOption) bool) bool {
+	for _, option := range tsoptions.OptionsDeclarations {
+		if !filter(option) {
+			continue
+		}
+		if string(getOptionValue(old, option.Name)) != string(getOptionValue(current, option.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+func getOptionValue(options *collections.OrderedMap[string, jsontext.Value], name string) jsontext.Value {
+	if options == nil {
+		return nil
+	}
+	return options.GetOrZero(name)
+}
@@ -0,0 +1,555 @@
+package incremental
+
+import (
+	"context"
+	"slices"
+
+	"github.com/go-json-experiment/json"
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/compiler"
+	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/tsoptions"
+	"github.com/microsoft/typescript-go/internal/tspath"
+)
+
+type fileInfo struct {
+	version string
+	// Hash of the declarations of the file, or "" if they were not computed
+	signature          string
+	affectsGlobalScope bool
+}
+
+// Program wraps a compiler.Program with the state saved by the previous compilation in its .tsbuildinfo file, so
+// that only the files affected by changes since then are checked and emitted again.
+type Program struct {
+	program           *compiler.Program
+	buildInfoFileName string
+
+	fileInfos     map[tspath.Path]*fileInfo
+	referencedMap map[tspath.Path][]tspath.Path
+	referencedBy  map[tspath.Path][]tspath.Path
+
+	// Semantic diagnostics of the files that were checked, either by this compilation or by an earlier one
+	semanticDiagnosticsPerFile map[tspath.Path][]*ast.Diagnostic
+	// Declaration emit diagnostics of the files that were emitted
+	emitDiagnosticsPerFile   map[tspath.Path][]*ast.Diagnostic
+	affectedFilesPendingEmit core.Set[tspath.Path]
+	hasDeclarationErrors     bool
+}
+
+// outputKindOptions select which outputs are written for a file rather than what they contain, so they are not
+// declared as affecting emit, but a change to them still means the files have to be emitted again.
+var outputKindOptions = []string{"declaration", "declarationMap", "emitDeclarationOnly", "sourceMap", "inlineSourceMap", "composite"}
+
+// oldState is the state of the previous compilation, with files identified by path.
+type oldState struct {
+	buildInfo           *BuildInfo
+	paths               []tspath.Path
+	fileInfos           map[tspath.Path]*BuildInfoFileInfo
+	referencedMap       map[tspath.Path][]tspath.Path
+	semanticDiagnostics map[tspath.Path][]*BuildInfoDiagnostic
+	emitDiagnostics     map[tspath.Path][]*BuildInfoDiagnostic
+	pendingEmit         core.Set[tspath.Path]
+}
+
+// NewProgram creates an incremental program from a freshly created program and the build info of the previous
+// compilation, which may be nil.
+func NewProgram(program *compiler.Program, oldBuildInfo *BuildInfo) *Program {
+	p := &Program{
+		program:                    program,
+		buildInfoFileName:          compiler.GetTsBuildInfoEmitOutputFilePath(program.Options()),
+		fileInfos:                  make(map[tspath.Path]*fileInfo, len(program.GetSourceFiles())),
+		referencedMap:              make(map[tspath.Path][]tspath.Path),
+		referencedBy:               make(map[tspath.Path][]tspath.Path),
+		semanticDiagnosticsPerFile: make(map[tspath.Path][]*ast.Diagnostic),
+		emitDiagnosticsPerFile:     make(map[tspath.Path][]*ast.Diagnostic),
+	}
+
+	files := program.GetSourceFiles()
+	infos := make([]*fileInfo, len(files))
+	wg := core.NewWorkGroup(program.SingleThreaded())
+	for i, file := range files {
+		wg.Queue(func() {
+			infos[i] = &fileInfo{
+				version:            ComputeHash(file.Text()),
+				affectsGlobalScope: fileAffectsGlobalScope(file),
+			}
+		})
+	}
+	wg.RunAndWait()
+
+	for i, file := range files {
+		path := file.Path()
+		p.fileInfos[path] = infos[i]
+		if references := program.GetReferencedFiles(path); len(references) > 0 {
+			p.referencedMap[path] = references
+			for _, reference := range references {
+				p.referencedBy[reference] = append(p.referencedBy[reference], path)
+			}
+		}
+	}
+
+	old := p.readOldState(oldBuildInfo)
+	if old == nil {
+		for _, file := range files {
+			if mayBeEmitted(file) {
+				p.affectedFilesPendingEmit.Add(file.Path())
+			}
+		}
+		return p
+	}
+
+	currentOptions := getBuildInfoOptions(program.Options(), tspath.GetDirectoryPath(p.buildInfoFileName))
+	semanticOptionsChanged := !optionsEqual(old.buildInfo.Options, currentOptions, func(option *tsoptions.CommandLineOption) bool {
+		return option.AffectsSemanticDiagnostics
+	})
+	emitOptionsChanged := !optionsEqual(old.buildInfo.Options, currentOptions, func(option *tsoptions.CommandLineOption) bool {
+		return option.AffectsEmit || option.AffectsDeclarationPath || slices.Contains(outputKindOptions, option.Name)
+	})
+
+	affected := p.getAffectedFiles(old)
+	for _, file := range files {
+		path := file.Path()
+		if !semanticOptionsChanged && !affected.Has(path) {
+			if diagnostics, ok := old.semanticDiagnostics[path]; ok {
+				p.semanticDiagnosticsPerFile[path] = p.fromBuildInfoDiagnostics(old, diagnostics, file)
+			}
+		}
+		if !mayBeEmitted(file) {
+			continue
+		}
+		if emitOptionsChanged || affected.Has(path) || old.pendingEmit.Has(path) {
+			p.affectedFilesPendingEmit.Add(path)
+		} else if diagnostics, ok := old.emitDiagnostics[path]; ok {
+			p.emitDiagnosticsPerFile[path] = p.fromBuildInfoDiagnostics(old, diagnostics, file)
+		}
+	}
+	return p
+}
+
+func (p *Program) readOldState(buildInfo *BuildInfo) *oldState {
+	if buildInfo == nil || !buildInfo.IsValidVersion() || len(buildInfo.FileInfos) != len(buildInfo.FileNames) {
+		return nil
+	}
+	buildInfoDirectory := tspath.GetDirectoryPath(p.buildInfoFileName)
+	useCaseSensitiveFileNames := p.program.Host().FS().UseCaseSensitiveFileNames()
+	old := &oldState{
+		buildInfo:           buildInfo,
+		paths:               make([]tspath.Path, len(buildInfo.FileNames)),
+		fileInfos:           make(map[tspath.Path]*BuildInfoFileInfo, len(buildInfo.FileNames)),
+		referencedMap:       make(map[tspath.Path][]tspath.Path, len(buildInfo.ReferencedMap)),
+		semanticDiagnostics: make(map[tspath.Path][]*BuildInfoDiagnostic, len(buildInfo.FileNames)),
+		emitDiagnostics:     make(map[tspath.Path][]*BuildInfoDiagnostic, len(buildInfo.FileNames)),
+	}
+	for i, fileName := range buildInfo.FileNames {
+		path := tspath.ToPath(fileName, buildInfoDirectory, useCaseSensitiveFileNames)
+		old.paths[i] = path
+		old.fileInfos[path] = buildInfo.FileInfos[i]
+		// Files are known to be checked and emitted without diagnostics unless recorded otherwise below
+		old.semanticDiagnostics[path] = nil
+	}
+	toPath := func(id BuildInfoFileId) (tspath.Path, bool) {
+		if id < 1 || int(id) > len(old.paths) {
+			return "", false
+		}
+		return old.paths[id-1], true
+	}
+	for _, referenced := range buildInfo.ReferencedMap {
+		path, ok := toPath(referenced.File)
+		if !ok {
+			return nil
+		}
+		for _, reference := range referenced.References {
+			referencePath, ok := toPath(reference)
+			if !ok {
+				return nil
+			}
+			old.referencedMap[path] = append(old.referencedMap[path], referencePath)
+		}
+	}
+	for _, id := range buildInfo.CheckPending {
+		if path, ok := toPath(id); ok {
+			delete(old.semanticDiagnostics, path)
+		}
+	}
+	for _, diagnostics := range buildInfo.SemanticDiagnosticsPerFile {
+		if path, ok := toPath(diagnostics.File); ok {
+			old.semanticDiagnostics[path] = diagnostics.Diagnostics
+		}
+	}
+	for _, id := range buildInfo.AffectedFilesPendingEmit {
+		if path, ok := toPath(id); ok {
+			old.pendingEmit.Add(path)
+		}
+	}
+	for _, path := range old.paths {
+		if !old.pendingEmit.Has(path) {
+			old.emitDiagnostics[path] = nil
+		}
+	}
+	for _, diagnostics := range buildInfo.EmitDiagnosticsPerFile {
+		if path, ok := toPath(diagnostics.File); ok && !old.pendingEmit.Has(path) {
+			old.emitDiagnostics[path] = diagnostics.Diagnostics
+		}
+	}
+	return old
+}
+
+// getAffectedFiles returns the files whose diagnostics and outputs may differ from those of the previous
+// compilation: the changed files, and the files referencing a file whose declarations changed.
+func (p *Program) getAffectedFiles(old *oldState) *core.Set[tspath.Path] {
+	affected := &core.Set[tspath.Path]{}
+	allAffected := func() *core.Set[tspath.Path] {
+		for _, file := range p.program.GetSourceFiles() {
+			affected.Add(file.Path())
+		}
+		return affected
+	}
+
+	// A deleted file that affected the global scope may have affected any file
+	for path, info := range old.fileInfos {
+		if info.AffectsGlobalScope && p.fileInfos[path] == nil {
+			return allAffected()
+		}
+	}
+
+	seen := &core.Set[tspath.Path]{}
+	for _, file := range p.program.GetSourceFiles() {
+		path := file.Path()
+		oldInfo := old.fileInfos[path]
+		if oldInfo != nil && oldInfo.Version == p.fileInfos[path].version && samePaths(old.referencedMap[path], p.referencedMap[path]) {
+			if !file.IsDeclarationFile {
+				p.fileInfos[path].signature = oldInfo.Signature
+			}
+			continue
+		}
+
+		affected.Add(path)
+		seen.Add(path)
+		if !p.updateShapeSignature(file, oldInfo) {
+			continue
+		}
+		if p.fileInfos[path].affectsGlobalScope || oldInfo != nil && oldInfo.AffectsGlobalScope {
+			return allAffected()
+		}
+
+		// The declarations of the file changed, so the files referencing it have to be checked again, as do the
+		// files referencing them in turn when their own declarations change as a result.
+		queue := slices.Clone(p.referencedBy[path])
+		for len(queue) > 0 {
+			current := queue[len(queue)-1]
+			queue = queue[:len(queue)-1]
+			if seen.Has(current) {
+				continue
+			}
+			seen.Add(current)
+			affected.Add(current)
+			currentFile := p.program.GetSourceFileByPath(current)
+			if p.updateShapeSignature(currentFile, old.fileInfos[current]) {
+				queue = append(queue, p.referencedBy[current]...)
+			}
+		}
+	}
+	return affected
+}
+
+// updateShapeSignature computes the signature of the file and reports whether it differs from the signature it had
+// in the previous compilation.
+func (p *Program) updateShapeSignature(file *ast.SourceFile, oldInfo *BuildInfoFileInfo) bool {
+	info := p.fileInfos[file.Path()]
+	if file.IsDeclarationFile || !tspath.HasTSFileExtension(file.FileName()) {
+		// The text of the file is all there is to its shape
+		return oldInfo == nil || oldInfo.Version != info.version
+	}
+	info.signature = ComputeHash(p.program.GetDeclarationText(file))
+	return oldInfo == nil || oldInfo.Signature != info.signature
+}
+
+// GetProgram returns the underlying program.
+func (p *Program) GetProgram() *compiler.Program {
+	return p.program
+}
+
+func (p *Program) Options() *core.CompilerOptions {
+	return p.program.Options()
+}
+
+func (p *Program) GetConfigFileParsingDiagnostics() []*ast.Diagnostic {
+	return p.program.GetConfigFileParsingDiagnostics()
+}
+
+func (p *Program) GetSyntacticDiagnostics(sourceFile *ast.SourceFile) []*ast.Diagnostic {
+	return p.program.GetSyntacticDiagnostics(sourceFile)
+}
+
+func (p *Program) GetOptionsDiagnostics() []*ast.Diagnostic {
+	return p.program.GetOptionsDiagnostics()
+}
+
+func (p *Program) GetGlobalDiagnostics() []*ast.Diagnostic {
+	return p.program.GetGlobalDiagnostics()
+}
+
+// GetSemanticDiagnostics checks the files affected by changes, and replays the diagnostics of the other files from
+// the previous compilation.
+func (p *Program) GetSemanticDiagnostics(ctx context.Context, sourceFile *ast.SourceFile) []*ast.Diagnostic {
+	files := p.program.GetSourceFiles()
+	if sourceFile != nil {
+		files = []*ast.SourceFile{sourceFile}
+	}
+	var pending []*ast.SourceFile
+	for _, file := range files {
+		if _, ok := p.semanticDiagnosticsPerFile[file.Path()]; !ok {
+			pending = append(pending, file)
+		}
+	}
+	if len(pending) > 1 {
+		p.program.CheckSourceFilesSubset(ctx, pending)
+	}
+	for _, file := range pending {
+		diagnostics := p.program.GetSemanticDiagnostics(ctx, file)
+		if ctx.Err() != nil {
+			// The file was not fully checked, so its diagnostics must not be replayed.
+			return nil
+		}
+		p.semanticDiagnosticsPerFile[file.Path()] = diagnostics
+	}
+
+	var result []*ast.Diagnostic
+	for _, file := range files {
+		result = append(result, p.semanticDiagnosticsPerFile[file.Path()]...)
+	}
+	return compiler.SortAndDeduplicateDiagnostics(result)
+}
+
+func (p *Program) GetDeclarationDiagnostics(ctx context.Context, sourceFile *ast.SourceFile) []*ast.Diagnostic {
+	files := p.program.GetSourceFiles()
+	if sourceFile != nil {
+		files = []*ast.SourceFile{sourceFile}
+	}
+	var result []*ast.Diagnostic
+	for _, file := range files {
+		diagnostics, ok := p.emitDiagnosticsPerFile[file.Path()]
+		if !ok {
+			diagnostics = p.program.GetDeclarationDiagnostics(ctx, file)
+		}
+		result = append(result, diagnostics...)
+	}
+	if len(result) > 0 {
+		p.hasDeclarationErrors = true
+	}
+	return compiler.SortAndDeduplicateDiagnostics(result)
+}
+
+// Emit emits the files affected by changes since the last emit, and writes the .tsbuildinfo file. The diagnostics
+// of the result include those of the files emitted by earlier compilations.
+func (p *Program) Emit(options compiler.EmitOptions) *compiler.EmitResult {
+	result := &compiler.EmitResult{}
+	var skippedDiagnostics []*ast.Diagnostic
+	if !p.Options().NoEmit.IsTrue() {
+		var files []*ast.SourceFile
+		for _, file := range p.program.GetSourceFiles() {
+			if p.affectedFilesPendingEmit.Has(file.Path()) && (options.TargetSourceFile == nil || options.TargetSourceFile == file) {
+				files = append(files, file)
+			}
+		}
+
+		results := make([]*compiler.EmitResult, len(files))
+		wg := core.NewWorkGroup(p.program.SingleThreaded())
+		for i, file := range files {
+			wg.Queue(func() {
+				results[i] = p.program.Emit(compiler.EmitOptions{TargetSourceFile: file})
+			})
+		}
+		wg.RunAndWait()
+
+		for i, file := range files {
+			emitResult := results[i]
+			result.EmittedFiles = append(result.EmittedFiles, emitResult.EmittedFiles...)
+			result.SourceMaps = append(result.SourceMaps, emitResult.SourceMaps...)
+			if emitResult.EmitSkipped {
+				result.EmitSkipped = true
+				skippedDiagnostics = append(skippedDiagnostics, emitResult.Diagnostics...)
+				continue
+			}
+			p.emitDiagnosticsPerFile[file.Path()] = emitResult.Diagnostics
+			p.affectedFilesPendingEmit.Delete(file.Path())
+		}
+	}
+
+	for _, file := range p.program.GetSourceFiles() {
+		if options.TargetSourceFile == nil || options.TargetSourceFile == file {
+			result.Diagnostics = append(result.Diagnostics, p.emitDiagnosticsPerFile[file.Path()]...)
+		}
+	}
+	result.Diagnostics = append(result.Diagnostics, skippedDiagnostics...)
+
+	if p.buildInfoFileName != "" {
+		p.computeMissingSignatures()
+		if err := p.writeBuildInfo(result); err != nil {
+			result.Diagnostics = append(result.Diagnostics, ast.NewCompilerDiagnostic(diagnostics.Could_not_write_file_0_Colon_1, p.buildInfoFileName, err.Error()))
+		}
+	}
+	return result
+}
+
+// computeMissingSignatures computes the signatures of the files that have none yet, such as all files of a first
+// compilation, so that the next compilation can tell whether a change to one of them changes its declarations.
+func (p *Program) computeMissingSignatures() {
+	wg := core.NewWorkGroup(p.program.SingleThreaded())
+	for _, file := range p.program.GetSourceFiles() {
+		info := p.fileInfos[file.Path()]
+		if info.signature != "" || file.IsDeclarationFile || !tspath.HasTSFileExtension(file.FileName()) {
+			continue
+		}
+		wg.Queue(func() {
+			info.signature = ComputeHash(p.program.GetDeclarationText(file))
+		})
+	}
+	wg.RunAndWait()
+}
+
+func (p *Program) writeBuildInfo(emitResult *compiler.EmitResult) error {
+	data, err := json.Marshal(p.toBuildInfo(emitResult))
+	if err != nil {
+		return err
+	}
+	return p.program.Host().FS().WriteFile(p.buildInfoFileName, string(data), false /*writeByteOrderMark*/)
+}
+
+func (p *Program) toBuildInfo(emitResult *compiler.EmitResult) *BuildInfo {
+	buildInfoDirectory := tspath.GetDirectoryPath(p.buildInfoFileName)
+	comparePathsOptions := tspath.ComparePathsOptions{
+		UseCaseSensitiveFileNames: p.program.Host().FS().UseCaseSensitiveFileNames(),
+		CurrentDirectory:          buildInfoDirectory,
+	}
+	relativeFileName := func(fileName string) string {
+		return tspath.GetRelativePathFromDirectory(buildInfoDirectory, fileName, comparePathsOptions)
+	}
+
+	files := p.program.GetSourceFiles()
+	fileIds := make(map[tspath.Path]BuildInfoFileId, len(files))
+	buildInfo := &BuildInfo{
+		Version: core.Version,
+		Root:    core.Map(p.program.GetRootFileNames(), relativeFileName),
+		Options: getBuildInfoOptions(p.Options(), buildInfoDirectory),
+	}
+	for i, file := range files {
+		fileIds[file.Path()] = BuildInfoFileId(i + 1)
+		info := p.fileInfos[file.Path()]
+		buildInfo.FileNames = append(buildInfo.FileNames, relativeFileName(file.FileName()))
+		buildInfo.FileInfos = append(buildInfo.FileInfos, &BuildInfoFileInfo{
+			Version:            info.version,
+			Signature:          info.signature,
+			AffectsGlobalScope: info.affectsGlobalScope,
+		})
+	}
+
+	for _, file := range files {
+		id := fileIds[file.Path()]
+		if references := p.referencedMap[file.Path()]; len(references) > 0 {
+			ids := core.Map(references, func(path tspath.Path) BuildInfoFileId { return fileIds[path] })
+			slices.Sort(ids)
+			buildInfo.ReferencedMap = append(buildInfo.ReferencedMap, &BuildInfoReferencedFiles{File: id, References: ids})
+		}
+		if diagnostics, ok := p.semanticDiagnosticsPerFile[file.Path()]; !ok {
+			buildInfo.CheckPending = append(buildInfo.CheckPending, id)
+		} else if len(diagnostics) > 0 {
+			buildInfo.SemanticDiagnosticsPerFile = append(buildInfo.SemanticDiagnosticsPerFile, &BuildInfoDiagnosticsOfFile{
+				File:        id,
+				Diagnostics: toBuildInfoDiagnostics(diagnostics, file, fileIds),
+			})
+		}
+		if p.affectedFilesPendingEmit.Has(file.Path()) {
+			buildInfo.AffectedFilesPendingEmit = append(buildInfo.AffectedFilesPendingEmit, id)
+		} else if diagnostics := p.emitDiagnosticsPerFile[file.Path()]; len(diagnostics) > 0 {
+			buildInfo.EmitDiagnosticsPerFile = append(buildInfo.EmitDiagnosticsPerFile, &BuildInfoDiagnosticsOfFile{
+				File:        id,
+				Diagnostics: toBuildInfoDiagnostics(diagnostics, file, fileIds),
+			})
+		}
+	}
+
+	buildInfo.Errors = len(buildInfo.CheckPending) > 0 ||
+		len(buildInfo.SemanticDiagnosticsPerFile) > 0 ||
+		len(emitResult.Diagnostics) > 0 ||
+		p.hasDeclarationErrors ||
+		len(p.program.GetConfigFileParsingDiagnostics()) > 0 ||
+		len(p.program.GetSyntacticDiagnostics(nil)) > 0 ||
+		len(p.program.GetOptionsDiagnostics()) > 0
+	return buildInfo
+}
+
+func toBuildInfoDiagnostics(diagnostics []*ast.Diagnostic, file *ast.SourceFile, fileIds map[tspath.Path]BuildInfoFileId) []*BuildInfoDiagnostic {
+	return core.Map(diagnostics, func(diagnostic *ast.Diagnostic) *BuildInfoDiagnostic {
+		result := &BuildInfoDiagnostic{
+			Pos:                diagnostic.Pos(),
+			End:                diagnostic.End(),
+			Code:               diagnostic.Code(),
+			Category:           diagnostic.Category(),
+			Message:            diagnostic.Message(),
+			MessageChain:       toBuildInfoDiagnostics(diagnostic.MessageChain(), file, fileIds),
+			RelatedInformation: toBuildInfoDiagnostics(diagnostic.RelatedInformation(), file, fileIds),
+		}
+		if diagnostic.File() == nil {
+			result.NoFile = true
+		} else if diagnostic.File() != file {
+			result.File = fileIds[diagnostic.File().Path()]
+		}
+		return result
+	})
+}
+
+func (p *Program) fromBuildInfoDiagnostics(old *oldState, diagnostics []*BuildInfoDiagnostic, file *ast.SourceFile) []*ast.Diagnostic {
+	if diagnostics == nil {
+		return []*ast.Diagnostic{}
+	}
+	return core.Map(diagnostics, func(diagnostic *BuildInfoDiagnostic) *ast.Diagnostic {
+		diagnosticFile := file
+		if diagnostic.NoFile {
+			diagnosticFile = nil
+		} else if diagnostic.File >= 1 && int(diagnostic.File) <= len(old.paths) {
+			diagnosticFile = p.program.GetSourceFileByPath(old.paths[diagnostic.File-1])
+		}
+		result := ast.NewDiagnosticFromMessage(diagnosticFile, core.NewTextRange(diagnostic.Pos, diagnostic.End), diagnostic.Code, diagnostic.Category, diagnostic.Message)
+		if len(diagnostic.MessageChain) > 0 {
+			result.SetMessageChain(p.fromBuildInfoDiagnostics(old, diagnostic.MessageChain, file))
+		}
+		if len(diagnostic.RelatedInformation) > 0 {
+			result.SetRelatedInfo(p.fromBuildInfoDiagnostics(old, diagnostic.RelatedInformation, file))
+		}
+		return result
+	})
+}
+
+// fileAffectsGlobalScope reports whether changes to the file may affect any file of the program, rather than only
+// the files that reference it.
+func fileAffectsGlobalScope(file *ast.SourceFile) bool {
+	if core.Some(file.ModuleAugmentations, func(name *ast.ModuleName) bool { return ast.IsGlobalScopeAugmentation(name.Parent) }) {
+		return true
+	}
+	if ast.IsExternalOrCommonJSModule(file) || ast.IsJsonSourceFile(file) {
+		return false
+	}
+	// A script with only ambient module declarations adds nothing to the global scope
+	return !core.Every(file.Statements.Nodes, func(statement *ast.Node) bool {
+		return ast.IsModuleDeclaration(statement) && statement.AsModuleDeclaration().Name().Kind == ast.KindStringLiteral
+	})
+}
+
+func mayBeEmitted(file *ast.SourceFile) bool {
+	return !file.IsDeclarationFile && !ast.IsJsonSourceFile(file)
+}
+
+func samePaths(a []tspath.Path, b []tspath.Path) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, path := range a {
+		if !slices.Contains(b, path) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,258 @@
+package incremental
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/microsoft/typescript-go/internal/bundled"
+	"github.com/microsoft/typescript-go/internal/compiler"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/tspath"
+	"github.com/microsoft/typescript-go/internal/vfs"
+	"github.com/microsoft/typescript-go/internal/vfs/vfstest"
+	"gotest.tools/v3/assert"
+)
+
+const (
+	buildInfoFileName = "/home/src/project/dist/tsconfig.tsbuildinfo"
+	aFileName         = "/home/src/project/a.ts"
+	bFileName         = "/home/src/project/b.ts"
+	cFileName         = "/home/src/project/c.ts"
+	globalsFileName   = "/home/src/project/globals.ts"
+)
+
+// The files form a chain of imports, c -> b -> a, and b re-exports the type of a, so that a change to the
+// declarations of a changes the declarations of b as well.
+var testFiles = map[string]string{
+	aFileName:       `export function a(): number { return 1; }`,
+	bFileName:       `import { a } from "./a"; export const b = a();`,
+	cFileName:       `import { b } from "./b"; export const c: string = b;`,
+	globalsFileName: `declare var version: string;`,
+}
+
+func newTestFS(t *testing.T) vfs.FS {
+	t.Helper()
+	if !bundled.Embedded {
+		// Without embedding, we'd need to read all of the lib files out from disk into the MapFS.
+		// Just skip this for now.
+		t.Skip("bundled files are not embedded")
+	}
+	fs := vfstest.FromMap[any](nil, true /*useCaseSensitiveFileNames*/)
+	for fileName, text := range testFiles {
+		assert.NilError(t, fs.WriteFile(fileName, text, false))
+	}
+	return bundled.WrapFS(fs)
+}
+
+func newTestProgram(fs vfs.FS, options *core.CompilerOptions, oldBuildInfo *BuildInfo) *Program {
+	options.Incremental = core.TSTrue
+	options.TsBuildInfoFile = buildInfoFileName
+	options.OutDir = "/home/src/project/dist"
+	options.SkipLibCheck = core.TSTrue
+	program := compiler.NewProgram(compiler.ProgramOptions{
+		RootFiles: []string{aFileName, bFileName, cFileName, globalsFileName},
+		Options:   options,
+		Host:      compiler.NewCompilerHost(options, "/home/src/project", fs, bundled.LibPath()),
+	})
+	return NewProgram(program, oldBuildInfo)
+}
+
+// build checks and emits the program like tsc does, which writes the .tsbuildinfo file.
+func build(p *Program) []string {
+	var messages []string
+	for _, diagnostic := range p.GetSemanticDiagnostics(context.Background(), nil) {
+		messages = append(messages, tspath.GetBaseFileName(diagnostic.File().FileName())+": "+diagnostic.Message())
+	}
+	p.Emit(compiler.EmitOptions{})
+	return messages
+}
+
+// filesToCheck returns the files whose diagnostics are not replayed from the build info.
+func filesToCheck(p *Program) []string {
+	var fileNames []string
+	for _, fileName := range []string{aFileName, bFileName, cFileName, globalsFileName} {
+		if _, ok := p.semanticDiagnosticsPerFile[tspath.Path(fileName)]; !ok {
+			fileNames = append(fileNames, tspath.GetBaseFileName(fileName))
+		}
+	}
+	return fileNames
+}
+
+func pendingEmitFiles(p *Program) []string {
+	var fileNames []string
+	for path := range p.affectedFilesPendingEmit.Keys() {
+		fileNames = append(fileNames, tspath.GetBaseFileName(string(path)))
+	}
+	slices.Sort(fileNames)
+	return fileNames
+}
+
+func TestBuildInfo(t *testing.T) {
+	t.Parallel()
+	fs := newTestFS(t)
+	p := newTestProgram(fs, &core.CompilerOptions{}, nil /*oldBuildInfo*/)
+	diagnostics := build(p)
+	assert.DeepEqual(t, diagnostics, []string{"c.ts: Type 'number' is not assignable to type 'string'."})
+
+	buildInfo := ReadBuildInfo(fs, buildInfoFileName)
+	assert.Assert(t, buildInfo != nil)
+	assert.Assert(t, buildInfo.IsValidVersion())
+	assert.Assert(t, buildInfo.Errors)
+	assert.DeepEqual(t, buildInfo.Root, []string{"../a.ts", "../b.ts", "../c.ts", "../globals.ts"})
+	assert.Assert(t, !buildInfo.HasChangedOptions(p.Options(), buildInfoFileName))
+	changedOptions := *p.Options()
+	changedOptions.NoImplicitAny = core.TSFalse
+	assert.Assert(t, buildInfo.HasChangedOptions(&changedOptions, buildInfoFileName))
+
+	id := func(fileName string) BuildInfoFileId {
+		index := slices.Index(buildInfo.FileNames, tspath.GetRelativePathFromDirectory("/home/src/project/dist", fileName, tspath.ComparePathsOptions{}))
+		assert.Assert(t, index >= 0, fileName)
+		return BuildInfoFileId(index + 1)
+	}
+	info := func(fileName string) *BuildInfoFileInfo {
+		return buildInfo.FileInfos[id(fileName)-1]
+	}
+
+	assert.Equal(t, info(aFileName).Version, ComputeHash(testFiles[aFileName]))
+	assert.Assert(t, info(aFileName).Signature != "")
+	assert.Assert(t, !info(aFileName).AffectsGlobalScope)
+	assert.Assert(t, info(globalsFileName).AffectsGlobalScope)
+
+	references := func(fileName string) []BuildInfoFileId {
+		for _, referenced := range buildInfo.ReferencedMap {
+			if referenced.File == id(fileName) {
+				return referenced.References
+			}
+		}
+		return nil
+	}
+	assert.DeepEqual(t, references(aFileName), []BuildInfoFileId(nil))
+	assert.DeepEqual(t, references(bFileName), []BuildInfoFileId{id(aFileName)})
+	assert.DeepEqual(t, references(cFileName), []BuildInfoFileId{id(bFileName)})
+	assert.Equal(t, len(buildInfo.SemanticDiagnosticsPerFile), 1)
+	assert.Equal(t, buildInfo.SemanticDiagnosticsPerFile[0].File, id(cFileName))
+	assert.Equal(t, buildInfo.SemanticDiagnosticsPerFile[0].Diagnostics[0].Code, int32(2322))
+	assert.Equal(t, len(buildInfo.CheckPending), 0)
+	assert.Equal(t, len(buildInfo.AffectedFilesPendingEmit), 0)
+}
+
+func TestAffectedFiles(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		title string
+		// The new text of each changed file
+		changes map[string]string
+		options *core.CompilerOptions
+		check   []string
+		emit    []string
+	}{
+		{
+			title: "no change",
+			check: nil,
+			emit:  nil,
+		},
+		{
+			title:   "change that keeps the declarations",
+			changes: map[string]string{aFileName: `export function a(): number { return 2; }`},
+			check:   []string{"a.ts"},
+			emit:    []string{"a.ts"},
+		},
+		{
+			title:   "change to the declarations of a dependency",
+			changes: map[string]string{aFileName: `export function a(): string { return ""; }`},
+			check:   []string{"a.ts", "b.ts", "c.ts"},
+			emit:    []string{"a.ts", "b.ts", "c.ts"},
+		},
+		{
+			title:   "change to the declarations of a file that is only referenced directly",
+			changes: map[string]string{bFileName: `import { a } from "./a"; export const b = a(); export const d = 1;`},
+			check:   []string{"b.ts", "c.ts"},
+			emit:    []string{"b.ts", "c.ts"},
+		},
+		{
+			title:   "change to a file that affects the global scope",
+			changes: map[string]string{globalsFileName: `declare var version: number;`},
+			check:   []string{"a.ts", "b.ts", "c.ts", "globals.ts"},
+			emit:    []string{"a.ts", "b.ts", "c.ts", "globals.ts"},
+		},
+		{
+			title:   "change to the imports of a file",
+			changes: map[string]string{cFileName: `import { b } from "./b"; import { a } from "./a"; export const c: string = b;`},
+			check:   []string{"c.ts"},
+			emit:    []string{"c.ts"},
+		},
+		{
+			title:   "change to an option that affects semantic diagnostics",
+			options: &core.CompilerOptions{NoImplicitAny: core.TSFalse},
+			check:   []string{"a.ts", "b.ts", "c.ts", "globals.ts"},
+			emit:    nil,
+		},
+		{
+			title:   "change to an option that affects emit",
+			options: &core.CompilerOptions{Declaration: core.TSTrue},
+			check:   nil,
+			emit:    []string{"a.ts", "b.ts", "c.ts", "globals.ts"},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.title, func(t *testing.T) {
+			t.Parallel()
+			fs := newTestFS(t)
+			build(newTestProgram(fs, &core.CompilerOptions{}, ReadBuildInfo(fs, buildInfoFileName)))
+
+			for fileName, text := range testCase.changes {
+				assert.NilError(t, fs.WriteFile(fileName, text, false))
+			}
+			options := testCase.options
+			if options == nil {
+				options = &core.CompilerOptions{}
+			}
+			p := newTestProgram(fs, options, ReadBuildInfo(fs, buildInfoFileName))
+			assert.DeepEqual(t, filesToCheck(p), testCase.check)
+			assert.DeepEqual(t, pendingEmitFiles(p), testCase.emit)
+		})
+	}
+}
+
+func TestReplayedDiagnostics(t *testing.T) {
+	t.Parallel()
+	fs := newTestFS(t)
+	first := build(newTestProgram(fs, &core.CompilerOptions{}, ReadBuildInfo(fs, buildInfoFileName)))
+
+	// c is not checked again, so its diagnostics come from the build info, with the same positions
+	p := newTestProgram(fs, &core.CompilerOptions{}, ReadBuildInfo(fs, buildInfoFileName))
+	replayed, ok := p.semanticDiagnosticsPerFile[tspath.Path(cFileName)]
+	assert.Assert(t, ok)
+	assert.Equal(t, len(replayed), 1)
+	assert.Equal(t, replayed[0].Code(), int32(2322))
+	assert.Equal(t, replayed[0].File().FileName(), cFileName)
+	assert.Equal(t, replayed[0].Pos(), len(`import { b } from "./b"; export const `))
+	assert.DeepEqual(t, build(p), first)
+
+	// Fixing the error is picked up, and the fixed state is written back
+	assert.NilError(t, fs.WriteFile(cFileName, `import { b } from "./b"; export const c: number = b;`, false))
+	assert.Equal(t, len(build(newTestProgram(fs, &core.CompilerOptions{}, ReadBuildInfo(fs, buildInfoFileName)))), 0)
+	buildInfo := ReadBuildInfo(fs, buildInfoFileName)
+	assert.Equal(t, len(buildInfo.SemanticDiagnosticsPerFile), 0)
+	assert.Assert(t, !buildInfo.Errors)
+}
+
+func TestInvalidBuildInfo(t *testing.T) {
+	t.Parallel()
+	fs := newTestFS(t)
+	build(newTestProgram(fs, &core.CompilerOptions{}, ReadBuildInfo(fs, buildInfoFileName)))
+
+	// A build info from another version of the compiler is ignored, so every file is checked and emitted again
+	buildInfo := ReadBuildInfo(fs, buildInfoFileName)
+	buildInfo.Version = "0.0.0"
+	p := newTestProgram(fs, &core.CompilerOptions{}, buildInfo)
+	assert.DeepEqual(t, filesToCheck(p), []string{"a.ts", "b.ts", "c.ts", "globals.ts"})
+	assert.DeepEqual(t, pendingEmitFiles(p), []string{"a.ts", "b.ts", "c.ts", "globals.ts"})
+
+	// So is a build info that cannot be parsed
+	assert.NilError(t, fs.WriteFile(buildInfoFileName, "{", false))
+	assert.Assert(t, ReadBuildInfo(fs, buildInfoFileName) == nil)
+}
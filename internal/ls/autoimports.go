@@ -0,0 +1,299 @@
+package ls
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/checker"
+	"github.com/microsoft/typescript-go/internal/compiler"
+	"github.com/microsoft/typescript-go/internal/compiler/module"
+	"github.com/microsoft/typescript-go/internal/compiler/packagejson"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/modulespecifiers"
+	"github.com/microsoft/typescript-go/internal/tspath"
+)
+
+// canAddImports reports whether imports can be added to a file. Adding one turns a script into a
+// module, which is only done when the options indicate that the program is written in modules.
+func canAddImports(program *compiler.Program, file *ast.SourceFile) bool {
+	if ast.IsExternalModule(file) {
+		return true
+	}
+	options := program.Options()
+	return options.ModuleKind != core.ModuleKindNone || options.GetEmitScriptTarget() >= core.ScriptTargetES2015 || options.NoEmit.IsTrue()
+}
+
+type exportKind int
+
+const (
+	exportKindNamed exportKind = iota
+	exportKindDefault
+	exportKindExportEquals
+)
+
+// exportInfo is an export of a module that another module can import.
+type exportInfo struct {
+	// name is the name that the export is imported by. Default exports are named after their
+	// declaration, or after their module if it has no name.
+	name   string
+	kind   exportKind
+	symbol *ast.Symbol
+	// moduleFileName is the file of the module, or "" for an ambient module.
+	moduleFileName string
+	// ambientModuleName is the name that an ambient module is declared and imported by.
+	ambientModuleName string
+}
+
+// exportInfoMap lists the exports of the modules of a program, for the imports that completions
+// and the missing import quick fix add. Like symbolIndex, the exports of a file are kept for as
+// long as the file is unchanged, except for files with export * declarations, whose exports
+// depend on other files.
+type exportInfoMap struct {
+	program  *compiler.Program
+	files    map[*ast.SourceFile][]exportInfo
+	ambient  []exportInfo
+	host     *moduleSpecifierHost
+	symlinks *modulespecifiers.SymlinkCache
+
+	specifiersMu sync.Mutex
+	specifiers   map[moduleSpecifierKey][]string
+}
+
+type moduleSpecifierKey struct {
+	importingFile  tspath.Path
+	moduleFileName string
+	preferences    modulespecifiers.UserPreferences
+}
+
+func (l *LanguageService) getExportInfoMap() *exportInfoMap {
+	program := l.GetProgram()
+	l.exportInfoMu.Lock()
+	defer l.exportInfoMu.Unlock()
+	if l.exportInfo != nil && l.exportInfo.program == program {
+		return l.exportInfo
+	}
+
+	c := l.GetTypeChecker()
+	exports := &exportInfoMap{
+		program:    program,
+		files:      make(map[*ast.SourceFile][]exportInfo),
+		specifiers: make(map[moduleSpecifierKey][]string),
+	}
+	exports.host = &moduleSpecifierHost{program: program, exports: exports}
+	for _, file := range program.SourceFiles() {
+		if program.IsSourceFileDefaultLibrary(file) || !ast.IsExternalModule(file) || file.AsNode().Symbol() == nil {
+			continue
+		}
+		entries, ok := l.exportInfo.getFileEntries(file)
+		if !ok {
+			entries = getFileExportInfo(c, file)
+		}
+		exports.files[file] = entries
+	}
+	for _, moduleSymbol := range c.GetAmbientModules() {
+		name := moduleSymbol.Name[1 : len(moduleSymbol.Name)-1]
+		if tspath.IsExternalModuleNameRelative(name) {
+			continue
+		}
+		exports.ambient = append(exports.ambient, getModuleExportInfo(c.GetExportsOfModule(moduleSymbol), "", name)...)
+	}
+	l.exportInfo = exports
+	return exports
+}
+
+func (exports *exportInfoMap) getFileEntries(file *ast.SourceFile) ([]exportInfo, bool) {
+	if exports == nil || hasExportStar(file) {
+		return nil, false
+	}
+	entries, ok := exports.files[file]
+	return entries, ok
+}
+
+func hasExportStar(file *ast.SourceFile) bool {
+	_, ok := file.AsNode().Symbol().Exports[ast.InternalSymbolNameExportStar]
+	return ok
+}
+
+// getFileExportInfo lists the exports of a module file. They are the bound exports of the file,
+// unless export * declarations add the exports of other modules.
+func getFileExportInfo(c *checker.Checker, file *ast.SourceFile) []exportInfo {
+	moduleSymbol := file.AsNode().Symbol()
+	var symbols []*ast.Symbol
+	if hasExportStar(file) {
+		symbols = c.GetExportsOfModule(moduleSymbol)
+	} else {
+		symbols = slices.Collect(maps.Values(moduleSymbol.Exports))
+	}
+	return getModuleExportInfo(symbols, file.FileName(), "")
+}
+
+func getModuleExportInfo(symbols []*ast.Symbol, moduleFileName string, ambientModuleName string) []exportInfo {
+	moduleName := core.OrElse(ambientModuleName, moduleFileName)
+	entries := make([]exportInfo, 0, len(symbols))
+	for _, symbol := range symbols {
+		entry := exportInfo{symbol: symbol, moduleFileName: moduleFileName, ambientModuleName: ambientModuleName}
+		switch symbol.Name {
+		case ast.InternalSymbolNameDefault:
+			entry.kind = exportKindDefault
+			entry.name = getDefaultExportName(symbol, moduleName)
+		case ast.InternalSymbolNameExportEquals:
+			entry.kind = exportKindExportEquals
+			entry.name = getDefaultExportName(symbol, moduleName)
+		default:
+			if strings.HasPrefix(symbol.Name, ast.InternalSymbolNamePrefix) {
+				continue
+			}
+			entry.kind = exportKindNamed
+			entry.name = symbol.Name
+		}
+		if entry.name != "" {
+			entries = append(entries, entry)
+		}
+	}
+	slices.SortFunc(entries, func(a, b exportInfo) int {
+		return cmp.Or(strings.Compare(a.name, b.name), cmp.Compare(a.kind, b.kind))
+	})
+	return entries
+}
+
+// getDefaultExportName returns the name of the declaration of a default export, or else a name
+// derived from its module, as in fooBar for foo-bar.ts or foo-bar/index.ts.
+func getDefaultExportName(symbol *ast.Symbol, moduleName string) string {
+	for _, decl := range symbol.Declarations {
+		if ast.IsExportAssignment(decl) {
+			if expression := decl.Expression(); ast.IsIdentifier(expression) {
+				return expression.Text()
+			}
+			continue
+		}
+		if name := ast.GetNameOfDeclaration(decl); name != nil && ast.IsIdentifier(name) {
+			return name.Text()
+		}
+	}
+	base := tspath.GetBaseFileName(tspath.RemoveFileExtension(moduleName))
+	if base == "index" {
+		base = tspath.GetBaseFileName(tspath.GetDirectoryPath(moduleName))
+	}
+	var b strings.Builder
+	upper := false
+	for _, ch := range base {
+		if !unicode.IsLetter(ch) && !unicode.IsDigit(ch) && ch != '_' && ch != '$' {
+			upper = b.Len() > 0
+			continue
+		}
+		if b.Len() == 0 && unicode.IsDigit(ch) {
+			b.WriteByte('_')
+		}
+		if upper {
+			ch = unicode.ToUpper(ch)
+			upper = false
+		}
+		b.WriteRune(ch)
+	}
+	return b.String()
+}
+
+// forEachExport calls f with the exports that a file can import from other modules.
+func (exports *exportInfoMap) forEachExport(importingFile *ast.SourceFile, f func(info *exportInfo)) {
+	for _, file := range exports.program.SourceFiles() {
+		entries := exports.files[file]
+		if file == importingFile {
+			continue
+		}
+		for i := range entries {
+			f(&entries[i])
+		}
+	}
+	for i := range exports.ambient {
+		f(&exports.ambient[i])
+	}
+}
+
+// getImportCandidate returns how a file imports an export, with the best specifier of its
+// module. It returns false if the file cannot import the module.
+func (exports *exportInfoMap) getImportCandidate(importingFile *ast.SourceFile, info *exportInfo, preferences *UserPreferences) (importCandidate, bool) {
+	if info.kind == exportKindExportEquals && !exports.program.Options().GetAllowSyntheticDefaultImports() {
+		return importCandidate{}, false
+	}
+	specifier := info.ambientModuleName
+	if specifier == "" {
+		specifiers := exports.getModuleSpecifiers(importingFile, info.moduleFileName, preferences.moduleSpecifierPreferences())
+		if len(specifiers) == 0 {
+			return importCandidate{}, false
+		}
+		specifier = specifiers[0]
+	}
+	return importCandidate{moduleSpecifier: specifier, isDefault: info.kind != exportKindNamed}, true
+}
+
+func (exports *exportInfoMap) getModuleSpecifiers(importingFile *ast.SourceFile, moduleFileName string, preferences modulespecifiers.UserPreferences) []string {
+	key := moduleSpecifierKey{importingFile: importingFile.Path(), moduleFileName: moduleFileName, preferences: preferences}
+	exports.specifiersMu.Lock()
+	defer exports.specifiersMu.Unlock()
+	if specifiers, ok := exports.specifiers[key]; ok {
+		return specifiers
+	}
+	program := exports.program
+	specifiers := modulespecifiers.GetModuleSpecifiers(
+		importingFile,
+		program.GetImpliedNodeFormatForEmit(importingFile),
+		moduleFileName,
+		program.Options(),
+		exports.host,
+		preferences,
+	)
+	exports.specifiers[key] = specifiers
+	return specifiers
+}
+
+// moduleSpecifierHost answers the questions of module specifier generation from a program.
+type moduleSpecifierHost struct {
+	program *compiler.Program
+	exports *exportInfoMap
+}
+
+var _ modulespecifiers.Host = (*moduleSpecifierHost)(nil)
+
+func (h *moduleSpecifierHost) GetCurrentDirectory() string {
+	return h.program.Host().GetCurrentDirectory()
+}
+
+func (h *moduleSpecifierHost) UseCaseSensitiveFileNames() bool {
+	return h.program.Host().FS().UseCaseSensitiveFileNames()
+}
+
+func (h *moduleSpecifierHost) FileExists(fileName string) bool {
+	return h.program.Host().FS().FileExists(fileName)
+}
+
+func (h *moduleSpecifierHost) GetPackageJsonInfo(packageDirectory string) *packagejson.InfoCacheEntry {
+	return h.program.ModuleResolver().GetPackageJsonInfo(packageDirectory)
+}
+
+func (h *moduleSpecifierHost) GetPackageScopeForPath(directory string) *packagejson.InfoCacheEntry {
+	return h.program.ModuleResolver().GetPackageScopeForPath(directory)
+}
+
+func (h *moduleSpecifierHost) GetCommonSourceDirectory() string {
+	return h.program.CommonSourceDirectory()
+}
+
+// GetSymlinkCache returns the symlinked directories that the module resolutions of the program
+// went through. It is called with the specifiers lock of the export info map held.
+func (h *moduleSpecifierHost) GetSymlinkCache() *modulespecifiers.SymlinkCache {
+	if h.exports.symlinks == nil {
+		symlinks := modulespecifiers.NewSymlinkCache(h.UseCaseSensitiveFileNames())
+		h.program.ForEachResolvedModule(func(resolved *module.ResolvedModule) {
+			if resolved != nil && resolved.IsResolved() {
+				symlinks.SetSymlinkedDirectoryFromResolution(resolved.ResolvedFileName, resolved.OriginalPath)
+			}
+		})
+		h.exports.symlinks = symlinks
+	}
+	return h.exports.symlinks
+}
@@ -0,0 +1,152 @@
+package ls_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/ls"
+	"gotest.tools/v3/assert"
+)
+
+// autoImportCompletions returns the auto-import completions at a marker as "name from specifier",
+// sorted.
+func autoImportCompletions(t *testing.T, p *testProject, markerName string) []string {
+	t.Helper()
+	l, m := p.languageServiceAt(markerName)
+	list := l.ProvideCompletions(m.fileName, m.position, nil)
+	assert.Assert(t, list != nil)
+	var result []string
+	for _, item := range list.Items {
+		data, err := ls.GetCompletionItemData(&item)
+		if err != nil || data.AutoImport == nil {
+			continue
+		}
+		result = append(result, item.Label+" from "+data.AutoImport.ModuleSpecifier)
+	}
+	slices.Sort(result)
+	return result
+}
+
+func TestAutoImports(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		title    string
+		files    map[string]string
+		expected []string
+	}{
+		{
+			title: "named exports matching the prefix",
+			files: map[string]string{
+				"/home/src/project/index.ts": `import { parseDate } from "./dates";
+export const parseLocal = 1;
+parse/*1*/`,
+				"/home/src/project/dates.ts":  `export function parseDate() {} export function formatDate() {}`,
+				"/home/src/project/parser.ts": `export class Parser {} export const pArSe_json = 1;`,
+			},
+			// Names in scope and the exports of the file itself are not offered
+			expected: []string{"Parser from ./parser", "pArSe_json from ./parser"},
+		},
+		{
+			title: "default exports",
+			files: map[string]string{
+				"/home/src/project/index.ts":           `export {}; w/*1*/`,
+				"/home/src/project/widget.ts":          `export default class Widget {}`,
+				"/home/src/project/wide-screen.ts":     `export default 16 / 9;`,
+				"/home/src/project/widgets/index.ts":   `export default [];`,
+				"/home/src/project/window-manager.ts":  `const windows = {}; export = windows;`,
+				"/home/src/project/wrapper/wrapper.ts": `export default function () {}`,
+			},
+			// Anonymous defaults are named after their module, or their directory for index files
+			expected: []string{"Widget from ./widget", "wideScreen from ./wide-screen", "widgets from ./widgets", "windows from ./window-manager", "wrapper from ./wrapper/wrapper"},
+		},
+		{
+			title: "re-exports",
+			files: map[string]string{
+				"/home/src/project/index.ts":       `export {}; helper/*1*/`,
+				"/home/src/project/lib/index.ts":   `export * from "./helpers";`,
+				"/home/src/project/lib/helpers.ts": `export function helperA() {}`,
+			},
+			expected: []string{"helperA from ./lib", "helperA from ./lib/helpers"},
+		},
+		{
+			title: "ambient modules and packages",
+			files: map[string]string{
+				"/home/src/project/index.ts":                         `export {}; ch/*1*/`,
+				"/home/src/project/types.d.ts":                       `declare module "channels" { export class Channel {} }`,
+				"/home/src/project/node_modules/lodash/package.json": `{ "name": "lodash", "types": "index.d.ts" }`,
+				"/home/src/project/node_modules/lodash/index.d.ts":   `export declare function chunk(): void;`,
+				"/home/src/project/uses-lodash.ts":                   `import "lodash";`,
+			},
+			expected: []string{"Channel from channels", "chunk from lodash"},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.title, func(t *testing.T) {
+			t.Parallel()
+			files := map[string]string{"/home/src/project/tsconfig.json": completionsConfig}
+			for fileName, text := range testCase.files {
+				files[fileName] = text
+			}
+			p := newTestProject(t, files)
+			assert.DeepEqual(t, autoImportCompletions(t, p, "1"), testCase.expected)
+		})
+	}
+
+	t.Run("not in a script that cannot become a module", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/tsconfig.json": `{ "compilerOptions": { "module": "none", "target": "es5" } }`,
+			"/home/src/project/index.ts":      `parse/*1*/`,
+			"/home/src/project/dates.ts":      `export function parseDate() {}`,
+		})
+		assert.Assert(t, autoImportCompletions(t, p, "1") == nil)
+	})
+
+	t.Run("exports are updated when a file changes", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/tsconfig.json": completionsConfig,
+			"/home/src/project/index.ts":      `export {}; parse/*1*/`,
+			"/home/src/project/dates.ts":      `export function parseDate() {}`,
+		})
+		assert.DeepEqual(t, autoImportCompletions(t, p, "1"), []string{"parseDate from ./dates"})
+
+		p.languageService("/home/src/project/dates.ts")
+		end := len(p.files["/home/src/project/dates.ts"])
+		p.service.ChangeFile("/home/src/project/dates.ts", []ls.TextChange{{
+			TextRange: core.NewTextRange(end, end),
+			NewText:   "\nexport function parseTime() {}",
+		}})
+		assert.DeepEqual(t, autoImportCompletions(t, p, "1"), []string{"parseDate from ./dates", "parseTime from ./dates"})
+	})
+
+	t.Run("candidates of the missing import fix", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/tsconfig.json":                 completionsConfig,
+			"/home/src/project/src/index.ts":                  `Channel;`,
+			"/home/src/project/src/channel.ts":                `export class Channel {}`,
+			"/home/src/project/src/nested/channel.ts":         `export class Channel {}`,
+			"/home/src/project/lib/channel.ts":                `export class Channel {}`,
+			"/home/src/project/node_modules/pkg/package.json": `{ "name": "pkg", "types": "index.d.ts" }`,
+			"/home/src/project/node_modules/pkg/index.d.ts":   `export declare class Channel {}`,
+			"/home/src/project/src/uses-pkg.ts":               `import "pkg";`,
+		})
+		l := p.languageService("/home/src/project/src/index.ts")
+		fixes := l.ProvideCodeFixes("/home/src/project/src/index.ts", []ls.CodeFixDiagnostic{{
+			Code: diagnostics.Cannot_find_name_0.Code(),
+			Span: core.NewTextRange(0, len("Channel")),
+		}})
+		// Packages come first, then shorter relative paths, then paths into parent directories
+		assert.DeepEqual(t, core.Map(fixes, func(fix ls.CodeFix) string { return fix.Title }), []string{
+			`Add import from "pkg"`,
+			`Add import from "./channel"`,
+			`Add import from "./nested/channel"`,
+			`Add import from "../lib/channel"`,
+		})
+	})
+}
@@ -0,0 +1,448 @@
+package ls
+
+import (
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/astnav"
+	"github.com/microsoft/typescript-go/internal/checker"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"github.com/microsoft/typescript-go/internal/scanner"
+	"github.com/microsoft/typescript-go/internal/tspath"
+)
+
+// HierarchyItem is a declaration in a call or type hierarchy. Requests for the callers, callees,
+// supertypes or subtypes of an item find it again by the start of its SelectionRange.
+type HierarchyItem struct {
+	Name           string
+	Kind           lsproto.SymbolKind
+	Detail         string
+	FileName       string
+	Range          core.TextRange
+	SelectionRange core.TextRange
+}
+
+// CallHierarchyIncomingCall is a declaration that calls an item, with the ranges of the calls,
+// which are in the file of From.
+type CallHierarchyIncomingCall struct {
+	From       HierarchyItem
+	FromRanges []core.TextRange
+}
+
+// CallHierarchyOutgoingCall is a declaration that an item calls, with the ranges of the calls,
+// which are in the file of the item.
+type CallHierarchyOutgoingCall struct {
+	To         HierarchyItem
+	FromRanges []core.TextRange
+}
+
+// ProvidePrepareCallHierarchy returns the function, method, class or other callable declaration
+// at position, or that the call at position resolves to.
+func (l *LanguageService) ProvidePrepareCallHierarchy(fileName string, position int) []HierarchyItem {
+	_, file := l.getProgramAndFile(fileName)
+	declaration := resolveCallHierarchyDeclaration(l.GetTypeChecker(), astnav.GetTouchingPropertyName(file, position))
+	if declaration == nil {
+		return nil
+	}
+	return []HierarchyItem{newCallHierarchyItem(declaration)}
+}
+
+// ProvideCallHierarchyIncomingCalls returns the declarations that call the item at position,
+// found among the references to it. Calls from the top level of a file come from the file.
+func (l *LanguageService) ProvideCallHierarchyIncomingCalls(fileName string, position int) []CallHierarchyIncomingCall {
+	program, file := l.getProgramAndFile(fileName)
+	c := l.GetTypeChecker()
+	declaration := resolveCallHierarchyDeclaration(c, astnav.GetTouchingPropertyName(file, position))
+	if declaration == nil || ast.IsSourceFile(declaration) || ast.IsModuleDeclaration(declaration) || ast.IsClassStaticBlockDeclaration(declaration) {
+		return nil
+	}
+	name := getCallHierarchyItemNameNode(declaration)
+	declarationFile := ast.GetSourceFileOfNode(name)
+	entries := findReferences(c, declarationFile, scanner.GetTokenPosOfNode(name, declarationFile, false /*includeJSDoc*/), program.SourceFiles())
+
+	var groups callSiteGroups
+	for _, entry := range entries {
+		if entry.IsDefinition || entry.node == nil || !isCallSiteReference(entry.node, declaration) {
+			continue
+		}
+		caller := ast.FindAncestor(entry.node.Parent, isValidCallHierarchyDeclaration)
+		groups.add(caller, entry.Range)
+	}
+	calls := make([]CallHierarchyIncomingCall, len(groups.declarations))
+	for i, caller := range groups.declarations {
+		calls[i] = CallHierarchyIncomingCall{From: newCallHierarchyItem(caller), FromRanges: groups.ranges[i]}
+	}
+	return calls
+}
+
+// ProvideCallHierarchyOutgoingCalls returns the declarations that the item at position calls, as
+// resolved by the checker, grouped by callee.
+func (l *LanguageService) ProvideCallHierarchyOutgoingCalls(fileName string, position int) []CallHierarchyOutgoingCall {
+	_, file := l.getProgramAndFile(fileName)
+	c := l.GetTypeChecker()
+	declaration := resolveCallHierarchyDeclaration(c, astnav.GetTouchingPropertyName(file, position))
+	if declaration == nil || declaration.Flags&ast.NodeFlagsAmbient != 0 || ast.IsMethodSignatureDeclaration(declaration) {
+		return nil
+	}
+
+	collector := &callSiteCollector{c: c, file: ast.GetSourceFileOfNode(declaration)}
+	collector.collectCallSitesOfDeclaration(declaration)
+	calls := make([]CallHierarchyOutgoingCall, len(collector.groups.declarations))
+	for i, callee := range collector.groups.declarations {
+		calls[i] = CallHierarchyOutgoingCall{To: newCallHierarchyItem(callee), FromRanges: collector.groups.ranges[i]}
+	}
+	return calls
+}
+
+// callSiteGroups groups call sites by the declaration they are in or call, in the order that the
+// declarations are first seen.
+type callSiteGroups struct {
+	declarations []*ast.Node
+	ranges       [][]core.TextRange
+	index        map[*ast.Node]int
+}
+
+func (g *callSiteGroups) add(declaration *ast.Node, textRange core.TextRange) {
+	if g.index == nil {
+		g.index = make(map[*ast.Node]int)
+	}
+	i, ok := g.index[declaration]
+	if !ok {
+		i = len(g.declarations)
+		g.index[declaration] = i
+		g.declarations = append(g.declarations, declaration)
+		g.ranges = append(g.ranges, nil)
+	}
+	g.ranges[i] = append(g.ranges[i], textRange)
+}
+
+// isValidCallHierarchyDeclaration reports whether a node is something that can call or be called:
+// a file or namespace for the code at its top level, a function, a class for its constructor, a
+// method or accessor, or a function or class expression that is named or assigned to a constant.
+func isValidCallHierarchyDeclaration(node *ast.Node) bool {
+	switch node.Kind {
+	case ast.KindSourceFile, ast.KindFunctionDeclaration, ast.KindClassDeclaration, ast.KindClassStaticBlockDeclaration,
+		ast.KindMethodDeclaration, ast.KindMethodSignature, ast.KindGetAccessor, ast.KindSetAccessor:
+		return true
+	case ast.KindModuleDeclaration:
+		return ast.IsIdentifier(node.Name())
+	case ast.KindFunctionExpression, ast.KindClassExpression:
+		return node.Name() != nil || isAssignedExpression(node)
+	case ast.KindArrowFunction:
+		return isAssignedExpression(node)
+	}
+	return false
+}
+
+// isAssignedExpression reports whether a function or class expression is the initializer of a
+// constant, whose name it is known by.
+func isAssignedExpression(node *ast.Node) bool {
+	parent := node.Parent
+	return ast.IsVariableDeclaration(parent) && parent.Initializer() == node && ast.IsIdentifier(parent.Name()) && ast.IsVarConst(parent)
+}
+
+// resolveCallHierarchyDeclaration returns the declaration that location declares, is in the name
+// of, or refers to. A function with overloads is represented by its implementation.
+func resolveCallHierarchyDeclaration(c *checker.Checker, location *ast.Node) *ast.Node {
+	followedSymbol := false
+	for location != nil {
+		switch {
+		case isValidCallHierarchyDeclaration(location):
+			return getImplementationDeclaration(location)
+		case ast.IsDeclarationName(location):
+			parent := location.Parent
+			if isValidCallHierarchyDeclaration(parent) {
+				return getImplementationDeclaration(parent)
+			}
+			if ast.IsVariableDeclaration(parent) && parent.Initializer() != nil && isValidCallHierarchyDeclaration(parent.Initializer()) {
+				return parent.Initializer()
+			}
+			return nil
+		case ast.IsConstructorDeclaration(location):
+			if isValidCallHierarchyDeclaration(location.Parent) {
+				return location.Parent
+			}
+			return nil
+		case location.Kind == ast.KindStaticKeyword && ast.IsClassStaticBlockDeclaration(location.Parent):
+			return location.Parent
+		case ast.IsVariableDeclaration(location) && location.Initializer() != nil && isValidCallHierarchyDeclaration(location.Initializer()):
+			return location.Initializer()
+		}
+		if followedSymbol {
+			return nil
+		}
+		symbol := c.GetSymbolAtLocation(location)
+		if symbol == nil {
+			return nil
+		}
+		symbol = skipAlias(c, symbol)
+		if symbol.ValueDeclaration == nil {
+			return nil
+		}
+		followedSymbol = true
+		location = symbol.ValueDeclaration
+	}
+	return nil
+}
+
+// getImplementationDeclaration returns the declaration of a function with a body, rather than one
+// of its overloads.
+func getImplementationDeclaration(declaration *ast.Node) *ast.Node {
+	if !ast.IsFunctionLikeDeclaration(declaration) || declaration.Body() != nil || declaration.Symbol() == nil {
+		return declaration
+	}
+	for _, decl := range declaration.Symbol().Declarations {
+		if decl.Kind == declaration.Kind && decl.Body() != nil {
+			return decl
+		}
+	}
+	return declaration
+}
+
+// isCallSiteReference reports whether a reference to declaration calls it: as the target of a
+// call, a new expression, a tagged template, a decorator or a JSX tag, or, for an accessor, by
+// accessing it.
+func isCallSiteReference(node *ast.Node, declaration *ast.Node) bool {
+	target := node
+	if parent := node.Parent; (ast.IsPropertyAccessExpression(parent) || ast.IsQualifiedName(parent)) && parent.Name() == node {
+		if ast.IsAccessor(declaration) {
+			return true
+		}
+		target = parent
+	}
+	parent := target.Parent
+	switch parent.Kind {
+	case ast.KindCallExpression, ast.KindNewExpression, ast.KindDecorator:
+		return parent.Expression() == target
+	case ast.KindTaggedTemplateExpression:
+		return parent.AsTaggedTemplateExpression().Tag == target
+	case ast.KindJsxOpeningElement, ast.KindJsxSelfClosingElement:
+		return parent.TagName() == target
+	}
+	return false
+}
+
+type callSiteCollector struct {
+	c      *checker.Checker
+	file   *ast.SourceFile
+	groups callSiteGroups
+}
+
+func (cs *callSiteCollector) collectCallSitesOfDeclaration(declaration *ast.Node) {
+	switch {
+	case ast.IsSourceFile(declaration):
+		for _, statement := range declaration.AsSourceFile().Statements.Nodes {
+			cs.collect(statement)
+		}
+	case ast.IsModuleDeclaration(declaration):
+		if body := declaration.Body(); body != nil {
+			body.ForEachChild(cs.visit)
+		}
+	case ast.IsClassLike(declaration):
+		// The code of a class that runs when it is defined or constructed: its decorators, its
+		// heritage clauses, the initializers of its properties and its static blocks.
+		declaration.ForEachChild(func(child *ast.Node) bool {
+			switch {
+			case ast.IsPropertyDeclaration(child):
+				cs.collect(child.Initializer())
+			case ast.IsConstructorDeclaration(child):
+				for _, parameter := range child.Parameters() {
+					cs.collect(parameter)
+				}
+				cs.collect(child.Body())
+			case ast.IsClassStaticBlockDeclaration(child):
+				cs.collect(child.AsClassStaticBlockDeclaration().Body)
+			case ast.IsDecorator(child) || ast.IsHeritageClause(child):
+				cs.collect(child)
+			}
+			return false
+		})
+	case ast.IsClassStaticBlockDeclaration(declaration):
+		cs.collect(declaration.AsClassStaticBlockDeclaration().Body)
+	default:
+		for _, parameter := range declaration.Parameters() {
+			cs.collect(parameter)
+		}
+		cs.collect(declaration.Body())
+	}
+}
+
+func (cs *callSiteCollector) visit(node *ast.Node) bool {
+	cs.collect(node)
+	return false
+}
+
+// collect records the call sites within node. Nested declarations that can be called are call
+// hierarchy items of their own, so their bodies are left out.
+func (cs *callSiteCollector) collect(node *ast.Node) {
+	if node == nil || node.Flags&ast.NodeFlagsAmbient != 0 || ast.IsPartOfTypeNode(node) {
+		return
+	}
+	if isValidCallHierarchyDeclaration(node) {
+		return
+	}
+	switch node.Kind {
+	case ast.KindIdentifier, ast.KindImportEqualsDeclaration, ast.KindImportDeclaration, ast.KindExportDeclaration,
+		ast.KindInterfaceDeclaration, ast.KindTypeAliasDeclaration:
+		return
+	case ast.KindCallExpression, ast.KindNewExpression, ast.KindDecorator:
+		cs.recordCallSite(node, node.Expression())
+	case ast.KindTaggedTemplateExpression:
+		cs.recordCallSite(node, node.AsTaggedTemplateExpression().Tag)
+	case ast.KindJsxOpeningElement, ast.KindJsxSelfClosingElement:
+		cs.recordCallSite(node, node.TagName())
+	case ast.KindPropertyAccessExpression, ast.KindElementAccessExpression:
+		cs.recordAccessorCallSite(node)
+	}
+	node.ForEachChild(cs.visit)
+}
+
+// recordCallSite records a call to the declaration that the target of the call refers to, or, when
+// that is not a declaration that can be called, such as a parameter of a function type, to the
+// declaration of the signature that the call resolves to.
+func (cs *callSiteCollector) recordCallSite(call *ast.Node, target *ast.Node) {
+	callee := resolveCallHierarchyDeclaration(cs.c, getCallTargetName(target))
+	if callee == nil {
+		if signature := cs.c.GetResolvedSignature(call); signature != nil && signature.Declaration() != nil {
+			callee = resolveCallHierarchyDeclaration(cs.c, signature.Declaration())
+		}
+	}
+	if callee != nil {
+		cs.groups.add(callee, getCallSiteRange(cs.file, target))
+	}
+}
+
+// recordAccessorCallSite records an access of a property that is declared by a get or set
+// accessor, which calls it.
+func (cs *callSiteCollector) recordAccessorCallSite(access *ast.Node) {
+	if parent := access.Parent; (ast.IsCallExpression(parent) || ast.IsNewExpression(parent)) && parent.Expression() == access {
+		return
+	}
+	symbol := cs.c.GetSymbolAtLocation(getCallTargetName(access))
+	if symbol == nil || symbol.Flags&ast.SymbolFlagsAccessor == 0 {
+		return
+	}
+	for _, decl := range symbol.Declarations {
+		if ast.IsAccessor(decl) {
+			cs.groups.add(decl, getCallSiteRange(cs.file, access))
+			return
+		}
+	}
+}
+
+// getCallTargetName returns the name that the target of a call refers to its callee by, as in b
+// for a.b(), or the target itself.
+func getCallTargetName(target *ast.Node) *ast.Node {
+	switch target.Kind {
+	case ast.KindPropertyAccessExpression:
+		return target.Name()
+	case ast.KindElementAccessExpression:
+		return target.AsElementAccessExpression().ArgumentExpression
+	}
+	return target
+}
+
+func getCallSiteRange(file *ast.SourceFile, target *ast.Node) core.TextRange {
+	name := getCallTargetName(target)
+	return core.NewTextRange(scanner.GetTokenPosOfNode(name, file, false /*includeJSDoc*/), name.End())
+}
+
+func newCallHierarchyItem(declaration *ast.Node) HierarchyItem {
+	file := ast.GetSourceFileOfNode(declaration)
+	if ast.IsSourceFile(declaration) {
+		return HierarchyItem{
+			Name:     tspath.GetBaseFileName(file.FileName()),
+			Kind:     lsproto.SymbolKindFile,
+			Detail:   file.FileName(),
+			FileName: file.FileName(),
+			Range:    core.NewTextRange(0, file.End()),
+		}
+	}
+
+	node := declaration
+	if isAssignedExpression(declaration) {
+		// An assigned function is declared by the constant, which also contains its name.
+		node = declaration.Parent
+	}
+	item := HierarchyItem{
+		Name:     getCallHierarchyItemName(declaration),
+		Kind:     getCallHierarchyItemKind(declaration),
+		Detail:   getCallHierarchyItemContainerName(declaration),
+		FileName: file.FileName(),
+		Range:    core.NewTextRange(scanner.GetTokenPosOfNode(node, file, true /*includeJSDoc*/), node.End()),
+	}
+	if name := getCallHierarchyItemNameNode(declaration); name != nil {
+		item.SelectionRange = core.NewTextRange(scanner.GetTokenPosOfNode(name, file, false /*includeJSDoc*/), name.End())
+	} else {
+		start := scanner.GetTokenPosOfNode(declaration, file, false /*includeJSDoc*/)
+		item.SelectionRange = core.NewTextRange(start, start)
+	}
+	return item
+}
+
+// getCallHierarchyItemNameNode returns the node that names a declaration, which is the name of the
+// constant for an assigned function.
+func getCallHierarchyItemNameNode(declaration *ast.Node) *ast.Node {
+	if isAssignedExpression(declaration) {
+		return declaration.Parent.Name()
+	}
+	if ast.IsClassStaticBlockDeclaration(declaration) {
+		return nil
+	}
+	return ast.GetNameOfDeclaration(declaration)
+}
+
+func getCallHierarchyItemName(declaration *ast.Node) string {
+	if ast.IsClassStaticBlockDeclaration(declaration) {
+		return "static {}"
+	}
+	name := getCallHierarchyItemNameNode(declaration)
+	if name == nil {
+		if ast.HasSyntacticModifier(declaration, ast.ModifierFlagsDefault) {
+			return "default"
+		}
+		return core.IfElse(ast.IsClassLike(declaration), "<class>", "<function>")
+	}
+	switch name.Kind {
+	case ast.KindIdentifier, ast.KindPrivateIdentifier, ast.KindStringLiteral, ast.KindNumericLiteral, ast.KindNoSubstitutionTemplateLiteral:
+		return name.Text()
+	}
+	return getNodeText(name)
+}
+
+func getCallHierarchyItemKind(declaration *ast.Node) lsproto.SymbolKind {
+	switch declaration.Kind {
+	case ast.KindClassDeclaration, ast.KindClassExpression:
+		return lsproto.SymbolKindClass
+	case ast.KindModuleDeclaration:
+		return lsproto.SymbolKindNamespace
+	case ast.KindMethodDeclaration, ast.KindMethodSignature, ast.KindClassStaticBlockDeclaration:
+		return lsproto.SymbolKindMethod
+	case ast.KindGetAccessor, ast.KindSetAccessor:
+		return lsproto.SymbolKindProperty
+	}
+	return lsproto.SymbolKindFunction
+}
+
+// getCallHierarchyItemContainerName returns the name of the class, object literal or namespace
+// that a declaration is a member of, or "" if it is not a member of one.
+func getCallHierarchyItemContainerName(declaration *ast.Node) string {
+	container := declaration.Parent
+	if isAssignedExpression(declaration) {
+		// The declaration list and the statement of the constant are between it and its container.
+		container = declaration.Parent.Parent.Parent.Parent
+	}
+	if ast.IsModuleBlock(container) {
+		container = container.Parent
+	}
+	switch {
+	case ast.IsClassLike(container) || ast.IsModuleDeclaration(container):
+		if name := container.Name(); name != nil {
+			return getNodeText(name)
+		}
+	case ast.IsObjectLiteralExpression(container):
+		if ast.IsVariableDeclaration(container.Parent) && ast.IsIdentifier(container.Parent.Name()) {
+			return container.Parent.Name().Text()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package ls_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/ls"
+	"gotest.tools/v3/assert"
+)
+
+// formatHierarchyItem prints an item as the marker at its name, its kind and its container.
+func (p *testProject) formatHierarchyItem(item ls.HierarchyItem) string {
+	s := p.markerAt(ls.Location{FileName: item.FileName, Range: item.SelectionRange}) + " (" + symbolKindNames[item.Kind] + ")"
+	if item.Detail != "" {
+		s += " " + item.Detail
+	}
+	return s
+}
+
+// formatCall prints a call as the item it is from or to, followed by the markers at its ranges.
+func (p *testProject) formatCall(item ls.HierarchyItem, fileName string, ranges []core.TextRange) string {
+	markers := make([]string, len(ranges))
+	for i, textRange := range ranges {
+		markers[i] = p.markerAt(ls.Location{FileName: fileName, Range: textRange})
+	}
+	return p.formatHierarchyItem(item) + ": " + strings.Join(markers, ", ")
+}
+
+func TestCallHierarchy(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"/home/src/project/tsconfig.json": `{}`,
+		"/home/src/project/index.ts": `// Greetings
+export function /*greet*/greet(name: string) { return /*c1*/format(name); }
+function /*format*/format(s: string) { return s + "!"; }
+class /*Service*/Service {
+    /*run*/run() { /*c2*/greet("a"); /*c3*/greet("b"); this./*c4*/value; }
+    get /*value*/value() { return /*c5*/format("x"); }
+}
+const /*handler*/handler = () => /*c6*/greet("c");
+/*c7*/greet("top");
+new /*c8*/Service();`,
+		"/home/src/project/other.ts": `import { greet } from "./index";
+namespace /*Tools*/Tools {
+    export function /*tool*/tool() { /*c9*/greet("tool"); }
+}`,
+	}
+
+	t.Run("prepare", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, files)
+		cases := []struct {
+			marker   string
+			expected []string
+		}{
+			{marker: "c2", expected: []string{"greet (function)"}},
+			{marker: "run", expected: []string{"run (method) Service"}},
+			{marker: "value", expected: []string{"value (property) Service"}},
+			{marker: "handler", expected: []string{"handler (function)"}},
+			{marker: "tool", expected: []string{"tool (function) Tools"}},
+		}
+		for _, testCase := range cases {
+			l, m := p.languageServiceAt(testCase.marker)
+			items := l.ProvidePrepareCallHierarchy(m.fileName, m.position)
+			assert.DeepEqual(t, core.Map(items, p.formatHierarchyItem), testCase.expected)
+		}
+	})
+
+	t.Run("incoming calls", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, files)
+		l, m := p.languageServiceAt("greet")
+		calls := l.ProvideCallHierarchyIncomingCalls(m.fileName, m.position)
+		actual := make([]string, len(calls))
+		for i, call := range calls {
+			actual[i] = p.formatCall(call.From, call.From.FileName, call.FromRanges)
+		}
+		// Calls from the top level of a file come from the file itself
+		assert.DeepEqual(t, actual, []string{
+			"run (method) Service: c2, c3",
+			"handler (function): c6",
+			"/home/src/project/index.ts(0) (file) /home/src/project/index.ts: c7",
+			"tool (function) Tools: c9",
+		})
+	})
+
+	t.Run("outgoing calls", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, files)
+		cases := []struct {
+			marker   string
+			expected []string
+		}{
+			// Accessing a property with a get accessor calls it
+			{marker: "run", expected: []string{"greet (function): c2, c3", "value (property) Service: c4"}},
+			{marker: "greet", expected: []string{"format (function): c1"}},
+			{marker: "format", expected: []string{}},
+			{marker: "handler", expected: []string{"greet (function): c6"}},
+		}
+		for _, testCase := range cases {
+			l, m := p.languageServiceAt(testCase.marker)
+			calls := l.ProvideCallHierarchyOutgoingCalls(m.fileName, m.position)
+			actual := make([]string, len(calls))
+			for i, call := range calls {
+				actual[i] = p.formatCall(call.To, m.fileName, call.FromRanges)
+			}
+			assert.DeepEqual(t, actual, testCase.expected)
+		}
+	})
+
+	t.Run("outgoing calls of a file", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, files)
+		l := p.languageService("/home/src/project/index.ts")
+		// The position of a file item is its start, which the comment keeps out of the first declaration
+		calls := l.ProvideCallHierarchyOutgoingCalls("/home/src/project/index.ts", 0)
+		actual := make([]string, len(calls))
+		for i, call := range calls {
+			actual[i] = p.formatCall(call.To, "/home/src/project/index.ts", call.FromRanges)
+		}
+		// Constructing a class calls it
+		assert.DeepEqual(t, actual, []string{"greet (function): c7", "Service (class): c8"})
+	})
+}
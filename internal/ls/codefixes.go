@@ -0,0 +1,352 @@
+package ls
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/checker"
+	"github.com/microsoft/typescript-go/internal/compiler"
+	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/scanner"
+	"github.com/microsoft/typescript-go/internal/stringutil"
+)
+
+// CodeFix is a set of changes that fixes a diagnostic.
+type CodeFix struct {
+	Title string
+	// FixID names the kind of fix, for applying it to every diagnostic in a file at once. It is
+	// empty if the fix cannot be applied that way.
+	FixID   string
+	Changes map[string][]TextChange
+}
+
+// CodeFixDiagnostic is a diagnostic that the client asks to fix, as it was reported to it.
+type CodeFixDiagnostic struct {
+	Code int32
+	Span core.TextRange
+}
+
+// codeFixProvider fixes the diagnostics with the given error codes.
+type codeFixProvider struct {
+	errorCodes []int32
+	// getCodeFixes returns the fixes for the diagnostic at context.span.
+	getCodeFixes func(context *codeFixContext) []CodeFix
+	// fixID and fixAllDescription are set for the providers whose fixes can be applied to every
+	// diagnostic in a file at once.
+	fixID             string
+	fixAllDescription *diagnostics.Message
+}
+
+// codeFixProviders is the registry of fixes. A diagnostic may be fixed by more than one of them.
+var codeFixProviders = []*codeFixProvider{
+	fixMissingImportProvider,
+	fixSpellingProvider,
+	fixMissingAwaitProvider,
+	fixImplementInterfaceProvider,
+	fixImplementAbstractClassProvider,
+	fixMissingPropertyProvider,
+	fixUnusedDeclarationProvider,
+	fixOverrideModifierProvider,
+}
+
+type codeFixContext struct {
+	languageService *LanguageService
+	program         *compiler.Program
+	checker         *checker.Checker
+	file            *ast.SourceFile
+	errorCode       int32
+	span            core.TextRange
+	newLine         string
+	preferences     *UserPreferences
+}
+
+func (context *codeFixContext) getTokenAtSpan() *ast.Node {
+	return getTokenAtPositionOfSpan(context.file, context.span)
+}
+
+func getCodeFixProviders(errorCode int32) []*codeFixProvider {
+	return core.Filter(codeFixProviders, func(provider *codeFixProvider) bool {
+		return slices.Contains(provider.errorCodes, errorCode)
+	})
+}
+
+// ProvideCodeFixes returns the fixes for the given diagnostics of a file.
+func (l *LanguageService) ProvideCodeFixes(fileName string, diagnostics []CodeFixDiagnostic) []CodeFix {
+	program, file := l.getProgramAndFile(fileName)
+	var fixes []CodeFix
+	for _, diagnostic := range diagnostics {
+		context := &codeFixContext{
+			languageService: l,
+			program:         program,
+			checker:         l.GetTypeChecker(),
+			file:            file,
+			errorCode:       diagnostic.Code,
+			span:            diagnostic.Span,
+			newLine:         l.host.NewLine(),
+			preferences:     l.host.GetUserPreferences(),
+		}
+		for _, provider := range getCodeFixProviders(diagnostic.Code) {
+			fixes = append(fixes, provider.getCodeFixes(context)...)
+		}
+	}
+	return fixes
+}
+
+// ProvideFixAll applies the fix named fixID to every diagnostic of a file that it fixes. Changes
+// that would overlap ones that were already made are left out; running the fix again applies them.
+func (l *LanguageService) ProvideFixAll(fileName string, fixID string) *CodeFix {
+	program, file := l.getProgramAndFile(fileName)
+	provider := core.Find(codeFixProviders, func(provider *codeFixProvider) bool {
+		return provider.fixID != "" && provider.fixID == fixID
+	})
+	if provider == nil {
+		return nil
+	}
+
+	changes := make(map[string][]TextChange)
+	for _, diagnostic := range l.GetDocumentDiagnostics(fileName) {
+		if !slices.Contains(provider.errorCodes, diagnostic.Code()) {
+			continue
+		}
+		context := &codeFixContext{
+			languageService: l,
+			program:         program,
+			checker:         l.GetTypeChecker(),
+			file:            file,
+			errorCode:       diagnostic.Code(),
+			span:            diagnostic.Loc(),
+			newLine:         l.host.NewLine(),
+			preferences:     l.host.GetUserPreferences(),
+		}
+		for _, fix := range provider.getCodeFixes(context) {
+			if fix.FixID != fixID {
+				continue
+			}
+			for fileName, fileChanges := range fix.Changes {
+				changes[fileName] = addNonOverlappingChanges(changes[fileName], fileChanges)
+			}
+			break
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	sortChangesByPosition(changes)
+	return &CodeFix{
+		Title:   provider.fixAllDescription.Message(),
+		FixID:   fixID,
+		Changes: changes,
+	}
+}
+
+// sourceFixAllFixIDs are the fixes applied by the source.fixAll action. They are the ones that
+// are safe to apply without the user choosing between alternatives.
+var sourceFixAllFixIDs = []string{
+	"fixClassIncorrectlyImplementsInterface",
+	"fixClassDoesntImplementInheritedAbstractMember",
+	"fixMissingAwait",
+	"fixAddOverrideModifier",
+}
+
+// ProvideSourceFixAll applies every fix of sourceFixAllFixIDs to a file.
+func (l *LanguageService) ProvideSourceFixAll(fileName string) map[string][]TextChange {
+	changes := make(map[string][]TextChange)
+	for _, fixID := range sourceFixAllFixIDs {
+		fix := l.ProvideFixAll(fileName, fixID)
+		if fix == nil {
+			continue
+		}
+		for fileName, fileChanges := range fix.Changes {
+			changes[fileName] = addNonOverlappingChanges(changes[fileName], fileChanges)
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	sortChangesByPosition(changes)
+	return changes
+}
+
+// GetFixAllDescription returns the title of the action that applies a fix to a whole file.
+func GetFixAllDescription(fixID string) string {
+	if provider := core.Find(codeFixProviders, func(provider *codeFixProvider) bool { return provider.fixID == fixID }); provider != nil {
+		return provider.fixAllDescription.Message()
+	}
+	return ""
+}
+
+// CanFixAll reports whether a fix applies to more than one of the given diagnostic codes.
+func CanFixAll(fixID string, codes []int32) bool {
+	provider := core.Find(codeFixProviders, func(provider *codeFixProvider) bool { return provider.fixID == fixID })
+	if provider == nil || fixID == "" {
+		return false
+	}
+	count := 0
+	for _, code := range codes {
+		if slices.Contains(provider.errorCodes, code) {
+			count++
+		}
+	}
+	return count > 1
+}
+
+func addNonOverlappingChanges(changes []TextChange, newChanges []TextChange) []TextChange {
+	for _, change := range newChanges {
+		if slices.ContainsFunc(changes, func(existing TextChange) bool {
+			if existing.Len() == 0 && change.Len() == 0 {
+				return existing.Pos() == change.Pos() && existing.NewText == change.NewText
+			}
+			if existing.Len() == 0 {
+				return change.Pos() < existing.Pos() && existing.Pos() < change.End()
+			}
+			if change.Len() == 0 {
+				return existing.Pos() < change.Pos() && change.Pos() < existing.End()
+			}
+			return existing.Pos() < change.End() && change.Pos() < existing.End()
+		}) {
+			// The whole fix is dropped rather than applied partially.
+			return changes
+		}
+	}
+	return append(changes, newChanges...)
+}
+
+func sortChangesByPosition(changes map[string][]TextChange) {
+	for _, fileChanges := range changes {
+		slices.SortStableFunc(fileChanges, func(a, b TextChange) int {
+			return cmp.Compare(a.Pos(), b.Pos())
+		})
+	}
+}
+
+func newCodeFix(title *diagnostics.Message, args []any, fixID string, fileName string, changes ...TextChange) CodeFix {
+	text := title.Message()
+	if len(args) != 0 {
+		text = stringutil.Format(text, args)
+	}
+	return CodeFix{
+		Title:   text,
+		FixID:   fixID,
+		Changes: map[string][]TextChange{fileName: changes},
+	}
+}
+
+// getTokenAtPositionOfSpan returns the innermost node that starts at the start of span, preferring
+// one that also ends where span does.
+func getTokenAtPositionOfSpan(file *ast.SourceFile, span core.TextRange) *ast.Node {
+	var result *ast.Node
+	var visit func(node *ast.Node) bool
+	visit = func(node *ast.Node) bool {
+		start := scanner.GetTokenPosOfNode(node, file, false /*includeJSDoc*/)
+		if start > span.Pos() || node.End() < span.Pos() || node.End() == span.Pos() && node.Pos() != node.End() {
+			return false
+		}
+		if start == span.Pos() && (result == nil || node.End() == span.End() || result.End() != span.End()) {
+			result = node
+		}
+		node.ForEachChild(visit)
+		return false
+	}
+	file.AsNode().ForEachChild(visit)
+	return result
+}
+
+// getDeleteRange returns the range to delete to remove a node, together with its JSDoc and the
+// line it is on when nothing else is on that line.
+func getDeleteRange(file *ast.SourceFile, node *ast.Node) core.TextRange {
+	text := file.Text()
+	start := scanner.GetTokenPosOfNode(node, file, true /*includeJSDoc*/)
+	end := node.End()
+	lineStart := getLineStartOfPosition(text, start)
+	if strings.TrimSpace(text[lineStart:start]) == "" {
+		lineEnd := end
+		for lineEnd < len(text) && (text[lineEnd] == ' ' || text[lineEnd] == '\t') {
+			lineEnd++
+		}
+		if lineEnd == len(text) || text[lineEnd] == '\r' || text[lineEnd] == '\n' {
+			start = lineStart
+			end = lineEnd
+			if strings.HasPrefix(text[end:], "\r\n") {
+				end += 2
+			} else if end < len(text) {
+				end++
+			}
+		}
+	}
+	return core.NewTextRange(start, end)
+}
+
+// getDeleteRangeInList returns the range to delete to remove an element of a comma separated
+// list, including the comma that separates it from its neighbour.
+func getDeleteRangeInList(file *ast.SourceFile, node *ast.Node, list []*ast.Node) core.TextRange {
+	index := slices.Index(list, node)
+	start := scanner.GetTokenPosOfNode(node, file, false /*includeJSDoc*/)
+	if index < len(list)-1 {
+		return core.NewTextRange(start, scanner.GetTokenPosOfNode(list[index+1], file, false /*includeJSDoc*/))
+	}
+	if index > 0 {
+		return core.NewTextRange(list[index-1].End(), node.End())
+	}
+	return core.NewTextRange(start, node.End())
+}
+
+func getLineStartOfPosition(text string, pos int) int {
+	for pos > 0 && text[pos-1] != '\n' && text[pos-1] != '\r' {
+		pos--
+	}
+	return pos
+}
+
+// getLineIndentation returns the whitespace at the start of the line that contains pos.
+func getLineIndentation(text string, pos int) string {
+	start := getLineStartOfPosition(text, pos)
+	end := start
+	for end < len(text) && (text[end] == ' ' || text[end] == '\t') {
+		end++
+	}
+	return text[start:end]
+}
+
+// getMemberIndentation returns the indentation of the members of a class, interface or object
+// literal, taken from its first member if it has one.
+func getMemberIndentation(file *ast.SourceFile, container *ast.Node, members []*ast.Node) string {
+	text := file.Text()
+	containerStart := scanner.GetTokenPosOfNode(container, file, false /*includeJSDoc*/)
+	if len(members) != 0 {
+		// Members written on the same line as the container do not show its indentation.
+		if memberStart := scanner.GetTokenPosOfNode(members[0], file, false /*includeJSDoc*/); getLineStartOfPosition(text, memberStart) != getLineStartOfPosition(text, containerStart) {
+			return getLineIndentation(text, memberStart)
+		}
+	}
+	return getLineIndentation(text, containerStart) + "    "
+}
+
+// getInsertionAtEndOfMembers returns an insertion of lines before the closing brace of a class,
+// interface or object literal.
+func getInsertionAtEndOfMembers(file *ast.SourceFile, container *ast.Node, members []*ast.Node, lines []string, newLine string) TextChange {
+	text := file.Text()
+	indentation := getMemberIndentation(file, container, members)
+	closeBrace := container.End() - 1
+	for closeBrace > 0 && text[closeBrace] != '}' {
+		closeBrace--
+	}
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(indentation)
+		b.WriteString(line)
+		b.WriteString(newLine)
+	}
+	insertAt := getLineStartOfPosition(text, closeBrace)
+	if strings.TrimSpace(text[insertAt:closeBrace]) != "" {
+		// The closing brace shares its line with the last member or the opening brace.
+		insertAt = closeBrace
+		return TextChange{
+			TextRange: core.NewTextRange(insertAt, insertAt),
+			NewText:   newLine + b.String() + getLineIndentation(text, closeBrace),
+		}
+	}
+	return TextChange{TextRange: core.NewTextRange(insertAt, insertAt), NewText: b.String()}
+}
@@ -0,0 +1,358 @@
+package ls_test
+
+import (
+	"testing"
+
+	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/ls"
+	"gotest.tools/v3/assert"
+)
+
+const codeFixesConfig = `{
+    "compilerOptions": {
+        "strict": true,
+        "target": "esnext",
+        "module": "esnext",
+        "moduleResolution": "bundler",
+        "noImplicitOverride": true,
+        "noUnusedLocals": true
+    }
+}`
+
+// getCodeFixDiagnostics returns the diagnostics of a file with the given code, as the client
+// reports them back to be fixed.
+func getCodeFixDiagnostics(l *ls.LanguageService, fileName string, code int32) []ls.CodeFixDiagnostic {
+	var result []ls.CodeFixDiagnostic
+	for _, diagnostic := range l.GetDocumentDiagnostics(fileName) {
+		if diagnostic.Code() == code {
+			result = append(result, ls.CodeFixDiagnostic{Code: diagnostic.Code(), Span: diagnostic.Loc()})
+		}
+	}
+	return result
+}
+
+func TestCodeFixes(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		title string
+		// The files of the project besides index.ts, which has the diagnostic
+		files map[string]string
+		text  string
+		// The diagnostic that is fixed, and the title of the fix that is applied to it
+		diagnostic *diagnostics.Message
+		fixTitle   string
+		// The text of index.ts after the fix
+		expected string
+	}{
+		{
+			title: "add missing import",
+			files: map[string]string{
+				"/home/src/project/helpers.ts": `export function helper() {}`,
+			},
+			text: `import { other } from "./other";
+
+helper();`,
+			diagnostic: diagnostics.Cannot_find_name_0,
+			fixTitle:   `Add import from "./helpers"`,
+			expected: `import { other } from "./other";
+import { helper } from "./helpers";
+
+helper();`,
+		},
+		{
+			title: "add to an existing import",
+			files: map[string]string{
+				"/home/src/project/helpers.ts": `export function helper() {}
+export function other() {}`,
+			},
+			text: `import { other } from "./helpers";
+other();
+helper();`,
+			diagnostic: diagnostics.Cannot_find_name_0,
+			fixTitle:   `Update import from "./helpers"`,
+			expected: `import { other, helper } from "./helpers";
+other();
+helper();`,
+		},
+		{
+			title: "fix spelling of a property",
+			text: `const values = [1, 2];
+values.lenght;`,
+			diagnostic: diagnostics.Property_0_does_not_exist_on_type_1_Did_you_mean_2,
+			fixTitle:   "Change spelling to 'length'",
+			expected: `const values = [1, 2];
+values.length;`,
+		},
+		{
+			title: "fix spelling of a name",
+			text: `const counter = 1;
+console.log(countr);`,
+			diagnostic: diagnostics.Cannot_find_name_0_Did_you_mean_1,
+			fixTitle:   "Change spelling to 'counter'",
+			expected: `const counter = 1;
+console.log(counter);`,
+		},
+		{
+			title: "add missing await to an operand",
+			text: `export async function f(p: Promise<number>) {
+    return p + 1;
+}`,
+			diagnostic: diagnostics.Operator_0_cannot_be_applied_to_types_1_and_2,
+			fixTitle:   "Add 'await'",
+			expected: `export async function f(p: Promise<number>) {
+    return await p + 1;
+}`,
+		},
+		{
+			title: "add missing await to an argument",
+			text: `declare function square(n: number): number;
+export async function f(p: Promise<number>) {
+    return square(p);
+}`,
+			diagnostic: diagnostics.Argument_of_type_0_is_not_assignable_to_parameter_of_type_1,
+			fixTitle:   "Add 'await'",
+			expected: `declare function square(n: number): number;
+export async function f(p: Promise<number>) {
+    return square(await p);
+}`,
+		},
+		{
+			title: "implement an interface",
+			text: `interface Shape {
+    name: string;
+    area(scale: number): number;
+}
+class Square implements Shape {
+}`,
+			diagnostic: diagnostics.Class_0_incorrectly_implements_interface_1,
+			fixTitle:   "Implement interface 'Shape'",
+			expected: `interface Shape {
+    name: string;
+    area(scale: number): number;
+}
+class Square implements Shape {
+    name: string;
+    area(scale: number): number {
+        throw new Error("Method not implemented.");
+    }
+}`,
+		},
+		{
+			title: "implement an abstract class",
+			text: `abstract class Base {
+    abstract run(): void;
+}
+class Runner extends Base {
+    stop() {}
+}`,
+			diagnostic: diagnostics.Non_abstract_class_0_does_not_implement_inherited_abstract_member_1_from_class_2,
+			fixTitle:   "Implement inherited abstract class",
+			expected: `abstract class Base {
+    abstract run(): void;
+}
+class Runner extends Base {
+    stop() {}
+    run(): void {
+        throw new Error("Method not implemented.");
+    }
+}`,
+		},
+		{
+			title: "declare a missing property",
+			text: `class Counter {
+    increment() {
+        this.count = 1;
+    }
+}`,
+			diagnostic: diagnostics.Property_0_does_not_exist_on_type_1,
+			fixTitle:   "Declare property 'count'",
+			expected: `class Counter {
+    increment() {
+        this.count = 1;
+    }
+    count: number;
+}`,
+		},
+		{
+			title: "add an override modifier",
+			text: `class Base {
+    run() {}
+}
+class Derived extends Base {
+    public run() {}
+}`,
+			diagnostic: diagnostics.This_member_must_have_an_override_modifier_because_it_overrides_a_member_in_the_base_class_0,
+			fixTitle:   "Add 'override' modifier",
+			expected: `class Base {
+    run() {}
+}
+class Derived extends Base {
+    public override run() {}
+}`,
+		},
+		{
+			title: "delete an unused variable",
+			text: `export function f() {
+    const unused = 1;
+    return 2;
+}`,
+			diagnostic: diagnostics.X_0_is_declared_but_its_value_is_never_read,
+			fixTitle:   "Remove unused declaration for: 'unused'",
+			expected: `export function f() {
+    return 2;
+}`,
+		},
+		{
+			title: "delete an unused import",
+			files: map[string]string{
+				"/home/src/project/helpers.ts": `export function a() {}
+export function b() {}`,
+			},
+			text: `import { a, b } from "./helpers";
+export {};`,
+			diagnostic: diagnostics.All_imports_in_import_declaration_are_unused,
+			fixTitle:   "Remove import from './helpers'",
+			expected:   `export {};`,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.title, func(t *testing.T) {
+			t.Parallel()
+			files := map[string]string{
+				"/home/src/project/tsconfig.json": codeFixesConfig,
+				"/home/src/project/index.ts":      testCase.text,
+			}
+			for fileName, text := range testCase.files {
+				files[fileName] = text
+			}
+			p := newTestProject(t, files)
+			l := p.languageService("/home/src/project/index.ts")
+			fixDiagnostics := getCodeFixDiagnostics(l, "/home/src/project/index.ts", testCase.diagnostic.Code())
+			assert.Assert(t, len(fixDiagnostics) != 0, "no diagnostic %d", testCase.diagnostic.Code())
+			fixes := l.ProvideCodeFixes("/home/src/project/index.ts", fixDiagnostics[:1])
+			fix := core.Find(fixes, func(fix ls.CodeFix) bool { return fix.Title == testCase.fixTitle })
+			assert.Assert(t, fix.Title != "", "no fix %q in %v", testCase.fixTitle, core.Map(fixes, func(fix ls.CodeFix) string { return fix.Title }))
+			assert.Equal(t, applyChanges(testCase.text, fix.Changes["/home/src/project/index.ts"]), testCase.expected)
+		})
+	}
+}
+
+func TestFixAll(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		title    string
+		text     string
+		fixID    string
+		expected string
+	}{
+		{
+			title: "spelling",
+			text: `const values = [1, 2];
+values.lenght;
+values.lenth;`,
+			fixID: "fixSpelling",
+			expected: `const values = [1, 2];
+values.length;
+values.length;`,
+		},
+		{
+			title: "override modifiers",
+			text: `class Base {
+    run() {}
+    stop() {}
+}
+class Derived extends Base {
+    run() {}
+    stop() {}
+}`,
+			fixID: "fixAddOverrideModifier",
+			expected: `class Base {
+    run() {}
+    stop() {}
+}
+class Derived extends Base {
+    override run() {}
+    override stop() {}
+}`,
+		},
+		{
+			title: "missing awaits",
+			text: `export async function f(a: Promise<number>, b: Promise<number>) {
+    return a * b;
+}`,
+			fixID: "fixMissingAwait",
+			expected: `export async function f(a: Promise<number>, b: Promise<number>) {
+    return await a * await b;
+}`,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.title, func(t *testing.T) {
+			t.Parallel()
+			p := newTestProject(t, map[string]string{
+				"/home/src/project/tsconfig.json": codeFixesConfig,
+				"/home/src/project/index.ts":      testCase.text,
+			})
+			l := p.languageService("/home/src/project/index.ts")
+			fix := l.ProvideFixAll("/home/src/project/index.ts", testCase.fixID)
+			assert.Assert(t, fix != nil)
+			assert.Equal(t, fix.FixID, testCase.fixID)
+			assert.Equal(t, fix.Title, ls.GetFixAllDescription(testCase.fixID))
+			assert.Equal(t, applyChanges(testCase.text, fix.Changes["/home/src/project/index.ts"]), testCase.expected)
+		})
+	}
+
+	t.Run("nothing to fix", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/tsconfig.json": codeFixesConfig,
+			"/home/src/project/index.ts":      `export const x = 1;`,
+		})
+		l := p.languageService("/home/src/project/index.ts")
+		assert.Assert(t, l.ProvideFixAll("/home/src/project/index.ts", "fixSpelling") == nil)
+		assert.Assert(t, l.ProvideFixAll("/home/src/project/index.ts", "unknownFix") == nil)
+	})
+
+	t.Run("source fix all", func(t *testing.T) {
+		t.Parallel()
+		text := `interface Shape {
+    name: string;
+}
+class Base {
+    run() {}
+}
+class Square extends Base implements Shape {
+    run() {}
+}
+export async function f(p: Promise<number>) {
+    const unused = 1;
+    return p + 1;
+}`
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/tsconfig.json": codeFixesConfig,
+			"/home/src/project/index.ts":      text,
+		})
+		l := p.languageService("/home/src/project/index.ts")
+		changes := l.ProvideSourceFixAll("/home/src/project/index.ts")
+		// Unused declarations are not deleted, since that is not always what the user wants
+		assert.Equal(t, applyChanges(text, changes["/home/src/project/index.ts"]), `interface Shape {
+    name: string;
+}
+class Base {
+    run() {}
+}
+class Square extends Base implements Shape {
+    override run() {}
+    name: string;
+}
+export async function f(p: Promise<number>) {
+    const unused = 1;
+    return await p + 1;
+}`)
+	})
+}
@@ -0,0 +1,795 @@
+package ls
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/astnav"
+	"github.com/microsoft/typescript-go/internal/checker"
+	"github.com/microsoft/typescript-go/internal/compiler"
+	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"github.com/microsoft/typescript-go/internal/scanner"
+)
+
+// CompletionItemData is attached to every completion item so that completionItem/resolve
+// can recompute the entry that the item was created from.
+type CompletionItemData struct {
+	FileName string `json:"fileName"`
+	Position int    `json:"position"`
+	Name     string `json:"name"`
+	// AutoImport is set for the exports of other modules, which accepting the item imports.
+	AutoImport *AutoImportData `json:"autoImport,omitempty"`
+}
+
+type AutoImportData struct {
+	ModuleSpecifier string `json:"moduleSpecifier"`
+	// ModuleFileName is the file of the exporting module, or "" for an ambient module.
+	ModuleFileName string `json:"moduleFileName,omitempty"`
+	IsDefault      bool   `json:"isDefault,omitempty"`
+}
+
+// Sort texts, from the original SortText namespace. Lower sorts first.
+const (
+	sortTextLocalDeclarationPriority = "10"
+	sortTextLocationPriority         = "11"
+	sortTextOptionalMember           = "12"
+	sortTextGlobalsOrKeywords        = "15"
+	sortTextAutoImportSuggestions    = "16"
+)
+
+// maxAutoImportCompletions limits the exports of other modules that are offered at once. The list
+// is marked incomplete when it is cut off, so that the client asks again as the name is typed.
+const maxAutoImportCompletions = 100
+
+type completionKind int
+
+const (
+	completionKindNone completionKind = iota
+	completionKindGlobal
+	completionKindMemberLike
+	completionKindJsxAttributes
+	completionKindString
+	completionKindPath
+)
+
+type completionData struct {
+	kind           completionKind
+	location       *ast.Node
+	symbols        []*ast.Symbol
+	keywords       []ast.Kind
+	isTypeLocation bool
+	// memberAccess is the property access or qualified name for member completions.
+	memberAccess *ast.Node
+	strings      []string
+	paths        []pathCompletion
+	// replacementSpan is the range replaced by string and path completions.
+	replacementSpan core.TextRange
+}
+
+func (l *LanguageService) ProvideCompletions(fileName string, position int, context *lsproto.CompletionContext) *lsproto.CompletionList {
+	program, file := l.getProgramAndFile(fileName)
+	data := getCompletionData(program, l.GetTypeChecker(), file, position)
+	if data == nil || !isValidTrigger(data, context) {
+		return nil
+	}
+
+	c := l.GetTypeChecker()
+	items := make([]lsproto.CompletionItem, 0, len(data.symbols)+len(data.keywords)+len(data.strings)+len(data.paths))
+	newItemData := func(name string) *lsproto.LSPAny {
+		return ptrTo[any](&CompletionItemData{FileName: fileName, Position: position, Name: name})
+	}
+
+	seen := make(map[string]struct{}, len(data.symbols))
+	for _, symbol := range data.symbols {
+		name := symbol.Name
+		if strings.HasPrefix(name, ast.InternalSymbolNamePrefix) {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+
+		kind := getSymbolKind(symbol, data.location)
+		item := lsproto.CompletionItem{
+			Label:    name,
+			Kind:     ptrTo(getCompletionItemKind(kind)),
+			SortText: ptrTo(getSymbolSortText(c, file, data, symbol)),
+			Data:     newItemData(name),
+		}
+		if data.kind == completionKindMemberLike && !scanner.IsIdentifierText(name, file.LanguageVersion) {
+			if edit := l.getBracketAccessEdit(file, data.memberAccess, position, name); edit != nil {
+				item.FilterText = ptrTo("." + name)
+				item.TextEdit = &lsproto.TextEditOrInsertReplaceEdit{TextEdit: edit}
+			}
+		}
+		items = append(items, item)
+	}
+
+	for _, keyword := range data.keywords {
+		name := scanner.TokenToString(keyword)
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		items = append(items, lsproto.CompletionItem{
+			Label:    name,
+			Kind:     ptrTo(lsproto.CompletionItemKindKeyword),
+			SortText: ptrTo(sortTextGlobalsOrKeywords),
+		})
+	}
+
+	for _, value := range data.strings {
+		items = append(items, lsproto.CompletionItem{
+			Label:    value,
+			Kind:     ptrTo(getCompletionItemKind(ScriptElementKindString)),
+			SortText: ptrTo(sortTextLocationPriority),
+			TextEdit: l.getReplacementEdit(file, data.replacementSpan, value),
+		})
+	}
+
+	for _, path := range data.paths {
+		items = append(items, lsproto.CompletionItem{
+			Label:    path.name,
+			Kind:     ptrTo(getCompletionItemKind(path.kind)),
+			SortText: ptrTo(sortTextLocationPriority),
+			TextEdit: l.getReplacementEdit(file, data.replacementSpan, path.name),
+		})
+	}
+
+	isIncomplete := false
+	if data.kind == completionKindGlobal && canAddImports(program, file) {
+		items, isIncomplete = l.appendAutoImportCompletions(items, file, data, position, seen)
+	}
+
+	return &lsproto.CompletionList{
+		IsIncomplete: isIncomplete,
+		Items:        items,
+	}
+}
+
+// appendAutoImportCompletions offers the exports of other modules whose names match the
+// identifier being typed and are not already in scope. It reports whether the list was cut off.
+func (l *LanguageService) appendAutoImportCompletions(items []lsproto.CompletionItem, file *ast.SourceFile, data *completionData, position int, seen map[string]struct{}) ([]lsproto.CompletionItem, bool) {
+	prefix := getCompletionPrefix(file, position)
+	if prefix == "" {
+		return items, false
+	}
+	lowerPrefix := strings.ToLower(prefix)
+	exports := l.getExportInfoMap()
+	preferences := l.host.GetUserPreferences()
+	count := 0
+	isIncomplete := false
+	type offeredImport struct {
+		name      string
+		candidate importCandidate
+	}
+	offered := make(map[offeredImport]struct{})
+	exports.forEachExport(file, func(info *exportInfo) {
+		if isIncomplete {
+			return
+		}
+		if _, ok := seen[info.name]; ok {
+			return
+		}
+		lowerName := strings.ToLower(info.name)
+		if lowerName[0] != lowerPrefix[0] || !isSubsequence(lowerPrefix, lowerName) {
+			return
+		}
+		candidate, ok := exports.getImportCandidate(file, info, preferences)
+		if !ok {
+			return
+		}
+		key := offeredImport{name: info.name, candidate: candidate}
+		if _, ok := offered[key]; ok {
+			return
+		}
+		offered[key] = struct{}{}
+		if count == maxAutoImportCompletions {
+			isIncomplete = true
+			return
+		}
+		count++
+		items = append(items, lsproto.CompletionItem{
+			Label:        info.name,
+			LabelDetails: &lsproto.CompletionItemLabelDetails{Description: ptrTo(candidate.moduleSpecifier)},
+			Kind:         ptrTo(getCompletionItemKind(getSymbolKind(info.symbol, data.location))),
+			SortText:     ptrTo(sortTextAutoImportSuggestions),
+			Data: ptrTo[any](&CompletionItemData{
+				FileName: file.FileName(),
+				Position: position,
+				Name:     info.name,
+				AutoImport: &AutoImportData{
+					ModuleSpecifier: candidate.moduleSpecifier,
+					ModuleFileName:  info.moduleFileName,
+					IsDefault:       candidate.isDefault,
+				},
+			}),
+		})
+	})
+	return items, isIncomplete
+}
+
+// getCompletionPrefix returns the part of the identifier before position that is being completed.
+func getCompletionPrefix(file *ast.SourceFile, position int) string {
+	token := astnav.FindPrecedingToken(file, position)
+	if token == nil || !ast.IsIdentifier(token) || position > token.End() {
+		return ""
+	}
+	start := scanner.GetTokenPosOfNode(token, file, false /*includeJSDoc*/)
+	return file.Text()[start:position]
+}
+
+// ResolveCompletionItem fills in the detail and documentation of a completion item
+// produced by ProvideCompletions.
+func (l *LanguageService) ResolveCompletionItem(item *lsproto.CompletionItem, data *CompletionItemData) *lsproto.CompletionItem {
+	program, file := l.tryGetProgramAndFile(data.FileName)
+	if file == nil {
+		return item
+	}
+	completionData := getCompletionData(program, l.GetTypeChecker(), file, data.Position)
+	if completionData == nil {
+		return item
+	}
+	if data.AutoImport != nil {
+		return l.resolveAutoImportCompletionItem(item, program, file, completionData, data)
+	}
+	index := slices.IndexFunc(completionData.symbols, func(symbol *ast.Symbol) bool {
+		return symbol.Name == data.Name
+	})
+	if index < 0 {
+		return item
+	}
+
+	symbol := completionData.symbols[index]
+	quickInfo := getQuickInfo(l.GetTypeChecker(), symbol, completionData.location)
+	item.Detail = ptrTo(quickInfo.DisplayText)
+	if documentation := quickInfo.Documentation; documentation != "" {
+		item.Documentation = &lsproto.StringOrMarkupContent{
+			MarkupContent: &lsproto.MarkupContent{
+				Kind:  lsproto.MarkupKindMarkdown,
+				Value: documentation,
+			},
+		}
+	}
+	return item
+}
+
+// resolveAutoImportCompletionItem describes the export that an auto-import item refers to, and
+// adds the edit that imports it.
+func (l *LanguageService) resolveAutoImportCompletionItem(item *lsproto.CompletionItem, program *compiler.Program, file *ast.SourceFile, completionData *completionData, data *CompletionItemData) *lsproto.CompletionItem {
+	var export *exportInfo
+	l.getExportInfoMap().forEachExport(file, func(info *exportInfo) {
+		if export == nil && info.name == data.Name && info.moduleFileName == data.AutoImport.ModuleFileName &&
+			(info.kind != exportKindNamed) == data.AutoImport.IsDefault {
+			export = info
+		}
+	})
+	if export == nil {
+		return item
+	}
+
+	candidate := importCandidate{moduleSpecifier: data.AutoImport.ModuleSpecifier, isDefault: data.AutoImport.IsDefault}
+	change, _ := getAddImportChange(file, data.Name, candidate, l.host.NewLine(), l.host.GetUserPreferences().QuotePreference)
+	if lspRange, err := l.converters.ToLSPRange(file.FileName(), change.TextRange); err == nil {
+		item.AdditionalTextEdits = &[]lsproto.TextEdit{{Range: lspRange, NewText: change.NewText}}
+	}
+
+	quickInfo := getQuickInfo(l.GetTypeChecker(), export.symbol, completionData.location)
+	item.Detail = ptrTo(diagnostics.Add_import_from_0.Format(data.AutoImport.ModuleSpecifier) + "\n" + quickInfo.DisplayText)
+	if documentation := quickInfo.Documentation; documentation != "" {
+		item.Documentation = &lsproto.StringOrMarkupContent{
+			MarkupContent: &lsproto.MarkupContent{
+				Kind:  lsproto.MarkupKindMarkdown,
+				Value: documentation,
+			},
+		}
+	}
+	return item
+}
+
+// GetCompletionItemData decodes the data that ProvideCompletions attached to an item.
+// After a round trip through the client the data is a generic JSON value.
+func GetCompletionItemData(item *lsproto.CompletionItem) (*CompletionItemData, error) {
+	if item.Data == nil {
+		return nil, fmt.Errorf("completion item %q has no data", item.Label)
+	}
+	raw, err := json.Marshal(*item.Data)
+	if err != nil {
+		return nil, err
+	}
+	var data CompletionItemData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("invalid completion item data: %w", err)
+	}
+	return &data, nil
+}
+
+func isValidTrigger(data *completionData, context *lsproto.CompletionContext) bool {
+	if context == nil || context.TriggerCharacter == nil {
+		return true
+	}
+	switch *context.TriggerCharacter {
+	case ".":
+		return data.kind == completionKindMemberLike
+	case "\"", "'", "`":
+		return data.kind == completionKindString || data.kind == completionKindPath
+	case "/":
+		return data.kind == completionKindPath
+	case "<":
+		// Opening a JSX element or type argument list; only global completions apply.
+		return data.kind == completionKindGlobal
+	case " ":
+		return data.kind == completionKindJsxAttributes
+	}
+	return true
+}
+
+func getCompletionData(program *compiler.Program, c *checker.Checker, file *ast.SourceFile, position int) *completionData {
+	if isInComment(file, position) {
+		return nil
+	}
+
+	// The decision to provide completion depends on the contextToken, which is determined through the previousToken.
+	// Note: 'previousToken' (and thus 'contextToken') can be nil if we are at the beginning of the file.
+	previousToken := astnav.FindPrecedingToken(file, position)
+	contextToken := previousToken
+
+	// Check if the caret is at the end of an identifier; this is a partial identifier that we want to complete: e.g. a.toS|
+	// Skip this partial identifier and adjust the contextToken to the token that precedes it.
+	if previousToken != nil && position <= previousToken.End() && (ast.IsMemberName(previousToken) || ast.IsKeywordKind(previousToken.Kind)) {
+		contextToken = astnav.FindPrecedingToken(file, scanner.GetTokenPosOfNode(previousToken, file, false /*includeJSDoc*/))
+	}
+
+	if previousToken != nil && ast.IsStringLiteralLike(previousToken) && isPositionInsideStringLiteral(file, previousToken, position) {
+		return getStringLiteralCompletionData(program, c, file, previousToken, position)
+	}
+
+	if isCompletionListBlocker(file, contextToken, previousToken, position) {
+		return nil
+	}
+
+	if contextToken != nil {
+		if access := getMemberAccessOfDot(contextToken); access != nil {
+			return getMemberCompletionData(c, access)
+		}
+		if element := getJsxElementForAttributes(contextToken, position); element != nil {
+			return getJsxAttributeCompletionData(c, element, previousToken, position)
+		}
+	}
+
+	return getGlobalCompletionData(c, file, contextToken, previousToken, position)
+}
+
+func isInComment(file *ast.SourceFile, position int) bool {
+	token := astnav.GetTokenAtPosition(file, position)
+	text := file.Text()
+	for comment := range scanner.GetLeadingCommentRanges(&ast.NodeFactory{}, text, token.Pos()) {
+		if comment.Pos() < position && position < comment.End() {
+			return true
+		}
+		// The end of a single-line comment, or of an unterminated multi-line comment, is still in the comment.
+		if position == comment.End() && (comment.Kind == ast.KindSingleLineCommentTrivia || !strings.HasSuffix(text[comment.Pos():comment.End()], "*/")) {
+			return true
+		}
+	}
+	return false
+}
+
+func isPositionInsideStringLiteral(file *ast.SourceFile, literal *ast.Node, position int) bool {
+	start := scanner.GetTokenPosOfNode(literal, file, false /*includeJSDoc*/)
+	end := literal.End()
+	if position <= start || position > end {
+		return false
+	}
+	if position < end {
+		return true
+	}
+	// At the end of the literal we are only inside of it if it is unterminated.
+	text := file.Text()
+	return end-start < 2 || text[end-1] != text[start]
+}
+
+func isCompletionListBlocker(file *ast.SourceFile, contextToken *ast.Node, previousToken *ast.Node, position int) bool {
+	if previousToken != nil && position <= previousToken.End() {
+		switch {
+		case previousToken.Kind == ast.KindNumericLiteral, previousToken.Kind == ast.KindBigIntLiteral:
+			// `1.` is a numeric literal and not a property access.
+			return true
+		case previousToken.Kind == ast.KindRegularExpressionLiteral, previousToken.Kind == ast.KindTemplateHead,
+			previousToken.Kind == ast.KindTemplateMiddle, previousToken.Kind == ast.KindTemplateTail:
+			return position > scanner.GetTokenPosOfNode(previousToken, file, false /*includeJSDoc*/)
+		case ast.IsIdentifier(previousToken) && isNewIdentifierDeclarationName(previousToken):
+			return true
+		}
+	}
+	if contextToken == nil {
+		return false
+	}
+	switch contextToken.Kind {
+	case ast.KindVarKeyword, ast.KindLetKeyword, ast.KindConstKeyword, ast.KindFunctionKeyword, ast.KindClassKeyword,
+		ast.KindInterfaceKeyword, ast.KindTypeKeyword, ast.KindEnumKeyword, ast.KindNamespaceKeyword, ast.KindModuleKeyword:
+		// The next token names a new declaration.
+		return contextToken.Parent != nil && !ast.IsExpressionStatement(contextToken.Parent) && !ast.IsIdentifier(contextToken)
+	}
+	return false
+}
+
+// isNewIdentifierDeclarationName reports whether a name is the name of the declaration it belongs to,
+// so that typing it introduces a new name rather than referring to an existing one.
+func isNewIdentifierDeclarationName(name *ast.Node) bool {
+	parent := name.Parent
+	if parent == nil || parent.Name() != name {
+		return false
+	}
+	switch parent.Kind {
+	case ast.KindVariableDeclaration, ast.KindFunctionDeclaration, ast.KindClassDeclaration, ast.KindInterfaceDeclaration,
+		ast.KindTypeAliasDeclaration, ast.KindEnumDeclaration, ast.KindModuleDeclaration, ast.KindParameter,
+		ast.KindTypeParameter, ast.KindMethodDeclaration, ast.KindPropertyDeclaration, ast.KindGetAccessor, ast.KindSetAccessor:
+		return true
+	}
+	return false
+}
+
+func getMemberAccessOfDot(contextToken *ast.Node) *ast.Node {
+	if contextToken.Kind != ast.KindDotToken && contextToken.Kind != ast.KindQuestionDotToken {
+		return nil
+	}
+	if parent := contextToken.Parent; parent != nil && (ast.IsPropertyAccessExpression(parent) || ast.IsQualifiedName(parent)) {
+		return parent
+	}
+	return nil
+}
+
+func getMemberCompletionData(c *checker.Checker, access *ast.Node) *completionData {
+	var left *ast.Node
+	if ast.IsQualifiedName(access) {
+		left = access.AsQualifiedName().Left
+	} else {
+		left = access.Expression()
+	}
+	isTypeLocation := ast.IsQualifiedName(access) && ast.IsPartOfTypeNode(access)
+
+	var symbols []*ast.Symbol
+	if symbol := c.GetSymbolAtLocation(left); symbol != nil {
+		symbol = skipAlias(c, symbol)
+		if symbol.Flags&ast.SymbolFlagsModule != 0 {
+			for _, export := range c.GetExportsOfModule(symbol) {
+				if isTypeLocation && symbolCanBeReferencedAtTypeLocation(c, export) ||
+					!isTypeLocation && symbolCanBeReferencedAtValueLocation(c, export) {
+					symbols = append(symbols, export)
+				}
+			}
+		}
+	}
+
+	if !isTypeLocation {
+		if t := c.GetTypeAtLocation(left); t != nil {
+			t = c.GetNonNullableType(t)
+			for _, property := range c.GetPropertiesOfType(c.GetApparentType(t)) {
+				if c.IsValidPropertyAccessForCompletions(access, t, property) {
+					symbols = append(symbols, property)
+				}
+			}
+		}
+	}
+
+	return &completionData{
+		kind:           completionKindMemberLike,
+		location:       access,
+		symbols:        symbols,
+		isTypeLocation: isTypeLocation,
+		memberAccess:   access,
+	}
+}
+
+// getJsxElementForAttributes returns the JSX opening or self-closing element whose attribute list
+// the context token ends in, that is after its tag name or after one of its attributes.
+func getJsxElementForAttributes(contextToken *ast.Node, position int) *ast.Node {
+	if contextToken.Kind == ast.KindGreaterThanToken || contextToken.Kind == ast.KindSlashToken || contextToken.Kind == ast.KindEqualsToken {
+		return nil
+	}
+	element := ast.FindAncestor(contextToken, ast.IsJsxOpeningLikeElement)
+	if element == nil || position > element.End() {
+		return nil
+	}
+	if contextToken.End() <= element.TagName().End() {
+		return element
+	}
+	for _, attribute := range element.Attributes().AsJsxAttributes().Properties.Nodes {
+		if contextToken.End() == attribute.End() {
+			return element
+		}
+	}
+	return nil
+}
+
+func getJsxAttributeCompletionData(c *checker.Checker, element *ast.Node, previousToken *ast.Node, position int) *completionData {
+	attributes := element.Attributes()
+	existing := make(map[string]struct{})
+	for _, attribute := range attributes.AsJsxAttributes().Properties.Nodes {
+		// The attribute that is currently being typed does not count as existing.
+		if ast.IsJsxAttribute(attribute) && !(previousToken != nil && attribute.Name() == previousToken && position <= previousToken.End()) {
+			existing[attribute.Name().Text()] = struct{}{}
+		}
+	}
+
+	var symbols []*ast.Symbol
+	if t := c.GetContextualType(attributes, checker.ContextFlagsCompletions); t != nil {
+		for _, property := range c.GetPropertiesOfType(c.GetApparentType(t)) {
+			if _, ok := existing[property.Name]; !ok {
+				symbols = append(symbols, property)
+			}
+		}
+	}
+
+	return &completionData{
+		kind:     completionKindJsxAttributes,
+		location: attributes,
+		symbols:  symbols,
+	}
+}
+
+func getGlobalCompletionData(c *checker.Checker, file *ast.SourceFile, contextToken *ast.Node, previousToken *ast.Node, position int) *completionData {
+	scopeNode := astnav.GetTokenAtPosition(file, position)
+	if previousToken != nil && position <= previousToken.End() && ast.IsIdentifier(previousToken) {
+		scopeNode = previousToken
+	}
+
+	isTypeLocation := isTypeLocationForCompletions(contextToken, previousToken, position)
+	var symbols []*ast.Symbol
+	for _, symbol := range c.GetSymbolsInScope(scopeNode, ast.SymbolFlagsType|ast.SymbolFlagsValue|ast.SymbolFlagsNamespace|ast.SymbolFlagsAlias) {
+		if isTypeLocation && symbolCanBeReferencedAtTypeLocation(c, symbol) ||
+			!isTypeLocation && symbolCanBeReferencedAtValueLocation(c, symbol) {
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	keywords := valueKeywords
+	if isTypeLocation {
+		keywords = typeKeywords
+	}
+
+	return &completionData{
+		kind:           completionKindGlobal,
+		location:       scopeNode,
+		symbols:        symbols,
+		keywords:       keywords,
+		isTypeLocation: isTypeLocation,
+	}
+}
+
+func isTypeLocationForCompletions(contextToken *ast.Node, previousToken *ast.Node, position int) bool {
+	if previousToken != nil && position <= previousToken.End() && ast.IsIdentifier(previousToken) && ast.IsPartOfTypeNode(previousToken) {
+		return !ast.IsPartOfTypeQuery(previousToken)
+	}
+	if contextToken == nil || contextToken.Parent == nil {
+		return false
+	}
+	parent := contextToken.Parent
+	switch contextToken.Kind {
+	case ast.KindColonToken:
+		switch parent.Kind {
+		case ast.KindParameter, ast.KindVariableDeclaration, ast.KindPropertyDeclaration, ast.KindPropertySignature, ast.KindIndexSignature:
+			return true
+		}
+		return ast.IsFunctionLike(parent)
+	case ast.KindAsKeyword:
+		return ast.IsAsExpression(parent)
+	case ast.KindSatisfiesKeyword:
+		return ast.IsSatisfiesExpression(parent)
+	case ast.KindLessThanToken, ast.KindCommaToken:
+		switch parent.Kind {
+		case ast.KindTypeReference, ast.KindExpressionWithTypeArguments, ast.KindTupleType:
+			return true
+		}
+	case ast.KindBarToken, ast.KindAmpersandToken:
+		return parent.Kind == ast.KindUnionType || parent.Kind == ast.KindIntersectionType
+	case ast.KindEqualsToken:
+		return parent.Kind == ast.KindTypeAliasDeclaration || parent.Kind == ast.KindTypeParameter
+	case ast.KindExtendsKeyword:
+		switch parent.Kind {
+		case ast.KindTypeParameter, ast.KindConditionalType:
+			return true
+		case ast.KindHeritageClause:
+			return parent.Parent != nil && parent.Parent.Kind == ast.KindInterfaceDeclaration
+		}
+	case ast.KindImplementsKeyword:
+		return parent.Kind == ast.KindHeritageClause
+	case ast.KindKeyOfKeyword, ast.KindUniqueKeyword, ast.KindReadonlyKeyword:
+		return parent.Kind == ast.KindTypeOperator
+	case ast.KindOpenBracketToken:
+		return parent.Kind == ast.KindTupleType || parent.Kind == ast.KindIndexedAccessType
+	case ast.KindQuestionToken:
+		return parent.Kind == ast.KindConditionalType
+	case ast.KindOpenParenToken:
+		return parent.Kind == ast.KindParenthesizedType
+	}
+	return false
+}
+
+func skipAlias(c *checker.Checker, symbol *ast.Symbol) *ast.Symbol {
+	if symbol.Flags&ast.SymbolFlagsAlias != 0 {
+		if resolved, ok := c.ResolveAlias(symbol); ok && resolved != nil {
+			return resolved
+		}
+	}
+	return symbol
+}
+
+func symbolCanBeReferencedAtTypeLocation(c *checker.Checker, symbol *ast.Symbol) bool {
+	return skipAlias(c, c.GetExportSymbolOfSymbol(symbol)).Flags&(ast.SymbolFlagsType|ast.SymbolFlagsNamespace) != 0
+}
+
+func symbolCanBeReferencedAtValueLocation(c *checker.Checker, symbol *ast.Symbol) bool {
+	return skipAlias(c, c.GetExportSymbolOfSymbol(symbol)).Flags&(ast.SymbolFlagsValue|ast.SymbolFlagsNamespace) != 0
+}
+
+func getSymbolSortText(c *checker.Checker, file *ast.SourceFile, data *completionData, symbol *ast.Symbol) string {
+	switch data.kind {
+	case completionKindMemberLike, completionKindJsxAttributes:
+		if symbol.Flags&ast.SymbolFlagsOptional != 0 {
+			return sortTextOptionalMember
+		}
+		return sortTextLocationPriority
+	}
+	if symbol.ValueDeclaration != nil && ast.GetSourceFileOfNode(symbol.ValueDeclaration) == file && ast.IsParameter(symbol.ValueDeclaration) {
+		return sortTextLocalDeclarationPriority
+	}
+	for _, decl := range symbol.Declarations {
+		if ast.GetSourceFileOfNode(decl) == file {
+			return sortTextLocationPriority
+		}
+	}
+	return sortTextGlobalsOrKeywords
+}
+
+func getCompletionItemKind(kind ScriptElementKind) lsproto.CompletionItemKind {
+	switch kind {
+	case ScriptElementKindPrimitiveType, ScriptElementKindKeyword:
+		return lsproto.CompletionItemKindKeyword
+	case ScriptElementKindConstElement, ScriptElementKindLetElement, ScriptElementKindVariableElement,
+		ScriptElementKindLocalVariableElement, ScriptElementKindAlias, ScriptElementKindParameterElement,
+		ScriptElementKindVariableUsingElement, ScriptElementKindVariableAwaitUsing:
+		return lsproto.CompletionItemKindVariable
+	case ScriptElementKindMemberVariableElement, ScriptElementKindMemberGetAccessor, ScriptElementKindMemberSetAccessor,
+		ScriptElementKindMemberAccessorVariable:
+		return lsproto.CompletionItemKindField
+	case ScriptElementKindFunctionElement, ScriptElementKindLocalFunctionElement:
+		return lsproto.CompletionItemKindFunction
+	case ScriptElementKindMemberFunctionElement, ScriptElementKindConstructSignature, ScriptElementKindCallSignatureElement,
+		ScriptElementKindIndexSignatureElement:
+		return lsproto.CompletionItemKindMethod
+	case ScriptElementKindEnumElement:
+		return lsproto.CompletionItemKindEnum
+	case ScriptElementKindEnumMemberElement:
+		return lsproto.CompletionItemKindEnumMember
+	case ScriptElementKindModuleElement, ScriptElementKindExternalModuleName:
+		return lsproto.CompletionItemKindModule
+	case ScriptElementKindClassElement, ScriptElementKindTypeElement, ScriptElementKindLocalClassElement:
+		return lsproto.CompletionItemKindClass
+	case ScriptElementKindInterfaceElement:
+		return lsproto.CompletionItemKindInterface
+	case ScriptElementKindWarning:
+		return lsproto.CompletionItemKindText
+	case ScriptElementKindScriptElement:
+		return lsproto.CompletionItemKindFile
+	case ScriptElementKindDirectory:
+		return lsproto.CompletionItemKindFolder
+	case ScriptElementKindString:
+		return lsproto.CompletionItemKindConstant
+	case ScriptElementKindTypeParameterElement:
+		return lsproto.CompletionItemKindTypeParameter
+	case ScriptElementKindConstructorImplElement:
+		return lsproto.CompletionItemKindConstructor
+	case ScriptElementKindJsxAttribute:
+		return lsproto.CompletionItemKindField
+	}
+	return lsproto.CompletionItemKindProperty
+}
+
+// getBracketAccessEdit rewrites `obj.|` into `obj["name"]` for names that are not identifiers.
+func (l *LanguageService) getBracketAccessEdit(file *ast.SourceFile, access *ast.Node, position int, name string) *lsproto.TextEdit {
+	if !ast.IsPropertyAccessExpression(access) {
+		return nil
+	}
+	dotStart := access.Expression().End()
+	newText := fmt.Sprintf("[%q]", name)
+	if access.AsPropertyAccessExpression().QuestionDotToken != nil {
+		newText = "?." + newText
+	}
+	return l.getReplacementEdit(file, core.NewTextRange(scanner.SkipTrivia(file.Text(), dotStart), position), newText).TextEdit
+}
+
+func (l *LanguageService) getReplacementEdit(file *ast.SourceFile, span core.TextRange, newText string) *lsproto.TextEditOrInsertReplaceEdit {
+	lspRange, err := l.converters.ToLSPRange(file.FileName(), span)
+	if err != nil {
+		return nil
+	}
+	return &lsproto.TextEditOrInsertReplaceEdit{
+		TextEdit: &lsproto.TextEdit{
+			Range:   lspRange,
+			NewText: newText,
+		},
+	}
+}
+
+var typeKeywords = []ast.Kind{
+	ast.KindAnyKeyword,
+	ast.KindAssertsKeyword,
+	ast.KindBigIntKeyword,
+	ast.KindBooleanKeyword,
+	ast.KindFalseKeyword,
+	ast.KindInferKeyword,
+	ast.KindKeyOfKeyword,
+	ast.KindNeverKeyword,
+	ast.KindNullKeyword,
+	ast.KindNumberKeyword,
+	ast.KindObjectKeyword,
+	ast.KindReadonlyKeyword,
+	ast.KindStringKeyword,
+	ast.KindSymbolKeyword,
+	ast.KindTypeOfKeyword,
+	ast.KindTrueKeyword,
+	ast.KindVoidKeyword,
+	ast.KindUndefinedKeyword,
+	ast.KindUniqueKeyword,
+	ast.KindUnknownKeyword,
+}
+
+var valueKeywords = []ast.Kind{
+	ast.KindAbstractKeyword,
+	ast.KindAsyncKeyword,
+	ast.KindAwaitKeyword,
+	ast.KindBreakKeyword,
+	ast.KindCaseKeyword,
+	ast.KindCatchKeyword,
+	ast.KindClassKeyword,
+	ast.KindConstKeyword,
+	ast.KindContinueKeyword,
+	ast.KindDebuggerKeyword,
+	ast.KindDeclareKeyword,
+	ast.KindDefaultKeyword,
+	ast.KindDeleteKeyword,
+	ast.KindDoKeyword,
+	ast.KindElseKeyword,
+	ast.KindEnumKeyword,
+	ast.KindExportKeyword,
+	ast.KindExtendsKeyword,
+	ast.KindFalseKeyword,
+	ast.KindFinallyKeyword,
+	ast.KindForKeyword,
+	ast.KindFunctionKeyword,
+	ast.KindIfKeyword,
+	ast.KindImplementsKeyword,
+	ast.KindImportKeyword,
+	ast.KindInKeyword,
+	ast.KindInstanceOfKeyword,
+	ast.KindInterfaceKeyword,
+	ast.KindLetKeyword,
+	ast.KindModuleKeyword,
+	ast.KindNamespaceKeyword,
+	ast.KindNewKeyword,
+	ast.KindNullKeyword,
+	ast.KindReturnKeyword,
+	ast.KindSatisfiesKeyword,
+	ast.KindSuperKeyword,
+	ast.KindSwitchKeyword,
+	ast.KindThisKeyword,
+	ast.KindThrowKeyword,
+	ast.KindTrueKeyword,
+	ast.KindTryKeyword,
+	ast.KindTypeKeyword,
+	ast.KindTypeOfKeyword,
+	ast.KindUsingKeyword,
+	ast.KindVarKeyword,
+	ast.KindVoidKeyword,
+	ast.KindWhileKeyword,
+	ast.KindWithKeyword,
+	ast.KindYieldKeyword,
+}
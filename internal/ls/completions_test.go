@@ -0,0 +1,208 @@
+package ls_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/microsoft/typescript-go/internal/ls"
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"gotest.tools/v3/assert"
+)
+
+const completionsConfig = `{ "compilerOptions": { "strict": true, "jsx": "preserve", "module": "esnext", "moduleResolution": "bundler" } }`
+
+func completionLabels(list *lsproto.CompletionList) []string {
+	if list == nil {
+		return nil
+	}
+	labels := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		labels = append(labels, item.Label)
+	}
+	return labels
+}
+
+func findCompletionItem(t *testing.T, list *lsproto.CompletionList, label string) *lsproto.CompletionItem {
+	t.Helper()
+	assert.Assert(t, list != nil)
+	index := slices.IndexFunc(list.Items, func(item lsproto.CompletionItem) bool { return item.Label == label })
+	assert.Assert(t, index >= 0, "no completion %s in %v", label, completionLabels(list))
+	return &list.Items[index]
+}
+
+func triggerCharacter(character string) *lsproto.CompletionContext {
+	return &lsproto.CompletionContext{
+		TriggerKind:      lsproto.CompletionTriggerKindTriggerCharacter,
+		TriggerCharacter: &character,
+	}
+}
+
+func TestCompletions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("member completions", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/tsconfig.json": completionsConfig,
+			"/home/src/project/index.ts": `interface Point { x: number; y?: number; "needs-quotes": string; }
+declare const point: Point;
+point./*1*/`,
+		})
+		l, m := p.languageServiceAt("1")
+		list := l.ProvideCompletions(m.fileName, m.position, triggerCharacter("."))
+		assert.DeepEqual(t, completionLabels(list), []string{"x", "y", "needs-quotes"})
+		assert.Equal(t, *findCompletionItem(t, list, "x").Kind, lsproto.CompletionItemKindField)
+		// Optional members sort after the required ones
+		assert.Assert(t, *findCompletionItem(t, list, "x").SortText < *findCompletionItem(t, list, "y").SortText)
+		// Names that are not identifiers are accessed with brackets
+		quoted := findCompletionItem(t, list, "needs-quotes")
+		assert.Assert(t, quoted.TextEdit != nil && quoted.TextEdit.TextEdit != nil)
+		assert.Equal(t, quoted.TextEdit.TextEdit.NewText, `["needs-quotes"]`)
+	})
+
+	t.Run("member completions are not triggered outside member accesses", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/tsconfig.json": completionsConfig,
+			"/home/src/project/index.ts":      `const a = 1.5/*1*/`,
+		})
+		l, m := p.languageServiceAt("1")
+		assert.Assert(t, l.ProvideCompletions(m.fileName, m.position, triggerCharacter(".")) == nil)
+	})
+
+	t.Run("global and keyword completions", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/tsconfig.json": completionsConfig,
+			"/home/src/project/index.ts": `const local = 1;
+function f(parameter: string) {
+    /*1*/
+}`,
+		})
+		l, m := p.languageServiceAt("1")
+		list := l.ProvideCompletions(m.fileName, m.position, nil)
+		labels := completionLabels(list)
+		for _, label := range []string{"local", "parameter", "f", "Array", "return", "const"} {
+			assert.Assert(t, slices.Contains(labels, label), "no completion %s", label)
+		}
+		assert.Equal(t, *findCompletionItem(t, list, "return").Kind, lsproto.CompletionItemKindKeyword)
+		// Locals sort before globals and keywords
+		assert.Assert(t, *findCompletionItem(t, list, "parameter").SortText < *findCompletionItem(t, list, "Array").SortText)
+		assert.Assert(t, *findCompletionItem(t, list, "local").SortText < *findCompletionItem(t, list, "return").SortText)
+	})
+
+	t.Run("type completions", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/tsconfig.json": completionsConfig,
+			"/home/src/project/index.ts": `interface Shape {}
+const value = 1;
+let shape: /*1*/`,
+		})
+		l, m := p.languageServiceAt("1")
+		labels := completionLabels(l.ProvideCompletions(m.fileName, m.position, nil))
+		assert.Assert(t, slices.Contains(labels, "Shape"))
+		assert.Assert(t, slices.Contains(labels, "string"))
+		assert.Assert(t, !slices.Contains(labels, "value"))
+	})
+
+	t.Run("JSX attribute completions", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/tsconfig.json": completionsConfig,
+			"/home/src/project/index.tsx": `declare namespace JSX {
+    interface Element {}
+    interface IntrinsicElements {
+        button: { disabled?: boolean; label: string; onClick?: () => void };
+    }
+}
+const element = <button label="ok" /*1*/ />;`,
+		})
+		l, m := p.languageServiceAt("1")
+		list := l.ProvideCompletions(m.fileName, m.position, triggerCharacter(" "))
+		// Attributes that are already given are not offered again
+		assert.DeepEqual(t, completionLabels(list), []string{"disabled", "onClick"})
+	})
+
+	t.Run("string literal union completions", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/tsconfig.json": completionsConfig,
+			"/home/src/project/index.ts": `type Direction = "up" | "down" | "left";
+function move(direction: Direction) {}
+move("/*1*/");`,
+		})
+		l, m := p.languageServiceAt("1")
+		list := l.ProvideCompletions(m.fileName, m.position, triggerCharacter(`"`))
+		assert.DeepEqual(t, completionLabels(list), []string{"down", "left", "up"})
+		assert.Equal(t, *findCompletionItem(t, list, "up").Kind, lsproto.CompletionItemKindConstant)
+	})
+
+	t.Run("import path completions", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/tsconfig.json":                    completionsConfig,
+			"/home/src/project/src/index.ts":                     `import { a } from "./nested//*1*/";`,
+			"/home/src/project/src/nested/a.ts":                  `export const a = 1;`,
+			"/home/src/project/src/nested/b.ts":                  `export const b = 1;`,
+			"/home/src/project/src/nested/deeper/c.ts":           `export const c = 1;`,
+			"/home/src/project/src/other.ts":                     `import {} from "/*2*/";`,
+			"/home/src/project/node_modules/lodash/package.json": `{ "name": "lodash", "types": "index.d.ts" }`,
+			"/home/src/project/node_modules/lodash/index.d.ts":   `export declare function chunk(): void;`,
+		})
+		l, m := p.languageServiceAt("1")
+		list := l.ProvideCompletions(m.fileName, m.position, triggerCharacter("/"))
+		assert.DeepEqual(t, completionLabels(list), []string{"deeper", "a", "b"})
+		assert.Equal(t, *findCompletionItem(t, list, "a").Kind, lsproto.CompletionItemKindFile)
+		assert.Equal(t, *findCompletionItem(t, list, "deeper").Kind, lsproto.CompletionItemKindFolder)
+
+		l, m = p.languageServiceAt("2")
+		labels := completionLabels(l.ProvideCompletions(m.fileName, m.position, triggerCharacter(`"`)))
+		assert.Assert(t, slices.Contains(labels, "lodash"), "no completion lodash in %v", labels)
+	})
+
+	t.Run("auto-import completions", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/tsconfig.json": completionsConfig,
+			"/home/src/project/src/index.ts":  `const x = formatD/*1*/`,
+			"/home/src/project/src/format.ts": `/** Formats a date. */
+export function formatDate(date: Date): string { return ""; }`,
+		})
+		l, m := p.languageServiceAt("1")
+		list := l.ProvideCompletions(m.fileName, m.position, nil)
+		item := findCompletionItem(t, list, "formatDate")
+		assert.Assert(t, item.LabelDetails != nil && item.LabelDetails.Description != nil)
+		assert.Equal(t, *item.LabelDetails.Description, "./format")
+
+		data, err := ls.GetCompletionItemData(item)
+		assert.NilError(t, err)
+		assert.Equal(t, data.AutoImport.ModuleSpecifier, "./format")
+		resolved := l.ResolveCompletionItem(item, data)
+		assert.Assert(t, resolved.AdditionalTextEdits != nil)
+		assert.Equal(t, len(*resolved.AdditionalTextEdits), 1)
+		assert.Equal(t, (*resolved.AdditionalTextEdits)[0].NewText, "import { formatDate } from \"./format\";\n")
+		assert.Equal(t, *resolved.Detail, "Add import from \"./format\"\nfunction formatDate(date: Date): string")
+		assert.Equal(t, resolved.Documentation.MarkupContent.Value, "Formats a date.")
+	})
+
+	t.Run("resolve completion item", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/tsconfig.json": completionsConfig,
+			"/home/src/project/index.ts": `const config = {
+    /** The number of retries. */
+    retries: 3,
+};
+config./*1*/`,
+		})
+		l, m := p.languageServiceAt("1")
+		item := findCompletionItem(t, l.ProvideCompletions(m.fileName, m.position, nil), "retries")
+		assert.Assert(t, item.Detail == nil)
+		data, err := ls.GetCompletionItemData(item)
+		assert.NilError(t, err)
+		resolved := l.ResolveCompletionItem(item, data)
+		assert.Equal(t, *resolved.Detail, "(property) retries: number")
+		assert.Equal(t, resolved.Documentation.MarkupContent.Value, "The number of retries.")
+	})
+}
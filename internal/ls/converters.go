@@ -121,6 +121,17 @@ func (c *Converters) ToLSPDiagnostic(diagnostic *ast.Diagnostic) (lsproto.Diagno
 		})
 	}
 
+	var tags *[]lsproto.DiagnosticTag
+	if diagnostic.ReportsUnnecessary() || diagnostic.ReportsDeprecated() {
+		tags = &[]lsproto.DiagnosticTag{}
+		if diagnostic.ReportsUnnecessary() {
+			*tags = append(*tags, lsproto.DiagnosticTagUnnecessary)
+		}
+		if diagnostic.ReportsDeprecated() {
+			*tags = append(*tags, lsproto.DiagnosticTagDeprecated)
+		}
+	}
+
 	return lsproto.Diagnostic{
 		Range: textRange,
 		Code: &lsproto.IntegerOrString{
@@ -129,6 +140,7 @@ func (c *Converters) ToLSPDiagnostic(diagnostic *ast.Diagnostic) (lsproto.Diagno
 		Severity:           &severity,
 		Message:            diagnostic.Message(),
 		Source:             ptrTo("ts"),
+		Tags:               tags,
 		RelatedInformation: &relatedInformation,
 	}, nil
 }
@@ -239,8 +251,10 @@ func (c *Converters) PositionToLineAndCharacter(scriptInfo ScriptInfo, position
 
 	lineMap := scriptInfo.LineMap()
 
-	line, _ := slices.BinarySearch(lineMap.LineStarts, position)
-	line = max(0, line-1)
+	line, found := slices.BinarySearch(lineMap.LineStarts, position)
+	if !found {
+		line = max(0, line-1)
+	}
 
 	// The current line ranges from lineMap.LineStarts[line] (or 0) to lineMap.LineStarts[line+1] (or len(text)).
 
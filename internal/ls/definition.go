@@ -1,39 +1,303 @@
 package ls
 
 import (
+	"slices"
+
 	"github.com/microsoft/typescript-go/internal/ast"
 	"github.com/microsoft/typescript-go/internal/astnav"
+	"github.com/microsoft/typescript-go/internal/checker"
+	"github.com/microsoft/typescript-go/internal/compiler"
 	"github.com/microsoft/typescript-go/internal/core"
 	"github.com/microsoft/typescript-go/internal/scanner"
+	"github.com/microsoft/typescript-go/internal/tspath"
 )
 
 func (l *LanguageService) ProvideDefinitions(fileName string, position int) []Location {
 	program, file := l.getProgramAndFile(fileName)
+	if location, ok := getReferencedFileLocation(program, file, position); ok {
+		return []Location{location}
+	}
 	node := astnav.GetTouchingPropertyName(file, position)
 	if node.Kind == ast.KindSourceFile {
 		return nil
 	}
 
-	checker := program.GetTypeChecker()
+	checker := l.GetTypeChecker()
 	if symbol := checker.GetSymbolAtLocation(node); symbol != nil {
-		if symbol.Flags&ast.SymbolFlagsAlias != 0 {
-			if resolved, ok := checker.ResolveAlias(symbol); ok {
-				symbol = resolved
+		return getDeclarationLocations(skipAlias(checker, symbol))
+	}
+	return nil
+}
+
+// ProvideDeclarations is like ProvideDefinitions, except that it stops at the import or export
+// that brings a name into scope rather than following it to what it refers to.
+func (l *LanguageService) ProvideDeclarations(fileName string, position int) []Location {
+	program, file := l.getProgramAndFile(fileName)
+	if location, ok := getReferencedFileLocation(program, file, position); ok {
+		return []Location{location}
+	}
+	node := astnav.GetTouchingPropertyName(file, position)
+	if node.Kind == ast.KindSourceFile {
+		return nil
+	}
+
+	if symbol := l.GetTypeChecker().GetSymbolAtLocation(node); symbol != nil {
+		return getDeclarationLocations(symbol)
+	}
+	return nil
+}
+
+// ProvideTypeDefinitions returns the declarations of the type of the expression or declaration at
+// position. The constituents of unions and intersections and the elements of arrays and tuples
+// are followed to their own declarations.
+func (l *LanguageService) ProvideTypeDefinitions(fileName string, position int) []Location {
+	_, file := l.getProgramAndFile(fileName)
+	node := astnav.GetTouchingPropertyName(file, position)
+	if node.Kind == ast.KindSourceFile {
+		return nil
+	}
+
+	c := l.GetTypeChecker()
+	var t *checker.Type
+	if symbol := c.GetSymbolAtLocation(node); symbol != nil {
+		symbol = skipAlias(c, symbol)
+		if symbol.Flags&(ast.SymbolFlagsType|ast.SymbolFlagsValue) == ast.SymbolFlagsTypeAlias {
+			return getDeclarationLocations(symbol)
+		}
+		t = getTypeOfSymbolAtLocation(c, symbol, node)
+	} else {
+		t = c.GetTypeAtLocation(node)
+	}
+	if t == nil {
+		return nil
+	}
+
+	var locations []Location
+	var seen []*ast.Symbol
+	for _, symbol := range getTypeDefinitionSymbols(c, t, nil) {
+		if !slices.Contains(seen, symbol) {
+			seen = append(seen, symbol)
+			locations = append(locations, getDeclarationLocations(symbol)...)
+		}
+	}
+	return locations
+}
+
+func getTypeDefinitionSymbols(c *checker.Checker, t *checker.Type, seen []*checker.Type) []*ast.Symbol {
+	if slices.Contains(seen, t) {
+		return nil
+	}
+	seen = append(seen, t)
+	if t.Flags()&checker.TypeFlagsUnionOrIntersection != 0 && t.Flags()&checker.TypeFlagsEnum == 0 {
+		var symbols []*ast.Symbol
+		for _, constituent := range t.Types() {
+			symbols = append(symbols, getTypeDefinitionSymbols(c, constituent, seen)...)
+		}
+		return symbols
+	}
+	if c.IsArrayOrTupleType(t) {
+		var symbols []*ast.Symbol
+		for _, element := range c.GetTypeArguments(t) {
+			symbols = append(symbols, getTypeDefinitionSymbols(c, element, seen)...)
+		}
+		return symbols
+	}
+	if t.Symbol() == nil {
+		return nil
+	}
+	return []*ast.Symbol{t.Symbol()}
+}
+
+// ProvideImplementations returns the concrete implementations of the interface, class or member at
+// position: the classes deriving from it and the object literals typed by it, or their members.
+// Any other symbol is its own implementation, so its declarations that have a body are returned.
+func (l *LanguageService) ProvideImplementations(fileName string, position int) []Location {
+	program, file := l.getProgramAndFile(fileName)
+	node := astnav.GetTouchingPropertyName(file, position)
+	if node.Kind == ast.KindSourceFile {
+		return nil
+	}
+
+	c := l.GetTypeChecker()
+	symbol := c.GetSymbolAtLocation(node)
+	if symbol == nil {
+		return nil
+	}
+	symbol = skipAlias(c, symbol)
+
+	var nodes []*ast.Node
+	switch {
+	case symbol.Flags&(ast.SymbolFlagsClass|ast.SymbolFlagsInterface) != 0:
+		nodes = findImplementations(program, c, symbol, "")
+	case symbol.Parent != nil && symbol.Parent.Flags&(ast.SymbolFlagsClass|ast.SymbolFlagsInterface) != 0 &&
+		symbol.Flags&(ast.SymbolFlagsProperty|ast.SymbolFlagsMethod|ast.SymbolFlagsAccessor) != 0:
+		nodes = findImplementations(program, c, symbol.Parent, symbol.Name)
+		nodes = append(nodes, core.Filter(symbol.Declarations, isImplementation)...)
+	default:
+		nodes = core.Filter(symbol.Declarations, isImplementation)
+		if len(nodes) == 0 {
+			nodes = symbol.Declarations
+		}
+	}
+
+	locations := make([]Location, 0, len(nodes))
+	for _, node := range nodes {
+		locations = append(locations, getDeclarationLocation(node))
+	}
+	return locations
+}
+
+// findImplementations returns the non-abstract classes and the object literals in the program that
+// derive from the class or interface target, or, if memberName is given, their members by that name.
+func findImplementations(program *compiler.Program, c *checker.Checker, target *ast.Symbol, memberName string) []*ast.Node {
+	derived := make(map[*ast.Symbol]bool)
+	var derivesFromTarget func(symbol *ast.Symbol) bool
+	derivesFromTarget = func(symbol *ast.Symbol) bool {
+		if symbol == target {
+			return true
+		}
+		if result, ok := derived[symbol]; ok {
+			return result
+		}
+		// Assume no derivation while this symbol is being resolved, which ends circular hierarchies.
+		derived[symbol] = false
+		for _, base := range getBaseTypeSymbols(c, symbol) {
+			if derivesFromTarget(base) {
+				derived[symbol] = true
+				return true
+			}
+		}
+		return false
+	}
+
+	var result []*ast.Node
+	addMembers := func(members []*ast.Node) {
+		for _, member := range members {
+			if name := member.Name(); name != nil && getReferenceNameText(name) == memberName && isImplementation(member) {
+				result = append(result, member)
+			}
+		}
+	}
+
+	var visit func(node *ast.Node) bool
+	visit = func(node *ast.Node) bool {
+		switch {
+		case ast.IsClassLike(node):
+			if symbol := node.Symbol(); symbol != nil && symbol != target && derivesFromTarget(symbol) {
+				if memberName != "" {
+					addMembers(node.Members())
+				} else if !ast.HasSyntacticModifier(node, ast.ModifierFlagsAbstract) {
+					result = append(result, node)
+				}
+			}
+		case ast.IsObjectLiteralExpression(node):
+			if t := c.GetContextualType(node, checker.ContextFlagsNone); t != nil && slices.ContainsFunc(getTypeDefinitionSymbols(c, t, nil), derivesFromTarget) {
+				if memberName != "" {
+					addMembers(node.AsObjectLiteralExpression().Properties.Nodes)
+				} else {
+					result = append(result, node)
+				}
 			}
 		}
+		node.ForEachChild(visit)
+		return false
+	}
+	for _, file := range program.SourceFiles() {
+		if !program.IsSourceFileDefaultLibrary(file) {
+			file.AsNode().ForEachChild(visit)
+		}
+	}
+	return result
+}
 
-		locations := make([]Location, 0, len(symbol.Declarations))
-		for _, decl := range symbol.Declarations {
-			file := ast.GetSourceFileOfNode(decl)
-			loc := decl.Loc
-			pos := scanner.GetTokenPosOfNode(decl, file, false /*includeJSDoc*/)
+// getBaseTypeSymbols returns the classes and interfaces that the declarations of a class or
+// interface name in their extends and implements clauses.
+func getBaseTypeSymbols(c *checker.Checker, symbol *ast.Symbol) []*ast.Symbol {
+	var result []*ast.Symbol
+	for _, decl := range symbol.Declarations {
+		if !ast.IsClassLike(decl) && !ast.IsInterfaceDeclaration(decl) {
+			continue
+		}
+		for _, typeReference := range slices.Concat(ast.GetExtendsHeritageClauseElements(decl), ast.GetImplementsHeritageClauseElements(decl)) {
+			if t := c.GetTypeAtLocation(typeReference); t != nil && t.Symbol() != nil {
+				result = append(result, t.Symbol())
+			}
+		}
+	}
+	return result
+}
+
+// isImplementation reports whether a declaration provides a value rather than only a type.
+func isImplementation(node *ast.Node) bool {
+	if node.Flags&ast.NodeFlagsAmbient != 0 || ast.HasSyntacticModifier(node, ast.ModifierFlagsAbstract) {
+		return false
+	}
+	switch node.Kind {
+	case ast.KindInterfaceDeclaration, ast.KindTypeAliasDeclaration, ast.KindPropertySignature, ast.KindMethodSignature,
+		ast.KindCallSignature, ast.KindConstructSignature, ast.KindIndexSignature, ast.KindTypeParameter:
+		return false
+	case ast.KindVariableDeclaration:
+		return node.Initializer() != nil || ast.IsCatchClause(node.Parent)
+	case ast.KindPropertyDeclaration:
+		return true
+	}
+	if ast.IsFunctionLike(node) {
+		return node.Body() != nil
+	}
+	return true
+}
+
+func getDeclarationLocations(symbol *ast.Symbol) []Location {
+	locations := make([]Location, 0, len(symbol.Declarations))
+	for _, decl := range symbol.Declarations {
+		locations = append(locations, getDeclarationLocation(decl))
+	}
+	return locations
+}
+
+func getDeclarationLocation(decl *ast.Node) Location {
+	file := ast.GetSourceFileOfNode(decl)
+	if decl == file.AsNode() {
+		// Modules are declared by their whole file, but the location is its start.
+		return Location{FileName: file.FileName(), Range: core.NewTextRange(0, 0)}
+	}
+	pos := scanner.GetTokenPosOfNode(decl, file, false /*includeJSDoc*/)
+	return Location{
+		FileName: file.FileName(),
+		Range:    core.NewTextRange(pos, decl.End()),
+	}
+}
+
+// getReferencedFileLocation returns the start of the file that a triple-slash path or types
+// reference at position refers to.
+func getReferencedFileLocation(program *compiler.Program, file *ast.SourceFile, position int) (Location, bool) {
+	var fileName string
+	if ref := findFileReference(file.ReferencedFiles, position); ref != nil {
+		fileName = ref.FileName
+		if !tspath.IsRootedDiskPath(fileName) {
+			fileName = tspath.CombinePaths(tspath.GetDirectoryPath(file.FileName()), fileName)
+		}
+		fileName = tspath.NormalizePath(fileName)
+	} else if ref := findFileReference(file.TypeReferenceDirectives, position); ref != nil {
+		resolved := program.ModuleResolver().ResolveTypeReferenceDirective(ref.FileName, file.FileName(), core.ModuleKindCommonJS, nil)
+		if !resolved.IsResolved() {
+			return Location{}, false
+		}
+		fileName = resolved.ResolvedFileName
+	} else {
+		return Location{}, false
+	}
+	if referenced := program.GetSourceFile(fileName); referenced != nil {
+		return Location{FileName: referenced.FileName(), Range: core.NewTextRange(0, 0)}, true
+	}
+	return Location{}, false
+}
 
-			locations = append(locations, Location{
-				FileName: file.FileName(),
-				Range:    core.NewTextRange(pos, loc.End()),
-			})
+func findFileReference(refs []*ast.FileReference, position int) *ast.FileReference {
+	for _, ref := range refs {
+		if ref.Pos() <= position && position <= ref.End() {
+			return ref
 		}
-		return locations
 	}
 	return nil
 }
@@ -0,0 +1,157 @@
+package ls_test
+
+import (
+	"testing"
+
+	"github.com/microsoft/typescript-go/internal/ls"
+	"gotest.tools/v3/assert"
+)
+
+func TestDefinitions(t *testing.T) {
+	t.Parallel()
+
+	type provider func(l *ls.LanguageService, fileName string, position int) []ls.Location
+	definitions := (*ls.LanguageService).ProvideDefinitions
+	declarations := (*ls.LanguageService).ProvideDeclarations
+	typeDefinitions := (*ls.LanguageService).ProvideTypeDefinitions
+	implementations := (*ls.LanguageService).ProvideImplementations
+
+	cases := []struct {
+		title   string
+		files   map[string]string
+		provide provider
+		// The markers at the start of the locations that are expected, in order
+		expected []string
+	}{
+		{
+			title: "definition of a variable",
+			files: map[string]string{
+				"/home/src/project/index.ts": `const /*x*/x = 1;
+/*1*/x;`,
+			},
+			provide:  definitions,
+			expected: []string{"x"},
+		},
+		{
+			title: "definition of an overloaded function",
+			files: map[string]string{
+				"/home/src/project/index.ts": `/*a*/function f(a: string): void;
+/*b*/function f(a: number): void;
+/*c*/function f(a: any) {}
+/*1*/f(1);`,
+			},
+			provide:  definitions,
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			title: "definition through an import",
+			files: map[string]string{
+				"/home/src/project/tsconfig.json": `{}`,
+				"/home/src/project/a.ts":          `export const /*value*/value = 1;`,
+				"/home/src/project/b.ts": `import { value as renamed } from "./a";
+/*1*/renamed;`,
+			},
+			provide:  definitions,
+			expected: []string{"value"},
+		},
+		{
+			title: "definition of a triple-slash reference",
+			files: map[string]string{
+				"/home/src/project/a.ts": `/// <reference path="./b/*1*/.ts" />`,
+				"/home/src/project/b.ts": `/*b*/const b = 1;`,
+			},
+			provide:  definitions,
+			expected: []string{"b"},
+		},
+		{
+			title: "declaration stops at the import",
+			files: map[string]string{
+				"/home/src/project/tsconfig.json": `{}`,
+				"/home/src/project/a.ts":          `export const value = 1;`,
+				"/home/src/project/b.ts": `import { /*import*/value } from "./a";
+/*1*/value;`,
+			},
+			provide:  declarations,
+			expected: []string{"import"},
+		},
+		{
+			title: "type definition of a variable",
+			files: map[string]string{
+				"/home/src/project/index.ts": `/*Point*/interface Point { x: number }
+declare const /*1*/point: Point;`,
+			},
+			provide:  typeDefinitions,
+			expected: []string{"Point"},
+		},
+		{
+			title: "type definition of unions and arrays",
+			files: map[string]string{
+				"/home/src/project/index.ts": `/*Point*/interface Point { x: number }
+/*Size*/class Size {}
+declare const /*1*/shapes: (Point | Size)[];`,
+			},
+			provide:  typeDefinitions,
+			expected: []string{"Point", "Size"},
+		},
+		{
+			title: "type definition of a type alias",
+			files: map[string]string{
+				"/home/src/project/index.ts": `interface Point { x: number }
+/*Points*/type Points = Point[];
+let p: /*1*/Points;`,
+			},
+			provide:  typeDefinitions,
+			expected: []string{"Points"},
+		},
+		{
+			title: "implementations of an interface",
+			files: map[string]string{
+				"/home/src/project/index.ts": `interface /*1*/Shape { area(): number; }
+/*Square*/class Square implements Shape { area() { return 1; } }
+abstract class Base implements Shape { abstract area(): number; }
+/*Circle*/class Circle extends Base { area() { return 2; } }
+const shape: Shape = /*literal*/{ area: () => 3 };`,
+			},
+			provide:  implementations,
+			expected: []string{"Square", "Circle", "literal"},
+		},
+		{
+			title: "implementations of a member",
+			files: map[string]string{
+				"/home/src/project/index.ts": `interface Shape { /*1*/area(): number; }
+class Square implements Shape { /*Square*/area() { return 1; } }
+const shape: Shape = { /*literal*/area: () => 3 };`,
+			},
+			provide:  implementations,
+			expected: []string{"Square", "literal"},
+		},
+		{
+			title: "implementation of a function",
+			files: map[string]string{
+				"/home/src/project/index.ts": `function f(a: string): void;
+/*f*/function f(a: any) {}
+/*1*/f("");`,
+			},
+			provide:  implementations,
+			expected: []string{"f"},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.title, func(t *testing.T) {
+			t.Parallel()
+			p := newTestProject(t, testCase.files)
+			l, m := p.languageServiceAt("1")
+			assert.DeepEqual(t, p.markersAt(testCase.provide(l, m.fileName, m.position)), testCase.expected)
+		})
+	}
+
+	t.Run("no symbol", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/index.ts": `const x = /*1*/ 1;`,
+		})
+		l, m := p.languageServiceAt("1")
+		assert.Assert(t, l.ProvideDefinitions(m.fileName, m.position) == nil)
+	})
+}
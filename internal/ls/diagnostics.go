@@ -9,6 +9,7 @@ import (
 func (l *LanguageService) GetDocumentDiagnostics(fileName string) []*ast.Diagnostic {
 	program, file := l.getProgramAndFile(fileName)
 	syntaxDiagnostics := program.GetSyntacticDiagnostics(file)
-	semanticDiagnostics := program.GetSemanticDiagnostics(file)
-	return slices.Concat(syntaxDiagnostics, semanticDiagnostics)
+	semanticDiagnostics := program.GetSemanticDiagnosticsWithChecker(l.ctx, l.GetTypeChecker(), file)
+	suggestionDiagnostics := program.GetSuggestionDiagnosticsWithChecker(l.ctx, l.GetTypeChecker(), file)
+	return slices.Concat(syntaxDiagnostics, semanticDiagnostics, suggestionDiagnostics)
 }
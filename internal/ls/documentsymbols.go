@@ -0,0 +1,218 @@
+package ls
+
+import (
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"github.com/microsoft/typescript-go/internal/scanner"
+)
+
+// navigationItem is a named declaration in the outline of a file.
+type navigationItem struct {
+	name string
+	kind lsproto.SymbolKind
+	// node is the whole declaration, and nameNode its name if it has one.
+	node     *ast.Node
+	nameNode *ast.Node
+	children []*navigationItem
+}
+
+func (l *LanguageService) ProvideDocumentSymbols(fileName string) []lsproto.DocumentSymbol {
+	_, file := l.getProgramAndFile(fileName)
+	return l.toDocumentSymbols(file, getNavigationTree(file))
+}
+
+func (l *LanguageService) toDocumentSymbols(file *ast.SourceFile, items []*navigationItem) []lsproto.DocumentSymbol {
+	result := make([]lsproto.DocumentSymbol, 0, len(items))
+	for _, item := range items {
+		span := getNavigationItemSpan(file, item.node)
+		selectionSpan := span
+		if item.nameNode != nil {
+			selectionSpan = getNavigationItemSpan(file, item.nameNode)
+		}
+		lspRange, err := l.converters.ToLSPRange(file.FileName(), span)
+		if err != nil {
+			continue
+		}
+		selectionRange, err := l.converters.ToLSPRange(file.FileName(), selectionSpan)
+		if err != nil {
+			continue
+		}
+		symbol := lsproto.DocumentSymbol{
+			Name:           item.name,
+			Kind:           item.kind,
+			Range:          lspRange,
+			SelectionRange: selectionRange,
+		}
+		if len(item.children) != 0 {
+			symbol.Children = ptrTo(l.toDocumentSymbols(file, item.children))
+		}
+		result = append(result, symbol)
+	}
+	return result
+}
+
+func getNavigationItemSpan(file *ast.SourceFile, node *ast.Node) core.TextRange {
+	return core.NewTextRange(scanner.GetTokenPosOfNode(node, file, false /*includeJSDoc*/), node.End())
+}
+
+// getNavigationTree returns the outline of a file: its classes and their members, functions,
+// namespaces, enums, variables and the properties of object literals assigned to them.
+func getNavigationTree(file *ast.SourceFile) []*navigationItem {
+	var items []*navigationItem
+	addNavigationChildren(file.AsNode(), &items)
+	return items
+}
+
+func addNavigationChildren(node *ast.Node, items *[]*navigationItem) {
+	node.ForEachChild(func(child *ast.Node) bool {
+		addNavigationItem(child, items)
+		return false
+	})
+}
+
+func addNavigationItem(node *ast.Node, items *[]*navigationItem) {
+	switch node.Kind {
+	case ast.KindClassDeclaration, ast.KindClassExpression:
+		if name := getNavigationItemName(node); name != "" || ast.IsClassDeclaration(node) {
+			item := newNavigationItem(node, core.IfElse(name == "", "<class>", name), lsproto.SymbolKindClass)
+			addNavigationChildren(node, &item.children)
+			*items = append(*items, item)
+		} else {
+			addNavigationChildren(node, items)
+		}
+	case ast.KindFunctionDeclaration:
+		name := getNavigationItemName(node)
+		if name == "" {
+			name = core.IfElse(ast.HasSyntacticModifier(node, ast.ModifierFlagsDefault), "default", "<function>")
+		}
+		item := newNavigationItem(node, name, lsproto.SymbolKindFunction)
+		addNavigationChildren(node, &item.children)
+		*items = append(*items, item)
+	case ast.KindModuleDeclaration:
+		addModuleNavigationItem(node, items)
+	case ast.KindVariableDeclaration, ast.KindBindingElement:
+		addVariableNavigationItem(node, items)
+	case ast.KindConstructor:
+		item := newNavigationItem(node, getNavigationItemName(node), lsproto.SymbolKindConstructor)
+		if body := node.Body(); body != nil {
+			addNavigationChildren(body, &item.children)
+		}
+		*items = append(*items, item)
+		// Parameter properties are members of the class.
+		for _, parameter := range node.Parameters() {
+			if ast.IsParameterPropertyDeclaration(parameter, node) {
+				*items = append(*items, newNavigationItem(parameter, getNavigationItemName(parameter), lsproto.SymbolKindProperty))
+			}
+		}
+	case ast.KindTypeAliasDeclaration:
+		item := newNavigationItem(node, getNavigationItemName(node), lsproto.SymbolKindVariable)
+		if t := node.Type(); t != nil && t.Kind == ast.KindTypeLiteral {
+			addNavigationChildren(t, &item.children)
+		}
+		*items = append(*items, item)
+	default:
+		if kind, ok := getNavigationItemKind(node); ok {
+			item := newNavigationItem(node, getNavigationItemName(node), kind)
+			if item.name != "" {
+				addNavigationChildren(node, &item.children)
+				*items = append(*items, item)
+			}
+		} else if !ast.IsTypeNode(node) {
+			// Declarations nested in other code are shown in the enclosing item. The members of
+			// type literals are left out, except in type aliases.
+			addNavigationChildren(node, items)
+		}
+	}
+}
+
+func getNavigationItemKind(node *ast.Node) (lsproto.SymbolKind, bool) {
+	switch node.Kind {
+	case ast.KindInterfaceDeclaration:
+		return lsproto.SymbolKindInterface, true
+	case ast.KindEnumDeclaration:
+		return lsproto.SymbolKindEnum, true
+	case ast.KindEnumMember:
+		return lsproto.SymbolKindEnumMember, true
+	case ast.KindMethodDeclaration, ast.KindMethodSignature:
+		return lsproto.SymbolKindMethod, true
+	case ast.KindPropertyDeclaration, ast.KindPropertySignature, ast.KindGetAccessor, ast.KindSetAccessor,
+		ast.KindPropertyAssignment, ast.KindShorthandPropertyAssignment:
+		return lsproto.SymbolKindProperty, true
+	}
+	return 0, false
+}
+
+// addModuleNavigationItem adds a namespace. `namespace A.B {}` is a single item named `A.B`.
+func addModuleNavigationItem(node *ast.Node, items *[]*navigationItem) {
+	name := getNavigationItemName(node)
+	kind := lsproto.SymbolKindNamespace
+	if ast.IsStringLiteral(node.Name()) {
+		kind = lsproto.SymbolKindModule
+	}
+	item := newNavigationItem(node, name, kind)
+	inner := node
+	for body := inner.Body(); body != nil && ast.IsModuleDeclaration(body); body = inner.Body() {
+		inner = body
+		item.name += "." + getNavigationItemName(inner)
+	}
+	if body := inner.Body(); body != nil {
+		addNavigationChildren(body, &item.children)
+	}
+	*items = append(*items, item)
+}
+
+func addVariableNavigationItem(node *ast.Node, items *[]*navigationItem) {
+	name := node.Name()
+	if name == nil {
+		return
+	}
+	if ast.IsObjectBindingPattern(name) || ast.IsArrayBindingPattern(name) {
+		addNavigationChildren(name, items)
+		return
+	}
+	kind := lsproto.SymbolKindVariable
+	if ast.IsVarConst(node) {
+		kind = lsproto.SymbolKindConstant
+	}
+	initializer := node.Initializer()
+	if initializer != nil {
+		initializer = ast.SkipParentheses(initializer)
+		switch initializer.Kind {
+		case ast.KindClassExpression:
+			kind = lsproto.SymbolKindClass
+		case ast.KindFunctionExpression, ast.KindArrowFunction:
+			kind = lsproto.SymbolKindFunction
+		}
+	}
+	item := newNavigationItem(node, getNavigationItemName(node), kind)
+	if initializer != nil {
+		// The members of an assigned class or object literal belong to the variable.
+		addNavigationChildren(initializer, &item.children)
+	}
+	*items = append(*items, item)
+}
+
+func newNavigationItem(node *ast.Node, name string, kind lsproto.SymbolKind) *navigationItem {
+	return &navigationItem{
+		name:     name,
+		kind:     kind,
+		node:     node,
+		nameNode: node.Name(),
+	}
+}
+
+func getNavigationItemName(node *ast.Node) string {
+	if ast.IsConstructorDeclaration(node) {
+		return "constructor"
+	}
+	name := node.Name()
+	if name == nil {
+		return ""
+	}
+	switch name.Kind {
+	case ast.KindIdentifier, ast.KindPrivateIdentifier, ast.KindStringLiteral, ast.KindNumericLiteral, ast.KindNoSubstitutionTemplateLiteral:
+		return name.Text()
+	}
+	return getNodeText(name)
+}
@@ -0,0 +1,195 @@
+package ls_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/microsoft/typescript-go/internal/ls"
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"gotest.tools/v3/assert"
+)
+
+var symbolKindNames = map[lsproto.SymbolKind]string{
+	lsproto.SymbolKindModule:      "module",
+	lsproto.SymbolKindNamespace:   "namespace",
+	lsproto.SymbolKindClass:       "class",
+	lsproto.SymbolKindMethod:      "method",
+	lsproto.SymbolKindProperty:    "property",
+	lsproto.SymbolKindConstructor: "constructor",
+	lsproto.SymbolKindEnum:        "enum",
+	lsproto.SymbolKindInterface:   "interface",
+	lsproto.SymbolKindFunction:    "function",
+	lsproto.SymbolKindVariable:    "variable",
+	lsproto.SymbolKindConstant:    "constant",
+	lsproto.SymbolKindEnumMember:  "enum member",
+	lsproto.SymbolKindFile:        "file",
+}
+
+// formatDocumentSymbols prints the outline of a file with one symbol per line, indented by depth.
+func formatDocumentSymbols(symbols []lsproto.DocumentSymbol) string {
+	var b strings.Builder
+	var write func(symbols []lsproto.DocumentSymbol, indent string)
+	write = func(symbols []lsproto.DocumentSymbol, indent string) {
+		for _, symbol := range symbols {
+			fmt.Fprintf(&b, "%s%s (%s)\n", indent, symbol.Name, symbolKindNames[symbol.Kind])
+			if symbol.Children != nil {
+				write(*symbol.Children, indent+"  ")
+			}
+		}
+	}
+	write(symbols, "")
+	return b.String()
+}
+
+func TestDocumentSymbols(t *testing.T) {
+	t.Parallel()
+
+	t.Run("outline", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/index.ts": `namespace App.Models {
+    export interface User {
+        name: string;
+        greet(): string;
+    }
+}
+class Service {
+    private cache = new Map<string, number>();
+    constructor(private readonly url: string) {}
+    get size() { return this.cache.size; }
+    fetch() {
+        const local = 1;
+        return local;
+    }
+}
+enum Color { Red, Green }
+type Options = { verbose: boolean };
+const handler = () => {};
+let { a, b: [c] } = { a: 1, b: [2] };
+const config = {
+    port: 80,
+    start() {},
+};
+export default function () {}
+for (const item of []) {}`,
+		})
+		l := p.languageService("/home/src/project/index.ts")
+		assert.Equal(t, formatDocumentSymbols(l.ProvideDocumentSymbols("/home/src/project/index.ts")), `App.Models (namespace)
+  User (interface)
+    name (property)
+    greet (method)
+Service (class)
+  cache (property)
+  constructor (constructor)
+  url (property)
+  size (property)
+  fetch (method)
+    local (constant)
+Color (enum)
+  Red (enum member)
+  Green (enum member)
+Options (variable)
+  verbose (property)
+handler (function)
+a (variable)
+c (variable)
+config (constant)
+  port (property)
+  start (method)
+default (function)
+item (constant)
+`)
+	})
+
+	t.Run("ranges", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/index.ts": `/** A function. */
+function /*1*/greet() {}`,
+		})
+		l, m := p.languageServiceAt("1")
+		symbols := l.ProvideDocumentSymbols(m.fileName)
+		assert.Equal(t, len(symbols), 1)
+		// The range covers the declaration without its JSDoc, and the selection range its name
+		assert.DeepEqual(t, symbols[0].Range, lsproto.Range{
+			Start: lsproto.Position{Line: 1, Character: 0},
+			End:   lsproto.Position{Line: 1, Character: 19},
+		})
+		assert.DeepEqual(t, symbols[0].SelectionRange, lsproto.Range{
+			Start: lsproto.Position{Line: 1, Character: 9},
+			End:   lsproto.Position{Line: 1, Character: 14},
+		})
+	})
+}
+
+func TestWorkspaceSymbols(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"/home/src/projects/a/tsconfig.json": `{}`,
+		"/home/src/projects/a/index.ts": `/*UserService*/export class UserService {
+    /*getUser*/getUser() {}
+}
+/*createUser*/export function createUser() {}`,
+		"/home/src/projects/a/shared.ts":     `export const /*user*/user = 1;`,
+		"/home/src/projects/b/tsconfig.json": `{ "files": ["index.ts", "../a/shared.ts"] }`,
+		"/home/src/projects/b/index.ts": `/*UserOptions*/export interface UserOptions {}
+export const /*superUser*/superUser = 1;`,
+	}
+
+	cases := []struct {
+		title    string
+		query    string
+		expected []string
+	}{
+		{
+			title: "exact matches come first, then prefixes, substrings and subsequences",
+			query: "user",
+			// shared.ts is in both projects and is only searched once
+			expected: []string{"user", "UserOptions", "UserService", "createUser", "getUser", "superUser"},
+		},
+		{
+			title:    "full name",
+			query:    "UserService",
+			expected: []string{"UserService"},
+		},
+		{
+			title:    "subsequence",
+			query:    "usrsvc",
+			expected: []string{"UserService"},
+		},
+		{
+			title:    "no match",
+			query:    "missing",
+			expected: []string{},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.title, func(t *testing.T) {
+			t.Parallel()
+			p := newTestProject(t, files)
+			services := []*ls.LanguageService{
+				p.languageService("/home/src/projects/a/index.ts"),
+				p.languageService("/home/src/projects/b/index.ts"),
+			}
+			symbols := ls.ProvideWorkspaceSymbols(services, testCase.query)
+			locations := make([]ls.Location, len(symbols))
+			for i, symbol := range symbols {
+				locations[i] = symbol.Location
+			}
+			assert.DeepEqual(t, p.markersAt(locations), testCase.expected)
+		})
+	}
+
+	t.Run("container names", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, files)
+		symbols := ls.ProvideWorkspaceSymbols([]*ls.LanguageService{p.languageService("/home/src/projects/a/index.ts")}, "getUser")
+		assert.Equal(t, len(symbols), 1)
+		assert.Equal(t, symbols[0].Name, "getUser")
+		assert.Equal(t, symbols[0].Kind, lsproto.SymbolKindMethod)
+		assert.Equal(t, symbols[0].ContainerName, "UserService")
+	})
+}
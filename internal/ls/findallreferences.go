@@ -0,0 +1,442 @@
+package ls
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/astnav"
+	"github.com/microsoft/typescript-go/internal/checker"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/scanner"
+)
+
+// ReferenceEntry is a single occurrence of the symbol that a reference search started from.
+type ReferenceEntry struct {
+	Location
+	// IsWriteAccess is set for declarations and assignment targets.
+	IsWriteAccess bool
+	// IsDefinition is set for the names of the declarations of the searched symbol.
+	IsDefinition bool
+
+	node *ast.Node
+}
+
+func (l *LanguageService) ProvideReferences(fileName string, position int, includeDeclaration bool) []Location {
+	program, file := l.getProgramAndFile(fileName)
+	entries := findReferences(l.GetTypeChecker(), file, position, program.SourceFiles())
+	locations := make([]Location, 0, len(entries))
+	for _, entry := range entries {
+		if includeDeclaration || !entry.IsDefinition {
+			locations = append(locations, entry.Location)
+		}
+	}
+	return locations
+}
+
+func (l *LanguageService) ProvideDocumentHighlights(fileName string, position int) []ReferenceEntry {
+	_, file := l.getProgramAndFile(fileName)
+	return findReferences(l.GetTypeChecker(), file, position, []*ast.SourceFile{file})
+}
+
+// findReferences returns the references in sourceFiles to the symbol at position, ordered by
+// file and then by position.
+func findReferences(c *checker.Checker, file *ast.SourceFile, position int, sourceFiles []*ast.SourceFile) []ReferenceEntry {
+	node := astnav.GetTouchingPropertyName(file, position)
+	switch node.Kind {
+	case ast.KindSourceFile:
+		return nil
+	case ast.KindThisKeyword, ast.KindSuperKeyword:
+		if !slices.Contains(sourceFiles, file) {
+			return nil
+		}
+		return findThisOrSuperReferences(file, node)
+	}
+
+	symbol := c.GetSymbolAtLocation(node)
+	if symbol == nil {
+		return nil
+	}
+
+	search := newReferenceSearch(c, node, symbol, getReferencedSymbols(c, node, symbol))
+	return search.findReferences(sourceFiles)
+}
+
+type referenceSearch struct {
+	c      *checker.Checker
+	symbol *ast.Symbol
+	// names are the texts that a reference can have. Besides the name of the symbol this
+	// includes the local names of the aliases that import or re-export it under another name.
+	names []string
+	// declarations are the declarations of the searched symbol and of the symbols related to it.
+	// A location references the searched symbol when its symbol shares one of them.
+	declarations map[*ast.Node]struct{}
+	// definitions are the declarations of the searched symbol itself.
+	definitions map[*ast.Node]struct{}
+}
+
+// newReferenceSearch creates a search for the references to symbol, the symbol at location, and
+// to everything related to the given search symbols.
+func newReferenceSearch(c *checker.Checker, location *ast.Node, symbol *ast.Symbol, searchSymbols []*ast.Symbol) *referenceSearch {
+	s := &referenceSearch{
+		c:            c,
+		symbol:       symbol,
+		declarations: make(map[*ast.Node]struct{}),
+		definitions:  make(map[*ast.Node]struct{}),
+	}
+	s.addName(getReferenceNameText(location))
+
+	for _, searchSymbol := range searchSymbols {
+		for _, related := range s.getRelatedSymbols(searchSymbol) {
+			for _, decl := range related.Declarations {
+				s.declarations[decl] = struct{}{}
+				// Default exports are named after their declaration rather than the symbol.
+				if name := ast.GetNameOfDeclaration(decl); name != nil && ast.IsIdentifier(name) {
+					s.addName(name.Text())
+				}
+			}
+			s.addName(related.Name)
+		}
+	}
+
+	definition := symbol
+	if symbol.Flags&ast.SymbolFlagsAlias != 0 {
+		if target, ok := c.ResolveAlias(symbol); ok {
+			definition = target
+		}
+	}
+	for _, symbol := range []*ast.Symbol{symbol, definition} {
+		for _, decl := range symbol.Declarations {
+			s.definitions[decl] = struct{}{}
+		}
+	}
+	return s
+}
+
+func (s *referenceSearch) findReferences(sourceFiles []*ast.SourceFile) []ReferenceEntry {
+	if scope := getSymbolScope(s.symbol); scope != nil {
+		if !slices.Contains(sourceFiles, scope) {
+			return nil
+		}
+		sourceFiles = []*ast.SourceFile{scope}
+	} else {
+		for _, sourceFile := range sourceFiles {
+			s.addAliasNames(sourceFile)
+		}
+	}
+
+	var entries []ReferenceEntry
+	for _, sourceFile := range sourceFiles {
+		entries = append(entries, s.findReferencesInFile(sourceFile)...)
+	}
+	return entries
+}
+
+func (s *referenceSearch) addName(name string) {
+	if name != "" && !strings.HasPrefix(name, ast.InternalSymbolNamePrefix) && !slices.Contains(s.names, name) {
+		s.names = append(s.names, name)
+	}
+}
+
+// addAliasNames adds the local names of the imports and re-exports in a file that refer to the
+// searched symbol, so that `import { x as y }` and default imports are followed to their uses.
+func (s *referenceSearch) addAliasNames(file *ast.SourceFile) {
+	var visit func(node *ast.Node) bool
+	visit = func(node *ast.Node) bool {
+		switch node.Kind {
+		case ast.KindImportClause, ast.KindImportEqualsDeclaration, ast.KindNamespaceImport, ast.KindImportSpecifier, ast.KindExportSpecifier:
+			if name := node.Name(); name != nil && ast.IsIdentifier(name) && !slices.Contains(s.names, name.Text()) {
+				if symbol := s.c.GetSymbolAtLocation(name); symbol != nil && s.isRelatedSymbol(symbol) {
+					s.addName(name.Text())
+				}
+			}
+			return node.ForEachChild(visit)
+		case ast.KindImportDeclaration, ast.KindNamedImports, ast.KindExportDeclaration, ast.KindNamedExports,
+			ast.KindModuleDeclaration, ast.KindModuleBlock:
+			return node.ForEachChild(visit)
+		}
+		return false
+	}
+	for _, statement := range file.Statements.Nodes {
+		visit(statement)
+	}
+}
+
+func (s *referenceSearch) findReferencesInFile(file *ast.SourceFile) []ReferenceEntry {
+	text := file.Text()
+	seen := make(map[int]struct{})
+	var entries []ReferenceEntry
+	for _, name := range s.names {
+		for offset := 0; ; {
+			index := strings.Index(text[offset:], name)
+			if index < 0 {
+				break
+			}
+			pos := offset + index
+			offset = pos + len(name)
+			if _, ok := seen[pos]; ok {
+				continue
+			}
+			node := astnav.GetTouchingPropertyName(file, pos)
+			if !isReferenceNameAtPosition(file, node, name, pos) {
+				continue
+			}
+			if !slices.ContainsFunc(getReferencedSymbols(s.c, node, s.c.GetSymbolAtLocation(node)), s.isRelatedSymbol) {
+				continue
+			}
+			seen[pos] = struct{}{}
+			entries = append(entries, ReferenceEntry{
+				Location: Location{
+					FileName: file.FileName(),
+					Range:    core.NewTextRange(pos, pos+len(name)),
+				},
+				IsWriteAccess: isWriteAccessForReference(node),
+				IsDefinition:  s.isDefinition(node),
+				node:          node,
+			})
+		}
+	}
+	slices.SortFunc(entries, func(a, b ReferenceEntry) int {
+		return cmp.Compare(a.Range.Pos(), b.Range.Pos())
+	})
+	return entries
+}
+
+func (s *referenceSearch) isDefinition(node *ast.Node) bool {
+	if !ast.IsDeclarationName(node) {
+		return false
+	}
+	_, ok := s.definitions[node.Parent]
+	return ok
+}
+
+// isRelatedSymbol reports whether a symbol found at a location refers to the searched symbol.
+func (s *referenceSearch) isRelatedSymbol(symbol *ast.Symbol) bool {
+	for _, related := range s.getRelatedSymbols(symbol) {
+		for _, decl := range related.Declarations {
+			if _, ok := s.declarations[decl]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getReferencedSymbols returns the symbols that a name refers to. Usually this is just the symbol
+// of the name, but some names refer to a property and to a value at the same time.
+func getReferencedSymbols(c *checker.Checker, node *ast.Node, symbol *ast.Symbol) []*ast.Symbol {
+	var symbols []*ast.Symbol
+	if symbol != nil {
+		symbols = append(symbols, symbol)
+	}
+	// `{ x }` refers to both the property x and the value x.
+	if parent := node.Parent; parent != nil && ast.IsShorthandPropertyAssignment(parent) {
+		if valueSymbol := c.GetShorthandAssignmentValueSymbol(parent); valueSymbol != nil {
+			symbols = append(symbols, valueSymbol)
+		}
+	}
+	return append(symbols, getContextualPropertySymbols(c, node)...)
+}
+
+// getContextualPropertySymbols returns the properties that a name in an object literal or an
+// object binding pattern stands for without being their declaration.
+func getContextualPropertySymbols(c *checker.Checker, node *ast.Node) []*ast.Symbol {
+	parent := node.Parent
+	if parent == nil {
+		return nil
+	}
+
+	// A property in an object literal also refers to the property of the contextual type.
+	if parent.Name() == node && parent.Parent != nil && ast.IsObjectLiteralExpression(parent.Parent) {
+		if t := c.GetContextualType(parent.Parent, checker.ContextFlagsNone); t != nil {
+			return getPropertiesOfConstituents(c, t, ast.GetTextOfPropertyName(node))
+		}
+	}
+
+	// `const { x } = o` refers to the property x of the type of o.
+	if ast.IsBindingElement(parent) && parent.Name() == node && parent.PropertyName() == nil && ast.IsObjectBindingPattern(parent.Parent) {
+		if t := c.GetTypeAtLocation(parent.Parent); t != nil {
+			return getPropertiesOfConstituents(c, t, node.Text())
+		}
+	}
+	return nil
+}
+
+func getPropertiesOfConstituents(c *checker.Checker, t *checker.Type, name string) []*ast.Symbol {
+	types := []*checker.Type{t}
+	if t.Flags()&checker.TypeFlagsUnion != 0 {
+		types = t.Types()
+	}
+	var result []*ast.Symbol
+	for _, t := range types {
+		if prop := c.GetPropertyOfType(c.GetApparentType(t), name); prop != nil {
+			result = append(result, prop)
+		}
+	}
+	return result
+}
+
+// getRelatedSymbols returns a symbol together with the symbols that it stands for: the targets of
+// aliases, the constituents of union and intersection properties, the uninstantiated forms of
+// instantiated symbols, and the members that a class or interface member overrides or implements.
+func (s *referenceSearch) getRelatedSymbols(symbol *ast.Symbol) []*ast.Symbol {
+	var result []*ast.Symbol
+	var add func(symbol *ast.Symbol)
+	add = func(symbol *ast.Symbol) {
+		if symbol == nil || slices.Contains(result, symbol) {
+			return
+		}
+		result = append(result, symbol)
+		add(symbol.ExportSymbol)
+		if symbol.Flags&ast.SymbolFlagsAlias != 0 {
+			if target, ok := s.c.ResolveAlias(symbol); ok {
+				add(target)
+			}
+		}
+		for _, root := range s.c.GetRootSymbols(symbol) {
+			add(root)
+		}
+		if symbol.Parent != nil && symbol.Flags&(ast.SymbolFlagsProperty|ast.SymbolFlagsMethod|ast.SymbolFlagsAccessor) != 0 {
+			for _, base := range s.getPropertySymbolsFromBaseTypes(symbol.Parent, symbol.Name, nil) {
+				add(base)
+			}
+		}
+	}
+	add(symbol)
+	return result
+}
+
+func (s *referenceSearch) getPropertySymbolsFromBaseTypes(symbol *ast.Symbol, name string, seen []*ast.Symbol) []*ast.Symbol {
+	if symbol.Flags&(ast.SymbolFlagsClass|ast.SymbolFlagsInterface) == 0 || slices.Contains(seen, symbol) {
+		return nil
+	}
+	seen = append(seen, symbol)
+	var result []*ast.Symbol
+	for _, decl := range symbol.Declarations {
+		if !ast.IsClassLike(decl) && !ast.IsInterfaceDeclaration(decl) {
+			continue
+		}
+		for _, typeReference := range slices.Concat(ast.GetExtendsHeritageClauseElements(decl), ast.GetImplementsHeritageClauseElements(decl)) {
+			t := s.c.GetTypeAtLocation(typeReference)
+			if t == nil || t.Symbol() == nil {
+				continue
+			}
+			if prop := s.c.GetPropertyOfType(t, name); prop != nil {
+				result = append(result, s.c.GetRootSymbols(prop)...)
+			}
+			result = append(result, s.getPropertySymbolsFromBaseTypes(t.Symbol(), name, seen)...)
+		}
+	}
+	return result
+}
+
+// getSymbolScope returns the file that contains every reference to a symbol declared inside a
+// function or block, or nil if the symbol may be referenced from any file.
+func getSymbolScope(symbol *ast.Symbol) *ast.SourceFile {
+	if symbol.Parent != nil || len(symbol.Declarations) == 0 ||
+		symbol.Flags&(ast.SymbolFlagsProperty|ast.SymbolFlagsMethod|ast.SymbolFlagsAccessor|ast.SymbolFlagsEnumMember|ast.SymbolFlagsAlias) != 0 {
+		return nil
+	}
+	var scope *ast.SourceFile
+	for _, decl := range symbol.Declarations {
+		container := ast.FindAncestor(decl.Parent, func(node *ast.Node) bool {
+			return ast.IsFunctionLike(node) || ast.IsBlock(node) || ast.IsSourceFile(node) || ast.IsModuleBlock(node)
+		})
+		if container == nil || ast.IsSourceFile(container) || ast.IsModuleBlock(container) {
+			return nil
+		}
+		file := ast.GetSourceFileOfNode(decl)
+		if scope != nil && scope != file {
+			return nil
+		}
+		scope = file
+	}
+	return scope
+}
+
+func getReferenceNameText(node *ast.Node) string {
+	switch node.Kind {
+	case ast.KindIdentifier, ast.KindPrivateIdentifier, ast.KindStringLiteral, ast.KindNoSubstitutionTemplateLiteral:
+		return node.Text()
+	}
+	return ""
+}
+
+// isReferenceNameAtPosition reports whether the name found in the text at pos is the whole
+// text of a node, rather than part of a longer name, a comment, or some other token.
+func isReferenceNameAtPosition(file *ast.SourceFile, node *ast.Node, name string, pos int) bool {
+	if getReferenceNameText(node) != name {
+		return false
+	}
+	start := scanner.GetTokenPosOfNode(node, file, false /*includeJSDoc*/)
+	if ast.IsStringLiteral(node) || node.Kind == ast.KindNoSubstitutionTemplateLiteral {
+		return start+1 == pos
+	}
+	return start == pos
+}
+
+func isWriteAccessForReference(node *ast.Node) bool {
+	parent := node.Parent
+	if ast.IsShorthandPropertyAssignment(parent) {
+		return ast.IsAssignmentTarget(node)
+	}
+	if ast.IsDeclarationName(node) {
+		return true
+	}
+	if ast.IsPropertyAccessExpression(parent) && parent.Name() == node {
+		node = parent
+	}
+	return ast.IsAssignmentTarget(node)
+}
+
+// findThisOrSuperReferences returns the `this` or `super` keywords in a file that refer to the
+// same object as the given keyword.
+func findThisOrSuperReferences(file *ast.SourceFile, keyword *ast.Node) []ReferenceEntry {
+	container := ast.GetThisContainer(keyword, false /*includeArrowFunctions*/, false /*includeClassComputedPropertyName*/)
+	class := getClassOfMember(container)
+	if keyword.Kind == ast.KindSuperKeyword && class == nil {
+		return nil
+	}
+	isSameContainer := func(other *ast.Node) bool {
+		if other == container {
+			return keyword.Kind == ast.KindThisKeyword || class != nil
+		}
+		// All instance (or all static) members of a class share `this` and `super`.
+		return class != nil && getClassOfMember(other) == class && ast.IsStatic(other) == ast.IsStatic(container)
+	}
+
+	root := container
+	if class != nil {
+		root = class
+	}
+	var entries []ReferenceEntry
+	var visit func(node *ast.Node) bool
+	visit = func(node *ast.Node) bool {
+		if node.Kind == keyword.Kind || keyword.Kind == ast.KindThisKeyword && node.Kind == ast.KindThisType {
+			if isSameContainer(ast.GetThisContainer(node, false /*includeArrowFunctions*/, false /*includeClassComputedPropertyName*/)) {
+				entries = append(entries, ReferenceEntry{
+					Location: Location{
+						FileName: file.FileName(),
+						Range:    core.NewTextRange(scanner.GetTokenPosOfNode(node, file, false /*includeJSDoc*/), node.End()),
+					},
+				})
+			}
+		}
+		return node.ForEachChild(visit)
+	}
+	root.ForEachChild(visit)
+	return entries
+}
+
+// getClassOfMember returns the class of a member that `this` or `super` can be used in.
+func getClassOfMember(node *ast.Node) *ast.Node {
+	switch node.Kind {
+	case ast.KindMethodDeclaration, ast.KindPropertyDeclaration, ast.KindConstructor, ast.KindGetAccessor,
+		ast.KindSetAccessor, ast.KindClassStaticBlockDeclaration:
+		if node.Parent != nil && ast.IsClassLike(node.Parent) {
+			return node.Parent
+		}
+	}
+	return nil
+}
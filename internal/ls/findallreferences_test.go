@@ -0,0 +1,160 @@
+package ls_test
+
+import (
+	"testing"
+
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/ls"
+	"gotest.tools/v3/assert"
+)
+
+func TestFindAllReferences(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		title string
+		files map[string]string
+		// The marker that references are searched from
+		marker string
+		// The markers at the start of the references that are expected, in order
+		expected []string
+	}{
+		{
+			title: "local variable",
+			files: map[string]string{
+				"/home/src/project/index.ts": `let /*1*/count = 0;
+/*2*/count++;
+console.log(/*3*/count);`,
+			},
+			marker:   "2",
+			expected: []string{"1", "2", "3"},
+		},
+		{
+			title: "renamed import alias",
+			files: map[string]string{
+				"/home/src/project/tsconfig.json": `{}`,
+				"/home/src/project/a.ts":          `export const /*1*/value = 1;`,
+				"/home/src/project/b.ts": `import { /*2*/value as /*3*/renamed } from "./a";
+console.log(/*4*/renamed);`,
+			},
+			marker:   "1",
+			expected: []string{"1", "2", "3", "4"},
+		},
+		{
+			title: "shorthand property",
+			files: map[string]string{
+				"/home/src/project/index.ts": `const /*1*/x = 1;
+const point = { /*2*/x, y: /*3*/x };`,
+			},
+			marker:   "1",
+			expected: []string{"1", "2", "3"},
+		},
+		{
+			title: "property of a shorthand property",
+			files: map[string]string{
+				"/home/src/project/index.ts": `const x = 1;
+const point = { /*1*/x };
+point./*2*/x;`,
+			},
+			marker:   "2",
+			expected: []string{"1", "2"},
+		},
+		{
+			title: "union property",
+			files: map[string]string{
+				"/home/src/project/index.ts": `interface Circle { /*1*/kind: "circle"; radius: number; }
+interface Square { /*2*/kind: "square"; size: number; }
+declare const shape: Circle | Square;
+shape./*3*/kind;`,
+			},
+			marker:   "3",
+			expected: []string{"1", "2", "3"},
+		},
+		{
+			title: "inherited member",
+			files: map[string]string{
+				"/home/src/project/index.ts": `class Base { /*1*/greet() {} }
+class Derived extends Base { /*2*/greet() { super./*3*/greet(); } }
+new Derived()./*4*/greet();
+new Base()./*5*/greet();`,
+			},
+			marker:   "1",
+			expected: []string{"1", "2", "3", "4", "5"},
+		},
+		{
+			title: "interface member implemented by a class",
+			files: map[string]string{
+				"/home/src/project/index.ts": `interface Named { /*1*/name: string; }
+class Person implements Named { /*2*/name = ""; }
+declare const named: Named;
+named./*3*/name;`,
+			},
+			marker:   "3",
+			expected: []string{"1", "2", "3"},
+		},
+		{
+			title: "this",
+			files: map[string]string{
+				"/home/src/project/index.ts": `class Counter {
+    count = 0;
+    increment() {
+        /*1*/this.count++;
+        return /*2*/this;
+    }
+    reset() {
+        /*3*/this.count = 0;
+    }
+}`,
+			},
+			marker:   "1",
+			expected: []string{"1", "2", "3"},
+		},
+		{
+			title: "super",
+			files: map[string]string{
+				"/home/src/project/index.ts": `class Base { m() {} n() {} }
+class Derived extends Base {
+    m() { /*1*/super.m(); }
+    n() { /*2*/super.n(); }
+}`,
+			},
+			marker:   "2",
+			expected: []string{"1", "2"},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.title, func(t *testing.T) {
+			t.Parallel()
+			p := newTestProject(t, testCase.files)
+			l, m := p.languageServiceAt(testCase.marker)
+			assert.DeepEqual(t, p.markersAt(l.ProvideReferences(m.fileName, m.position, true /*includeDeclaration*/)), testCase.expected)
+		})
+	}
+
+	t.Run("exclude declarations", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/index.ts": `function /*1*/f() {}
+/*2*/f();`,
+		})
+		l, m := p.languageServiceAt("2")
+		assert.DeepEqual(t, p.markersAt(l.ProvideReferences(m.fileName, m.position, false /*includeDeclaration*/)), []string{"2"})
+	})
+
+	t.Run("document highlights", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/a.ts": `export let /*1*/total = 0;
+/*2*/total += 1;
+console.log(/*3*/total);`,
+			"/home/src/project/b.ts": `import { total } from "./a";
+console.log(total);`,
+		})
+		l, m := p.languageServiceAt("3")
+		highlights := l.ProvideDocumentHighlights(m.fileName, m.position)
+		assert.DeepEqual(t, p.markersAt(core.Map(highlights, func(entry ls.ReferenceEntry) ls.Location { return entry.Location })), []string{"1", "2", "3"})
+		assert.DeepEqual(t, core.Map(highlights, func(entry ls.ReferenceEntry) bool { return entry.IsWriteAccess }), []bool{true, true, false})
+		assert.DeepEqual(t, core.Map(highlights, func(entry ls.ReferenceEntry) bool { return entry.IsDefinition }), []bool{true, false, false})
+	})
+}
@@ -0,0 +1,83 @@
+package ls
+
+import (
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/checker"
+	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
+)
+
+var fixMissingPropertyProvider = &codeFixProvider{
+	errorCodes: []int32{
+		diagnostics.Property_0_does_not_exist_on_type_1.Code(),
+		diagnostics.Property_0_does_not_exist_on_type_1_Did_you_mean_2.Code(),
+	},
+	getCodeFixes:      getMissingPropertyFixes,
+	fixID:             "fixMissingMember",
+	fixAllDescription: diagnostics.Add_all_missing_members,
+}
+
+// getMissingPropertyFixes declares a property that is accessed on a class or interface of the
+// program but missing from it. The property is typed by the value assigned to it if there is one.
+func getMissingPropertyFixes(context *codeFixContext) []CodeFix {
+	token := context.getTokenAtSpan()
+	if token == nil || !ast.IsIdentifier(token) && !ast.IsPrivateIdentifier(token) {
+		return nil
+	}
+	access := token.Parent
+	if !ast.IsPropertyAccessExpression(access) || access.Name() != token {
+		return nil
+	}
+	c := context.checker
+	expression := access.Expression()
+	t := c.GetNonNullableType(c.GetTypeAtLocation(expression))
+	symbol := t.Symbol()
+	if symbol == nil || symbol.Flags&(ast.SymbolFlagsClass|ast.SymbolFlagsInterface) == 0 {
+		return nil
+	}
+	// Accesses on the constructor itself rather than on an instance declare static members.
+	isStatic := symbol.Flags&ast.SymbolFlagsClass != 0 && c.GetTypeOfSymbolAtLocation(symbol, expression) == t
+	if ast.IsPrivateIdentifier(token) && (symbol.Flags&ast.SymbolFlagsClass == 0 || ast.GetContainingClass(access) == nil) {
+		return nil
+	}
+
+	declaration := getMemberContainerDeclaration(context, symbol, isStatic)
+	if declaration == nil {
+		return nil
+	}
+
+	typeText := "any"
+	if ast.IsBinaryExpression(access.Parent) && access.Parent.AsBinaryExpression().Left == access &&
+		access.Parent.AsBinaryExpression().OperatorToken.Kind == ast.KindEqualsToken {
+		assigned := c.GetBaseTypeOfLiteralType(c.GetTypeAtLocation(access.Parent.AsBinaryExpression().Right))
+		typeText = c.TypeToStringEx(assigned, declaration, checker.TypeFormatFlagsNoTruncation)
+	}
+	line := token.Text() + ": " + typeText + ";"
+	if isStatic {
+		line = "static " + line
+	}
+
+	file := ast.GetSourceFileOfNode(declaration)
+	change := getInsertionAtEndOfMembers(file, declaration, declaration.Members(), []string{line}, context.newLine)
+	title := diagnostics.Declare_property_0
+	if ast.IsPrivateIdentifier(token) {
+		title = diagnostics.Declare_a_private_field_named_0
+	}
+	return []CodeFix{newCodeFix(title, []any{token.Text()}, "fixMissingMember", file.FileName(), change)}
+}
+
+// getMemberContainerDeclaration returns the class or interface declaration of symbol that a
+// member can be added to: one in a source file of the program rather than a library or a
+// declaration file. Static members can only be added to classes.
+func getMemberContainerDeclaration(context *codeFixContext, symbol *ast.Symbol, isStatic bool) *ast.Node {
+	for _, decl := range symbol.Declarations {
+		if !ast.IsClassLike(decl) && (isStatic || !ast.IsInterfaceDeclaration(decl)) {
+			continue
+		}
+		file := ast.GetSourceFileOfNode(decl)
+		if file.IsDeclarationFile || context.program.IsSourceFileDefaultLibrary(file) {
+			continue
+		}
+		return decl
+	}
+	return nil
+}
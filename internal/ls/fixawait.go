@@ -0,0 +1,110 @@
+package ls
+
+import (
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/scanner"
+)
+
+var fixMissingAwaitProvider = &codeFixProvider{
+	errorCodes: []int32{
+		diagnostics.An_arithmetic_operand_must_be_of_type_any_number_bigint_or_an_enum_type.Code(),
+		diagnostics.The_left_hand_side_of_an_arithmetic_operation_must_be_of_type_any_number_bigint_or_an_enum_type.Code(),
+		diagnostics.The_right_hand_side_of_an_arithmetic_operation_must_be_of_type_any_number_bigint_or_an_enum_type.Code(),
+		diagnostics.Operator_0_cannot_be_applied_to_type_1.Code(),
+		diagnostics.Operator_0_cannot_be_applied_to_types_1_and_2.Code(),
+		diagnostics.This_comparison_appears_to_be_unintentional_because_the_types_0_and_1_have_no_overlap.Code(),
+		diagnostics.This_condition_will_always_return_true_since_this_0_is_always_defined.Code(),
+		diagnostics.Type_0_is_not_an_array_type.Code(),
+		diagnostics.Type_0_is_not_an_array_type_or_a_string_type.Code(),
+		diagnostics.Type_0_must_have_a_Symbol_iterator_method_that_returns_an_iterator.Code(),
+		diagnostics.Type_0_is_not_an_array_type_or_does_not_have_a_Symbol_iterator_method_that_returns_an_iterator.Code(),
+		diagnostics.Argument_of_type_0_is_not_assignable_to_parameter_of_type_1.Code(),
+		diagnostics.Type_0_is_not_assignable_to_type_1.Code(),
+		diagnostics.This_expression_is_not_callable.Code(),
+		diagnostics.This_expression_is_not_constructable.Code(),
+		diagnostics.Property_0_does_not_exist_on_type_1.Code(),
+	},
+	getCodeFixes:      getMissingAwaitFixes,
+	fixID:             "fixMissingAwait",
+	fixAllDescription: diagnostics.Fix_all_expressions_possibly_missing_await,
+}
+
+// getMissingAwaitFixes awaits the promises in an expression that was reported for being used as
+// the value that the promise resolves to.
+func getMissingAwaitFixes(context *codeFixContext) []CodeFix {
+	node := context.getTokenAtSpan()
+	if node == nil || !isInAwaitContext(node) {
+		return nil
+	}
+	if ast.IsVariableDeclaration(node.Parent) && node.Parent.Name() == node && node.Parent.Initializer() != nil {
+		// Assignability errors are reported on the name of the declaration rather than its value.
+		node = node.Parent.Initializer()
+	}
+
+	var expressions []*ast.Node
+	switch {
+	case context.errorCode == diagnostics.Property_0_does_not_exist_on_type_1.Code():
+		access := node.Parent
+		if !ast.IsPropertyAccessExpression(access) || access.Name() != node {
+			return nil
+		}
+		promised := context.checker.GetPromisedTypeOfPromise(context.checker.GetTypeAtLocation(access.Expression()))
+		if promised == nil || context.checker.GetPropertyOfType(promised, node.Text()) == nil {
+			return nil
+		}
+		expressions = []*ast.Node{access.Expression()}
+	case ast.IsBinaryExpression(node):
+		expressions = []*ast.Node{node.AsBinaryExpression().Left, node.AsBinaryExpression().Right}
+	default:
+		expressions = []*ast.Node{node}
+	}
+
+	var changes []TextChange
+	for _, expression := range expressions {
+		if !ast.IsExpressionNode(expression) || ast.IsAwaitExpression(expression) ||
+			context.checker.GetPromisedTypeOfPromise(context.checker.GetTypeAtLocation(expression)) == nil {
+			continue
+		}
+		start := scanner.GetTokenPosOfNode(expression, context.file, false /*includeJSDoc*/)
+		if needsParenthesesForAwait(expression) {
+			changes = append(changes,
+				TextChange{TextRange: core.NewTextRange(start, start), NewText: "(await "},
+				TextChange{TextRange: core.NewTextRange(expression.End(), expression.End()), NewText: ")"})
+		} else {
+			changes = append(changes, TextChange{TextRange: core.NewTextRange(start, start), NewText: "await "})
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return []CodeFix{newCodeFix(diagnostics.Add_await, nil, "fixMissingAwait", context.file.FileName(), changes...)}
+}
+
+// isInAwaitContext reports whether an await expression can be written at node: in an async
+// function, or at the top level of a module.
+func isInAwaitContext(node *ast.Node) bool {
+	container := ast.FindAncestor(node.Parent, func(n *ast.Node) bool {
+		return ast.IsFunctionLike(n) || ast.IsSourceFile(n)
+	})
+	if container == nil {
+		return false
+	}
+	if ast.IsSourceFile(container) {
+		return ast.IsExternalModule(container.AsSourceFile())
+	}
+	return ast.HasSyntacticModifier(container, ast.ModifierFlagsAsync)
+}
+
+func needsParenthesesForAwait(expression *ast.Node) bool {
+	parent := expression.Parent
+	switch parent.Kind {
+	case ast.KindPropertyAccessExpression, ast.KindElementAccessExpression, ast.KindCallExpression, ast.KindNewExpression,
+		ast.KindNonNullExpression:
+		return parent.Expression() == expression
+	case ast.KindTaggedTemplateExpression:
+		return parent.AsTaggedTemplateExpression().Tag == expression
+	}
+	return false
+}
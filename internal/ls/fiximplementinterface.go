@@ -0,0 +1,184 @@
+package ls
+
+import (
+	"strings"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/checker"
+	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/scanner"
+)
+
+var fixImplementInterfaceProvider = &codeFixProvider{
+	errorCodes: []int32{
+		diagnostics.Class_0_incorrectly_implements_interface_1.Code(),
+		diagnostics.Class_0_incorrectly_implements_class_1_Did_you_mean_to_extend_1_and_inherit_its_members_as_a_subclass.Code(),
+	},
+	getCodeFixes:      getImplementInterfaceFixes,
+	fixID:             "fixClassIncorrectlyImplementsInterface",
+	fixAllDescription: diagnostics.Implement_all_unimplemented_interfaces,
+}
+
+var fixImplementAbstractClassProvider = &codeFixProvider{
+	errorCodes: []int32{
+		diagnostics.Non_abstract_class_0_does_not_implement_inherited_abstract_member_1_from_class_2.Code(),
+		diagnostics.Non_abstract_class_expression_does_not_implement_inherited_abstract_member_0_from_class_1.Code(),
+		diagnostics.Non_abstract_class_0_is_missing_implementations_for_the_following_members_of_1_Colon_2.Code(),
+		diagnostics.Non_abstract_class_0_is_missing_implementations_for_the_following_members_of_1_Colon_2_and_3_more.Code(),
+		diagnostics.Non_abstract_class_expression_is_missing_implementations_for_the_following_members_of_0_Colon_1.Code(),
+		diagnostics.Non_abstract_class_expression_is_missing_implementations_for_the_following_members_of_0_Colon_1_and_2_more.Code(),
+	},
+	getCodeFixes:      getImplementAbstractClassFixes,
+	fixID:             "fixClassDoesntImplementInheritedAbstractMember",
+	fixAllDescription: diagnostics.Implement_all_inherited_abstract_classes,
+}
+
+// getImplementInterfaceFixes adds stubs for the members that a class is missing for each of the
+// types in its implements clause.
+func getImplementInterfaceFixes(context *codeFixContext) []CodeFix {
+	class := getClassAtSpan(context)
+	if class == nil {
+		return nil
+	}
+	c := context.checker
+	classType := c.GetDeclaredTypeOfSymbol(class.Symbol())
+	var fixes []CodeFix
+	for _, heritage := range ast.GetImplementsHeritageClauseElements(class) {
+		implemented := c.GetTypeAtLocation(heritage)
+		var missing []*ast.Symbol
+		for _, member := range c.GetPropertiesOfType(implemented) {
+			if c.GetPropertyOfType(classType, member.Name) == nil && !isPrivateMember(member) {
+				missing = append(missing, member)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		change := getMissingMembersChange(context, class, missing)
+		name := scanner.GetTextOfNode(heritage)
+		fixes = append(fixes, newCodeFix(diagnostics.Implement_interface_0, []any{name}, "fixClassIncorrectlyImplementsInterface", context.file.FileName(), change))
+	}
+	return fixes
+}
+
+// getImplementAbstractClassFixes adds stubs for the abstract members that a class inherits without
+// implementing them.
+func getImplementAbstractClassFixes(context *codeFixContext) []CodeFix {
+	class := getClassAtSpan(context)
+	if class == nil {
+		return nil
+	}
+	extends := ast.GetExtendsHeritageClauseElements(class)
+	if len(extends) == 0 {
+		return nil
+	}
+	c := context.checker
+	classType := c.GetDeclaredTypeOfSymbol(class.Symbol())
+	var missing []*ast.Symbol
+	for _, member := range c.GetPropertiesOfType(c.GetTypeAtLocation(extends[0])) {
+		if !isAbstractMember(member) {
+			continue
+		}
+		if inherited := c.GetPropertyOfType(classType, member.Name); inherited == nil || isAbstractMember(inherited) {
+			missing = append(missing, member)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	change := getMissingMembersChange(context, class, missing)
+	return []CodeFix{newCodeFix(diagnostics.Implement_inherited_abstract_class, nil, "fixClassDoesntImplementInheritedAbstractMember", context.file.FileName(), change)}
+}
+
+func getClassAtSpan(context *codeFixContext) *ast.Node {
+	token := context.getTokenAtSpan()
+	if token == nil {
+		return nil
+	}
+	class := ast.FindAncestor(token, ast.IsClassLike)
+	if class == nil || class.Symbol() == nil {
+		return nil
+	}
+	return class
+}
+
+func isAbstractMember(symbol *ast.Symbol) bool {
+	for _, decl := range symbol.Declarations {
+		if ast.GetCombinedModifierFlags(decl)&ast.ModifierFlagsAbstract != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func isPrivateMember(symbol *ast.Symbol) bool {
+	for _, decl := range symbol.Declarations {
+		if ast.GetCombinedModifierFlags(decl)&ast.ModifierFlagsPrivate != 0 {
+			return true
+		}
+		if name := decl.Name(); name != nil && ast.IsPrivateIdentifier(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// getMissingMembersChange returns the insertion of stubs for the given members at the end of a
+// class. Properties are declared with their type and methods throw when they are called.
+func getMissingMembersChange(context *codeFixContext, class *ast.Node, members []*ast.Symbol) TextChange {
+	c := context.checker
+	classMembers := class.Members()
+	indentation := getMemberIndentation(context.file, class, classMembers)
+	classIndentation := getLineIndentation(context.file.Text(), scanner.GetTokenPosOfNode(class, context.file, false /*includeJSDoc*/))
+	indentUnit := "    "
+	if strings.HasPrefix(indentation, classIndentation) && len(indentation) > len(classIndentation) {
+		indentUnit = indentation[len(classIndentation):]
+	}
+
+	var lines []string
+	for _, member := range members {
+		name, ok := getMemberNameText(member.Name)
+		if !ok {
+			continue
+		}
+		modifiers := ""
+		if core.Some(member.Declarations, func(decl *ast.Node) bool {
+			return ast.GetCombinedModifierFlags(decl)&ast.ModifierFlagsProtected != 0
+		}) {
+			modifiers = "protected "
+		}
+		t := c.GetTypeOfSymbolAtLocation(member, class)
+		signatures := c.GetSignaturesOfType(t, checker.SignatureKindCall)
+		if member.Flags&ast.SymbolFlagsMethod == 0 || len(signatures) == 0 {
+			optional := core.IfElse(member.Flags&ast.SymbolFlagsOptional != 0, "?", "")
+			lines = append(lines, modifiers+name+optional+": "+c.TypeToStringEx(t, class, checker.TypeFormatFlagsNoTruncation)+";")
+			continue
+		}
+		implementation := "(...args: any[]): any"
+		if len(signatures) == 1 {
+			implementation = c.SignatureToString(signatures[0])
+		} else {
+			for _, signature := range signatures {
+				lines = append(lines, modifiers+name+c.SignatureToString(signature)+";")
+			}
+		}
+		lines = append(lines,
+			modifiers+name+implementation+" {",
+			indentUnit+"throw new Error(\""+diagnostics.Method_not_implemented.Message()+"\");",
+			"}")
+	}
+	return getInsertionAtEndOfMembers(context.file, class, classMembers, lines, context.newLine)
+}
+
+// getMemberNameText returns the text that declares a member by the given name, quoting it if it
+// is not an identifier. Members with computed symbol names cannot be declared by name.
+func getMemberNameText(name string) (string, bool) {
+	if strings.HasPrefix(name, ast.InternalSymbolNamePrefix) {
+		return "", false
+	}
+	if scanner.IsIdentifierText(name, core.ScriptTargetESNext) {
+		return name, true
+	}
+	return "\"" + strings.ReplaceAll(name, "\"", "\\\"") + "\"", true
+}
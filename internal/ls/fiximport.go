@@ -0,0 +1,170 @@
+package ls
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/scanner"
+	"github.com/microsoft/typescript-go/internal/tspath"
+)
+
+var fixMissingImportProvider = &codeFixProvider{
+	errorCodes: []int32{
+		diagnostics.Cannot_find_name_0.Code(),
+		diagnostics.Cannot_find_name_0_Did_you_mean_1.Code(),
+		diagnostics.Cannot_find_namespace_0.Code(),
+		diagnostics.Cannot_find_namespace_0_Did_you_mean_1.Code(),
+	},
+	getCodeFixes:      getMissingImportFixes,
+	fixID:             "fixMissingImport",
+	fixAllDescription: diagnostics.Add_all_missing_imports,
+}
+
+// importCandidate is an exported symbol that an unresolved name can be imported as.
+type importCandidate struct {
+	moduleSpecifier string
+	// isDefault is set when the name is imported as the default export of the module.
+	isDefault bool
+}
+
+func getMissingImportFixes(context *codeFixContext) []CodeFix {
+	token := context.getTokenAtSpan()
+	if token == nil || !ast.IsIdentifier(token) || !canAddImports(context.program, context.file) {
+		return nil
+	}
+	name := token.Text()
+	candidates := getImportCandidates(context.languageService.getExportInfoMap(), context.file, name, context.preferences)
+	fixes := make([]CodeFix, 0, len(candidates))
+	for _, candidate := range candidates {
+		change, updatesExisting := getAddImportChange(context.file, name, candidate, context.newLine, context.preferences.QuotePreference)
+		title := core.IfElse(updatesExisting, diagnostics.Update_import_from_0, diagnostics.Add_import_from_0)
+		fixes = append(fixes, newCodeFix(title, []any{candidate.moduleSpecifier}, "fixMissingImport", context.file.FileName(), change))
+	}
+	return fixes
+}
+
+// getImportCandidates finds the modules that export a symbol by the given name, either by that
+// name or as a default export declared with it. Modules with shorter specifiers come first, and
+// ambient modules and packages before relative paths into parent directories.
+func getImportCandidates(exports *exportInfoMap, importingFile *ast.SourceFile, name string, preferences *UserPreferences) []importCandidate {
+	var candidates []importCandidate
+	exports.forEachExport(importingFile, func(info *exportInfo) {
+		if info.name != name {
+			return
+		}
+		if candidate, ok := exports.getImportCandidate(importingFile, info, preferences); ok && !slices.Contains(candidates, candidate) {
+			candidates = append(candidates, candidate)
+		}
+	})
+	slices.SortFunc(candidates, func(a, b importCandidate) int {
+		return cmp.Or(
+			cmp.Compare(getModuleSpecifierRank(a.moduleSpecifier), getModuleSpecifierRank(b.moduleSpecifier)),
+			cmp.Compare(strings.Count(a.moduleSpecifier, "/"), strings.Count(b.moduleSpecifier, "/")),
+			strings.Compare(a.moduleSpecifier, b.moduleSpecifier),
+			cmp.Compare(core.IfElse(a.isDefault, 1, 0), core.IfElse(b.isDefault, 1, 0)),
+		)
+	})
+	return candidates
+}
+
+func getModuleSpecifierRank(specifier string) int {
+	switch {
+	case strings.HasPrefix(specifier, "../"):
+		return 2
+	case tspath.PathIsRelative(specifier):
+		return 1
+	}
+	return 0
+}
+
+// getAddImportChange returns the change that imports name from a module: it is added to an
+// existing import of the module if there is one, or else a new import is added after the last one.
+func getAddImportChange(file *ast.SourceFile, name string, candidate importCandidate, newLine string, quotePreference QuotePreference) (TextChange, bool) {
+	var lastImport *ast.Node
+	for _, statement := range file.Statements.Nodes {
+		if !ast.IsImportDeclaration(statement) {
+			continue
+		}
+		lastImport = statement
+		decl := statement.AsImportDeclaration()
+		if decl.ImportClause == nil || !ast.IsStringLiteral(decl.ModuleSpecifier) || decl.ModuleSpecifier.Text() != candidate.moduleSpecifier {
+			continue
+		}
+		clause := decl.ImportClause.AsImportClause()
+		if clause.IsTypeOnly {
+			continue
+		}
+		if candidate.isDefault {
+			if clause.Name() == nil {
+				pos := scanner.GetTokenPosOfNode(decl.ImportClause, file, false /*includeJSDoc*/)
+				return TextChange{TextRange: core.NewTextRange(pos, pos), NewText: name + ", "}, true
+			}
+			continue
+		}
+		if clause.NamedBindings != nil && ast.IsNamedImports(clause.NamedBindings) {
+			elements := clause.NamedBindings.AsNamedImports().Elements
+			if len(elements.Nodes) == 0 {
+				pos := clause.NamedBindings.End() - 1
+				return TextChange{TextRange: core.NewTextRange(pos, pos), NewText: " " + name + " "}, true
+			}
+			last := elements.Nodes[len(elements.Nodes)-1]
+			return TextChange{TextRange: core.NewTextRange(last.End(), last.End()), NewText: ", " + name}, true
+		}
+		if clause.NamedBindings == nil {
+			end := clause.Name().End()
+			return TextChange{TextRange: core.NewTextRange(end, end), NewText: ", { " + name + " }"}, true
+		}
+	}
+
+	quote := getQuoteCharacter(file, quotePreference)
+	var importText string
+	if candidate.isDefault {
+		importText = "import " + name + " from " + quote + candidate.moduleSpecifier + quote + ";"
+	} else {
+		importText = "import { " + name + " } from " + quote + candidate.moduleSpecifier + quote + ";"
+	}
+	if lastImport != nil {
+		return TextChange{TextRange: core.NewTextRange(lastImport.End(), lastImport.End()), NewText: newLine + importText}, false
+	}
+	pos := getInsertionPositionAfterHeader(file)
+	return TextChange{TextRange: core.NewTextRange(pos, pos), NewText: importText + newLine}, false
+}
+
+// getQuoteCharacter returns the quote to write a module specifier in a file with: the preferred
+// one, or else the one that the module specifiers of the file are written with.
+func getQuoteCharacter(file *ast.SourceFile, preference QuotePreference) string {
+	switch preference {
+	case QuotePreferenceSingle:
+		return "'"
+	case QuotePreferenceDouble:
+		return "\""
+	}
+	for _, specifier := range file.Imports {
+		if ast.IsStringLiteral(specifier) {
+			if text := file.Text()[scanner.GetTokenPosOfNode(specifier, file, false /*includeJSDoc*/):]; strings.HasPrefix(text, "'") {
+				return "'"
+			}
+			return "\""
+		}
+	}
+	return "\""
+}
+
+// getInsertionPositionAfterHeader returns the start of the first statement of a file, skipping
+// the comments that precede it only if they are a header separated from it by a blank line.
+func getInsertionPositionAfterHeader(file *ast.SourceFile) int {
+	if len(file.Statements.Nodes) == 0 {
+		return 0
+	}
+	first := file.Statements.Nodes[0]
+	start := scanner.GetTokenPosOfNode(first, file, true /*includeJSDoc*/)
+	text := file.Text()
+	if blankLine := strings.LastIndex(text[:start], "\n\n"); blankLine >= 0 {
+		return blankLine + 2
+	}
+	return 0
+}
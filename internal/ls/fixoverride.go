@@ -0,0 +1,56 @@
+package ls
+
+import (
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/scanner"
+)
+
+var fixOverrideModifierProvider = &codeFixProvider{
+	errorCodes: []int32{
+		diagnostics.This_member_must_have_an_override_modifier_because_it_overrides_a_member_in_the_base_class_0.Code(),
+		diagnostics.This_parameter_property_must_have_an_override_modifier_because_it_overrides_a_member_in_base_class_0.Code(),
+		diagnostics.This_member_must_have_an_override_modifier_because_it_overrides_an_abstract_method_that_is_declared_in_the_base_class_0.Code(),
+	},
+	getCodeFixes:      getOverrideModifierFixes,
+	fixID:             "fixAddOverrideModifier",
+	fixAllDescription: diagnostics.Add_all_missing_override_modifiers,
+}
+
+// getOverrideModifierFixes adds the override modifier to a member that overrides one of its base
+// class under noImplicitOverride.
+func getOverrideModifierFixes(context *codeFixContext) []CodeFix {
+	token := context.getTokenAtSpan()
+	if token == nil {
+		return nil
+	}
+	member := ast.FindAncestor(token, func(node *ast.Node) bool {
+		return ast.IsClassElement(node) || ast.IsParameterPropertyDeclaration(node, node.Parent)
+	})
+	if member == nil || ast.HasSyntacticModifier(member, ast.ModifierFlagsOverride) {
+		return nil
+	}
+	return []CodeFix{newCodeFix(diagnostics.Add_override_modifier, nil, "fixAddOverrideModifier", context.file.FileName(), getAddOverrideModifierChange(context.file, member))}
+}
+
+// getAddOverrideModifierChange inserts override in the position that the grammar requires: after
+// decorators and the accessibility, static and abstract modifiers, and before any others.
+func getAddOverrideModifierChange(file *ast.SourceFile, member *ast.Node) TextChange {
+	var preceding *ast.Node
+	for _, modifier := range member.ModifierNodes() {
+		switch modifier.Kind {
+		case ast.KindDecorator, ast.KindPublicKeyword, ast.KindPrivateKeyword, ast.KindProtectedKeyword,
+			ast.KindStaticKeyword, ast.KindAbstractKeyword, ast.KindDeclareKeyword:
+			preceding = modifier
+			continue
+		}
+		pos := scanner.GetTokenPosOfNode(modifier, file, false /*includeJSDoc*/)
+		return TextChange{TextRange: core.NewTextRange(pos, pos), NewText: "override "}
+	}
+	if preceding != nil {
+		return TextChange{TextRange: core.NewTextRange(preceding.End(), preceding.End()), NewText: " override"}
+	}
+	pos := scanner.GetTokenPosOfNode(member, file, false /*includeJSDoc*/)
+	return TextChange{TextRange: core.NewTextRange(pos, pos), NewText: "override "}
+}
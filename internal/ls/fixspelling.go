@@ -0,0 +1,78 @@
+package ls
+
+import (
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/scanner"
+)
+
+var fixSpellingProvider = &codeFixProvider{
+	errorCodes: []int32{
+		diagnostics.Property_0_does_not_exist_on_type_1_Did_you_mean_2.Code(),
+		diagnostics.Cannot_find_name_0_Did_you_mean_1.Code(),
+		diagnostics.Cannot_find_namespace_0_Did_you_mean_1.Code(),
+		diagnostics.X_0_has_no_exported_member_named_1_Did_you_mean_2.Code(),
+	},
+	getCodeFixes:      getSpellingFixes,
+	fixID:             "fixSpelling",
+	fixAllDescription: diagnostics.Fix_all_detected_spelling_errors,
+}
+
+// getSpellingFixes replaces a misspelled name with the name that the checker suggests for it.
+func getSpellingFixes(context *codeFixContext) []CodeFix {
+	token := context.getTokenAtSpan()
+	if token == nil || !ast.IsIdentifier(token) && !ast.IsPrivateIdentifier(token) {
+		return nil
+	}
+	c := context.checker
+	parent := token.Parent
+
+	var suggestion *ast.Symbol
+	switch {
+	case ast.IsPropertyAccessExpression(parent) && parent.Name() == token:
+		containingType := c.GetNonNullableType(c.GetTypeAtLocation(parent.Expression()))
+		suggestion = c.GetSuggestedSymbolForNonexistentProperty(token, containingType)
+	case ast.IsImportSpecifier(parent) && getImportSpecifierPropertyName(parent) == token:
+		importDecl := ast.FindAncestor(parent, ast.IsImportDeclaration)
+		if importDecl == nil {
+			return nil
+		}
+		moduleSymbol := c.GetSymbolAtLocation(importDecl.AsImportDeclaration().ModuleSpecifier)
+		if moduleSymbol == nil {
+			return nil
+		}
+		suggestion = c.GetSuggestedSymbolForNonexistentModule(token, moduleSymbol)
+	default:
+		meaning := ast.SymbolFlagsValue
+		if context.errorCode == diagnostics.Cannot_find_namespace_0_Did_you_mean_1.Code() {
+			meaning = ast.SymbolFlagsNamespace
+		} else if ast.IsPartOfTypeNode(token) {
+			meaning = ast.SymbolFlagsType
+		}
+		suggestion = c.GetSuggestedSymbolForNonexistentSymbol(token, token.Text(), meaning)
+	}
+	if suggestion == nil {
+		return nil
+	}
+
+	name := ast.SymbolName(suggestion)
+	if ast.IsIdentifier(token) && !scanner.IsIdentifierText(name, core.ScriptTargetESNext) {
+		return nil
+	}
+	start := scanner.GetTokenPosOfNode(token, context.file, false /*includeJSDoc*/)
+	return []CodeFix{newCodeFix(diagnostics.Change_spelling_to_0, []any{name}, "fixSpelling", context.file.FileName(), TextChange{
+		TextRange: core.NewTextRange(start, token.End()),
+		NewText:   name,
+	})}
+}
+
+// getImportSpecifierPropertyName returns the name that an import specifier imports by, which is
+// its property name in `import { a as b }`.
+func getImportSpecifierPropertyName(node *ast.Node) *ast.Node {
+	specifier := node.AsImportSpecifier()
+	if specifier.PropertyName != nil {
+		return specifier.PropertyName
+	}
+	return specifier.Name()
+}
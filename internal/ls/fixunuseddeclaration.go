@@ -0,0 +1,165 @@
+package ls
+
+import (
+	"strings"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/scanner"
+)
+
+var fixUnusedDeclarationProvider = &codeFixProvider{
+	errorCodes: []int32{
+		diagnostics.X_0_is_declared_but_its_value_is_never_read.Code(),
+		diagnostics.X_0_is_declared_but_never_used.Code(),
+		diagnostics.Property_0_is_declared_but_its_value_is_never_read.Code(),
+		diagnostics.All_imports_in_import_declaration_are_unused.Code(),
+		diagnostics.All_destructured_elements_are_unused.Code(),
+		diagnostics.All_variables_are_unused.Code(),
+		diagnostics.All_type_parameters_are_unused.Code(),
+	},
+	getCodeFixes:      getUnusedDeclarationFixes,
+	fixID:             "unusedIdentifier_delete",
+	fixAllDescription: diagnostics.Delete_all_unused_declarations,
+}
+
+// getUnusedDeclarationFixes deletes a declaration that the checker reports as unused under
+// noUnusedLocals or noUnusedParameters.
+func getUnusedDeclarationFixes(context *codeFixContext) []CodeFix {
+	file := context.file
+	newFix := func(title *diagnostics.Message, args []any, deleteRange core.TextRange) []CodeFix {
+		return []CodeFix{newCodeFix(title, args, "unusedIdentifier_delete", file.FileName(), TextChange{TextRange: deleteRange})}
+	}
+
+	if context.errorCode == diagnostics.All_type_parameters_are_unused.Code() {
+		// The diagnostic spans the type parameter list together with its angle brackets.
+		if text := file.Text()[context.span.Pos():context.span.End()]; strings.HasPrefix(text, "<") && strings.HasSuffix(text, ">") {
+			return newFix(diagnostics.Remove_type_parameters, nil, context.span)
+		}
+		return nil
+	}
+
+	token := context.getTokenAtSpan()
+	if token == nil {
+		return nil
+	}
+	switch context.errorCode {
+	case diagnostics.All_imports_in_import_declaration_are_unused.Code():
+		if importDecl := ast.FindAncestor(token, ast.IsImportDeclaration); importDecl != nil {
+			return newFix(diagnostics.Remove_import_from_0, []any{importDecl.AsImportDeclaration().ModuleSpecifier.Text()}, getDeleteRange(file, importDecl))
+		}
+		return nil
+	case diagnostics.All_variables_are_unused.Code():
+		if ast.IsVariableDeclarationList(token) && ast.IsVariableStatement(token.Parent) {
+			return newFix(diagnostics.Remove_variable_statement, nil, getDeleteRange(file, token.Parent))
+		}
+		return nil
+	case diagnostics.All_destructured_elements_are_unused.Code():
+		if !ast.IsBindingPattern(token) || !ast.IsVariableDeclaration(token.Parent) {
+			return nil
+		}
+		if deleteRange, ok := getVariableDeclarationDeleteRange(file, token.Parent); ok {
+			return newFix(diagnostics.Remove_unused_destructuring_declaration, nil, deleteRange)
+		}
+		return nil
+	}
+
+	declaration := token
+	if !ast.IsTypeParameterDeclaration(token) {
+		declaration = token.Parent
+	}
+	if declaration == nil {
+		return nil
+	}
+	name := scanner.GetTextOfNode(core.OrElse(declaration.Name(), token))
+	if deleteRange, ok := getUnusedDeclarationDeleteRange(file, declaration); ok {
+		if ast.IsImportDeclaration(declaration) {
+			return newFix(diagnostics.Remove_import_from_0, []any{declaration.AsImportDeclaration().ModuleSpecifier.Text()}, deleteRange)
+		}
+		return newFix(diagnostics.Remove_unused_declaration_for_Colon_0, []any{name}, deleteRange)
+	}
+	return nil
+}
+
+// getUnusedDeclarationDeleteRange returns the range to delete to remove an unused declaration,
+// widened to the enclosing import or statement when nothing else would be left in it.
+func getUnusedDeclarationDeleteRange(file *ast.SourceFile, declaration *ast.Node) (core.TextRange, bool) {
+	switch declaration.Kind {
+	case ast.KindImportSpecifier:
+		namedImports := declaration.Parent
+		clause := namedImports.Parent
+		elements := namedImports.AsNamedImports().Elements.Nodes
+		if len(elements) > 1 {
+			return getDeleteRangeInList(file, declaration, elements), true
+		}
+		if clause.Name() != nil {
+			// Remove `, { name }` after the default import.
+			return core.NewTextRange(clause.Name().End(), namedImports.End()), true
+		}
+		return getDeleteRange(file, clause.Parent), true
+	case ast.KindImportClause:
+		namedBindings := declaration.AsImportClause().NamedBindings
+		if namedBindings != nil {
+			// Remove `name, ` before the named or namespace imports.
+			return core.NewTextRange(scanner.GetTokenPosOfNode(declaration.Name(), file, false /*includeJSDoc*/), scanner.GetTokenPosOfNode(namedBindings, file, false /*includeJSDoc*/)), true
+		}
+		return getDeleteRange(file, declaration.Parent), true
+	case ast.KindNamespaceImport:
+		clause := declaration.Parent
+		if clause.Name() != nil {
+			return core.NewTextRange(clause.Name().End(), declaration.End()), true
+		}
+		return getDeleteRange(file, clause.Parent), true
+	case ast.KindVariableDeclaration:
+		return getVariableDeclarationDeleteRange(file, declaration)
+	case ast.KindParameter:
+		// Only trailing parameters are removed, so that the arguments of callers still line up.
+		parameters := declaration.Parent.Parameters()
+		if declaration != parameters[len(parameters)-1] {
+			return core.TextRange{}, false
+		}
+		return getDeleteRangeInList(file, declaration, parameters), true
+	case ast.KindBindingElement:
+		return getDeleteRangeInList(file, declaration, declaration.Parent.AsBindingPattern().Elements.Nodes), true
+	case ast.KindTypeParameter:
+		typeParameters := declaration.Parent.TypeParameters()
+		if len(typeParameters) > 1 {
+			return getDeleteRangeInList(file, declaration, typeParameters), true
+		}
+		return getTypeParameterListRange(file, declaration), true
+	case ast.KindFunctionDeclaration, ast.KindClassDeclaration, ast.KindInterfaceDeclaration, ast.KindTypeAliasDeclaration,
+		ast.KindEnumDeclaration, ast.KindModuleDeclaration, ast.KindMethodDeclaration, ast.KindPropertyDeclaration,
+		ast.KindGetAccessor, ast.KindSetAccessor:
+		return getDeleteRange(file, declaration), true
+	}
+	return core.TextRange{}, false
+}
+
+func getVariableDeclarationDeleteRange(file *ast.SourceFile, declaration *ast.Node) (core.TextRange, bool) {
+	list := declaration.Parent
+	if !ast.IsVariableDeclarationList(list) || !ast.IsVariableStatement(list.Parent) {
+		// Declarations of for statements cannot be removed without rewriting the loop.
+		return core.TextRange{}, false
+	}
+	declarations := list.AsVariableDeclarationList().Declarations.Nodes
+	if len(declarations) > 1 {
+		return getDeleteRangeInList(file, declaration, declarations), true
+	}
+	return getDeleteRange(file, list.Parent), true
+}
+
+// getTypeParameterListRange returns the range of the angle brackets around the only type
+// parameter of a declaration.
+func getTypeParameterListRange(file *ast.SourceFile, typeParameter *ast.Node) core.TextRange {
+	text := file.Text()
+	start := scanner.GetTokenPosOfNode(typeParameter, file, false /*includeJSDoc*/)
+	for start > 0 && text[start] != '<' {
+		start--
+	}
+	end := typeParameter.End()
+	for end < len(text) && text[end] != '>' {
+		end++
+	}
+	return core.NewTextRange(start, min(end+1, len(text)))
+}
@@ -0,0 +1,266 @@
+package ls
+
+import (
+	"iter"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/astnav"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"github.com/microsoft/typescript-go/internal/scanner"
+)
+
+// outliningSpan is a region of a file that can be collapsed. kind is empty for code.
+type outliningSpan struct {
+	span          core.TextRange
+	kind          lsproto.FoldingRangeKind
+	collapsedText string
+}
+
+// ProvideFoldingRanges returns the regions of a file that can be folded: bracketed code, runs of
+// imports, comments and #region blocks. Folding only looks at the syntax of the file, so it does
+// not wait for the program to be checked.
+func (l *LanguageService) ProvideFoldingRanges(fileName string) []lsproto.FoldingRange {
+	_, file := l.getProgramAndFile(fileName)
+	text := file.Text()
+	var result []lsproto.FoldingRange
+	for _, outlining := range getOutliningSpans(file) {
+		lspRange, err := l.converters.ToLSPRange(file.FileName(), outlining.span)
+		if err != nil {
+			continue
+		}
+		// The line that closes a bracketed region stays visible, so that folding a block does not
+		// hide its closing brace and whatever follows it.
+		endLine := lspRange.End.Line
+		if end := outlining.span.End(); lspRange.End.Character > 0 && endLine > lspRange.Start.Line && strings.IndexByte("}])`>", text[end-1]) >= 0 {
+			endLine--
+		}
+		if endLine == lspRange.Start.Line {
+			continue
+		}
+		foldingRange := lsproto.FoldingRange{
+			StartLine: lspRange.Start.Line,
+			EndLine:   endLine,
+		}
+		if outlining.kind != "" {
+			foldingRange.Kind = ptrTo(outlining.kind)
+		}
+		if outlining.collapsedText != "" {
+			foldingRange.CollapsedText = ptrTo(outlining.collapsedText)
+		}
+		result = append(result, foldingRange)
+	}
+	return result
+}
+
+func getOutliningSpans(file *ast.SourceFile) []outliningSpan {
+	c := &outliningCollector{file: file, visitedTrivia: make(map[int]bool)}
+	c.addRegionSpans()
+	c.addImportSpans(file.Statements.Nodes)
+
+	var visit func(node *ast.Node) bool
+	visit = func(node *ast.Node) bool {
+		c.addCommentSpans(node.Pos())
+		c.addNodeSpan(node)
+		node.ForEachChild(visit)
+		return false
+	}
+	file.AsNode().ForEachChild(visit)
+	// The comments after the last statement are the leading comments of the end of the file.
+	c.addCommentSpans(file.Statements.End())
+
+	slices.SortStableFunc(c.spans, func(a, b outliningSpan) int {
+		return a.span.Pos() - b.span.Pos()
+	})
+	return c.spans
+}
+
+type outliningCollector struct {
+	file  *ast.SourceFile
+	spans []outliningSpan
+	// visitedTrivia holds the positions whose leading comments have been collected, since a node
+	// and its first child start at the same position.
+	visitedTrivia map[int]bool
+}
+
+func (c *outliningCollector) add(pos int, end int, kind lsproto.FoldingRangeKind) {
+	c.spans = append(c.spans, outliningSpan{span: core.NewTextRange(pos, end), kind: kind})
+}
+
+// addImportSpans groups each run of consecutive imports into one region.
+func (c *outliningCollector) addImportSpans(statements []*ast.Node) {
+	for i := 0; i < len(statements); i++ {
+		if !ast.IsAnyImportSyntax(statements[i]) {
+			continue
+		}
+		first := i
+		for i+1 < len(statements) && ast.IsAnyImportSyntax(statements[i+1]) {
+			i++
+		}
+		if i > first {
+			c.add(scanner.GetTokenPosOfNode(statements[first], c.file, false /*includeJSDoc*/), statements[i].End(), lsproto.FoldingRangeKindImports)
+		}
+	}
+}
+
+// addCommentSpans adds the multi-line comments of the trivia at pos, and its runs of single-line
+// comments that are not region markers.
+func (c *outliningCollector) addCommentSpans(pos int) {
+	if c.visitedTrivia[pos] {
+		return
+	}
+	c.visitedTrivia[pos] = true
+
+	text := c.file.Text()
+	runStart, runEnd, runLength := 0, 0, 0
+	endRun := func() {
+		if runLength > 1 {
+			c.add(runStart, runEnd, lsproto.FoldingRangeKindComment)
+		}
+		runLength = 0
+	}
+	for comment := range scanner.GetLeadingCommentRanges(&ast.NodeFactory{}, text, pos) {
+		if comment.Kind == ast.KindMultiLineCommentTrivia {
+			endRun()
+			c.add(comment.Pos(), comment.End(), lsproto.FoldingRangeKindComment)
+			continue
+		}
+		if _, _, ok := parseRegionDelimiter(text[comment.Pos():comment.End()]); ok {
+			endRun()
+			continue
+		}
+		if runLength == 0 {
+			runStart = comment.Pos()
+		}
+		runEnd = comment.End()
+		runLength++
+	}
+	endRun()
+}
+
+var regionDelimiterPattern = regexp.MustCompile(`^#(end)?region(?:\s+(.*))?$`)
+
+// parseRegionDelimiter parses a line comment that starts or ends a region, as in
+// `// #region name` or `// #endregion`.
+func parseRegionDelimiter(line string) (name string, isEnd bool, ok bool) {
+	line = strings.TrimLeft(line, " \t")
+	if !strings.HasPrefix(line, "//") {
+		return "", false, false
+	}
+	match := regionDelimiterPattern.FindStringSubmatch(strings.TrimSpace(line[2:]))
+	if match == nil {
+		return "", false, false
+	}
+	return strings.TrimSpace(match[2]), match[1] != "", true
+}
+
+// addRegionSpans adds the regions between matching #region and #endregion comments. A region
+// spans from the start of its #region comment to the end of the line of its #endregion comment.
+func (c *outliningCollector) addRegionSpans() {
+	text := c.file.Text()
+	lineStarts := scanner.GetLineStarts(c.file)
+	var open []outliningSpan
+	for i, lineStart := range lineStarts {
+		lineEnd := len(text)
+		if i+1 < len(lineStarts) {
+			lineEnd = int(lineStarts[i+1])
+		}
+		line := strings.TrimRight(text[lineStart:lineEnd], "\r\n")
+		lineEnd = int(lineStart) + len(line)
+		name, isEnd, ok := parseRegionDelimiter(line)
+		if !ok {
+			continue
+		}
+		commentStart := int(lineStart) + strings.Index(line, "//")
+		if !c.isLineCommentAt(commentStart) {
+			continue
+		}
+		if !isEnd {
+			open = append(open, outliningSpan{
+				span:          core.NewTextRange(commentStart, lineEnd),
+				kind:          lsproto.FoldingRangeKindRegion,
+				collapsedText: core.OrElse(name, "#region"),
+			})
+		} else if len(open) > 0 {
+			region := open[len(open)-1]
+			open = open[:len(open)-1]
+			region.span = core.NewTextRange(region.span.Pos(), lineEnd)
+			c.spans = append(c.spans, region)
+		}
+	}
+}
+
+// isLineCommentAt reports whether a line comment starts at pos, which rules out a region marker
+// that is inside a string, a template or a block comment.
+func (c *outliningCollector) isLineCommentAt(pos int) bool {
+	triviaStart := 0
+	if token := astnav.FindPrecedingToken(c.file, pos); token != nil {
+		if token.End() > pos {
+			return false
+		}
+		triviaStart = token.End()
+	}
+	text := c.file.Text()
+	factory := &ast.NodeFactory{}
+	for _, comments := range []iter.Seq[ast.CommentRange]{
+		scanner.GetTrailingCommentRanges(factory, text, triviaStart),
+		scanner.GetLeadingCommentRanges(factory, text, triviaStart),
+	} {
+		for comment := range comments {
+			if comment.Pos() == pos {
+				return comment.Kind == ast.KindSingleLineCommentTrivia
+			}
+		}
+	}
+	return false
+}
+
+func (c *outliningCollector) addNodeSpan(node *ast.Node) {
+	switch node.Kind {
+	case ast.KindBlock:
+		c.addBracketedSpan(node, node.AsBlock().Statements, '{')
+	case ast.KindModuleBlock:
+		c.addBracketedSpan(node, node.AsModuleBlock().Statements, '{')
+	case ast.KindCaseBlock:
+		c.addBracketedSpan(node, node.AsCaseBlock().Clauses, '{')
+	case ast.KindClassDeclaration, ast.KindClassExpression, ast.KindInterfaceDeclaration, ast.KindEnumDeclaration, ast.KindTypeLiteral:
+		c.addBracketedSpan(node, node.MemberList(), '{')
+	case ast.KindObjectLiteralExpression:
+		c.addBracketedSpan(node, node.AsObjectLiteralExpression().Properties, '{')
+	case ast.KindObjectBindingPattern:
+		c.addBracketedSpan(node, node.AsBindingPattern().Elements, '{')
+	case ast.KindNamedImports:
+		c.addBracketedSpan(node, node.AsNamedImports().Elements, '{')
+	case ast.KindNamedExports:
+		c.addBracketedSpan(node, node.AsNamedExports().Elements, '{')
+	case ast.KindArrayLiteralExpression:
+		c.addBracketedSpan(node, node.AsArrayLiteralExpression().Elements, '[')
+	case ast.KindArrayBindingPattern:
+		c.addBracketedSpan(node, node.AsBindingPattern().Elements, '[')
+	case ast.KindTupleType:
+		c.addBracketedSpan(node, node.AsTupleTypeNode().Elements, '[')
+	case ast.KindCallExpression, ast.KindNewExpression:
+		if arguments := node.ArgumentList(); arguments != nil && len(arguments.Nodes) != 0 {
+			c.addBracketedSpan(node, arguments, '(')
+		}
+	case ast.KindCaseClause, ast.KindDefaultClause:
+		if statements := node.AsCaseOrDefaultClause().Statements.Nodes; len(statements) != 0 {
+			c.add(scanner.GetTokenPosOfNode(node, c.file, false /*includeJSDoc*/), statements[len(statements)-1].End(), "")
+		}
+	case ast.KindJsxElement, ast.KindJsxFragment, ast.KindJsxSelfClosingElement, ast.KindTemplateExpression, ast.KindNoSubstitutionTemplateLiteral:
+		c.add(scanner.GetTokenPosOfNode(node, c.file, false /*includeJSDoc*/), node.End(), "")
+	}
+}
+
+// addBracketedSpan adds the span from the bracket that opens list to the end of node, which ends
+// with the matching closing bracket.
+func (c *outliningCollector) addBracketedSpan(node *ast.Node, list *ast.NodeList, open byte) {
+	openPos := list.Pos() - 1
+	if openPos < 0 || c.file.Text()[openPos] != open {
+		return
+	}
+	c.add(openPos, node.End(), "")
+}
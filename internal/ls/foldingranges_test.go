@@ -0,0 +1,106 @@
+package ls_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"gotest.tools/v3/assert"
+)
+
+// formatFoldingRanges prints each folding range as its first and last line, followed by its kind
+// and collapsed text if it has them.
+func formatFoldingRanges(ranges []lsproto.FoldingRange) []string {
+	result := make([]string, 0, len(ranges))
+	for _, foldingRange := range ranges {
+		s := fmt.Sprintf("%d-%d", foldingRange.StartLine, foldingRange.EndLine)
+		if foldingRange.Kind != nil {
+			s += " " + string(*foldingRange.Kind)
+		}
+		if foldingRange.CollapsedText != nil {
+			s += " " + *foldingRange.CollapsedText
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+func TestFoldingRanges(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		title    string
+		text     string
+		expected []string
+	}{
+		{
+			title: "blocks",
+			text: `class Counter {
+    increment() {
+        if (true) {
+            return 1;
+        }
+    }
+}
+function single() { return 1; }`,
+			// The closing line of a block stays visible
+			expected: []string{"0-5", "1-4", "2-3"},
+		},
+		{
+			title: "imports and comments",
+			text: `import { a } from "./a";
+import { b } from "./b";
+import { c } from "./c";
+
+/**
+ * A doc comment.
+ */
+// one
+// two
+const x = [
+    1,
+    2,
+];`,
+			expected: []string{"0-2 imports", "4-6 comment", "7-8 comment", "9-11"},
+		},
+		{
+			title: "regions",
+			text: `// #region Helpers
+function a() {}
+// #region
+function b() {}
+// #endregion
+// #endregion
+const s = "// #region in a string";`,
+			expected: []string{"0-5 region Helpers", "2-4 region #region"},
+		},
+		{
+			title: "switch, calls and templates",
+			text: `switch (x) {
+    case 1:
+        a();
+        break;
+    default:
+        b();
+}
+call(
+    1,
+    2);
+const t = ` + "`" + `
+line
+` + "`" + `;`,
+			expected: []string{"0-5", "1-3", "4-5", "7-8", "10-11"},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.title, func(t *testing.T) {
+			t.Parallel()
+			p := newTestProject(t, map[string]string{
+				"/home/src/project/index.ts": testCase.text,
+			})
+			l := p.languageService("/home/src/project/index.ts")
+			assert.DeepEqual(t, formatFoldingRanges(l.ProvideFoldingRanges("/home/src/project/index.ts")), testCase.expected)
+		})
+	}
+}
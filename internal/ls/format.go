@@ -0,0 +1,868 @@
+package ls
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/astnav"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/scanner"
+	"github.com/microsoft/typescript-go/internal/stringutil"
+)
+
+type SemicolonPreference string
+
+const (
+	SemicolonPreferenceIgnore SemicolonPreference = "ignore"
+	SemicolonPreferenceInsert SemicolonPreference = "insert"
+	SemicolonPreferenceRemove SemicolonPreference = "remove"
+)
+
+// FormatCodeSettings controls the layout that the formatter produces. The settings are named after
+// the formatting options of tsserver, and GetDefaultFormatCodeSettings returns their defaults.
+type FormatCodeSettings struct {
+	IndentSize             int
+	TabSize                int
+	NewLineCharacter       string
+	ConvertTabsToSpaces    bool
+	TrimTrailingWhitespace bool
+	IndentSwitchCase       bool
+
+	InsertSpaceAfterCommaDelimiter                              bool
+	InsertSpaceAfterSemicolonInForStatements                    bool
+	InsertSpaceBeforeAndAfterBinaryOperators                    bool
+	InsertSpaceAfterConstructor                                 bool
+	InsertSpaceAfterKeywordsInControlFlowStatements             bool
+	InsertSpaceAfterFunctionKeywordForAnonymousFunctions        bool
+	InsertSpaceAfterOpeningAndBeforeClosingNonemptyParenthesis  bool
+	InsertSpaceAfterOpeningAndBeforeClosingNonemptyBrackets     bool
+	InsertSpaceAfterOpeningAndBeforeClosingNonemptyBraces       bool
+	InsertSpaceAfterOpeningAndBeforeClosingEmptyBraces          bool
+	InsertSpaceAfterOpeningAndBeforeClosingTemplateStringBraces bool
+	InsertSpaceAfterOpeningAndBeforeClosingJsxExpressionBraces  bool
+	InsertSpaceAfterTypeAssertion                               bool
+	InsertSpaceBeforeFunctionParenthesis                        bool
+	InsertSpaceBeforeTypeAnnotation                             bool
+
+	PlaceOpenBraceOnNewLineForFunctions              bool
+	PlaceOpenBraceOnNewLineForControlBlocks          bool
+	IndentMultiLineObjectLiteralBeginningOnBlankLine bool
+	Semicolons                                       SemicolonPreference
+}
+
+func GetDefaultFormatCodeSettings(newLine string) *FormatCodeSettings {
+	return &FormatCodeSettings{
+		IndentSize:                                            4,
+		TabSize:                                               4,
+		NewLineCharacter:                                      newLine,
+		ConvertTabsToSpaces:                                   true,
+		TrimTrailingWhitespace:                                true,
+		IndentSwitchCase:                                      true,
+		InsertSpaceAfterCommaDelimiter:                        true,
+		InsertSpaceAfterSemicolonInForStatements:              true,
+		InsertSpaceBeforeAndAfterBinaryOperators:              true,
+		InsertSpaceAfterKeywordsInControlFlowStatements:       true,
+		InsertSpaceAfterOpeningAndBeforeClosingNonemptyBraces: true,
+		InsertSpaceAfterOpeningAndBeforeClosingEmptyBraces:    true,
+		Semicolons:                                            SemicolonPreferenceIgnore,
+	}
+}
+
+type formatRequestKind int
+
+const (
+	formatRequestKindDocument formatRequestKind = iota
+	formatRequestKindSelection
+	formatRequestKindOnEnter
+	formatRequestKindOnSemicolon
+	formatRequestKindOnClosingCurlyBrace
+)
+
+// ProvideFormatDocument returns the edits that format a file.
+func (l *LanguageService) ProvideFormatDocument(fileName string, settings *FormatCodeSettings) []TextChange {
+	_, file := l.getProgramAndFile(fileName)
+	return formatSpan(file, core.NewTextRange(0, file.End()), settings, formatRequestKindDocument)
+}
+
+// ProvideFormatRange returns the edits that format the lines of a file that span touches.
+func (l *LanguageService) ProvideFormatRange(fileName string, span core.TextRange, settings *FormatCodeSettings) []TextChange {
+	_, file := l.getProgramAndFile(fileName)
+	lineStarts := file.LineMap()
+	start := int(lineStarts[scanner.ComputeLineOfPosition(lineStarts, span.Pos())])
+	return formatSpan(file, core.NewTextRange(start, span.End()), settings, formatRequestKindSelection)
+}
+
+// ProvideFormatOnType returns the edits that format the code around position after key was typed
+// there: the line that a new line ends, or the statement or member that a ; or } ends.
+func (l *LanguageService) ProvideFormatOnType(fileName string, position int, key string, settings *FormatCodeSettings) []TextChange {
+	_, file := l.getProgramAndFile(fileName)
+	switch key {
+	case "\n", "\r\n":
+		return formatOnEnter(file, position, settings)
+	case ";":
+		return formatOutermostNodeEndingAt(file, position, ast.KindSemicolonToken, settings, formatRequestKindOnSemicolon)
+	case "}":
+		return formatOutermostNodeEndingAt(file, position, ast.KindCloseBraceToken, settings, formatRequestKindOnClosingCurlyBrace)
+	}
+	return nil
+}
+
+// formatOnEnter formats the line before position and the code on the line of position, so that
+// the line that was ended is cleaned up and the new line is indented.
+func formatOnEnter(file *ast.SourceFile, position int, settings *FormatCodeSettings) []TextChange {
+	lineStarts := file.LineMap()
+	line := scanner.ComputeLineOfPosition(lineStarts, position)
+	if line == 0 {
+		return nil
+	}
+	text := file.Text()
+	end := len(text)
+	if line+1 < len(lineStarts) {
+		end = int(lineStarts[line+1])
+	}
+	// The span ends after the last character of the line that is not whitespace.
+	for end > int(lineStarts[line]) {
+		ch, size := utf8.DecodeLastRuneInString(text[:end])
+		if !stringutil.IsWhiteSpaceLike(ch) {
+			break
+		}
+		end -= size
+	}
+	return formatSpan(file, core.NewTextRange(int(lineStarts[line-1]), max(end, position)), settings, formatRequestKindOnEnter)
+}
+
+func formatOutermostNodeEndingAt(file *ast.SourceFile, position int, kind ast.Kind, settings *FormatCodeSettings, requestKind formatRequestKind) []TextChange {
+	token := astnav.FindPrecedingToken(file, position)
+	if token == nil || token.Kind != kind || token.End() != position {
+		return nil
+	}
+	node := findOutermostNodeWithinListLevel(token)
+	lineStarts := file.LineMap()
+	start := int(lineStarts[scanner.ComputeLineOfPosition(lineStarts, scanner.GetTokenPosOfNode(node, file, false /*includeJSDoc*/))])
+	return formatSpan(file, core.NewTextRange(start, node.End()), settings, requestKind)
+}
+
+// findOutermostNodeWithinListLevel returns the outermost node that ends with a token, without
+// leaving the list of statements or members that the token is in.
+func findOutermostNodeWithinListLevel(node *ast.Node) *ast.Node {
+	for node.Parent != nil && node.Parent.End() == node.End() && !isListElement(node.Parent, node) {
+		node = node.Parent
+	}
+	return node
+}
+
+func isListElement(parent *ast.Node, node *ast.Node) bool {
+	var list *ast.NodeList
+	switch parent.Kind {
+	case ast.KindClassDeclaration, ast.KindClassExpression, ast.KindInterfaceDeclaration:
+		list = parent.MemberList()
+	case ast.KindModuleDeclaration:
+		if body := parent.Body(); body != nil && ast.IsModuleBlock(body) {
+			list = body.AsModuleBlock().Statements
+		}
+	case ast.KindSourceFile:
+		list = parent.AsSourceFile().Statements
+	case ast.KindBlock:
+		list = parent.AsBlock().Statements
+	case ast.KindModuleBlock:
+		list = parent.AsModuleBlock().Statements
+	case ast.KindCatchClause:
+		list = parent.AsCatchClause().Block.AsBlock().Statements
+	}
+	return list != nil && list.Pos() <= node.Pos() && node.End() <= list.End()
+}
+
+type formatToken struct {
+	kind ast.Kind
+	pos  int
+	end  int
+}
+
+// formatIndentation is the indentation of a node: the column its lines start at, and the amount
+// that the lines of its children are indented by relative to it.
+type formatIndentation struct {
+	node        *ast.Node
+	indentation int
+	delta       int
+	// line is the line that the node starts on, and undecoratedLine the line of its first token
+	// after its decorators, whose position is undecoratedStart, or -1 if it has no decorators.
+	line             int
+	undecoratedLine  int
+	undecoratedStart int
+}
+
+type formatLineAction int
+
+const (
+	formatLineActionNone formatLineAction = iota
+	formatLineActionLineAdded
+	formatLineActionLineRemoved
+)
+
+// formatter visits the tokens of a file in order, applies the formatting rules to each pair of
+// adjacent tokens and indents the tokens that start a line. Only the tokens within span are
+// formatted, but the whole file is visited so that the indentation of the code in span is
+// computed from the code around it.
+type formatter struct {
+	file        *ast.SourceFile
+	text        string
+	settings    *FormatCodeSettings
+	requestKind formatRequestKind
+	span        core.TextRange
+	lineStarts  []core.TextPos
+	scanner     *scanner.Scanner
+	errors      []core.TextRange
+	changes     []TextChange
+
+	hasPrevious    bool
+	previous       formatToken
+	previousParent *ast.Node
+	// lineIndentation records the indentation given to the lines that were indented.
+	lineIndentation map[int]int
+	// braces caches the positions of the { and } of nodes, or -1 for those without one.
+	braces map[*ast.Node][2]int
+}
+
+func formatSpan(file *ast.SourceFile, span core.TextRange, settings *FormatCodeSettings, requestKind formatRequestKind) []TextChange {
+	f := &formatter{
+		file:            file,
+		text:            file.Text(),
+		settings:        settings,
+		requestKind:     requestKind,
+		span:            span,
+		lineStarts:      file.LineMap(),
+		scanner:         scanner.GetScannerForSourceFile(file, 0),
+		lineIndentation: make(map[int]int),
+		braces:          make(map[*ast.Node][2]int),
+	}
+	for _, diagnostic := range file.Diagnostics() {
+		f.errors = append(f.errors, diagnostic.Loc())
+	}
+	root := &formatIndentation{node: file.AsNode(), undecoratedStart: -1}
+	f.processNode(root)
+	f.consumeToken(formatToken{kind: ast.KindEndOfFile, pos: len(f.text), end: len(f.text)}, root)
+	slices.SortStableFunc(f.changes, func(a, b TextChange) int {
+		return cmp.Or(cmp.Compare(a.Pos(), b.Pos()), cmp.Compare(a.End(), b.End()))
+	})
+	return f.changes
+}
+
+func (f *formatter) processNode(ctx *formatIndentation) {
+	node := ctx.node
+	pos := node.Pos()
+	node.ForEachChild(func(child *ast.Node) bool {
+		if !isFormattedChild(child) || child.Pos() < pos {
+			return false
+		}
+		f.forEachGapToken(pos, child.Pos(), func(token formatToken) {
+			f.consumeToken(token, ctx)
+		})
+		if ast.IsTokenKind(child.Kind) {
+			f.consumeToken(f.leafToken(child), ctx)
+		} else {
+			f.processNode(f.getChildIndentation(ctx, child))
+		}
+		pos = child.End()
+		return false
+	})
+	f.forEachGapToken(pos, node.End(), func(token formatToken) {
+		f.consumeToken(token, ctx)
+	})
+}
+
+func isFormattedChild(child *ast.Node) bool {
+	return child.Flags&ast.NodeFlagsReparsed == 0 && child.Pos() != child.End() && !ast.IsWhitespaceOnlyJsxText(child)
+}
+
+// forEachGapToken calls fn with the tokens between pos and end, which lie between the children
+// of a node and belong to it.
+func (f *formatter) forEachGapToken(pos int, end int, fn func(token formatToken)) {
+	if pos >= end {
+		return
+	}
+	f.scanner.ResetPos(pos)
+	for f.scanner.Scan() != ast.KindEndOfFile && f.scanner.TokenEnd() <= end {
+		token := formatToken{kind: f.scanner.Token(), pos: f.scanner.TokenStart(), end: f.scanner.TokenEnd()}
+		fn(token)
+		f.scanner.ResetPos(token.end)
+	}
+}
+
+func (f *formatter) leafToken(child *ast.Node) formatToken {
+	token := formatToken{kind: child.Kind, pos: scanner.GetTokenPosOfNode(child, f.file, false /*includeJSDoc*/), end: child.End()}
+	if child.Kind == ast.KindJsxText {
+		// The whitespace that ends JSX text is left to the indentation of the next token.
+		token.end = token.pos + len(strings.TrimRightFunc(f.text[token.pos:token.end], stringutil.IsWhiteSpaceLike))
+	}
+	return token
+}
+
+// firstTokenOf returns the first token of a node and the node it belongs to.
+func (f *formatter) firstTokenOf(node *ast.Node) (formatToken, *ast.Node, bool) {
+	for {
+		var first *ast.Node
+		node.ForEachChild(func(child *ast.Node) bool {
+			if isFormattedChild(child) {
+				first = child
+				return true
+			}
+			return false
+		})
+		end := node.End()
+		if first != nil {
+			end = first.Pos()
+		}
+		var token formatToken
+		found := false
+		f.forEachGapToken(node.Pos(), end, func(t formatToken) {
+			if !found {
+				token, found = t, true
+			}
+		})
+		if found {
+			return token, node, true
+		}
+		if first == nil {
+			return formatToken{}, nil, false
+		}
+		if ast.IsTokenKind(first.Kind) {
+			return f.leafToken(first), node, true
+		}
+		node = first
+	}
+}
+
+// getChildIndentation computes the indentation of a child from that of its parent. A child that
+// starts on the line of its parent shares its indentation, a child that starts a line in span is
+// indented relative to its parent, and any other child keeps the indentation of its line.
+func (f *formatter) getChildIndentation(parent *formatIndentation, child *ast.Node) *formatIndentation {
+	start := scanner.GetTokenPosOfNode(child, f.file, false /*includeJSDoc*/)
+	result := &formatIndentation{node: child, line: f.lineOf(start), undecoratedStart: getUndecoratedStart(f.text, child)}
+	result.undecoratedLine = result.line
+	if result.undecoratedStart >= 0 {
+		result.undecoratedLine = f.lineOf(result.undecoratedStart)
+	}
+	ownDelta := 0
+	if nodeWillIndentChild(f.settings, f.file, child, nil, false /*indentByDefault*/) {
+		ownDelta = f.settings.IndentSize
+	}
+	effectiveDelta := 0
+	if nodeWillIndentChild(f.settings, f.file, parent.node, child, true /*indentByDefault*/) {
+		effectiveDelta = parent.delta
+	}
+	parentLine := parent.undecoratedLine
+	if ast.IsDecorator(child) {
+		parentLine = parent.line
+	}
+	inSpan := f.inSpan(start, start)
+	// A child that only comments precede on its line starts the line as well.
+	startsLine := f.isFirstOnLine(start) || f.hasPrevious && f.lineOf(f.previous.end) < result.line
+	lineAction := formatLineActionNone
+	if inSpan && f.hasPrevious {
+		// A rule may move the child to a line of its own or join it to the previous line.
+		if token, tokenParent, ok := f.firstTokenOf(child); ok {
+			lineAction = f.predictLineAction(token, tokenParent)
+		}
+	}
+	switch lineAction {
+	case formatLineActionLineAdded:
+		startsLine = true
+	case formatLineActionLineRemoved:
+		startsLine = false
+	}
+	if result.line == parentLine && lineAction != formatLineActionLineAdded {
+		result.indentation = parent.indentation
+		result.delta = min(f.settings.IndentSize, effectiveDelta+ownDelta)
+		return result
+	}
+	result.delta = ownDelta
+	switch {
+	case startsLine && inSpan && start == parent.undecoratedStart:
+		result.indentation = parent.indentation
+	case startsLine && inSpan:
+		result.indentation = parent.indentation + effectiveDelta
+	case startsLine:
+		result.indentation = f.getLineIndentation(result.line)
+	default:
+		result.indentation = f.getLineIndentation(f.lineOf(f.previous.end))
+	}
+	return result
+}
+
+// getUndecoratedStart returns the position of the first token of a node after its decorators, or
+// -1 if it has none.
+func getUndecoratedStart(text string, node *ast.Node) int {
+	modifiers := node.Modifiers()
+	if modifiers == nil {
+		return -1
+	}
+	var lastDecorator *ast.Node
+	for _, modifier := range modifiers.Nodes {
+		if ast.IsDecorator(modifier) {
+			lastDecorator = modifier
+		}
+	}
+	if lastDecorator == nil {
+		return -1
+	}
+	return scanner.SkipTrivia(text, lastDecorator.End())
+}
+
+// getTokenIndentation returns the indentation of a token of a node that starts a line. Closing
+// tokens and the tokens that continue a statement, like else, line up with the node rather than
+// being indented within it.
+func (f *formatter) getTokenIndentation(ctx *formatIndentation, token formatToken) int {
+	if f.lineOf(token.pos) == ctx.line || token.pos == ctx.undecoratedStart || !tokenIsIndentedInNode(token.kind, ctx.node) {
+		return ctx.indentation
+	}
+	return ctx.indentation + ctx.delta
+}
+
+func tokenIsIndentedInNode(kind ast.Kind, node *ast.Node) bool {
+	switch kind {
+	case ast.KindOpenBraceToken, ast.KindCloseBraceToken, ast.KindCloseParenToken, ast.KindElseKeyword, ast.KindWhileKeyword, ast.KindAtToken:
+		return false
+	case ast.KindSlashToken, ast.KindGreaterThanToken:
+		switch node.Kind {
+		case ast.KindJsxOpeningElement, ast.KindJsxClosingElement, ast.KindJsxSelfClosingElement:
+			return false
+		}
+	case ast.KindOpenBracketToken, ast.KindCloseBracketToken:
+		return node.Kind == ast.KindMappedType
+	}
+	return true
+}
+
+// getCommentIndentation returns the indentation of a comment on a line of its own before a token.
+// Comments before a closing token are indented like the code before them.
+func (f *formatter) getCommentIndentation(ctx *formatIndentation, token formatToken) int {
+	switch token.kind {
+	case ast.KindCloseBraceToken, ast.KindCloseBracketToken, ast.KindCloseParenToken:
+		return ctx.indentation + ctx.delta
+	}
+	return f.getTokenIndentation(ctx, token)
+}
+
+// consumeToken formats a token of ctx.node: the trivia before it, the whitespace between it and
+// the previous token, and its indentation.
+func (f *formatter) consumeToken(token formatToken, ctx *formatIndentation) {
+	gapStart := 0
+	if f.hasPrevious {
+		gapStart = f.previous.end
+	}
+	comments, isTrivia := f.scanTrivia(gapStart, token.pos)
+	tokenInSpan := f.inSpan(token.pos, token.end)
+	hasError := f.hasError(token)
+	isEndOfFile := token.kind == ast.KindEndOfFile
+
+	lineAction := formatLineActionNone
+	gapReplaced := false
+	tokenIndented := false
+	if isTrivia && tokenInSpan && !hasError && f.pairInSpan() {
+		c := f.newFormattingContext(token, ctx.node)
+		spaceRule, tokenRule := getFormatRulesForContext(c)
+		if spaceRule != nil && len(comments) == 0 {
+			lineAction, gapReplaced, tokenIndented = f.applySpaceRule(spaceRule, token, ctx)
+		}
+		if tokenRule != nil {
+			f.applyTokenRule(tokenRule)
+		}
+	}
+
+	if isTrivia && !gapReplaced {
+		f.formatTrivia(gapStart, token, comments, f.getCommentIndentation(ctx, token))
+	}
+
+	if tokenInSpan && !hasError && !isEndOfFile && !tokenIndented && lineAction == formatLineActionNone && f.isFirstOnLine(token.pos) {
+		indentation := f.getTokenIndentation(ctx, token)
+		f.indentLine(token.pos, indentation)
+		f.lineIndentation[f.lineOf(token.pos)] = indentation
+	}
+
+	f.hasPrevious = true
+	f.previous = token
+	f.previousParent = ctx.node
+}
+
+// pairInSpan reports whether the previous token is within span, so that the whitespace between it
+// and the next token is formatted.
+func (f *formatter) pairInSpan() bool {
+	return f.hasPrevious && f.inSpan(f.previous.pos, f.previous.end) && !f.hasError(f.previous)
+}
+
+func (f *formatter) newFormattingContext(token formatToken, parent *ast.Node) *formattingContext {
+	return &formattingContext{
+		f:                  f,
+		currentToken:       f.previous,
+		currentTokenParent: f.previousParent,
+		nextToken:          token,
+		nextTokenParent:    parent,
+		contextNode:        findCommonAncestor(f.previousParent, parent),
+	}
+}
+
+func findCommonAncestor(a *ast.Node, b *ast.Node) *ast.Node {
+	for n := a; n != nil; n = n.Parent {
+		if ast.IsNodeDescendantOf(b, n) {
+			return n
+		}
+	}
+	return b
+}
+
+// predictLineAction returns what the space rule between the previous token and a token does to
+// the lines they are on, without applying it.
+func (f *formatter) predictLineAction(token formatToken, parent *ast.Node) formatLineAction {
+	comments, isTrivia := f.scanTrivia(f.previous.end, token.pos)
+	if !isTrivia || len(comments) != 0 || f.hasError(token) || !f.pairInSpan() {
+		return formatLineActionNone
+	}
+	spaceRule, _ := getFormatRulesForContext(f.newFormattingContext(token, parent))
+	if spaceRule == nil {
+		return formatLineActionNone
+	}
+	previousLine := f.lineOf(f.previous.pos)
+	line := f.lineOf(token.pos)
+	if !spaceRule.canDeleteNewLines && previousLine != line {
+		return formatLineActionNone
+	}
+	switch spaceRule.action {
+	case formatRuleActionInsertNewLine:
+		if line == previousLine {
+			return formatLineActionLineAdded
+		}
+	case formatRuleActionInsertSpace, formatRuleActionDeleteSpace:
+		if line != previousLine {
+			return formatLineActionLineRemoved
+		}
+	}
+	return formatLineActionNone
+}
+
+// applySpaceRule replaces the whitespace between the previous token and token as a rule says. It
+// reports how that changed the lines of the tokens, whether the whitespace was replaced, and
+// whether token was indented along with a line break that was inserted before it.
+func (f *formatter) applySpaceRule(rule *formatRule, token formatToken, ctx *formatIndentation) (lineAction formatLineAction, gapReplaced bool, tokenIndented bool) {
+	previousLine := f.lineOf(f.previous.pos)
+	line := f.lineOf(token.pos)
+	onLaterLine := line != previousLine
+	gap := core.NewTextRange(f.previous.end, token.pos)
+	switch rule.action {
+	case formatRuleActionDeleteSpace:
+		if gap.Len() != 0 {
+			f.addChange(gap, "")
+			if onLaterLine {
+				return formatLineActionLineRemoved, true, false
+			}
+			return formatLineActionNone, true, false
+		}
+	case formatRuleActionInsertSpace:
+		if !rule.canDeleteNewLines && onLaterLine {
+			break
+		}
+		if f.text[gap.Pos():gap.End()] != " " {
+			f.addChange(gap, " ")
+			if onLaterLine {
+				return formatLineActionLineRemoved, true, false
+			}
+			return formatLineActionNone, true, false
+		}
+	case formatRuleActionInsertNewLine:
+		if !rule.canDeleteNewLines && onLaterLine {
+			break
+		}
+		if line-previousLine != 1 {
+			indentation := f.getIndentationString(f.getTokenIndentation(ctx, token))
+			f.addChange(gap, f.settings.NewLineCharacter+indentation)
+			if onLaterLine {
+				return formatLineActionNone, true, true
+			}
+			return formatLineActionLineAdded, true, true
+		}
+	}
+	return formatLineActionNone, false, false
+}
+
+func (f *formatter) applyTokenRule(rule *formatRule) {
+	switch rule.action {
+	case formatRuleActionDeleteToken:
+		f.addChange(core.NewTextRange(f.previous.pos, f.previous.end), "")
+	case formatRuleActionInsertTrailingSemicolon:
+		f.addChange(core.NewTextRange(f.previous.end, f.previous.end), ";")
+	}
+}
+
+// formatTrivia trims the trailing whitespace of the lines that end between pos and token, and
+// indents the comments there that start a line.
+func (f *formatter) formatTrivia(pos int, token formatToken, comments []core.TextRange, commentIndentation int) {
+	segmentStart := pos
+	trim := func(end int) {
+		for i := segmentStart; i < end; {
+			ch, size := utf8.DecodeRuneInString(f.text[i:])
+			if !stringutil.IsLineBreak(ch) {
+				i += size
+				continue
+			}
+			whitespaceStart := i
+			for whitespaceStart > segmentStart {
+				prev, prevSize := utf8.DecodeLastRuneInString(f.text[:whitespaceStart])
+				if !stringutil.IsWhiteSpaceSingleLine(prev) {
+					break
+				}
+				whitespaceStart -= prevSize
+			}
+			if f.settings.TrimTrailingWhitespace && whitespaceStart < i && f.inSpan(whitespaceStart, i) {
+				f.addChange(core.NewTextRange(whitespaceStart, i), "")
+			}
+			i += size
+		}
+	}
+	for _, comment := range comments {
+		trim(comment.Pos())
+		if f.inSpan(comment.Pos(), comment.End()) && f.isFirstOnLine(comment.Pos()) && (!f.hasPrevious || f.lineOf(f.previous.end) < f.lineOf(comment.Pos())) {
+			f.indentComment(comment, commentIndentation)
+		}
+		segmentStart = comment.End()
+	}
+	trim(token.pos)
+}
+
+// indentComment indents a comment that starts a line. The lines of a block comment are shifted
+// along with its first line.
+func (f *formatter) indentComment(comment core.TextRange, indentation int) {
+	line := f.lineOf(comment.Pos())
+	shift := indentation - f.getActualIndentation(line)
+	f.indentLine(comment.Pos(), indentation)
+	f.lineIndentation[line] = indentation
+	for line++; line <= f.lineOf(comment.End()) && line < len(f.lineStarts); line++ {
+		lineStart := int(f.lineStarts[line])
+		contentStart := lineStart
+		for contentStart < comment.End() {
+			ch, size := utf8.DecodeRuneInString(f.text[contentStart:])
+			if !stringutil.IsWhiteSpaceSingleLine(ch) {
+				break
+			}
+			contentStart += size
+		}
+		if contentStart >= comment.End() || stringutil.IsLineBreak(rune(f.text[contentStart])) {
+			continue
+		}
+		f.indentLine(contentStart, max(0, f.getActualIndentation(line)+shift))
+	}
+}
+
+// scanTrivia returns the comments between pos and end. It returns false if there is more than
+// whitespace and comments between them, as there is where the tree does not match the text.
+func (f *formatter) scanTrivia(pos int, end int) ([]core.TextRange, bool) {
+	var comments []core.TextRange
+	text := f.text
+	if pos == 0 && strings.HasPrefix(text, "#!") {
+		// A shebang is a comment that is never indented.
+		pos = strings.IndexFunc(text[:end], stringutil.IsLineBreak)
+		if pos < 0 {
+			pos = end
+		}
+	}
+	for pos < end {
+		ch, size := utf8.DecodeRuneInString(text[pos:])
+		switch {
+		case stringutil.IsWhiteSpaceLike(ch):
+			pos += size
+		case strings.HasPrefix(text[pos:end], "//"):
+			commentEnd := pos + 2
+			for commentEnd < end {
+				ch, size := utf8.DecodeRuneInString(text[commentEnd:])
+				if stringutil.IsLineBreak(ch) {
+					break
+				}
+				commentEnd += size
+			}
+			comments = append(comments, core.NewTextRange(pos, commentEnd))
+			pos = commentEnd
+		case strings.HasPrefix(text[pos:end], "/*"):
+			commentEnd := strings.Index(text[pos+2:end], "*/")
+			if commentEnd < 0 {
+				return nil, false
+			}
+			commentEnd += pos + 4
+			comments = append(comments, core.NewTextRange(pos, commentEnd))
+			pos = commentEnd
+		default:
+			return nil, false
+		}
+	}
+	return comments, true
+}
+
+// indentLine replaces the whitespace before pos on its line with the given indentation.
+func (f *formatter) indentLine(pos int, indentation int) {
+	lineStart := int(f.lineStarts[f.lineOf(pos)])
+	indentationString := f.getIndentationString(indentation)
+	if f.text[lineStart:pos] != indentationString {
+		f.addChange(core.NewTextRange(lineStart, pos), indentationString)
+	}
+}
+
+func (f *formatter) getIndentationString(indentation int) string {
+	if f.settings.ConvertTabsToSpaces || f.settings.TabSize <= 0 {
+		return strings.Repeat(" ", indentation)
+	}
+	return strings.Repeat("\t", indentation/f.settings.TabSize) + strings.Repeat(" ", indentation%f.settings.TabSize)
+}
+
+// getLineIndentation returns the indentation of a line: the indentation the line was given, or the
+// indentation it has if it was not indented.
+func (f *formatter) getLineIndentation(line int) int {
+	if indentation, ok := f.lineIndentation[line]; ok {
+		return indentation
+	}
+	return f.getActualIndentation(line)
+}
+
+// getActualIndentation returns the column of the first character of a line that is not whitespace.
+func (f *formatter) getActualIndentation(line int) int {
+	column := 0
+	for pos := int(f.lineStarts[line]); pos < len(f.text); {
+		ch, size := utf8.DecodeRuneInString(f.text[pos:])
+		if ch == '\t' && f.settings.TabSize > 0 {
+			column += f.settings.TabSize - column%f.settings.TabSize
+		} else if stringutil.IsWhiteSpaceSingleLine(ch) {
+			column++
+		} else {
+			break
+		}
+		pos += size
+	}
+	return column
+}
+
+// isFirstOnLine reports whether only whitespace precedes pos on its line.
+func (f *formatter) isFirstOnLine(pos int) bool {
+	lineStart := int(f.lineStarts[f.lineOf(pos)])
+	if f.hasPrevious && f.previous.end > lineStart {
+		return false
+	}
+	return strings.TrimLeftFunc(f.text[lineStart:pos], stringutil.IsWhiteSpaceSingleLine) == ""
+}
+
+func (f *formatter) inSpan(pos int, end int) bool {
+	return pos >= f.span.Pos() && end <= f.span.End()
+}
+
+func (f *formatter) hasError(token formatToken) bool {
+	for _, loc := range f.errors {
+		if loc.Pos() <= token.end && token.pos <= loc.End() {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *formatter) lineOf(pos int) int {
+	return scanner.ComputeLineOfPosition(f.lineStarts, pos)
+}
+
+func (f *formatter) addChange(loc core.TextRange, newText string) {
+	f.changes = append(f.changes, TextChange{TextRange: loc, NewText: newText})
+}
+
+// nodeIsOnOneLine reports whether a node starts and ends on the same line.
+func (f *formatter) nodeIsOnOneLine(node *ast.Node) bool {
+	return f.lineOf(scanner.GetTokenPosOfNode(node, f.file, false /*includeJSDoc*/)) == f.lineOf(node.End())
+}
+
+// blockIsOnOneLine reports whether the { and } of a node are on the same line.
+func (f *formatter) blockIsOnOneLine(node *ast.Node) bool {
+	braces, ok := f.braces[node]
+	if !ok {
+		braces = [2]int{-1, -1}
+		pos := node.Pos()
+		scanGap := func(end int) {
+			f.forEachGapToken(pos, end, func(token formatToken) {
+				switch {
+				case token.kind == ast.KindOpenBraceToken && braces[0] < 0:
+					braces[0] = token.end
+				case token.kind == ast.KindCloseBraceToken:
+					braces[1] = token.pos
+				}
+			})
+		}
+		node.ForEachChild(func(child *ast.Node) bool {
+			if isFormattedChild(child) && child.Pos() >= pos {
+				scanGap(child.Pos())
+				pos = child.End()
+			}
+			return false
+		})
+		scanGap(node.End())
+		f.braces[node] = braces
+	}
+	return braces[0] >= 0 && braces[1] >= 0 && f.lineOf(braces[0]) == f.lineOf(braces[1])
+}
+
+// nodeWillIndentChild reports whether the children of a node are indented within it. Some nodes
+// indent their children except for particular ones, like a block that is the body of a function;
+// child is nil to ask about the children of a node in general.
+func nodeWillIndentChild(settings *FormatCodeSettings, file *ast.SourceFile, parent *ast.Node, child *ast.Node, indentByDefault bool) bool {
+	childKind := ast.KindUnknown
+	if child != nil {
+		childKind = child.Kind
+	}
+	rangeIsOnOneLine := func(node *ast.Node) bool {
+		lineStarts := file.LineMap()
+		return scanner.ComputeLineOfPosition(lineStarts, scanner.GetTokenPosOfNode(node, file, false /*includeJSDoc*/)) == scanner.ComputeLineOfPosition(lineStarts, node.End())
+	}
+	switch parent.Kind {
+	case ast.KindExpressionStatement, ast.KindClassDeclaration, ast.KindClassExpression, ast.KindInterfaceDeclaration, ast.KindEnumDeclaration,
+		ast.KindTypeAliasDeclaration, ast.KindArrayLiteralExpression, ast.KindBlock, ast.KindModuleBlock, ast.KindObjectLiteralExpression,
+		ast.KindTypeLiteral, ast.KindMappedType, ast.KindTupleType, ast.KindParenthesizedExpression, ast.KindPropertyAccessExpression,
+		ast.KindCallExpression, ast.KindNewExpression, ast.KindVariableStatement, ast.KindExportAssignment, ast.KindReturnStatement,
+		ast.KindConditionalExpression, ast.KindArrayBindingPattern, ast.KindObjectBindingPattern, ast.KindJsxOpeningElement,
+		ast.KindJsxOpeningFragment, ast.KindJsxSelfClosingElement, ast.KindJsxExpression, ast.KindMethodSignature, ast.KindCallSignature,
+		ast.KindConstructSignature, ast.KindParameter, ast.KindFunctionType, ast.KindConstructorType, ast.KindParenthesizedType,
+		ast.KindTaggedTemplateExpression, ast.KindAwaitExpression, ast.KindNamedExports, ast.KindNamedImports, ast.KindExportSpecifier,
+		ast.KindImportSpecifier, ast.KindPropertyDeclaration, ast.KindCaseClause, ast.KindDefaultClause:
+		return true
+	case ast.KindCaseBlock:
+		return settings.IndentSwitchCase
+	case ast.KindVariableDeclaration, ast.KindPropertyAssignment, ast.KindBinaryExpression:
+		if !settings.IndentMultiLineObjectLiteralBeginningOnBlankLine && childKind == ast.KindObjectLiteralExpression {
+			return rangeIsOnOneLine(child)
+		}
+		if parent.Kind != ast.KindBinaryExpression {
+			return true
+		}
+	case ast.KindDoStatement, ast.KindWhileStatement, ast.KindForInStatement, ast.KindForOfStatement, ast.KindForStatement, ast.KindIfStatement,
+		ast.KindFunctionDeclaration, ast.KindFunctionExpression, ast.KindMethodDeclaration, ast.KindConstructor, ast.KindGetAccessor, ast.KindSetAccessor:
+		return childKind != ast.KindBlock
+	case ast.KindArrowFunction:
+		if childKind == ast.KindParenthesizedExpression {
+			return rangeIsOnOneLine(child)
+		}
+		return childKind != ast.KindBlock
+	case ast.KindExportDeclaration:
+		return childKind != ast.KindNamedExports
+	case ast.KindImportDeclaration:
+		if childKind != ast.KindImportClause {
+			return true
+		}
+		namedBindings := child.AsImportClause().NamedBindings
+		return namedBindings != nil && namedBindings.Kind != ast.KindNamedImports
+	case ast.KindJsxElement:
+		return childKind != ast.KindJsxClosingElement
+	case ast.KindJsxFragment:
+		return childKind != ast.KindJsxClosingFragment
+	case ast.KindIntersectionType, ast.KindUnionType, ast.KindSatisfiesExpression:
+		if childKind == ast.KindTypeLiteral || childKind == ast.KindTupleType || childKind == ast.KindMappedType {
+			return false
+		}
+	case ast.KindTryStatement:
+		if childKind == ast.KindBlock {
+			return false
+		}
+	}
+	return indentByDefault
+}
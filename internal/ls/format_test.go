@@ -0,0 +1,258 @@
+package ls_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/ls"
+	"gotest.tools/v3/assert"
+)
+
+func TestFormatDocument(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		title    string
+		text     string
+		settings func(settings *ls.FormatCodeSettings)
+		expected string
+	}{
+		{
+			title:    "spaces",
+			text:     `function  add( a:number,b:number ){return a+b}`,
+			expected: `function add(a: number, b: number) { return a + b }`,
+		},
+		{
+			title: "indentation",
+			text: `class Counter {
+count = 0;
+        increment() {
+  if (this.count < 10) {
+this.count++;
+      }
+}
+}`,
+			expected: `class Counter {
+    count = 0;
+    increment() {
+        if (this.count < 10) {
+            this.count++;
+        }
+    }
+}`,
+		},
+		{
+			title: "switch cases and comments",
+			text: `switch (x) {
+case 1:
+// one
+break;   
+    default:
+  /* other */ break;
+}`,
+			expected: `switch (x) {
+    case 1:
+        // one
+        break;
+    default:
+        /* other */ break;
+}`,
+		},
+		{
+			title: "settings",
+			text: `function f(a,b) {
+    for (let i=0;i<a;i++) {}
+}`,
+			settings: func(settings *ls.FormatCodeSettings) {
+				settings.InsertSpaceAfterCommaDelimiter = false
+				settings.InsertSpaceAfterSemicolonInForStatements = false
+				settings.PlaceOpenBraceOnNewLineForFunctions = true
+				settings.IndentSize = 2
+			},
+			expected: `function f(a,b)
+{
+  for (let i = 0;i < a;i++) { }
+}`,
+		},
+		{
+			title: "tabs",
+			text: `if (x) {
+y();
+}`,
+			settings: func(settings *ls.FormatCodeSettings) {
+				settings.ConvertTabsToSpaces = false
+			},
+			expected: "if (x) {\n\ty();\n}",
+		},
+		{
+			title: "insert semicolons",
+			text: `let a = 1
+const f = () => a
+class C {
+    x = 1
+    m() { return this.x }
+}
+interface I { y: number }`,
+			settings: func(settings *ls.FormatCodeSettings) {
+				settings.Semicolons = ls.SemicolonPreferenceInsert
+			},
+			expected: `let a = 1;
+const f = () => a;
+class C {
+    x = 1;
+    m() { return this.x; }
+}
+interface I { y: number; }`,
+		},
+		{
+			title: "remove semicolons",
+			text: `let a = 1;
+let b = a;
+(b as any).c;
+class C {
+    x = 1;
+    m() { return this.x; };
+}
+for (;;) {}`,
+			settings: func(settings *ls.FormatCodeSettings) {
+				settings.Semicolons = ls.SemicolonPreferenceRemove
+			},
+			// The semicolons that the next line needs, as it starts with a parenthesis, that end
+			// property declarations with an initializer, or that are class elements, are kept
+			expected: `let a = 1
+let b = a;
+(b as any).c
+class C {
+    x = 1;
+    m() { return this.x };
+}
+for (; ;) { }`,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.title, func(t *testing.T) {
+			t.Parallel()
+			p := newTestProject(t, map[string]string{
+				"/home/src/project/index.ts": testCase.text,
+			})
+			l := p.languageService("/home/src/project/index.ts")
+			settings := ls.GetDefaultFormatCodeSettings("\n")
+			if testCase.settings != nil {
+				testCase.settings(settings)
+			}
+			changes := l.ProvideFormatDocument("/home/src/project/index.ts", settings)
+			assert.Equal(t, applyChanges(testCase.text, changes), testCase.expected)
+		})
+	}
+}
+
+func TestFormatRange(t *testing.T) {
+	t.Parallel()
+
+	p := newTestProject(t, map[string]string{
+		"/home/src/project/index.ts": `function f() {
+let a=1;
+let b=/*start*/2;
+let c=3;/*end*/
+let d=4;
+}`,
+	})
+	l, start := p.languageServiceAt("start")
+	end := p.marker("end")
+	changes := l.ProvideFormatRange(start.fileName, core.NewTextRange(start.position, end.position), ls.GetDefaultFormatCodeSettings("\n"))
+	// The whole lines that the range touches are formatted
+	assert.Equal(t, applyChanges(p.files[start.fileName], changes), `function f() {
+let a=1;
+    let b = 2;
+    let c = 3;
+let d=4;
+}`)
+}
+
+func TestFormatOnType(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		title    string
+		text     string
+		key      string
+		expected string
+	}{
+		{
+			title: "semicolon formats the statement it ends",
+			text: `function f() {
+let a=1;
+    let   b=[1,2];/*1*/
+}`,
+			key: ";",
+			expected: `function f() {
+let a=1;
+    let b = [1, 2];
+}`,
+		},
+		{
+			title: "semicolon formats a whole member",
+			text: `class C {
+    x=1;
+      y :  number=f( 1 );/*1*/
+}`,
+			key: ";",
+			expected: `class C {
+    x=1;
+    y: number = f(1);
+}`,
+		},
+		{
+			title: "closing brace formats the block it ends",
+			text: `let a=1;
+if(a){
+a++;
+}/*1*/`,
+			key: "}",
+			expected: `let a=1;
+if (a) {
+    a++;
+}`,
+		},
+		{
+			title: "new line formats the line it ends and indents the next",
+			text: `function f() {
+    let a=1;
+      let b=2
+/*1*/
+}`,
+			key: "\n",
+			expected: `function f() {
+    let a=1;
+    let b = 2
+
+}`,
+		},
+		{
+			title:    "other keys do nothing",
+			text:     `let a=1;/*1*/`,
+			key:      ")",
+			expected: `let a=1;`,
+		},
+		{
+			title:    "semicolon elsewhere does nothing",
+			text:     `let a=/*1*/1;`,
+			key:      ";",
+			expected: `let a=1;`,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(strings.ReplaceAll(testCase.title, "\n", ""), func(t *testing.T) {
+			t.Parallel()
+			p := newTestProject(t, map[string]string{
+				"/home/src/project/index.ts": testCase.text,
+			})
+			l, m := p.languageServiceAt("1")
+			changes := l.ProvideFormatOnType(m.fileName, m.position, testCase.key, ls.GetDefaultFormatCodeSettings("\n"))
+			assert.Equal(t, applyChanges(p.files[m.fileName], changes), testCase.expected)
+		})
+	}
+}
@@ -0,0 +1,887 @@
+package ls
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/scanner"
+)
+
+// formatRuleAction is what a formatting rule does between two tokens. A pair of tokens gets at most
+// one action on the whitespace between them, and at most one action on the tokens themselves.
+type formatRuleAction int
+
+const (
+	formatRuleActionInsertSpace formatRuleAction = 1 << iota
+	formatRuleActionInsertNewLine
+	formatRuleActionDeleteSpace
+	formatRuleActionDeleteToken
+	formatRuleActionInsertTrailingSemicolon
+
+	formatRuleActionModifySpace = formatRuleActionInsertSpace | formatRuleActionInsertNewLine | formatRuleActionDeleteSpace
+	formatRuleActionModifyToken = formatRuleActionDeleteToken | formatRuleActionInsertTrailingSemicolon
+)
+
+type formatTokenRange struct {
+	tokens []ast.Kind
+	// isSpecific is false for ranges like any token, which a rule with specific ranges takes
+	// precedence over.
+	isSpecific bool
+}
+
+type formatContextPredicate func(c *formattingContext) bool
+
+type formatRule struct {
+	name    string
+	left    formatTokenRange
+	right   formatTokenRange
+	context []formatContextPredicate
+	action  formatRuleAction
+	// canDeleteNewLines lets the rule apply to tokens on different lines, joining them.
+	canDeleteNewLines bool
+}
+
+// formattingContext is the pair of adjacent tokens that rules are applied to.
+type formattingContext struct {
+	f                  *formatter
+	currentToken       formatToken
+	nextToken          formatToken
+	currentTokenParent *ast.Node
+	nextTokenParent    *ast.Node
+	// contextNode is the innermost node that contains both tokens.
+	contextNode *ast.Node
+}
+
+func (c *formattingContext) tokensAreOnSameLine() bool {
+	return c.f.lineOf(c.currentToken.end) == c.f.lineOf(c.nextToken.pos)
+}
+
+func (c *formattingContext) contextNodeAllOnSameLine() bool {
+	return c.f.nodeIsOnOneLine(c.contextNode)
+}
+
+func (c *formattingContext) nextNodeAllOnSameLine() bool {
+	return c.f.nodeIsOnOneLine(c.nextTokenParent)
+}
+
+func (c *formattingContext) contextNodeBlockIsOnOneLine() bool {
+	return c.f.blockIsOnOneLine(c.contextNode)
+}
+
+func (c *formattingContext) nextNodeBlockIsOnOneLine() bool {
+	return c.f.blockIsOnOneLine(c.nextTokenParent)
+}
+
+// getFormatRules returns the rules for a pair of tokens, in order of precedence, indexed by the
+// kinds of the tokens.
+var getFormatRules = sync.OnceValue(func() [][]*formatRule {
+	const kindCount = int(ast.KindLastToken) + 1
+	buckets := make([][]*formatRule, kindCount*kindCount)
+	for _, rule := range getAllFormatRules() {
+		for _, left := range rule.left.tokens {
+			for _, right := range rule.right.tokens {
+				index := int(left)*kindCount + int(right)
+				buckets[index] = append(buckets[index], rule)
+			}
+		}
+	}
+	for _, bucket := range buckets {
+		// Rules with a context come before those without one, and rules with specific token
+		// ranges before those with open ones. Otherwise rules keep the order they are listed in.
+		slices.SortStableFunc(bucket, func(a, b *formatRule) int {
+			return formatRulePosition(a) - formatRulePosition(b)
+		})
+	}
+	return buckets
+})
+
+func formatRulePosition(rule *formatRule) int {
+	position := 0
+	if len(rule.context) == 0 {
+		position = 2
+	}
+	if !rule.left.isSpecific || !rule.right.isSpecific {
+		position++
+	}
+	return position
+}
+
+// getFormatRulesForContext returns the rules that apply to a pair of tokens: the rule that acts on
+// the whitespace between them and the rule that acts on the tokens, either of which may be nil.
+func getFormatRulesForContext(c *formattingContext) (spaceRule *formatRule, tokenRule *formatRule) {
+	const kindCount = int(ast.KindLastToken) + 1
+	if c.currentToken.kind > ast.KindLastToken || c.nextToken.kind > ast.KindLastToken {
+		return nil, nil
+	}
+	for _, rule := range getFormatRules()[int(c.currentToken.kind)*kindCount+int(c.nextToken.kind)] {
+		if rule.action&formatRuleActionModifySpace != 0 && spaceRule != nil ||
+			rule.action&formatRuleActionModifyToken != 0 && tokenRule != nil {
+			continue
+		}
+		if !rule.appliesTo(c) {
+			continue
+		}
+		if rule.action&formatRuleActionModifySpace != 0 {
+			spaceRule = rule
+		} else {
+			tokenRule = rule
+		}
+		if spaceRule != nil && tokenRule != nil {
+			break
+		}
+	}
+	return spaceRule, tokenRule
+}
+
+func (r *formatRule) appliesTo(c *formattingContext) bool {
+	for _, predicate := range r.context {
+		if !predicate(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func getAllFormatRules() []*formatRule {
+	var allTokens []ast.Kind
+	for token := ast.KindFirstToken; token <= ast.KindLastToken; token++ {
+		if token != ast.KindEndOfFile {
+			allTokens = append(allTokens, token)
+		}
+	}
+	anyToken := formatTokenRange{tokens: allTokens}
+	anyTokenIncludingEOF := formatTokenRange{tokens: append(slices.Clone(allTokens), ast.KindEndOfFile)}
+	anyTokenExcept := func(tokens ...ast.Kind) formatTokenRange {
+		return formatTokenRange{tokens: slices.DeleteFunc(slices.Clone(allTokens), func(token ast.Kind) bool {
+			return slices.Contains(tokens, token)
+		})}
+	}
+	tokenRangeFromRange := func(first ast.Kind, last ast.Kind) []ast.Kind {
+		var tokens []ast.Kind
+		for token := first; token <= last; token++ {
+			tokens = append(tokens, token)
+		}
+		return tokens
+	}
+
+	keywords := tokenRangeFromRange(ast.KindFirstKeyword, ast.KindLastKeyword)
+	binaryOperators := append(tokenRangeFromRange(ast.KindFirstBinaryOperator, ast.KindLastBinaryOperator), ast.KindInKeyword, ast.KindInstanceOfKeyword, ast.KindOfKeyword, ast.KindAsKeyword, ast.KindIsKeyword, ast.KindSatisfiesKeyword)
+	binaryKeywordOperators := []ast.Kind{ast.KindInKeyword, ast.KindInstanceOfKeyword, ast.KindOfKeyword, ast.KindAsKeyword, ast.KindIsKeyword, ast.KindSatisfiesKeyword}
+	unaryPrefixOperators := []ast.Kind{ast.KindPlusPlusToken, ast.KindMinusMinusToken, ast.KindTildeToken, ast.KindExclamationToken}
+	unaryPrefixExpressions := []ast.Kind{ast.KindNumericLiteral, ast.KindBigIntLiteral, ast.KindIdentifier, ast.KindOpenParenToken, ast.KindOpenBracketToken, ast.KindOpenBraceToken, ast.KindThisKeyword, ast.KindNewKeyword}
+	unaryPreincrementExpressions := []ast.Kind{ast.KindIdentifier, ast.KindOpenParenToken, ast.KindThisKeyword, ast.KindNewKeyword}
+	unaryPostincrementExpressions := []ast.Kind{ast.KindIdentifier, ast.KindCloseParenToken, ast.KindCloseBracketToken, ast.KindNewKeyword}
+	typeNames := []ast.Kind{
+		ast.KindIdentifier, ast.KindAnyKeyword, ast.KindAssertsKeyword, ast.KindBigIntKeyword, ast.KindBooleanKeyword, ast.KindFalseKeyword,
+		ast.KindInferKeyword, ast.KindKeyOfKeyword, ast.KindNeverKeyword, ast.KindNullKeyword, ast.KindNumberKeyword, ast.KindObjectKeyword,
+		ast.KindReadonlyKeyword, ast.KindStringKeyword, ast.KindSymbolKeyword, ast.KindTypeOfKeyword, ast.KindTrueKeyword, ast.KindVoidKeyword,
+		ast.KindUndefinedKeyword, ast.KindUniqueKeyword, ast.KindUnknownKeyword,
+	}
+
+	// Function declarations can have return types made of most kinds of tokens before their body.
+	functionOpenBraceLeftTokenRange := anyToken
+	typeScriptOpenBraceLeftTokenRange := []ast.Kind{ast.KindIdentifier, ast.KindGreaterThanToken, ast.KindClassKeyword, ast.KindExportKeyword, ast.KindImportKeyword}
+	controlOpenBraceLeftTokenRange := []ast.Kind{ast.KindCloseParenToken, ast.KindDoKeyword, ast.KindTryKeyword, ast.KindFinallyKeyword, ast.KindElseKeyword, ast.KindCatchKeyword}
+
+	// Rules that come before the configurable ones.
+	highPriorityCommonRules := []*formatRule{
+		rule("NotSpaceBeforeColon", anyToken, ast.KindColonToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isNotBinaryOpContext, isNotTypeAnnotationContext}, formatRuleActionDeleteSpace),
+		rule("SpaceAfterColon", ast.KindColonToken, anyToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isNotBinaryOpContext, isNextTokenParentNotJsxNamespacedName}, formatRuleActionInsertSpace),
+		rule("NoSpaceBeforeQuestionMark", anyToken, ast.KindQuestionToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isNotBinaryOpContext, isNotTypeAnnotationContext}, formatRuleActionDeleteSpace),
+		// A space follows ? only in conditional operators.
+		rule("SpaceAfterQuestionMarkInConditionalOperator", ast.KindQuestionToken, anyToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isConditionalOperatorContext}, formatRuleActionInsertSpace),
+		rule("NoSpaceAfterQuestionMark", ast.KindQuestionToken, anyToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isNonOptionalPropertyContext}, formatRuleActionDeleteSpace),
+
+		rule("NoSpaceBeforeDot", anyToken, []ast.Kind{ast.KindDotToken, ast.KindQuestionDotToken}, []formatContextPredicate{isNonJsxSameLineTokenContext, isNotPropertyAccessOnIntegerLiteral}, formatRuleActionDeleteSpace),
+		rule("NoSpaceAfterDot", []ast.Kind{ast.KindDotToken, ast.KindQuestionDotToken}, anyToken, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+
+		rule("NoSpaceBetweenImportParenInImportType", ast.KindImportKeyword, ast.KindOpenParenToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isImportTypeContext}, formatRuleActionDeleteSpace),
+
+		// Prefix operators are not separated from their operand.
+		rule("NoSpaceAfterUnaryPrefixOperator", unaryPrefixOperators, unaryPrefixExpressions, []formatContextPredicate{isNonJsxSameLineTokenContext, isNotBinaryOpContext}, formatRuleActionDeleteSpace),
+		rule("NoSpaceAfterUnaryPreincrementOperator", ast.KindPlusPlusToken, unaryPreincrementExpressions, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+		rule("NoSpaceAfterUnaryPredecrementOperator", ast.KindMinusMinusToken, unaryPreincrementExpressions, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+		rule("NoSpaceBeforeUnaryPostincrementOperator", unaryPostincrementExpressions, ast.KindPlusPlusToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isNotStatementConditionContext}, formatRuleActionDeleteSpace),
+		rule("NoSpaceBeforeUnaryPostdecrementOperator", unaryPostincrementExpressions, ast.KindMinusMinusToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isNotStatementConditionContext}, formatRuleActionDeleteSpace),
+
+		// Removing the space in 1 - -2 or a + ++b would change the tokens.
+		rule("SpaceAfterPostincrementWhenFollowedByAdd", ast.KindPlusPlusToken, ast.KindPlusToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isBinaryOpContext}, formatRuleActionInsertSpace),
+		rule("SpaceAfterAddWhenFollowedByUnaryPlus", ast.KindPlusToken, ast.KindPlusToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isBinaryOpContext}, formatRuleActionInsertSpace),
+		rule("SpaceAfterAddWhenFollowedByPreincrement", ast.KindPlusToken, ast.KindPlusPlusToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isBinaryOpContext}, formatRuleActionInsertSpace),
+		rule("SpaceAfterPostdecrementWhenFollowedBySubtract", ast.KindMinusMinusToken, ast.KindMinusToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isBinaryOpContext}, formatRuleActionInsertSpace),
+		rule("SpaceAfterSubtractWhenFollowedByUnaryMinus", ast.KindMinusToken, ast.KindMinusToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isBinaryOpContext}, formatRuleActionInsertSpace),
+		rule("SpaceAfterSubtractWhenFollowedByPredecrement", ast.KindMinusToken, ast.KindMinusMinusToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isBinaryOpContext}, formatRuleActionInsertSpace),
+
+		rule("NoSpaceAfterCloseBrace", ast.KindCloseBraceToken, []ast.Kind{ast.KindCommaToken, ast.KindSemicolonToken}, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+		// The } of a block that spans lines goes on its own line.
+		rule("NewLineBeforeCloseBraceInBlockContext", anyToken, ast.KindCloseBraceToken, []formatContextPredicate{isMultilineBlockContext}, formatRuleActionInsertNewLine),
+
+		rule("SpaceAfterCloseBrace", ast.KindCloseBraceToken, anyTokenExcept(ast.KindCloseParenToken), []formatContextPredicate{isNonJsxSameLineTokenContext, isAfterCodeBlockContext}, formatRuleActionInsertSpace),
+		// else and while follow the } of the block before them, which is not their parent.
+		rule("SpaceBetweenCloseBraceAndElse", ast.KindCloseBraceToken, ast.KindElseKeyword, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionInsertSpace),
+		rule("SpaceBetweenCloseBraceAndWhile", ast.KindCloseBraceToken, ast.KindWhileKeyword, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionInsertSpace),
+		rule("NoSpaceBetweenEmptyBraceBrackets", ast.KindOpenBraceToken, ast.KindCloseBraceToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isObjectContext}, formatRuleActionDeleteSpace),
+
+		// if (false) [a, b] = [1, 2];
+		rule("SpaceAfterConditionalClosingParen", ast.KindCloseParenToken, ast.KindOpenBracketToken, []formatContextPredicate{isControlDeclContext}, formatRuleActionInsertSpace),
+
+		rule("NoSpaceBetweenFunctionKeywordAndStar", ast.KindFunctionKeyword, ast.KindAsteriskToken, []formatContextPredicate{isFunctionDeclarationOrFunctionExpressionContext}, formatRuleActionDeleteSpace),
+		rule("SpaceAfterStarInGeneratorDeclaration", ast.KindAsteriskToken, ast.KindIdentifier, []formatContextPredicate{isFunctionDeclarationOrFunctionExpressionContext}, formatRuleActionInsertSpace),
+
+		rule("SpaceAfterFunctionInFuncDecl", ast.KindFunctionKeyword, anyToken, []formatContextPredicate{isFunctionDeclContext}, formatRuleActionInsertSpace),
+		// The contents of a block that spans lines start on a line after its {.
+		rule("NewLineAfterOpenBraceInBlockContext", ast.KindOpenBraceToken, anyToken, []formatContextPredicate{isMultilineBlockContext}, formatRuleActionInsertNewLine),
+
+		rule("SpaceAfterGetSetInMember", []ast.Kind{ast.KindGetKeyword, ast.KindSetKeyword}, ast.KindIdentifier, []formatContextPredicate{isFunctionDeclContext}, formatRuleActionInsertSpace),
+
+		rule("NoSpaceBetweenYieldKeywordAndStar", ast.KindYieldKeyword, ast.KindAsteriskToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isYieldOrYieldStarWithOperand}, formatRuleActionDeleteSpace),
+		rule("SpaceBetweenYieldOrYieldStarAndOperand", []ast.Kind{ast.KindYieldKeyword, ast.KindAsteriskToken}, anyToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isYieldOrYieldStarWithOperand}, formatRuleActionInsertSpace),
+
+		rule("NoSpaceBetweenReturnAndSemicolon", ast.KindReturnKeyword, ast.KindSemicolonToken, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+		rule("SpaceAfterCertainKeywords", []ast.Kind{ast.KindVarKeyword, ast.KindThrowKeyword, ast.KindNewKeyword, ast.KindDeleteKeyword, ast.KindReturnKeyword, ast.KindTypeOfKeyword, ast.KindAwaitKeyword}, anyToken, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionInsertSpace),
+		rule("SpaceAfterLetConstInVariableDeclaration", []ast.Kind{ast.KindLetKeyword, ast.KindConstKeyword}, anyToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isStartOfVariableDeclarationList}, formatRuleActionInsertSpace),
+		rule("NoSpaceBeforeOpenParenInFuncCall", anyToken, ast.KindOpenParenToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isFunctionCallOrNewContext, isPreviousTokenNotComma}, formatRuleActionDeleteSpace),
+
+		// Keyword operators need spaces around them whatever the options say.
+		rule("SpaceBeforeBinaryKeywordOperator", anyToken, binaryKeywordOperators, []formatContextPredicate{isNonJsxSameLineTokenContext, isBinaryOpContext}, formatRuleActionInsertSpace),
+		rule("SpaceAfterBinaryKeywordOperator", binaryKeywordOperators, anyToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isBinaryOpContext}, formatRuleActionInsertSpace),
+
+		rule("SpaceAfterVoidOperator", ast.KindVoidKeyword, anyToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isVoidOpContext}, formatRuleActionInsertSpace),
+
+		rule("SpaceBetweenAsyncAndOpenParen", ast.KindAsyncKeyword, ast.KindOpenParenToken, []formatContextPredicate{isArrowFunctionContext, isNonJsxSameLineTokenContext}, formatRuleActionInsertSpace),
+		rule("SpaceBetweenAsyncAndFunctionKeyword", ast.KindAsyncKeyword, []ast.Kind{ast.KindFunctionKeyword, ast.KindIdentifier}, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionInsertSpace),
+
+		rule("NoSpaceBetweenTagAndTemplateString", []ast.Kind{ast.KindIdentifier, ast.KindCloseParenToken}, []ast.Kind{ast.KindNoSubstitutionTemplateLiteral, ast.KindTemplateHead}, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+
+		rule("SpaceBeforeJsxAttribute", anyToken, ast.KindIdentifier, []formatContextPredicate{isNextTokenParentJsxAttribute, isNonJsxSameLineTokenContext}, formatRuleActionInsertSpace),
+		rule("SpaceBeforeSlashInJsxOpeningElement", anyToken, ast.KindSlashToken, []formatContextPredicate{isJsxSelfClosingElementContext, isNonJsxSameLineTokenContext}, formatRuleActionInsertSpace),
+		rule("NoSpaceBeforeGreaterThanTokenInJsxOpeningElement", ast.KindSlashToken, ast.KindGreaterThanToken, []formatContextPredicate{isJsxSelfClosingElementContext, isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+		rule("NoSpaceBeforeEqualInJsxAttribute", anyToken, ast.KindEqualsToken, []formatContextPredicate{isJsxAttributeContext, isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+		rule("NoSpaceAfterEqualInJsxAttribute", ast.KindEqualsToken, anyToken, []formatContextPredicate{isJsxAttributeContext, isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+		rule("NoSpaceBeforeJsxNamespaceColon", ast.KindIdentifier, ast.KindColonToken, []formatContextPredicate{isNextTokenParentJsxNamespacedName}, formatRuleActionDeleteSpace),
+		rule("NoSpaceAfterJsxNamespaceColon", ast.KindColonToken, ast.KindIdentifier, []formatContextPredicate{isNextTokenParentJsxNamespacedName}, formatRuleActionDeleteSpace),
+
+		// import m = require("m");
+		rule("NoSpaceAfterModuleImport", []ast.Kind{ast.KindModuleKeyword, ast.KindRequireKeyword}, ast.KindOpenParenToken, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+		rule(
+			"SpaceAfterCertainTypeScriptKeywords",
+			[]ast.Kind{
+				ast.KindAbstractKeyword, ast.KindAccessorKeyword, ast.KindClassKeyword, ast.KindDeclareKeyword, ast.KindDefaultKeyword,
+				ast.KindEnumKeyword, ast.KindExportKeyword, ast.KindExtendsKeyword, ast.KindGetKeyword, ast.KindImplementsKeyword,
+				ast.KindImportKeyword, ast.KindInterfaceKeyword, ast.KindModuleKeyword, ast.KindNamespaceKeyword, ast.KindPrivateKeyword,
+				ast.KindPublicKeyword, ast.KindProtectedKeyword, ast.KindReadonlyKeyword, ast.KindSetKeyword, ast.KindStaticKeyword,
+				ast.KindTypeKeyword, ast.KindFromKeyword, ast.KindKeyOfKeyword, ast.KindInferKeyword,
+			},
+			anyToken,
+			[]formatContextPredicate{isNonJsxSameLineTokenContext},
+			formatRuleActionInsertSpace,
+		),
+		rule("SpaceBeforeCertainTypeScriptKeywords", anyToken, []ast.Kind{ast.KindExtendsKeyword, ast.KindImplementsKeyword, ast.KindFromKeyword}, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionInsertSpace),
+		// module "m" {
+		rule("SpaceAfterModuleName", ast.KindStringLiteral, ast.KindOpenBraceToken, []formatContextPredicate{isModuleDeclContext}, formatRuleActionInsertSpace),
+
+		rule("SpaceBeforeArrow", anyToken, ast.KindEqualsGreaterThanToken, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionInsertSpace),
+		rule("SpaceAfterArrow", ast.KindEqualsGreaterThanToken, anyToken, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionInsertSpace),
+
+		rule("NoSpaceAfterEllipsis", ast.KindDotDotDotToken, ast.KindIdentifier, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+		rule("NoSpaceAfterOptionalParameters", ast.KindQuestionToken, []ast.Kind{ast.KindCloseParenToken, ast.KindCommaToken}, []formatContextPredicate{isNonJsxSameLineTokenContext, isNotBinaryOpContext}, formatRuleActionDeleteSpace),
+
+		// x: {}
+		rule("NoSpaceBetweenEmptyInterfaceBraceBrackets", ast.KindOpenBraceToken, ast.KindCloseBraceToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isObjectTypeContext}, formatRuleActionDeleteSpace),
+
+		// Type arguments, type parameters and type assertions.
+		rule("NoSpaceBeforeOpenAngularBracket", typeNames, ast.KindLessThanToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isTypeArgumentOrParameterOrAssertionContext}, formatRuleActionDeleteSpace),
+		rule("NoSpaceBetweenCloseParenAndAngularBracket", ast.KindCloseParenToken, ast.KindLessThanToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isTypeArgumentOrParameterOrAssertionContext}, formatRuleActionDeleteSpace),
+		rule("NoSpaceAfterOpenAngularBracket", ast.KindLessThanToken, anyToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isTypeArgumentOrParameterOrAssertionContext}, formatRuleActionDeleteSpace),
+		rule("NoSpaceBeforeCloseAngularBracket", anyToken, ast.KindGreaterThanToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isTypeArgumentOrParameterOrAssertionContext}, formatRuleActionDeleteSpace),
+		rule("NoSpaceAfterCloseAngularBracket", ast.KindGreaterThanToken, []ast.Kind{ast.KindOpenParenToken, ast.KindOpenBracketToken, ast.KindGreaterThanToken, ast.KindCommaToken}, []formatContextPredicate{
+			isNonJsxSameLineTokenContext,
+			isTypeArgumentOrParameterOrAssertionContext,
+			// Leaves the space before the parameters of a function to SpaceBeforeOpenParenInFuncDecl.
+			isNotFunctionDeclContext,
+			isNonTypeAssertionContext,
+		}, formatRuleActionDeleteSpace),
+
+		rule("SpaceBeforeAt", []ast.Kind{ast.KindCloseParenToken, ast.KindIdentifier}, ast.KindAtToken, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionInsertSpace),
+		rule("NoSpaceAfterAt", ast.KindAtToken, anyToken, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+		rule(
+			"SpaceAfterDecorator",
+			anyToken,
+			[]ast.Kind{
+				ast.KindAbstractKeyword, ast.KindIdentifier, ast.KindExportKeyword, ast.KindDefaultKeyword, ast.KindClassKeyword,
+				ast.KindStaticKeyword, ast.KindPublicKeyword, ast.KindPrivateKeyword, ast.KindProtectedKeyword, ast.KindGetKeyword,
+				ast.KindSetKeyword, ast.KindOpenBracketToken, ast.KindAsteriskToken,
+			},
+			[]formatContextPredicate{isEndOfDecoratorContextOnSameLine},
+			formatRuleActionInsertSpace,
+		),
+
+		rule("NoSpaceBeforeNonNullAssertionOperator", anyToken, ast.KindExclamationToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isNonNullAssertionContext}, formatRuleActionDeleteSpace),
+		rule("NoSpaceAfterNewKeywordOnConstructorSignature", ast.KindNewKeyword, ast.KindOpenParenToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isConstructorSignatureContext}, formatRuleActionDeleteSpace),
+		rule("SpaceLessThanAndNonJSXTypeAnnotation", ast.KindLessThanToken, ast.KindLessThanToken, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionInsertSpace),
+	}
+
+	// Rules controlled by FormatCodeSettings.
+	userConfigurableRules := []*formatRule{
+		rule("SpaceAfterConstructor", ast.KindConstructorKeyword, ast.KindOpenParenToken, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterConstructor }), isNonJsxSameLineTokenContext}, formatRuleActionInsertSpace),
+		rule("NoSpaceAfterConstructor", ast.KindConstructorKeyword, ast.KindOpenParenToken, []formatContextPredicate{isSettingDisabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterConstructor }), isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+
+		rule("SpaceAfterComma", ast.KindCommaToken, anyToken, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterCommaDelimiter }), isNonJsxSameLineTokenContext, isNonJsxElementOrFragmentContext, isNextTokenNotCloseBracket, isNextTokenNotCloseParen}, formatRuleActionInsertSpace),
+		rule("NoSpaceAfterComma", ast.KindCommaToken, anyToken, []formatContextPredicate{isSettingDisabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterCommaDelimiter }), isNonJsxSameLineTokenContext, isNonJsxElementOrFragmentContext}, formatRuleActionDeleteSpace),
+
+		rule("SpaceAfterAnonymousFunctionKeyword", []ast.Kind{ast.KindFunctionKeyword, ast.KindAsteriskToken}, ast.KindOpenParenToken, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterFunctionKeywordForAnonymousFunctions }), isFunctionDeclContext}, formatRuleActionInsertSpace),
+		rule("NoSpaceAfterAnonymousFunctionKeyword", []ast.Kind{ast.KindFunctionKeyword, ast.KindAsteriskToken}, ast.KindOpenParenToken, []formatContextPredicate{isSettingDisabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterFunctionKeywordForAnonymousFunctions }), isFunctionDeclContext}, formatRuleActionDeleteSpace),
+
+		rule("SpaceAfterKeywordInControl", keywords, ast.KindOpenParenToken, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterKeywordsInControlFlowStatements }), isControlDeclContext}, formatRuleActionInsertSpace),
+		rule("NoSpaceAfterKeywordInControl", keywords, ast.KindOpenParenToken, []formatContextPredicate{isSettingDisabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterKeywordsInControlFlowStatements }), isControlDeclContext}, formatRuleActionDeleteSpace),
+
+		rule("SpaceAfterOpenParen", ast.KindOpenParenToken, anyToken, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingNonemptyParenthesis }), isNonJsxSameLineTokenContext}, formatRuleActionInsertSpace),
+		rule("SpaceBeforeCloseParen", anyToken, ast.KindCloseParenToken, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingNonemptyParenthesis }), isNonJsxSameLineTokenContext}, formatRuleActionInsertSpace),
+		rule("SpaceBetweenOpenParens", ast.KindOpenParenToken, ast.KindOpenParenToken, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingNonemptyParenthesis }), isNonJsxSameLineTokenContext}, formatRuleActionInsertSpace),
+		rule("NoSpaceBetweenParens", ast.KindOpenParenToken, ast.KindCloseParenToken, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+		rule("NoSpaceAfterOpenParen", ast.KindOpenParenToken, anyToken, []formatContextPredicate{isSettingDisabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingNonemptyParenthesis }), isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+		rule("NoSpaceBeforeCloseParen", anyToken, ast.KindCloseParenToken, []formatContextPredicate{isSettingDisabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingNonemptyParenthesis }), isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+
+		rule("SpaceAfterOpenBracket", ast.KindOpenBracketToken, anyToken, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingNonemptyBrackets }), isNonJsxSameLineTokenContext}, formatRuleActionInsertSpace),
+		rule("SpaceBeforeCloseBracket", anyToken, ast.KindCloseBracketToken, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingNonemptyBrackets }), isNonJsxSameLineTokenContext}, formatRuleActionInsertSpace),
+		rule("NoSpaceBetweenBrackets", ast.KindOpenBracketToken, ast.KindCloseBracketToken, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+		rule("NoSpaceAfterOpenBracket", ast.KindOpenBracketToken, anyToken, []formatContextPredicate{isSettingDisabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingNonemptyBrackets }), isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+		rule("NoSpaceBeforeCloseBracket", anyToken, ast.KindCloseBracketToken, []formatContextPredicate{isSettingDisabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingNonemptyBrackets }), isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+
+		// Braces of blocks and objects that fit on a line are padded with a space.
+		rule("SpaceAfterOpenBrace", ast.KindOpenBraceToken, anyToken, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingNonemptyBraces }), isBraceWrappedContext}, formatRuleActionInsertSpace),
+		rule("SpaceBeforeCloseBrace", anyToken, ast.KindCloseBraceToken, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingNonemptyBraces }), isBraceWrappedContext}, formatRuleActionInsertSpace),
+		rule("NoSpaceAfterOpenBrace", ast.KindOpenBraceToken, anyToken, []formatContextPredicate{isSettingDisabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingNonemptyBraces }), isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+		rule("NoSpaceBeforeCloseBrace", anyToken, ast.KindCloseBraceToken, []formatContextPredicate{isSettingDisabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingNonemptyBraces }), isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+
+		rule("SpaceBetweenEmptyBraceBrackets", ast.KindOpenBraceToken, ast.KindCloseBraceToken, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingEmptyBraces })}, formatRuleActionInsertSpace),
+		rule("NoSpaceBetweenEmptyBraceBrackets", ast.KindOpenBraceToken, ast.KindCloseBraceToken, []formatContextPredicate{isSettingDisabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingEmptyBraces }), isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+
+		rule("SpaceAfterTemplateHeadAndMiddle", []ast.Kind{ast.KindTemplateHead, ast.KindTemplateMiddle}, anyToken, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingTemplateStringBraces }), isNonJsxTextContext}, formatRuleActionInsertSpace).deletingNewLines(),
+		rule("SpaceBeforeTemplateMiddleAndTail", anyToken, []ast.Kind{ast.KindTemplateMiddle, ast.KindTemplateTail}, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingTemplateStringBraces }), isNonJsxSameLineTokenContext}, formatRuleActionInsertSpace),
+		rule("NoSpaceAfterTemplateHeadAndMiddle", []ast.Kind{ast.KindTemplateHead, ast.KindTemplateMiddle}, anyToken, []formatContextPredicate{isSettingDisabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingTemplateStringBraces }), isNonJsxTextContext}, formatRuleActionDeleteSpace).deletingNewLines(),
+		rule("NoSpaceBeforeTemplateMiddleAndTail", anyToken, []ast.Kind{ast.KindTemplateMiddle, ast.KindTemplateTail}, []formatContextPredicate{isSettingDisabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingTemplateStringBraces }), isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+
+		rule("SpaceAfterOpenBraceInJsxExpression", ast.KindOpenBraceToken, anyToken, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingJsxExpressionBraces }), isNonJsxSameLineTokenContext, isJsxExpressionContext}, formatRuleActionInsertSpace),
+		rule("SpaceBeforeCloseBraceInJsxExpression", anyToken, ast.KindCloseBraceToken, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingJsxExpressionBraces }), isNonJsxSameLineTokenContext, isJsxExpressionContext}, formatRuleActionInsertSpace),
+		rule("NoSpaceAfterOpenBraceInJsxExpression", ast.KindOpenBraceToken, anyToken, []formatContextPredicate{isSettingDisabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingJsxExpressionBraces }), isNonJsxSameLineTokenContext, isJsxExpressionContext}, formatRuleActionDeleteSpace),
+		rule("NoSpaceBeforeCloseBraceInJsxExpression", anyToken, ast.KindCloseBraceToken, []formatContextPredicate{isSettingDisabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterOpeningAndBeforeClosingJsxExpressionBraces }), isNonJsxSameLineTokenContext, isJsxExpressionContext}, formatRuleActionDeleteSpace),
+
+		rule("SpaceAfterSemicolonInFor", ast.KindSemicolonToken, anyToken, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterSemicolonInForStatements }), isNonJsxSameLineTokenContext, isForContext}, formatRuleActionInsertSpace),
+		rule("NoSpaceAfterSemicolonInFor", ast.KindSemicolonToken, anyToken, []formatContextPredicate{isSettingDisabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterSemicolonInForStatements }), isNonJsxSameLineTokenContext, isForContext}, formatRuleActionDeleteSpace),
+
+		rule("SpaceBeforeBinaryOperator", anyToken, binaryOperators, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceBeforeAndAfterBinaryOperators }), isNonJsxSameLineTokenContext, isBinaryOpContext}, formatRuleActionInsertSpace),
+		rule("SpaceAfterBinaryOperator", binaryOperators, anyToken, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceBeforeAndAfterBinaryOperators }), isNonJsxSameLineTokenContext, isBinaryOpContext}, formatRuleActionInsertSpace),
+		rule("NoSpaceBeforeBinaryOperator", anyToken, binaryOperators, []formatContextPredicate{isSettingDisabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceBeforeAndAfterBinaryOperators }), isNonJsxSameLineTokenContext, isBinaryOpContext}, formatRuleActionDeleteSpace),
+		rule("NoSpaceAfterBinaryOperator", binaryOperators, anyToken, []formatContextPredicate{isSettingDisabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceBeforeAndAfterBinaryOperators }), isNonJsxSameLineTokenContext, isBinaryOpContext}, formatRuleActionDeleteSpace),
+
+		rule("SpaceBeforeOpenParenInFuncDecl", anyToken, ast.KindOpenParenToken, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceBeforeFunctionParenthesis }), isNonJsxSameLineTokenContext, isFunctionDeclContext}, formatRuleActionInsertSpace),
+		rule("NoSpaceBeforeOpenParenInFuncDecl", anyToken, ast.KindOpenParenToken, []formatContextPredicate{isSettingDisabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceBeforeFunctionParenthesis }), isNonJsxSameLineTokenContext, isFunctionDeclContext}, formatRuleActionDeleteSpace),
+
+		rule("NewLineBeforeOpenBraceInControl", controlOpenBraceLeftTokenRange, ast.KindOpenBraceToken, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.PlaceOpenBraceOnNewLineForControlBlocks }), isControlDeclContext, isBeforeMultilineBlockContext}, formatRuleActionInsertNewLine).deletingNewLines(),
+		rule("NewLineBeforeOpenBraceInFunction", functionOpenBraceLeftTokenRange, ast.KindOpenBraceToken, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.PlaceOpenBraceOnNewLineForFunctions }), isFunctionDeclContext, isBeforeMultilineBlockContext}, formatRuleActionInsertNewLine).deletingNewLines(),
+		rule("NewLineBeforeOpenBraceInTypeScriptDeclWithBlock", typeScriptOpenBraceLeftTokenRange, ast.KindOpenBraceToken, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.PlaceOpenBraceOnNewLineForFunctions }), isTypeScriptDeclWithBlockContext, isBeforeMultilineBlockContext}, formatRuleActionInsertNewLine).deletingNewLines(),
+
+		rule("SpaceAfterTypeAssertion", ast.KindGreaterThanToken, anyToken, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterTypeAssertion }), isNonJsxSameLineTokenContext, isTypeAssertionContext}, formatRuleActionInsertSpace),
+		rule("NoSpaceAfterTypeAssertion", ast.KindGreaterThanToken, anyToken, []formatContextPredicate{isSettingDisabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceAfterTypeAssertion }), isNonJsxSameLineTokenContext, isTypeAssertionContext}, formatRuleActionDeleteSpace),
+
+		rule("SpaceBeforeTypeAnnotation", anyToken, []ast.Kind{ast.KindQuestionToken, ast.KindColonToken}, []formatContextPredicate{isSettingEnabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceBeforeTypeAnnotation }), isNonJsxSameLineTokenContext, isTypeAnnotationContext}, formatRuleActionInsertSpace),
+		rule("NoSpaceBeforeTypeAnnotation", anyToken, []ast.Kind{ast.KindQuestionToken, ast.KindColonToken}, []formatContextPredicate{isSettingDisabled(func(s *FormatCodeSettings) bool { return s.InsertSpaceBeforeTypeAnnotation }), isNonJsxSameLineTokenContext, isTypeAnnotationContext}, formatRuleActionDeleteSpace),
+
+		rule("NoOptionalSemicolon", ast.KindSemicolonToken, anyTokenIncludingEOF, []formatContextPredicate{isSemicolonPreference(SemicolonPreferenceRemove), isSemicolonDeletionContext}, formatRuleActionDeleteToken),
+		rule("OptionalSemicolon", anyToken, anyTokenIncludingEOF, []formatContextPredicate{isSemicolonPreference(SemicolonPreferenceInsert), isSemicolonInsertionContext}, formatRuleActionInsertTrailingSemicolon),
+	}
+
+	// Rules that come after the configurable ones, earlier ones first.
+	lowPriorityCommonRules := []*formatRule{
+		rule("NoSpaceBeforeSemicolon", anyToken, ast.KindSemicolonToken, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+
+		rule("SpaceBeforeOpenBraceInControl", controlOpenBraceLeftTokenRange, ast.KindOpenBraceToken, []formatContextPredicate{isSettingDisabledOrTokensOnSameLine(func(s *FormatCodeSettings) bool { return s.PlaceOpenBraceOnNewLineForControlBlocks }), isControlDeclContext, isNotFormatOnEnter, isSameLineTokenOrBeforeBlockContext}, formatRuleActionInsertSpace).deletingNewLines(),
+		rule("SpaceBeforeOpenBraceInFunction", functionOpenBraceLeftTokenRange, ast.KindOpenBraceToken, []formatContextPredicate{isSettingDisabledOrTokensOnSameLine(func(s *FormatCodeSettings) bool { return s.PlaceOpenBraceOnNewLineForFunctions }), isFunctionDeclContext, isBeforeBlockContext, isNotFormatOnEnter, isSameLineTokenOrBeforeBlockContext}, formatRuleActionInsertSpace).deletingNewLines(),
+		rule("SpaceBeforeOpenBraceInTypeScriptDeclWithBlock", typeScriptOpenBraceLeftTokenRange, ast.KindOpenBraceToken, []formatContextPredicate{isSettingDisabledOrTokensOnSameLine(func(s *FormatCodeSettings) bool { return s.PlaceOpenBraceOnNewLineForFunctions }), isTypeScriptDeclWithBlockContext, isNotFormatOnEnter, isSameLineTokenOrBeforeBlockContext}, formatRuleActionInsertSpace).deletingNewLines(),
+
+		rule("NoSpaceBeforeComma", anyToken, ast.KindCommaToken, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+
+		// x[]
+		rule("NoSpaceBeforeOpenBracket", anyTokenExcept(ast.KindAsyncKeyword, ast.KindCaseKeyword), ast.KindOpenBracketToken, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+		rule("NoSpaceAfterCloseBracket", ast.KindCloseBracketToken, anyToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isNotBeforeBlockInFunctionDeclarationContext}, formatRuleActionDeleteSpace),
+		rule("SpaceAfterSemicolon", ast.KindSemicolonToken, anyToken, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionInsertSpace),
+
+		rule("SpaceBetweenForAndAwaitKeyword", ast.KindForKeyword, ast.KindAwaitKeyword, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionInsertSpace),
+		// [...string]
+		rule("SpaceBetweenDotDotDotAndTypeName", ast.KindDotDotDotToken, typeNames, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionDeleteSpace),
+
+		// Statements are separated by a space. Keywords other than do, else and case are followed
+		// by parentheses, so ) stands in for them.
+		rule("SpaceBetweenStatements", []ast.Kind{ast.KindCloseParenToken, ast.KindDoKeyword, ast.KindElseKeyword, ast.KindCaseKeyword}, anyToken, []formatContextPredicate{isNonJsxSameLineTokenContext, isNonJsxElementOrFragmentContext, isNotForContext}, formatRuleActionInsertSpace),
+		// try {, catch { and finally { when SpaceBeforeOpenBraceInControl did not apply on enter.
+		rule("SpaceAfterTryCatchFinally", []ast.Kind{ast.KindTryKeyword, ast.KindCatchKeyword, ast.KindFinallyKeyword}, ast.KindOpenBraceToken, []formatContextPredicate{isNonJsxSameLineTokenContext}, formatRuleActionInsertSpace),
+	}
+
+	return slices.Concat(highPriorityCommonRules, userConfigurableRules, lowPriorityCommonRules)
+}
+
+// rule creates a formatting rule. The token ranges are a kind, a list of kinds, or a
+// formatTokenRange.
+func rule(name string, left any, right any, context []formatContextPredicate, action formatRuleAction) *formatRule {
+	return &formatRule{name: name, left: toFormatTokenRange(left), right: toFormatTokenRange(right), context: context, action: action}
+}
+
+func (r *formatRule) deletingNewLines() *formatRule {
+	r.canDeleteNewLines = true
+	return r
+}
+
+func toFormatTokenRange(tokens any) formatTokenRange {
+	switch tokens := tokens.(type) {
+	case ast.Kind:
+		return formatTokenRange{tokens: []ast.Kind{tokens}, isSpecific: true}
+	case []ast.Kind:
+		return formatTokenRange{tokens: tokens, isSpecific: true}
+	case formatTokenRange:
+		return tokens
+	}
+	panic("unexpected token range")
+}
+
+func isSettingEnabled(setting func(s *FormatCodeSettings) bool) formatContextPredicate {
+	return func(c *formattingContext) bool { return setting(c.f.settings) }
+}
+
+func isSettingDisabled(setting func(s *FormatCodeSettings) bool) formatContextPredicate {
+	return func(c *formattingContext) bool { return !setting(c.f.settings) }
+}
+
+func isSettingDisabledOrTokensOnSameLine(setting func(s *FormatCodeSettings) bool) formatContextPredicate {
+	return func(c *formattingContext) bool { return !setting(c.f.settings) || c.tokensAreOnSameLine() }
+}
+
+func isSemicolonPreference(preference SemicolonPreference) formatContextPredicate {
+	return func(c *formattingContext) bool { return c.f.settings.Semicolons == preference }
+}
+
+func isForContext(c *formattingContext) bool {
+	return c.contextNode.Kind == ast.KindForStatement
+}
+
+func isNotForContext(c *formattingContext) bool {
+	return !isForContext(c)
+}
+
+func isBinaryOpContext(c *formattingContext) bool {
+	switch c.contextNode.Kind {
+	case ast.KindBinaryExpression:
+		return c.contextNode.AsBinaryExpression().OperatorToken.Kind != ast.KindCommaToken
+	case ast.KindConditionalExpression, ast.KindConditionalType, ast.KindAsExpression, ast.KindExportSpecifier, ast.KindImportSpecifier,
+		ast.KindTypePredicate, ast.KindUnionType, ast.KindIntersectionType, ast.KindSatisfiesExpression:
+		return true
+	case ast.KindBindingElement, ast.KindTypeAliasDeclaration, ast.KindImportEqualsDeclaration, ast.KindExportAssignment,
+		ast.KindVariableDeclaration, ast.KindParameter, ast.KindEnumMember, ast.KindPropertyDeclaration, ast.KindPropertySignature:
+		// The = of an initializer.
+		return c.currentToken.kind == ast.KindEqualsToken || c.nextToken.kind == ast.KindEqualsToken
+	case ast.KindForInStatement, ast.KindTypeParameter:
+		// for (x in y) and [P in keyof T]
+		return c.currentToken.kind == ast.KindInKeyword || c.nextToken.kind == ast.KindInKeyword ||
+			c.currentToken.kind == ast.KindEqualsToken || c.nextToken.kind == ast.KindEqualsToken
+	case ast.KindForOfStatement:
+		return c.currentToken.kind == ast.KindOfKeyword || c.nextToken.kind == ast.KindOfKeyword
+	}
+	return false
+}
+
+func isNotBinaryOpContext(c *formattingContext) bool {
+	return !isBinaryOpContext(c)
+}
+
+func isTypeAnnotationContext(c *formattingContext) bool {
+	switch c.contextNode.Kind {
+	case ast.KindPropertyDeclaration, ast.KindPropertySignature, ast.KindParameter, ast.KindVariableDeclaration:
+		return true
+	}
+	return ast.IsFunctionLike(c.contextNode)
+}
+
+func isNotTypeAnnotationContext(c *formattingContext) bool {
+	return !isTypeAnnotationContext(c)
+}
+
+func isNonOptionalPropertyContext(c *formattingContext) bool {
+	if !ast.IsPropertyDeclaration(c.contextNode) {
+		return true
+	}
+	postfixToken := c.contextNode.AsPropertyDeclaration().PostfixToken
+	return postfixToken == nil || postfixToken.Kind != ast.KindQuestionToken
+}
+
+func isConditionalOperatorContext(c *formattingContext) bool {
+	return c.contextNode.Kind == ast.KindConditionalExpression || c.contextNode.Kind == ast.KindConditionalType
+}
+
+func isSameLineTokenOrBeforeBlockContext(c *formattingContext) bool {
+	return c.tokensAreOnSameLine() || isBeforeBlockContext(c)
+}
+
+func isBraceWrappedContext(c *formattingContext) bool {
+	return c.contextNode.Kind == ast.KindObjectBindingPattern || c.contextNode.Kind == ast.KindMappedType || isSingleLineBlockContext(c)
+}
+
+// isBeforeMultilineBlockContext is checked before the { of a block that spans lines.
+func isBeforeMultilineBlockContext(c *formattingContext) bool {
+	return isBeforeBlockContext(c) && !(c.nextNodeAllOnSameLine() || c.nextNodeBlockIsOnOneLine())
+}
+
+func isMultilineBlockContext(c *formattingContext) bool {
+	return isBlockContext(c) && !(c.contextNodeAllOnSameLine() || c.contextNodeBlockIsOnOneLine())
+}
+
+func isSingleLineBlockContext(c *formattingContext) bool {
+	return isBlockContext(c) && (c.contextNodeAllOnSameLine() || c.contextNodeBlockIsOnOneLine())
+}
+
+func isBlockContext(c *formattingContext) bool {
+	return nodeIsBlockContext(c.contextNode)
+}
+
+func isBeforeBlockContext(c *formattingContext) bool {
+	return nodeIsBlockContext(c.nextTokenParent)
+}
+
+// nodeIsBlockContext reports whether a node has braces among its own tokens.
+func nodeIsBlockContext(node *ast.Node) bool {
+	if nodeIsTypeScriptDeclWithBlockContext(node) {
+		return true
+	}
+	switch node.Kind {
+	case ast.KindBlock, ast.KindCaseBlock, ast.KindObjectLiteralExpression, ast.KindModuleBlock:
+		return true
+	}
+	return false
+}
+
+func isFunctionDeclContext(c *formattingContext) bool {
+	switch c.contextNode.Kind {
+	case ast.KindFunctionDeclaration, ast.KindMethodDeclaration, ast.KindMethodSignature, ast.KindGetAccessor, ast.KindSetAccessor,
+		ast.KindCallSignature, ast.KindFunctionExpression, ast.KindConstructor, ast.KindArrowFunction,
+		// Interfaces are not functions, but their braces are formatted like those of one.
+		ast.KindInterfaceDeclaration:
+		return true
+	}
+	return false
+}
+
+func isNotFunctionDeclContext(c *formattingContext) bool {
+	return !isFunctionDeclContext(c)
+}
+
+func isFunctionDeclarationOrFunctionExpressionContext(c *formattingContext) bool {
+	return c.contextNode.Kind == ast.KindFunctionDeclaration || c.contextNode.Kind == ast.KindFunctionExpression
+}
+
+func isTypeScriptDeclWithBlockContext(c *formattingContext) bool {
+	return nodeIsTypeScriptDeclWithBlockContext(c.contextNode)
+}
+
+func nodeIsTypeScriptDeclWithBlockContext(node *ast.Node) bool {
+	switch node.Kind {
+	case ast.KindClassDeclaration, ast.KindClassExpression, ast.KindInterfaceDeclaration, ast.KindEnumDeclaration, ast.KindTypeLiteral,
+		ast.KindModuleDeclaration, ast.KindExportDeclaration, ast.KindNamedExports, ast.KindImportDeclaration, ast.KindNamedImports:
+		return true
+	}
+	return false
+}
+
+func isAfterCodeBlockContext(c *formattingContext) bool {
+	switch c.currentTokenParent.Kind {
+	case ast.KindClassDeclaration, ast.KindModuleDeclaration, ast.KindEnumDeclaration, ast.KindCatchClause, ast.KindModuleBlock, ast.KindSwitchStatement:
+		return true
+	case ast.KindBlock:
+		blockParent := c.currentTokenParent.Parent
+		return blockParent == nil || blockParent.Kind != ast.KindArrowFunction && blockParent.Kind != ast.KindFunctionExpression
+	}
+	return false
+}
+
+func isControlDeclContext(c *formattingContext) bool {
+	switch c.contextNode.Kind {
+	case ast.KindIfStatement, ast.KindSwitchStatement, ast.KindForStatement, ast.KindForInStatement, ast.KindForOfStatement,
+		ast.KindWhileStatement, ast.KindTryStatement, ast.KindDoStatement, ast.KindWithStatement, ast.KindCatchClause:
+		return true
+	}
+	return false
+}
+
+func isObjectContext(c *formattingContext) bool {
+	return c.contextNode.Kind == ast.KindObjectLiteralExpression
+}
+
+func isFunctionCallOrNewContext(c *formattingContext) bool {
+	return c.contextNode.Kind == ast.KindCallExpression || c.contextNode.Kind == ast.KindNewExpression
+}
+
+func isPreviousTokenNotComma(c *formattingContext) bool {
+	return c.currentToken.kind != ast.KindCommaToken
+}
+
+func isNextTokenNotCloseBracket(c *formattingContext) bool {
+	return c.nextToken.kind != ast.KindCloseBracketToken
+}
+
+func isNextTokenNotCloseParen(c *formattingContext) bool {
+	return c.nextToken.kind != ast.KindCloseParenToken
+}
+
+func isArrowFunctionContext(c *formattingContext) bool {
+	return c.contextNode.Kind == ast.KindArrowFunction
+}
+
+func isImportTypeContext(c *formattingContext) bool {
+	return c.contextNode.Kind == ast.KindImportType
+}
+
+func isNonJsxSameLineTokenContext(c *formattingContext) bool {
+	return c.tokensAreOnSameLine() && c.contextNode.Kind != ast.KindJsxText
+}
+
+func isNonJsxTextContext(c *formattingContext) bool {
+	return c.contextNode.Kind != ast.KindJsxText
+}
+
+func isNonJsxElementOrFragmentContext(c *formattingContext) bool {
+	return c.contextNode.Kind != ast.KindJsxElement && c.contextNode.Kind != ast.KindJsxFragment
+}
+
+func isJsxExpressionContext(c *formattingContext) bool {
+	return c.contextNode.Kind == ast.KindJsxExpression || c.contextNode.Kind == ast.KindJsxSpreadAttribute
+}
+
+func isNextTokenParentJsxAttribute(c *formattingContext) bool {
+	return c.nextTokenParent.Kind == ast.KindJsxAttribute ||
+		c.nextTokenParent.Kind == ast.KindJsxNamespacedName && c.nextTokenParent.Parent.Kind == ast.KindJsxAttribute
+}
+
+func isJsxAttributeContext(c *formattingContext) bool {
+	return c.contextNode.Kind == ast.KindJsxAttribute
+}
+
+func isNextTokenParentNotJsxNamespacedName(c *formattingContext) bool {
+	return !isNextTokenParentJsxNamespacedName(c)
+}
+
+func isNextTokenParentJsxNamespacedName(c *formattingContext) bool {
+	return c.nextTokenParent.Kind == ast.KindJsxNamespacedName
+}
+
+func isJsxSelfClosingElementContext(c *formattingContext) bool {
+	return c.contextNode.Kind == ast.KindJsxSelfClosingElement
+}
+
+func isNotBeforeBlockInFunctionDeclarationContext(c *formattingContext) bool {
+	return !isFunctionDeclContext(c) && !isBeforeBlockContext(c)
+}
+
+func isEndOfDecoratorContextOnSameLine(c *formattingContext) bool {
+	return c.tokensAreOnSameLine() &&
+		ast.HasSyntacticModifier(c.contextNode, ast.ModifierFlagsDecorator) &&
+		nodeIsInDecoratorContext(c.currentTokenParent) &&
+		!nodeIsInDecoratorContext(c.nextTokenParent)
+}
+
+func nodeIsInDecoratorContext(node *ast.Node) bool {
+	for node != nil && ast.IsExpression(node) {
+		node = node.Parent
+	}
+	return node != nil && node.Kind == ast.KindDecorator
+}
+
+func isStartOfVariableDeclarationList(c *formattingContext) bool {
+	return c.currentTokenParent.Kind == ast.KindVariableDeclarationList &&
+		scanner.GetTokenPosOfNode(c.currentTokenParent, c.f.file, false /*includeJSDoc*/) == c.currentToken.pos
+}
+
+func isNotFormatOnEnter(c *formattingContext) bool {
+	return c.f.requestKind != formatRequestKindOnEnter
+}
+
+func isModuleDeclContext(c *formattingContext) bool {
+	return c.contextNode.Kind == ast.KindModuleDeclaration
+}
+
+func isObjectTypeContext(c *formattingContext) bool {
+	return c.contextNode.Kind == ast.KindTypeLiteral
+}
+
+func isConstructorSignatureContext(c *formattingContext) bool {
+	return c.contextNode.Kind == ast.KindConstructSignature
+}
+
+func isTypeArgumentOrParameterOrAssertion(token formatToken, parent *ast.Node) bool {
+	if token.kind != ast.KindLessThanToken && token.kind != ast.KindGreaterThanToken {
+		return false
+	}
+	switch parent.Kind {
+	case ast.KindTypeReference, ast.KindTypeAssertionExpression, ast.KindTypeAliasDeclaration, ast.KindClassDeclaration, ast.KindClassExpression,
+		ast.KindInterfaceDeclaration, ast.KindFunctionDeclaration, ast.KindFunctionExpression, ast.KindArrowFunction, ast.KindMethodDeclaration,
+		ast.KindMethodSignature, ast.KindCallSignature, ast.KindConstructSignature, ast.KindCallExpression, ast.KindNewExpression,
+		ast.KindExpressionWithTypeArguments:
+		return true
+	}
+	return false
+}
+
+func isTypeArgumentOrParameterOrAssertionContext(c *formattingContext) bool {
+	return isTypeArgumentOrParameterOrAssertion(c.currentToken, c.currentTokenParent) ||
+		isTypeArgumentOrParameterOrAssertion(c.nextToken, c.nextTokenParent)
+}
+
+func isTypeAssertionContext(c *formattingContext) bool {
+	return c.contextNode.Kind == ast.KindTypeAssertionExpression
+}
+
+func isNonTypeAssertionContext(c *formattingContext) bool {
+	return !isTypeAssertionContext(c)
+}
+
+func isVoidOpContext(c *formattingContext) bool {
+	return c.currentToken.kind == ast.KindVoidKeyword && c.currentTokenParent.Kind == ast.KindVoidExpression
+}
+
+func isYieldOrYieldStarWithOperand(c *formattingContext) bool {
+	return c.contextNode.Kind == ast.KindYieldExpression && c.contextNode.Expression() != nil
+}
+
+func isNonNullAssertionContext(c *formattingContext) bool {
+	return c.contextNode.Kind == ast.KindNonNullExpression
+}
+
+func isNotStatementConditionContext(c *formattingContext) bool {
+	switch c.contextNode.Kind {
+	case ast.KindIfStatement, ast.KindForStatement, ast.KindForInStatement, ast.KindForOfStatement, ast.KindDoStatement, ast.KindWhileStatement:
+		return false
+	}
+	return true
+}
+
+func isNotPropertyAccessOnIntegerLiteral(c *formattingContext) bool {
+	if !ast.IsPropertyAccessExpression(c.contextNode) {
+		return true
+	}
+	expression := c.contextNode.Expression()
+	return !ast.IsNumericLiteral(expression) || strings.Contains(scanner.GetSourceTextOfNodeFromSourceFile(c.f.file, expression, false /*includeTrivia*/), ".")
+}
+
+// isSemicolonDeletionContext reports whether removing a semicolon leaves the meaning of the code
+// unchanged, because automatic semicolon insertion puts it back.
+func isSemicolonDeletionContext(c *formattingContext) bool {
+	nextTokenKind := c.nextToken.kind
+	if c.tokensAreOnSameLine() {
+		return nextTokenKind == ast.KindCloseBraceToken || nextTokenKind == ast.KindEndOfFile
+	}
+	if nextTokenKind == ast.KindSemicolonToken {
+		return false
+	}
+	parent := c.currentTokenParent
+	if c.contextNode.Kind == ast.KindInterfaceDeclaration || c.contextNode.Kind == ast.KindTypeAliasDeclaration {
+		// In interface I { foo; (): void }, foo would become a method without the semicolon.
+		return !ast.IsPropertySignatureDeclaration(parent) || parent.Type() != nil || nextTokenKind != ast.KindOpenParenToken
+	}
+	if ast.IsPropertyDeclaration(parent) {
+		return parent.Initializer() == nil
+	}
+	switch parent.Kind {
+	case ast.KindForStatement, ast.KindEmptyStatement, ast.KindSemicolonClassElement:
+		return false
+	}
+	switch nextTokenKind {
+	case ast.KindOpenBracketToken, ast.KindOpenParenToken, ast.KindPlusToken, ast.KindMinusToken, ast.KindSlashToken,
+		ast.KindRegularExpressionLiteral, ast.KindCommaToken, ast.KindTemplateHead, ast.KindNoSubstitutionTemplateLiteral, ast.KindDotToken:
+		return false
+	}
+	return true
+}
+
+func isSemicolonInsertionContext(c *formattingContext) bool {
+	return positionIsASICandidate(c, c.currentToken.end, c.currentTokenParent)
+}
+
+// positionIsASICandidate reports whether a statement or member ends at a position without the
+// semicolon that could end it.
+func positionIsASICandidate(c *formattingContext, pos int, context *ast.Node) bool {
+	node := ast.FindAncestorOrQuit(context, func(ancestor *ast.Node) ast.FindAncestorResult {
+		if ancestor.End() != pos {
+			return ast.FindAncestorQuit
+		}
+		if syntaxMayBeASICandidate(ancestor.Kind) {
+			return ast.FindAncestorTrue
+		}
+		return ast.FindAncestorFalse
+	})
+	return node != nil && isASICandidate(c, node)
+}
+
+func syntaxRequiresTrailingCommaOrSemicolonOrASI(kind ast.Kind) bool {
+	switch kind {
+	case ast.KindCallSignature, ast.KindConstructSignature, ast.KindIndexSignature, ast.KindPropertySignature, ast.KindMethodSignature:
+		return true
+	}
+	return false
+}
+
+func syntaxRequiresTrailingFunctionBlockOrSemicolonOrASI(kind ast.Kind) bool {
+	switch kind {
+	case ast.KindFunctionDeclaration, ast.KindConstructor, ast.KindMethodDeclaration, ast.KindGetAccessor, ast.KindSetAccessor:
+		return true
+	}
+	return false
+}
+
+func syntaxRequiresTrailingSemicolonOrASI(kind ast.Kind) bool {
+	switch kind {
+	case ast.KindVariableStatement, ast.KindExpressionStatement, ast.KindDoStatement, ast.KindContinueStatement, ast.KindBreakStatement,
+		ast.KindReturnStatement, ast.KindThrowStatement, ast.KindDebuggerStatement, ast.KindPropertyDeclaration, ast.KindTypeAliasDeclaration,
+		ast.KindImportDeclaration, ast.KindImportEqualsDeclaration, ast.KindExportDeclaration, ast.KindNamespaceExportDeclaration,
+		ast.KindExportAssignment:
+		return true
+	}
+	return false
+}
+
+func syntaxMayBeASICandidate(kind ast.Kind) bool {
+	return syntaxRequiresTrailingCommaOrSemicolonOrASI(kind) ||
+		syntaxRequiresTrailingFunctionBlockOrSemicolonOrASI(kind) ||
+		kind == ast.KindModuleDeclaration ||
+		syntaxRequiresTrailingSemicolonOrASI(kind)
+}
+
+func isASICandidate(c *formattingContext, node *ast.Node) bool {
+	// The current token is the last token of the node.
+	lastToken := c.currentToken.kind
+	if lastToken == ast.KindSemicolonToken {
+		return false
+	}
+	switch {
+	case syntaxRequiresTrailingCommaOrSemicolonOrASI(node.Kind):
+		if lastToken == ast.KindCommaToken {
+			return false
+		}
+	case node.Kind == ast.KindModuleDeclaration:
+		if body := node.Body(); body != nil && ast.IsModuleBlock(body) {
+			return false
+		}
+	case syntaxRequiresTrailingFunctionBlockOrSemicolonOrASI(node.Kind):
+		if node.Body() != nil {
+			return false
+		}
+	case !syntaxRequiresTrailingSemicolonOrASI(node.Kind):
+		return false
+	}
+	// A do statement ends with its ) whatever follows it.
+	if node.Kind == ast.KindDoStatement {
+		return true
+	}
+	if c.nextToken.kind == ast.KindEndOfFile || c.nextToken.kind == ast.KindCloseBraceToken {
+		return true
+	}
+	return !c.tokensAreOnSameLine()
+}
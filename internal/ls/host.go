@@ -2,6 +2,7 @@ package ls
 
 import (
 	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/checker"
 	"github.com/microsoft/typescript-go/internal/compiler"
 	"github.com/microsoft/typescript-go/internal/core"
 	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
@@ -24,7 +25,11 @@ type Host interface {
 	// This responsibility was moved from the language service to the project,
 	// because they were bidirectionally interdependent.
 	GetProgram() *compiler.Program
+	// GetTypeChecker returns a checker of the program that the host does not hand out to anyone
+	// else while the language service uses it.
+	GetTypeChecker() *checker.Checker
 	GetDefaultLibraryPath() string
 	GetPositionEncoding() lsproto.PositionEncodingKind
 	GetScriptInfo(fileName string) ScriptInfo
+	GetUserPreferences() *UserPreferences
 }
@@ -1,23 +1,228 @@
 package ls
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+
 	"github.com/microsoft/typescript-go/internal/ast"
 	"github.com/microsoft/typescript-go/internal/astnav"
+	"github.com/microsoft/typescript-go/internal/checker"
 )
 
-func (l *LanguageService) ProvideHover(fileName string, position int) string {
-	program, file := l.getProgramAndFile(fileName)
+type QuickInfo struct {
+	// DisplayText describes the symbol the way it is declared, as TypeScript code.
+	DisplayText string
+	// Documentation is the JSDoc comment and tags of the symbol, rendered as markdown.
+	Documentation string
+}
+
+func (l *LanguageService) ProvideHover(fileName string, position int) *QuickInfo {
+	_, file := l.getProgramAndFile(fileName)
 	node := astnav.GetTouchingPropertyName(file, position)
 	if node.Kind == ast.KindSourceFile {
 		// Avoid giving quickInfo for the sourceFile as a whole.
-		return ""
+		return nil
+	}
+
+	c := l.GetTypeChecker()
+	// `this` shows its type, rather than the class or `this` parameter that it refers to.
+	if node.Kind == ast.KindThisKeyword && ast.IsExpressionNode(node) {
+		return &QuickInfo{DisplayText: "this: " + c.TypeToString(c.GetTypeAtLocation(node))}
+	}
+	symbol := c.GetSymbolAtLocation(node)
+	if symbol == nil {
+		return nil
+	}
+	return getQuickInfo(c, symbol, node)
+}
+
+func getQuickInfo(c *checker.Checker, symbol *ast.Symbol, location *ast.Node) *QuickInfo {
+	if symbol.Flags&ast.SymbolFlagsAlias != 0 {
+		// Aliases show what they refer to, followed by the import or export that declares them.
+		info := &QuickInfo{}
+		if target := skipAlias(c, symbol); target != symbol {
+			targetInfo := getQuickInfo(c, target, location)
+			info.DisplayText = "(alias) " + targetInfo.DisplayText + "\n"
+			info.Documentation = targetInfo.Documentation
+		}
+		info.DisplayText += getAliasDeclarationText(c, symbol)
+		return info
+	}
+
+	displayText, declarations := getSymbolDisplay(c, symbol, location)
+	return &QuickInfo{
+		DisplayText:   displayText,
+		Documentation: getDocumentation(declarations),
+	}
+}
+
+func getAliasDeclarationText(c *checker.Checker, symbol *ast.Symbol) string {
+	if len(symbol.Declarations) != 0 {
+		switch symbol.Declarations[0].Kind {
+		case ast.KindExportSpecifier, ast.KindNamespaceExport, ast.KindExportAssignment:
+			return "export " + c.SymbolToString(symbol)
+		}
+	}
+	return "import " + c.SymbolToString(symbol)
+}
+
+// getSymbolDisplay returns the quick info text of a symbol that is not an alias, along with the
+// declarations to take its documentation from.
+func getSymbolDisplay(c *checker.Checker, symbol *ast.Symbol, location *ast.Node) (string, []*ast.Node) {
+	flags := symbol.Flags
+	if symbol.ExportSymbol != nil {
+		flags |= symbol.ExportSymbol.Flags
+	}
+	kind := getSymbolKind(symbol, location)
+	name := c.SymbolToString(symbol)
+
+	switch {
+	case flags&ast.SymbolFlagsClass != 0:
+		// `new C()` and the `constructor` keyword show a constructor signature rather than the class.
+		if call := getCallOfCallee(location); call != nil && ast.IsNewExpression(call) {
+			if signature := c.GetResolvedSignature(call); signature != nil && signature.Declaration() != nil {
+				return "constructor " + name + c.SignatureToString(signature), getSignatureDocumentationDeclarations(symbol, signature)
+			}
+		}
+		if location.Kind == ast.KindConstructorKeyword && ast.IsConstructorDeclaration(location.Parent) {
+			return getSignatureDisplay(c, symbol, location, "constructor "+name, c.GetTypeOfSymbolAtLocation(symbol, location), checker.SignatureKindConstruct)
+		}
+		if kind == ScriptElementKindLocalClassElement {
+			return "(local class) " + name + c.TypeParametersToString(symbol), symbol.Declarations
+		}
+		return "class " + name + c.TypeParametersToString(symbol), symbol.Declarations
+	case flags&ast.SymbolFlagsInterface != 0:
+		return "interface " + name + c.TypeParametersToString(symbol), symbol.Declarations
+	case flags&ast.SymbolFlagsTypeAlias != 0:
+		aliasedType := c.TypeToStringEx(c.GetDeclaredTypeOfSymbol(symbol), nil /*enclosingDeclaration*/, checker.TypeFormatFlagsInTypeAlias)
+		return "type " + name + c.TypeParametersToString(symbol) + " = " + aliasedType, symbol.Declarations
+	case flags&ast.SymbolFlagsEnum != 0:
+		if slices.ContainsFunc(symbol.Declarations, ast.IsEnumConst) {
+			return "const enum " + name, symbol.Declarations
+		}
+		return "enum " + name, symbol.Declarations
+	case flags&ast.SymbolFlagsTypeParameter != 0:
+		return "(type parameter) " + name, symbol.Declarations
+	case flags&ast.SymbolFlagsEnumMember != 0:
+		text := "(enum member) " + getQualifiedSymbolName(c, symbol)
+		switch value := c.GetConstantValue(symbol.ValueDeclaration).(type) {
+		case string:
+			text += fmt.Sprintf(" = %q", value)
+		case nil:
+		default:
+			text += fmt.Sprintf(" = %v", value)
+		}
+		return text, symbol.Declarations
+	case flags&ast.SymbolFlagsFunction != 0 && flags&ast.SymbolFlagsVariable == 0:
+		prefix := "function "
+		if kind == ScriptElementKindLocalFunctionElement {
+			prefix = "(local function) "
+		}
+		return getSignatureDisplay(c, symbol, location, prefix+getQualifiedSymbolName(c, symbol), c.GetTypeOfSymbolAtLocation(symbol, location), checker.SignatureKindCall)
+	case flags&ast.SymbolFlagsMethod != 0:
+		return getSignatureDisplay(c, symbol, location, "(method) "+getQualifiedSymbolName(c, symbol), c.GetTypeOfSymbolAtLocation(symbol, location), checker.SignatureKindCall)
+	case flags&ast.SymbolFlagsConstructor != 0 && symbol.Parent != nil:
+		class := symbol.Parent
+		return getSignatureDisplay(c, symbol, location, "constructor "+c.SymbolToString(class), c.GetTypeOfSymbolAtLocation(class, location), checker.SignatureKindConstruct)
+	case flags&ast.SymbolFlagsModule != 0:
+		if strings.HasPrefix(name, "\"") {
+			return "module " + name, symbol.Declarations
+		}
+		return "namespace " + getQualifiedSymbolName(c, symbol), symbol.Declarations
+	}
+
+	var prefix string
+	switch kind {
+	case ScriptElementKindConstElement, ScriptElementKindLetElement, ScriptElementKindVariableElement,
+		ScriptElementKindVariableUsingElement, ScriptElementKindVariableAwaitUsing:
+		prefix = string(kind) + " "
+	case ScriptElementKindUnknown:
+	default:
+		prefix = "(" + string(kind) + ") "
+	}
+	if kind != ScriptElementKindParameterElement && kind != ScriptElementKindLocalVariableElement {
+		name = getQualifiedSymbolName(c, symbol)
+	}
+	if flags&ast.SymbolFlagsOptional != 0 && flags&ast.SymbolFlagsProperty != 0 {
+		name += "?"
+	}
+	return prefix + name + ": " + c.TypeToString(getTypeOfSymbolAtLocation(c, symbol, location)), symbol.Declarations
+}
+
+// getTypeOfSymbolAtLocation returns the narrowed type of a symbol where it is referenced in an
+// expression, and its declared type elsewhere.
+func getTypeOfSymbolAtLocation(c *checker.Checker, symbol *ast.Symbol, location *ast.Node) *checker.Type {
+	if ast.IsIdentifier(location) && ast.IsExpressionNode(location) && c.GetSymbolAtLocation(location) == symbol {
+		if t := c.GetTypeAtLocation(location); t != nil {
+			return t
+		}
+	}
+	return c.GetTypeOfSymbolAtLocation(symbol, location)
+}
+
+// getSignatureDisplay shows a function-like symbol by one of its signatures: the one that is
+// called at location, the one declared at location, or else the first.
+func getSignatureDisplay(c *checker.Checker, symbol *ast.Symbol, location *ast.Node, prefix string, t *checker.Type, kind checker.SignatureKind) (string, []*ast.Node) {
+	signatures := c.GetSignaturesOfType(t, kind)
+	if len(signatures) == 0 {
+		return prefix + ": " + c.TypeToString(t), symbol.Declarations
 	}
 
-	checker := program.GetTypeChecker()
-	if symbol := checker.GetSymbolAtLocation(node); symbol != nil {
-		if t := checker.GetTypeOfSymbolAtLocation(symbol, node); t != nil {
-			return checker.TypeToString(t)
+	signature := signatures[0]
+	if call := getCallOfCallee(location); call != nil {
+		if resolved := c.GetResolvedSignature(call); resolved != nil && resolved.Declaration() != nil && slices.Contains(symbol.Declarations, resolved.Declaration()) {
+			signature = resolved
 		}
+	} else if location.Parent != nil && ast.IsFunctionLike(location.Parent) {
+		declaration := location.Parent
+		if ast.IsConstructorDeclaration(declaration) || declaration.Name() == location {
+			if index := slices.IndexFunc(signatures, func(s *checker.Signature) bool { return s.Declaration() == declaration }); index >= 0 {
+				signature = signatures[index]
+			}
+		}
+	}
+
+	text := prefix + c.SignatureToString(signature)
+	if overloads := len(signatures) - 1; overloads == 1 {
+		text += " (+1 overload)"
+	} else if overloads > 1 {
+		text += fmt.Sprintf(" (+%d overloads)", overloads)
+	}
+	return text, getSignatureDocumentationDeclarations(symbol, signature)
+}
+
+// getSignatureDocumentationDeclarations prefers the documentation of an overload over that of
+// the whole symbol.
+func getSignatureDocumentationDeclarations(symbol *ast.Symbol, signature *checker.Signature) []*ast.Node {
+	if declaration := signature.Declaration(); declaration != nil && len(declaration.JSDoc(nil)) != 0 {
+		return []*ast.Node{declaration}
+	}
+	return symbol.Declarations
+}
+
+// getCallOfCallee returns the call or `new` expression that location is the callee of, as in
+// `f()` or `o.f()`.
+func getCallOfCallee(location *ast.Node) *ast.Node {
+	callee := location
+	if callee.Parent != nil && ast.IsPropertyAccessExpression(callee.Parent) && callee.Parent.Name() == callee {
+		callee = callee.Parent
+	}
+	if parent := callee.Parent; parent != nil && (ast.IsCallExpression(parent) || ast.IsNewExpression(parent)) && parent.Expression() == callee {
+		return parent
+	}
+	return nil
+}
+
+// getQualifiedSymbolName prefixes the name of a member with the name of the class, interface,
+// enum or namespace that declares it, as in `C.m`.
+func getQualifiedSymbolName(c *checker.Checker, symbol *ast.Symbol) string {
+	name := c.SymbolToString(symbol)
+	parent := symbol.Parent
+	if parent == nil || parent.Flags&(ast.SymbolFlagsClass|ast.SymbolFlagsInterface|ast.SymbolFlagsEnum|ast.SymbolFlagsModule) == 0 ||
+		strings.HasPrefix(parent.Name, "\"") || ast.GetDeclarationOfKind(parent, ast.KindSourceFile) != nil ||
+		strings.HasPrefix(parent.Name, ast.InternalSymbolNamePrefix) {
+		return name
 	}
-	return ""
+	return getQualifiedSymbolName(c, parent) + "." + name
 }
@@ -0,0 +1,182 @@
+package ls_test
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestHover(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		title                 string
+		text                  string
+		expectedDisplayText   string
+		expectedDocumentation string
+	}{
+		{
+			title:               "const",
+			text:                `const /*1*/answer = 42;`,
+			expectedDisplayText: "const answer: 42",
+		},
+		{
+			title:               "let",
+			text:                `let /*1*/answer = 42;`,
+			expectedDisplayText: "let answer: number",
+		},
+		{
+			title: "narrowed variable",
+			text: `declare const value: string | number;
+if (typeof value === "string") {
+    /*1*/value;
+}`,
+			expectedDisplayText: "const value: string",
+		},
+		{
+			title:               "parameter",
+			text:                `function f(/*1*/count: number) {}`,
+			expectedDisplayText: "(parameter) count: number",
+		},
+		{
+			title: "local variable",
+			text: `function f() {
+    var /*1*/local = "";
+}`,
+			expectedDisplayText: "(local var) local: string",
+		},
+		{
+			title: "function with documentation",
+			text: `/**
+ * Adds two numbers.
+ * @param a The first number.
+ * @returns The sum.
+ */
+function /*1*/add(a: number, b: number): number { return a + b; }`,
+			expectedDisplayText:   "function add(a: number, b: number): number",
+			expectedDocumentation: "Adds two numbers.\n\n*@param* `a` — The first number.\n\n*@returns* — The sum.",
+		},
+		{
+			title: "overloaded function call",
+			text: `function parse(value: string): number;
+function parse(value: number): string;
+function parse(value: any): any { return value; }
+/*1*/parse(1);`,
+			expectedDisplayText: "function parse(value: number): string (+1 overload)",
+		},
+		{
+			title: "local function",
+			text: `function outer() {
+    function /*1*/inner() {}
+}`,
+			expectedDisplayText: "(local function) inner(): void",
+		},
+		{
+			title:               "generic class",
+			text:                `class /*1*/Box<T> { value!: T; }`,
+			expectedDisplayText: "class Box<T>",
+		},
+		{
+			title: "constructor call",
+			text: `class Point { constructor(x: number, y: number) {} }
+new /*1*/Point(1, 2);`,
+			expectedDisplayText: "constructor Point(x: number, y: number): Point",
+		},
+		{
+			title: "method",
+			text: `class Counter { increment(by: number): void {} }
+new Counter()./*1*/increment(1);`,
+			expectedDisplayText: "(method) Counter.increment(by: number): void",
+		},
+		{
+			title: "property",
+			text: `interface Options {
+    /** Whether to log. */
+    /*1*/verbose?: boolean;
+}`,
+			expectedDisplayText:   "(property) Options.verbose?: boolean | undefined",
+			expectedDocumentation: "Whether to log.",
+		},
+		{
+			title:               "interface",
+			text:                `interface /*1*/Map2<K, V> {}`,
+			expectedDisplayText: "interface Map2<K, V>",
+		},
+		{
+			title:               "type alias",
+			text:                `type /*1*/Pair<T> = [T, T];`,
+			expectedDisplayText: "type Pair<T> = [T, T]",
+		},
+		{
+			title: "enum member",
+			text: `enum Color { Red = 1, Green }
+Color./*1*/Green;`,
+			expectedDisplayText: "(enum member) Color.Green = 2",
+		},
+		{
+			title:               "string enum member",
+			text:                `enum Direction { /*1*/Up = "UP" }`,
+			expectedDisplayText: `(enum member) Direction.Up = "UP"`,
+		},
+		{
+			title:               "const enum",
+			text:                `const enum /*1*/Flags { None }`,
+			expectedDisplayText: "const enum Flags",
+		},
+		{
+			title:               "namespace",
+			text:                `namespace Outer.Inner { export const x = 1; } /*1*/Outer;`,
+			expectedDisplayText: "namespace Outer",
+		},
+		{
+			title:               "type parameter",
+			text:                `function identity</*1*/T>(value: T): T { return value; }`,
+			expectedDisplayText: "(type parameter) T",
+		},
+		{
+			title: "this",
+			text: `class Node2 {
+    next() { return /*1*/this; }
+}`,
+			expectedDisplayText: "this: this",
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.title, func(t *testing.T) {
+			t.Parallel()
+			p := newTestProject(t, map[string]string{
+				"/home/src/project/tsconfig.json": `{ "compilerOptions": { "strict": true } }`,
+				"/home/src/project/index.ts":      testCase.text,
+			})
+			l, m := p.languageServiceAt("1")
+			info := l.ProvideHover(m.fileName, m.position)
+			assert.Assert(t, info != nil)
+			assert.Equal(t, info.DisplayText, testCase.expectedDisplayText)
+			assert.Equal(t, info.Documentation, testCase.expectedDocumentation)
+		})
+	}
+
+	t.Run("alias", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/tsconfig.json": `{}`,
+			"/home/src/project/a.ts": `/** The version. */
+export const version = "1.0";`,
+			"/home/src/project/b.ts": `import { /*1*/version } from "./a";`,
+		})
+		l, m := p.languageServiceAt("1")
+		info := l.ProvideHover(m.fileName, m.position)
+		assert.Equal(t, info.DisplayText, "(alias) const version: \"1.0\"\nimport version")
+		assert.Equal(t, info.Documentation, "The version.")
+	})
+
+	t.Run("no symbol", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/index.ts": `const x = /*1*/ 1;`,
+		})
+		l, m := p.languageServiceAt("1")
+		assert.Assert(t, l.ProvideHover(m.fileName, m.position) == nil)
+	})
+}
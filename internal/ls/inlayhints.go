@@ -0,0 +1,373 @@
+package ls
+
+import (
+	"fmt"
+	"iter"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/checker"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"github.com/microsoft/typescript-go/internal/scanner"
+)
+
+type InlayParameterNameHints string
+
+const (
+	InlayParameterNameHintsNone     InlayParameterNameHints = "none"
+	InlayParameterNameHintsLiterals InlayParameterNameHints = "literals"
+	InlayParameterNameHintsAll      InlayParameterNameHints = "all"
+)
+
+// InlayHintsPreferences selects the kinds of inlay hints to show. The preferences are named after
+// the inlay hint preferences of tsserver.
+type InlayHintsPreferences struct {
+	// IncludeInlayParameterNameHints shows the parameter names of the arguments of calls, either
+	// for literal arguments only or for all of them.
+	IncludeInlayParameterNameHints                        InlayParameterNameHints
+	IncludeInlayParameterNameHintsWhenArgumentMatchesName bool
+	IncludeInlayFunctionParameterTypeHints                bool
+	IncludeInlayVariableTypeHints                         bool
+	IncludeInlayVariableTypeHintsWhenTypeMatchesName      bool
+	IncludeInlayPropertyDeclarationTypeHints              bool
+	IncludeInlayFunctionLikeReturnTypeHints               bool
+	IncludeInlayEnumMemberValueHints                      bool
+}
+
+type InlayHint struct {
+	Position int
+	Label    []InlayHintLabelPart
+	// Kind is 0 for the values of enum members, which are neither types nor parameters.
+	Kind         lsproto.InlayHintKind
+	PaddingLeft  bool
+	PaddingRight bool
+}
+
+// InlayHintLabelPart is a piece of the label of a hint, with the location of the declaration it
+// names, if any.
+type InlayHintLabelPart struct {
+	Text     string
+	Location *Location
+}
+
+// maxTypeHintLength is the length that printed types are truncated to, so that a hint does not
+// push the rest of its line out of view.
+const maxTypeHintLength = 30
+
+// ProvideInlayHints returns the hints for the nodes of a file within span, in document order.
+func (l *LanguageService) ProvideInlayHints(fileName string, span core.TextRange) []InlayHint {
+	_, file := l.getProgramAndFile(fileName)
+	preferences := &l.host.GetUserPreferences().InlayHintsPreferences
+	h := &inlayHintsProvider{
+		c:           l.GetTypeChecker(),
+		file:        file,
+		preferences: preferences,
+	}
+	showParameterNames := preferences.IncludeInlayParameterNameHints == InlayParameterNameHintsLiterals ||
+		preferences.IncludeInlayParameterNameHints == InlayParameterNameHintsAll
+
+	var visit func(node *ast.Node) bool
+	visit = func(node *ast.Node) bool {
+		if node.Pos() == node.End() || node.Pos() >= span.End() || node.End() <= span.Pos() {
+			return false
+		}
+		if ast.IsTypeNode(node) && !ast.IsExpressionWithTypeArguments(node) {
+			return false
+		}
+		switch {
+		case ast.IsVariableDeclaration(node):
+			if preferences.IncludeInlayVariableTypeHints {
+				h.visitVariableLikeDeclaration(node)
+			}
+		case ast.IsPropertyDeclaration(node):
+			if preferences.IncludeInlayPropertyDeclarationTypeHints {
+				h.visitVariableLikeDeclaration(node)
+			}
+		case ast.IsEnumMember(node):
+			if preferences.IncludeInlayEnumMemberValueHints {
+				h.visitEnumMember(node)
+			}
+		case ast.IsCallExpression(node) || ast.IsNewExpression(node):
+			if showParameterNames {
+				h.visitCallOrNewExpression(node)
+			}
+		default:
+			if preferences.IncludeInlayFunctionParameterTypeHints && ast.IsFunctionExpressionOrArrowFunction(node) {
+				h.visitFunctionLikeForParameterTypes(node)
+			}
+			if preferences.IncludeInlayFunctionLikeReturnTypeHints && isSignatureSupportingReturnAnnotation(node) {
+				h.visitFunctionLikeForReturnType(node)
+			}
+		}
+		node.ForEachChild(visit)
+		return false
+	}
+	file.AsNode().ForEachChild(visit)
+
+	slices.SortStableFunc(h.hints, func(a, b InlayHint) int {
+		return a.Position - b.Position
+	})
+	return h.hints
+}
+
+type inlayHintsProvider struct {
+	c           *checker.Checker
+	file        *ast.SourceFile
+	preferences *InlayHintsPreferences
+	hints       []InlayHint
+}
+
+func (h *inlayHintsProvider) visitEnumMember(member *ast.Node) {
+	if member.Initializer() != nil {
+		return
+	}
+	var text string
+	switch value := h.c.GetConstantValue(member).(type) {
+	case nil:
+		return
+	case string:
+		text = fmt.Sprintf("%q", value)
+	default:
+		text = fmt.Sprintf("%v", value)
+	}
+	h.hints = append(h.hints, InlayHint{
+		Position:    member.End(),
+		Label:       []InlayHintLabelPart{{Text: "= " + text}},
+		PaddingLeft: true,
+	})
+}
+
+func (h *inlayHintsProvider) visitVariableLikeDeclaration(decl *ast.Node) {
+	if decl.Type() != nil || ast.IsBindingPattern(decl.Name()) {
+		return
+	}
+	if decl.Initializer() == nil {
+		// A property without an initializer is shown only if it is typed by an assignment in the
+		// constructor, rather than being implicitly any.
+		if !ast.IsPropertyDeclaration(decl) || h.c.GetTypeAtLocation(decl).Flags()&checker.TypeFlagsAny != 0 {
+			return
+		}
+	}
+	if ast.IsVariableDeclaration(decl) && !isHintableDeclaration(decl) {
+		return
+	}
+	t := h.c.GetTypeAtLocation(decl)
+	if isModuleReferenceType(t) {
+		return
+	}
+	label := h.getTypeLabel(t, decl)
+	if !h.preferences.IncludeInlayVariableTypeHintsWhenTypeMatchesName && strings.EqualFold(label.Text, scanner.GetSourceTextOfNodeFromSourceFile(h.file, decl.Name(), false /*includeTrivia*/)) {
+		return
+	}
+	h.addTypeHint(label, decl.Name().End())
+}
+
+func (h *inlayHintsProvider) visitCallOrNewExpression(expr *ast.Node) {
+	args := expr.Arguments()
+	if len(args) == 0 {
+		return
+	}
+	signature := h.c.GetResolvedSignature(expr)
+	if signature == nil {
+		return
+	}
+	literalsOnly := h.preferences.IncludeInlayParameterNameHints == InlayParameterNameHintsLiterals
+	signatureParamPos := 0
+	for _, originalArg := range args {
+		arg := ast.SkipParentheses(originalArg)
+		if literalsOnly && !isHintableLiteral(arg) {
+			signatureParamPos++
+			continue
+		}
+		// A spread of a tuple passes its required elements to as many parameters.
+		spreadArgs := 0
+		if ast.IsSpreadElement(arg) {
+			spreadArgs = h.c.GetRequiredTupleElementCount(h.c.GetTypeAtLocation(arg.Expression()))
+		}
+		name, isRest := h.c.GetParameterIdentifierAtPosition(signature, signatureParamPos)
+		signatureParamPos += max(spreadArgs, 1)
+		if name == nil {
+			continue
+		}
+		parameterName := name.Text()
+		if !h.preferences.IncludeInlayParameterNameHintsWhenArgumentMatchesName && !isRest && argumentMatchesParameterName(arg, parameterName) {
+			continue
+		}
+		if h.leadingCommentsContainParameterName(originalArg, parameterName) {
+			continue
+		}
+		if isRest {
+			parameterName = "..." + parameterName
+		}
+		location := getDeclarationLocation(name)
+		h.hints = append(h.hints, InlayHint{
+			Position:     scanner.GetTokenPosOfNode(originalArg, h.file, false /*includeJSDoc*/),
+			Label:        []InlayHintLabelPart{{Text: parameterName, Location: &location}, {Text: ":"}},
+			Kind:         lsproto.InlayHintKindParameter,
+			PaddingRight: true,
+		})
+	}
+}
+
+// argumentMatchesParameterName reports whether an argument is a name, or ends in one, that is the
+// same as the name of its parameter, which makes a hint redundant.
+func argumentMatchesParameterName(arg *ast.Node, parameterName string) bool {
+	switch {
+	case ast.IsIdentifier(arg):
+		return arg.Text() == parameterName
+	case ast.IsPropertyAccessExpression(arg):
+		return arg.Name().Text() == parameterName
+	}
+	return false
+}
+
+// leadingCommentsContainParameterName reports whether an argument is already labeled with the name
+// of its parameter by a comment, as in f(/*name*/ 1). The comments that precede the argument on
+// its own line are trailing comments of the previous token, so both kinds are checked.
+func (h *inlayHintsProvider) leadingCommentsContainParameterName(arg *ast.Node, name string) bool {
+	pattern := regexp.MustCompile(`^\s?/\*\*?\s?` + regexp.QuoteMeta(name) + `\s?\*/\s?$`)
+	text := h.file.Text()
+	factory := &ast.NodeFactory{}
+	for _, comments := range []iter.Seq[ast.CommentRange]{
+		scanner.GetTrailingCommentRanges(factory, text, arg.Pos()),
+		scanner.GetLeadingCommentRanges(factory, text, arg.Pos()),
+	} {
+		for comment := range comments {
+			if pattern.MatchString(text[comment.Pos():comment.End()]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// visitFunctionLikeForParameterTypes shows the types of the parameters of a function expression
+// that are typed by the context of the function rather than by annotations.
+func (h *inlayHintsProvider) visitFunctionLikeForParameterTypes(node *ast.Node) {
+	if node.TypeParameters() != nil || h.c.GetContextualType(node, checker.ContextFlagsNone) == nil {
+		return
+	}
+	for _, param := range node.Parameters() {
+		if param.Type() != nil || ast.IsThisParameter(param) || param.Symbol() == nil || !isHintableDeclaration(param) {
+			continue
+		}
+		t := h.c.GetTypeOfSymbolAtLocation(param.Symbol(), param)
+		if isModuleReferenceType(t) {
+			continue
+		}
+		position := param.Name().End()
+		if questionToken := param.AsParameterDeclaration().QuestionToken; questionToken != nil {
+			position = questionToken.End()
+		}
+		h.addTypeHint(h.getTypeLabel(t, param), position)
+	}
+}
+
+func (h *inlayHintsProvider) visitFunctionLikeForReturnType(decl *ast.Node) {
+	if decl.Type() != nil || decl.Body() == nil {
+		return
+	}
+	// The return type of an arrow function goes after its parameter list, which cannot be
+	// annotated without parentheses.
+	if ast.IsArrowFunction(decl) && !h.hasParenthesizedParameters(decl) {
+		return
+	}
+	signature := h.c.GetSignatureFromDeclaration(decl)
+	if signature == nil {
+		return
+	}
+	position := h.getReturnTypeAnnotationPosition(decl)
+	if predicate := h.c.GetTypePredicateOfSignature(signature); predicate != nil {
+		h.addTypeHint(InlayHintLabelPart{Text: h.c.TypePredicateToString(predicate)}, position)
+		return
+	}
+	returnType := h.c.GetReturnTypeOfSignature(signature)
+	if isModuleReferenceType(returnType) {
+		return
+	}
+	h.addTypeHint(h.getTypeLabel(returnType, decl), position)
+}
+
+func (h *inlayHintsProvider) hasParenthesizedParameters(decl *ast.Node) bool {
+	start := decl.Pos()
+	if modifiers := decl.Modifiers(); modifiers != nil {
+		start = modifiers.End()
+	}
+	text := h.file.Text()
+	start = scanner.SkipTrivia(text, start)
+	return start < len(text) && (text[start] == '(' || text[start] == '<')
+}
+
+// getReturnTypeAnnotationPosition returns the end of the closing parenthesis of the parameter list
+// of a function, which is where an annotation of its return type starts.
+func (h *inlayHintsProvider) getReturnTypeAnnotationPosition(decl *ast.Node) int {
+	end := decl.ParameterList().End()
+	if pos := scanner.SkipTrivia(h.file.Text(), end); pos < len(h.file.Text()) && h.file.Text()[pos] == ')' {
+		return pos + 1
+	}
+	return end
+}
+
+// getTypeLabel prints a type for a hint, linked to the declaration of the type if it is named.
+func (h *inlayHintsProvider) getTypeLabel(t *checker.Type, enclosingDeclaration *ast.Node) InlayHintLabelPart {
+	label := InlayHintLabelPart{Text: h.c.TypeToStringTruncated(t, enclosingDeclaration, checker.TypeFormatFlagsNone, maxTypeHintLength)}
+	symbol := t.Alias().Symbol()
+	if symbol == nil {
+		symbol = t.Symbol()
+	}
+	if symbol != nil && len(symbol.Declarations) != 0 && !strings.HasPrefix(symbol.Name, ast.InternalSymbolNamePrefix) {
+		location := getDeclarationLocation(core.OrElse(ast.GetNameOfDeclaration(symbol.Declarations[0]), symbol.Declarations[0]))
+		label.Location = &location
+	}
+	return label
+}
+
+func (h *inlayHintsProvider) addTypeHint(label InlayHintLabelPart, position int) {
+	h.hints = append(h.hints, InlayHint{
+		Position:    position,
+		Label:       []InlayHintLabelPart{{Text: ": "}, label},
+		Kind:        lsproto.InlayHintKindType,
+		PaddingLeft: true,
+	})
+}
+
+func isSignatureSupportingReturnAnnotation(node *ast.Node) bool {
+	switch node.Kind {
+	case ast.KindArrowFunction, ast.KindFunctionExpression, ast.KindFunctionDeclaration, ast.KindMethodDeclaration, ast.KindGetAccessor:
+		return true
+	}
+	return false
+}
+
+// isModuleReferenceType reports whether a type is that of a module or namespace, which has no
+// name to show.
+func isModuleReferenceType(t *checker.Type) bool {
+	return t.Symbol() != nil && t.Symbol().Flags&ast.SymbolFlagsModule != 0
+}
+
+// isHintableDeclaration reports whether the type of a declaration is not already evident from
+// its initializer. The initializer of a const or a parameter is evident when it is a literal,
+// a new expression, an object literal or an assertion.
+func isHintableDeclaration(node *ast.Node) bool {
+	if (ast.IsParameter(node) || ast.IsVariableDeclaration(node) && ast.IsVarConst(node)) && node.Initializer() != nil {
+		initializer := ast.SkipParentheses(node.Initializer())
+		return !(isHintableLiteral(initializer) || ast.IsNewExpression(initializer) || ast.IsObjectLiteralExpression(initializer) || ast.IsAssertionExpression(initializer))
+	}
+	return true
+}
+
+func isHintableLiteral(node *ast.Node) bool {
+	switch node.Kind {
+	case ast.KindPrefixUnaryExpression:
+		operand := node.AsPrefixUnaryExpression().Operand
+		return ast.IsLiteralExpression(operand) || ast.IsIdentifier(operand) && isInfinityOrNaN(operand)
+	case ast.KindTrueKeyword, ast.KindFalseKeyword, ast.KindNullKeyword, ast.KindNoSubstitutionTemplateLiteral, ast.KindTemplateExpression:
+		return true
+	case ast.KindIdentifier:
+		return node.Text() == "undefined" || isInfinityOrNaN(node)
+	}
+	return ast.IsLiteralExpression(node)
+}
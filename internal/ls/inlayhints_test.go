@@ -0,0 +1,210 @@
+package ls_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/ls"
+	"gotest.tools/v3/assert"
+)
+
+// renderInlayHints writes each hint into the text at its position, as in "let x<: number> = f()".
+func renderInlayHints(text string, hints []ls.InlayHint) string {
+	for _, hint := range slices.Backward(hints) {
+		var label strings.Builder
+		for _, part := range hint.Label {
+			label.WriteString(part.Text)
+		}
+		text = text[:hint.Position] + "<" + label.String() + ">" + text[hint.Position:]
+	}
+	return text
+}
+
+func TestInlayHints(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		title       string
+		text        string
+		preferences func(preferences *ls.InlayHintsPreferences)
+		expected    string
+	}{
+		{
+			title: "variable types",
+			text: `declare function getName(): string;
+declare function getDate(): Date;
+let values = [1, 2];
+const count = 1;
+const now = new Date();
+const name = getName();
+const date = getDate();
+const { x } = { x: 1 };
+let typed: number = 1;`,
+			preferences: func(preferences *ls.InlayHintsPreferences) {
+				preferences.IncludeInlayVariableTypeHints = true
+			},
+			// The type of a const is evident from a literal or new expression, and is left out
+			// when it is the name of the variable
+			expected: `declare function getName(): string;
+declare function getDate(): Date;
+let values<: number[]> = [1, 2];
+const count = 1;
+const now = new Date();
+const name<: string> = getName();
+const date = getDate();
+const { x } = { x: 1 };
+let typed: number = 1;`,
+		},
+		{
+			title: "variable types that match the name",
+			text: `declare function getDate(): Date;
+const date = getDate();`,
+			preferences: func(preferences *ls.InlayHintsPreferences) {
+				preferences.IncludeInlayVariableTypeHints = true
+				preferences.IncludeInlayVariableTypeHintsWhenTypeMatchesName = true
+			},
+			expected: `declare function getDate(): Date;
+const date<: Date> = getDate();`,
+		},
+		{
+			title: "parameter names of literals",
+			text: `declare function greet(name: string, times: number, loud?: boolean): void;
+declare const times: number;
+greet("Ann", times, true);
+greet(String(1), -1);`,
+			preferences: func(preferences *ls.InlayHintsPreferences) {
+				preferences.IncludeInlayParameterNameHints = ls.InlayParameterNameHintsLiterals
+			},
+			expected: `declare function greet(name: string, times: number, loud?: boolean): void;
+declare const times: number;
+greet(<name:>"Ann", times, <loud:>true);
+greet(String(<value:>1), <times:>-1);`,
+		},
+		{
+			title: "parameter names of all arguments",
+			text: `declare function greet(name: string, times: number): void;
+declare function log(...messages: string[]): void;
+declare const options: { times: number };
+greet(String(1), options.times);
+greet(/* name */ "Ann", 1 + 1);
+log("a", "b");
+new Date(2000, 1);`,
+			preferences: func(preferences *ls.InlayHintsPreferences) {
+				preferences.IncludeInlayParameterNameHints = ls.InlayParameterNameHintsAll
+			},
+			// Arguments that already name their parameter are left out, and the arguments of a rest
+			// parameter after the first
+			expected: `declare function greet(name: string, times: number): void;
+declare function log(...messages: string[]): void;
+declare const options: { times: number };
+greet(<name:>String(<value:>1), options.times);
+greet(/* name */ "Ann", <times:>1 + 1);
+log(<...messages:>"a", "b");
+new Date(<year:>2000, <monthIndex:>1);`,
+		},
+		{
+			title: "function parameter and return types",
+			text: `const doubled = [1, 2].map(n => n * 2);
+const handler: (event: string, extra?: number) => void = (event, extra?) => {};
+function add(a: number, b: number) { return a + b; }
+const square = (n: number) => n * n;
+const half = [1].map(n => n / 2);
+function isString(value: unknown) { return typeof value === "string"; }`,
+			preferences: func(preferences *ls.InlayHintsPreferences) {
+				preferences.IncludeInlayFunctionParameterTypeHints = true
+				preferences.IncludeInlayFunctionLikeReturnTypeHints = true
+			},
+			// Arrow functions without parentheses around their parameter cannot be annotated
+			// with a return type
+			expected: `const doubled = [1, 2].map(n<: number> => n * 2);
+const handler: (event: string, extra?: number) => void = (event<: string>, extra?<: number | undefined>)<: void> => {};
+function add(a: number, b: number)<: number> { return a + b; }
+const square = (n: number)<: number> => n * n;
+const half = [1].map(n<: number> => n / 2);
+function isString(value: unknown)<: value is string> { return typeof value === "string"; }`,
+		},
+		{
+			title: "property declaration types",
+			text: `class Point {
+    x = 1;
+    y;
+    z;
+    constructor() { this.y = ""; }
+}`,
+			preferences: func(preferences *ls.InlayHintsPreferences) {
+				preferences.IncludeInlayPropertyDeclarationTypeHints = true
+			},
+			expected: `class Point {
+    x<: number> = 1;
+    y<: string>;
+    z;
+    constructor() { this.y = ""; }
+}`,
+		},
+		{
+			title: "enum member values",
+			text: `enum Color { Red, Green = 5, Blue }
+enum Direction { Up = "UP" }`,
+			preferences: func(preferences *ls.InlayHintsPreferences) {
+				preferences.IncludeInlayEnumMemberValueHints = true
+			},
+			expected: `enum Color { Red<= 0>, Green = 5, Blue<= 6> }
+enum Direction { Up = "UP" }`,
+		},
+		{
+			title: "long types are truncated",
+			text: `declare function f(): { first: string; second: string; third: string };
+let value = f();`,
+			preferences: func(preferences *ls.InlayHintsPreferences) {
+				preferences.IncludeInlayVariableTypeHints = true
+			},
+			expected: `declare function f(): { first: string; second: string; third: string };
+let value<: { first: string; second: st...> = f();`,
+		},
+		{
+			title:    "nothing by default",
+			text:     `let values = [1].map(n => n); function f() { return 1; }`,
+			expected: `let values = [1].map(n => n); function f() { return 1; }`,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.title, func(t *testing.T) {
+			t.Parallel()
+			p := newTestProject(t, map[string]string{
+				"/home/src/project/tsconfig.json": `{ "compilerOptions": { "strict": true } }`,
+				"/home/src/project/index.ts":      testCase.text,
+			})
+			preferences := ls.GetDefaultUserPreferences()
+			if testCase.preferences != nil {
+				testCase.preferences(&preferences.InlayHintsPreferences)
+			}
+			p.service.SetUserPreferences(preferences)
+			l := p.languageService("/home/src/project/index.ts")
+			hints := l.ProvideInlayHints("/home/src/project/index.ts", core.NewTextRange(0, len(testCase.text)))
+			assert.Equal(t, renderInlayHints(p.files["/home/src/project/index.ts"], hints), testCase.expected)
+		})
+	}
+
+	t.Run("span and label locations", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/index.ts": `interface /*Point*/Point { x: number }
+declare function f(): Point;
+let a = f();
+/*start*/let b = f();/*end*/
+let c = f();`,
+		})
+		preferences := ls.GetDefaultUserPreferences()
+		preferences.IncludeInlayVariableTypeHints = true
+		p.service.SetUserPreferences(preferences)
+		l, start := p.languageServiceAt("start")
+		hints := l.ProvideInlayHints(start.fileName, core.NewTextRange(start.position, p.marker("end").position))
+		assert.Equal(t, len(hints), 1)
+		assert.Equal(t, hints[0].Position, start.position+len("let b"))
+		assert.Equal(t, hints[0].Label[1].Text, "Point")
+		assert.Equal(t, p.markerAt(*hints[0].Label[1].Location), "Point")
+	})
+}
@@ -1,7 +1,11 @@
 package ls
 
 import (
+	"context"
+	"sync"
+
 	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/checker"
 	"github.com/microsoft/typescript-go/internal/compiler"
 	"github.com/microsoft/typescript-go/internal/core"
 	"github.com/microsoft/typescript-go/internal/tspath"
@@ -11,17 +15,51 @@ import (
 var _ compiler.CompilerHost = (*LanguageService)(nil)
 
 type LanguageService struct {
+	// ctx is canceled when the result of the request that the language service answers is no
+	// longer needed.
+	ctx        context.Context
 	converters *Converters
 	host       Host
+
+	*languageServiceIndexes
+}
+
+// languageServiceIndexes are the indexes over a program that are kept across requests and
+// rebuilt for the files that changed when the program does.
+type languageServiceIndexes struct {
+	symbolIndexMu sync.Mutex
+	symbolIndex   *symbolIndex
+
+	exportInfoMu sync.Mutex
+	exportInfo   *exportInfoMap
 }
 
 func NewLanguageService(host Host) *LanguageService {
 	return &LanguageService{
-		host:       host,
-		converters: NewConverters(host.GetPositionEncoding(), host.GetScriptInfo),
+		ctx:                    context.Background(),
+		host:                   host,
+		converters:             NewConverters(host.GetPositionEncoding(), host.GetScriptInfo),
+		languageServiceIndexes: &languageServiceIndexes{},
 	}
 }
 
+// ForSnapshot returns a language service that answers a request from host, a snapshot of the
+// project that l serves, and stops early when ctx is canceled. It shares its indexes with l.
+func (l *LanguageService) ForSnapshot(ctx context.Context, host Host) *LanguageService {
+	return &LanguageService{
+		ctx:                    ctx,
+		host:                   host,
+		converters:             NewConverters(host.GetPositionEncoding(), host.GetScriptInfo),
+		languageServiceIndexes: l.languageServiceIndexes,
+	}
+}
+
+// Converters converts between positions in the files of the language service's program and
+// LSP positions.
+func (l *LanguageService) Converters() *Converters {
+	return l.converters
+}
+
 // FS implements compiler.CompilerHost.
 func (l *LanguageService) FS() vfs.FS {
 	return l.host.FS()
@@ -57,6 +95,12 @@ func (l *LanguageService) GetProgram() *compiler.Program {
 	return l.host.GetProgram()
 }
 
+// GetTypeChecker returns the checker of the program that the language service uses for its
+// request, which no other request uses at the same time.
+func (l *LanguageService) GetTypeChecker() *checker.Checker {
+	return l.host.GetTypeChecker()
+}
+
 func (l *LanguageService) tryGetProgramAndFile(fileName string) (*compiler.Program, *ast.SourceFile) {
 	program := l.GetProgram()
 	file := program.GetSourceFile(fileName)
@@ -0,0 +1,119 @@
+package ls_test
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/microsoft/typescript-go/internal/bundled"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/ls"
+	"github.com/microsoft/typescript-go/internal/project"
+	"github.com/microsoft/typescript-go/internal/testutil/projecttestutil"
+	"gotest.tools/v3/assert"
+)
+
+// markerPattern matches the /*name*/ comments that mark positions in the files of a test project.
+var markerPattern = regexp.MustCompile(`/\*([\w$]+)\*/`)
+
+type marker struct {
+	fileName string
+	position int
+}
+
+// testProject is a project service over files in which /*name*/ comments mark the positions that
+// a test asks about. The comments are removed from the files before they are loaded.
+type testProject struct {
+	t       *testing.T
+	service *project.Service
+	files   map[string]string
+	markers map[string]marker
+}
+
+func newTestProject(t *testing.T, files map[string]string) *testProject {
+	t.Helper()
+	if !bundled.Embedded {
+		// Without embedding, we'd need to read all of the lib files out from disk into the MapFS.
+		// Just skip this for now.
+		t.Skip("bundled files are not embedded")
+	}
+	p := &testProject{
+		t:       t,
+		files:   make(map[string]string, len(files)),
+		markers: make(map[string]marker),
+	}
+	for fileName, text := range files {
+		for {
+			match := markerPattern.FindStringSubmatchIndex(text)
+			if match == nil {
+				break
+			}
+			name := text[match[2]:match[3]]
+			_, exists := p.markers[name]
+			assert.Assert(t, !exists, "duplicate marker %s", name)
+			p.markers[name] = marker{fileName: fileName, position: match[0]}
+			text = text[:match[0]] + text[match[1]:]
+		}
+		p.files[fileName] = text
+	}
+	p.service, _ = projecttestutil.Setup(p.files)
+	return p
+}
+
+// languageService opens the file and returns the language service of its default project.
+func (p *testProject) languageService(fileName string) *ls.LanguageService {
+	p.t.Helper()
+	text, ok := p.files[fileName]
+	assert.Assert(p.t, ok, "no file %s", fileName)
+	p.service.OpenFile(fileName, text, core.GetScriptKindFromFileName(fileName), "")
+	_, proj := p.service.EnsureDefaultProjectForFile(fileName)
+	return proj.LanguageService()
+}
+
+// marker returns the file and position of the /*name*/ marker.
+func (p *testProject) marker(name string) marker {
+	p.t.Helper()
+	m, ok := p.markers[name]
+	assert.Assert(p.t, ok, "no marker %s", name)
+	return m
+}
+
+// languageServiceAt opens the file of the marker and returns the language service of its default
+// project along with the marker.
+func (p *testProject) languageServiceAt(name string) (*ls.LanguageService, marker) {
+	p.t.Helper()
+	m := p.marker(name)
+	return p.languageService(m.fileName), m
+}
+
+// text returns the text of the file in the range.
+func (p *testProject) text(fileName string, textRange core.TextRange) string {
+	return p.files[fileName][textRange.Pos():textRange.End()]
+}
+
+// markerAt returns the name of the marker at the start of the location, or the location itself
+// when no marker is there, so that tests can compare locations by the markers placed at them.
+func (p *testProject) markerAt(location ls.Location) string {
+	for name, m := range p.markers {
+		if m.fileName == location.FileName && m.position == location.Range.Pos() {
+			return name
+		}
+	}
+	return fmt.Sprintf("%s(%d)", location.FileName, location.Range.Pos())
+}
+
+// markersAt returns the names of the markers at the start of the locations.
+func (p *testProject) markersAt(locations []ls.Location) []string {
+	return core.Map(locations, p.markerAt)
+}
+
+// applyChanges applies changes computed against text, which do not overlap, to it.
+func applyChanges(text string, changes []ls.TextChange) string {
+	changes = slices.Clone(changes)
+	slices.SortFunc(changes, func(a, b ls.TextChange) int { return b.Pos() - a.Pos() })
+	for _, change := range changes {
+		text = change.ApplyTo(text)
+	}
+	return text
+}
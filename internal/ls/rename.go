@@ -0,0 +1,261 @@
+package ls
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/astnav"
+	"github.com/microsoft/typescript-go/internal/checker"
+	"github.com/microsoft/typescript-go/internal/compiler"
+	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/scanner"
+	"github.com/microsoft/typescript-go/internal/tspath"
+)
+
+type RenameInfo struct {
+	// DisplayName is the current name of the symbol.
+	DisplayName string
+	// TriggerSpan is the range of the name that rename was invoked on.
+	TriggerSpan core.TextRange
+}
+
+type RenameOptions struct {
+	// AllowRenameFile allows renaming the file that declares a default export along with the
+	// export, when the file is named after it.
+	AllowRenameFile bool
+}
+
+type FileRename struct {
+	OldFileName string
+	NewFileName string
+}
+
+type RenameResult struct {
+	// Changes are the edits to make, by file name.
+	Changes    map[string][]TextChange
+	FileRename *FileRename
+}
+
+// PrepareRename checks whether the symbol at position can be renamed. If it cannot, the returned
+// message explains why.
+func (l *LanguageService) PrepareRename(fileName string, position int) (*RenameInfo, *diagnostics.Message) {
+	program, file := l.getProgramAndFile(fileName)
+	target, message := getRenameTarget(program, l.GetTypeChecker(), file, position)
+	if message != nil {
+		return nil, message
+	}
+	return &RenameInfo{
+		DisplayName: target.name,
+		TriggerSpan: target.span,
+	}, nil
+}
+
+func (l *LanguageService) ProvideRename(fileName string, position int, newName string, options RenameOptions) (*RenameResult, *diagnostics.Message) {
+	program, file := l.getProgramAndFile(fileName)
+	target, message := getRenameTarget(program, l.GetTypeChecker(), file, position)
+	if message != nil {
+		return nil, message
+	}
+
+	c := l.GetTypeChecker()
+	var entries []ReferenceEntry
+	var search *referenceSearch
+	if target.isLocalAlias {
+		// Renaming the local name of an import only affects the importing file.
+		search = newReferenceSearch(c, target.node, target.symbol, nil /*searchSymbols*/)
+		for _, decl := range target.symbol.Declarations {
+			search.declarations[decl] = struct{}{}
+		}
+		entries = search.findReferencesInFile(file)
+	} else {
+		search = newReferenceSearch(c, target.node, target.symbol, []*ast.Symbol{target.symbol})
+		entries = search.findReferences(program.SourceFiles())
+	}
+
+	result := &RenameResult{Changes: make(map[string][]TextChange)}
+	for _, entry := range entries {
+		entryFile := ast.GetSourceFileOfNode(entry.node)
+		// Aliases that import or export the symbol under another name keep that name.
+		if entryFile.Text()[entry.Range.Pos():entry.Range.End()] != target.name {
+			continue
+		}
+		result.Changes[entry.FileName] = append(result.Changes[entry.FileName], TextChange{
+			TextRange: entry.Range,
+			NewText:   getRenameText(c, search, entry.node, target, newName),
+		})
+	}
+
+	if options.AllowRenameFile {
+		if declFile := getRenamedDefaultExportFile(target); declFile != nil {
+			result.FileRename = &FileRename{
+				OldFileName: declFile.FileName(),
+				NewFileName: tspath.CombinePaths(tspath.GetDirectoryPath(declFile.FileName()), newName+tspath.TryGetExtensionFromPath(declFile.FileName())),
+			}
+			addModuleSpecifierRenames(program, l.GetTypeChecker(), declFile, target.name, newName, result.Changes)
+		}
+	}
+	return result, nil
+}
+
+type renameTarget struct {
+	node   *ast.Node
+	symbol *ast.Symbol
+	name   string
+	span   core.TextRange
+	// isLocalAlias is set when renaming the local name of an import, which leaves the
+	// imported symbol alone.
+	isLocalAlias bool
+}
+
+func getRenameTarget(program *compiler.Program, c *checker.Checker, file *ast.SourceFile, position int) (*renameTarget, *diagnostics.Message) {
+	node := astnav.GetTouchingPropertyName(file, position)
+	name := getReferenceNameText(node)
+	if name == "" || ast.IsStringLiteralLike(node) && !isPropertyNameLiteral(node) {
+		return nil, diagnostics.You_cannot_rename_this_element
+	}
+
+	symbol := c.GetSymbolAtLocation(node)
+	if symbol == nil || len(symbol.Declarations) == 0 {
+		return nil, diagnostics.You_cannot_rename_this_element
+	}
+
+	isLocalAlias := isLocalImportAlias(symbol)
+	if !isLocalAlias {
+		target := skipAlias(c, symbol)
+		for _, decl := range target.Declarations {
+			declFile := ast.GetSourceFileOfNode(decl)
+			if program.IsSourceFileDefaultLibrary(declFile) {
+				return nil, diagnostics.You_cannot_rename_elements_that_are_defined_in_the_standard_TypeScript_library
+			}
+			if isInNodeModules(declFile.FileName()) {
+				return nil, diagnostics.You_cannot_rename_elements_that_are_defined_in_a_node_modules_folder
+			}
+		}
+	}
+
+	start := scanner.GetTokenPosOfNode(node, file, false /*includeJSDoc*/)
+	if ast.IsStringLiteralLike(node) {
+		start++
+	}
+	return &renameTarget{
+		node:         node,
+		symbol:       symbol,
+		name:         name,
+		span:         core.NewTextRange(start, start+len(name)),
+		isLocalAlias: isLocalAlias,
+	}, nil
+}
+
+// isPropertyNameLiteral reports whether a string literal names a property, as in `{ "x": 1 }` or `o["x"]`.
+func isPropertyNameLiteral(node *ast.Node) bool {
+	parent := node.Parent
+	switch {
+	case parent.Name() == node:
+		return ast.IsPropertyAssignment(parent) || ast.IsPropertyDeclaration(parent) || ast.IsPropertySignatureDeclaration(parent) ||
+			ast.IsMethodDeclaration(parent) || ast.IsMethodSignatureDeclaration(parent) || ast.IsEnumMember(parent)
+	case ast.IsElementAccessExpression(parent):
+		return parent.AsElementAccessExpression().ArgumentExpression == node
+	case ast.IsLiteralTypeNode(parent):
+		return parent.Parent != nil && ast.IsIndexedAccessTypeNode(parent.Parent)
+	}
+	return false
+}
+
+func isLocalImportAlias(symbol *ast.Symbol) bool {
+	if symbol.Flags&ast.SymbolFlagsAlias == 0 || len(symbol.Declarations) == 0 {
+		return false
+	}
+	switch symbol.Declarations[0].Kind {
+	case ast.KindImportSpecifier, ast.KindImportClause, ast.KindNamespaceImport, ast.KindImportEqualsDeclaration:
+		return true
+	}
+	return false
+}
+
+func isInNodeModules(fileName string) bool {
+	return strings.Contains(fileName, "/node_modules/")
+}
+
+// getRenameText returns the text that replaces a reference. A name that is shared by a property
+// and a value, as in `{ x }` and `const { x } = o`, is expanded so that the other one keeps its name.
+// Likewise `import { x }` becomes `import { x as y }` when only the local name is renamed.
+func getRenameText(c *checker.Checker, search *referenceSearch, node *ast.Node, target *renameTarget, newName string) string {
+	parent := node.Parent
+	switch {
+	case target.isLocalAlias && ast.IsImportSpecifier(parent) && parent.Name() == node && parent.PropertyName() == nil:
+		return target.name + " as " + newName
+	case ast.IsShorthandPropertyAssignment(parent):
+		valueSymbol := c.GetShorthandAssignmentValueSymbol(parent)
+		return expandShorthandName(search, valueSymbol, c.GetSymbolAtLocation(node), getContextualPropertySymbols(c, node), target.name, newName)
+	case ast.IsBindingElement(parent) && parent.Name() == node && parent.PropertyName() == nil && ast.IsObjectBindingPattern(parent.Parent):
+		return expandShorthandName(search, c.GetSymbolAtLocation(node), nil, getContextualPropertySymbols(c, node), target.name, newName)
+	}
+	return newName
+}
+
+func expandShorthandName(search *referenceSearch, valueSymbol *ast.Symbol, propertySymbol *ast.Symbol, contextualProperties []*ast.Symbol, name string, newName string) string {
+	renamesValue := valueSymbol != nil && search.isRelatedSymbol(valueSymbol)
+	renamesProperty := propertySymbol != nil && search.isRelatedSymbol(propertySymbol) || slices.ContainsFunc(contextualProperties, search.isRelatedSymbol)
+	switch {
+	case renamesValue && !renamesProperty:
+		// The property keeps its name and is given the renamed value, or bound to the renamed variable.
+		return name + ": " + newName
+	case renamesProperty && !renamesValue:
+		return newName + ": " + name
+	}
+	return newName
+}
+
+// getRenamedDefaultExportFile returns the file that declares the renamed symbol as its default
+// export, if the file is named after the symbol.
+func getRenamedDefaultExportFile(target *renameTarget) *ast.SourceFile {
+	if target.isLocalAlias {
+		return nil
+	}
+	for _, decl := range target.symbol.Declarations {
+		file := ast.GetSourceFileOfNode(decl)
+		if !ast.HasSyntacticModifier(decl, ast.ModifierFlagsExportDefault) && !isExportedAsDefault(file, target.name) {
+			continue
+		}
+		if tspath.RemoveFileExtension(tspath.GetBaseFileName(file.FileName())) == target.name {
+			return file
+		}
+	}
+	return nil
+}
+
+// isExportedAsDefault reports whether a file has an `export default name` statement.
+func isExportedAsDefault(file *ast.SourceFile, name string) bool {
+	return slices.ContainsFunc(file.Statements.Nodes, func(statement *ast.Node) bool {
+		if !ast.IsExportAssignment(statement) || statement.AsExportAssignment().IsExportEquals {
+			return false
+		}
+		expression := statement.AsExportAssignment().Expression
+		return ast.IsIdentifier(expression) && expression.Text() == name
+	})
+}
+
+// addModuleSpecifierRenames updates the module specifiers that import a renamed file by name.
+func addModuleSpecifierRenames(program *compiler.Program, c *checker.Checker, renamedFile *ast.SourceFile, oldName string, newName string, changes map[string][]TextChange) {
+	for _, file := range program.SourceFiles() {
+		for _, specifier := range file.Imports {
+			symbol := c.GetSymbolAtLocation(specifier)
+			if symbol == nil || symbol.ValueDeclaration != renamedFile.AsNode() {
+				continue
+			}
+			text := specifier.Text()
+			directory, base := text[:strings.LastIndexByte(text, '/')+1], text[strings.LastIndexByte(text, '/')+1:]
+			extension := tspath.TryGetExtensionFromPath(base)
+			if strings.TrimSuffix(base, extension) != oldName {
+				continue
+			}
+			start := scanner.GetTokenPosOfNode(specifier, file, false /*includeJSDoc*/) + 1
+			changes[file.FileName()] = append(changes[file.FileName()], TextChange{
+				TextRange: core.NewTextRange(start+len(directory), start+len(text)),
+				NewText:   newName + extension,
+			})
+		}
+	}
+}
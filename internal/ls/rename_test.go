@@ -0,0 +1,258 @@
+package ls_test
+
+import (
+	"testing"
+
+	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
+	"github.com/microsoft/typescript-go/internal/ls"
+	"gotest.tools/v3/assert"
+)
+
+func TestRename(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		title   string
+		files   map[string]string
+		marker  string
+		newName string
+		options ls.RenameOptions
+		// The text of each changed file after the rename
+		expected map[string]string
+		// The file rename that is expected, as old and new file name
+		expectedFileRename *ls.FileRename
+	}{
+		{
+			title: "local variable",
+			files: map[string]string{
+				"/home/src/project/index.ts": `let /*1*/count = 0;
+count++;`,
+			},
+			marker:  "1",
+			newName: "total",
+			expected: map[string]string{
+				"/home/src/project/index.ts": `let total = 0;
+total++;`,
+			},
+		},
+		{
+			title: "variable of a shorthand property",
+			files: map[string]string{
+				"/home/src/project/index.ts": `const /*1*/x = 1;
+const point = { x };
+point.x;`,
+			},
+			marker:  "1",
+			newName: "y",
+			expected: map[string]string{
+				"/home/src/project/index.ts": `const y = 1;
+const point = { x: y };
+point.x;`,
+			},
+		},
+		{
+			title: "property of a shorthand property",
+			files: map[string]string{
+				"/home/src/project/index.ts": `const x = 1;
+const point = { x };
+point./*1*/x;`,
+			},
+			marker:  "1",
+			newName: "y",
+			expected: map[string]string{
+				"/home/src/project/index.ts": `const x = 1;
+const point = { y: x };
+point.y;`,
+			},
+		},
+		{
+			title: "property of a destructuring",
+			files: map[string]string{
+				"/home/src/project/index.ts": `interface Options { /*1*/verbose: boolean; }
+function run({ verbose }: Options) { return verbose; }`,
+			},
+			marker:  "1",
+			newName: "debug",
+			expected: map[string]string{
+				"/home/src/project/index.ts": `interface Options { debug: boolean; }
+function run({ debug: verbose }: Options) { return verbose; }`,
+			},
+		},
+		{
+			title: "local name of an import",
+			files: map[string]string{
+				"/home/src/project/tsconfig.json": `{}`,
+				"/home/src/project/a.ts":          `export const value = 1;`,
+				"/home/src/project/b.ts": `import { /*1*/value } from "./a";
+console.log(value);`,
+			},
+			marker:  "1",
+			newName: "renamed",
+			expected: map[string]string{
+				"/home/src/project/b.ts": `import { value as renamed } from "./a";
+console.log(renamed);`,
+			},
+		},
+		{
+			title: "local name of an import from a node_modules package",
+			files: map[string]string{
+				"/home/src/project/tsconfig.json": `{ "compilerOptions": { "module": "esnext", "moduleResolution": "bundler" } }`,
+				"/home/src/project/index.ts": `import { chunk } from "lodash";
+/*1*/chunk();`,
+				"/home/src/project/node_modules/lodash/package.json": `{ "name": "lodash", "types": "index.d.ts" }`,
+				"/home/src/project/node_modules/lodash/index.d.ts":   `export declare function chunk(): void;`,
+			},
+			marker:  "1",
+			newName: "split",
+			expected: map[string]string{
+				"/home/src/project/index.ts": `import { chunk as split } from "lodash";
+split();`,
+			},
+		},
+		{
+			title: "export that is imported under another name",
+			files: map[string]string{
+				"/home/src/project/tsconfig.json": `{}`,
+				"/home/src/project/a.ts":          `export const /*1*/value = 1;`,
+				"/home/src/project/b.ts": `import { value as renamed } from "./a";
+console.log(renamed);`,
+				"/home/src/project/c.ts": `import { value } from "./a";
+console.log(value);`,
+			},
+			marker:  "1",
+			newName: "total",
+			expected: map[string]string{
+				"/home/src/project/a.ts": `export const total = 1;`,
+				"/home/src/project/b.ts": `import { total as renamed } from "./a";
+console.log(renamed);`,
+				"/home/src/project/c.ts": `import { total } from "./a";
+console.log(total);`,
+			},
+		},
+		{
+			title: "default export named after its file",
+			files: map[string]string{
+				"/home/src/project/tsconfig.json": `{}`,
+				"/home/src/project/src/Button.ts": `export default function /*1*/Button() {}`,
+				"/home/src/project/src/index.ts": `import Button from "./Button";
+Button();`,
+				"/home/src/project/src/nested/a.ts": `export { default } from "../Button.js";`,
+			},
+			marker:  "1",
+			newName: "Widget",
+			options: ls.RenameOptions{AllowRenameFile: true},
+			expected: map[string]string{
+				"/home/src/project/src/Button.ts": `export default function Widget() {}`,
+				"/home/src/project/src/index.ts": `import Widget from "./Widget";
+Widget();`,
+				"/home/src/project/src/nested/a.ts": `export { default } from "../Widget.js";`,
+			},
+			expectedFileRename: &ls.FileRename{
+				OldFileName: "/home/src/project/src/Button.ts",
+				NewFileName: "/home/src/project/src/Widget.ts",
+			},
+		},
+		{
+			title: "default export when file renames are not allowed",
+			files: map[string]string{
+				"/home/src/project/tsconfig.json": `{}`,
+				"/home/src/project/src/Button.ts": `export default function /*1*/Button() {}`,
+				"/home/src/project/src/index.ts": `import Button from "./Button";
+Button();`,
+			},
+			marker:  "1",
+			newName: "Widget",
+			expected: map[string]string{
+				"/home/src/project/src/Button.ts": `export default function Widget() {}`,
+				"/home/src/project/src/index.ts": `import Widget from "./Button";
+Widget();`,
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.title, func(t *testing.T) {
+			t.Parallel()
+			p := newTestProject(t, testCase.files)
+			l, m := p.languageServiceAt(testCase.marker)
+			result, message := l.ProvideRename(m.fileName, m.position, testCase.newName, testCase.options)
+			assert.Assert(t, message == nil, "cannot rename: %v", message)
+			actual := make(map[string]string, len(result.Changes))
+			for fileName, changes := range result.Changes {
+				actual[fileName] = applyChanges(p.files[fileName], changes)
+			}
+			assert.DeepEqual(t, actual, testCase.expected)
+			assert.DeepEqual(t, result.FileRename, testCase.expectedFileRename)
+		})
+	}
+
+	t.Run("prepare rename", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/index.ts": `const point = { "x-coordinate": 1 };
+point[/*1*/"x-coordinate"];`,
+		})
+		l, m := p.languageServiceAt("1")
+		info, message := l.PrepareRename(m.fileName, m.position)
+		assert.Assert(t, message == nil)
+		assert.Equal(t, info.DisplayName, "x-coordinate")
+		// The quotes are not part of the name
+		assert.Equal(t, p.text(m.fileName, info.TriggerSpan), "x-coordinate")
+	})
+
+	refusals := []struct {
+		title    string
+		files    map[string]string
+		expected *diagnostics.Message
+	}{
+		{
+			title: "element of the standard library",
+			files: map[string]string{
+				"/home/src/project/index.ts": `const values = [1, 2];
+values./*1*/push(3);`,
+			},
+			expected: diagnostics.You_cannot_rename_elements_that_are_defined_in_the_standard_TypeScript_library,
+		},
+		{
+			title: "element of a node_modules package",
+			files: map[string]string{
+				"/home/src/project/tsconfig.json": `{ "compilerOptions": { "module": "esnext", "moduleResolution": "bundler" } }`,
+				"/home/src/project/index.ts": `import { chunk } from "lodash";
+chunk([1, 2])./*1*/length;`,
+				"/home/src/project/node_modules/lodash/package.json": `{ "name": "lodash", "types": "index.d.ts" }`,
+				"/home/src/project/node_modules/lodash/index.d.ts":   `export declare function chunk(values: number[]): { length: number };`,
+			},
+			expected: diagnostics.You_cannot_rename_elements_that_are_defined_in_a_node_modules_folder,
+		},
+		{
+			title: "import of an element of a node_modules package",
+			files: map[string]string{
+				"/home/src/project/tsconfig.json":                    `{ "compilerOptions": { "module": "esnext", "moduleResolution": "bundler" } }`,
+				"/home/src/project/index.ts":                         `export { /*1*/chunk } from "lodash";`,
+				"/home/src/project/node_modules/lodash/package.json": `{ "name": "lodash", "types": "index.d.ts" }`,
+				"/home/src/project/node_modules/lodash/index.d.ts":   `export declare function chunk(): void;`,
+			},
+			expected: diagnostics.You_cannot_rename_elements_that_are_defined_in_a_node_modules_folder,
+		},
+		{
+			title: "keyword",
+			files: map[string]string{
+				"/home/src/project/index.ts": `/*1*/const x = 1;`,
+			},
+			expected: diagnostics.You_cannot_rename_this_element,
+		},
+	}
+
+	for _, testCase := range refusals {
+		t.Run("refuse "+testCase.title, func(t *testing.T) {
+			t.Parallel()
+			p := newTestProject(t, testCase.files)
+			l, m := p.languageServiceAt("1")
+			_, message := l.PrepareRename(m.fileName, m.position)
+			assert.Equal(t, message, testCase.expected)
+			result, message := l.ProvideRename(m.fileName, m.position, "renamed", ls.RenameOptions{})
+			assert.Assert(t, result == nil)
+			assert.Equal(t, message, testCase.expected)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package ls
+
+import (
+	"slices"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/astnav"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"github.com/microsoft/typescript-go/internal/scanner"
+)
+
+// ProvideSelectionRanges returns, for each position, the ranges that a selection at the position
+// expands to, from the token at the position out to the whole file. Like folding, it only looks
+// at the syntax of the file.
+func (l *LanguageService) ProvideSelectionRanges(fileName string, positions []int) []lsproto.SelectionRange {
+	_, file := l.getProgramAndFile(fileName)
+	result := make([]lsproto.SelectionRange, 0, len(positions))
+	for _, position := range positions {
+		var selectionRange *lsproto.SelectionRange
+		for _, span := range slices.Backward(getSelectionSpans(file, position)) {
+			lspRange, err := l.converters.ToLSPRange(file.FileName(), span)
+			if err != nil {
+				continue
+			}
+			selectionRange = &lsproto.SelectionRange{Range: lspRange, Parent: selectionRange}
+		}
+		if selectionRange == nil {
+			// Every position has to have a range, so an empty one stands for no selection.
+			lspRange, err := l.converters.ToLSPRange(file.FileName(), core.NewTextRange(position, position))
+			if err != nil {
+				continue
+			}
+			selectionRange = &lsproto.SelectionRange{Range: lspRange}
+		}
+		result = append(result, *selectionRange)
+	}
+	return result
+}
+
+// getSelectionSpans returns the spans around position from the innermost out, each containing
+// the one before it. They are the spans of the token at position and its ancestors, with the
+// contents of string literals and of the lists that nodes are elements of in between.
+func getSelectionSpans(file *ast.SourceFile, position int) []core.TextRange {
+	var spans []core.TextRange
+	add := func(pos int, end int) {
+		if pos > position || end < position {
+			return
+		}
+		if len(spans) != 0 {
+			last := spans[len(spans)-1]
+			if last.Pos() == pos && last.End() == end || pos > last.Pos() || end < last.End() {
+				return
+			}
+		}
+		spans = append(spans, core.NewTextRange(pos, end))
+	}
+
+	for node := astnav.GetTokenAtPosition(file, position); node != nil; node = node.Parent {
+		if ast.IsSourceFile(node) {
+			add(0, node.End())
+			break
+		}
+		start := scanner.GetTokenPosOfNode(node, file, false /*includeJSDoc*/)
+		if ast.IsStringLiteralLike(node) {
+			add(start+1, max(node.End()-1, start+1))
+		}
+		add(start, node.End())
+		if list := getContainingList(node); list != nil && len(list.Nodes) > 1 {
+			add(scanner.GetTokenPosOfNode(list.Nodes[0], file, false /*includeJSDoc*/), list.Nodes[len(list.Nodes)-1].End())
+		}
+	}
+	return spans
+}
+
+// getContainingList returns the list of the children of the parent of node that node is in, such
+// as the statements of a block or the arguments of a call, or nil if it is not in a list.
+func getContainingList(node *ast.Node) *ast.NodeList {
+	if node.Parent == nil {
+		return nil
+	}
+	var result *ast.NodeList
+	visitor := ast.NewNodeVisitor(core.Identity, nil, ast.NodeVisitorHooks{
+		VisitNodes: func(list *ast.NodeList, v *ast.NodeVisitor) *ast.NodeList {
+			if list != nil && slices.Contains(list.Nodes, node) {
+				result = list
+			}
+			return list
+		},
+	})
+	node.Parent.VisitEachChild(visitor)
+	return result
+}
@@ -0,0 +1,65 @@
+package ls_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"gotest.tools/v3/assert"
+)
+
+// selectionRangeTexts returns the texts of a selection range and its parents, from the innermost
+// out.
+func selectionRangeTexts(text string, selectionRange *lsproto.SelectionRange) []string {
+	lines := strings.SplitAfter(text, "\n")
+	offset := func(position lsproto.Position) int {
+		result := int(position.Character)
+		for _, line := range lines[:position.Line] {
+			result += len(line)
+		}
+		return result
+	}
+	var result []string
+	for ; selectionRange != nil; selectionRange = selectionRange.Parent {
+		result = append(result, text[offset(selectionRange.Range.Start):offset(selectionRange.Range.End)])
+	}
+	return result
+}
+
+func TestSelectionRanges(t *testing.T) {
+	t.Parallel()
+
+	p := newTestProject(t, map[string]string{
+		"/home/src/project/index.ts": `function f(a: number, b: string) {
+    const message = "hello /*1*/world";
+    return g(a, /*2*/b);
+}`,
+	})
+	l, m := p.languageServiceAt("1")
+	text := p.files[m.fileName]
+	ranges := l.ProvideSelectionRanges(m.fileName, []int{m.position, p.marker("2").position})
+	assert.Equal(t, len(ranges), 2)
+
+	// String literals select their contents before their quotes
+	assert.DeepEqual(t, selectionRangeTexts(text, &ranges[0]), []string{
+		`hello world`,
+		`"hello world"`,
+		`message = "hello world"`,
+		`const message = "hello world"`,
+		`const message = "hello world";`,
+		"const message = \"hello world\";\n    return g(a, b);",
+		"{\n    const message = \"hello world\";\n    return g(a, b);\n}",
+		text,
+	})
+
+	// Lists of arguments and parameters are selected before the nodes that contain them
+	assert.DeepEqual(t, selectionRangeTexts(text, &ranges[1]), []string{
+		`b`,
+		`a, b`,
+		`g(a, b)`,
+		`return g(a, b);`,
+		"const message = \"hello world\";\n    return g(a, b);",
+		"{\n    const message = \"hello world\";\n    return g(a, b);\n}",
+		text,
+	})
+}
@@ -0,0 +1,337 @@
+package ls
+
+import (
+	"slices"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/checker"
+	"github.com/microsoft/typescript-go/internal/compiler"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"github.com/microsoft/typescript-go/internal/scanner"
+)
+
+type semanticTokenType uint32
+
+// The order of the token types and modifiers is the legend that the server announces; the
+// encoded tokens refer to them by index.
+const (
+	semanticTokenTypeClass semanticTokenType = iota
+	semanticTokenTypeEnum
+	semanticTokenTypeInterface
+	semanticTokenTypeNamespace
+	semanticTokenTypeTypeParameter
+	semanticTokenTypeType
+	semanticTokenTypeParameter
+	semanticTokenTypeVariable
+	semanticTokenTypeEnumMember
+	semanticTokenTypeProperty
+	semanticTokenTypeFunction
+	semanticTokenTypeMethod
+)
+
+type semanticTokenModifier uint32
+
+const (
+	semanticTokenModifierDeclaration semanticTokenModifier = 1 << iota
+	semanticTokenModifierStatic
+	semanticTokenModifierAsync
+	semanticTokenModifierReadonly
+	semanticTokenModifierDefaultLibrary
+	semanticTokenModifierLocal
+)
+
+var SemanticTokensLegend = lsproto.SemanticTokensLegend{
+	TokenTypes: []string{
+		string(lsproto.SemanticTokenTypesclass),
+		string(lsproto.SemanticTokenTypesenum),
+		string(lsproto.SemanticTokenTypesinterface),
+		string(lsproto.SemanticTokenTypesnamespace),
+		string(lsproto.SemanticTokenTypestypeParameter),
+		string(lsproto.SemanticTokenTypestype),
+		string(lsproto.SemanticTokenTypesparameter),
+		string(lsproto.SemanticTokenTypesvariable),
+		string(lsproto.SemanticTokenTypesenumMember),
+		string(lsproto.SemanticTokenTypesproperty),
+		string(lsproto.SemanticTokenTypesfunction),
+		string(lsproto.SemanticTokenTypesmethod),
+	},
+	TokenModifiers: []string{
+		string(lsproto.SemanticTokenModifiersdeclaration),
+		string(lsproto.SemanticTokenModifiersstatic),
+		string(lsproto.SemanticTokenModifiersasync),
+		string(lsproto.SemanticTokenModifiersreadonly),
+		string(lsproto.SemanticTokenModifiersdefaultLibrary),
+		"local",
+	},
+}
+
+var semanticTokenTypeOfDeclaration = map[ast.Kind]semanticTokenType{
+	ast.KindVariableDeclaration:         semanticTokenTypeVariable,
+	ast.KindParameter:                   semanticTokenTypeParameter,
+	ast.KindPropertyDeclaration:         semanticTokenTypeProperty,
+	ast.KindModuleDeclaration:           semanticTokenTypeNamespace,
+	ast.KindEnumDeclaration:             semanticTokenTypeEnum,
+	ast.KindEnumMember:                  semanticTokenTypeEnumMember,
+	ast.KindClassDeclaration:            semanticTokenTypeClass,
+	ast.KindMethodDeclaration:           semanticTokenTypeMethod,
+	ast.KindFunctionDeclaration:         semanticTokenTypeFunction,
+	ast.KindFunctionExpression:          semanticTokenTypeFunction,
+	ast.KindMethodSignature:             semanticTokenTypeMethod,
+	ast.KindGetAccessor:                 semanticTokenTypeProperty,
+	ast.KindSetAccessor:                 semanticTokenTypeProperty,
+	ast.KindPropertySignature:           semanticTokenTypeProperty,
+	ast.KindInterfaceDeclaration:        semanticTokenTypeInterface,
+	ast.KindTypeAliasDeclaration:        semanticTokenTypeType,
+	ast.KindTypeParameter:               semanticTokenTypeTypeParameter,
+	ast.KindPropertyAssignment:          semanticTokenTypeProperty,
+	ast.KindShorthandPropertyAssignment: semanticTokenTypeProperty,
+}
+
+type semanticToken struct {
+	node      *ast.Node
+	tokenType semanticTokenType
+	modifiers semanticTokenModifier
+}
+
+// ProvideSemanticTokens returns the semantic tokens of the identifiers within span in the
+// relative encoding of the LSP, ready to be sent as the data of a SemanticTokens result.
+func (l *LanguageService) ProvideSemanticTokens(fileName string, span core.TextRange) []uint32 {
+	program, file := l.getProgramAndFile(fileName)
+	tokens := getSemanticTokens(program, l.GetTypeChecker(), file, span)
+	return l.encodeSemanticTokens(file, tokens)
+}
+
+func getSemanticTokens(program *compiler.Program, c *checker.Checker, file *ast.SourceFile, span core.TextRange) []semanticToken {
+	var tokens []semanticToken
+	inJsxElement := false
+
+	var visit func(node *ast.Node) bool
+	visit = func(node *ast.Node) bool {
+		if node == nil || node.Pos() == node.End() || node.Pos() >= span.End() || node.End() <= span.Pos() {
+			return false
+		}
+		wasInJsxElement := inJsxElement
+		if ast.IsJsxElement(node) || ast.IsJsxSelfClosingElement(node) {
+			inJsxElement = true
+		}
+		if ast.IsJsxExpression(node) {
+			inJsxElement = false
+		}
+		if ast.IsIdentifier(node) && !inJsxElement && !isInImportClause(node) && !isInfinityOrNaN(node) {
+			if token, ok := classifyIdentifier(program, c, file, node); ok {
+				tokens = append(tokens, token)
+			}
+		}
+		node.ForEachChild(visit)
+		inJsxElement = wasInJsxElement
+		return false
+	}
+	file.AsNode().ForEachChild(visit)
+	return tokens
+}
+
+func classifyIdentifier(program *compiler.Program, c *checker.Checker, file *ast.SourceFile, node *ast.Node) (semanticToken, bool) {
+	symbol := c.GetSymbolAtLocation(node)
+	if symbol == nil {
+		return semanticToken{}, false
+	}
+	// Imports are classified as what they refer to, so that a type-only import reads as a type.
+	symbol = skipAlias(c, symbol)
+	tokenType, ok := getSemanticTokenTypeOfSymbol(symbol, node)
+	if !ok {
+		return semanticToken{}, false
+	}
+
+	var modifiers semanticTokenModifier
+	if parent := node.Parent; parent != nil && parent.Name() == node {
+		if declarationType, ok := semanticTokenTypeOfDeclaration[parent.Kind]; ast.IsBindingElement(parent) || ok && declarationType == tokenType {
+			modifiers |= semanticTokenModifierDeclaration
+		}
+	}
+	if tokenType == semanticTokenTypeParameter && isRightSideOfPropertyAccessOrQualifiedName(node) {
+		tokenType = semanticTokenTypeProperty
+	}
+	tokenType = reclassifySemanticTokenByType(c, node, tokenType)
+
+	if decl := symbol.ValueDeclaration; decl != nil {
+		modifierFlags := ast.GetCombinedModifierFlags(decl)
+		nodeFlags := ast.GetCombinedNodeFlags(decl)
+		if modifierFlags&ast.ModifierFlagsStatic != 0 {
+			modifiers |= semanticTokenModifierStatic
+		}
+		if modifierFlags&ast.ModifierFlagsAsync != 0 {
+			modifiers |= semanticTokenModifierAsync
+		}
+		if tokenType != semanticTokenTypeClass && tokenType != semanticTokenTypeInterface &&
+			(modifierFlags&ast.ModifierFlagsReadonly != 0 || nodeFlags&ast.NodeFlagsConst != 0 || symbol.Flags&ast.SymbolFlagsEnumMember != 0) {
+			modifiers |= semanticTokenModifierReadonly
+		}
+		if (tokenType == semanticTokenTypeVariable || tokenType == semanticTokenTypeFunction) && isLocalDeclaration(decl, file) {
+			modifiers |= semanticTokenModifierLocal
+		}
+		if program.IsSourceFileDefaultLibrary(ast.GetSourceFileOfNode(decl)) {
+			modifiers |= semanticTokenModifierDefaultLibrary
+		}
+	} else if slices.ContainsFunc(symbol.Declarations, func(decl *ast.Node) bool {
+		return program.IsSourceFileDefaultLibrary(ast.GetSourceFileOfNode(decl))
+	}) {
+		modifiers |= semanticTokenModifierDefaultLibrary
+	}
+
+	return semanticToken{node: node, tokenType: tokenType, modifiers: modifiers}, true
+}
+
+func getSemanticTokenTypeOfSymbol(symbol *ast.Symbol, location *ast.Node) (semanticTokenType, bool) {
+	flags := symbol.Flags
+	switch {
+	case flags&ast.SymbolFlagsClass != 0:
+		return semanticTokenTypeClass, true
+	case flags&ast.SymbolFlagsEnum != 0:
+		return semanticTokenTypeEnum, true
+	case flags&ast.SymbolFlagsTypeAlias != 0:
+		return semanticTokenTypeType, true
+	case flags&ast.SymbolFlagsInterface != 0:
+		// An interface merged with a value is classified as the value where it is used as one.
+		if !ast.IsExpressionNode(location) {
+			return semanticTokenTypeInterface, true
+		}
+	case flags&ast.SymbolFlagsTypeParameter != 0:
+		return semanticTokenTypeTypeParameter, true
+	}
+
+	decl := symbol.ValueDeclaration
+	if decl == nil && len(symbol.Declarations) != 0 {
+		decl = symbol.Declarations[0]
+	}
+	if decl == nil {
+		return 0, false
+	}
+	if ast.IsBindingElement(decl) {
+		decl = ast.GetRootDeclaration(decl)
+	}
+	tokenType, ok := semanticTokenTypeOfDeclaration[decl.Kind]
+	return tokenType, ok
+}
+
+// reclassifySemanticTokenByType classifies variables, properties and parameters that hold
+// classes or functions by what they hold.
+func reclassifySemanticTokenByType(c *checker.Checker, node *ast.Node, tokenType semanticTokenType) semanticTokenType {
+	if tokenType != semanticTokenTypeVariable && tokenType != semanticTokenTypeProperty && tokenType != semanticTokenTypeParameter {
+		return tokenType
+	}
+	t := c.GetTypeAtLocation(node)
+	if t == nil {
+		return tokenType
+	}
+	test := func(condition func(t *checker.Type) bool) bool {
+		return condition(t) || t.Flags()&checker.TypeFlagsUnion != 0 && slices.ContainsFunc(t.Types(), condition)
+	}
+	if tokenType != semanticTokenTypeParameter && test(func(t *checker.Type) bool {
+		return len(c.GetSignaturesOfType(t, checker.SignatureKindConstruct)) != 0
+	}) {
+		return semanticTokenTypeClass
+	}
+	isCallable := test(func(t *checker.Type) bool {
+		return len(c.GetSignaturesOfType(t, checker.SignatureKindCall)) != 0
+	})
+	hasProperties := test(func(t *checker.Type) bool {
+		return len(c.GetPropertiesOfType(t)) != 0
+	})
+	if isCallable && !hasProperties || isExpressionInCallExpression(node) {
+		if tokenType == semanticTokenTypeProperty {
+			return semanticTokenTypeMethod
+		}
+		return semanticTokenTypeFunction
+	}
+	return tokenType
+}
+
+func isLocalDeclaration(decl *ast.Node, file *ast.SourceFile) bool {
+	if ast.IsBindingElement(decl) {
+		decl = ast.GetRootDeclaration(decl)
+	}
+	if ast.GetSourceFileOfNode(decl) != file {
+		return false
+	}
+	switch decl.Kind {
+	case ast.KindVariableDeclaration:
+		return !ast.IsSourceFile(decl.Parent.Parent.Parent) || ast.IsCatchClause(decl.Parent)
+	case ast.KindFunctionDeclaration:
+		return !ast.IsSourceFile(decl.Parent)
+	}
+	return false
+}
+
+func isExpressionInCallExpression(node *ast.Node) bool {
+	for isRightSideOfPropertyAccessOrQualifiedName(node) {
+		node = node.Parent
+	}
+	return ast.IsCallExpression(node.Parent) && node.Parent.Expression() == node
+}
+
+func isRightSideOfPropertyAccessOrQualifiedName(node *ast.Node) bool {
+	parent := node.Parent
+	return ast.IsQualifiedName(parent) && parent.AsQualifiedName().Right == node ||
+		ast.IsPropertyAccessExpression(parent) && parent.Name() == node
+}
+
+func isInImportClause(node *ast.Node) bool {
+	parent := node.Parent
+	return parent != nil && (ast.IsImportClause(parent) || ast.IsImportSpecifier(parent) || ast.IsNamespaceImport(parent))
+}
+
+func isInfinityOrNaN(node *ast.Node) bool {
+	text := node.Text()
+	return text == "Infinity" || text == "NaN"
+}
+
+// encodeSemanticTokens converts tokens, which are in document order, to the LSP encoding of
+// five integers per token whose line and start are relative to the previous token.
+func (l *LanguageService) encodeSemanticTokens(file *ast.SourceFile, tokens []semanticToken) []uint32 {
+	scriptInfo := l.host.GetScriptInfo(file.FileName())
+	data := make([]uint32, 0, len(tokens)*5)
+	var previousLine, previousCharacter uint32
+	for _, token := range tokens {
+		start := scanner.GetTokenPosOfNode(token.node, file, false /*includeJSDoc*/)
+		startPosition := l.converters.PositionToLineAndCharacter(scriptInfo, core.TextPos(start))
+		endPosition := l.converters.PositionToLineAndCharacter(scriptInfo, core.TextPos(token.node.End()))
+		if startPosition.Line != endPosition.Line {
+			// Tokens cannot span lines; identifiers never do.
+			continue
+		}
+		deltaLine := startPosition.Line - previousLine
+		deltaCharacter := startPosition.Character
+		if deltaLine == 0 {
+			deltaCharacter -= previousCharacter
+		}
+		data = append(data, deltaLine, deltaCharacter, endPosition.Character-startPosition.Character, uint32(token.tokenType), uint32(token.modifiers))
+		previousLine, previousCharacter = startPosition.Line, startPosition.Character
+	}
+	return data
+}
+
+// ComputeSemanticTokensEdits returns the edit that turns a previous result into the current one.
+// Edits to a file usually shift the tokens after them, so a single edit spanning everything
+// between the unchanged prefix and the unchanged suffix is as small as it is worth computing.
+func ComputeSemanticTokensEdits(previous []uint32, current []uint32) []lsproto.SemanticTokensEdit {
+	prefix := 0
+	for prefix < len(previous) && prefix < len(current) && previous[prefix] == current[prefix] {
+		prefix++
+	}
+	if prefix == len(previous) && prefix == len(current) {
+		return nil
+	}
+	suffix := 0
+	for suffix < len(previous)-prefix && suffix < len(current)-prefix && previous[len(previous)-1-suffix] == current[len(current)-1-suffix] {
+		suffix++
+	}
+	edit := lsproto.SemanticTokensEdit{
+		Start:       uint32(prefix),
+		DeleteCount: uint32(len(previous) - prefix - suffix),
+	}
+	if inserted := current[prefix : len(current)-suffix]; len(inserted) != 0 {
+		edit.Data = ptrTo(slices.Clone(inserted))
+	}
+	return []lsproto.SemanticTokensEdit{edit}
+}
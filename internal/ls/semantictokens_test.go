@@ -0,0 +1,142 @@
+package ls_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/ls"
+	"gotest.tools/v3/assert"
+)
+
+// decodeSemanticTokens turns the relative encoding of semantic tokens back into one line per
+// token, as in "name: variable.declaration.readonly".
+func decodeSemanticTokens(t *testing.T, text string, data []uint32) []string {
+	t.Helper()
+	assert.Equal(t, len(data)%5, 0)
+	lines := strings.Split(text, "\n")
+	var tokens []string
+	line, character := 0, 0
+	for i := 0; i < len(data); i += 5 {
+		if data[i] != 0 {
+			character = 0
+		}
+		line += int(data[i])
+		character += int(data[i+1])
+		token := lines[line][character:character+int(data[i+2])] + ": " + ls.SemanticTokensLegend.TokenTypes[data[i+3]]
+		for bit, modifier := range ls.SemanticTokensLegend.TokenModifiers {
+			if data[i+4]&(1<<bit) != 0 {
+				token += "." + modifier
+			}
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+func TestSemanticTokens(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		title    string
+		text     string
+		expected []string
+	}{
+		{
+			title: "declarations and references",
+			text: `class Point { static origin = 0; readonly x = 1; move(by: number) {} }
+const point = new Point();
+point.move(Point.origin);`,
+			expected: []string{
+				"Point: class.declaration",
+				"origin: property.declaration.static",
+				"x: property.declaration.readonly",
+				"move: method.declaration",
+				"by: parameter.declaration",
+				"point: variable.declaration.readonly",
+				"Point: class",
+				"point: variable.readonly",
+				"move: method",
+				"Point: class",
+				"origin: property.static",
+			},
+		},
+		{
+			title: "functions, locals and the default library",
+			text: `async function load<T>(value: T) {
+    let result = value;
+    return Math.max(1, 2);
+}`,
+			expected: []string{
+				"load: function.declaration.async",
+				"T: typeParameter.declaration",
+				"value: parameter.declaration",
+				"T: typeParameter",
+				"result: variable.declaration.local",
+				"value: parameter",
+				"Math: variable.defaultLibrary",
+				"max: method.defaultLibrary",
+			},
+		},
+		{
+			title: "types, enums and namespaces",
+			text: `interface Shape {}
+type Id = string;
+enum Color { Red }
+namespace Utils { export const c = Color.Red; }
+let shape: Shape;`,
+			expected: []string{
+				"Shape: interface.declaration",
+				"Id: type.declaration",
+				"Color: enum.declaration",
+				"Red: enumMember.declaration.readonly",
+				"Utils: namespace.declaration",
+				"c: variable.declaration.readonly.local",
+				"Color: enum",
+				"Red: enumMember.readonly",
+				"shape: variable.declaration",
+				"Shape: interface",
+			},
+		},
+		{
+			title: "variables holding functions",
+			text: `const handler = () => {};
+handler();`,
+			expected: []string{
+				"handler: function.declaration.readonly",
+				"handler: function.readonly",
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.title, func(t *testing.T) {
+			t.Parallel()
+			p := newTestProject(t, map[string]string{
+				"/home/src/project/tsconfig.json": `{ "compilerOptions": { "target": "esnext" } }`,
+				"/home/src/project/index.ts":      testCase.text,
+			})
+			l := p.languageService("/home/src/project/index.ts")
+			data := l.ProvideSemanticTokens("/home/src/project/index.ts", core.NewTextRange(0, len(testCase.text)))
+			assert.DeepEqual(t, decodeSemanticTokens(t, testCase.text, data), testCase.expected)
+		})
+	}
+
+	t.Run("range", func(t *testing.T) {
+		t.Parallel()
+		text := `const a = 1;
+const b = a;
+const c = b;`
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/index.ts": text,
+		})
+		l := p.languageService("/home/src/project/index.ts")
+		start := strings.Index(text, "const b")
+		data := l.ProvideSemanticTokens("/home/src/project/index.ts", core.NewTextRange(start, start+len("const b = a;")))
+		// The tokens of the range are encoded relative to the start of the file
+		assert.DeepEqual(t, decodeSemanticTokens(t, text, data), []string{
+			"b: variable.declaration.readonly",
+			"a: variable.readonly",
+		})
+	})
+}
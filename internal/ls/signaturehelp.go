@@ -0,0 +1,393 @@
+package ls
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/astnav"
+	"github.com/microsoft/typescript-go/internal/checker"
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"github.com/microsoft/typescript-go/internal/scanner"
+)
+
+type argumentListKind int
+
+const (
+	// argumentListKindCall is the argument list of a call, `new` expression or tagged template.
+	argumentListKindCall argumentListKind = iota
+	argumentListKindTypeArguments
+	argumentListKindJsxAttributes
+)
+
+type argumentInfo struct {
+	kind argumentListKind
+	// invocation is the call-like expression whose arguments are being written. For a type argument
+	// list that has not been parsed as one yet, as in `f<string,`, it is nil and callee is set.
+	invocation    *ast.Node
+	callee        *ast.Node
+	argumentIndex int
+	argumentCount int
+}
+
+func (l *LanguageService) ProvideSignatureHelp(fileName string, position int, context *lsproto.SignatureHelpContext) *lsproto.SignatureHelp {
+	_, file := l.getProgramAndFile(fileName)
+	info := getContainingArgumentInfo(file, position)
+	if info == nil {
+		return nil
+	}
+
+	c := l.GetTypeChecker()
+	var resolved *checker.Signature
+	var candidates []*checker.Signature
+	if info.kind == argumentListKindTypeArguments {
+		// Resolved signatures are instantiated and no longer have type parameters to show.
+		candidates = getPossibleGenericSignatures(c, info.callee)
+	} else {
+		resolved, candidates = c.GetResolvedSignatureForSignatureHelp(info.invocation, info.argumentCount)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	calleeName := getCalleeName(c, info.callee)
+	signatures := make([]lsproto.SignatureInformation, len(candidates))
+	activeSignature := 0
+	for i, candidate := range candidates {
+		signatures[i] = getSignatureInformation(c, candidate, calleeName, info)
+		if resolved != nil && (candidate == resolved || candidate.Declaration() != nil && candidate.Declaration() == resolved.Declaration()) {
+			activeSignature = i
+		}
+	}
+	// Keep the overload that the user moved to while signature help was showing.
+	if context != nil && context.IsRetrigger && context.ActiveSignatureHelp != nil && context.ActiveSignatureHelp.ActiveSignature != nil {
+		previous := context.ActiveSignatureHelp
+		if int(*previous.ActiveSignature) < len(signatures) && slices.EqualFunc(previous.Signatures, signatures, func(a, b lsproto.SignatureInformation) bool {
+			return a.Label == b.Label
+		}) {
+			activeSignature = int(*previous.ActiveSignature)
+		}
+	}
+
+	return &lsproto.SignatureHelp{
+		Signatures:      signatures,
+		ActiveSignature: ptrTo(uint32(activeSignature)),
+		ActiveParameter: signatures[activeSignature].ActiveParameter,
+	}
+}
+
+func getSignatureInformation(c *checker.Checker, signature *checker.Signature, calleeName string, info *argumentInfo) lsproto.SignatureInformation {
+	parts := c.GetSignatureParts(signature)
+	var label strings.Builder
+	parameters := []lsproto.ParameterInformation{}
+	addParameters := func(open string, params []string, close string, highlight bool) {
+		label.WriteString(open)
+		for i, param := range params {
+			if i > 0 {
+				label.WriteString(", ")
+			}
+			start := utf16Length(label.String())
+			label.WriteString(param)
+			if highlight {
+				parameters = append(parameters, lsproto.ParameterInformation{
+					Label: lsproto.StringOrUintegerPair{
+						UintegerPair: &[2]uint32{uint32(start), uint32(start + utf16Length(param))},
+					},
+				})
+			}
+		}
+		label.WriteString(close)
+	}
+
+	label.WriteString(calleeName)
+	isTypeArgumentList := info.kind == argumentListKindTypeArguments
+	if len(parts.TypeParameters) != 0 || isTypeArgumentList {
+		addParameters("<", parts.TypeParameters, ">", isTypeArgumentList)
+	}
+	addParameters("(", parts.Parameters, "): "+parts.ReturnType, !isTypeArgumentList)
+
+	activeParameter := info.argumentIndex
+	if info.kind == argumentListKindCall && len(parts.Parameters) != 0 && activeParameter >= len(parts.Parameters) &&
+		strings.HasPrefix(parts.Parameters[len(parts.Parameters)-1], "...") {
+		// All further arguments are given to the rest parameter.
+		activeParameter = len(parts.Parameters) - 1
+	}
+
+	parameterNames := getParameterNames(signature)
+	for i := range parameters {
+		if isTypeArgumentList || i >= len(parameterNames) {
+			break
+		}
+		if documentation := getParameterDocumentation(signature.Declaration(), parameterNames[i]); documentation != "" {
+			parameters[i].Documentation = &lsproto.StringOrMarkupContent{
+				MarkupContent: &lsproto.MarkupContent{
+					Kind:  lsproto.MarkupKindMarkdown,
+					Value: documentation,
+				},
+			}
+		}
+	}
+
+	result := lsproto.SignatureInformation{
+		Label:           label.String(),
+		Parameters:      &parameters,
+		ActiveParameter: ptrTo(lsproto.ToNullable(uint32(activeParameter))),
+	}
+	if declaration := signature.Declaration(); declaration != nil {
+		if documentation := getDocumentation([]*ast.Node{declaration}); documentation != "" {
+			result.Documentation = &lsproto.StringOrMarkupContent{
+				MarkupContent: &lsproto.MarkupContent{
+					Kind:  lsproto.MarkupKindMarkdown,
+					Value: documentation,
+				},
+			}
+		}
+	}
+	return result
+}
+
+func getParameterNames(signature *checker.Signature) []string {
+	declaration := signature.Declaration()
+	if declaration == nil || !ast.IsFunctionLike(declaration) {
+		return nil
+	}
+	var names []string
+	for _, parameter := range declaration.Parameters() {
+		if name := parameter.Name(); name != nil && ast.IsIdentifier(name) {
+			names = append(names, name.Text())
+		} else {
+			names = append(names, "")
+		}
+	}
+	return names
+}
+
+// getParameterDocumentation returns the text of the `@param` tag that documents a parameter.
+func getParameterDocumentation(declaration *ast.Node, name string) string {
+	if declaration == nil || name == "" {
+		return ""
+	}
+	for _, jsdoc := range getJSDocHost(declaration).JSDoc(nil) {
+		tags := jsdoc.AsJSDoc().Tags
+		if tags == nil {
+			continue
+		}
+		for _, tag := range tags.Nodes {
+			if tag.Kind == ast.KindJSDocParameterTag && ast.IsIdentifier(tag.Name()) && tag.Name().Text() == name {
+				return getJSDocCommentText(tag.CommentList())
+			}
+		}
+	}
+	return ""
+}
+
+func getCalleeName(c *checker.Checker, callee *ast.Node) string {
+	if ast.IsPropertyAccessExpression(callee) {
+		callee = callee.Name()
+	}
+	if symbol := c.GetSymbolAtLocation(callee); symbol != nil {
+		return c.SymbolToString(symbol)
+	}
+	if ast.IsIdentifier(callee) {
+		return callee.Text()
+	}
+	return ""
+}
+
+// getPossibleGenericSignatures returns the generic call and construct signatures of a callee.
+func getPossibleGenericSignatures(c *checker.Checker, callee *ast.Node) []*checker.Signature {
+	t := c.GetTypeAtLocation(callee)
+	if t == nil {
+		return nil
+	}
+	var result []*checker.Signature
+	for _, kind := range []checker.SignatureKind{checker.SignatureKindCall, checker.SignatureKindConstruct} {
+		for _, signature := range c.GetSignaturesOfType(t, kind) {
+			if len(c.GetSignatureParts(signature).TypeParameters) != 0 {
+				result = append(result, signature)
+			}
+		}
+	}
+	return result
+}
+
+// getContainingArgumentInfo finds the innermost argument list that position is in.
+func getContainingArgumentInfo(file *ast.SourceFile, position int) *argumentInfo {
+	token := astnav.FindPrecedingToken(file, position)
+	if token == nil {
+		return nil
+	}
+	for node := token; node != nil && !ast.IsSourceFile(node) && !ast.IsBlock(node); node = node.Parent {
+		if info := getImmediateArgumentInfo(file, node, position); info != nil {
+			return info
+		}
+	}
+	// An unfinished type argument list, as in `f<string, `, is parsed as comparisons.
+	return getPossibleTypeArgumentsInfo(file, token)
+}
+
+func getImmediateArgumentInfo(file *ast.SourceFile, node *ast.Node, position int) *argumentInfo {
+	text := file.Text()
+	switch node.Kind {
+	case ast.KindCallExpression, ast.KindNewExpression:
+		if list := node.TypeArgumentList(); list != nil && isPositionInList(file, list, position) {
+			index, count := getListArgumentIndexAndCount(file, list, position)
+			return &argumentInfo{kind: argumentListKindTypeArguments, invocation: node, callee: node.Expression(), argumentIndex: index, argumentCount: count}
+		}
+		list := node.ArgumentList()
+		if list == nil || position < list.Pos() {
+			return nil
+		}
+		end := node.End()
+		if text[end-1] == ')' {
+			end--
+		} else {
+			// The argument list is not closed yet, so it runs up to whatever comes next.
+			end = scanner.SkipTrivia(text, end)
+		}
+		if position > end {
+			return nil
+		}
+		index, count := getListArgumentIndexAndCount(file, list, position)
+		return &argumentInfo{kind: argumentListKindCall, invocation: node, callee: node.Expression(), argumentIndex: index, argumentCount: count}
+	case ast.KindTaggedTemplateExpression:
+		template := node.AsTaggedTemplateExpression().Template
+		if position <= scanner.GetTokenPosOfNode(template, file, false /*includeJSDoc*/) || position > template.End() ||
+			position == template.End() && isTemplateTerminated(text, template) {
+			return nil
+		}
+		index, count := 0, 1
+		if ast.IsTemplateExpression(template) {
+			spans := template.AsTemplateExpression().TemplateSpans.Nodes
+			count = len(spans) + 1
+			for i, span := range spans {
+				if position >= span.Pos() {
+					index = i + 1
+				}
+			}
+		}
+		return &argumentInfo{kind: argumentListKindCall, invocation: node, callee: node.AsTaggedTemplateExpression().Tag, argumentIndex: index, argumentCount: count}
+	case ast.KindJsxOpeningElement, ast.KindJsxSelfClosingElement:
+		// The attributes of a JSX element are passed as a single props argument.
+		attributes := node.Attributes()
+		if position <= node.TagName().End() || position > scanner.SkipTrivia(text, attributes.End()) {
+			return nil
+		}
+		return &argumentInfo{kind: argumentListKindJsxAttributes, invocation: node, callee: node.TagName(), argumentIndex: 0, argumentCount: 1}
+	}
+	return nil
+}
+
+func isPositionInList(file *ast.SourceFile, list *ast.NodeList, position int) bool {
+	return position >= list.Pos() && position <= scanner.SkipTrivia(file.Text(), list.End())
+}
+
+func isTemplateTerminated(text string, template *ast.Node) bool {
+	end := template.End()
+	return end >= 2 && text[end-1] == '`' && text[end-2] != '\\'
+}
+
+// getListArgumentIndexAndCount returns the index of the argument at position, counted by the commas
+// before it, and the number of arguments in the list including one after a trailing comma.
+func getListArgumentIndexAndCount(file *ast.SourceFile, list *ast.NodeList, position int) (int, int) {
+	text := file.Text()
+	index, count := 0, len(list.Nodes)
+	for _, argument := range list.Nodes {
+		comma := scanner.SkipTrivia(text, argument.End())
+		if comma < len(text) && text[comma] == ',' && comma < position {
+			index++
+		}
+	}
+	if list.HasTrailingComma() {
+		count++
+	}
+	return index, max(count, index+1)
+}
+
+// getPossibleTypeArgumentsInfo walks back from token to a `<` that follows a name, counting the
+// type arguments in between.
+func getPossibleTypeArgumentsInfo(file *ast.SourceFile, token *ast.Node) *argumentInfo {
+	if !strings.Contains(file.Text()[:token.End()], "<") {
+		return nil
+	}
+	remainingLessThanTokens := 0
+	typeArgumentCount := 0
+	for token != nil {
+		switch token.Kind {
+		case ast.KindLessThanToken:
+			token = astnav.FindPrecedingToken(file, token.Pos())
+			if token != nil && token.Kind == ast.KindQuestionDotToken {
+				token = astnav.FindPrecedingToken(file, token.Pos())
+			}
+			if token == nil || !ast.IsIdentifier(token) {
+				return nil
+			}
+			if remainingLessThanTokens == 0 {
+				if ast.IsDeclarationName(token) {
+					return nil
+				}
+				return &argumentInfo{
+					kind:          argumentListKindTypeArguments,
+					callee:        token,
+					argumentIndex: typeArgumentCount,
+					argumentCount: typeArgumentCount + 1,
+				}
+			}
+			remainingLessThanTokens--
+		case ast.KindGreaterThanGreaterThanGreaterThanToken:
+			remainingLessThanTokens += 3
+		case ast.KindGreaterThanGreaterThanToken:
+			remainingLessThanTokens += 2
+		case ast.KindGreaterThanToken:
+			remainingLessThanTokens++
+		case ast.KindCloseBraceToken:
+			token = findPrecedingMatchingToken(file, token, ast.KindOpenBraceToken)
+		case ast.KindCloseParenToken:
+			token = findPrecedingMatchingToken(file, token, ast.KindOpenParenToken)
+		case ast.KindCloseBracketToken:
+			token = findPrecedingMatchingToken(file, token, ast.KindOpenBracketToken)
+		case ast.KindCommaToken:
+			typeArgumentCount++
+		case ast.KindEqualsGreaterThanToken, ast.KindIdentifier, ast.KindStringLiteral, ast.KindNumericLiteral, ast.KindBigIntLiteral,
+			ast.KindTrueKeyword, ast.KindFalseKeyword, ast.KindTypeOfKeyword, ast.KindExtendsKeyword, ast.KindKeyOfKeyword,
+			ast.KindDotToken, ast.KindBarToken, ast.KindQuestionToken, ast.KindColonToken:
+		default:
+			if !ast.IsTypeNode(token) {
+				return nil
+			}
+		}
+		if token == nil {
+			return nil
+		}
+		token = astnav.FindPrecedingToken(file, token.Pos())
+	}
+	return nil
+}
+
+// findPrecedingMatchingToken finds the opening token that matches a closing one.
+func findPrecedingMatchingToken(file *ast.SourceFile, token *ast.Node, openKind ast.Kind) *ast.Node {
+	closeKind := token.Kind
+	depth := 0
+	for token != nil {
+		switch token.Kind {
+		case closeKind:
+			depth++
+		case openKind:
+			depth--
+			if depth == 0 {
+				return token
+			}
+		}
+		token = astnav.FindPrecedingToken(file, token.Pos())
+	}
+	return nil
+}
+
+func utf16Length(s string) int {
+	length := 0
+	for _, r := range s {
+		length += utf16.RuneLen(r)
+	}
+	return length
+}
@@ -0,0 +1,157 @@
+package ls_test
+
+import (
+	"testing"
+
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"gotest.tools/v3/assert"
+)
+
+func TestSignatureHelp(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		title string
+		text  string
+		// The labels of the signatures
+		expectedSignatures      []string
+		expectedActiveSignature int
+		// The text of the active parameter in the label of the active signature
+		expectedActiveParameter string
+	}{
+		{
+			title: "first argument",
+			text: `function move(x: number, y: number): void {}
+move(/*1*/`,
+			expectedSignatures:      []string{"move(x: number, y: number): void"},
+			expectedActiveParameter: "x: number",
+		},
+		{
+			title: "second argument",
+			text: `function move(x: number, y: number): void {}
+move(1, /*1*/);`,
+			expectedSignatures:      []string{"move(x: number, y: number): void"},
+			expectedActiveParameter: "y: number",
+		},
+		{
+			title: "overloads",
+			text: `function parse(value: string): number;
+function parse(value: string, radix: number): number;
+function parse(value: any, radix?: number): number { return 0; }
+parse("1", /*1*/);`,
+			expectedSignatures: []string{
+				"parse(value: string): number",
+				"parse(value: string, radix: number): number",
+			},
+			expectedActiveSignature: 1,
+			expectedActiveParameter: "radix: number",
+		},
+		{
+			title: "rest parameter",
+			text: `function join(separator: string, ...parts: string[]): string { return ""; }
+join(",", "a", "b", /*1*/);`,
+			expectedSignatures:      []string{"join(separator: string, ...parts: string[]): string"},
+			expectedActiveParameter: "...parts: string[]",
+		},
+		{
+			title: "method",
+			text: `class Logger { log(message: string, level?: number): void {} }
+new Logger().log(/*1*/);`,
+			expectedSignatures:      []string{"log(message: string, level?: number | undefined): void"},
+			expectedActiveParameter: "message: string",
+		},
+		{
+			title: "new expression",
+			text: `class Point { constructor(x: number, y: number) {} }
+new Point(1, /*1*/);`,
+			expectedSignatures:      []string{"Point(x: number, y: number): Point"},
+			expectedActiveParameter: "y: number",
+		},
+		{
+			title: "type arguments",
+			text: `function pair<K, V>(key: K, value: V): [K, V] { return [key, value]; }
+pair<string, /*1*/>`,
+			expectedSignatures:      []string{"pair<K, V>(key: K, value: V): [K, V]"},
+			expectedActiveParameter: "V",
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.title, func(t *testing.T) {
+			t.Parallel()
+			p := newTestProject(t, map[string]string{
+				"/home/src/project/tsconfig.json": `{ "compilerOptions": { "strict": true } }`,
+				"/home/src/project/index.ts":      testCase.text,
+			})
+			l, m := p.languageServiceAt("1")
+			help := l.ProvideSignatureHelp(m.fileName, m.position, nil)
+			assert.Assert(t, help != nil)
+			labels := make([]string, len(help.Signatures))
+			for i, signature := range help.Signatures {
+				labels[i] = signature.Label
+			}
+			assert.DeepEqual(t, labels, testCase.expectedSignatures)
+			assert.Equal(t, int(*help.ActiveSignature), testCase.expectedActiveSignature)
+			assert.Equal(t, activeParameterText(help), testCase.expectedActiveParameter)
+		})
+	}
+
+	t.Run("documentation", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/index.ts": `/**
+ * Waits for a while.
+ * @param milliseconds How long to wait.
+ */
+function sleep(milliseconds: number): void {}
+sleep(/*1*/);`,
+		})
+		l, m := p.languageServiceAt("1")
+		signature := l.ProvideSignatureHelp(m.fileName, m.position, nil).Signatures[0]
+		assert.Equal(t, signature.Documentation.MarkupContent.Value, "Waits for a while.\n\n*@param* `milliseconds` — How long to wait.")
+		assert.Equal(t, (*signature.Parameters)[0].Documentation.MarkupContent.Value, "How long to wait.")
+	})
+
+	t.Run("retrigger keeps the active overload", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/index.ts": `function f(a: string): void;
+function f(a: string, b: string): void;
+function f(a: string, b?: string): void {}
+f(/*1*/);`,
+		})
+		l, m := p.languageServiceAt("1")
+		help := l.ProvideSignatureHelp(m.fileName, m.position, nil)
+		assert.Equal(t, *help.ActiveSignature, uint32(0))
+
+		activeSignature := uint32(1)
+		help.ActiveSignature = &activeSignature
+		retriggered := l.ProvideSignatureHelp(m.fileName, m.position, &lsproto.SignatureHelpContext{
+			TriggerKind:         lsproto.SignatureHelpTriggerKindContentChange,
+			IsRetrigger:         true,
+			ActiveSignatureHelp: help,
+		})
+		assert.Equal(t, *retriggered.ActiveSignature, uint32(1))
+	})
+
+	t.Run("outside of a call", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/project/index.ts": `function f(a: string) {}
+f("");/*1*/`,
+		})
+		l, m := p.languageServiceAt("1")
+		assert.Assert(t, l.ProvideSignatureHelp(m.fileName, m.position, nil) == nil)
+	})
+}
+
+// activeParameterText returns the part of the label of the active signature that names the
+// active parameter.
+func activeParameterText(help *lsproto.SignatureHelp) string {
+	signature := help.Signatures[*help.ActiveSignature]
+	if signature.ActiveParameter == nil || signature.Parameters == nil || int(signature.ActiveParameter.Value) >= len(*signature.Parameters) {
+		return ""
+	}
+	offsets := (*signature.Parameters)[signature.ActiveParameter.Value].Label.UintegerPair
+	return signature.Label[offsets[0]:offsets[1]]
+}
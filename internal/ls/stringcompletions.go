@@ -0,0 +1,214 @@
+package ls
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/checker"
+	"github.com/microsoft/typescript-go/internal/compiler"
+	"github.com/microsoft/typescript-go/internal/compiler/packagejson"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/scanner"
+	"github.com/microsoft/typescript-go/internal/tspath"
+)
+
+type pathCompletion struct {
+	name string
+	kind ScriptElementKind
+}
+
+func getStringLiteralCompletionData(program *compiler.Program, c *checker.Checker, file *ast.SourceFile, literal *ast.Node, position int) *completionData {
+	start := scanner.GetTokenPosOfNode(literal, file, false /*includeJSDoc*/) + 1
+	if isModuleSpecifierLiteral(literal) {
+		return getImportPathCompletionData(program, file, start, position)
+	}
+
+	var values []string
+	if t := c.GetContextualType(literal, checker.ContextFlagsCompletions); t != nil {
+		values = getStringLiteralTypeValues(t)
+	}
+
+	end := literal.End()
+	if end > start && !isPositionInsideStringLiteral(file, literal, end) {
+		// Don't replace the closing quote.
+		end--
+	}
+	return &completionData{
+		kind:            completionKindString,
+		location:        literal,
+		strings:         values,
+		replacementSpan: core.NewTextRange(start, max(end, position)),
+	}
+}
+
+func getStringLiteralTypeValues(t *checker.Type) []string {
+	types := []*checker.Type{t}
+	if t.Flags()&checker.TypeFlagsUnion != 0 {
+		types = t.Types()
+	}
+	var values []string
+	for _, t := range types {
+		if t.Flags()&checker.TypeFlagsStringLiteral != 0 {
+			if value, ok := t.AsLiteralType().Value().(string); ok && !slices.Contains(values, value) {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+func isModuleSpecifierLiteral(node *ast.Node) bool {
+	parent := node.Parent
+	if parent == nil {
+		return false
+	}
+	switch parent.Kind {
+	case ast.KindImportDeclaration:
+		return parent.AsImportDeclaration().ModuleSpecifier == node
+	case ast.KindExportDeclaration:
+		return parent.AsExportDeclaration().ModuleSpecifier == node
+	case ast.KindExternalModuleReference:
+		return true
+	case ast.KindLiteralType:
+		return parent.Parent != nil && ast.IsImportTypeNode(parent.Parent)
+	case ast.KindCallExpression:
+		return (ast.IsImportCall(parent) || ast.IsRequireCall(parent, false /*requireStringLiteralLikeArgument*/)) &&
+			len(parent.Arguments()) > 0 && parent.Arguments()[0] == node
+	}
+	return false
+}
+
+func getImportPathCompletionData(program *compiler.Program, file *ast.SourceFile, start int, position int) *completionData {
+	typed := file.Text()[start:position]
+	var paths []pathCompletion
+	spanStart := start
+	if tspath.PathIsRelative(typed) || strings.HasPrefix(typed, "/") {
+		directoryPart := typed[:strings.LastIndexByte(typed, '/')+1]
+		spanStart += len(directoryPart)
+		paths = getCompletionsForRelativeImportPath(program, file, directoryPart)
+	} else {
+		paths = getCompletionsForPackageImportPath(program, file)
+	}
+	return &completionData{
+		kind:            completionKindPath,
+		paths:           paths,
+		replacementSpan: core.NewTextRange(spanStart, position),
+	}
+}
+
+func getCompletionsForRelativeImportPath(program *compiler.Program, file *ast.SourceFile, directoryPart string) []pathCompletion {
+	options := program.Options()
+	directory := tspath.GetNormalizedAbsolutePath(directoryPart, tspath.GetDirectoryPath(file.FileName()))
+	entries := program.Host().FS().GetAccessibleEntries(directory)
+
+	var result []pathCompletion
+	for _, name := range entries.Directories {
+		if name == "node_modules" || strings.HasPrefix(name, ".") {
+			continue
+		}
+		result = append(result, pathCompletion{name: name, kind: ScriptElementKindDirectory})
+	}
+	for _, name := range entries.Files {
+		fileName := tspath.CombinePaths(directory, name)
+		if fileName == file.FileName() || !isImportableFile(name, options) {
+			continue
+		}
+		specifier := getImportPathForFile(program, file, name)
+		if !slices.ContainsFunc(result, func(p pathCompletion) bool { return p.name == specifier }) {
+			result = append(result, pathCompletion{name: specifier, kind: ScriptElementKindScriptElement})
+		}
+	}
+	return result
+}
+
+func isImportableFile(fileName string, options *core.CompilerOptions) bool {
+	return tspath.HasTSFileExtension(fileName) ||
+		options.GetAllowJS() && tspath.HasJSFileExtension(fileName) ||
+		options.GetResolveJsonModule() && tspath.HasJSONFileExtension(fileName)
+}
+
+// getImportPathForFile returns how a file is named in a module specifier. Under the node16 and
+// nodenext module resolution modes ES module imports must name the output file's extension.
+func getImportPathForFile(program *compiler.Program, importingFile *ast.SourceFile, fileName string) string {
+	if tspath.HasJSONFileExtension(fileName) {
+		return fileName
+	}
+	options := program.Options()
+	resolution := options.GetModuleResolutionKind()
+	if (resolution == core.ModuleResolutionKindNode16 || resolution == core.ModuleResolutionKindNodeNext) &&
+		program.GetImpliedNodeFormatForEmit(importingFile) == core.ModuleKindESNext {
+		return tspath.RemoveFileExtension(fileName) + core.GetOutputExtension(fileName, options.Jsx)
+	}
+	return tspath.RemoveFileExtension(fileName)
+}
+
+func getCompletionsForPackageImportPath(program *compiler.Program, file *ast.SourceFile) []pathCompletion {
+	fs := program.Host().FS()
+	var names []string
+	add := func(name string) {
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	// Packages that are installed in any ancestor node_modules directory.
+	for directory := tspath.GetDirectoryPath(file.FileName()); ; {
+		nodeModules := tspath.CombinePaths(directory, "node_modules")
+		for _, name := range fs.GetAccessibleEntries(nodeModules).Directories {
+			switch {
+			case strings.HasPrefix(name, "."):
+				continue
+			case name == "@types":
+				for _, typesName := range fs.GetAccessibleEntries(tspath.CombinePaths(nodeModules, name)).Directories {
+					add(unmangleScopedPackageName(typesName))
+				}
+			case strings.HasPrefix(name, "@"):
+				for _, scopedName := range fs.GetAccessibleEntries(tspath.CombinePaths(nodeModules, name)).Directories {
+					add(name + "/" + scopedName)
+				}
+			default:
+				add(name)
+			}
+		}
+		parent := tspath.GetDirectoryPath(directory)
+		if parent == directory {
+			break
+		}
+		directory = parent
+	}
+
+	// Dependencies declared by the enclosing package, which may not be installed yet.
+	if scope := program.ModuleResolver().GetPackageScopeForPath(tspath.GetDirectoryPath(file.FileName())); scope.Exists() {
+		fields := scope.Contents.DependencyFields
+		for _, field := range []packagejson.Expected[map[string]string]{fields.Dependencies, fields.PeerDependencies, fields.OptionalDependencies} {
+			if dependencies, ok := field.GetValue(); ok {
+				for _, name := range slices.Sorted(maps.Keys(dependencies)) {
+					add(name)
+				}
+			}
+		}
+	}
+
+	// Path mapping patterns, without their wildcard.
+	if paths := program.Options().Paths; paths != nil {
+		for pattern := range paths.Keys() {
+			add(strings.TrimSuffix(pattern, "*"))
+		}
+	}
+
+	result := make([]pathCompletion, len(names))
+	for i, name := range names {
+		result[i] = pathCompletion{name: name, kind: ScriptElementKindExternalModuleName}
+	}
+	return result
+}
+
+// unmangleScopedPackageName turns an @types directory name like `scope__name` into `@scope/name`.
+func unmangleScopedPackageName(typesPackageName string) string {
+	if scope, name, ok := strings.Cut(typesPackageName, "__"); ok {
+		return "@" + scope + "/" + name
+	}
+	return typesPackageName
+}
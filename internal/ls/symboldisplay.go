@@ -0,0 +1,275 @@
+package ls
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/scanner"
+)
+
+// ScriptElementKind is kept as a string so that it reads the same as tsserver's kinds.
+type ScriptElementKind string
+
+const (
+	ScriptElementKindUnknown                ScriptElementKind = ""
+	ScriptElementKindWarning                ScriptElementKind = "warning"
+	ScriptElementKindKeyword                ScriptElementKind = "keyword"
+	ScriptElementKindScriptElement          ScriptElementKind = "script"
+	ScriptElementKindModuleElement          ScriptElementKind = "module"
+	ScriptElementKindClassElement           ScriptElementKind = "class"
+	ScriptElementKindLocalClassElement      ScriptElementKind = "local class"
+	ScriptElementKindInterfaceElement       ScriptElementKind = "interface"
+	ScriptElementKindTypeElement            ScriptElementKind = "type"
+	ScriptElementKindEnumElement            ScriptElementKind = "enum"
+	ScriptElementKindEnumMemberElement      ScriptElementKind = "enum member"
+	ScriptElementKindVariableElement        ScriptElementKind = "var"
+	ScriptElementKindLocalVariableElement   ScriptElementKind = "local var"
+	ScriptElementKindVariableUsingElement   ScriptElementKind = "using"
+	ScriptElementKindVariableAwaitUsing     ScriptElementKind = "await using"
+	ScriptElementKindFunctionElement        ScriptElementKind = "function"
+	ScriptElementKindLocalFunctionElement   ScriptElementKind = "local function"
+	ScriptElementKindMemberFunctionElement  ScriptElementKind = "method"
+	ScriptElementKindMemberGetAccessor      ScriptElementKind = "getter"
+	ScriptElementKindMemberSetAccessor      ScriptElementKind = "setter"
+	ScriptElementKindMemberVariableElement  ScriptElementKind = "property"
+	ScriptElementKindMemberAccessorVariable ScriptElementKind = "accessor"
+	ScriptElementKindConstructorImplElement ScriptElementKind = "constructor"
+	ScriptElementKindCallSignatureElement   ScriptElementKind = "call"
+	ScriptElementKindIndexSignatureElement  ScriptElementKind = "index"
+	ScriptElementKindConstructSignature     ScriptElementKind = "construct"
+	ScriptElementKindParameterElement       ScriptElementKind = "parameter"
+	ScriptElementKindTypeParameterElement   ScriptElementKind = "type parameter"
+	ScriptElementKindPrimitiveType          ScriptElementKind = "primitive type"
+	ScriptElementKindLabel                  ScriptElementKind = "label"
+	ScriptElementKindAlias                  ScriptElementKind = "alias"
+	ScriptElementKindConstElement           ScriptElementKind = "const"
+	ScriptElementKindLetElement             ScriptElementKind = "let"
+	ScriptElementKindDirectory              ScriptElementKind = "directory"
+	ScriptElementKindExternalModuleName     ScriptElementKind = "external module name"
+	ScriptElementKindJsxAttribute           ScriptElementKind = "JSX attribute"
+	ScriptElementKindString                 ScriptElementKind = "string"
+)
+
+func getSymbolKind(symbol *ast.Symbol, location *ast.Node) ScriptElementKind {
+	flags := symbol.Flags
+	if symbol.ExportSymbol != nil {
+		flags |= symbol.ExportSymbol.Flags
+	}
+
+	if flags&ast.SymbolFlagsClass != 0 {
+		if ast.GetDeclarationOfKind(symbol, ast.KindClassExpression) != nil {
+			return ScriptElementKindLocalClassElement
+		}
+		return ScriptElementKindClassElement
+	}
+	if flags&ast.SymbolFlagsEnum != 0 {
+		return ScriptElementKindEnumElement
+	}
+	if flags&ast.SymbolFlagsTypeAlias != 0 {
+		return ScriptElementKindTypeElement
+	}
+	if flags&ast.SymbolFlagsInterface != 0 {
+		return ScriptElementKindInterfaceElement
+	}
+	if flags&ast.SymbolFlagsTypeParameter != 0 {
+		return ScriptElementKindTypeParameterElement
+	}
+	if flags&ast.SymbolFlagsEnumMember != 0 {
+		return ScriptElementKindEnumMemberElement
+	}
+	if flags&ast.SymbolFlagsAlias != 0 {
+		return ScriptElementKindAlias
+	}
+	if flags&ast.SymbolFlagsModule != 0 {
+		return ScriptElementKindModuleElement
+	}
+	return getSymbolKindOfConstructorPropertyMethodAccessorFunctionOrVar(symbol, flags, location)
+}
+
+func getSymbolKindOfConstructorPropertyMethodAccessorFunctionOrVar(symbol *ast.Symbol, flags ast.SymbolFlags, location *ast.Node) ScriptElementKind {
+	if location != nil && ast.IsThisIdentifier(location) {
+		return ScriptElementKindParameterElement
+	}
+	if flags&ast.SymbolFlagsVariable != 0 {
+		if isFirstDeclarationOfSymbolParameter(symbol) {
+			return ScriptElementKindParameterElement
+		}
+		if decl := symbol.ValueDeclaration; decl != nil {
+			switch {
+			case ast.IsVarConst(decl):
+				return ScriptElementKindConstElement
+			case ast.IsVarUsing(decl):
+				return ScriptElementKindVariableUsingElement
+			case ast.IsVarAwaitUsing(decl):
+				return ScriptElementKindVariableAwaitUsing
+			case ast.IsVarLet(decl):
+				return ScriptElementKindLetElement
+			}
+		}
+		if isLocalVariableOrFunction(symbol) {
+			return ScriptElementKindLocalVariableElement
+		}
+		return ScriptElementKindVariableElement
+	}
+	if flags&ast.SymbolFlagsFunction != 0 {
+		if isLocalVariableOrFunction(symbol) {
+			return ScriptElementKindLocalFunctionElement
+		}
+		return ScriptElementKindFunctionElement
+	}
+	if flags&ast.SymbolFlagsGetAccessor != 0 {
+		return ScriptElementKindMemberGetAccessor
+	}
+	if flags&ast.SymbolFlagsSetAccessor != 0 {
+		return ScriptElementKindMemberSetAccessor
+	}
+	if flags&ast.SymbolFlagsMethod != 0 {
+		return ScriptElementKindMemberFunctionElement
+	}
+	if flags&ast.SymbolFlagsConstructor != 0 {
+		return ScriptElementKindConstructorImplElement
+	}
+	if flags&ast.SymbolFlagsSignature != 0 {
+		return ScriptElementKindIndexSignatureElement
+	}
+	if flags&ast.SymbolFlagsProperty != 0 {
+		if decl := symbol.ValueDeclaration; decl != nil && ast.IsPropertyDeclaration(decl) && ast.HasAccessorModifier(decl) {
+			return ScriptElementKindMemberAccessorVariable
+		}
+		return ScriptElementKindMemberVariableElement
+	}
+	return ScriptElementKindUnknown
+}
+
+func isFirstDeclarationOfSymbolParameter(symbol *ast.Symbol) bool {
+	return len(symbol.Declarations) > 0 && ast.FindAncestorOrQuit(symbol.Declarations[0], func(node *ast.Node) ast.FindAncestorResult {
+		if ast.IsParameter(node) {
+			return ast.FindAncestorTrue
+		}
+		if ast.IsBindingElement(node) || ast.IsObjectBindingPattern(node) || ast.IsArrayBindingPattern(node) {
+			return ast.FindAncestorFalse
+		}
+		return ast.FindAncestorQuit
+	}) != nil
+}
+
+// isLocalVariableOrFunction reports whether a symbol is declared inside a function body
+// rather than at the top level of a file or module.
+func isLocalVariableOrFunction(symbol *ast.Symbol) bool {
+	for _, decl := range symbol.Declarations {
+		// Function expressions are local
+		if decl.Kind == ast.KindFunctionExpression {
+			return true
+		}
+		if decl.Kind != ast.KindVariableDeclaration && decl.Kind != ast.KindFunctionDeclaration {
+			continue
+		}
+		// If the parent is not sourceFile or module block it is local variable
+		for parent := decl.Parent; parent != nil; parent = parent.Parent {
+			if parent.Kind == ast.KindSourceFile || parent.Kind == ast.KindModuleBlock {
+				break
+			}
+			if ast.IsFunctionLike(parent) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getDocumentation renders the JSDoc comments and tags of declarations as markdown.
+func getDocumentation(declarations []*ast.Node) string {
+	var comments, tags []string
+	for _, decl := range declarations {
+		for _, jsdoc := range getJSDocHost(decl).JSDoc(nil) {
+			if text := getJSDocCommentText(jsdoc.CommentList()); text != "" && !slices.Contains(comments, text) {
+				comments = append(comments, text)
+			}
+			if jsdocTags := jsdoc.AsJSDoc().Tags; jsdocTags != nil {
+				for _, tag := range jsdocTags.Nodes {
+					if text := getJSDocTagText(tag); !slices.Contains(tags, text) {
+						tags = append(tags, text)
+					}
+				}
+			}
+		}
+	}
+	return strings.Join(append(comments, tags...), "\n\n")
+}
+
+// getJSDocTagText renders a tag the way editors show it, as in "*@param* `x` — The value."
+func getJSDocTagText(tag *ast.Node) string {
+	var b strings.Builder
+	b.WriteString("*@")
+	b.WriteString(tag.TagName().Text())
+	b.WriteString("*")
+	switch tag.Kind {
+	case ast.KindJSDocParameterTag, ast.KindJSDocPropertyTag:
+		if name := tag.Name(); name != nil {
+			b.WriteString(" `" + getNodeText(name) + "`")
+		}
+	case ast.KindJSDocTemplateTag:
+		if typeParameters := tag.AsJSDocTemplateTag().TypeParameters(); typeParameters != nil {
+			names := make([]string, len(typeParameters.Nodes))
+			for i, tp := range typeParameters.Nodes {
+				names[i] = tp.Name().Text()
+			}
+			b.WriteString(" `" + strings.Join(names, ", ") + "`")
+		}
+	}
+	if comment := getJSDocCommentText(tag.CommentList()); comment != "" {
+		b.WriteString(" — ")
+		b.WriteString(comment)
+	}
+	return b.String()
+}
+
+// getJSDocHost returns the node that a declaration's JSDoc is attached to.
+func getJSDocHost(node *ast.Node) *ast.Node {
+	if ast.IsVariableDeclaration(node) && node.Parent != nil && node.Parent.Parent != nil && ast.IsVariableStatement(node.Parent.Parent) {
+		return node.Parent.Parent
+	}
+	return node
+}
+
+func getJSDocCommentText(comment *ast.NodeList) string {
+	if comment == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, part := range comment.Nodes {
+		switch part.Kind {
+		case ast.KindJSDocText:
+			b.WriteString(part.AsJSDocText().Text)
+		case ast.KindJSDocLink, ast.KindJSDocLinkCode, ast.KindJSDocLinkPlain:
+			b.WriteString(getJSDocLinkText(part))
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func getJSDocLinkText(link *ast.Node) string {
+	var text string
+	switch link.Kind {
+	case ast.KindJSDocLink:
+		text = link.AsJSDocLink().Text
+	case ast.KindJSDocLinkCode:
+		text = link.AsJSDocLinkCode().Text
+	case ast.KindJSDocLinkPlain:
+		text = link.AsJSDocLinkPlain().Text
+	}
+	if name := link.Name(); name != nil {
+		nameText := getNodeText(name)
+		if text == "" {
+			return "`" + nameText + "`"
+		}
+		return strings.TrimSpace(text)
+	}
+	return text
+}
+
+func getNodeText(node *ast.Node) string {
+	file := ast.GetSourceFileOfNode(node)
+	return file.Text()[scanner.GetTokenPosOfNode(node, file, false /*includeJSDoc*/):node.End()]
+}
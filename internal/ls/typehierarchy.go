@@ -0,0 +1,149 @@
+package ls
+
+import (
+	"slices"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/astnav"
+	"github.com/microsoft/typescript-go/internal/checker"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"github.com/microsoft/typescript-go/internal/scanner"
+)
+
+// ProvidePrepareTypeHierarchy returns the class or interface that is declared or referred to at
+// position.
+func (l *LanguageService) ProvidePrepareTypeHierarchy(fileName string, position int) []HierarchyItem {
+	_, file := l.getProgramAndFile(fileName)
+	symbol := getTypeHierarchySymbol(l.GetTypeChecker(), astnav.GetTouchingPropertyName(file, position))
+	if symbol == nil {
+		return nil
+	}
+	if declaration := getTypeHierarchyDeclaration(symbol); declaration != nil {
+		return []HierarchyItem{newTypeHierarchyItem(declaration)}
+	}
+	return nil
+}
+
+// ProvideTypeHierarchySupertypes returns the classes and interfaces that the class or interface at
+// position extends or implements.
+func (l *LanguageService) ProvideTypeHierarchySupertypes(fileName string, position int) []HierarchyItem {
+	_, file := l.getProgramAndFile(fileName)
+	c := l.GetTypeChecker()
+	symbol := getTypeHierarchySymbol(c, astnav.GetTouchingPropertyName(file, position))
+	if symbol == nil {
+		return nil
+	}
+	var items []HierarchyItem
+	for _, base := range getBaseTypeSymbols(c, symbol) {
+		if declaration := getTypeHierarchyDeclaration(base); declaration != nil {
+			items = append(items, newTypeHierarchyItem(declaration))
+		}
+	}
+	return items
+}
+
+// ProvideTypeHierarchySubtypes returns the classes and interfaces that directly extend or
+// implement the class or interface at position. Subtypes can be declared in any project that
+// includes the file, so the programs of all the language services that do are searched, and a
+// subtype in a file that is shared by programs is returned once.
+func ProvideTypeHierarchySubtypes(services []*LanguageService, fileName string, position int) []HierarchyItem {
+	var items []HierarchyItem
+	seen := make(map[Location]struct{})
+	for _, service := range services {
+		program, file := service.tryGetProgramAndFile(fileName)
+		if file == nil {
+			continue
+		}
+		c := service.GetTypeChecker()
+		target := getTypeHierarchySymbol(c, astnav.GetTouchingPropertyName(file, position))
+		if target == nil {
+			continue
+		}
+		for _, declaration := range findDirectSubtypes(program.SourceFiles(), c, target) {
+			if program.IsSourceFileDefaultLibrary(ast.GetSourceFileOfNode(declaration)) {
+				continue
+			}
+			item := newTypeHierarchyItem(declaration)
+			location := Location{FileName: item.FileName, Range: item.SelectionRange}
+			if _, ok := seen[location]; ok {
+				continue
+			}
+			seen[location] = struct{}{}
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getTypeHierarchySymbol returns the class or interface that node is in the declaration of or
+// refers to, or nil if it is neither.
+func getTypeHierarchySymbol(c *checker.Checker, node *ast.Node) *ast.Symbol {
+	var symbol *ast.Symbol
+	if parent := node.Parent; parent != nil && (ast.IsClassLike(parent) || ast.IsInterfaceDeclaration(parent)) {
+		symbol = parent.Symbol()
+	} else {
+		symbol = c.GetSymbolAtLocation(node)
+	}
+	if symbol == nil {
+		return nil
+	}
+	symbol = skipAlias(c, symbol)
+	if symbol.Flags&(ast.SymbolFlagsClass|ast.SymbolFlagsInterface) == 0 {
+		return nil
+	}
+	return symbol
+}
+
+// getTypeHierarchyDeclaration returns the declaration that represents a class or interface: the
+// class of a class merged with interfaces, or else the first of its declarations.
+func getTypeHierarchyDeclaration(symbol *ast.Symbol) *ast.Node {
+	if index := slices.IndexFunc(symbol.Declarations, ast.IsClassLike); index >= 0 {
+		return symbol.Declarations[index]
+	}
+	if index := slices.IndexFunc(symbol.Declarations, ast.IsInterfaceDeclaration); index >= 0 {
+		return symbol.Declarations[index]
+	}
+	return nil
+}
+
+// findDirectSubtypes returns the declarations of classes and interfaces that name target in their
+// extends or implements clauses.
+func findDirectSubtypes(files []*ast.SourceFile, c *checker.Checker, target *ast.Symbol) []*ast.Node {
+	var result []*ast.Node
+	var visit func(node *ast.Node) bool
+	visit = func(node *ast.Node) bool {
+		if ast.IsClassLike(node) || ast.IsInterfaceDeclaration(node) {
+			for _, typeReference := range slices.Concat(ast.GetExtendsHeritageClauseElements(node), ast.GetImplementsHeritageClauseElements(node)) {
+				if t := c.GetTypeAtLocation(typeReference); t != nil && t.Symbol() == target {
+					result = append(result, node)
+					break
+				}
+			}
+		}
+		node.ForEachChild(visit)
+		return false
+	}
+	for _, file := range files {
+		file.AsNode().ForEachChild(visit)
+	}
+	return result
+}
+
+func newTypeHierarchyItem(declaration *ast.Node) HierarchyItem {
+	file := ast.GetSourceFileOfNode(declaration)
+	item := HierarchyItem{
+		Name:     getCallHierarchyItemName(declaration),
+		Kind:     core.IfElse(ast.IsInterfaceDeclaration(declaration), lsproto.SymbolKindInterface, lsproto.SymbolKindClass),
+		Detail:   getCallHierarchyItemContainerName(declaration),
+		FileName: file.FileName(),
+		Range:    core.NewTextRange(scanner.GetTokenPosOfNode(declaration, file, true /*includeJSDoc*/), declaration.End()),
+	}
+	if name := declaration.Name(); name != nil {
+		item.SelectionRange = core.NewTextRange(scanner.GetTokenPosOfNode(name, file, false /*includeJSDoc*/), name.End())
+	} else {
+		start := scanner.GetTokenPosOfNode(declaration, file, false /*includeJSDoc*/)
+		item.SelectionRange = core.NewTextRange(start, start)
+	}
+	return item
+}
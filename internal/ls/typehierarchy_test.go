@@ -0,0 +1,112 @@
+package ls_test
+
+import (
+	"testing"
+
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/ls"
+	"gotest.tools/v3/assert"
+)
+
+func TestTypeHierarchy(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"/home/src/project/tsconfig.json": `{}`,
+		"/home/src/project/index.ts": `export interface /*Shape*/Shape {}
+export interface /*Named*/Named {}
+export class /*Base*/Base implements /*ShapeRef*/Shape {}
+export interface /*Round*/Round extends Shape, Named {}
+export class /*Square*/Square extends /*BaseRef*/Base implements Named {}
+namespace /*Shapes*/Shapes {
+    export class /*Circle*/Circle extends Base implements Round {}
+}
+const /*value*/value = 1;`,
+	}
+
+	t.Run("prepare", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, files)
+		cases := []struct {
+			marker   string
+			expected []string
+		}{
+			{marker: "Shape", expected: []string{"Shape (interface)"}},
+			{marker: "ShapeRef", expected: []string{"Shape (interface)"}},
+			{marker: "BaseRef", expected: []string{"Base (class)"}},
+			{marker: "Circle", expected: []string{"Circle (class) Shapes"}},
+			{marker: "value", expected: nil},
+		}
+		for _, testCase := range cases {
+			l, m := p.languageServiceAt(testCase.marker)
+			items := l.ProvidePrepareTypeHierarchy(m.fileName, m.position)
+			assert.DeepEqual(t, core.Map(items, p.formatHierarchyItem), testCase.expected)
+		}
+	})
+
+	t.Run("supertypes", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, files)
+		cases := []struct {
+			marker   string
+			expected []string
+		}{
+			{marker: "Shape", expected: nil},
+			{marker: "Base", expected: []string{"Shape (interface)"}},
+			{marker: "Round", expected: []string{"Shape (interface)", "Named (interface)"}},
+			{marker: "Circle", expected: []string{"Base (class)", "Round (interface)"}},
+		}
+		for _, testCase := range cases {
+			l, m := p.languageServiceAt(testCase.marker)
+			items := l.ProvideTypeHierarchySupertypes(m.fileName, m.position)
+			assert.DeepEqual(t, core.Map(items, p.formatHierarchyItem), testCase.expected)
+		}
+	})
+
+	t.Run("subtypes", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, files)
+		cases := []struct {
+			marker   string
+			expected []string
+		}{
+			// Only direct subtypes are returned
+			{marker: "Shape", expected: []string{"Base (class)", "Round (interface)"}},
+			{marker: "Named", expected: []string{"Round (interface)", "Square (class)"}},
+			{marker: "Base", expected: []string{"Square (class)", "Circle (class) Shapes"}},
+			{marker: "Square", expected: nil},
+		}
+		for _, testCase := range cases {
+			l, m := p.languageServiceAt(testCase.marker)
+			items := ls.ProvideTypeHierarchySubtypes([]*ls.LanguageService{l}, m.fileName, m.position)
+			assert.DeepEqual(t, core.Map(items, p.formatHierarchyItem), testCase.expected)
+		}
+	})
+
+	t.Run("subtypes across projects", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProject(t, map[string]string{
+			"/home/src/shared/base.ts": `export interface /*Shape*/Shape {}
+export class /*Base*/Base implements Shape {}`,
+			"/home/src/a/tsconfig.json": `{ "files": ["index.ts", "../shared/base.ts"] }`,
+			"/home/src/a/index.ts": `import { Base } from "../shared/base";
+export class /*Square*/Square extends Base {}`,
+			"/home/src/b/tsconfig.json": `{ "files": ["index.ts", "../shared/base.ts"] }`,
+			"/home/src/b/index.ts": `import { Base, Shape } from "../shared/base";
+export class /*Circle*/Circle extends Base {}
+export interface /*Round*/Round extends Shape {}`,
+		})
+		services := []*ls.LanguageService{
+			p.languageService("/home/src/a/index.ts"),
+			p.languageService("/home/src/b/index.ts"),
+		}
+		// Each project finds the subtypes that it includes, and a subtype in the file that both
+		// include is returned once
+		base := p.marker("Base")
+		items := ls.ProvideTypeHierarchySubtypes(services, base.fileName, base.position)
+		assert.DeepEqual(t, core.Map(items, p.formatHierarchyItem), []string{"Square (class)", "Circle (class)"})
+		shape := p.marker("Shape")
+		items = ls.ProvideTypeHierarchySubtypes(services, shape.fileName, shape.position)
+		assert.DeepEqual(t, core.Map(items, p.formatHierarchyItem), []string{"Base (class)", "Round (interface)"})
+	})
+}
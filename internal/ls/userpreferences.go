@@ -0,0 +1,44 @@
+package ls
+
+import (
+	"github.com/microsoft/typescript-go/internal/modulespecifiers"
+)
+
+type QuotePreference string
+
+const (
+	// QuotePreferenceAuto uses the quotes of the existing imports of a file.
+	QuotePreferenceAuto   QuotePreference = "auto"
+	QuotePreferenceDouble QuotePreference = "double"
+	QuotePreferenceSingle QuotePreference = "single"
+)
+
+// UserPreferences are the preferences that change the results of the language service, other than
+// formatting. They are named after the user preferences of tsserver, and GetDefaultUserPreferences
+// returns their defaults.
+type UserPreferences struct {
+	// QuotePreference is the quote that the module specifiers of added imports are written with.
+	QuotePreference                 QuotePreference
+	ImportModuleSpecifierPreference modulespecifiers.ImportModuleSpecifierPreference
+	ImportModuleSpecifierEnding     modulespecifiers.ImportModuleSpecifierEndingPreference
+
+	InlayHintsPreferences
+}
+
+func GetDefaultUserPreferences() *UserPreferences {
+	return &UserPreferences{
+		QuotePreference:                 QuotePreferenceAuto,
+		ImportModuleSpecifierPreference: modulespecifiers.ImportModuleSpecifierPreferenceShortest,
+		ImportModuleSpecifierEnding:     modulespecifiers.ImportModuleSpecifierEndingPreferenceAuto,
+		InlayHintsPreferences: InlayHintsPreferences{
+			IncludeInlayParameterNameHints: InlayParameterNameHintsNone,
+		},
+	}
+}
+
+func (p *UserPreferences) moduleSpecifierPreferences() modulespecifiers.UserPreferences {
+	return modulespecifiers.UserPreferences{
+		ImportModuleSpecifierPreference: p.ImportModuleSpecifierPreference,
+		ImportModuleSpecifierEnding:     p.ImportModuleSpecifierEnding,
+	}
+}
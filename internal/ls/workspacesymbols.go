@@ -0,0 +1,171 @@
+package ls
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/compiler"
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"github.com/microsoft/typescript-go/internal/tspath"
+)
+
+// maxWorkspaceSymbols limits the results of a search, which a short query can make very large.
+const maxWorkspaceSymbols = 256
+
+type WorkspaceSymbol struct {
+	Name          string
+	Kind          lsproto.SymbolKind
+	ContainerName string
+	Location      Location
+}
+
+// symbolIndex lists the declarations of a program by name. The entries of a file are kept for as
+// long as the file is unchanged, so a new program only indexes the files that changed.
+type symbolIndex struct {
+	program *compiler.Program
+	files   map[*ast.SourceFile][]symbolIndexEntry
+}
+
+type symbolIndexEntry struct {
+	name          string
+	lowerName     string
+	kind          lsproto.SymbolKind
+	containerName string
+	node          *ast.Node
+}
+
+func (l *LanguageService) getSymbolIndex() *symbolIndex {
+	program := l.GetProgram()
+	l.symbolIndexMu.Lock()
+	defer l.symbolIndexMu.Unlock()
+	if l.symbolIndex != nil && l.symbolIndex.program == program {
+		return l.symbolIndex
+	}
+
+	index := &symbolIndex{
+		program: program,
+		files:   make(map[*ast.SourceFile][]symbolIndexEntry),
+	}
+	for _, file := range program.SourceFiles() {
+		if program.IsSourceFileDefaultLibrary(file) {
+			continue
+		}
+		entries, ok := l.symbolIndex.getFileEntries(file)
+		if !ok {
+			entries = getSymbolIndexEntries(file)
+		}
+		index.files[file] = entries
+	}
+	l.symbolIndex = index
+	return index
+}
+
+func (index *symbolIndex) getFileEntries(file *ast.SourceFile) ([]symbolIndexEntry, bool) {
+	if index == nil {
+		return nil, false
+	}
+	entries, ok := index.files[file]
+	return entries, ok
+}
+
+func getSymbolIndexEntries(file *ast.SourceFile) []symbolIndexEntry {
+	var entries []symbolIndexEntry
+	var add func(items []*navigationItem, containerName string)
+	add = func(items []*navigationItem, containerName string) {
+		for _, item := range items {
+			entries = append(entries, symbolIndexEntry{
+				name:          item.name,
+				lowerName:     strings.ToLower(item.name),
+				kind:          item.kind,
+				containerName: containerName,
+				node:          item.node,
+			})
+			add(item.children, item.name)
+		}
+	}
+	add(getNavigationTree(file), "")
+	return entries
+}
+
+// ProvideWorkspaceSymbols searches the declarations of the programs of several language services
+// for names that match query, best matches first. Files that are shared by programs are searched once.
+func ProvideWorkspaceSymbols(services []*LanguageService, query string) []WorkspaceSymbol {
+	lowerQuery := strings.ToLower(query)
+	type match struct {
+		symbol WorkspaceSymbol
+		score  int
+	}
+	var matches []match
+	seen := make(map[tspath.Path]struct{})
+	for _, service := range services {
+		index := service.getSymbolIndex()
+		for file, entries := range index.files {
+			if _, ok := seen[file.Path()]; ok {
+				continue
+			}
+			seen[file.Path()] = struct{}{}
+			for _, entry := range entries {
+				score, ok := getWorkspaceSymbolMatchScore(query, lowerQuery, entry)
+				if !ok {
+					continue
+				}
+				matches = append(matches, match{
+					symbol: WorkspaceSymbol{
+						Name:          entry.name,
+						Kind:          entry.kind,
+						ContainerName: entry.containerName,
+						Location: Location{
+							FileName: file.FileName(),
+							Range:    getNavigationItemSpan(file, entry.node),
+						},
+					},
+					score: score,
+				})
+			}
+		}
+	}
+
+	slices.SortFunc(matches, func(a, b match) int {
+		return cmp.Or(
+			cmp.Compare(a.score, b.score),
+			cmp.Compare(a.symbol.Name, b.symbol.Name),
+			cmp.Compare(a.symbol.Location.FileName, b.symbol.Location.FileName),
+			cmp.Compare(a.symbol.Location.Range.Pos(), b.symbol.Location.Range.Pos()),
+		)
+	})
+	result := make([]WorkspaceSymbol, 0, min(len(matches), maxWorkspaceSymbols))
+	for _, m := range matches[:min(len(matches), maxWorkspaceSymbols)] {
+		result = append(result, m.symbol)
+	}
+	return result
+}
+
+// getWorkspaceSymbolMatchScore reports whether a name matches a query, and how well: exact matches
+// come first, then prefixes, substrings and finally names that contain the query's characters in order.
+func getWorkspaceSymbolMatchScore(query string, lowerQuery string, entry symbolIndexEntry) (int, bool) {
+	switch {
+	case entry.name == query:
+		return 0, true
+	case entry.lowerName == lowerQuery:
+		return 1, true
+	case strings.HasPrefix(entry.lowerName, lowerQuery):
+		return 2, true
+	case strings.Contains(entry.lowerName, lowerQuery):
+		return 3, true
+	case isSubsequence(lowerQuery, entry.lowerName):
+		return 4, true
+	}
+	return 0, false
+}
+
+func isSubsequence(query string, name string) bool {
+	i := 0
+	for j := 0; i < len(query) && j < len(name); j++ {
+		if query[i] == name[j] {
+			i++
+		}
+	}
+	return i == len(query)
+}
@@ -0,0 +1,212 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"runtime/debug"
+	"slices"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/collections"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/ls"
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"github.com/microsoft/typescript-go/internal/modulespecifiers"
+	"github.com/microsoft/typescript-go/internal/project"
+	"github.com/microsoft/typescript-go/internal/tsoptions"
+)
+
+// configurationSection is the section of the configuration of the client that holds the settings
+// of the server.
+const configurationSection = "typescript"
+
+// settings are the settings of the server, which are named after the options of tsserver:
+//
+//   - format holds the FormatCodeSettings of tsserver, which the options of each formatting
+//     request override.
+//   - preferences holds the UserPreferences of tsserver, including the inlay hint preferences,
+//     which turn inlay hints off by default, as in tsserver.
+//   - logVerbosity is one of "terse", "normal", "requestTime" and "verbose", and logFile is a
+//     file to log to in addition to stderr.
+//   - maxTsServerMemory is a soft limit of the memory that the server uses, in megabytes.
+//   - inferredProjectCompilerOptions are the compilerOptions, as in a tsconfig.json, of the
+//     projects of files that are not included by one.
+//
+// A setting that is not set takes its default value.
+type settings struct {
+	Format                         json.RawMessage `json:"format"`
+	Preferences                    json.RawMessage `json:"preferences"`
+	LogVerbosity                   string          `json:"logVerbosity"`
+	LogFile                        string          `json:"logFile"`
+	MaxTsServerMemory              int64           `json:"maxTsServerMemory"`
+	InferredProjectCompilerOptions json.RawMessage `json:"inferredProjectCompilerOptions"`
+}
+
+var logVerbosities = map[string]project.LogLevel{
+	"terse":       project.LogLevelTerse,
+	"normal":      project.LogLevelNormal,
+	"requestTime": project.LogLevelRequestTime,
+	"verbose":     project.LogLevelVerbose,
+}
+
+// watchConfiguration asks the client for the settings of the server, and to notify the server when
+// they change, if it can.
+func (s *Server) watchConfiguration() error {
+	workspace := s.initializeParams.Capabilities.Workspace
+	if workspace == nil {
+		return nil
+	}
+	if workspace.DidChangeConfiguration != nil && workspace.DidChangeConfiguration.DynamicRegistration != nil && *workspace.DidChangeConfiguration.DynamicRegistration {
+		if err := s.sendRequest(lsproto.MethodClientRegisterCapability, &lsproto.RegistrationParams{
+			Registrations: []lsproto.Registration{
+				{
+					Id:     "configuration",
+					Method: string(lsproto.MethodWorkspaceDidChangeConfiguration),
+					RegisterOptions: ptrTo(any(lsproto.DidChangeConfigurationRegistrationOptions{
+						Section: &lsproto.StringOrStrings{String: ptrTo(configurationSection)},
+					})),
+				},
+			},
+		}, nil /*onResult*/); err != nil {
+			return err
+		}
+	}
+	return s.requestConfiguration()
+}
+
+// requestConfiguration asks the client for the settings of the server, if it can be asked, and
+// applies them once it responds.
+func (s *Server) requestConfiguration() error {
+	workspace := s.initializeParams.Capabilities.Workspace
+	if workspace == nil || workspace.Configuration == nil || !*workspace.Configuration {
+		return nil
+	}
+	return s.sendRequest(lsproto.MethodWorkspaceConfiguration, &lsproto.ConfigurationParams{
+		Items: []lsproto.ConfigurationItem{{Section: ptrTo(configurationSection)}},
+	}, func(result json.RawMessage) {
+		var sections []json.RawMessage
+		if err := json.Unmarshal(result, &sections); err != nil || len(sections) != 1 {
+			s.logger.Error(fmt.Sprintf("Invalid configuration: %s", result))
+			return
+		}
+		s.applySettings(sections[0])
+	})
+}
+
+// handleDidChangeConfiguration applies the settings that the client sends along, or asks for them
+// if the client can be asked, since clients that can be asked usually send none.
+func (s *Server) handleDidChangeConfiguration(req *lsproto.RequestMessage) error {
+	workspace := s.initializeParams.Capabilities.Workspace
+	if workspace != nil && workspace.Configuration != nil && *workspace.Configuration {
+		return s.requestConfiguration()
+	}
+	params := req.Params.(*lsproto.DidChangeConfigurationParams)
+	if sections, ok := params.Settings.(map[string]any); ok {
+		data, err := json.Marshal(sections[configurationSection])
+		if err != nil {
+			return err
+		}
+		s.applySettings(data)
+	}
+	return nil
+}
+
+// applySettings replaces the settings of the server. Settings that are invalid are logged and
+// take their default values.
+func (s *Server) applySettings(data json.RawMessage) {
+	var settings settings
+	if len(data) > 0 && !bytes.Equal(data, []byte("null")) {
+		if err := json.Unmarshal(data, &settings); err != nil {
+			s.logger.Error("Invalid settings: " + err.Error())
+		}
+	}
+
+	formatCodeSettings := ls.GetDefaultFormatCodeSettings(s.NewLine())
+	if settings.Format != nil {
+		if err := json.Unmarshal(settings.Format, formatCodeSettings); err != nil || !slices.Contains([]ls.SemicolonPreference{
+			ls.SemicolonPreferenceIgnore, ls.SemicolonPreferenceInsert, ls.SemicolonPreferenceRemove,
+		}, formatCodeSettings.Semicolons) {
+			s.logger.Error(fmt.Sprintf("Invalid format settings: %s", settings.Format))
+			formatCodeSettings = ls.GetDefaultFormatCodeSettings(s.NewLine())
+		}
+	}
+	s.projectService.SetFormatCodeSettings(formatCodeSettings)
+
+	preferences := ls.GetDefaultUserPreferences()
+	if settings.Preferences != nil {
+		if err := json.Unmarshal(settings.Preferences, preferences); err != nil || !validUserPreferences(preferences) {
+			s.logger.Error(fmt.Sprintf("Invalid preferences: %s", settings.Preferences))
+			preferences = ls.GetDefaultUserPreferences()
+		}
+	}
+	s.projectService.SetUserPreferences(preferences)
+
+	// Changing the compiler options of the inferred projects rebuilds their programs, so they are
+	// only changed when the settings do.
+	if !bytes.Equal(settings.InferredProjectCompilerOptions, s.inferredProjectCompilerOptions) {
+		s.inferredProjectCompilerOptions = settings.InferredProjectCompilerOptions
+		compilerOptions := &core.CompilerOptions{}
+		if settings.InferredProjectCompilerOptions != nil {
+			var jsonOptions collections.OrderedMap[string, any]
+			if err := json.Unmarshal(settings.InferredProjectCompilerOptions, &jsonOptions); err != nil {
+				s.logger.Error("Invalid inferred project compiler options: " + err.Error())
+			} else {
+				var errors []*ast.Diagnostic
+				compilerOptions, errors = tsoptions.ConvertCompilerOptionsFromJson(&jsonOptions, s.cwd)
+				for _, err := range errors {
+					s.logger.Error("Invalid inferred project compiler options: " + err.Message())
+				}
+			}
+		}
+		s.projectService.SetCompilerOptionsForInferredProjects(compilerOptions)
+		s.scheduleDiagnostics("")
+	}
+
+	level, ok := logVerbosities[settings.LogVerbosity]
+	if !ok {
+		if settings.LogVerbosity != "" {
+			s.logger.Error("Invalid log verbosity: " + settings.LogVerbosity)
+		}
+		level = project.LogLevelVerbose
+	}
+	s.logger.SetLevel(level)
+	if err := s.logger.SetFile(settings.LogFile); err != nil {
+		s.logger.Error("Failed to open log file: " + err.Error())
+	}
+
+	if settings.MaxTsServerMemory > 0 {
+		debug.SetMemoryLimit(settings.MaxTsServerMemory << 20)
+	} else {
+		debug.SetMemoryLimit(math.MaxInt64)
+	}
+
+	if workspace := s.initializeParams.Capabilities.Workspace; workspace != nil && workspace.InlayHint != nil &&
+		workspace.InlayHint.RefreshSupport != nil && *workspace.InlayHint.RefreshSupport {
+		if err := s.sendRequest(lsproto.MethodWorkspaceInlayHintRefresh, nil, nil /*onResult*/); err != nil {
+			s.setRequestError(err)
+		}
+	}
+}
+
+func validUserPreferences(preferences *ls.UserPreferences) bool {
+	return slices.Contains([]ls.QuotePreference{
+		ls.QuotePreferenceAuto, ls.QuotePreferenceDouble, ls.QuotePreferenceSingle,
+	}, preferences.QuotePreference) &&
+		slices.Contains([]modulespecifiers.ImportModuleSpecifierPreference{
+			modulespecifiers.ImportModuleSpecifierPreferenceShortest,
+			modulespecifiers.ImportModuleSpecifierPreferenceProjectRelative,
+			modulespecifiers.ImportModuleSpecifierPreferenceRelative,
+			modulespecifiers.ImportModuleSpecifierPreferenceNonRelative,
+		}, preferences.ImportModuleSpecifierPreference) &&
+		slices.Contains([]modulespecifiers.ImportModuleSpecifierEndingPreference{
+			modulespecifiers.ImportModuleSpecifierEndingPreferenceAuto,
+			modulespecifiers.ImportModuleSpecifierEndingPreferenceMinimal,
+			modulespecifiers.ImportModuleSpecifierEndingPreferenceIndex,
+			modulespecifiers.ImportModuleSpecifierEndingPreferenceJs,
+		}, preferences.ImportModuleSpecifierEnding) &&
+		slices.Contains([]ls.InlayParameterNameHints{
+			ls.InlayParameterNameHintsNone, ls.InlayParameterNameHintsLiterals, ls.InlayParameterNameHintsAll,
+		}, preferences.IncludeInlayParameterNameHints)
+}
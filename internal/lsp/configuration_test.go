@@ -0,0 +1,164 @@
+package lsp
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/microsoft/typescript-go/internal/bundled"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/ls"
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"github.com/microsoft/typescript-go/internal/project"
+	"github.com/microsoft/typescript-go/internal/vfs/vfstest"
+	"gotest.tools/v3/assert"
+)
+
+const scriptFileName = "/home/src/project/script.ts"
+
+// newConfigurationTestServer returns an initialized server whose log, other than to a log file, is
+// written to stderr.
+func newConfigurationTestServer(t *testing.T, stderr io.Writer) *Server {
+	t.Helper()
+	if !bundled.Embedded {
+		// Without embedding, we'd need to read all of the lib files out from disk into the MapFS.
+		// Just skip this for now.
+		t.Skip("bundled files are not embedded")
+	}
+	s := NewServer(&ServerOptions{
+		In:                 strings.NewReader(""),
+		Out:                io.Discard,
+		Err:                stderr,
+		Cwd:                "/home/src/project",
+		NewLine:            core.NewLineKindLF,
+		FS:                 bundled.WrapFS(vfstest.FromMap(map[string]string{scriptFileName: `let x = 1;`}, false /*useCaseSensitiveFileNames*/)),
+		DefaultLibraryPath: bundled.LibPath(),
+	})
+	s.initializeParams = &lsproto.InitializeParams{}
+	assert.NilError(t, s.handleInitialized(nil))
+	t.Cleanup(func() {
+		s.stopDiagnostics()
+		s.logger.Close()
+	})
+	return s
+}
+
+func TestDefaultSettings(t *testing.T) {
+	t.Parallel()
+	s := newConfigurationTestServer(t, io.Discard)
+	s.applySettings(nil)
+	assert.DeepEqual(t, s.projectService.UserPreferences(), ls.GetDefaultUserPreferences())
+	// Inlay hints are off unless the client turns them on, as in tsserver
+	assert.DeepEqual(t, s.projectService.UserPreferences().InlayHintsPreferences, ls.InlayHintsPreferences{
+		IncludeInlayParameterNameHints: ls.InlayParameterNameHintsNone,
+	})
+	assert.DeepEqual(t, s.projectService.FormatCodeSettings(), ls.GetDefaultFormatCodeSettings("\n"))
+	assert.Assert(t, s.logger.HasLevel(project.LogLevelVerbose))
+}
+
+func TestInvalidSettings(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		settings string
+		logged   string
+	}{
+		{"settings", `"typescript"`, "Invalid settings"},
+		{"preferences", `{"preferences": {"quotePreference": "backtick", "includeInlayVariableTypeHints": true}}`, "Invalid preferences"},
+		{"preferences type", `{"preferences": {"includeInlayVariableTypeHints": "yes"}}`, "Invalid preferences"},
+		{"format", `{"format": {"semicolons": "always", "indentSize": 2}}`, "Invalid format settings"},
+		{"log verbosity", `{"logVerbosity": "loud"}`, "Invalid log verbosity: loud"},
+		{"log file", `{"logFile": "/nonexistent/directory/tsserver.log"}`, "Failed to open log file"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			var stderr bytes.Buffer
+			s := newConfigurationTestServer(t, &stderr)
+			s.applySettings([]byte(test.settings))
+			assert.Assert(t, strings.Contains(stderr.String(), test.logged), stderr.String())
+			// Invalid settings take their default values
+			assert.DeepEqual(t, s.projectService.UserPreferences(), ls.GetDefaultUserPreferences())
+			assert.DeepEqual(t, s.projectService.FormatCodeSettings(), ls.GetDefaultFormatCodeSettings("\n"))
+			assert.Assert(t, s.logger.HasLevel(project.LogLevelVerbose))
+		})
+	}
+}
+
+func TestPreferencesSettings(t *testing.T) {
+	t.Parallel()
+	s := newConfigurationTestServer(t, io.Discard)
+	s.applySettings([]byte(`{"preferences": {"quotePreference": "single", "includeInlayVariableTypeHints": true}}`))
+	preferences := s.projectService.UserPreferences()
+	assert.Equal(t, preferences.QuotePreference, ls.QuotePreferenceSingle)
+	assert.Assert(t, preferences.IncludeInlayVariableTypeHints)
+	assert.Equal(t, preferences.IncludeInlayParameterNameHints, ls.InlayParameterNameHintsNone)
+
+	// Settings that are no longer set take their default values again
+	s.applySettings([]byte(`{}`))
+	assert.DeepEqual(t, s.projectService.UserPreferences(), ls.GetDefaultUserPreferences())
+}
+
+func TestLogSettings(t *testing.T) {
+	t.Parallel()
+	s := newConfigurationTestServer(t, io.Discard)
+	logFile := filepath.Join(t.TempDir(), "tsserver.log")
+	s.applySettings([]byte(`{"logVerbosity": "normal", "logFile": ` + strconv.Quote(logFile) + `}`))
+	assert.Assert(t, s.logger.HasLevel(project.LogLevelNormal))
+	assert.Assert(t, !s.logger.HasLevel(project.LogLevelRequestTime))
+	s.logger.Info("logged to the file")
+
+	s.applySettings([]byte(`{}`))
+	assert.Assert(t, s.logger.HasLevel(project.LogLevelVerbose))
+	s.logger.Info("not logged to the file")
+
+	data, err := os.ReadFile(logFile)
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(data), "logged to the file"), string(data))
+	assert.Assert(t, !strings.Contains(string(data), "not logged to the file"), string(data))
+}
+
+func TestInferredProjectCompilerOptionsSettings(t *testing.T) {
+	t.Parallel()
+	var stderr bytes.Buffer
+	s := newConfigurationTestServer(t, &stderr)
+	s.projectService.OpenFile(scriptFileName, `let x = 1;`, core.ScriptKindTS, "")
+	inferredProject := func() *project.Project {
+		projects := s.projectService.Projects()
+		assert.Equal(t, len(projects), 1)
+		assert.Equal(t, projects[0].Kind(), project.KindInferred)
+		return projects[0]
+	}
+
+	s.applySettings([]byte(`{"inferredProjectCompilerOptions": {"strict": true, "target": "es2020"}}`))
+	options := inferredProject().GetCompilerOptions()
+	assert.Equal(t, options.Strict, core.TSTrue)
+	assert.Equal(t, options.Target, core.ScriptTargetES2020)
+
+	s.applySettings([]byte(`{"inferredProjectCompilerOptions": {"notAnOption": true}}`))
+	assert.Assert(t, strings.Contains(stderr.String(), "Invalid inferred project compiler options"), stderr.String())
+
+	s.applySettings([]byte(`{}`))
+	options = inferredProject().GetCompilerOptions()
+	assert.Equal(t, options.Strict, core.TSUnknown)
+	assert.Equal(t, options.Target, core.ScriptTargetNone)
+}
+
+// TestMaxTsServerMemorySettings is not parallel, as the memory limit is shared by the whole process.
+func TestMaxTsServerMemorySettings(t *testing.T) {
+	limit := debug.SetMemoryLimit(-1)
+	t.Cleanup(func() {
+		debug.SetMemoryLimit(limit)
+	})
+	s := newConfigurationTestServer(t, io.Discard)
+	s.applySettings([]byte(`{"maxTsServerMemory": 3072}`))
+	assert.Equal(t, debug.SetMemoryLimit(-1), int64(3072<<20))
+	s.applySettings([]byte(`{}`))
+	assert.Equal(t, debug.SetMemoryLimit(-1), int64(math.MaxInt64))
+}
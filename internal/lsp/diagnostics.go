@@ -0,0 +1,276 @@
+package lsp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/ls"
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+)
+
+// diagnosticsDelay is how long the server waits for the client to stop making changes before it
+// publishes diagnostics, so that files are not checked on every keystroke.
+const diagnosticsDelay = 200 * time.Millisecond
+
+func (s *Server) handleDocumentDiagnostic(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.DocumentDiagnosticParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	if file == nil {
+		// The document is not part of its project, so there is nothing to check
+		return s.sendResult(req.ID, &lsproto.DocumentDiagnosticReport{
+			RelatedFullDocumentDiagnosticReport: &lsproto.RelatedFullDocumentDiagnosticReport{
+				FullDocumentDiagnosticReport: lsproto.FullDocumentDiagnosticReport{
+					Kind:  lsproto.StringLiteralFull{},
+					Items: []lsproto.Diagnostic{},
+				},
+			},
+		})
+	}
+	lspDiagnostics, err := toLSPDiagnostics(project.Converters(), project.LanguageService().GetDocumentDiagnostics(file.FileName()))
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+	resultID, err := diagnosticsResultID(lspDiagnostics)
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+	if params.PreviousResultId != nil && *params.PreviousResultId == resultID {
+		return s.sendResult(req.ID, &lsproto.DocumentDiagnosticReport{
+			RelatedUnchangedDocumentDiagnosticReport: &lsproto.RelatedUnchangedDocumentDiagnosticReport{
+				UnchangedDocumentDiagnosticReport: lsproto.UnchangedDocumentDiagnosticReport{
+					Kind:     lsproto.StringLiteralUnchanged{},
+					ResultId: resultID,
+				},
+			},
+		})
+	}
+	return s.sendResult(req.ID, &lsproto.DocumentDiagnosticReport{
+		RelatedFullDocumentDiagnosticReport: &lsproto.RelatedFullDocumentDiagnosticReport{
+			FullDocumentDiagnosticReport: lsproto.FullDocumentDiagnosticReport{
+				Kind:     lsproto.StringLiteralFull{},
+				ResultId: &resultID,
+				Items:    lspDiagnostics,
+			},
+		},
+	})
+}
+
+// handleWorkspaceDiagnostic reports the diagnostics of the files of every project, other than
+// default libraries and files from node_modules. A file whose result ID is the one that the client
+// already has is reported as unchanged, so that its diagnostics are not sent again. Each project is
+// released once its files are checked.
+func (s *Server) handleWorkspaceDiagnostic(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.WorkspaceDiagnosticParams)
+	previousResultIDs := make(map[lsproto.DocumentUri]string, len(params.PreviousResultIds))
+	for _, previous := range params.PreviousResultIds {
+		previousResultIDs[previous.Uri] = previous.Value
+	}
+
+	items := []lsproto.WorkspaceDocumentDiagnosticReport{}
+	reported := make(map[lsproto.DocumentUri]struct{})
+	for _, project := range s.getProjects(ctx) {
+		program := project.GetProgram()
+		for _, file := range program.GetSourceFiles() {
+			if program.IsSourceFileDefaultLibrary(file) || strings.Contains(file.FileName(), "/node_modules/") {
+				continue
+			}
+			uri := ls.FileNameToDocumentURI(file.FileName())
+			if _, ok := reported[uri]; ok {
+				continue
+			}
+			reported[uri] = struct{}{}
+			diagnostics := project.LanguageService().GetDocumentDiagnostics(file.FileName())
+			if ctx.Err() != nil {
+				return s.sendError(req.ID, context.Cause(ctx))
+			}
+			lspDiagnostics, err := toLSPDiagnostics(project.Converters(), diagnostics)
+			if err != nil {
+				return s.sendError(req.ID, err)
+			}
+			resultID, err := diagnosticsResultID(lspDiagnostics)
+			if err != nil {
+				return s.sendError(req.ID, err)
+			}
+			if previousResultIDs[uri] == resultID {
+				items = append(items, lsproto.WorkspaceDocumentDiagnosticReport{
+					WorkspaceUnchangedDocumentDiagnosticReport: &lsproto.WorkspaceUnchangedDocumentDiagnosticReport{
+						UnchangedDocumentDiagnosticReport: lsproto.UnchangedDocumentDiagnosticReport{
+							Kind:     lsproto.StringLiteralUnchanged{},
+							ResultId: resultID,
+						},
+						Uri: uri,
+					},
+				})
+				continue
+			}
+			items = append(items, lsproto.WorkspaceDocumentDiagnosticReport{
+				WorkspaceFullDocumentDiagnosticReport: &lsproto.WorkspaceFullDocumentDiagnosticReport{
+					FullDocumentDiagnosticReport: lsproto.FullDocumentDiagnosticReport{
+						Kind:     lsproto.StringLiteralFull{},
+						ResultId: &resultID,
+						Items:    lspDiagnostics,
+					},
+					Uri: uri,
+				},
+			})
+		}
+		project.Release()
+	}
+	return s.sendResult(req.ID, &lsproto.WorkspaceDiagnosticReport{Items: items})
+}
+
+// diagnosticsResultID identifies the diagnostics of a file by a hash of them. A change to any file
+// of a project can change the diagnostics of every other file, so a file's diagnostics are checked
+// again for every report, but the client is only sent those that differ from the ones it has.
+func diagnosticsResultID(diagnostics []lsproto.Diagnostic) (string, error) {
+	data, err := json.Marshal(diagnostics)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func toLSPDiagnostics(converters *ls.Converters, diagnostics []*ast.Diagnostic) ([]lsproto.Diagnostic, error) {
+	lspDiagnostics := make([]lsproto.Diagnostic, len(diagnostics))
+	for i, diag := range diagnostics {
+		lspDiagnostic, err := converters.ToLSPDiagnostic(diag)
+		if err != nil {
+			return nil, err
+		}
+		lspDiagnostics[i] = lspDiagnostic
+	}
+	return lspDiagnostics, nil
+}
+
+// scheduleDiagnostics publishes the diagnostics of the open documents, for a client that does not
+// pull them, once the client has not made a change for diagnosticsDelay. The documents that changed
+// most recently are published first. A change can affect the diagnostics of every file, so it
+// cancels the diagnostics that are being published. uri is the document that changed, if any.
+// It is called with stateMu held.
+func (s *Server) scheduleDiagnostics(uri lsproto.DocumentUri) {
+	if !s.pushDiagnostics {
+		return
+	}
+	s.diagnosticsMu.Lock()
+	defer s.diagnosticsMu.Unlock()
+	if s.diagnosticsStopped {
+		return
+	}
+	if s.diagnosticsCancel != nil {
+		s.diagnosticsCancel(lsproto.ErrContentModified)
+		s.diagnosticsCancel = nil
+	}
+	if uri != "" {
+		s.changedDocuments = slices.Insert(slices.DeleteFunc(s.changedDocuments, func(changed lsproto.DocumentUri) bool {
+			return changed == uri
+		}), 0, uri)
+	}
+	if s.diagnosticsTimer != nil {
+		s.diagnosticsTimer.Stop()
+	}
+	s.diagnosticsTimer = time.AfterFunc(diagnosticsDelay, s.publishDiagnostics)
+}
+
+// stopDiagnostics stops publishing diagnostics, when the server shuts down.
+func (s *Server) stopDiagnostics() {
+	s.diagnosticsMu.Lock()
+	defer s.diagnosticsMu.Unlock()
+	s.diagnosticsStopped = true
+	if s.diagnosticsTimer != nil {
+		s.diagnosticsTimer.Stop()
+	}
+	if s.diagnosticsCancel != nil {
+		s.diagnosticsCancel(lsproto.ErrRequestCancelled)
+	}
+}
+
+func (s *Server) publishDiagnostics() {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	s.diagnosticsMu.Lock()
+	if s.diagnosticsStopped {
+		s.diagnosticsMu.Unlock()
+		return
+	}
+	s.diagnosticsCancel = cancel
+	uris := slices.Clone(s.changedDocuments)
+	s.diagnosticsMu.Unlock()
+
+	s.stateMu.Lock()
+	uris = slices.DeleteFunc(uris, func(uri lsproto.DocumentUri) bool {
+		_, ok := s.openDocuments[uri]
+		return !ok
+	})
+	for uri := range s.openDocuments {
+		if !slices.Contains(uris, uri) {
+			uris = append(uris, uri)
+		}
+	}
+	s.stateMu.Unlock()
+
+	for _, uri := range uris {
+		if err := s.publishDocumentDiagnostics(ctx, cancel, uri); err != nil {
+			if ctx.Err() == nil {
+				s.setRequestError(err)
+			}
+			return
+		}
+		s.diagnosticsMu.Lock()
+		s.changedDocuments = slices.DeleteFunc(s.changedDocuments, func(changed lsproto.DocumentUri) bool {
+			return changed == uri
+		})
+		s.diagnosticsMu.Unlock()
+	}
+}
+
+// publishDocumentDiagnostics publishes the diagnostics of an open document. It returns the cause of
+// ctx if it is canceled, which is checked while stateMu is held, so that a document that has been
+// closed or changed since is not published.
+func (s *Server) publishDocumentDiagnostics(ctx context.Context, cancel context.CancelCauseFunc, uri lsproto.DocumentUri) error {
+	r := newRequest(ctx, cancel)
+	defer r.release()
+
+	s.stateMu.Lock()
+	if ctx.Err() != nil {
+		s.stateMu.Unlock()
+		return context.Cause(ctx)
+	}
+	version := s.openDocuments[uri]
+	file, project, _ := s.takeFileAndProjects(r, uri, false /*allProjects*/)
+	s.stateMu.Unlock()
+
+	var diagnostics []*ast.Diagnostic
+	if file != nil {
+		diagnostics = project.LanguageService().GetDocumentDiagnostics(file.FileName())
+	}
+	if ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+	lspDiagnostics, err := toLSPDiagnostics(project.Converters(), diagnostics)
+	if err != nil {
+		return err
+	}
+	return s.sendNotification(lsproto.MethodTextDocumentPublishDiagnostics, &lsproto.PublishDiagnosticsParams{
+		Uri:         uri,
+		Version:     &version,
+		Diagnostics: lspDiagnostics,
+	})
+}
+
+// clearDiagnostics publishes no diagnostics for a document that has been closed.
+func (s *Server) clearDiagnostics(uri lsproto.DocumentUri) error {
+	if !s.pushDiagnostics {
+		return nil
+	}
+	return s.sendNotification(lsproto.MethodTextDocumentPublishDiagnostics, &lsproto.PublishDiagnosticsParams{
+		Uri:         uri,
+		Diagnostics: []lsproto.Diagnostic{},
+	})
+}
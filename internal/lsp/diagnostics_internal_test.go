@@ -0,0 +1,32 @@
+package lsp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"gotest.tools/v3/assert"
+)
+
+func TestChangeCancelsPublishDiagnostics(t *testing.T) {
+	t.Parallel()
+	s := &Server{pushDiagnostics: true}
+	t.Cleanup(s.stopDiagnostics)
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	s.diagnosticsMu.Lock()
+	s.diagnosticsCancel = cancel
+	s.diagnosticsMu.Unlock()
+
+	s.scheduleDiagnostics("file:///a.ts")
+	assert.ErrorIs(t, context.Cause(ctx), lsproto.ErrContentModified)
+	// The document is not published once its diagnostics have been canceled
+	assert.ErrorIs(t, s.publishDocumentDiagnostics(ctx, cancel, "file:///a.ts"), lsproto.ErrContentModified)
+
+	s.scheduleDiagnostics("file:///b.ts")
+	s.scheduleDiagnostics("file:///a.ts")
+	s.diagnosticsMu.Lock()
+	defer s.diagnosticsMu.Unlock()
+	assert.Assert(t, s.diagnosticsCancel == nil)
+	assert.DeepEqual(t, s.changedDocuments, []lsproto.DocumentUri{"file:///a.ts", "file:///b.ts"})
+}
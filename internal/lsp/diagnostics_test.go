@@ -0,0 +1,215 @@
+package lsp_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"gotest.tools/v3/assert"
+)
+
+const (
+	aURI lsproto.DocumentUri = "file:///home/src/project/a.ts"
+	bURI lsproto.DocumentUri = "file:///home/src/project/b.ts"
+	cURI lsproto.DocumentUri = "file:///home/src/project/c.ts"
+)
+
+var diagnosticsTestFiles = map[string]string{
+	"/home/src/project/tsconfig.json": `{ "files": ["a.ts", "b.ts", "c.ts"] }`,
+	"/home/src/project/a.ts":          `export const x: number = 1;`,
+	"/home/src/project/b.ts":          `import { x } from "./a"; export const s: string = x;`,
+	"/home/src/project/c.ts":          `export const c = 1;`,
+}
+
+// pullCapabilities are the capabilities of a client that pulls diagnostics.
+var pullCapabilities = map[string]any{"textDocument": map[string]any{"diagnostic": map[string]any{}}}
+
+// publishedDiagnostics returns the diagnostics in the next textDocument/publishDiagnostics notification.
+func (c *testClient) publishedDiagnostics() *lsproto.PublishDiagnosticsParams {
+	c.t.Helper()
+	msg := c.next(func(msg *message) bool {
+		return msg.Method == lsproto.MethodTextDocumentPublishDiagnostics
+	})
+	var params lsproto.PublishDiagnosticsParams
+	assert.NilError(c.t, json.Unmarshal(msg.Params, &params))
+	return &params
+}
+
+// noPublishedDiagnostics checks that the server does not publish diagnostics for a while.
+func (c *testClient) noPublishedDiagnostics(d time.Duration) {
+	c.t.Helper()
+	timeout := time.After(d)
+	for {
+		select {
+		case msg := <-c.messages:
+			assert.Assert(c.t, msg.Method != lsproto.MethodTextDocumentPublishDiagnostics, "unexpected diagnostics for %s", string(msg.Params))
+		case <-timeout:
+			return
+		}
+	}
+}
+
+// waitForPublishedDiagnostics waits until the server has stopped publishing diagnostics.
+func (c *testClient) waitForPublishedDiagnostics() {
+	c.t.Helper()
+	c.publishedDiagnostics()
+	for {
+		select {
+		case _, ok := <-c.messages:
+			assert.Assert(c.t, ok, "the server closed the connection")
+		case <-time.After(2 * 200 * time.Millisecond):
+			return
+		}
+	}
+}
+
+func (c *testClient) workspaceDiagnostics(previousResultIDs map[lsproto.DocumentUri]string) map[lsproto.DocumentUri]lsproto.WorkspaceDocumentDiagnosticReport {
+	c.t.Helper()
+	previous := []map[string]any{}
+	for uri, id := range previousResultIDs {
+		previous = append(previous, map[string]any{"uri": uri, "value": id})
+	}
+	msg := c.response(c.request(lsproto.MethodWorkspaceDiagnostic, map[string]any{"previousResultIds": previous}))
+	assert.Assert(c.t, msg.Error == nil)
+	var report lsproto.WorkspaceDiagnosticReport
+	assert.NilError(c.t, json.Unmarshal(msg.Result, &report))
+	reports := make(map[lsproto.DocumentUri]lsproto.WorkspaceDocumentDiagnosticReport, len(report.Items))
+	for _, item := range report.Items {
+		if full := item.WorkspaceFullDocumentDiagnosticReport; full != nil {
+			reports[full.Uri] = item
+		} else {
+			reports[item.WorkspaceUnchangedDocumentDiagnosticReport.Uri] = item
+		}
+	}
+	return reports
+}
+
+func TestPublishDiagnosticsAfterChanges(t *testing.T) {
+	t.Parallel()
+	c := startServer(t, diagnosticsTestFiles, map[string]any{})
+	c.open(bURI, diagnosticsTestFiles["/home/src/project/b.ts"])
+	c.change(bURI, 2, `const s: string = 1;`)
+	c.change(bURI, 3, `const s: string = 1; const n: number = "";`)
+
+	// The document is published once, after the client has stopped making changes
+	params := c.publishedDiagnostics()
+	assert.Equal(t, params.Uri, bURI)
+	assert.Equal(t, *params.Version, int32(3))
+	assert.Equal(t, len(params.Diagnostics), 2)
+	c.noPublishedDiagnostics(2 * 200 * time.Millisecond)
+}
+
+func TestPublishChangedDiagnosticsFirst(t *testing.T) {
+	t.Parallel()
+	c := startServer(t, diagnosticsTestFiles, map[string]any{})
+	c.open(aURI, diagnosticsTestFiles["/home/src/project/a.ts"])
+	c.open(bURI, diagnosticsTestFiles["/home/src/project/b.ts"])
+	c.open(cURI, diagnosticsTestFiles["/home/src/project/c.ts"])
+	c.waitForPublishedDiagnostics()
+
+	// A change to one document can change the diagnostics of the others, which are published after
+	// the documents that changed
+	c.change(bURI, 2, `import { x } from "./a"; export const s: string = x; // changed`)
+	c.change(aURI, 2, `export const x: string = "";`)
+	var uris []lsproto.DocumentUri
+	published := map[lsproto.DocumentUri]*lsproto.PublishDiagnosticsParams{}
+	for range 3 {
+		params := c.publishedDiagnostics()
+		uris = append(uris, params.Uri)
+		published[params.Uri] = params
+	}
+	assert.DeepEqual(t, uris, []lsproto.DocumentUri{aURI, bURI, cURI})
+	assert.Equal(t, *published[aURI].Version, int32(2))
+	assert.Equal(t, *published[bURI].Version, int32(2))
+	assert.Equal(t, len(published[bURI].Diagnostics), 0)
+}
+
+func TestWorkspaceDiagnosticsUnchanged(t *testing.T) {
+	t.Parallel()
+	c := startServer(t, diagnosticsTestFiles, pullCapabilities)
+	c.open(aURI, diagnosticsTestFiles["/home/src/project/a.ts"])
+
+	reports := c.workspaceDiagnostics(nil)
+	resultIDs := map[lsproto.DocumentUri]string{}
+	for _, uri := range []lsproto.DocumentUri{aURI, bURI, cURI} {
+		full := reports[uri].WorkspaceFullDocumentDiagnosticReport
+		assert.Assert(t, full != nil, uri)
+		resultIDs[uri] = *full.ResultId
+	}
+	assert.Equal(t, len(reports[bURI].WorkspaceFullDocumentDiagnosticReport.Items), 1)
+
+	// Diagnostics that the client already has are not sent again
+	reports = c.workspaceDiagnostics(resultIDs)
+	for _, uri := range []lsproto.DocumentUri{aURI, bURI, cURI} {
+		assert.Assert(t, reports[uri].WorkspaceUnchangedDocumentDiagnosticReport != nil, uri)
+	}
+
+	// A change to a.ts does not change its own diagnostics, but fixes those of b.ts
+	c.change(aURI, 2, `export const x: string = "";`)
+	reports = c.workspaceDiagnostics(resultIDs)
+	assert.Assert(t, reports[aURI].WorkspaceUnchangedDocumentDiagnosticReport != nil)
+	assert.Assert(t, reports[cURI].WorkspaceUnchangedDocumentDiagnosticReport != nil)
+	full := reports[bURI].WorkspaceFullDocumentDiagnosticReport
+	assert.Assert(t, full != nil)
+	assert.Equal(t, len(full.Items), 0)
+	assert.Assert(t, *full.ResultId != resultIDs[bURI])
+}
+
+func TestDocumentDiagnosticsUnchanged(t *testing.T) {
+	t.Parallel()
+	c := startServer(t, diagnosticsTestFiles, pullCapabilities)
+	c.open(bURI, diagnosticsTestFiles["/home/src/project/b.ts"])
+
+	documentDiagnostics := func(previousResultID *string) *lsproto.DocumentDiagnosticReport {
+		params := map[string]any{"textDocument": map[string]any{"uri": bURI}}
+		if previousResultID != nil {
+			params["previousResultId"] = *previousResultID
+		}
+		msg := c.response(c.request(lsproto.MethodTextDocumentDiagnostic, params))
+		assert.Assert(t, msg.Error == nil)
+		var report lsproto.DocumentDiagnosticReport
+		assert.NilError(t, json.Unmarshal(msg.Result, &report))
+		return &report
+	}
+
+	full := documentDiagnostics(nil).RelatedFullDocumentDiagnosticReport
+	assert.Assert(t, full != nil)
+	assert.Equal(t, len(full.Items), 1)
+
+	// An edit that does not change the diagnostics leaves them unchanged
+	c.change(bURI, 2, `import { x } from "./a"; export const s: string = x; // unchanged`)
+	assert.Assert(t, documentDiagnostics(full.ResultId).RelatedUnchangedDocumentDiagnosticReport != nil)
+
+	c.change(bURI, 3, `import { x } from "./a"; export const s: number = x;`)
+	changed := documentDiagnostics(full.ResultId).RelatedFullDocumentDiagnosticReport
+	assert.Assert(t, changed != nil)
+	assert.Equal(t, len(changed.Items), 0)
+}
+
+func TestDiagnosticsForFileOutsideProgram(t *testing.T) {
+	t.Parallel()
+	const notesURI lsproto.DocumentUri = "file:///home/src/project/notes.txt"
+	openNotes := func(c *testClient) {
+		c.notify(lsproto.MethodTextDocumentDidOpen, map[string]any{
+			"textDocument": map[string]any{"uri": notesURI, "languageId": "plaintext", "version": 1, "text": "notes"},
+		})
+	}
+
+	c := startServer(t, diagnosticsTestFiles, pullCapabilities)
+	openNotes(c)
+	msg := c.response(c.request(lsproto.MethodTextDocumentDiagnostic, map[string]any{"textDocument": map[string]any{"uri": notesURI}}))
+	assert.Assert(t, msg.Error == nil)
+	var report lsproto.DocumentDiagnosticReport
+	assert.NilError(t, json.Unmarshal(msg.Result, &report))
+	full := report.RelatedFullDocumentDiagnosticReport
+	assert.Assert(t, full != nil)
+	assert.Assert(t, full.ResultId == nil)
+	assert.Equal(t, len(full.Items), 0)
+
+	c = startServer(t, diagnosticsTestFiles, map[string]any{})
+	openNotes(c)
+	params := c.publishedDiagnostics()
+	assert.Equal(t, params.Uri, notesURI)
+	assert.Equal(t, len(params.Diagnostics), 0)
+}
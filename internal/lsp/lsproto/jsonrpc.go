@@ -51,11 +51,27 @@ func (id *ID) MustInt() int32 {
 	return id.int
 }
 
+// NewIDString returns the ID of a request that the server sends to the client.
+func NewIDString(str string) *ID {
+	return &ID{str: str}
+}
+
+// ToID returns the ID of the request that v refers to, as in the params of $/cancelRequest.
+func (v IntegerOrString) ToID() ID {
+	if v.String != nil {
+		return ID{str: *v.String}
+	}
+	if v.Integer != nil {
+		return ID{int: *v.Integer}
+	}
+	return ID{}
+}
+
 // TODO(jakebailey): NotificationMessage? Use RequestMessage without ID?
 
 type RequestMessage struct {
 	JSONRPC JSONRPCVersion `json:"jsonrpc"`
-	ID      *ID            `json:"id"`
+	ID      *ID            `json:"id,omitempty"`
 	Method  Method         `json:"method"`
 	Params  any            `json:"params"`
 }
@@ -66,6 +82,8 @@ func (r *RequestMessage) UnmarshalJSON(data []byte) error {
 		ID      *ID             `json:"id"`
 		Method  Method          `json:"method"`
 		Params  json.RawMessage `json:"params"`
+		Result  json.RawMessage `json:"result"`
+		Error   *ResponseError  `json:"error"`
 	}
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
@@ -77,6 +95,16 @@ func (r *RequestMessage) UnmarshalJSON(data []byte) error {
 		// These methods have no params.
 		return nil
 	}
+	if r.Method == "" && r.ID != nil {
+		// This is the response to a request that the server sent, which is read as a message
+		// without a method whose params are either the *ResponseError or the raw result.
+		if raw.Error != nil {
+			r.Params = raw.Error
+		} else {
+			r.Params = raw.Result
+		}
+		return nil
+	}
 
 	if strings.HasPrefix(string(r.Method), "@ts/") {
 		r.Params = raw.Params
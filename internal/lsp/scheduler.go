@@ -0,0 +1,250 @@
+package lsp
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/microsoft/typescript-go/internal/ls"
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"github.com/microsoft/typescript-go/internal/project"
+)
+
+// request is a request that is being handled on its own goroutine.
+type request struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+
+	// started is closed once the request has taken the snapshots it reads, or has finished
+	// without taking any.
+	started     chan struct{}
+	startedOnce sync.Once
+
+	// uri is the document that the request is about, if any. It is guarded by Server.requestsMu.
+	uri lsproto.DocumentUri
+	// snapshots are the snapshots that the request has taken, which are released when it finishes.
+	snapshots []*project.Snapshot
+}
+
+// newRequest returns a request that is handled with ctx and canceled with cancel.
+func newRequest(ctx context.Context, cancel context.CancelCauseFunc) *request {
+	r := &request{cancel: cancel, started: make(chan struct{})}
+	r.ctx = context.WithValue(ctx, requestContextKey{}, r)
+	return r
+}
+
+func (r *request) markStarted() {
+	r.startedOnce.Do(func() {
+		close(r.started)
+	})
+}
+
+// release releases the snapshots that the request has taken.
+func (r *request) release() {
+	for _, snapshot := range r.snapshots {
+		snapshot.Release()
+	}
+}
+
+type requestContextKey struct{}
+
+func getRequestFromContext(ctx context.Context) *request {
+	r, _ := ctx.Value(requestContextKey{}).(*request)
+	return r
+}
+
+// dispatch handles a message after initialization. Notifications change the state of the server,
+// so they are handled here, in order. A request is started on its own goroutine, and dispatch
+// returns once the request has taken its snapshots, so that the request sees every notification
+// that arrived before it and none that arrived after it.
+func (s *Server) dispatch(req *lsproto.RequestMessage) error {
+	if req.Method == "" {
+		s.stateMu.Lock()
+		defer s.stateMu.Unlock()
+		s.handleClientResponse(req)
+		return nil
+	}
+	if req.Method == lsproto.MethodCancelRequest {
+		s.cancelRequest(req.Params.(*lsproto.CancelParams).Id.ToID())
+		return nil
+	}
+	if req.ID == nil {
+		s.stateMu.Lock()
+		defer s.stateMu.Unlock()
+		return s.handleMessage(context.Background(), req)
+	}
+	if req.Method == lsproto.MethodShutdown {
+		s.pending.Wait()
+		s.stateMu.Lock()
+		defer s.stateMu.Unlock()
+		return s.handleMessage(context.Background(), req)
+	}
+
+	r := newRequest(context.WithCancelCause(context.Background()))
+
+	s.requestsMu.Lock()
+	s.requests[*req.ID] = r
+	s.requestsMu.Unlock()
+
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+		defer r.markStarted()
+		start := time.Now()
+		var err error
+		if r.ctx.Err() == nil {
+			err = s.handleRequest(r.ctx, req)
+		} else {
+			err = s.sendError(req.ID, context.Cause(r.ctx))
+		}
+		r.release()
+		s.requestsMu.Lock()
+		delete(s.requests, *req.ID)
+		s.requestsMu.Unlock()
+		s.logger.PerfTrace(fmt.Sprintf("%s: %s", req.Method, time.Since(start)))
+		if err != nil {
+			s.setRequestError(err)
+		}
+	}()
+	<-r.started
+	return nil
+}
+
+// handleRequest handles a request on its own goroutine. A panic while handling it is reported to the
+// client as an internal error rather than ending the server, as the other requests and the state of
+// the server are unaffected by it.
+func (s *Server) handleRequest(ctx context.Context, req *lsproto.RequestMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.Log(fmt.Sprintf("panic handling %s: %v\n%s", req.Method, r, debug.Stack()))
+			err = s.sendError(req.ID, fmt.Errorf("%w: panic handling %s: %v", lsproto.ErrInternalError, req.Method, r))
+		}
+	}()
+	return s.handleMessage(ctx, req)
+}
+
+func (s *Server) getRequest(id lsproto.ID) *request {
+	s.requestsMu.Lock()
+	defer s.requestsMu.Unlock()
+	return s.requests[id]
+}
+
+// cancelRequest handles $/cancelRequest. A request that has already finished is ignored.
+func (s *Server) cancelRequest(id lsproto.ID) {
+	if r := s.getRequest(id); r != nil {
+		r.cancel(lsproto.ErrRequestCancelled)
+	}
+}
+
+// cancelRequestsForDocument cancels the requests about a document that is about to change, since
+// their results would refer to text that the client no longer has.
+func (s *Server) cancelRequestsForDocument(uri lsproto.DocumentUri) {
+	s.requestsMu.Lock()
+	defer s.requestsMu.Unlock()
+	for _, r := range s.requests {
+		if r.uri == uri {
+			r.cancel(lsproto.ErrContentModified)
+		}
+	}
+}
+
+// cancelRequests cancels every request, when the connection is closed.
+func (s *Server) cancelRequests() {
+	s.requestsMu.Lock()
+	defer s.requestsMu.Unlock()
+	for _, r := range s.requests {
+		r.cancel(lsproto.ErrRequestCancelled)
+	}
+}
+
+func (s *Server) setRequestError(err error) {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+func (s *Server) requestError() error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	return s.err
+}
+
+// getFileAndProject takes a snapshot of the default project of a document for the request of ctx,
+// and returns the document as of the snapshot.
+func (s *Server) getFileAndProject(ctx context.Context, uri lsproto.DocumentUri) (*project.SnapshotScriptInfo, *project.Snapshot) {
+	file, snapshot, _ := s.getFileAndProjects(ctx, uri, false /*allProjects*/)
+	return file, snapshot
+}
+
+// getFileAndProjects is like getFileAndProject, but also takes snapshots of all the projects if
+// allProjects is set.
+func (s *Server) getFileAndProjects(ctx context.Context, uri lsproto.DocumentUri, allProjects bool) (*project.SnapshotScriptInfo, *project.Snapshot, []*project.Snapshot) {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	return s.takeFileAndProjects(getRequestFromContext(ctx), uri, allProjects)
+}
+
+// takeFileAndProjects is getFileAndProjects for a caller that holds stateMu.
+func (s *Server) takeFileAndProjects(r *request, uri lsproto.DocumentUri, allProjects bool) (*project.SnapshotScriptInfo, *project.Snapshot, []*project.Snapshot) {
+	s.requestsMu.Lock()
+	r.uri = uri
+	s.requestsMu.Unlock()
+
+	fileName := ls.DocumentURIToFileName(uri)
+	_, defaultProject := s.projectService.EnsureDefaultProjectForFile(fileName)
+	var snapshot *project.Snapshot
+	var snapshots []*project.Snapshot
+	if allProjects {
+		snapshots = s.takeSnapshots(r, s.projectService.Projects())
+		snapshot = snapshots[slices.IndexFunc(snapshots, func(snapshot *project.Snapshot) bool {
+			return snapshot.Name() == defaultProject.Name()
+		})]
+	} else {
+		snapshot = s.takeSnapshots(r, []*project.Project{defaultProject})[0]
+	}
+	r.markStarted()
+	return snapshot.GetFile(fileName), snapshot, snapshots
+}
+
+// getProjects takes snapshots of all the projects for the request of ctx.
+func (s *Server) getProjects(ctx context.Context) []*project.Snapshot {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	r := getRequestFromContext(ctx)
+	snapshots := s.takeSnapshots(r, s.projectService.Projects())
+	r.markStarted()
+	return snapshots
+}
+
+// takeSnapshots takes snapshots of projects, ordered by name so that the results of a request
+// that uses several projects are in a stable order.
+func (s *Server) takeSnapshots(r *request, projects []*project.Project) []*project.Snapshot {
+	snapshots := make([]*project.Snapshot, len(projects))
+	for i, p := range projects {
+		snapshots[i] = p.Snapshot(r.ctx)
+	}
+	slices.SortFunc(snapshots, func(a, b *project.Snapshot) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	r.snapshots = append(r.snapshots, snapshots...)
+	return snapshots
+}
+
+// newSnapshotsConverters returns converters for the files of all the given snapshots, for results
+// that span projects.
+func (s *Server) newSnapshotsConverters(snapshots []*project.Snapshot) *ls.Converters {
+	return ls.NewConverters(s.positionEncoding, func(fileName string) ls.ScriptInfo {
+		for _, snapshot := range snapshots {
+			if info := snapshot.GetScriptInfo(fileName); info != nil {
+				return info
+			}
+		}
+		return nil
+	})
+}
@@ -1,14 +1,20 @@
 package lsp
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/microsoft/typescript-go/internal/ast"
 	"github.com/microsoft/typescript-go/internal/core"
 	"github.com/microsoft/typescript-go/internal/ls"
 	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
@@ -50,8 +56,20 @@ type Server struct {
 
 	stderr io.Writer
 
-	requestMethod string
-	requestTime   time.Time
+	// writeMu serializes the responses of requests that are handled concurrently.
+	writeMu sync.Mutex
+	// stateMu is held while a notification changes the projects, and while a request takes
+	// snapshots of them.
+	stateMu sync.Mutex
+
+	requestsMu sync.Mutex
+	// requests holds the requests that are being handled, which can be canceled.
+	requests map[lsproto.ID]*request
+	pending  sync.WaitGroup
+
+	errMu sync.Mutex
+	// err is the first error that a request failed with, which ends Run.
+	err error
 
 	cwd                string
 	newLine            core.NewLineKind
@@ -60,10 +78,50 @@ type Server struct {
 
 	initializeParams *lsproto.InitializeParams
 	positionEncoding lsproto.PositionEncodingKind
+	watchEnabled     bool
+	// pushDiagnostics is set when the client does not pull diagnostics, so that the server publishes
+	// them.
+	pushDiagnostics bool
+
+	// clientRequestID numbers the requests that the server sends to the client.
+	clientRequestID atomic.Int32
+	// clientRequests are the requests that the client has not responded to yet. It is guarded by
+	// stateMu.
+	clientRequests map[lsproto.ID]*clientRequest
 
 	logger         *project.Logger
 	projectService *project.Service
 	converters     *ls.Converters
+	// openDocuments holds the versions of the open documents. It is guarded by stateMu.
+	openDocuments map[lsproto.DocumentUri]int32
+
+	diagnosticsMu      sync.Mutex
+	diagnosticsTimer   *time.Timer
+	diagnosticsCancel  context.CancelCauseFunc
+	diagnosticsStopped bool
+	// changedDocuments are the documents that have changed since their diagnostics were last
+	// published, most recently changed first.
+	changedDocuments []lsproto.DocumentUri
+
+	// semanticTokens holds the last full semantic tokens result of each document, which
+	// delta requests are computed against.
+	semanticTokensMu       sync.Mutex
+	semanticTokens         map[lsproto.DocumentUri]*semanticTokensResult
+	semanticTokensResultID int
+
+	// inferredProjectCompilerOptions are the settings that the compiler options of the inferred
+	// projects were last set from.
+	inferredProjectCompilerOptions json.RawMessage
+}
+
+type clientRequest struct {
+	method   lsproto.Method
+	onResult func(result json.RawMessage)
+}
+
+type semanticTokensResult struct {
+	resultID string
+	data     []uint32
 }
 
 // FS implements project.ProjectServiceHost.
@@ -91,9 +149,58 @@ func (s *Server) Trace(msg string) {
 	s.Log(msg)
 }
 
-func (s *Server) Run() error {
+// Client implements project.ProjectServiceHost.
+func (s *Server) Client() project.Client {
+	if !s.watchEnabled {
+		return nil
+	}
+	return s
+}
+
+// WatchFiles implements project.Client. The watchers are registered without waiting for the client
+// to respond, since responses are read by the loop that is handling the current message.
+func (s *Server) WatchFiles(watchers []lsproto.FileSystemWatcher) (project.WatcherHandle, error) {
+	watcherID := fmt.Sprintf("watcher-%d", s.clientRequestID.Add(1))
+	if err := s.sendRequest(lsproto.MethodClientRegisterCapability, &lsproto.RegistrationParams{
+		Registrations: []lsproto.Registration{
+			{
+				Id:     watcherID,
+				Method: string(lsproto.MethodWorkspaceDidChangeWatchedFiles),
+				RegisterOptions: ptrTo(any(lsproto.DidChangeWatchedFilesRegistrationOptions{
+					Watchers: watchers,
+				})),
+			},
+		},
+	}, nil /*onResult*/); err != nil {
+		return "", err
+	}
+	return project.WatcherHandle(watcherID), nil
+}
+
+// UnwatchFiles implements project.Client.
+func (s *Server) UnwatchFiles(handle project.WatcherHandle) error {
+	return s.sendRequest(lsproto.MethodClientUnregisterCapability, &lsproto.UnregistrationParams{
+		Unregisterations: []lsproto.Unregistration{
+			{
+				Id:     string(handle),
+				Method: string(lsproto.MethodWorkspaceDidChangeWatchedFiles),
+			},
+		},
+	}, nil /*onResult*/)
+}
+
+// Run reads and handles messages until the client closes the connection or ctx is canceled, in
+// which case it returns the cause of ctx. Notifications are handled in the order they arrive. Each
+// request is handled on its own goroutine against snapshots of the projects as of when it arrived,
+// so that a slow request does not hold up the others.
+func (s *Server) Run(ctx context.Context) error {
+	defer s.pending.Wait()
+	defer s.cancelRequests()
 	for {
-		req, err := s.read()
+		if err := s.requestError(); err != nil {
+			return err
+		}
+		req, err := s.readContext(ctx)
 		if err != nil {
 			if errors.Is(err, lsproto.ErrInvalidRequest) {
 				if err := s.sendError(nil, err); err != nil {
@@ -117,12 +224,32 @@ func (s *Server) Run() error {
 			continue
 		}
 
-		if err := s.handleMessage(req); err != nil {
+		if err := s.dispatch(req); err != nil {
 			return err
 		}
 	}
 }
 
+// readContext reads the next message, or returns the cause of ctx if it is canceled first. A read
+// cannot be interrupted, so one that ctx cancels is left to finish in the background.
+func (s *Server) readContext(ctx context.Context) (*lsproto.RequestMessage, error) {
+	type result struct {
+		req *lsproto.RequestMessage
+		err error
+	}
+	results := make(chan result, 1)
+	go func() {
+		req, err := s.read()
+		results <- result{req, err}
+	}()
+	select {
+	case r := <-results:
+		return r.req, r.err
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	}
+}
+
 func (s *Server) read() (*lsproto.RequestMessage, error) {
 	data, err := s.r.Read()
 	if err != nil {
@@ -137,6 +264,42 @@ func (s *Server) read() (*lsproto.RequestMessage, error) {
 	return req, nil
 }
 
+// sendRequest sends a request to the client. Unless onResult is nil, it is called with the result
+// once the client responds, with stateMu held. It must be called with stateMu held.
+func (s *Server) sendRequest(method lsproto.Method, params any, onResult func(result json.RawMessage)) error {
+	id := lsproto.NewIDString(fmt.Sprintf("ts%d", s.clientRequestID.Add(1)))
+	s.clientRequests[*id] = &clientRequest{method: method, onResult: onResult}
+	return s.write(&lsproto.RequestMessage{
+		ID:     id,
+		Method: method,
+		Params: params,
+	})
+}
+
+// handleClientResponse handles the response to a request that the server sent.
+func (s *Server) handleClientResponse(resp *lsproto.RequestMessage) {
+	r, ok := s.clientRequests[*resp.ID]
+	if !ok {
+		return
+	}
+	delete(s.clientRequests, *resp.ID)
+	switch result := resp.Params.(type) {
+	case *lsproto.ResponseError:
+		s.logger.Error(fmt.Sprintf("%s failed: %s", r.method, result.Message))
+	case json.RawMessage:
+		if r.onResult != nil {
+			r.onResult(result)
+		}
+	}
+}
+
+func (s *Server) sendNotification(method lsproto.Method, params any) error {
+	return s.write(&lsproto.RequestMessage{
+		Method: method,
+		Params: params,
+	})
+}
+
 func (s *Server) sendResult(id *lsproto.ID, result any) error {
 	return s.sendResponse(&lsproto.ResponseMessage{
 		ID:     id,
@@ -145,35 +308,49 @@ func (s *Server) sendResult(id *lsproto.ID, result any) error {
 }
 
 func (s *Server) sendError(id *lsproto.ID, err error) error {
+	return s.sendResponse(&lsproto.ResponseMessage{
+		ID:    id,
+		Error: toResponseError(err),
+	})
+}
+
+func toResponseError(err error) *lsproto.ResponseError {
 	code := lsproto.ErrInternalError.Code
 	if errCode := (*lsproto.ErrorCode)(nil); errors.As(err, &errCode) {
 		code = errCode.Code
 	}
 	// TODO(jakebailey): error data
-	return s.sendResponse(&lsproto.ResponseMessage{
-		ID: id,
-		Error: &lsproto.ResponseError{
-			Code:    code,
-			Message: err.Error(),
-		},
-	})
+	return &lsproto.ResponseError{
+		Code:    code,
+		Message: err.Error(),
+	}
 }
 
+// sendResponse sends the response to a request, or the error that the request was canceled
+// with if it was canceled before it finished.
 func (s *Server) sendResponse(resp *lsproto.ResponseMessage) error {
-	if !s.requestTime.IsZero() {
-		s.logger.PerfTrace(fmt.Sprintf("%s: %s", s.requestMethod, time.Since(s.requestTime)))
+	if resp.ID != nil {
+		if r := s.getRequest(*resp.ID); r != nil && r.ctx.Err() != nil {
+			resp = &lsproto.ResponseMessage{
+				ID:    resp.ID,
+				Error: toResponseError(context.Cause(r.ctx)),
+			}
+		}
 	}
-	data, err := json.Marshal(resp)
+	return s.write(resp)
+}
+
+func (s *Server) write(msg any) error {
+	data, err := json.Marshal(msg)
 	if err != nil {
 		return err
 	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
 	return s.w.Write(data)
 }
 
-func (s *Server) handleMessage(req *lsproto.RequestMessage) error {
-	s.requestTime = time.Now()
-	s.requestMethod = string(req.Method)
-
+func (s *Server) handleMessage(ctx context.Context, req *lsproto.RequestMessage) error {
 	params := req.Params
 	switch params.(type) {
 	case *lsproto.InitializeParams:
@@ -188,15 +365,78 @@ func (s *Server) handleMessage(req *lsproto.RequestMessage) error {
 		return s.handleDidSave(req)
 	case *lsproto.DidCloseTextDocumentParams:
 		return s.handleDidClose(req)
+	case *lsproto.DidChangeConfigurationParams:
+		return s.handleDidChangeConfiguration(req)
+	case *lsproto.DidChangeWatchedFilesParams:
+		return s.handleDidChangeWatchedFiles(req)
 	case *lsproto.DocumentDiagnosticParams:
-		return s.handleDocumentDiagnostic(req)
+		return s.handleDocumentDiagnostic(ctx, req)
+	case *lsproto.WorkspaceDiagnosticParams:
+		return s.handleWorkspaceDiagnostic(ctx, req)
 	case *lsproto.HoverParams:
-		return s.handleHover(req)
+		return s.handleHover(ctx, req)
 	case *lsproto.DefinitionParams:
-		return s.handleDefinition(req)
+		return s.handleDefinition(ctx, req)
+	case *lsproto.DeclarationParams:
+		return s.handleDeclaration(ctx, req)
+	case *lsproto.TypeDefinitionParams:
+		return s.handleTypeDefinition(ctx, req)
+	case *lsproto.ImplementationParams:
+		return s.handleImplementation(ctx, req)
+	case *lsproto.CompletionParams:
+		return s.handleCompletion(ctx, req)
+	case *lsproto.CompletionItem:
+		return s.handleCompletionItemResolve(ctx, req)
+	case *lsproto.SignatureHelpParams:
+		return s.handleSignatureHelp(ctx, req)
+	case *lsproto.ReferenceParams:
+		return s.handleReferences(ctx, req)
+	case *lsproto.DocumentHighlightParams:
+		return s.handleDocumentHighlight(ctx, req)
+	case *lsproto.PrepareRenameParams:
+		return s.handlePrepareRename(ctx, req)
+	case *lsproto.RenameParams:
+		return s.handleRename(ctx, req)
+	case *lsproto.CodeActionParams:
+		return s.handleCodeAction(ctx, req)
+	case *lsproto.DocumentSymbolParams:
+		return s.handleDocumentSymbol(ctx, req)
+	case *lsproto.WorkspaceSymbolParams:
+		return s.handleWorkspaceSymbol(ctx, req)
+	case *lsproto.SemanticTokensParams:
+		return s.handleSemanticTokensFull(ctx, req)
+	case *lsproto.SemanticTokensDeltaParams:
+		return s.handleSemanticTokensFullDelta(ctx, req)
+	case *lsproto.SemanticTokensRangeParams:
+		return s.handleSemanticTokensRange(ctx, req)
+	case *lsproto.DocumentFormattingParams:
+		return s.handleDocumentFormatting(ctx, req)
+	case *lsproto.DocumentRangeFormattingParams:
+		return s.handleDocumentRangeFormatting(ctx, req)
+	case *lsproto.DocumentOnTypeFormattingParams:
+		return s.handleDocumentOnTypeFormatting(ctx, req)
+	case *lsproto.InlayHintParams:
+		return s.handleInlayHint(ctx, req)
+	case *lsproto.FoldingRangeParams:
+		return s.handleFoldingRange(ctx, req)
+	case *lsproto.SelectionRangeParams:
+		return s.handleSelectionRange(ctx, req)
+	case *lsproto.CallHierarchyPrepareParams:
+		return s.handlePrepareCallHierarchy(ctx, req)
+	case *lsproto.CallHierarchyIncomingCallsParams:
+		return s.handleCallHierarchyIncomingCalls(ctx, req)
+	case *lsproto.CallHierarchyOutgoingCallsParams:
+		return s.handleCallHierarchyOutgoingCalls(ctx, req)
+	case *lsproto.TypeHierarchyPrepareParams:
+		return s.handlePrepareTypeHierarchy(ctx, req)
+	case *lsproto.TypeHierarchySupertypesParams:
+		return s.handleTypeHierarchySupertypes(ctx, req)
+	case *lsproto.TypeHierarchySubtypesParams:
+		return s.handleTypeHierarchySubtypes(ctx, req)
 	default:
 		switch req.Method {
 		case lsproto.MethodShutdown:
+			s.stopDiagnostics()
 			s.projectService.Close()
 			return s.sendResult(req.ID, nil)
 		case lsproto.MethodExit:
@@ -245,32 +485,127 @@ func (s *Server) handleInitialize(req *lsproto.RequestMessage) error {
 			DefinitionProvider: &lsproto.BooleanOrDefinitionOptions{
 				Boolean: ptrTo(true),
 			},
+			DeclarationProvider: &lsproto.BooleanOrDeclarationOptionsOrDeclarationRegistrationOptions{
+				Boolean: ptrTo(true),
+			},
+			TypeDefinitionProvider: &lsproto.BooleanOrTypeDefinitionOptionsOrTypeDefinitionRegistrationOptions{
+				Boolean: ptrTo(true),
+			},
+			ImplementationProvider: &lsproto.BooleanOrImplementationOptionsOrImplementationRegistrationOptions{
+				Boolean: ptrTo(true),
+			},
 			DiagnosticProvider: &lsproto.DiagnosticOptionsOrDiagnosticRegistrationOptions{
 				DiagnosticOptions: &lsproto.DiagnosticOptions{
 					InterFileDependencies: true,
+					WorkspaceDiagnostics:  true,
+				},
+			},
+			ReferencesProvider: &lsproto.BooleanOrReferenceOptions{
+				Boolean: ptrTo(true),
+			},
+			DocumentHighlightProvider: &lsproto.BooleanOrDocumentHighlightOptions{
+				Boolean: ptrTo(true),
+			},
+			RenameProvider: &lsproto.BooleanOrRenameOptions{
+				RenameOptions: &lsproto.RenameOptions{
+					PrepareProvider: ptrTo(true),
+				},
+			},
+			CodeActionProvider: &lsproto.BooleanOrCodeActionOptions{
+				CodeActionOptions: &lsproto.CodeActionOptions{
+					CodeActionKinds: &[]lsproto.CodeActionKind{
+						lsproto.CodeActionKindQuickFix,
+						lsproto.CodeActionKindSourceFixAll,
+					},
+				},
+			},
+			CompletionProvider: &lsproto.CompletionOptions{
+				TriggerCharacters: &[]string{".", "\"", "'", "`", "/", "@", "<", "#", " "},
+				ResolveProvider:   ptrTo(true),
+			},
+			DocumentSymbolProvider: &lsproto.BooleanOrDocumentSymbolOptions{
+				Boolean: ptrTo(true),
+			},
+			WorkspaceSymbolProvider: &lsproto.BooleanOrWorkspaceSymbolOptions{
+				Boolean: ptrTo(true),
+			},
+			SignatureHelpProvider: &lsproto.SignatureHelpOptions{
+				TriggerCharacters:   &[]string{"(", ",", "<"},
+				RetriggerCharacters: &[]string{")"},
+			},
+			SemanticTokensProvider: &lsproto.SemanticTokensOptionsOrSemanticTokensRegistrationOptions{
+				SemanticTokensOptions: &lsproto.SemanticTokensOptions{
+					Legend: ls.SemanticTokensLegend,
+					Range: &lsproto.BooleanOrEmptyObject{
+						Boolean: ptrTo(true),
+					},
+					Full: &lsproto.BooleanOrSemanticTokensFullDelta{
+						SemanticTokensFullDelta: &lsproto.SemanticTokensFullDelta{
+							Delta: ptrTo(true),
+						},
+					},
 				},
 			},
+			DocumentFormattingProvider: &lsproto.BooleanOrDocumentFormattingOptions{
+				Boolean: ptrTo(true),
+			},
+			DocumentRangeFormattingProvider: &lsproto.BooleanOrDocumentRangeFormattingOptions{
+				Boolean: ptrTo(true),
+			},
+			DocumentOnTypeFormattingProvider: &lsproto.DocumentOnTypeFormattingOptions{
+				FirstTriggerCharacter: ";",
+				MoreTriggerCharacter:  &[]string{"}", "\n"},
+			},
+			InlayHintProvider: &lsproto.BooleanOrInlayHintOptionsOrInlayHintRegistrationOptions{
+				Boolean: ptrTo(true),
+			},
+			FoldingRangeProvider: &lsproto.BooleanOrFoldingRangeOptionsOrFoldingRangeRegistrationOptions{
+				Boolean: ptrTo(true),
+			},
+			SelectionRangeProvider: &lsproto.BooleanOrSelectionRangeOptionsOrSelectionRangeRegistrationOptions{
+				Boolean: ptrTo(true),
+			},
+			CallHierarchyProvider: &lsproto.BooleanOrCallHierarchyOptionsOrCallHierarchyRegistrationOptions{
+				Boolean: ptrTo(true),
+			},
+			TypeHierarchyProvider: &lsproto.BooleanOrTypeHierarchyOptionsOrTypeHierarchyRegistrationOptions{
+				Boolean: ptrTo(true),
+			},
 		},
 	})
 }
 
 func (s *Server) handleInitialized(req *lsproto.RequestMessage) error {
+	if workspace := s.initializeParams.Capabilities.Workspace; workspace != nil && workspace.DidChangeWatchedFiles != nil {
+		dynamicRegistration := workspace.DidChangeWatchedFiles.DynamicRegistration
+		s.watchEnabled = dynamicRegistration != nil && *dynamicRegistration
+	}
+	if textDocument := s.initializeParams.Capabilities.TextDocument; textDocument == nil || textDocument.Diagnostic == nil {
+		s.pushDiagnostics = true
+	}
 	s.logger = project.NewLogger([]io.Writer{s.stderr}, "" /*file*/, project.LogLevelVerbose)
 	s.projectService = project.NewService(s, project.ServiceOptions{
 		Logger:           s.logger,
 		PositionEncoding: s.positionEncoding,
+		UserPreferences:  ls.GetDefaultUserPreferences(),
 	})
 
+	s.requests = make(map[lsproto.ID]*request)
+	s.clientRequests = make(map[lsproto.ID]*clientRequest)
+	s.openDocuments = make(map[lsproto.DocumentUri]int32)
+	s.semanticTokens = make(map[lsproto.DocumentUri]*semanticTokensResult)
 	s.converters = ls.NewConverters(s.positionEncoding, func(fileName string) ls.ScriptInfo {
 		return s.projectService.GetScriptInfo(fileName)
 	})
 
-	return nil
+	return s.watchConfiguration()
 }
 
 func (s *Server) handleDidOpen(req *lsproto.RequestMessage) error {
 	params := req.Params.(*lsproto.DidOpenTextDocumentParams)
 	s.projectService.OpenFile(ls.DocumentURIToFileName(params.TextDocument.Uri), params.TextDocument.Text, ls.LanguageKindToScriptKind(params.TextDocument.LanguageId), "")
+	s.openDocuments[params.TextDocument.Uri] = params.TextDocument.Version
+	s.scheduleDiagnostics(params.TextDocument.Uri)
 	return nil
 }
 
@@ -299,7 +634,10 @@ func (s *Server) handleDidChange(req *lsproto.RequestMessage) error {
 		}
 	}
 
+	s.cancelRequestsForDocument(params.TextDocument.Uri)
 	s.projectService.ChangeFile(ls.DocumentURIToFileName(params.TextDocument.Uri), changes)
+	s.openDocuments[params.TextDocument.Uri] = params.TextDocument.Version
+	s.scheduleDiagnostics(params.TextDocument.Uri)
 	return nil
 }
 
@@ -311,81 +649,763 @@ func (s *Server) handleDidSave(req *lsproto.RequestMessage) error {
 
 func (s *Server) handleDidClose(req *lsproto.RequestMessage) error {
 	params := req.Params.(*lsproto.DidCloseTextDocumentParams)
+	s.cancelRequestsForDocument(params.TextDocument.Uri)
 	s.projectService.CloseFile(ls.DocumentURIToFileName(params.TextDocument.Uri))
-	return nil
+	delete(s.openDocuments, params.TextDocument.Uri)
+	s.scheduleDiagnostics("")
+	s.semanticTokensMu.Lock()
+	delete(s.semanticTokens, params.TextDocument.Uri)
+	s.semanticTokensMu.Unlock()
+	return s.clearDiagnostics(params.TextDocument.Uri)
 }
 
-func (s *Server) handleDocumentDiagnostic(req *lsproto.RequestMessage) error {
-	params := req.Params.(*lsproto.DocumentDiagnosticParams)
-	file, project := s.getFileAndProject(params.TextDocument.Uri)
-	diagnostics := project.LanguageService().GetDocumentDiagnostics(file.FileName())
-	lspDiagnostics := make([]lsproto.Diagnostic, len(diagnostics))
-	for i, diag := range diagnostics {
-		if lspDiagnostic, err := s.converters.ToLSPDiagnostic(diag); err != nil {
-			return s.sendError(req.ID, err)
-		} else {
-			lspDiagnostics[i] = lspDiagnostic
-		}
-	}
-	return s.sendResult(req.ID, &lsproto.DocumentDiagnosticReport{
-		RelatedFullDocumentDiagnosticReport: &lsproto.RelatedFullDocumentDiagnosticReport{
-			FullDocumentDiagnosticReport: lsproto.FullDocumentDiagnosticReport{
-				Kind:  lsproto.StringLiteralFull{},
-				Items: lspDiagnostics,
-			},
-		},
-	})
+func (s *Server) handleDidChangeWatchedFiles(req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.DidChangeWatchedFilesParams)
+	s.projectService.OnWatchedFilesChanged(params.Changes)
+	s.scheduleDiagnostics("")
+	return nil
 }
 
-func (s *Server) handleHover(req *lsproto.RequestMessage) error {
+func (s *Server) handleHover(ctx context.Context, req *lsproto.RequestMessage) error {
 	params := req.Params.(*lsproto.HoverParams)
-	file, project := s.getFileAndProject(params.TextDocument.Uri)
-	pos, err := s.converters.LineAndCharacterToPositionForFile(params.Position, file.FileName())
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	pos, err := project.Converters().LineAndCharacterToPositionForFile(params.Position, file.FileName())
 	if err != nil {
 		return s.sendError(req.ID, err)
 	}
 
-	hoverText := project.LanguageService().ProvideHover(file.FileName(), pos)
+	quickInfo := project.LanguageService().ProvideHover(file.FileName(), pos)
+	if quickInfo == nil {
+		return s.sendResult(req.ID, nil)
+	}
+
+	value := codeFence("ts", quickInfo.DisplayText)
+	if quickInfo.Documentation != "" {
+		value += "\n\n" + quickInfo.Documentation
+	}
 	return s.sendResult(req.ID, &lsproto.Hover{
 		Contents: lsproto.MarkupContentOrMarkedStringOrMarkedStrings{
 			MarkupContent: &lsproto.MarkupContent{
 				Kind:  lsproto.MarkupKindMarkdown,
-				Value: codeFence("ts", hoverText),
+				Value: value,
 			},
 		},
 	})
 }
 
-func (s *Server) handleDefinition(req *lsproto.RequestMessage) error {
+func (s *Server) handleDefinition(ctx context.Context, req *lsproto.RequestMessage) error {
 	params := req.Params.(*lsproto.DefinitionParams)
-	file, project := s.getFileAndProject(params.TextDocument.Uri)
-	pos, err := s.converters.LineAndCharacterToPositionForFile(params.Position, file.FileName())
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	pos, err := project.Converters().LineAndCharacterToPositionForFile(params.Position, file.FileName())
 	if err != nil {
 		return s.sendError(req.ID, err)
 	}
 
 	locations := project.LanguageService().ProvideDefinitions(file.FileName(), pos)
+	return s.sendLocations(project.Converters(), req.ID, locations)
+}
+
+func (s *Server) handleDeclaration(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.DeclarationParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	pos, err := project.Converters().LineAndCharacterToPositionForFile(params.Position, file.FileName())
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+
+	locations := project.LanguageService().ProvideDeclarations(file.FileName(), pos)
+	return s.sendLocations(project.Converters(), req.ID, locations)
+}
+
+func (s *Server) handleTypeDefinition(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.TypeDefinitionParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	pos, err := project.Converters().LineAndCharacterToPositionForFile(params.Position, file.FileName())
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+
+	locations := project.LanguageService().ProvideTypeDefinitions(file.FileName(), pos)
+	return s.sendLocations(project.Converters(), req.ID, locations)
+}
+
+func (s *Server) handleImplementation(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.ImplementationParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	pos, err := project.Converters().LineAndCharacterToPositionForFile(params.Position, file.FileName())
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+
+	locations := project.LanguageService().ProvideImplementations(file.FileName(), pos)
+	return s.sendLocations(project.Converters(), req.ID, locations)
+}
+
+func (s *Server) sendLocations(converters *ls.Converters, id *lsproto.ID, locations []ls.Location) error {
+	lspLocations := make([]lsproto.Location, len(locations))
+	for i, loc := range locations {
+		if lspLocation, err := converters.ToLSPLocation(loc); err != nil {
+			return s.sendError(id, err)
+		} else {
+			lspLocations[i] = lspLocation
+		}
+	}
+
+	return s.sendResult(id, &lsproto.Definition{Locations: &lspLocations})
+}
+
+func (s *Server) handleCompletion(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.CompletionParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	pos, err := project.Converters().LineAndCharacterToPositionForFile(params.Position, file.FileName())
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+
+	list := project.LanguageService().ProvideCompletions(file.FileName(), pos, params.Context)
+	return s.sendResult(req.ID, list)
+}
+
+func (s *Server) handleCompletionItemResolve(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.CompletionItem)
+	if params.Data == nil {
+		// Keywords and string completions have nothing further to resolve.
+		return s.sendResult(req.ID, params)
+	}
+	data, err := ls.GetCompletionItemData(params)
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+
+	_, project := s.getFileAndProject(ctx, ls.FileNameToDocumentURI(data.FileName))
+	return s.sendResult(req.ID, project.LanguageService().ResolveCompletionItem(params, data))
+}
+
+func (s *Server) handleSignatureHelp(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.SignatureHelpParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	pos, err := project.Converters().LineAndCharacterToPositionForFile(params.Position, file.FileName())
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+
+	signatureHelp := project.LanguageService().ProvideSignatureHelp(file.FileName(), pos, params.Context)
+	return s.sendResult(req.ID, signatureHelp)
+}
+
+func (s *Server) handleReferences(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.ReferenceParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	pos, err := project.Converters().LineAndCharacterToPositionForFile(params.Position, file.FileName())
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+
+	locations := project.LanguageService().ProvideReferences(file.FileName(), pos, params.Context.IncludeDeclaration)
 	lspLocations := make([]lsproto.Location, len(locations))
 	for i, loc := range locations {
-		if lspLocation, err := s.converters.ToLSPLocation(loc); err != nil {
+		if lspLocation, err := project.Converters().ToLSPLocation(loc); err != nil {
 			return s.sendError(req.ID, err)
 		} else {
 			lspLocations[i] = lspLocation
 		}
 	}
 
-	return s.sendResult(req.ID, &lsproto.Definition{Locations: &lspLocations})
+	return s.sendResult(req.ID, lspLocations)
+}
+
+func (s *Server) handleDocumentHighlight(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.DocumentHighlightParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	pos, err := project.Converters().LineAndCharacterToPositionForFile(params.Position, file.FileName())
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+
+	entries := project.LanguageService().ProvideDocumentHighlights(file.FileName(), pos)
+	highlights := make([]lsproto.DocumentHighlight, len(entries))
+	for i, entry := range entries {
+		lspRange, err := project.Converters().ToLSPRange(entry.FileName, entry.Range)
+		if err != nil {
+			return s.sendError(req.ID, err)
+		}
+		kind := lsproto.DocumentHighlightKindRead
+		if entry.IsWriteAccess {
+			kind = lsproto.DocumentHighlightKindWrite
+		}
+		highlights[i] = lsproto.DocumentHighlight{
+			Range: lspRange,
+			Kind:  ptrTo(kind),
+		}
+	}
+
+	return s.sendResult(req.ID, highlights)
+}
+
+func (s *Server) handlePrepareRename(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.PrepareRenameParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	pos, err := project.Converters().LineAndCharacterToPositionForFile(params.Position, file.FileName())
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+
+	info, message := project.LanguageService().PrepareRename(file.FileName(), pos)
+	if message != nil {
+		return s.sendError(req.ID, &requestFailedError{message.Message()})
+	}
+	lspRange, err := project.Converters().ToLSPRange(file.FileName(), info.TriggerSpan)
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+	return s.sendResult(req.ID, &lsproto.PrepareRenamePlaceholder{
+		Range:       lspRange,
+		Placeholder: info.DisplayName,
+	})
+}
+
+func (s *Server) handleRename(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.RenameParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	pos, err := project.Converters().LineAndCharacterToPositionForFile(params.Position, file.FileName())
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+
+	options := ls.RenameOptions{AllowRenameFile: s.clientSupportsRenameFile()}
+	result, message := project.LanguageService().ProvideRename(file.FileName(), pos, params.NewName, options)
+	if message != nil {
+		return s.sendError(req.ID, &requestFailedError{message.Message()})
+	}
+
+	changes := make(map[lsproto.DocumentUri][]lsproto.TextEdit, len(result.Changes))
+	var documentChanges []lsproto.TextDocumentEditOrCreateFileOrRenameFileOrDeleteFile
+	for _, fileName := range slices.Sorted(maps.Keys(result.Changes)) {
+		edits, err := s.toLSPTextEdits(project.Converters(), fileName, result.Changes[fileName])
+		if err != nil {
+			return s.sendError(req.ID, err)
+		}
+		uri := ls.FileNameToDocumentURI(fileName)
+		changes[uri] = edits
+		documentChanges = append(documentChanges, lsproto.TextDocumentEditOrCreateFileOrRenameFileOrDeleteFile{
+			TextDocumentEdit: &lsproto.TextDocumentEdit{
+				TextDocument: lsproto.OptionalVersionedTextDocumentIdentifier{
+					TextDocumentIdentifier: lsproto.TextDocumentIdentifier{Uri: uri},
+				},
+				Edits: core.Map(edits, func(edit lsproto.TextEdit) lsproto.TextEditOrAnnotatedTextEditOrSnippetTextEdit {
+					return lsproto.TextEditOrAnnotatedTextEditOrSnippetTextEdit{TextEdit: &edit}
+				}),
+			},
+		})
+	}
+
+	if result.FileRename == nil {
+		return s.sendResult(req.ID, &lsproto.WorkspaceEdit{Changes: &changes})
+	}
+	// Renaming a file can only be expressed through document changes, which are applied in order.
+	documentChanges = append(documentChanges, lsproto.TextDocumentEditOrCreateFileOrRenameFileOrDeleteFile{
+		RenameFile: &lsproto.RenameFile{
+			OldUri: ls.FileNameToDocumentURI(result.FileRename.OldFileName),
+			NewUri: ls.FileNameToDocumentURI(result.FileRename.NewFileName),
+		},
+	})
+	return s.sendResult(req.ID, &lsproto.WorkspaceEdit{DocumentChanges: &documentChanges})
 }
 
-func (s *Server) getFileAndProject(uri lsproto.DocumentUri) (*project.ScriptInfo, *project.Project) {
-	fileName := ls.DocumentURIToFileName(uri)
-	return s.projectService.EnsureDefaultProjectForFile(fileName)
+func (s *Server) handleCodeAction(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.CodeActionParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	languageService := project.LanguageService()
+	wantsKind := func(kind lsproto.CodeActionKind) bool {
+		if params.Context.Only == nil {
+			return true
+		}
+		return slices.ContainsFunc(*params.Context.Only, func(only lsproto.CodeActionKind) bool {
+			return kind == only || strings.HasPrefix(string(kind), string(only)+".")
+		})
+	}
+
+	actions := []lsproto.CodeAction{}
+	if wantsKind(lsproto.CodeActionKindQuickFix) {
+		var fileCodes []int32
+		fixAllAdded := make(map[string]bool)
+		for _, diagnostic := range params.Context.Diagnostics {
+			if diagnostic.Code == nil || diagnostic.Code.Integer == nil {
+				continue
+			}
+			span, err := project.Converters().FromLSPRange(diagnostic.Range, file.FileName())
+			if err != nil {
+				return s.sendError(req.ID, err)
+			}
+			fixes := languageService.ProvideCodeFixes(file.FileName(), []ls.CodeFixDiagnostic{{Code: *diagnostic.Code.Integer, Span: span}})
+			for _, fix := range fixes {
+				edit, err := s.toLSPWorkspaceEdit(project.Converters(), fix.Changes)
+				if err != nil {
+					return s.sendError(req.ID, err)
+				}
+				actions = append(actions, lsproto.CodeAction{
+					Title:       fix.Title,
+					Kind:        ptrTo(lsproto.CodeActionKindQuickFix),
+					Diagnostics: &[]lsproto.Diagnostic{diagnostic},
+					Edit:        edit,
+				})
+			}
+			for _, fix := range fixes {
+				if fix.FixID == "" || fixAllAdded[fix.FixID] {
+					continue
+				}
+				fixAllAdded[fix.FixID] = true
+				if fileCodes == nil {
+					fileCodes = core.Map(languageService.GetDocumentDiagnostics(file.FileName()), (*ast.Diagnostic).Code)
+				}
+				if !ls.CanFixAll(fix.FixID, fileCodes) {
+					continue
+				}
+				fixAll := languageService.ProvideFixAll(file.FileName(), fix.FixID)
+				if fixAll == nil {
+					continue
+				}
+				edit, err := s.toLSPWorkspaceEdit(project.Converters(), fixAll.Changes)
+				if err != nil {
+					return s.sendError(req.ID, err)
+				}
+				actions = append(actions, lsproto.CodeAction{
+					Title: fixAll.Title,
+					Kind:  ptrTo(lsproto.CodeActionKindQuickFix),
+					Edit:  edit,
+				})
+			}
+		}
+	}
+	// source.fixAll is only computed when asked for, since it is usually run on save.
+	if params.Context.Only != nil && wantsKind(lsproto.CodeActionKindSourceFixAll) {
+		if changes := languageService.ProvideSourceFixAll(file.FileName()); changes != nil {
+			edit, err := s.toLSPWorkspaceEdit(project.Converters(), changes)
+			if err != nil {
+				return s.sendError(req.ID, err)
+			}
+			actions = append(actions, lsproto.CodeAction{
+				Title: "Fix all",
+				Kind:  ptrTo(lsproto.CodeActionKindSourceFixAll),
+				Edit:  edit,
+			})
+		}
+	}
+	return s.sendResult(req.ID, actions)
+}
+
+func (s *Server) toLSPWorkspaceEdit(converters *ls.Converters, changes map[string][]ls.TextChange) (*lsproto.WorkspaceEdit, error) {
+	lspChanges := make(map[lsproto.DocumentUri][]lsproto.TextEdit, len(changes))
+	for fileName, fileChanges := range changes {
+		edits, err := s.toLSPTextEdits(converters, fileName, fileChanges)
+		if err != nil {
+			return nil, err
+		}
+		lspChanges[ls.FileNameToDocumentURI(fileName)] = edits
+	}
+	return &lsproto.WorkspaceEdit{Changes: &lspChanges}, nil
+}
+
+func (s *Server) toLSPTextEdits(converters *ls.Converters, fileName string, changes []ls.TextChange) ([]lsproto.TextEdit, error) {
+	edits := make([]lsproto.TextEdit, len(changes))
+	for i, change := range changes {
+		lspRange, err := converters.ToLSPRange(fileName, change.TextRange)
+		if err != nil {
+			return nil, err
+		}
+		edits[i] = lsproto.TextEdit{Range: lspRange, NewText: change.NewText}
+	}
+	return edits, nil
+}
+
+func (s *Server) clientSupportsRenameFile() bool {
+	workspace := s.initializeParams.Capabilities.Workspace
+	if workspace == nil || workspace.WorkspaceEdit == nil {
+		return false
+	}
+	edit := workspace.WorkspaceEdit
+	return edit.DocumentChanges != nil && *edit.DocumentChanges &&
+		edit.ResourceOperations != nil && slices.Contains(*edit.ResourceOperations, lsproto.ResourceOperationKindRename)
+}
+
+func (s *Server) handleDocumentSymbol(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.DocumentSymbolParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	return s.sendResult(req.ID, project.LanguageService().ProvideDocumentSymbols(file.FileName()))
+}
+
+func (s *Server) handleWorkspaceSymbol(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.WorkspaceSymbolParams)
+	projects := s.getProjects(ctx)
+	services := make([]*ls.LanguageService, len(projects))
+	for i, project := range projects {
+		services[i] = project.LanguageService()
+	}
+
+	symbols := ls.ProvideWorkspaceSymbols(services, params.Query)
+	converters := s.newSnapshotsConverters(projects)
+	lspSymbols := make([]lsproto.SymbolInformation, 0, len(symbols))
+	for _, symbol := range symbols {
+		location, err := converters.ToLSPLocation(symbol.Location)
+		if err != nil {
+			return s.sendError(req.ID, err)
+		}
+		information := lsproto.SymbolInformation{
+			BaseSymbolInformation: lsproto.BaseSymbolInformation{
+				Name: symbol.Name,
+				Kind: symbol.Kind,
+			},
+			Location: location,
+		}
+		if symbol.ContainerName != "" {
+			information.ContainerName = ptrTo(symbol.ContainerName)
+		}
+		lspSymbols = append(lspSymbols, information)
+	}
+	return s.sendResult(req.ID, lspSymbols)
+}
+
+func (s *Server) handleSemanticTokensFull(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.SemanticTokensParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	data := project.LanguageService().ProvideSemanticTokens(file.FileName(), core.NewTextRange(0, len(file.Text())))
+	_, result := s.storeSemanticTokens(params.TextDocument.Uri, data)
+	return s.sendResult(req.ID, &lsproto.SemanticTokens{
+		ResultId: ptrTo(result.resultID),
+		Data:     data,
+	})
+}
+
+func (s *Server) handleSemanticTokensFullDelta(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.SemanticTokensDeltaParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	data := project.LanguageService().ProvideSemanticTokens(file.FileName(), core.NewTextRange(0, len(file.Text())))
+	previous, result := s.storeSemanticTokens(params.TextDocument.Uri, data)
+	if previous == nil || previous.resultID != params.PreviousResultId {
+		// The client refers to a result we no longer have, so it gets the full tokens instead.
+		return s.sendResult(req.ID, &lsproto.SemanticTokens{
+			ResultId: ptrTo(result.resultID),
+			Data:     data,
+		})
+	}
+	edits := ls.ComputeSemanticTokensEdits(previous.data, data)
+	if edits == nil {
+		edits = []lsproto.SemanticTokensEdit{}
+	}
+	return s.sendResult(req.ID, &lsproto.SemanticTokensDelta{
+		ResultId: ptrTo(result.resultID),
+		Edits:    edits,
+	})
+}
+
+func (s *Server) handleSemanticTokensRange(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.SemanticTokensRangeParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	span, err := project.Converters().FromLSPRange(params.Range, file.FileName())
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+	return s.sendResult(req.ID, &lsproto.SemanticTokens{
+		Data: project.LanguageService().ProvideSemanticTokens(file.FileName(), span),
+	})
+}
+
+func (s *Server) handleDocumentFormatting(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.DocumentFormattingParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	languageService := project.LanguageService()
+	changes := languageService.ProvideFormatDocument(file.FileName(), getFormatCodeSettings(project, &params.Options))
+	return s.sendFormattingEdits(project.Converters(), req, file.FileName(), changes)
+}
+
+func (s *Server) handleDocumentRangeFormatting(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.DocumentRangeFormattingParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	span, err := project.Converters().FromLSPRange(params.Range, file.FileName())
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+	languageService := project.LanguageService()
+	changes := languageService.ProvideFormatRange(file.FileName(), span, getFormatCodeSettings(project, &params.Options))
+	return s.sendFormattingEdits(project.Converters(), req, file.FileName(), changes)
+}
+
+func (s *Server) handleDocumentOnTypeFormatting(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.DocumentOnTypeFormattingParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	pos, err := project.Converters().LineAndCharacterToPositionForFile(params.Position, file.FileName())
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+	languageService := project.LanguageService()
+	changes := languageService.ProvideFormatOnType(file.FileName(), pos, params.Ch, getFormatCodeSettings(project, &params.Options))
+	return s.sendFormattingEdits(project.Converters(), req, file.FileName(), changes)
+}
+
+func (s *Server) sendFormattingEdits(converters *ls.Converters, req *lsproto.RequestMessage, fileName string, changes []ls.TextChange) error {
+	edits, err := s.toLSPTextEdits(converters, fileName, changes)
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+	return s.sendResult(req.ID, edits)
+}
+
+// getFormatCodeSettings returns the format settings of a snapshot with the options that the client
+// sends along with each formatting request applied to them.
+func getFormatCodeSettings(snapshot *project.Snapshot, options *lsproto.FormattingOptions) *ls.FormatCodeSettings {
+	settings := ptrTo(*snapshot.FormatCodeSettings())
+	if options.TabSize > 0 {
+		settings.TabSize = int(options.TabSize)
+		settings.IndentSize = int(options.TabSize)
+	}
+	settings.ConvertTabsToSpaces = options.InsertSpaces
+	if options.TrimTrailingWhitespace != nil {
+		settings.TrimTrailingWhitespace = *options.TrimTrailingWhitespace
+	}
+	return settings
+}
+
+func (s *Server) handleInlayHint(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.InlayHintParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	span, err := project.Converters().FromLSPRange(params.Range, file.FileName())
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+
+	hints := project.LanguageService().ProvideInlayHints(file.FileName(), span)
+	lspHints := make([]lsproto.InlayHint, 0, len(hints))
+	for _, hint := range hints {
+		parts := make([]lsproto.InlayHintLabelPart, len(hint.Label))
+		for i, part := range hint.Label {
+			parts[i].Value = part.Text
+			if part.Location != nil {
+				location, err := project.Converters().ToLSPLocation(*part.Location)
+				if err != nil {
+					return s.sendError(req.ID, err)
+				}
+				parts[i].Location = &location
+			}
+		}
+		lspHint := lsproto.InlayHint{
+			Position: project.Converters().PositionToLineAndCharacter(file, core.TextPos(hint.Position)),
+			Label:    lsproto.StringOrInlayHintLabelParts{InlayHintLabelParts: &parts},
+		}
+		if hint.Kind != 0 {
+			lspHint.Kind = ptrTo(hint.Kind)
+		}
+		if hint.PaddingLeft {
+			lspHint.PaddingLeft = ptrTo(true)
+		}
+		if hint.PaddingRight {
+			lspHint.PaddingRight = ptrTo(true)
+		}
+		lspHints = append(lspHints, lspHint)
+	}
+	return s.sendResult(req.ID, lspHints)
+}
+
+func (s *Server) handleFoldingRange(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.FoldingRangeParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	return s.sendResult(req.ID, project.LanguageService().ProvideFoldingRanges(file.FileName()))
+}
+
+func (s *Server) handleSelectionRange(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.SelectionRangeParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	positions := make([]int, len(params.Positions))
+	for i, position := range params.Positions {
+		pos, err := project.Converters().LineAndCharacterToPositionForFile(position, file.FileName())
+		if err != nil {
+			return s.sendError(req.ID, err)
+		}
+		positions[i] = pos
+	}
+	return s.sendResult(req.ID, project.LanguageService().ProvideSelectionRanges(file.FileName(), positions))
+}
+
+func (s *Server) handlePrepareCallHierarchy(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.CallHierarchyPrepareParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	pos, err := project.Converters().LineAndCharacterToPositionForFile(params.Position, file.FileName())
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+
+	items := project.LanguageService().ProvidePrepareCallHierarchy(file.FileName(), pos)
+	lspItems := make([]lsproto.CallHierarchyItem, len(items))
+	for i, item := range items {
+		if lspItems[i], err = s.toLSPCallHierarchyItem(project.Converters(), item); err != nil {
+			return s.sendError(req.ID, err)
+		}
+	}
+	return s.sendResult(req.ID, lspItems)
+}
+
+func (s *Server) handleCallHierarchyIncomingCalls(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.CallHierarchyIncomingCallsParams)
+	file, project := s.getFileAndProject(ctx, params.Item.Uri)
+	pos, err := project.Converters().LineAndCharacterToPositionForFile(params.Item.SelectionRange.Start, file.FileName())
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+
+	calls := project.LanguageService().ProvideCallHierarchyIncomingCalls(file.FileName(), pos)
+	lspCalls := make([]lsproto.CallHierarchyIncomingCall, len(calls))
+	for i, call := range calls {
+		from, err := s.toLSPCallHierarchyItem(project.Converters(), call.From)
+		if err != nil {
+			return s.sendError(req.ID, err)
+		}
+		fromRanges, err := s.toLSPRanges(project.Converters(), call.From.FileName, call.FromRanges)
+		if err != nil {
+			return s.sendError(req.ID, err)
+		}
+		lspCalls[i] = lsproto.CallHierarchyIncomingCall{From: from, FromRanges: fromRanges}
+	}
+	return s.sendResult(req.ID, lspCalls)
+}
+
+func (s *Server) handleCallHierarchyOutgoingCalls(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.CallHierarchyOutgoingCallsParams)
+	file, project := s.getFileAndProject(ctx, params.Item.Uri)
+	pos, err := project.Converters().LineAndCharacterToPositionForFile(params.Item.SelectionRange.Start, file.FileName())
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+
+	calls := project.LanguageService().ProvideCallHierarchyOutgoingCalls(file.FileName(), pos)
+	lspCalls := make([]lsproto.CallHierarchyOutgoingCall, len(calls))
+	for i, call := range calls {
+		to, err := s.toLSPCallHierarchyItem(project.Converters(), call.To)
+		if err != nil {
+			return s.sendError(req.ID, err)
+		}
+		fromRanges, err := s.toLSPRanges(project.Converters(), file.FileName(), call.FromRanges)
+		if err != nil {
+			return s.sendError(req.ID, err)
+		}
+		lspCalls[i] = lsproto.CallHierarchyOutgoingCall{To: to, FromRanges: fromRanges}
+	}
+	return s.sendResult(req.ID, lspCalls)
+}
+
+func (s *Server) handlePrepareTypeHierarchy(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.TypeHierarchyPrepareParams)
+	file, project := s.getFileAndProject(ctx, params.TextDocument.Uri)
+	pos, err := project.Converters().LineAndCharacterToPositionForFile(params.Position, file.FileName())
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+	return s.sendTypeHierarchyItems(project.Converters(), req.ID, project.LanguageService().ProvidePrepareTypeHierarchy(file.FileName(), pos))
+}
+
+func (s *Server) handleTypeHierarchySupertypes(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.TypeHierarchySupertypesParams)
+	file, project := s.getFileAndProject(ctx, params.Item.Uri)
+	pos, err := project.Converters().LineAndCharacterToPositionForFile(params.Item.SelectionRange.Start, file.FileName())
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+	return s.sendTypeHierarchyItems(project.Converters(), req.ID, project.LanguageService().ProvideTypeHierarchySupertypes(file.FileName(), pos))
+}
+
+func (s *Server) handleTypeHierarchySubtypes(ctx context.Context, req *lsproto.RequestMessage) error {
+	params := req.Params.(*lsproto.TypeHierarchySubtypesParams)
+	// Subtypes can be declared in any project that includes the file, not only its default project.
+	file, defaultProject, projects := s.getFileAndProjects(ctx, params.Item.Uri, true /*allProjects*/)
+	pos, err := defaultProject.Converters().LineAndCharacterToPositionForFile(params.Item.SelectionRange.Start, file.FileName())
+	if err != nil {
+		return s.sendError(req.ID, err)
+	}
+
+	services := make([]*ls.LanguageService, len(projects))
+	for i, project := range projects {
+		services[i] = project.LanguageService()
+	}
+	return s.sendTypeHierarchyItems(s.newSnapshotsConverters(projects), req.ID, ls.ProvideTypeHierarchySubtypes(services, file.FileName(), pos))
+}
+
+func (s *Server) sendTypeHierarchyItems(converters *ls.Converters, id *lsproto.ID, items []ls.HierarchyItem) error {
+	lspItems := make([]lsproto.TypeHierarchyItem, len(items))
+	for i, item := range items {
+		callItem, err := s.toLSPCallHierarchyItem(converters, item)
+		if err != nil {
+			return s.sendError(id, err)
+		}
+		lspItems[i] = lsproto.TypeHierarchyItem{
+			Name:           callItem.Name,
+			Kind:           callItem.Kind,
+			Detail:         callItem.Detail,
+			Uri:            callItem.Uri,
+			Range:          callItem.Range,
+			SelectionRange: callItem.SelectionRange,
+		}
+	}
+	return s.sendResult(id, lspItems)
+}
+
+func (s *Server) toLSPCallHierarchyItem(converters *ls.Converters, item ls.HierarchyItem) (lsproto.CallHierarchyItem, error) {
+	lspRange, err := converters.ToLSPRange(item.FileName, item.Range)
+	if err != nil {
+		return lsproto.CallHierarchyItem{}, err
+	}
+	selectionRange, err := converters.ToLSPRange(item.FileName, item.SelectionRange)
+	if err != nil {
+		return lsproto.CallHierarchyItem{}, err
+	}
+	lspItem := lsproto.CallHierarchyItem{
+		Name:           item.Name,
+		Kind:           item.Kind,
+		Uri:            ls.FileNameToDocumentURI(item.FileName),
+		Range:          lspRange,
+		SelectionRange: selectionRange,
+	}
+	if item.Detail != "" {
+		lspItem.Detail = ptrTo(item.Detail)
+	}
+	return lspItem, nil
+}
+
+func (s *Server) toLSPRanges(converters *ls.Converters, fileName string, ranges []core.TextRange) ([]lsproto.Range, error) {
+	lspRanges := make([]lsproto.Range, len(ranges))
+	for i, textRange := range ranges {
+		lspRange, err := converters.ToLSPRange(fileName, textRange)
+		if err != nil {
+			return nil, err
+		}
+		lspRanges[i] = lspRange
+	}
+	return lspRanges, nil
+}
+
+// storeSemanticTokens replaces the last semantic tokens result of a document, and returns both.
+func (s *Server) storeSemanticTokens(uri lsproto.DocumentUri, data []uint32) (previous *semanticTokensResult, result *semanticTokensResult) {
+	s.semanticTokensMu.Lock()
+	defer s.semanticTokensMu.Unlock()
+	s.semanticTokensResultID++
+	result = &semanticTokensResult{
+		resultID: strconv.Itoa(s.semanticTokensResultID),
+		data:     data,
+	}
+	previous = s.semanticTokens[uri]
+	s.semanticTokens[uri] = result
+	return previous, result
 }
 
 func (s *Server) Log(msg ...any) {
 	fmt.Fprintln(s.stderr, msg...)
 }
 
+// requestFailedError is reported to the client as a failed request with a message for the user.
+type requestFailedError struct {
+	message string
+}
+
+func (e *requestFailedError) Error() string { return e.message }
+func (e *requestFailedError) Unwrap() error { return lsproto.ErrRequestFailed }
+
 func codeFence(lang string, code string) string {
 	if code == "" {
 		return ""
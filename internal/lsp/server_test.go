@@ -0,0 +1,335 @@
+package lsp_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/microsoft/typescript-go/internal/bundled"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/lsp"
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"github.com/microsoft/typescript-go/internal/vfs"
+	"github.com/microsoft/typescript-go/internal/vfs/vfstest"
+	"gotest.tools/v3/assert"
+)
+
+const (
+	// blockingDirectory is a directory whose listing blocks until the test releases it, so that
+	// a request for import path completions in it is still in flight while the test sends more messages.
+	blockingDirectory = "/home/src/project/blocking"
+	// panickingDirectory is a directory whose listing panics.
+	panickingDirectory = "/home/src/project/panicking"
+)
+
+type testFS struct {
+	vfs.FS
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (fs *testFS) GetAccessibleEntries(path string) vfs.Entries {
+	switch path {
+	case blockingDirectory:
+		fs.entered <- struct{}{}
+		<-fs.release
+	case panickingDirectory:
+		panic("cannot list " + path)
+	}
+	return fs.FS.GetAccessibleEntries(path)
+}
+
+// message is a message from the server: a response, a notification or a request.
+type message struct {
+	ID     json.RawMessage        `json:"id"`
+	Method lsproto.Method         `json:"method"`
+	Params json.RawMessage        `json:"params"`
+	Result json.RawMessage        `json:"result"`
+	Error  *lsproto.ResponseError `json:"error"`
+}
+
+type testClient struct {
+	t        *testing.T
+	fs       *testFS
+	w        *lsproto.BaseWriter
+	messages chan *message
+	nextID   int
+	// texts are the texts of the open documents.
+	texts map[lsproto.DocumentUri]string
+}
+
+// startServer runs a server over the given files, initialized with the given client capabilities.
+func startServer(t *testing.T, files map[string]string, capabilities map[string]any) *testClient {
+	t.Helper()
+	if !bundled.Embedded {
+		// Without embedding, we'd need to read all of the lib files out from disk into the MapFS.
+		// Just skip this for now.
+		t.Skip("bundled files are not embedded")
+	}
+
+	fs := &testFS{
+		FS:      bundled.WrapFS(vfstest.FromMap(files, false /*useCaseSensitiveFileNames*/)),
+		entered: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+	server := lsp.NewServer(&lsp.ServerOptions{
+		In:                 inReader,
+		Out:                outWriter,
+		Err:                io.Discard,
+		Cwd:                "/home/src/project",
+		NewLine:            core.NewLineKindLF,
+		FS:                 fs,
+		DefaultLibraryPath: bundled.LibPath(),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = server.Run(ctx)
+		outWriter.Close()
+	}()
+
+	c := &testClient{t: t, fs: fs, w: lsproto.NewBaseWriter(inWriter), messages: make(chan *message, 100), texts: make(map[lsproto.DocumentUri]string)}
+	go func() {
+		defer close(c.messages)
+		r := lsproto.NewBaseReader(outReader)
+		for {
+			data, err := r.Read()
+			if err != nil {
+				return
+			}
+			var msg message
+			if err := json.Unmarshal(data, &msg); err != nil {
+				return
+			}
+			c.messages <- &msg
+		}
+	}()
+	t.Cleanup(func() {
+		cancel()
+		inWriter.Close()
+		// Unblock any request that is still listing the blocking directory
+		close(fs.release)
+		<-done
+	})
+
+	id := c.request(lsproto.MethodInitialize, map[string]any{"capabilities": capabilities})
+	assert.Assert(t, c.response(id).Error == nil)
+	c.notify(lsproto.MethodInitialized, map[string]any{})
+	return c
+}
+
+func (c *testClient) write(msg map[string]any) {
+	c.t.Helper()
+	msg["jsonrpc"] = "2.0"
+	data, err := json.Marshal(msg)
+	assert.NilError(c.t, err)
+	assert.NilError(c.t, c.w.Write(data))
+}
+
+// request sends a request and returns its ID.
+func (c *testClient) request(method lsproto.Method, params any) int {
+	c.t.Helper()
+	c.nextID++
+	c.write(map[string]any{"id": c.nextID, "method": method, "params": params})
+	return c.nextID
+}
+
+func (c *testClient) notify(method lsproto.Method, params any) {
+	c.t.Helper()
+	c.write(map[string]any{"method": method, "params": params})
+}
+
+// next returns the next message from the server that satisfies match, skipping the others.
+func (c *testClient) next(match func(msg *message) bool) *message {
+	c.t.Helper()
+	timeout := time.After(time.Minute)
+	for {
+		select {
+		case msg, ok := <-c.messages:
+			if !ok {
+				c.t.Fatal("the server closed the connection")
+			}
+			if match(msg) {
+				return msg
+			}
+		case <-timeout:
+			c.t.Fatal("timed out waiting for a message from the server")
+		}
+	}
+}
+
+// response returns the next response from the server, which must be the response to the request with id.
+func (c *testClient) response(id int) *message {
+	c.t.Helper()
+	msg := c.next(func(msg *message) bool {
+		return msg.Method == ""
+	})
+	assert.Equal(c.t, string(msg.ID), strconv.Itoa(id))
+	return msg
+}
+
+// noResponse checks that the server does not respond for a while.
+func (c *testClient) noResponse() {
+	c.t.Helper()
+	timeout := time.After(100 * time.Millisecond)
+	for {
+		select {
+		case msg := <-c.messages:
+			assert.Assert(c.t, msg.Method != "", "unexpected response to %s", string(msg.ID))
+		case <-timeout:
+			return
+		}
+	}
+}
+
+// sync waits until the server has handled every message sent so far, by sending a request that
+// does not wait for anything.
+func (c *testClient) sync() {
+	c.t.Helper()
+	assert.Assert(c.t, c.response(c.request(lsproto.MethodWorkspaceSymbol, map[string]any{"query": "value"})).Error == nil)
+}
+
+func (c *testClient) open(uri lsproto.DocumentUri, text string) {
+	c.t.Helper()
+	c.texts[uri] = text
+	c.notify(lsproto.MethodTextDocumentDidOpen, map[string]any{
+		"textDocument": map[string]any{"uri": uri, "languageId": "typescript", "version": 1, "text": text},
+	})
+}
+
+// change replaces the text of an open document.
+func (c *testClient) change(uri lsproto.DocumentUri, version int, text string) {
+	c.t.Helper()
+	previous := c.texts[uri]
+	lines := strings.Split(previous, "\n")
+	end := map[string]any{"line": len(lines) - 1, "character": len(lines[len(lines)-1])}
+	c.texts[uri] = text
+	c.notify(lsproto.MethodTextDocumentDidChange, map[string]any{
+		"textDocument": map[string]any{"uri": uri, "version": version},
+		"contentChanges": []map[string]any{{
+			"range": map[string]any{"start": map[string]any{"line": 0, "character": 0}, "end": end},
+			"text":  text,
+		}},
+	})
+}
+
+func position(uri lsproto.DocumentUri, line int, character int) map[string]any {
+	return map[string]any{
+		"textDocument": map[string]any{"uri": uri},
+		"position":     map[string]any{"line": line, "character": character},
+	}
+}
+
+func (c *testClient) hover(uri lsproto.DocumentUri, line int, character int) string {
+	c.t.Helper()
+	msg := c.response(c.request(lsproto.MethodTextDocumentHover, position(uri, line, character)))
+	assert.Assert(c.t, msg.Error == nil)
+	var hover lsproto.Hover
+	assert.NilError(c.t, json.Unmarshal(msg.Result, &hover))
+	return hover.Contents.MarkupContent.Value
+}
+
+const indexURI lsproto.DocumentUri = "file:///home/src/project/index.ts"
+
+// blockingImport is the text of a file whose import path completions, requested at the end of
+// its first line, list the blocking directory.
+const blockingImport = `import "./blocking/";`
+
+var serverTestFiles = map[string]string{
+	"/home/src/project/tsconfig.json":       `{ "files": ["index.ts", "other.ts"] }`,
+	"/home/src/project/other.ts":            `export const value = 1;`,
+	"/home/src/project/blocking/module.ts":  `export {};`,
+	"/home/src/project/panicking/module.ts": `export {};`,
+	"/home/src/project/index.ts":            blockingImport,
+}
+
+// startBlockingCompletion requests completions that wait until the test releases the blocking directory.
+func (c *testClient) startBlockingCompletion() int {
+	c.t.Helper()
+	c.open(indexURI, blockingImport)
+	id := c.request(lsproto.MethodTextDocumentCompletion, position(indexURI, 0, strings.Index(blockingImport, `/"`)+1))
+	<-c.fs.entered
+	return id
+}
+
+func TestCancelRequest(t *testing.T) {
+	t.Parallel()
+	c := startServer(t, serverTestFiles, map[string]any{})
+	id := c.startBlockingCompletion()
+	c.notify(lsproto.MethodCancelRequest, map[string]any{"id": id})
+	c.sync()
+	c.fs.release <- struct{}{}
+	msg := c.response(id)
+	assert.Assert(t, msg.Error != nil)
+	assert.Equal(t, msg.Error.Code, lsproto.ErrRequestCancelled.Code)
+}
+
+func TestContentModified(t *testing.T) {
+	t.Parallel()
+	c := startServer(t, serverTestFiles, map[string]any{})
+	id := c.startBlockingCompletion()
+	// A change to another document does not cancel the request
+	c.open("file:///home/src/project/other.ts", `export const value = 1;`)
+	c.change("file:///home/src/project/other.ts", 2, `export const value = 2;`)
+	c.change(indexURI, 2, blockingImport+"\n")
+	c.sync()
+	c.fs.release <- struct{}{}
+	msg := c.response(id)
+	assert.Assert(t, msg.Error != nil)
+	assert.Equal(t, msg.Error.Code, lsproto.ErrContentModified.Code)
+}
+
+func TestRequestsSeeNotificationsInOrder(t *testing.T) {
+	t.Parallel()
+	c := startServer(t, serverTestFiles, map[string]any{})
+	otherURI := lsproto.DocumentUri("file:///home/src/project/other.ts")
+	c.open(otherURI, `export const value = 1;`)
+	c.open(indexURI, `import { value } from "./other";`)
+
+	// The request sees the change that was sent before it, without waiting for anything in between
+	c.change(otherURI, 2, `export const value = "changed";`)
+	assert.Assert(t, strings.Contains(c.hover(indexURI, 0, len("import { ")), `"changed"`))
+
+	// The request is not affected by the change that was sent after it, even if the change is
+	// handled before the request finishes
+	id := c.request(lsproto.MethodTextDocumentHover, position(indexURI, 0, len("import { ")))
+	c.change(otherURI, 3, `export const value = "later";`)
+	msg := c.response(id)
+	assert.Assert(t, msg.Error == nil)
+	var hover lsproto.Hover
+	assert.NilError(t, json.Unmarshal(msg.Result, &hover))
+	assert.Assert(t, strings.Contains(hover.Contents.MarkupContent.Value, `"changed"`), hover.Contents.MarkupContent.Value)
+	assert.Assert(t, strings.Contains(c.hover(indexURI, 0, len("import { ")), `"later"`))
+}
+
+func TestShutdownWaitsForRequests(t *testing.T) {
+	t.Parallel()
+	c := startServer(t, serverTestFiles, map[string]any{})
+	completionID := c.startBlockingCompletion()
+	shutdownID := c.request(lsproto.MethodShutdown, nil)
+	c.noResponse()
+	c.fs.release <- struct{}{}
+	assert.Assert(t, c.response(completionID).Error == nil)
+	assert.Assert(t, c.response(shutdownID).Error == nil)
+}
+
+func TestRequestPanic(t *testing.T) {
+	t.Parallel()
+	c := startServer(t, serverTestFiles, map[string]any{})
+	text := `import "./panicking/"; import { value } from "./other";`
+	c.open(indexURI, text)
+	msg := c.response(c.request(lsproto.MethodTextDocumentCompletion, position(indexURI, 0, strings.Index(text, `/"`)+1)))
+	assert.Assert(t, msg.Error != nil)
+	assert.Equal(t, msg.Error.Code, lsproto.ErrInternalError.Code)
+	assert.Assert(t, strings.Contains(msg.Error.Message, "cannot list "+panickingDirectory), msg.Error.Message)
+	// The server keeps handling requests
+	assert.Assert(t, strings.Contains(c.hover(indexURI, 0, strings.Index(text, "value")), "value"))
+}
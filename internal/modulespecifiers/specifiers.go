@@ -0,0 +1,674 @@
+package modulespecifiers
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/collections"
+	"github.com/microsoft/typescript-go/internal/compiler/module"
+	"github.com/microsoft/typescript-go/internal/compiler/packagejson"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/tspath"
+)
+
+const nodeModulesPathPart = "/node_modules/"
+
+var extensionsNotSupportingExtensionlessResolution = []string{
+	tspath.ExtensionMts, tspath.ExtensionDmts, tspath.ExtensionMjs,
+	tspath.ExtensionCts, tspath.ExtensionDcts, tspath.ExtensionCjs,
+}
+
+// GetModuleSpecifiers returns the specifiers that importingFile can import the module file
+// toFileName by, best first. A module in node_modules is imported by the name of its package,
+// which is preferred over any local specifier. It returns nil if the module cannot be imported,
+// such as a file of a package that the exports of its package.json do not expose.
+func GetModuleSpecifiers(importingFile *ast.SourceFile, importMode core.ResolutionMode, toFileName string, options *core.CompilerOptions, host Host, preferences UserPreferences) []string {
+	info := newSpecifierInfo(importingFile, importMode, options, host, preferences)
+	modulePaths := append([]string{toFileName}, host.GetSymlinkCache().GetSymlinkedPaths(toFileName)...)
+
+	var nodeModulesSpecifiers, pathsSpecifiers, relativeSpecifiers []string
+	for _, modulePath := range modulePaths {
+		if strings.Contains(modulePath, nodeModulesPathPart) {
+			if specifier := info.tryGetModuleNameAsNodeModule(modulePath); specifier != "" {
+				nodeModulesSpecifiers = appendIfUnique(nodeModulesSpecifiers, specifier)
+			}
+			// Files in node_modules are not imported by relative paths into node_modules.
+			continue
+		}
+		local := info.getLocalModuleSpecifier(modulePath)
+		switch {
+		case local == "":
+		case !tspath.PathIsAbsolute(local) && !tspath.PathIsRelative(local):
+			pathsSpecifiers = appendIfUnique(pathsSpecifiers, local)
+		default:
+			relativeSpecifiers = appendIfUnique(relativeSpecifiers, local)
+		}
+	}
+	switch {
+	case len(nodeModulesSpecifiers) > 0:
+		return nodeModulesSpecifiers
+	case len(pathsSpecifiers) > 0:
+		return pathsSpecifiers
+	}
+	return relativeSpecifiers
+}
+
+// specifierInfo holds what the specifiers of one importing file are computed from.
+type specifierInfo struct {
+	importingFile      *ast.SourceFile
+	importMode         core.ResolutionMode
+	sourceDirectory    string
+	options            *core.CompilerOptions
+	host               Host
+	relativePreference relativePreference
+	// allowedEndings lists the endings that the module resolution of the importing file accepts,
+	// preferred first.
+	allowedEndings []moduleSpecifierEnding
+}
+
+func newSpecifierInfo(importingFile *ast.SourceFile, importMode core.ResolutionMode, options *core.CompilerOptions, host Host, preferences UserPreferences) *specifierInfo {
+	info := &specifierInfo{
+		importingFile:   importingFile,
+		importMode:      importMode,
+		sourceDirectory: tspath.GetDirectoryPath(importingFile.FileName()),
+		options:         options,
+		host:            host,
+	}
+	switch preferences.ImportModuleSpecifierPreference {
+	case ImportModuleSpecifierPreferenceRelative:
+		info.relativePreference = relativePreferenceRelative
+	case ImportModuleSpecifierPreferenceNonRelative:
+		info.relativePreference = relativePreferenceNonRelative
+	case ImportModuleSpecifierPreferenceProjectRelative:
+		info.relativePreference = relativePreferenceExternalNonRelative
+	default:
+		info.relativePreference = relativePreferenceShortest
+	}
+	info.allowedEndings = info.getAllowedEndings(preferences.ImportModuleSpecifierEnding)
+	return info
+}
+
+func (info *specifierInfo) comparePathsOptions() tspath.ComparePathsOptions {
+	return tspath.ComparePathsOptions{
+		UseCaseSensitiveFileNames: info.host.UseCaseSensitiveFileNames(),
+		CurrentDirectory:          info.host.GetCurrentDirectory(),
+	}
+}
+
+func (info *specifierInfo) canonical(fileName string) string {
+	return tspath.GetCanonicalFileName(fileName, info.host.UseCaseSensitiveFileNames())
+}
+
+func (info *specifierInfo) isNodeNextResolution() bool {
+	resolution := info.options.GetModuleResolutionKind()
+	return core.ModuleResolutionKindNode16 <= resolution && resolution <= core.ModuleResolutionKindNodeNext
+}
+
+func (info *specifierInfo) allowImportingTsExtensions() bool {
+	return info.options.AllowImportingTsExtensions.IsTrue() || tspath.IsDeclarationFileName(info.importingFile.FileName())
+}
+
+// getAllowedEndings returns the endings that specifiers can be written with. ES modules under
+// node16 and nodenext must name the file that they import, with its emitted extension.
+func (info *specifierInfo) getAllowedEndings(preference ImportModuleSpecifierEndingPreference) []moduleSpecifierEnding {
+	allowTs := info.allowImportingTsExtensions()
+	if info.importMode == core.ModuleKindESNext && info.isNodeNextResolution() {
+		if allowTs {
+			return []moduleSpecifierEnding{moduleSpecifierEndingTsExtension, moduleSpecifierEndingJsExtension}
+		}
+		return []moduleSpecifierEnding{moduleSpecifierEndingJsExtension}
+	}
+	switch info.getPreferredEnding(preference) {
+	case moduleSpecifierEndingJsExtension:
+		if allowTs {
+			return []moduleSpecifierEnding{moduleSpecifierEndingJsExtension, moduleSpecifierEndingTsExtension, moduleSpecifierEndingMinimal, moduleSpecifierEndingIndex}
+		}
+		return []moduleSpecifierEnding{moduleSpecifierEndingJsExtension, moduleSpecifierEndingMinimal, moduleSpecifierEndingIndex}
+	case moduleSpecifierEndingTsExtension:
+		return []moduleSpecifierEnding{moduleSpecifierEndingTsExtension, moduleSpecifierEndingMinimal, moduleSpecifierEndingJsExtension, moduleSpecifierEndingIndex}
+	case moduleSpecifierEndingIndex:
+		if allowTs {
+			return []moduleSpecifierEnding{moduleSpecifierEndingIndex, moduleSpecifierEndingMinimal, moduleSpecifierEndingTsExtension, moduleSpecifierEndingJsExtension}
+		}
+		return []moduleSpecifierEnding{moduleSpecifierEndingIndex, moduleSpecifierEndingMinimal, moduleSpecifierEndingJsExtension}
+	}
+	if allowTs {
+		return []moduleSpecifierEnding{moduleSpecifierEndingMinimal, moduleSpecifierEndingIndex, moduleSpecifierEndingTsExtension, moduleSpecifierEndingJsExtension}
+	}
+	return []moduleSpecifierEnding{moduleSpecifierEndingMinimal, moduleSpecifierEndingIndex, moduleSpecifierEndingJsExtension}
+}
+
+func (info *specifierInfo) getPreferredEnding(preference ImportModuleSpecifierEndingPreference) moduleSpecifierEnding {
+	switch preference {
+	case ImportModuleSpecifierEndingPreferenceJs:
+		if info.allowImportingTsExtensions() && info.inferEndingFromImports() != moduleSpecifierEndingJsExtension {
+			return moduleSpecifierEndingTsExtension
+		}
+		return moduleSpecifierEndingJsExtension
+	case ImportModuleSpecifierEndingPreferenceMinimal:
+		return moduleSpecifierEndingMinimal
+	case ImportModuleSpecifierEndingPreferenceIndex:
+		return moduleSpecifierEndingIndex
+	}
+	return info.inferEndingFromImports()
+}
+
+// inferEndingFromImports returns the ending that the existing relative imports of the importing
+// file are written with.
+func (info *specifierInfo) inferEndingFromImports() moduleSpecifierEnding {
+	usesJsExtensions := false
+	for _, specifier := range info.importingFile.Imports {
+		text := specifier.Text()
+		if !tspath.PathIsRelative(text) || tspath.FileExtensionIsOneOf(text, extensionsNotSupportingExtensionlessResolution) {
+			continue
+		}
+		if tspath.HasTSFileExtension(text) && info.allowImportingTsExtensions() {
+			return moduleSpecifierEndingTsExtension
+		}
+		if tspath.HasJSFileExtension(text) {
+			usesJsExtensions = true
+		}
+	}
+	if usesJsExtensions {
+		return moduleSpecifierEndingJsExtension
+	}
+	return moduleSpecifierEndingMinimal
+}
+
+// getLocalModuleSpecifier returns the relative specifier of a file, or the non-relative one from
+// package.json imports, paths or baseUrl if the preference picks it.
+func (info *specifierInfo) getLocalModuleSpecifier(moduleFileName string) string {
+	options := info.options
+	relativePath := processEnding(tspath.EnsurePathIsNonModuleName(tspath.GetRelativePathFromDirectory(info.sourceDirectory, moduleFileName, info.comparePathsOptions())), info.sourceDirectory, info.allowedEndings, options, info.host)
+	resolvesImports := module.GetNodeResolutionFeatures(options)&module.NodeResolutionFeaturesImports != 0
+	if options.BaseUrl == "" && options.Paths.Size() == 0 && !resolvesImports || info.relativePreference == relativePreferenceRelative {
+		return relativePath
+	}
+
+	maybeNonRelative := info.tryGetModuleNameFromPackageJsonImports(moduleFileName)
+	if maybeNonRelative == "" && (options.BaseUrl != "" || options.Paths.Size() != 0) {
+		baseDirectory := tspath.GetNormalizedAbsolutePath(core.OrElse(module.GetPathsBasePath(options, info.host.GetCurrentDirectory()), options.BaseUrl), info.host.GetCurrentDirectory())
+		relativeToBaseUrl, ok := info.getRelativePathIfInSameVolume(moduleFileName, baseDirectory)
+		if !ok {
+			return relativePath
+		}
+		if options.Paths.Size() != 0 {
+			maybeNonRelative = info.tryGetModuleNameFromPaths(relativeToBaseUrl, options.Paths, baseDirectory)
+		}
+		if maybeNonRelative == "" && options.BaseUrl != "" {
+			maybeNonRelative = processEnding(relativeToBaseUrl, baseDirectory, info.allowedEndings, options, info.host)
+		}
+	}
+	if maybeNonRelative == "" {
+		return relativePath
+	}
+
+	switch info.relativePreference {
+	case relativePreferenceNonRelative:
+		if !isPathRelativeToParent(maybeNonRelative) {
+			return maybeNonRelative
+		}
+	case relativePreferenceExternalNonRelative:
+		if !isPathRelativeToParent(maybeNonRelative) {
+			// Files of the same package are imported relatively, and files of other packages or
+			// outside of the project are not.
+			projectDirectory := info.host.GetCurrentDirectory()
+			if options.ConfigFilePath != "" {
+				projectDirectory = tspath.GetDirectoryPath(options.ConfigFilePath)
+			}
+			projectDirectory = tspath.EnsureTrailingDirectorySeparator(info.canonical(projectDirectory))
+			sourceIsInternal := strings.HasPrefix(info.canonical(info.sourceDirectory)+"/", projectDirectory)
+			targetIsInternal := strings.HasPrefix(info.canonical(moduleFileName), projectDirectory)
+			if sourceIsInternal != targetIsInternal {
+				return maybeNonRelative
+			}
+			targetScope := info.host.GetPackageScopeForPath(tspath.GetDirectoryPath(moduleFileName))
+			sourceScope := info.host.GetPackageScopeForPath(info.sourceDirectory)
+			if getPackageDirectory(targetScope) != getPackageDirectory(sourceScope) {
+				return maybeNonRelative
+			}
+			return relativePath
+		}
+	}
+	if isPathRelativeToParent(maybeNonRelative) || countPathComponents(relativePath) < countPathComponents(maybeNonRelative) {
+		return relativePath
+	}
+	return maybeNonRelative
+}
+
+func getPackageDirectory(scope *packagejson.InfoCacheEntry) string {
+	if !scope.Exists() {
+		return ""
+	}
+	return scope.PackageDirectory
+}
+
+func (info *specifierInfo) getRelativePathIfInSameVolume(path string, directory string) (string, bool) {
+	relativePath := tspath.GetRelativePathFromDirectory(directory, path, info.comparePathsOptions())
+	if tspath.IsRootedDiskPath(relativePath) {
+		return "", false
+	}
+	return relativePath, true
+}
+
+// tryGetModuleNameFromPaths returns the key of a path mapping that one of the patterns of matches
+// a file, with the star replaced. It is used both for the paths option and for the typesVersions
+// of a package.
+func (info *specifierInfo) tryGetModuleNameFromPaths(relativeToBaseUrl string, paths *collections.OrderedMap[string, []string], baseDirectory string) string {
+	for key, patterns := range paths.Entries() {
+		for _, patternText := range patterns {
+			pattern := tspath.GetRelativePathFromDirectory(baseDirectory, tspath.GetNormalizedAbsolutePath(patternText, baseDirectory), info.comparePathsOptions())
+			candidates := make([]string, 0, len(info.allowedEndings)+1)
+			for _, ending := range info.allowedEndings {
+				candidates = append(candidates, processEnding(relativeToBaseUrl, baseDirectory, []moduleSpecifierEnding{ending}, info.options, info.host))
+			}
+			if tspath.TryGetExtensionFromPath(pattern) != "" {
+				candidates = append(candidates, relativeToBaseUrl)
+			}
+			if prefix, suffix, ok := strings.Cut(pattern, "*"); ok {
+				for _, candidate := range candidates {
+					if len(candidate) >= len(prefix)+len(suffix) && strings.HasPrefix(candidate, prefix) && strings.HasSuffix(candidate, suffix) {
+						matchedStar := candidate[len(prefix) : len(candidate)-len(suffix)]
+						if !tspath.PathIsRelative(matchedStar) {
+							return strings.Replace(key, "*", matchedStar, 1)
+						}
+					}
+				}
+			} else if slices.Contains(candidates, pattern) {
+				return key
+			}
+		}
+	}
+	return ""
+}
+
+// tryGetModuleNameFromPackageJsonImports returns the specifier starting with # that the imports
+// of the package of the importing file map to a file.
+func (info *specifierInfo) tryGetModuleNameFromPackageJsonImports(moduleFileName string) string {
+	if module.GetNodeResolutionFeatures(info.options)&module.NodeResolutionFeaturesImports == 0 {
+		return ""
+	}
+	scope := info.host.GetPackageScopeForPath(info.sourceDirectory)
+	if !scope.Exists() || scope.Contents.Imports.Type != packagejson.JSONValueTypeObject {
+		return ""
+	}
+	conditions := module.GetConditions(info.options, info.importMode)
+	for key, target := range scope.Contents.Imports.AsObject().Entries() {
+		if !strings.HasPrefix(key, "#") || key == "#" || strings.HasPrefix(key, "#/") {
+			continue
+		}
+		if specifier := info.tryGetModuleNameFromExportsOrImports(moduleFileName, scope.PackageDirectory, key, target, conditions, getMatchingMode(key), true /*isImports*/); specifier != "" {
+			return specifier
+		}
+	}
+	return ""
+}
+
+func getMatchingMode(key string) matchingMode {
+	switch {
+	case strings.HasSuffix(key, "/"):
+		return matchingModeDirectory
+	case strings.Contains(key, "*"):
+		return matchingModePattern
+	}
+	return matchingModeExact
+}
+
+// tryGetModuleNameFromExports returns the specifier, starting with packageName, that the exports
+// of a package map to a file.
+func (info *specifierInfo) tryGetModuleNameFromExports(targetFilePath string, packageDirectory string, packageName string, exports packagejson.ExportsOrImports, conditions []string) string {
+	switch exports.Type {
+	case packagejson.JSONValueTypeString:
+		return info.tryGetModuleNameFromExportsOrImports(targetFilePath, packageDirectory, packageName, exports, conditions, matchingModeExact, false /*isImports*/)
+	case packagejson.JSONValueTypeArray:
+		for _, element := range exports.AsArray() {
+			if specifier := info.tryGetModuleNameFromExports(targetFilePath, packageDirectory, packageName, element, conditions); specifier != "" {
+				return specifier
+			}
+		}
+	case packagejson.JSONValueTypeObject:
+		if exports.IsSubpaths() {
+			for key, target := range exports.AsObject().Entries() {
+				subPackageName := tspath.GetNormalizedAbsolutePath(tspath.CombinePaths(packageName, key), "")
+				if specifier := info.tryGetModuleNameFromExportsOrImports(targetFilePath, packageDirectory, subPackageName, target, conditions, getMatchingMode(key), false /*isImports*/); specifier != "" {
+					return specifier
+				}
+			}
+			return ""
+		}
+		for condition, target := range exports.AsObject().Entries() {
+			if module.ConditionMatches(conditions, condition) {
+				if specifier := info.tryGetModuleNameFromExports(targetFilePath, packageDirectory, packageName, target, conditions); specifier != "" {
+					return specifier
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// tryGetModuleNameFromExportsOrImports returns packageName, with the part that a directory or
+// pattern target matches filled in, if a target of an exports or imports map is the file
+// targetFilePath. Targets name emitted files, so source and declaration files match the targets
+// with their JavaScript extension, and local source files of imports match their output files.
+func (info *specifierInfo) tryGetModuleNameFromExportsOrImports(targetFilePath string, packageDirectory string, packageName string, exports packagejson.ExportsOrImports, conditions []string, mode matchingMode, isImports bool) string {
+	switch exports.Type {
+	case packagejson.JSONValueTypeString:
+		target, _ := exports.Value.(string)
+		pathOrPattern := tspath.GetNormalizedAbsolutePath(tspath.CombinePaths(packageDirectory, target), "")
+		var candidates []string
+		if tspath.HasTSFileExtension(targetFilePath) {
+			candidates = append(candidates, tspath.RemoveFileExtension(targetFilePath)+core.GetOutputExtension(targetFilePath, info.options.Jsx))
+		}
+		candidates = append(candidates, targetFilePath)
+		if isImports && !tspath.IsDeclarationFileName(targetFilePath) && !tspath.HasJSONFileExtension(targetFilePath) {
+			candidates = append(candidates, info.getOutputFileNames(targetFilePath)...)
+		}
+		options := info.comparePathsOptions()
+		for _, candidate := range candidates {
+			switch mode {
+			case matchingModeExact:
+				if tspath.ComparePaths(candidate, pathOrPattern, options) == 0 {
+					return packageName
+				}
+			case matchingModeDirectory:
+				if tspath.ContainsPath(pathOrPattern, candidate, options) {
+					fragment := tspath.GetRelativePathFromDirectory(pathOrPattern, candidate, options)
+					return tspath.GetNormalizedAbsolutePath(tspath.CombinePaths(packageName, fragment), "")
+				}
+			case matchingModePattern:
+				leading, trailing, _ := strings.Cut(pathOrPattern, "*")
+				if len(candidate) >= len(leading)+len(trailing) &&
+					strings.HasPrefix(info.canonical(candidate), info.canonical(leading)) &&
+					strings.HasSuffix(info.canonical(candidate), info.canonical(trailing)) {
+					return strings.Replace(packageName, "*", candidate[len(leading):len(candidate)-len(trailing)], 1)
+				}
+			}
+		}
+	case packagejson.JSONValueTypeArray:
+		for _, element := range exports.AsArray() {
+			if specifier := info.tryGetModuleNameFromExportsOrImports(targetFilePath, packageDirectory, packageName, element, conditions, mode, isImports); specifier != "" {
+				return specifier
+			}
+		}
+	case packagejson.JSONValueTypeObject:
+		for condition, target := range exports.AsObject().Entries() {
+			if module.ConditionMatches(conditions, condition) {
+				if specifier := info.tryGetModuleNameFromExportsOrImports(targetFilePath, packageDirectory, packageName, target, conditions, mode, isImports); specifier != "" {
+					return specifier
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// getOutputFileNames returns the JavaScript and declaration files that a source file is emitted to.
+func (info *specifierInfo) getOutputFileNames(fileName string) []string {
+	getOutputPath := func(outputDirectory string) string {
+		if outputDirectory == "" {
+			return fileName
+		}
+		commonSourceDirectory := info.host.GetCommonSourceDirectory()
+		return tspath.ResolvePath(outputDirectory, tspath.GetRelativePathFromDirectory(commonSourceDirectory, fileName, info.comparePathsOptions()))
+	}
+	js := tspath.ChangeExtension(getOutputPath(info.options.OutDir), core.GetOutputExtension(fileName, info.options.Jsx))
+	declaration := tspath.ChangeExtension(getOutputPath(core.OrElse(info.options.DeclarationDir, info.options.OutDir)), tspath.GetDeclarationEmitExtensionForPath(fileName))
+	return []string{js, declaration}
+}
+
+// nodeModulePathParts are the indices of the parts of a path into node_modules, such as
+// /base/node_modules/@scope/package/node_modules/dependency/subdirectory/file.js.
+type nodeModulePathParts struct {
+	// topLevelNodeModulesIndex is the index of the first /node_modules/.
+	topLevelNodeModulesIndex int
+	// topLevelPackageNameIndex is the index of the separator before the first package name.
+	topLevelPackageNameIndex int
+	// packageRootIndex is the index of the separator after the innermost package name.
+	packageRootIndex int
+	// fileNameIndex is the index of the separator before the file name.
+	fileNameIndex int
+}
+
+func getNodeModulePathParts(fullPath string) (nodeModulePathParts, bool) {
+	const (
+		stateBeforeNodeModules = iota
+		stateNodeModules
+		stateScope
+		statePackageContent
+	)
+	var parts nodeModulePathParts
+	partStart := 0
+	partEnd := 0
+	state := stateBeforeNodeModules
+	for partEnd >= 0 {
+		partStart = partEnd
+		partEnd = strings.Index(fullPath[partStart+1:], "/")
+		if partEnd >= 0 {
+			partEnd += partStart + 1
+		}
+		switch state {
+		case stateBeforeNodeModules:
+			if strings.HasPrefix(fullPath[partStart:], nodeModulesPathPart) {
+				parts.topLevelNodeModulesIndex = partStart
+				parts.topLevelPackageNameIndex = partEnd
+				state = stateNodeModules
+			}
+		case stateNodeModules, stateScope:
+			if state == stateNodeModules && partStart+1 < len(fullPath) && fullPath[partStart+1] == '@' {
+				state = stateScope
+			} else {
+				parts.packageRootIndex = partEnd
+				state = statePackageContent
+			}
+		case statePackageContent:
+			if strings.HasPrefix(fullPath[partStart:], nodeModulesPathPart) {
+				state = stateNodeModules
+			}
+		}
+	}
+	parts.fileNameIndex = partStart
+	return parts, state > stateNodeModules
+}
+
+// tryGetModuleNameAsNodeModule returns the specifier of a file in node_modules by the name of its
+// package, or "" if the importing file cannot see that node_modules directory, or the package
+// does not expose the file.
+func (info *specifierInfo) tryGetModuleNameAsNodeModule(path string) string {
+	parts, ok := getNodeModulePathParts(path)
+	if !ok || parts.packageRootIndex < 0 {
+		return ""
+	}
+	// The node_modules directory must be in the importing file's directory or an ancestor of it.
+	pathToTopLevelNodeModules := info.canonical(path[:parts.topLevelNodeModulesIndex]) + "/"
+	if !strings.HasPrefix(info.canonical(info.sourceDirectory)+"/", pathToTopLevelNodeModules) {
+		return ""
+	}
+
+	moduleSpecifier := ""
+	moduleFileName := ""
+	for packageRootIndex := parts.packageRootIndex; ; {
+		result := info.tryDirectoryWithPackageJson(path, parts, packageRootIndex)
+		if result.blockedByExports {
+			return ""
+		}
+		if result.verbatimFromExports {
+			return result.moduleFileToTry
+		}
+		if result.packageRootPath != "" {
+			moduleSpecifier = result.packageRootPath
+			break
+		}
+		if moduleFileName == "" {
+			moduleFileName = result.moduleFileToTry
+		}
+		// Try the directories of the path within the package, in case they have a package.json of their own.
+		next := strings.Index(path[packageRootIndex+1:], "/")
+		if next < 0 {
+			moduleSpecifier = processEnding(moduleFileName, "", info.allowedEndings, info.options, info.host)
+			break
+		}
+		packageRootIndex += next + 1
+	}
+	return getPackageNameFromTypesPackageName(moduleSpecifier[parts.topLevelPackageNameIndex+1:])
+}
+
+type packageJsonResult struct {
+	moduleFileToTry string
+	// packageRootPath is set when the file is the entry point of the package, so that the
+	// package is imported by its name alone.
+	packageRootPath     string
+	blockedByExports    bool
+	verbatimFromExports bool
+}
+
+func (info *specifierInfo) tryDirectoryWithPackageJson(path string, parts nodeModulePathParts, packageRootIndex int) packageJsonResult {
+	packageRootPath := path[:packageRootIndex]
+	moduleFileToTry := path
+	packageJson := info.host.GetPackageJsonInfo(packageRootPath)
+	if !packageJson.Exists() {
+		fileName := info.canonical(moduleFileToTry[parts.packageRootIndex+1:])
+		switch fileName {
+		case "index.d.ts", "index.js", "index.ts", "index.tsx":
+			return packageJsonResult{moduleFileToTry: moduleFileToTry, packageRootPath: packageRootPath}
+		}
+		return packageJsonResult{moduleFileToTry: moduleFileToTry}
+	}
+
+	contents := packageJson.Contents
+	if module.GetNodeResolutionFeatures(info.options)&module.NodeResolutionFeaturesExports != 0 && contents.Exports.Type != packagejson.JSONValueTypeNotPresent {
+		packageName := getPackageNameFromTypesPackageName(packageRootPath[parts.topLevelPackageNameIndex+1:])
+		conditions := module.GetConditions(info.options, info.importMode)
+		if specifier := info.tryGetModuleNameFromExports(path, packageRootPath, packageName, contents.Exports, conditions); specifier != "" {
+			return packageJsonResult{moduleFileToTry: specifier, verbatimFromExports: true}
+		}
+		// Files that the exports do not expose cannot be imported at all.
+		return packageJsonResult{moduleFileToTry: path, blockedByExports: true}
+	}
+
+	var versionPaths *collections.OrderedMap[string, []string]
+	maybeBlockedByTypesVersions := false
+	if typesVersions := contents.GetVersionPaths(nil); typesVersions.Exists() {
+		versionPaths = typesVersions.GetPaths()
+		subModuleName := path[len(packageRootPath)+1:]
+		if fromPaths := info.tryGetModuleNameFromPaths(subModuleName, versionPaths, packageRootPath); fromPaths != "" {
+			moduleFileToTry = tspath.CombinePaths(packageRootPath, fromPaths)
+		} else {
+			maybeBlockedByTypesVersions = true
+		}
+	}
+
+	mainFileRelative := "index.js"
+	for _, field := range []packagejson.Expected[string]{contents.Typings, contents.Types, contents.Main} {
+		if value, ok := field.GetValue(); ok && value != "" {
+			mainFileRelative = value
+			break
+		}
+	}
+	if maybeBlockedByTypesVersions && matchesAnyPattern(versionPaths, mainFileRelative) {
+		return packageJsonResult{moduleFileToTry: moduleFileToTry}
+	}
+	mainExportFile := info.canonical(tspath.GetNormalizedAbsolutePath(mainFileRelative, packageRootPath))
+	canonicalModuleFileToTry := info.canonical(moduleFileToTry)
+	if tspath.RemoveFileExtension(mainExportFile) == tspath.RemoveFileExtension(canonicalModuleFileToTry) {
+		return packageJsonResult{moduleFileToTry: moduleFileToTry, packageRootPath: packageRootPath}
+	}
+	// A main field that names a directory resolves to its index file, except in ES module packages.
+	if packageType, _ := contents.Type.GetValue(); packageType != "module" &&
+		!tspath.FileExtensionIsOneOf(canonicalModuleFileToTry, extensionsNotSupportingExtensionlessResolution) &&
+		tspath.GetDirectoryPath(canonicalModuleFileToTry) == tspath.RemoveTrailingDirectorySeparator(mainExportFile) &&
+		tspath.RemoveFileExtension(tspath.GetBaseFileName(canonicalModuleFileToTry)) == "index" {
+		return packageJsonResult{moduleFileToTry: moduleFileToTry, packageRootPath: packageRootPath}
+	}
+	return packageJsonResult{moduleFileToTry: moduleFileToTry}
+}
+
+func matchesAnyPattern(paths *collections.OrderedMap[string, []string], candidate string) bool {
+	for key := range paths.Keys() {
+		if pattern := core.TryParsePattern(key); pattern.IsValid() && pattern.Matches(candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// getPackageNameFromTypesPackageName returns the name of the package that a package in @types
+// declares, such as @scope/name for @types/scope__name.
+func getPackageNameFromTypesPackageName(mangledName string) string {
+	if withoutAtTypes, ok := strings.CutPrefix(mangledName, "@types/"); ok {
+		return module.UnmangleScopedPackageName(withoutAtTypes)
+	}
+	return mangledName
+}
+
+// processEnding writes the file name at the end of a specifier with the first of the allowed
+// endings. Relative file names are relative to directory.
+func processEnding(fileName string, directory string, allowedEndings []moduleSpecifierEnding, options *core.CompilerOptions, host Host) string {
+	if tspath.FileExtensionIsOneOf(fileName, []string{tspath.ExtensionJson, tspath.ExtensionMjs, tspath.ExtensionCjs}) {
+		return fileName
+	}
+	noExtension := tspath.RemoveFileExtension(fileName)
+	if noExtension == fileName {
+		return fileName
+	}
+	jsPriority := slices.Index(allowedEndings, moduleSpecifierEndingJsExtension)
+	tsPriority := slices.Index(allowedEndings, moduleSpecifierEndingTsExtension)
+	if tspath.FileExtensionIsOneOf(fileName, []string{tspath.ExtensionMts, tspath.ExtensionCts}) && tsPriority != -1 && (jsPriority == -1 || tsPriority < jsPriority) {
+		return fileName
+	}
+	// .mts and .cts files are never resolved without their extension.
+	if tspath.FileExtensionIsOneOf(fileName, []string{tspath.ExtensionDmts, tspath.ExtensionMts, tspath.ExtensionDcts, tspath.ExtensionCts}) {
+		return noExtension + core.GetOutputExtension(fileName, options.Jsx)
+	}
+
+	switch allowedEndings[0] {
+	case moduleSpecifierEndingMinimal:
+		withoutIndex, ok := strings.CutSuffix(noExtension, "/index")
+		// A sibling file by the name of the directory would be resolved instead of its index.
+		if ok && host != nil && fileExistsWithAnyExtension(host, tspath.GetNormalizedAbsolutePath(withoutIndex, directory)) {
+			return noExtension
+		}
+		return withoutIndex
+	case moduleSpecifierEndingIndex:
+		return noExtension
+	case moduleSpecifierEndingJsExtension:
+		return noExtension + core.GetOutputExtension(fileName, options.Jsx)
+	case moduleSpecifierEndingTsExtension:
+		if tspath.IsDeclarationFileName(fileName) {
+			extensionlessPriority := slices.IndexFunc(allowedEndings, func(ending moduleSpecifierEnding) bool {
+				return ending == moduleSpecifierEndingMinimal || ending == moduleSpecifierEndingIndex
+			})
+			if extensionlessPriority != -1 && (jsPriority == -1 || extensionlessPriority < jsPriority) {
+				return noExtension
+			}
+			return noExtension + core.GetOutputExtension(fileName, options.Jsx)
+		}
+		return fileName
+	}
+	return fileName
+}
+
+func fileExistsWithAnyExtension(host Host, path string) bool {
+	for _, extension := range []string{tspath.ExtensionTs, tspath.ExtensionTsx, tspath.ExtensionDts, tspath.ExtensionJs, tspath.ExtensionJsx} {
+		if host.FileExists(path + extension) {
+			return true
+		}
+	}
+	return false
+}
+
+func isPathRelativeToParent(path string) bool {
+	return strings.HasPrefix(path, "..")
+}
+
+func countPathComponents(path string) int {
+	return strings.Count(strings.TrimPrefix(path, "./"), "/")
+}
+
+func appendIfUnique(specifiers []string, specifier string) []string {
+	if slices.Contains(specifiers, specifier) {
+		return specifiers
+	}
+	return append(specifiers, specifier)
+}
@@ -0,0 +1,191 @@
+package modulespecifiers_test
+
+import (
+	"testing"
+
+	"github.com/microsoft/typescript-go/internal/collections"
+	"github.com/microsoft/typescript-go/internal/compiler/module"
+	"github.com/microsoft/typescript-go/internal/compiler/packagejson"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/modulespecifiers"
+	"github.com/microsoft/typescript-go/internal/parser"
+	"github.com/microsoft/typescript-go/internal/scanner"
+	"github.com/microsoft/typescript-go/internal/tspath"
+	"github.com/microsoft/typescript-go/internal/vfs"
+	"github.com/microsoft/typescript-go/internal/vfs/vfstest"
+	"gotest.tools/v3/assert"
+)
+
+type testHost struct {
+	fs       vfs.FS
+	resolver *module.Resolver
+	symlinks *modulespecifiers.SymlinkCache
+}
+
+func (h *testHost) FS() vfs.FS                      { return h.fs }
+func (h *testHost) GetCurrentDirectory() string     { return "/project" }
+func (h *testHost) Trace(msg string)                {}
+func (h *testHost) UseCaseSensitiveFileNames() bool { return true }
+func (h *testHost) FileExists(fileName string) bool { return h.fs.FileExists(fileName) }
+func (h *testHost) GetCommonSourceDirectory() string {
+	return "/project/src"
+}
+
+func (h *testHost) GetPackageJsonInfo(packageDirectory string) *packagejson.InfoCacheEntry {
+	return h.resolver.GetPackageJsonInfo(packageDirectory)
+}
+
+func (h *testHost) GetPackageScopeForPath(directory string) *packagejson.InfoCacheEntry {
+	return h.resolver.GetPackageScopeForPath(directory)
+}
+
+func (h *testHost) GetSymlinkCache() *modulespecifiers.SymlinkCache { return h.symlinks }
+
+func TestGetModuleSpecifiers(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"/project/src/main.ts":                       "",
+		"/project/src/utils/a.ts":                    "export const a = 1;",
+		"/project/src/utils/index.ts":                "export * from './a';",
+		"/project/src/lib/deep/b.ts":                 "export const b = 1;",
+		"/project/node_modules/plain/package.json":   `{ "name": "plain", "types": "./lib/index.d.ts" }`,
+		"/project/node_modules/plain/lib/index.d.ts": "export declare const plain: number;",
+		"/project/node_modules/plain/lib/other.d.ts": "export declare const other: number;",
+		"/project/node_modules/exported/package.json": `{
+			"name": "exported",
+			"exports": {
+				".": { "types": "./dist/index.d.ts", "default": "./dist/index.js" },
+				"./features/*": "./dist/features/*.js"
+			}
+		}`,
+		"/project/node_modules/exported/dist/index.d.ts":        "export declare const x: number;",
+		"/project/node_modules/exported/dist/features/one.d.ts": "export declare const one: number;",
+		"/project/node_modules/exported/dist/internal.d.ts":     "export declare const internal: number;",
+		"/project/node_modules/versioned/package.json": `{
+			"name": "versioned",
+			"types": "./index.d.ts",
+			"typesVersions": { "*": { "*": ["ts/*"] } }
+		}`,
+		"/project/node_modules/versioned/ts/index.d.ts":        "export declare const v: number;",
+		"/project/node_modules/versioned/ts/sub.d.ts":          "export declare const sub: number;",
+		"/project/node_modules/@types/scope__typed/index.d.ts": "export declare const typed: number;",
+		"/project/node_modules/workspace/package.json":         `{ "name": "workspace", "types": "./src/index.ts" }`,
+		"/project/node_modules/workspace/src/index.ts":         "export const w = 1;",
+		"/project/node_modules/workspace/src/helper.ts":        "export const helper = 1;",
+		"/packages/workspace/package.json":                     `{ "name": "workspace", "types": "./src/index.ts" }`,
+		"/packages/workspace/src/index.ts":                     "export const w = 1;",
+		"/packages/workspace/src/helper.ts":                    "export const helper = 1;",
+		"/project/esm/package.json":                            `{ "type": "module", "imports": { "#internal/*": "./internal/*.js" } }`,
+		"/project/esm/main.ts":                                 "",
+		"/project/esm/util.ts":                                 "export const u = 1;",
+		"/project/esm/internal/secret.ts":                      "export const s = 1;",
+	}
+
+	tests := []struct {
+		name          string
+		importingFile string
+		importMode    core.ResolutionMode
+		toFileName    string
+		options       *core.CompilerOptions
+		preferences   modulespecifiers.UserPreferences
+		expected      []string
+	}{
+		{name: "relative sibling directory", importingFile: "/project/src/main.ts", toFileName: "/project/src/utils/a.ts", expected: []string{"./utils/a"}},
+		{name: "relative index", importingFile: "/project/src/main.ts", toFileName: "/project/src/utils/index.ts", expected: []string{"./utils"}},
+		{name: "relative parent", importingFile: "/project/src/lib/deep/b.ts", toFileName: "/project/src/utils/a.ts", expected: []string{"../../utils/a"}},
+		{
+			name:          "index ending",
+			importingFile: "/project/src/main.ts",
+			toFileName:    "/project/src/utils/index.ts",
+			preferences:   modulespecifiers.UserPreferences{ImportModuleSpecifierEnding: modulespecifiers.ImportModuleSpecifierEndingPreferenceIndex},
+			expected:      []string{"./utils/index"},
+		},
+		{
+			name:          "node16 ES module requires js extension",
+			importingFile: "/project/esm/main.ts",
+			importMode:    core.ModuleKindESNext,
+			toFileName:    "/project/esm/util.ts",
+			options:       &core.CompilerOptions{ModuleKind: core.ModuleKindNode16, ModuleResolution: core.ModuleResolutionKindNode16},
+			expected:      []string{"./util.js"},
+		},
+		{
+			name:          "package.json imports",
+			importingFile: "/project/esm/main.ts",
+			importMode:    core.ModuleKindESNext,
+			toFileName:    "/project/esm/internal/secret.ts",
+			options:       &core.CompilerOptions{ModuleKind: core.ModuleKindNodeNext, ModuleResolution: core.ModuleResolutionKindNodeNext},
+			preferences:   modulespecifiers.UserPreferences{ImportModuleSpecifierPreference: modulespecifiers.ImportModuleSpecifierPreferenceNonRelative},
+			expected:      []string{"#internal/secret"},
+		},
+		{
+			name:          "paths shorter than relative",
+			importingFile: "/project/src/lib/deep/b.ts",
+			toFileName:    "/project/src/utils/a.ts",
+			options:       &core.CompilerOptions{Paths: newPaths("@utils/*", "./src/utils/*"), PathsBasePath: "/project"},
+			expected:      []string{"@utils/a"},
+		},
+		{
+			name:          "relative preference ignores paths",
+			importingFile: "/project/src/lib/deep/b.ts",
+			toFileName:    "/project/src/utils/a.ts",
+			options:       &core.CompilerOptions{Paths: newPaths("@utils/*", "./src/utils/*"), PathsBasePath: "/project"},
+			preferences:   modulespecifiers.UserPreferences{ImportModuleSpecifierPreference: modulespecifiers.ImportModuleSpecifierPreferenceRelative},
+			expected:      []string{"../../utils/a"},
+		},
+		{
+			name:          "baseUrl",
+			importingFile: "/project/src/lib/deep/b.ts",
+			toFileName:    "/project/src/utils/a.ts",
+			options:       &core.CompilerOptions{BaseUrl: "/project/src"},
+			preferences:   modulespecifiers.UserPreferences{ImportModuleSpecifierPreference: modulespecifiers.ImportModuleSpecifierPreferenceNonRelative},
+			expected:      []string{"utils/a"},
+		},
+		{name: "package main", importingFile: "/project/src/main.ts", toFileName: "/project/node_modules/plain/lib/index.d.ts", expected: []string{"plain"}},
+		{name: "package subpath", importingFile: "/project/src/main.ts", toFileName: "/project/node_modules/plain/lib/other.d.ts", expected: []string{"plain/lib/other"}},
+		{name: "exports root", importingFile: "/project/src/main.ts", toFileName: "/project/node_modules/exported/dist/index.d.ts", expected: []string{"exported"}},
+		{name: "exports pattern", importingFile: "/project/src/main.ts", toFileName: "/project/node_modules/exported/dist/features/one.d.ts", expected: []string{"exported/features/one"}},
+		{name: "blocked by exports", importingFile: "/project/src/main.ts", toFileName: "/project/node_modules/exported/dist/internal.d.ts", expected: nil},
+		{name: "typesVersions", importingFile: "/project/src/main.ts", toFileName: "/project/node_modules/versioned/ts/sub.d.ts", expected: []string{"versioned/sub"}},
+		{name: "types package", importingFile: "/project/src/main.ts", toFileName: "/project/node_modules/@types/scope__typed/index.d.ts", expected: []string{"@scope/typed"}},
+		{name: "symlinked package", importingFile: "/project/src/main.ts", toFileName: "/packages/workspace/src/helper.ts", expected: []string{"workspace/src/helper"}},
+		{name: "symlinked package entry", importingFile: "/project/src/main.ts", toFileName: "/packages/workspace/src/index.ts", expected: []string{"workspace"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			options := test.options
+			if options == nil {
+				options = &core.CompilerOptions{ModuleKind: core.ModuleKindESNext, ModuleResolution: core.ModuleResolutionKindBundler}
+			}
+			host := &testHost{fs: vfstest.FromMap(files, true /*useCaseSensitiveFileNames*/)}
+			host.resolver = module.NewResolver(host, options)
+			host.symlinks = modulespecifiers.NewSymlinkCache(true /*useCaseSensitiveFileNames*/)
+			host.symlinks.SetSymlinkedDirectoryFromResolution("/packages/workspace/src/index.ts", "/project/node_modules/workspace/src/index.ts")
+
+			importingFile := parser.ParseSourceFile(test.importingFile, tspath.Path(test.importingFile), files[test.importingFile], core.ScriptTargetESNext, scanner.JSDocParsingModeParseAll)
+			actual := modulespecifiers.GetModuleSpecifiers(importingFile, test.importMode, test.toFileName, options, host, test.preferences)
+			assert.DeepEqual(t, actual, test.expected)
+		})
+	}
+}
+
+func TestSymlinkCache(t *testing.T) {
+	t.Parallel()
+
+	cache := modulespecifiers.NewSymlinkCache(true /*useCaseSensitiveFileNames*/)
+	cache.SetSymlinkedDirectoryFromResolution("/repo/packages/pkg/src/index.ts", "/repo/app/node_modules/pkg/src/index.ts")
+	cache.SetSymlinkedDirectoryFromResolution("/repo/packages/@scope/other/index.ts", "/repo/app/node_modules/@scope/other/index.ts")
+	cache.SetSymlinkedDirectoryFromResolution("/repo/src/a.ts", "/repo/src/a.ts")
+
+	assert.DeepEqual(t, cache.GetSymlinkedPaths("/repo/packages/pkg/lib/util.ts"), []string{"/repo/app/node_modules/pkg/lib/util.ts"})
+	assert.DeepEqual(t, cache.GetSymlinkedPaths("/repo/packages/@scope/other/index.ts"), []string{"/repo/app/node_modules/@scope/other/index.ts"})
+	assert.DeepEqual(t, cache.GetSymlinkedPaths("/repo/src/a.ts"), []string(nil))
+}
+
+func newPaths(key string, patterns ...string) *collections.OrderedMap[string, []string] {
+	paths := collections.NewOrderedMapWithSizeHint[string, []string](1)
+	paths.Set(key, patterns)
+	return paths
+}
@@ -0,0 +1,81 @@
+package modulespecifiers
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/microsoft/typescript-go/internal/tspath"
+)
+
+// SymlinkCache records the directories that are reachable through symlinks, such as the packages
+// of a workspace that are linked into node_modules, so that files in them can be imported by the
+// name of their package.
+type SymlinkCache struct {
+	useCaseSensitiveFileNames bool
+	directories               []symlinkedDirectory
+}
+
+type symlinkedDirectory struct {
+	// real is the directory that the symlink resolves to, with a trailing separator.
+	real string
+	// symlink is the directory of the symlink itself, with a trailing separator.
+	symlink string
+}
+
+func NewSymlinkCache(useCaseSensitiveFileNames bool) *SymlinkCache {
+	return &SymlinkCache{useCaseSensitiveFileNames: useCaseSensitiveFileNames}
+}
+
+// SetSymlinkedDirectoryFromResolution records the symlinked directory that a module resolution
+// went through, if it did: originalPath is the resolved file name before symlinks were followed.
+// The directory is guessed from the longest common suffix of the two paths, which stops at
+// node_modules so that the symlink is of a package rather than of a directory inside it.
+func (c *SymlinkCache) SetSymlinkedDirectoryFromResolution(resolvedFileName string, originalPath string) {
+	if originalPath == "" || originalPath == resolvedFileName {
+		return
+	}
+	realParts := tspath.GetPathComponents(resolvedFileName, "")
+	symlinkParts := tspath.GetPathComponents(originalPath, "")
+	isDirectory := false
+	for len(realParts) >= 2 && len(symlinkParts) >= 2 &&
+		!isNodeModulesOrScopedPackageDirectory(realParts[len(realParts)-2]) &&
+		!isNodeModulesOrScopedPackageDirectory(symlinkParts[len(symlinkParts)-2]) &&
+		c.canonical(realParts[len(realParts)-1]) == c.canonical(symlinkParts[len(symlinkParts)-1]) {
+		realParts = realParts[:len(realParts)-1]
+		symlinkParts = symlinkParts[:len(symlinkParts)-1]
+		isDirectory = true
+	}
+	if !isDirectory {
+		return
+	}
+	directory := symlinkedDirectory{
+		real:    tspath.EnsureTrailingDirectorySeparator(tspath.GetPathFromPathComponents(realParts)),
+		symlink: tspath.EnsureTrailingDirectorySeparator(tspath.GetPathFromPathComponents(symlinkParts)),
+	}
+	if !slices.Contains(c.directories, directory) {
+		c.directories = append(c.directories, directory)
+	}
+}
+
+// GetSymlinkedPaths returns the paths through symlinked directories that a file is also reachable by.
+func (c *SymlinkCache) GetSymlinkedPaths(fileName string) []string {
+	if c == nil {
+		return nil
+	}
+	var paths []string
+	canonicalFileName := c.canonical(fileName)
+	for _, directory := range c.directories {
+		if strings.HasPrefix(canonicalFileName, c.canonical(directory.real)) {
+			paths = append(paths, directory.symlink+fileName[len(directory.real):])
+		}
+	}
+	return paths
+}
+
+func (c *SymlinkCache) canonical(fileName string) string {
+	return tspath.GetCanonicalFileName(fileName, c.useCaseSensitiveFileNames)
+}
+
+func isNodeModulesOrScopedPackageDirectory(name string) bool {
+	return name == "node_modules" || strings.HasPrefix(name, "@")
+}
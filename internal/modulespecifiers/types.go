@@ -0,0 +1,78 @@
+package modulespecifiers
+
+import (
+	"github.com/microsoft/typescript-go/internal/compiler/packagejson"
+)
+
+type ImportModuleSpecifierPreference string
+
+const (
+	// ImportModuleSpecifierPreferenceShortest picks a non-relative specifier from paths or baseUrl
+	// when it has fewer path segments than the relative one.
+	ImportModuleSpecifierPreferenceShortest ImportModuleSpecifierPreference = "shortest"
+	// ImportModuleSpecifierPreferenceProjectRelative picks a relative specifier within a project,
+	// and a non-relative one for files of another project or package.
+	ImportModuleSpecifierPreferenceProjectRelative ImportModuleSpecifierPreference = "project-relative"
+	ImportModuleSpecifierPreferenceRelative        ImportModuleSpecifierPreference = "relative"
+	ImportModuleSpecifierPreferenceNonRelative     ImportModuleSpecifierPreference = "non-relative"
+)
+
+type ImportModuleSpecifierEndingPreference string
+
+const (
+	// ImportModuleSpecifierEndingPreferenceAuto infers the ending from the existing imports of a
+	// file and the module resolution of the program.
+	ImportModuleSpecifierEndingPreferenceAuto    ImportModuleSpecifierEndingPreference = "auto"
+	ImportModuleSpecifierEndingPreferenceMinimal ImportModuleSpecifierEndingPreference = "minimal"
+	ImportModuleSpecifierEndingPreferenceIndex   ImportModuleSpecifierEndingPreference = "index"
+	ImportModuleSpecifierEndingPreferenceJs      ImportModuleSpecifierEndingPreference = "js"
+)
+
+type UserPreferences struct {
+	ImportModuleSpecifierPreference ImportModuleSpecifierPreference
+	ImportModuleSpecifierEnding     ImportModuleSpecifierEndingPreference
+}
+
+type Host interface {
+	GetCurrentDirectory() string
+	UseCaseSensitiveFileNames() bool
+	FileExists(fileName string) bool
+	// GetPackageJsonInfo returns the package.json in a directory, or nil if there is none.
+	GetPackageJsonInfo(packageDirectory string) *packagejson.InfoCacheEntry
+	// GetPackageScopeForPath returns the nearest package.json at or above a directory.
+	GetPackageScopeForPath(directory string) *packagejson.InfoCacheEntry
+	GetCommonSourceDirectory() string
+	GetSymlinkCache() *SymlinkCache
+}
+
+type relativePreference int
+
+const (
+	relativePreferenceRelative relativePreference = iota
+	relativePreferenceNonRelative
+	relativePreferenceShortest
+	relativePreferenceExternalNonRelative
+)
+
+// moduleSpecifierEnding is how the file name at the end of a specifier is written.
+type moduleSpecifierEnding int
+
+const (
+	// moduleSpecifierEndingMinimal drops the extension, and the file name of index files.
+	moduleSpecifierEndingMinimal moduleSpecifierEnding = iota
+	// moduleSpecifierEndingIndex drops the extension only.
+	moduleSpecifierEndingIndex
+	// moduleSpecifierEndingJsExtension replaces the extension by that of the emitted file.
+	moduleSpecifierEndingJsExtension
+	// moduleSpecifierEndingTsExtension keeps the extension of the source file.
+	moduleSpecifierEndingTsExtension
+)
+
+// matchingMode is how a key of a package.json exports or imports map matches module names.
+type matchingMode int
+
+const (
+	matchingModeExact matchingMode = iota
+	matchingModeDirectory
+	matchingModePattern
+)
@@ -3,8 +3,38 @@ package printer
 import (
 	"github.com/microsoft/typescript-go/internal/ast"
 	"github.com/microsoft/typescript-go/internal/binder"
+	"github.com/microsoft/typescript-go/internal/evaluator"
 )
 
+type SymbolAccessibility int32
+
+const (
+	SymbolAccessibilityAccessible SymbolAccessibility = iota
+	SymbolAccessibilityNotAccessible
+	SymbolAccessibilityCannotBeNamed
+)
+
+type SymbolAccessibilityResult struct {
+	Accessibility        SymbolAccessibility
+	AliasesToMakeVisible []*ast.Node // Statements that must be emitted for the symbol to be visible
+	ErrorSymbolName      string      // Optional symbol name that results in error
+	ErrorModuleName      string      // Optional module name that results in error
+	ErrorNode            *ast.Node   // Optional node that results in error
+}
+
+// SymbolTracker receives notifications from the checker while it synthesizes type nodes for declaration emit.
+// Methods are called while the checker is in use and must not call back into the EmitResolver.
+type SymbolTracker interface {
+	// Reports the accessibility of a symbol referenced by a synthesized type node. Returns true if an error was reported.
+	TrackSymbolAccessibility(result SymbolAccessibilityResult) bool
+	ReportInaccessibleThisError()
+	ReportInaccessibleUniqueSymbolError()
+	ReportCyclicStructureError()
+	ReportPrivateInBaseOfClassExpression(propertyName string)
+	ReportLikelyUnsafeImportRequiredError(specifier string)
+	ReportTruncationError()
+}
+
 type EmitResolver interface {
 	binder.ReferenceResolver
 	IsReferencedAliasDeclaration(node *ast.Node) bool
@@ -12,4 +42,19 @@ type EmitResolver interface {
 	IsTopLevelValueImportEqualsWithEntityName(node *ast.Node) bool
 	MarkLinkedReferencesRecursively(file *ast.SourceFile)
 	GetExternalModuleFileFromDeclaration(node *ast.Node) *ast.SourceFile
+
+	// declaration emit checker functionality projections
+	IsDeclarationVisible(node *ast.Node) bool
+	IsSymbolAccessible(symbol *ast.Symbol, enclosingDeclaration *ast.Node, meaning ast.SymbolFlags, shouldComputeAliasToMakeVisible bool) SymbolAccessibilityResult
+	IsEntityNameVisible(entityName *ast.Node, enclosingDeclaration *ast.Node) SymbolAccessibilityResult
+	IsImplementationOfOverload(node *ast.SignatureDeclaration) bool
+	IsOptionalParameter(node *ast.ParameterDeclarationNode) bool
+	IsLiteralConstDeclaration(node *ast.Node) bool
+	IsLateBound(node *ast.Node) bool
+	RequiresAddingImplicitUndefined(node *ast.Node, enclosingDeclaration *ast.Node) bool
+	GetEnumMemberValue(node *ast.EnumMemberNode) evaluator.Result
+	CreateTypeOfDeclaration(emitContext *EmitContext, declaration *ast.Node, enclosingDeclaration *ast.Node, tracker SymbolTracker) *ast.Node
+	CreateReturnTypeOfSignatureDeclaration(emitContext *EmitContext, signatureDeclaration *ast.Node, enclosingDeclaration *ast.Node, tracker SymbolTracker) *ast.Node
+	CreateTypeOfExpression(emitContext *EmitContext, expression *ast.Node, enclosingDeclaration *ast.Node, tracker SymbolTracker) *ast.Node
+	CreateLiteralConstValue(emitContext *EmitContext, node *ast.Node, tracker SymbolTracker) *ast.Node
 }
@@ -260,6 +260,7 @@ func TestEmit(t *testing.T) {
 		{title: "ModuleDeclaration#6", input: `namespace a.b{}`, output: "namespace a.b { }"},
 		{title: "ModuleDeclaration#7", input: `global;`, output: "global;"},
 		{title: "ModuleDeclaration#8", input: `global{}`, output: "global { }"},
+		{title: "ModuleDeclaration#9", input: "namespace a {\n    const b = 1;\n}", output: "namespace a {\n    const b = 1;\n}"},
 		{title: "ImportEqualsDeclaration#1", input: `import a = b`, output: "import a = b;"},
 		{title: "ImportEqualsDeclaration#2", input: `import a = b.c`, output: "import a = b.c;"},
 		{title: "ImportEqualsDeclaration#3", input: `import a = require("b")`, output: "import a = require(\"b\");"},
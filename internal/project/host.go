@@ -1,10 +1,25 @@
 package project
 
-import "github.com/microsoft/typescript-go/internal/vfs"
+import (
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"github.com/microsoft/typescript-go/internal/vfs"
+)
+
+type WatcherHandle string
+
+// Client is the client of the service, which watches files on its behalf. Changes to watched
+// files are reported back through Service.OnWatchedFilesChanged.
+type Client interface {
+	WatchFiles(watchers []lsproto.FileSystemWatcher) (WatcherHandle, error)
+	UnwatchFiles(handle WatcherHandle) error
+}
 
 type ServiceHost interface {
 	FS() vfs.FS
 	DefaultLibraryPath() string
 	GetCurrentDirectory() string
 	NewLine() string
+
+	// Client returns the client that watches files, or nil if files are not watched.
+	Client() Client
 }
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -18,7 +19,9 @@ const (
 	LogLevelVerbose
 )
 
+// Logger is safe for concurrent use, since requests are handled concurrently.
 type Logger struct {
+	mu         sync.Mutex
 	outputs    []*bufio.Writer
 	fileHandle *os.File
 	level      LogLevel
@@ -32,25 +35,43 @@ func NewLogger(outputs []io.Writer, file string, level LogLevel) *Logger {
 		o = append(o, bufio.NewWriter(w))
 	}
 	logger := &Logger{outputs: o, level: level}
-	logger.SetFile(file)
+	if err := logger.SetFile(file); err != nil {
+		panic(err)
+	}
 	return logger
 }
 
-func (l *Logger) SetFile(file string) {
+// SetFile logs to a file, in addition to the outputs of the logger, instead of the file that it
+// logged to before, if any. An empty file name stops logging to a file. The logger keeps logging to
+// the file that it logged to before if the new one cannot be opened.
+func (l *Logger) SetFile(file string) error {
+	var f *os.File
+	if file != "" {
+		var err error
+		if f, err = os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o666); err != nil {
+			return err
+		}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	if l.fileHandle != nil {
 		oldWriter := l.outputs[len(l.outputs)-1]
 		l.outputs = l.outputs[:len(l.outputs)-1]
 		_ = oldWriter.Flush()
 		l.fileHandle.Close()
+		l.fileHandle = nil
 	}
-	if file != "" {
-		f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o666)
-		if err != nil {
-			panic(err)
-		}
+	if f != nil {
 		l.fileHandle = f
 		l.outputs = append(l.outputs, bufio.NewWriter(f))
 	}
+	return nil
+}
+
+func (l *Logger) SetLevel(level LogLevel) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
 }
 
 func (l *Logger) PerfTrace(s string) {
@@ -69,6 +90,8 @@ func (l *Logger) StartGroup() {
 	if l == nil {
 		return
 	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	l.inGroup = true
 }
 
@@ -76,21 +99,35 @@ func (l *Logger) EndGroup() {
 	if l == nil {
 		return
 	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	l.inGroup = false
 }
 
 func (l *Logger) LoggingEnabled() bool {
-	return l != nil && len(l.outputs) > 0
+	if l == nil {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.outputs) > 0
 }
 
 func (l *Logger) HasLevel(level LogLevel) bool {
-	return l != nil && l.LoggingEnabled() && l.level >= level
+	if l == nil {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.outputs) > 0 && l.level >= level
 }
 
 func (l *Logger) Close() {
 	if l == nil {
 		return
 	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	for _, output := range l.outputs {
 		_ = output.Flush()
 	}
@@ -103,6 +140,8 @@ func (l *Logger) msg(s string, messageType string) {
 	if l == nil {
 		return
 	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	for _, output := range l.outputs {
 		header := fmt.Sprintf("%s %d", messageType, l.seq)
 		output.WriteString(header)                                      //nolint: errcheck
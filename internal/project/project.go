@@ -2,12 +2,15 @@ package project
 
 import (
 	"fmt"
+	"maps"
 	"strings"
 	"sync"
 
 	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/checker"
 	"github.com/microsoft/typescript-go/internal/collections"
 	"github.com/microsoft/typescript-go/internal/compiler"
+	"github.com/microsoft/typescript-go/internal/compiler/module"
 	"github.com/microsoft/typescript-go/internal/core"
 	"github.com/microsoft/typescript-go/internal/ls"
 	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
@@ -39,8 +42,11 @@ type ProjectHost interface {
 	GetScriptInfoByPath(path tspath.Path) *ScriptInfo
 	GetOrCreateScriptInfoForFile(fileName string, path tspath.Path, scriptKind core.ScriptKind) *ScriptInfo
 	OnDiscoveredSymlink(info *ScriptInfo)
+	OnProjectGraphUpdated(project *Project)
 	Log(s string)
 	PositionEncoding() lsproto.PositionEncodingKind
+	FormatCodeSettings() *ls.FormatCodeSettings
+	UserPreferences() *ls.UserPreferences
 }
 
 type Project struct {
@@ -57,6 +63,9 @@ type Project struct {
 	hasAddedOrRemovedSymlinks bool
 	deferredClose             bool
 	reloadConfig              bool
+	// hasChangedLookupLocations is set when a location that a module resolution looked at changes,
+	// since module resolutions are only made again when the program is.
+	hasChangedLookupLocations bool
 
 	currentDirectory string
 	// Inferred projects only
@@ -64,6 +73,15 @@ type Project struct {
 
 	configFileName string
 	configFilePath tspath.Path
+	// Configured projects only
+	extendedConfigFileNames []string
+	wildcardDirectories     map[string]bool
+
+	// failedLookupLocations and affectingLocations map the paths that the module resolutions of the
+	// program looked at to their file names.
+	failedLookupLocations   map[tspath.Path]string
+	failedLookupDirectories map[tspath.Path]struct{}
+	affectingLocations      map[tspath.Path]string
 	// rootFileNames was a map from Path to { NormalizedPath, ScriptInfo? } in the original code.
 	// But the ProjectService owns script infos, so it's not clear why there was an extra pointer.
 	rootFileNames   *collections.OrderedMap[tspath.Path, string]
@@ -141,6 +159,9 @@ func (p *Project) GetRootFileNames() []string {
 func (p *Project) GetSourceFile(fileName string, path tspath.Path, languageVersion core.ScriptTarget) *ast.SourceFile {
 	scriptKind := p.getScriptKind(fileName)
 	if scriptInfo := p.getOrCreateScriptInfoAndAttachToProject(fileName, scriptKind); scriptInfo != nil {
+		if scriptInfo.pendingReloadFromDisk && !scriptInfo.isOpen {
+			scriptInfo.reloadFromDisk(p.FS())
+		}
 		var (
 			oldSourceFile      *ast.SourceFile
 			oldCompilerOptions *core.CompilerOptions
@@ -160,6 +181,12 @@ func (p *Project) GetProgram() *compiler.Program {
 	return p.program
 }
 
+// GetTypeChecker implements ls.Host. The language service of a project shares the checker of its
+// program, so requests that run concurrently use snapshots instead.
+func (p *Project) GetTypeChecker() *checker.Checker {
+	return p.GetProgram().GetTypeChecker()
+}
+
 // NewLine implements LanguageServiceHost.
 func (p *Project) NewLine() string {
 	return p.host.NewLine()
@@ -185,6 +212,11 @@ func (p *Project) GetPositionEncoding() lsproto.PositionEncodingKind {
 	return p.host.PositionEncoding()
 }
 
+// GetUserPreferences implements ls.Host.
+func (p *Project) GetUserPreferences() *ls.UserPreferences {
+	return p.host.UserPreferences()
+}
+
 func (p *Project) Name() string {
 	return p.name
 }
@@ -223,6 +255,12 @@ func (p *Project) markFileAsDirty(path tspath.Path) {
 	p.markAsDirty()
 }
 
+// setCompilerOptions changes the compiler options of an inferred project.
+func (p *Project) setCompilerOptions(compilerOptions *core.CompilerOptions) {
+	p.compilerOptions = compilerOptions
+	p.markAsDirty()
+}
+
 func (p *Project) markAsDirty() {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -233,10 +271,17 @@ func (p *Project) markAsDirty() {
 }
 
 func (p *Project) updateIfDirty() bool {
-	// !!! p.invalidateResolutionsOfFailedLookupLocations()
+	p.invalidateResolutionsOfFailedLookupLocations()
 	return p.dirty && p.updateGraph()
 }
 
+func (p *Project) invalidateResolutionsOfFailedLookupLocations() {
+	if p.hasChangedLookupLocations {
+		p.hasChangedLookupLocations = false
+		p.markAsDirty()
+	}
+}
+
 func (p *Project) onFileAddedOrRemoved(isSymlink bool) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -257,7 +302,8 @@ func (p *Project) updateGraph() bool {
 	hasAddedOrRemovedFiles := p.hasAddedOrRemovedFiles
 	p.initialLoadPending = false
 
-	if p.kind == KindConfigured && p.reloadConfig {
+	reloadedConfig := p.kind == KindConfigured && p.reloadConfig
+	if reloadedConfig {
 		if err := p.LoadConfig(); err != nil {
 			panic(fmt.Sprintf("failed to reload config: %v", err))
 		}
@@ -267,6 +313,7 @@ func (p *Project) updateGraph() bool {
 	p.hasAddedOrRemovedFiles = false
 	p.hasAddedOrRemovedSymlinks = false
 	p.updateProgram()
+	lookupLocationsChanged := p.updateLookupLocations()
 	p.dirty = false
 	p.log(fmt.Sprintf("Finishing updateGraph: Project: %s version: %d", p.name, p.version))
 	if hasAddedOrRemovedFiles {
@@ -275,14 +322,19 @@ func (p *Project) updateGraph() bool {
 		p.log("Different program with same set of files")
 	}
 
+	filesChanged := oldProgram == nil || len(oldProgram.GetSourceFiles()) != len(p.program.GetSourceFiles())
 	if p.program != oldProgram && oldProgram != nil {
 		for _, oldSourceFile := range oldProgram.GetSourceFiles() {
 			if p.program.GetSourceFileByPath(oldSourceFile.Path()) == nil {
 				p.host.DocumentRegistry().ReleaseDocument(oldSourceFile, oldProgram.GetCompilerOptions())
+				filesChanged = true
 			}
 		}
 	}
 
+	if filesChanged || reloadedConfig || lookupLocationsChanged {
+		p.host.OnProjectGraphUpdated(p)
+	}
 	return true
 }
 
@@ -299,6 +351,62 @@ func (p *Project) updateProgram() {
 	p.program.BindSourceFiles()
 }
 
+// updateLookupLocations records the locations that the module resolutions of the program looked at,
+// and returns whether they have changed.
+func (p *Project) updateLookupLocations() bool {
+	failedLookupLocations := make(map[tspath.Path]string)
+	failedLookupDirectories := make(map[tspath.Path]struct{})
+	affectingLocations := make(map[tspath.Path]string)
+	resolver := p.program.ModuleResolver()
+	p.program.ForEachResolvedModule(func(resolved *module.ResolvedModule) {
+		lookupLocations := resolver.GetLookupLocationsForResolvedModule(resolved)
+		if lookupLocations == nil {
+			return
+		}
+		for _, fileName := range lookupLocations.FailedLookupLocations {
+			path := p.toPath(fileName)
+			failedLookupLocations[path] = fileName
+			failedLookupDirectories[path.GetDirectoryPath()] = struct{}{}
+		}
+		for _, fileName := range lookupLocations.AffectingLocations {
+			affectingLocations[p.toPath(fileName)] = fileName
+		}
+	})
+	changed := !maps.Equal(failedLookupLocations, p.failedLookupLocations) || !maps.Equal(affectingLocations, p.affectingLocations)
+	p.failedLookupLocations = failedLookupLocations
+	p.failedLookupDirectories = failedLookupDirectories
+	p.affectingLocations = affectingLocations
+	return changed
+}
+
+// onLookupLocationChanged notes that a file or directory has changed on disk if a module resolution
+// of the program looked at it, so that the module resolutions are made again.
+func (p *Project) onLookupLocationChanged(path tspath.Path) {
+	_, isFailedLookupLocation := p.failedLookupLocations[path]
+	_, isFailedLookupDirectory := p.failedLookupDirectories[path]
+	_, isAffectingLocation := p.affectingLocations[path]
+	if isFailedLookupLocation || isFailedLookupDirectory || isAffectingLocation {
+		p.hasChangedLookupLocations = true
+	}
+}
+
+// isInWildcardDirectory returns whether a file could be matched by the include specs of the config.
+func (p *Project) isInWildcardDirectory(fileName string) bool {
+	for directory, recursive := range p.wildcardDirectories {
+		if recursive {
+			if tspath.ContainsPath(directory, fileName, tspath.ComparePathsOptions{
+				UseCaseSensitiveFileNames: p.FS().UseCaseSensitiveFileNames(),
+				CurrentDirectory:          p.currentDirectory,
+			}) {
+				return true
+			}
+		} else if p.toPath(tspath.GetDirectoryPath(fileName)) == p.toPath(directory) {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *Project) isOrphan() bool {
 	switch p.kind {
 	case KindInferred:
@@ -385,6 +493,8 @@ func (p *Project) LoadConfig() error {
 		)
 
 		p.compilerOptions = parsedCommandLine.CompilerOptions()
+		p.extendedConfigFileNames = parsedCommandLine.ExtendedSourceFiles()
+		p.wildcardDirectories = parsedCommandLine.WildcardDirectories(p.FS().UseCaseSensitiveFileNames())
 		p.setRootFiles(parsedCommandLine.FileNames())
 	} else {
 		p.compilerOptions = &core.CompilerOptions{}
@@ -463,6 +573,21 @@ func (p *Project) logf(format string, args ...interface{}) {
 	p.log(fmt.Sprintf(format, args...))
 }
 
+// Close releases the program of the project and detaches the project from its files.
 func (p *Project) Close() {
-	// !!!
+	if p.program != nil {
+		for _, sourceFile := range p.program.GetSourceFiles() {
+			p.host.DocumentRegistry().ReleaseDocument(sourceFile, p.program.GetCompilerOptions())
+			if info := p.host.GetScriptInfoByPath(sourceFile.Path()); info != nil {
+				info.detachFromProject(p)
+			}
+		}
+		p.program = nil
+	}
+	for path := range p.rootFileNames.Keys() {
+		if info := p.host.GetScriptInfoByPath(path); info != nil {
+			info.detachFromProject(p)
+		}
+	}
+	p.rootFileNames = &collections.OrderedMap[tspath.Path, string]{}
 }
@@ -77,6 +77,15 @@ func (s *ScriptInfo) SetTextFromDisk(newText string) {
 	}
 }
 
+// reloadFromDisk replaces the text of a closed file that has changed on disk.
+func (s *ScriptInfo) reloadFromDisk(fs vfs.FS) {
+	s.pendingReloadFromDisk = false
+	if text, ok := fs.ReadFile(s.fileName); ok {
+		s.SetTextFromDisk(text)
+		s.matchesDiskText = true
+	}
+}
+
 func (s *ScriptInfo) close(fileExists bool) {
 	s.isOpen = false
 	if fileExists && !s.pendingReloadFromDisk && !s.matchesDiskText {
@@ -30,6 +30,12 @@ type assignProjectResult struct {
 type ServiceOptions struct {
 	Logger           *Logger
 	PositionEncoding lsproto.PositionEncodingKind
+	// FormatCodeSettings, UserPreferences and InferredProjectCompilerOptions default to those of
+	// tsserver when nil. They are changed afterwards with SetFormatCodeSettings, SetUserPreferences
+	// and SetCompilerOptionsForInferredProjects.
+	FormatCodeSettings             *ls.FormatCodeSettings
+	UserPreferences                *ls.UserPreferences
+	InferredProjectCompilerOptions *core.CompilerOptions
 }
 
 var _ ProjectHost = (*Service)(nil)
@@ -56,11 +62,25 @@ type Service struct {
 	filenameToScriptInfoVersion map[tspath.Path]int
 	realpathToScriptInfosMu     sync.Mutex
 	realpathToScriptInfos       map[tspath.Path]map[*ScriptInfo]struct{}
+
+	// watchedGlobs are the globs that the client watches with watcherHandle.
+	watchedGlobs               []string
+	watcherHandle              WatcherHandle
+	watchedFilesUpdateDeferred bool
 }
 
 func NewService(host ServiceHost, options ServiceOptions) *Service {
 	options.Logger.Info(fmt.Sprintf("currentDirectory:: %s useCaseSensitiveFileNames:: %t", host.GetCurrentDirectory(), host.FS().UseCaseSensitiveFileNames()))
 	options.Logger.Info("libs Location:: " + host.DefaultLibraryPath())
+	if options.FormatCodeSettings == nil {
+		options.FormatCodeSettings = ls.GetDefaultFormatCodeSettings(host.NewLine())
+	}
+	if options.UserPreferences == nil {
+		options.UserPreferences = ls.GetDefaultUserPreferences()
+	}
+	if options.InferredProjectCompilerOptions == nil {
+		options.InferredProjectCompilerOptions = &core.CompilerOptions{}
+	}
 	return &Service{
 		host:    host,
 		options: options,
@@ -124,6 +144,38 @@ func (s *Service) PositionEncoding() lsproto.PositionEncodingKind {
 	return s.options.PositionEncoding
 }
 
+// FormatCodeSettings implements ProjectHost.
+func (s *Service) FormatCodeSettings() *ls.FormatCodeSettings {
+	return s.options.FormatCodeSettings
+}
+
+// UserPreferences implements ProjectHost.
+func (s *Service) UserPreferences() *ls.UserPreferences {
+	return s.options.UserPreferences
+}
+
+// SetFormatCodeSettings sets the settings that files are formatted with. Snapshots keep the
+// settings that were set when they were taken, so the settings must not be changed afterwards.
+func (s *Service) SetFormatCodeSettings(settings *ls.FormatCodeSettings) {
+	s.options.FormatCodeSettings = settings
+}
+
+// SetUserPreferences sets the preferences of the language services of the projects. Snapshots keep
+// the preferences that were set when they were taken, so the preferences must not be changed
+// afterwards.
+func (s *Service) SetUserPreferences(preferences *ls.UserPreferences) {
+	s.options.UserPreferences = preferences
+}
+
+// SetCompilerOptionsForInferredProjects sets the compiler options of the inferred projects, both
+// of the existing ones and of those created afterwards.
+func (s *Service) SetCompilerOptionsForInferredProjects(compilerOptions *core.CompilerOptions) {
+	s.options.InferredProjectCompilerOptions = compilerOptions
+	for _, project := range s.inferredProjects {
+		project.setCompilerOptions(compilerOptions)
+	}
+}
+
 func (s *Service) Projects() []*Project {
 	projects := make([]*Project, 0, len(s.configuredProjects)+len(s.inferredProjects))
 	for _, project := range s.configuredProjects {
@@ -147,12 +199,12 @@ func (s *Service) GetScriptInfoByPath(path tspath.Path) *ScriptInfo {
 }
 
 func (s *Service) OpenFile(fileName string, fileContent string, scriptKind core.ScriptKind, projectRootPath string) {
+	defer s.deferWatchedFilesUpdate()()
 	path := s.toPath(fileName)
 	existing := s.GetScriptInfoByPath(path)
 	info := s.getOrCreateOpenScriptInfo(fileName, path, fileContent, scriptKind, projectRootPath)
 	if existing == nil && info != nil && !info.isDynamic {
-		// !!!
-		// s.tryInvokeWildcardDirectories(info)
+		s.tryInvokeWildcardDirectories(info.fileName)
 	}
 	result := s.assignProjectToOpenedScriptInfo(info)
 	s.cleanupProjectsAndScriptInfos(result.retainProjects, []tspath.Path{info.path})
@@ -169,6 +221,7 @@ func (s *Service) ChangeFile(fileName string, changes []ls.TextChange) {
 }
 
 func (s *Service) CloseFile(fileName string) {
+	defer s.deferWatchedFilesUpdate()()
 	if info := s.GetScriptInfoByPath(s.toPath(fileName)); info != nil {
 		fileExists := !info.isDynamic && s.host.FS().FileExists(info.fileName)
 		info.close(fileExists)
@@ -240,7 +293,7 @@ func (s *Service) ensureProjectForOpenFiles() {
 		if info.isOrphan() {
 			s.assignOrphanScriptInfoToInferredProject(info, projectRootPath)
 		} else {
-			// !!! s.removeRootOfInferredProjectIfNowPartOfOtherProject(info)
+			s.removeRootOfInferredProjectIfNowPartOfOtherProject(info)
 		}
 	}
 	for _, project := range s.inferredProjects {
@@ -282,8 +335,6 @@ func (s *Service) deleteScriptInfo(info *ScriptInfo) {
 	defer s.scriptInfosMu.Unlock()
 	delete(s.scriptInfos, info.path)
 	s.filenameToScriptInfoVersion[info.path] = info.version
-	// !!!
-	// s.stopWatchingScriptInfo(info)
 	if realpath, ok := info.getRealpathIfDifferent(); ok {
 		s.realpathToScriptInfosMu.Lock()
 		defer s.realpathToScriptInfosMu.Unlock()
@@ -374,12 +425,7 @@ func (s *Service) getOrCreateScriptInfoWorker(fileName string, path tspath.Path,
 	if openedByClient {
 		// Opening closed script info
 		// either it was created just now, or was part of projects but was closed
-		// !!!
-		// s.stopWatchingScriptInfo(info)
 		info.open(fileContent)
-	} else {
-		// !!!
-		// s.watchClosedScriptInfo(info)
 	}
 	return info
 }
@@ -437,8 +483,6 @@ func (s *Service) createConfiguredProject(configFileName string, configFilePath
 	// !!! config file existence cache stuff omitted
 	project := NewConfiguredProject(configFileName, configFilePath, s)
 	s.configuredProjects[configFilePath] = project
-	// !!!
-	// s.createConfigFileWatcherForParsedConfig(configFileName, configFilePath, project)
 	return project
 }
 
@@ -502,6 +546,25 @@ func (s *Service) cleanupProjectsAndScriptInfos(toRetainConfiguredProjects map[*
 	// !!!
 }
 
+// removeRootOfInferredProjectIfNowPartOfOtherProject removes an open file from the inferred project
+// that it is a root of, once another project contains it.
+func (s *Service) removeRootOfInferredProjectIfNowPartOfOtherProject(info *ScriptInfo) {
+	if len(info.containingProjects) == 0 {
+		panic("scriptInfo must be attached to a project")
+	}
+	// The file is only added as a root of an inferred project when no other project contains it,
+	// so the inferred project is the first containing project.
+	firstProject := info.containingProjects[0]
+	if !firstProject.isOrphan() &&
+		firstProject.kind == KindInferred &&
+		firstProject.isRoot(info) &&
+		core.Some(info.containingProjects, func(project *Project) bool {
+			return project != firstProject && !project.isOrphan()
+		}) {
+		firstProject.removeFile(info, true /*fileExists*/, true /*detachFromProject*/)
+	}
+}
+
 func (s *Service) assignOrphanScriptInfoToInferredProject(info *ScriptInfo, projectRootDirectory string) {
 	if !info.isOrphan() {
 		panic("scriptInfo is not orphan")
@@ -618,9 +681,7 @@ func (s *Service) getDefaultProjectForScript(scriptInfo *ScriptInfo) *Project {
 }
 
 func (s *Service) createInferredProject(currentDirectory string, projectRootPath tspath.Path) *Project {
-	// !!!
-	compilerOptions := core.CompilerOptions{}
-	project := NewInferredProject(&compilerOptions, currentDirectory, projectRootPath, s)
+	project := NewInferredProject(s.options.InferredProjectCompilerOptions, currentDirectory, projectRootPath, s)
 	s.inferredProjects = append(s.inferredProjects, project)
 	return project
 }
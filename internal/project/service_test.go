@@ -1,19 +1,16 @@
 package project_test
 
 import (
-	"fmt"
-	"io"
 	"maps"
-	"strings"
-	"sync"
+	"slices"
 	"testing"
 
 	"github.com/microsoft/typescript-go/internal/bundled"
 	"github.com/microsoft/typescript-go/internal/core"
 	"github.com/microsoft/typescript-go/internal/ls"
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
 	"github.com/microsoft/typescript-go/internal/project"
-	"github.com/microsoft/typescript-go/internal/vfs"
-	"github.com/microsoft/typescript-go/internal/vfs/vfstest"
+	"github.com/microsoft/typescript-go/internal/testutil/projecttestutil"
 	"gotest.tools/v3/assert"
 )
 
@@ -41,7 +38,7 @@ func TestService(t *testing.T) {
 		t.Parallel()
 		t.Run("create configured project", func(t *testing.T) {
 			t.Parallel()
-			service, _ := setup(files)
+			service, _ := projecttestutil.Setup(files)
 			assert.Equal(t, len(service.Projects()), 0)
 			service.OpenFile("/home/projects/TS/p1/src/index.ts", files["/home/projects/TS/p1/src/index.ts"], core.ScriptKindTS, "")
 			assert.Equal(t, len(service.Projects()), 1)
@@ -54,7 +51,7 @@ func TestService(t *testing.T) {
 
 		t.Run("create inferred project", func(t *testing.T) {
 			t.Parallel()
-			service, _ := setup(files)
+			service, _ := projecttestutil.Setup(files)
 			service.OpenFile("/home/projects/TS/p1/config.ts", files["/home/projects/TS/p1/config.ts"], core.ScriptKindTS, "")
 			// Find tsconfig, load, notice config.ts is not included, create inferred project
 			assert.Equal(t, len(service.Projects()), 2)
@@ -64,7 +61,7 @@ func TestService(t *testing.T) {
 
 		t.Run("inferred project for in-memory files", func(t *testing.T) {
 			t.Parallel()
-			service, _ := setup(files)
+			service, _ := projecttestutil.Setup(files)
 			service.OpenFile("/home/projects/TS/p1/config.ts", files["/home/projects/TS/p1/config.ts"], core.ScriptKindTS, "")
 			service.OpenFile("^/untitled/ts-nul-authority/Untitled-1", "x", core.ScriptKindTS, "")
 			service.OpenFile("^/untitled/ts-nul-authority/Untitled-2", "y", core.ScriptKindTS, "")
@@ -81,7 +78,7 @@ func TestService(t *testing.T) {
 		t.Parallel()
 		t.Run("update script info eagerly and program lazily", func(t *testing.T) {
 			t.Parallel()
-			service, _ := setup(files)
+			service, _ := projecttestutil.Setup(files)
 			service.OpenFile("/home/projects/TS/p1/src/x.ts", files["/home/projects/TS/p1/src/x.ts"], core.ScriptKindTS, "")
 			info, proj := service.EnsureDefaultProjectForFile("/home/projects/TS/p1/src/x.ts")
 			programBefore := proj.GetProgram()
@@ -94,7 +91,7 @@ func TestService(t *testing.T) {
 
 		t.Run("unchanged source files are reused", func(t *testing.T) {
 			t.Parallel()
-			service, _ := setup(files)
+			service, _ := projecttestutil.Setup(files)
 			service.OpenFile("/home/projects/TS/p1/src/x.ts", files["/home/projects/TS/p1/src/x.ts"], core.ScriptKindTS, "")
 			_, proj := service.EnsureDefaultProjectForFile("/home/projects/TS/p1/src/x.ts")
 			programBefore := proj.GetProgram()
@@ -107,7 +104,7 @@ func TestService(t *testing.T) {
 			t.Parallel()
 			filesCopy := maps.Clone(files)
 			filesCopy["/home/projects/TS/p1/y.ts"] = `export const y = 2;`
-			service, _ := setup(filesCopy)
+			service, _ := projecttestutil.Setup(filesCopy)
 			service.OpenFile("/home/projects/TS/p1/src/index.ts", filesCopy["/home/projects/TS/p1/src/index.ts"], core.ScriptKindTS, "")
 			assert.Check(t, service.GetScriptInfo("/home/projects/TS/p1/y.ts") == nil)
 
@@ -122,14 +119,14 @@ func TestService(t *testing.T) {
 			t.Parallel()
 			t.Run("delete a file, close it, recreate it", func(t *testing.T) {
 				t.Parallel()
-				service, host := setup(files)
+				service, host := projecttestutil.Setup(files)
 				service.OpenFile("/home/projects/TS/p1/src/x.ts", files["/home/projects/TS/p1/src/x.ts"], core.ScriptKindTS, "")
 				service.OpenFile("/home/projects/TS/p1/src/index.ts", files["/home/projects/TS/p1/src/index.ts"], core.ScriptKindTS, "")
 				assert.Equal(t, service.SourceFileCount(), 2)
 
 				filesCopy := maps.Clone(files)
 				delete(filesCopy, "/home/projects/TS/p1/src/x.ts")
-				host.replaceFS(filesCopy)
+				host.ReplaceFS(filesCopy)
 
 				service.CloseFile("/home/projects/TS/p1/src/x.ts")
 				assert.Check(t, service.GetScriptInfo("/home/projects/TS/p1/src/x.ts") == nil)
@@ -137,7 +134,7 @@ func TestService(t *testing.T) {
 				assert.Equal(t, service.SourceFileCount(), 1)
 
 				filesCopy["/home/projects/TS/p1/src/x.ts"] = ``
-				host.replaceFS(filesCopy)
+				host.ReplaceFS(filesCopy)
 				service.OpenFile("/home/projects/TS/p1/src/x.ts", filesCopy["/home/projects/TS/p1/src/x.ts"], core.ScriptKindTS, "")
 				assert.Equal(t, service.GetScriptInfo("/home/projects/TS/p1/src/x.ts").Text(), "")
 				assert.Check(t, service.Projects()[0].GetProgram().GetSourceFile("/home/projects/TS/p1/src/x.ts") != nil)
@@ -151,19 +148,19 @@ func TestService(t *testing.T) {
 				t.Parallel()
 				filesCopy := maps.Clone(files)
 				delete(filesCopy, "/home/projects/TS/p1/tsconfig.json")
-				service, host := setup(filesCopy)
+				service, host := projecttestutil.Setup(filesCopy)
 				service.OpenFile("/home/projects/TS/p1/src/x.ts", files["/home/projects/TS/p1/src/x.ts"], core.ScriptKindTS, "")
 				service.OpenFile("/home/projects/TS/p1/src/index.ts", files["/home/projects/TS/p1/src/index.ts"], core.ScriptKindTS, "")
 
 				delete(filesCopy, "/home/projects/TS/p1/src/x.ts")
-				host.replaceFS(filesCopy)
+				host.ReplaceFS(filesCopy)
 
 				service.CloseFile("/home/projects/TS/p1/src/x.ts")
 				assert.Check(t, service.GetScriptInfo("/home/projects/TS/p1/src/x.ts") == nil)
 				assert.Check(t, service.Projects()[0].GetProgram().GetSourceFile("/home/projects/TS/p1/src/x.ts") == nil)
 
 				filesCopy["/home/projects/TS/p1/src/x.ts"] = ``
-				host.replaceFS(filesCopy)
+				host.ReplaceFS(filesCopy)
 				service.OpenFile("/home/projects/TS/p1/src/x.ts", filesCopy["/home/projects/TS/p1/src/x.ts"], core.ScriptKindTS, "")
 				assert.Equal(t, service.GetScriptInfo("/home/projects/TS/p1/src/x.ts").Text(), "")
 				assert.Check(t, service.Projects()[0].GetProgram().GetSourceFile("/home/projects/TS/p1/src/x.ts") != nil)
@@ -172,6 +169,125 @@ func TestService(t *testing.T) {
 		})
 	})
 
+	t.Run("OnWatchedFilesChanged", func(t *testing.T) {
+		t.Parallel()
+		t.Run("watch config and include directories", func(t *testing.T) {
+			t.Parallel()
+			service, host := projecttestutil.Setup(files)
+			host.WatchingClient = &projecttestutil.WatchingClient{}
+			service.OpenFile("/home/projects/TS/p1/src/index.ts", files["/home/projects/TS/p1/src/index.ts"], core.ScriptKindTS, "")
+			globs := host.WatchingClient.Globs()
+			assert.Check(t, slices.Contains(globs, "/home/projects/TS/p1/tsconfig.json"))
+			assert.Check(t, slices.Contains(globs, "/home/projects/TS/p1/src/**/*"))
+			assert.Check(t, slices.Contains(globs, "/home/projects/TS/tsconfig.json"))
+		})
+
+		t.Run("change config file", func(t *testing.T) {
+			t.Parallel()
+			filesCopy := maps.Clone(files)
+			service, host := projecttestutil.Setup(filesCopy)
+			service.OpenFile("/home/projects/TS/p1/src/index.ts", files["/home/projects/TS/p1/src/index.ts"], core.ScriptKindTS, "")
+			filesCopy["/home/projects/TS/p1/tsconfig.json"] = `{
+				"compilerOptions": {
+					"noLib": true
+				},
+				"include": ["src", "config.ts"]
+			}`
+			host.ReplaceFS(filesCopy)
+			service.OnWatchedFilesChanged([]lsproto.FileEvent{
+				{Uri: "file:///home/projects/TS/p1/tsconfig.json", Type: lsproto.FileChangeTypeChanged},
+			})
+			_, proj := service.EnsureDefaultProjectForFile("/home/projects/TS/p1/src/index.ts")
+			assert.Check(t, proj.GetProgram().GetSourceFile("/home/projects/TS/p1/config.ts") != nil)
+		})
+
+		t.Run("delete config file", func(t *testing.T) {
+			t.Parallel()
+			filesCopy := maps.Clone(files)
+			service, host := projecttestutil.Setup(filesCopy)
+			service.OpenFile("/home/projects/TS/p1/src/index.ts", files["/home/projects/TS/p1/src/index.ts"], core.ScriptKindTS, "")
+			delete(filesCopy, "/home/projects/TS/p1/tsconfig.json")
+			host.ReplaceFS(filesCopy)
+			service.OnWatchedFilesChanged([]lsproto.FileEvent{
+				{Uri: "file:///home/projects/TS/p1/tsconfig.json", Type: lsproto.FileChangeTypeDeleted},
+			})
+			_, proj := service.EnsureDefaultProjectForFile("/home/projects/TS/p1/src/index.ts")
+			assert.Equal(t, proj.Kind(), project.KindInferred)
+		})
+
+		t.Run("create and delete files in include directories", func(t *testing.T) {
+			t.Parallel()
+			filesCopy := maps.Clone(files)
+			service, host := projecttestutil.Setup(filesCopy)
+			service.OpenFile("/home/projects/TS/p1/src/index.ts", files["/home/projects/TS/p1/src/index.ts"], core.ScriptKindTS, "")
+			filesCopy["/home/projects/TS/p1/src/z.ts"] = `export const z = 1;`
+			delete(filesCopy, "/home/projects/TS/p1/src/x.ts")
+			host.ReplaceFS(filesCopy)
+			service.OnWatchedFilesChanged([]lsproto.FileEvent{
+				{Uri: "file:///home/projects/TS/p1/src/z.ts", Type: lsproto.FileChangeTypeCreated},
+				{Uri: "file:///home/projects/TS/p1/src/x.ts", Type: lsproto.FileChangeTypeDeleted},
+			})
+			_, proj := service.EnsureDefaultProjectForFile("/home/projects/TS/p1/src/index.ts")
+			program := proj.GetProgram()
+			assert.Check(t, program.GetSourceFile("/home/projects/TS/p1/src/z.ts") != nil)
+			assert.Check(t, program.GetSourceFile("/home/projects/TS/p1/src/x.ts") == nil)
+			assert.Check(t, service.GetScriptInfo("/home/projects/TS/p1/src/x.ts") == nil)
+		})
+
+		t.Run("change closed file", func(t *testing.T) {
+			t.Parallel()
+			filesCopy := maps.Clone(files)
+			service, host := projecttestutil.Setup(filesCopy)
+			service.OpenFile("/home/projects/TS/p1/src/index.ts", files["/home/projects/TS/p1/src/index.ts"], core.ScriptKindTS, "")
+			filesCopy["/home/projects/TS/p1/src/x.ts"] = `export const x = 2;`
+			host.ReplaceFS(filesCopy)
+			service.OnWatchedFilesChanged([]lsproto.FileEvent{
+				{Uri: "file:///home/projects/TS/p1/src/x.ts", Type: lsproto.FileChangeTypeChanged},
+			})
+			_, proj := service.EnsureDefaultProjectForFile("/home/projects/TS/p1/src/index.ts")
+			assert.Equal(t, proj.GetProgram().GetSourceFile("/home/projects/TS/p1/src/x.ts").Text(), "export const x = 2;")
+		})
+
+		t.Run("create failed lookup location", func(t *testing.T) {
+			t.Parallel()
+			filesCopy := maps.Clone(files)
+			filesCopy["/home/projects/TS/p1/src/index.ts"] = `import { y } from "../lib/y";`
+			service, host := projecttestutil.Setup(filesCopy)
+			host.WatchingClient = &projecttestutil.WatchingClient{}
+			service.OpenFile("/home/projects/TS/p1/src/index.ts", filesCopy["/home/projects/TS/p1/src/index.ts"], core.ScriptKindTS, "")
+			_, proj := service.EnsureDefaultProjectForFile("/home/projects/TS/p1/src/index.ts")
+			assert.Check(t, proj.GetProgram().GetSourceFile("/home/projects/TS/p1/lib/y.ts") == nil)
+			assert.Check(t, slices.Contains(host.WatchingClient.Globs(), "/home/projects/TS/p1/lib/*"))
+
+			filesCopy["/home/projects/TS/p1/lib/y.ts"] = `export const y = 1;`
+			host.ReplaceFS(filesCopy)
+			service.OnWatchedFilesChanged([]lsproto.FileEvent{
+				{Uri: "file:///home/projects/TS/p1/lib/y.ts", Type: lsproto.FileChangeTypeCreated},
+			})
+			assert.Check(t, proj.GetProgram().GetSourceFile("/home/projects/TS/p1/lib/y.ts") != nil)
+		})
+	})
+
+	t.Run("SetCompilerOptionsForInferredProjects", func(t *testing.T) {
+		t.Parallel()
+		t.Run("update existing and new inferred projects", func(t *testing.T) {
+			t.Parallel()
+			service, _ := projecttestutil.Setup(files)
+			service.OpenFile("/home/projects/TS/p1/config.ts", files["/home/projects/TS/p1/config.ts"], core.ScriptKindTS, "")
+			_, proj := service.EnsureDefaultProjectForFile("/home/projects/TS/p1/config.ts")
+			assert.Equal(t, proj.GetProgram().Options().Strict, core.TSUnknown)
+
+			compilerOptions := &core.CompilerOptions{Strict: core.TSTrue}
+			service.SetCompilerOptionsForInferredProjects(compilerOptions)
+			assert.Equal(t, proj.GetProgram().Options().Strict, core.TSTrue)
+
+			service.OpenFile("^/untitled/ts-nul-authority/Untitled-1", "x", core.ScriptKindTS, "/home/projects/other")
+			_, untitled := service.EnsureDefaultProjectForFile("^/untitled/ts-nul-authority/Untitled-1")
+			assert.Assert(t, untitled != proj)
+			assert.Equal(t, untitled.GetProgram().Options(), compilerOptions)
+		})
+	})
+
 	t.Run("Source file sharing", func(t *testing.T) {
 		t.Parallel()
 		t.Run("projects with similar options share source files", func(t *testing.T) {
@@ -186,7 +302,7 @@ func TestService(t *testing.T) {
 				},
 			}`
 			filesCopy["/home/projects/TS/p2/src/index.ts"] = `import { x } from "../../p1/src/x";`
-			service, _ := setup(filesCopy)
+			service, _ := projecttestutil.Setup(filesCopy)
 			service.OpenFile("/home/projects/TS/p1/src/index.ts", filesCopy["/home/projects/TS/p1/src/index.ts"], core.ScriptKindTS, "")
 			service.OpenFile("/home/projects/TS/p2/src/index.ts", filesCopy["/home/projects/TS/p2/src/index.ts"], core.ScriptKindTS, "")
 			assert.Equal(t, len(service.Projects()), 2)
@@ -209,7 +325,7 @@ func TestService(t *testing.T) {
 				}
 			}`
 			filesCopy["/home/projects/TS/p2/src/index.ts"] = `import { x } from "../../p1/src/x";`
-			service, _ := setup(filesCopy)
+			service, _ := projecttestutil.Setup(filesCopy)
 			service.OpenFile("/home/projects/TS/p1/src/index.ts", filesCopy["/home/projects/TS/p1/src/index.ts"], core.ScriptKindTS, "")
 			service.OpenFile("/home/projects/TS/p2/src/index.ts", filesCopy["/home/projects/TS/p2/src/index.ts"], core.ScriptKindTS, "")
 			assert.Equal(t, len(service.Projects()), 2)
@@ -222,62 +338,3 @@ func TestService(t *testing.T) {
 		})
 	})
 }
-
-func setup(files map[string]string) (*project.Service, *projectServiceHost) {
-	host := newProjectServiceHost(files)
-	service := project.NewService(host, project.ServiceOptions{
-		Logger: host.logger,
-	})
-	return service, host
-}
-
-type projectServiceHost struct {
-	fs                 vfs.FS
-	mu                 sync.Mutex
-	defaultLibraryPath string
-	output             strings.Builder
-	logger             *project.Logger
-}
-
-func newProjectServiceHost(files map[string]string) *projectServiceHost {
-	fs := bundled.WrapFS(vfstest.FromMap(files, false /*useCaseSensitiveFileNames*/))
-	host := &projectServiceHost{
-		fs:                 fs,
-		defaultLibraryPath: bundled.LibPath(),
-	}
-	host.logger = project.NewLogger([]io.Writer{&host.output}, "", project.LogLevelVerbose)
-	return host
-}
-
-// DefaultLibraryPath implements project.ProjectServiceHost.
-func (p *projectServiceHost) DefaultLibraryPath() string {
-	return p.defaultLibraryPath
-}
-
-// FS implements project.ProjectServiceHost.
-func (p *projectServiceHost) FS() vfs.FS {
-	return p.fs
-}
-
-// GetCurrentDirectory implements project.ProjectServiceHost.
-func (p *projectServiceHost) GetCurrentDirectory() string {
-	return "/"
-}
-
-// Log implements project.ProjectServiceHost.
-func (p *projectServiceHost) Log(msg ...any) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-	fmt.Fprintln(&p.output, msg...)
-}
-
-// NewLine implements project.ProjectServiceHost.
-func (p *projectServiceHost) NewLine() string {
-	return "\n"
-}
-
-func (p *projectServiceHost) replaceFS(files map[string]string) {
-	p.fs = bundled.WrapFS(vfstest.FromMap(files, false /*useCaseSensitiveFileNames*/))
-}
-
-var _ project.ServiceHost = (*projectServiceHost)(nil)
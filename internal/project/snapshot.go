@@ -0,0 +1,219 @@
+package project
+
+import (
+	"context"
+	"sync"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/checker"
+	"github.com/microsoft/typescript-go/internal/compiler"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/ls"
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"github.com/microsoft/typescript-go/internal/tspath"
+	"github.com/microsoft/typescript-go/internal/vfs"
+)
+
+var _ ls.Host = (*Snapshot)(nil)
+
+// Snapshot is a project as of one version. Its program and the text of its files do not change
+// when the project does, so a request can read it while later changes are applied to the project.
+//
+// A checker is not safe for concurrent use, so each snapshot takes a checker of its own from the
+// program, which it returns when it is released.
+type Snapshot struct {
+	project         *Project
+	version         int
+	rootFileNames   []string
+	compilerOptions *core.CompilerOptions
+	// formatCodeSettings and userPreferences are those of the service when the snapshot was taken.
+	formatCodeSettings *ls.FormatCodeSettings
+	userPreferences    *ls.UserPreferences
+	program            *compiler.Program
+	languageService    *ls.LanguageService
+
+	checkerMu      sync.Mutex
+	checker        *checker.Checker
+	releaseChecker func()
+
+	scriptInfosMu sync.Mutex
+	scriptInfos   map[tspath.Path]*SnapshotScriptInfo
+}
+
+// Snapshot updates the program of the project if needed and returns a snapshot of the project
+// whose language service stops early when ctx is canceled. Like every other change to a project,
+// it must not be called concurrently with one. The snapshot must be released when it is no longer
+// used.
+func (p *Project) Snapshot(ctx context.Context) *Snapshot {
+	program := p.GetProgram()
+	snapshot := &Snapshot{
+		project:            p,
+		version:            p.version,
+		rootFileNames:      p.GetRootFileNames(),
+		compilerOptions:    p.compilerOptions,
+		formatCodeSettings: p.host.FormatCodeSettings(),
+		userPreferences:    p.host.UserPreferences(),
+		program:            program,
+		scriptInfos:        make(map[tspath.Path]*SnapshotScriptInfo),
+	}
+	snapshot.languageService = p.languageService.ForSnapshot(ctx, snapshot)
+	return snapshot
+}
+
+// Release returns the checker of the snapshot to its program for other snapshots to use. A
+// request can release a snapshot before it finishes, as releasing it again does nothing.
+func (s *Snapshot) Release() {
+	s.checkerMu.Lock()
+	defer s.checkerMu.Unlock()
+	if s.checker != nil {
+		s.releaseChecker()
+		s.checker = nil
+		s.releaseChecker = nil
+	}
+}
+
+func (s *Snapshot) Name() string {
+	return s.project.name
+}
+
+func (s *Snapshot) LanguageService() *ls.LanguageService {
+	return s.languageService
+}
+
+// Converters converts between positions in the files of the snapshot and LSP positions.
+func (s *Snapshot) Converters() *ls.Converters {
+	return s.languageService.Converters()
+}
+
+// GetFile returns the text of a file of the program of the snapshot, or nil if the program does
+// not include the file.
+func (s *Snapshot) GetFile(fileName string) *SnapshotScriptInfo {
+	file := s.program.GetSourceFile(fileName)
+	if file == nil {
+		return nil
+	}
+	s.scriptInfosMu.Lock()
+	defer s.scriptInfosMu.Unlock()
+	info, ok := s.scriptInfos[file.Path()]
+	if !ok {
+		info = &SnapshotScriptInfo{fileName: file.FileName(), text: file.Text()}
+		s.scriptInfos[file.Path()] = info
+	}
+	return info
+}
+
+// FS implements ls.Host.
+func (s *Snapshot) FS() vfs.FS {
+	return s.project.host.FS()
+}
+
+// DefaultLibraryPath implements ls.Host.
+func (s *Snapshot) DefaultLibraryPath() string {
+	return s.project.host.DefaultLibraryPath()
+}
+
+// GetCurrentDirectory implements ls.Host.
+func (s *Snapshot) GetCurrentDirectory() string {
+	return s.project.currentDirectory
+}
+
+// NewLine implements ls.Host.
+func (s *Snapshot) NewLine() string {
+	return s.project.host.NewLine()
+}
+
+// Trace implements ls.Host.
+func (s *Snapshot) Trace(msg string) {
+	s.project.log(msg)
+}
+
+// GetProjectVersion implements ls.Host.
+func (s *Snapshot) GetProjectVersion() int {
+	return s.version
+}
+
+// GetRootFileNames implements ls.Host.
+func (s *Snapshot) GetRootFileNames() []string {
+	return s.rootFileNames
+}
+
+// GetCompilerOptions implements ls.Host.
+func (s *Snapshot) GetCompilerOptions() *core.CompilerOptions {
+	return s.compilerOptions
+}
+
+// GetSourceFile implements ls.Host. The program of a snapshot is never updated, so this only
+// returns the files that it already has.
+func (s *Snapshot) GetSourceFile(fileName string, path tspath.Path, languageVersion core.ScriptTarget) *ast.SourceFile {
+	return s.program.GetSourceFileByPath(path)
+}
+
+// GetProgram implements ls.Host.
+func (s *Snapshot) GetProgram() *compiler.Program {
+	return s.program
+}
+
+// GetTypeChecker implements ls.Host. The snapshot takes a checker from the program when it is
+// first asked for one, so requests that use the same program do not wait for each other.
+func (s *Snapshot) GetTypeChecker() *checker.Checker {
+	s.checkerMu.Lock()
+	defer s.checkerMu.Unlock()
+	if s.checker == nil {
+		s.checker, s.releaseChecker = s.program.AcquireChecker()
+	}
+	return s.checker
+}
+
+// GetDefaultLibraryPath implements ls.Host.
+func (s *Snapshot) GetDefaultLibraryPath() string {
+	return s.project.host.DefaultLibraryPath()
+}
+
+// GetPositionEncoding implements ls.Host.
+func (s *Snapshot) GetPositionEncoding() lsproto.PositionEncodingKind {
+	return s.project.host.PositionEncoding()
+}
+
+// GetUserPreferences implements ls.Host.
+func (s *Snapshot) GetUserPreferences() *ls.UserPreferences {
+	return s.userPreferences
+}
+
+// FormatCodeSettings returns the settings that the files of the snapshot are formatted with.
+func (s *Snapshot) FormatCodeSettings() *ls.FormatCodeSettings {
+	return s.formatCodeSettings
+}
+
+// GetScriptInfo implements ls.Host.
+func (s *Snapshot) GetScriptInfo(fileName string) ls.ScriptInfo {
+	if info := s.GetFile(fileName); info != nil {
+		return info
+	}
+	return nil
+}
+
+var _ ls.ScriptInfo = (*SnapshotScriptInfo)(nil)
+
+// SnapshotScriptInfo is the text of a file as of a snapshot.
+type SnapshotScriptInfo struct {
+	fileName string
+	text     string
+
+	lineMapOnce sync.Once
+	lineMap     *ls.LineMap
+}
+
+func (s *SnapshotScriptInfo) FileName() string {
+	return s.fileName
+}
+
+func (s *SnapshotScriptInfo) Text() string {
+	return s.text
+}
+
+func (s *SnapshotScriptInfo) LineMap() *ls.LineMap {
+	s.lineMapOnce.Do(func() {
+		s.lineMap = ls.ComputeLineStarts(s.text)
+	})
+	return s.lineMap
+}
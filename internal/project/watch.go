@@ -0,0 +1,234 @@
+package project
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/ls"
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"github.com/microsoft/typescript-go/internal/tspath"
+)
+
+// OnProjectGraphUpdated implements ProjectHost.
+func (s *Service) OnProjectGraphUpdated(project *Project) {
+	if !s.watchedFilesUpdateDeferred {
+		s.updateWatchedFiles()
+	}
+}
+
+// deferWatchedFilesUpdate defers updates of the watched files, while several projects are updated,
+// until the returned function is called.
+func (s *Service) deferWatchedFilesUpdate() func() {
+	s.watchedFilesUpdateDeferred = true
+	return func() {
+		s.watchedFilesUpdateDeferred = false
+		s.updateWatchedFiles()
+	}
+}
+
+// OnWatchedFilesChanged handles the changes that the client reports to watched files: config files
+// are reloaded, files are added to or removed from the projects whose include specs match them, and
+// projects whose module resolutions looked at a changed location are updated.
+func (s *Service) OnWatchedFilesChanged(changes []lsproto.FileEvent) {
+	defer s.deferWatchedFilesUpdate()()
+	for _, change := range changes {
+		fileName := ls.DocumentURIToFileName(change.Uri)
+		path := s.toPath(fileName)
+
+		if project, ok := s.configuredProjects[path]; ok {
+			s.onConfigFileChanged(project, change.Type)
+		} else if change.Type == lsproto.FileChangeTypeCreated && tspath.GetBaseFileName(fileName) == "tsconfig.json" {
+			s.onConfigFileCreated(path)
+		}
+		for _, project := range s.configuredProjects {
+			if slices.ContainsFunc(project.extendedConfigFileNames, func(extendedConfigFileName string) bool {
+				return s.toPath(extendedConfigFileName) == path
+			}) {
+				project.reloadConfig = true
+				project.markAsDirty()
+			}
+		}
+
+		if info := s.GetScriptInfoByPath(path); info != nil && !info.isOpen && !info.isDynamic {
+			switch change.Type {
+			case lsproto.FileChangeTypeChanged:
+				info.delayReloadNonMixedContentFile()
+			case lsproto.FileChangeTypeDeleted:
+				s.handleDeletedFile(info, false /*deferredDelete*/)
+			}
+		}
+		if change.Type != lsproto.FileChangeTypeChanged {
+			s.tryInvokeWildcardDirectories(fileName)
+		}
+
+		for _, project := range s.Projects() {
+			project.onLookupLocationChanged(path)
+		}
+	}
+
+	s.ensureProjectStructureUpToDate()
+}
+
+func (s *Service) onConfigFileChanged(project *Project, changeKind lsproto.FileChangeType) {
+	if changeKind == lsproto.FileChangeTypeDeleted || !s.host.FS().FileExists(project.configFileName) {
+		s.closeConfiguredProject(project)
+		return
+	}
+	project.reloadConfig = true
+	project.markAsDirty()
+}
+
+// onConfigFileCreated loads the configured project of a new config file for the open files that it
+// is now the nearest config file of.
+func (s *Service) onConfigFileCreated(configFilePath tspath.Path) {
+	for path := range s.openFiles {
+		info := s.GetScriptInfoByPath(path)
+		if configFileName := s.getConfigFileNameForFile(info, false /*findFromCacheOnly*/); configFileName != "" && s.toPath(configFileName) == configFilePath {
+			s.assignProjectToOpenedScriptInfo(info)
+		}
+	}
+}
+
+// closeConfiguredProject closes the project of a deleted config file, and finds other projects for
+// the open files that were in it.
+func (s *Service) closeConfiguredProject(project *Project) {
+	s.Log("Closing configured project: " + project.configFileName)
+	delete(s.configuredProjects, project.configFilePath)
+	project.deferredClose = true
+	project.Close()
+	for path := range s.openFiles {
+		if info := s.GetScriptInfoByPath(path); info != nil && info.isOrphan() {
+			s.assignProjectToOpenedScriptInfo(info)
+		}
+	}
+}
+
+// tryInvokeWildcardDirectories reloads the configs whose include specs could match a file that has
+// been created or deleted.
+func (s *Service) tryInvokeWildcardDirectories(fileName string) {
+	if !tspath.FileExtensionIsOneOf(fileName, watchedExtensions) {
+		return
+	}
+	for _, project := range s.configuredProjects {
+		if !project.reloadConfig && project.isInWildcardDirectory(fileName) {
+			project.reloadConfig = true
+			project.markAsDirty()
+		}
+	}
+}
+
+var watchedExtensions = slices.Concat(tspath.AllSupportedExtensionsWithJson...)
+
+// updateWatchedFiles asks the client to watch the files that the projects depend on, if they have
+// changed since it last did.
+func (s *Service) updateWatchedFiles() {
+	client := s.host.Client()
+	if client == nil {
+		return
+	}
+	globs := s.getWatchedGlobs()
+	if slices.Equal(globs, s.watchedGlobs) {
+		return
+	}
+	if s.watcherHandle != "" {
+		if err := client.UnwatchFiles(s.watcherHandle); err != nil {
+			s.Log("Failed to unwatch files: " + err.Error())
+		}
+		s.watcherHandle = ""
+	}
+	s.watchedGlobs = globs
+	if len(globs) == 0 {
+		return
+	}
+	watchers := make([]lsproto.FileSystemWatcher, len(globs))
+	for i, glob := range globs {
+		watchers[i] = lsproto.FileSystemWatcher{
+			GlobPattern: lsproto.PatternOrRelativePattern{
+				Pattern: &glob,
+			},
+		}
+	}
+	handle, err := client.WatchFiles(watchers)
+	if err != nil {
+		s.Log("Failed to watch files: " + err.Error())
+		return
+	}
+	s.watcherHandle = handle
+	s.logf("Watching %d globs", len(globs))
+}
+
+// getWatchedGlobs returns the sorted globs for the files that the projects depend on. Config files
+// and the locations that affect module resolution are watched by name. The include directories of
+// configs are watched, as are the directories of closed files, which are reloaded when they change,
+// and of failed lookup locations, which are mostly in directories that do not exist yet.
+func (s *Service) getWatchedGlobs() []string {
+	files := make(map[string]struct{})
+	directories := make(map[string]bool)
+	watchDirectory := func(directory string, recursive bool) {
+		directories[directory] = directories[directory] || recursive
+	}
+
+	for _, project := range s.configuredProjects {
+		files[project.configFileName] = struct{}{}
+		for _, fileName := range project.extendedConfigFileNames {
+			files[fileName] = struct{}{}
+		}
+		for directory, recursive := range project.wildcardDirectories {
+			watchDirectory(directory, recursive)
+		}
+	}
+	for _, project := range s.Projects() {
+		for _, fileName := range project.failedLookupLocations {
+			watchDirectory(tspath.GetDirectoryPath(fileName), false /*recursive*/)
+		}
+		for _, fileName := range project.affectingLocations {
+			files[fileName] = struct{}{}
+		}
+	}
+	for path, projectRootPath := range s.openFiles {
+		info := s.GetScriptInfoByPath(path)
+		if info == nil || info.isDynamic {
+			continue
+		}
+		// A config file could be created in any directory that getConfigFileNameForFile looks in.
+		tspath.ForEachAncestorDirectory(tspath.GetDirectoryPath(info.fileName), func(directory string) (any, bool) {
+			files[tspath.CombinePaths(directory, "tsconfig.json")] = struct{}{}
+			return nil, strings.HasSuffix(directory, "/node_modules") ||
+				projectRootPath != "" && !tspath.ContainsPath(projectRootPath, directory, s.comparePathsOptions)
+		})
+	}
+	s.scriptInfosMu.RLock()
+	for _, info := range s.scriptInfos {
+		if info.isOpen || info.isDynamic || info.deferredDelete || len(info.containingProjects) == 0 ||
+			strings.HasPrefix(info.fileName, s.host.DefaultLibraryPath()) {
+			continue
+		}
+		watchDirectory(tspath.GetDirectoryPath(info.fileName), false /*recursive*/)
+	}
+	s.scriptInfosMu.RUnlock()
+
+	isWatchedRecursively := func(directory string) bool {
+		_, ok := tspath.ForEachAncestorDirectory(directory, func(ancestor string) (any, bool) {
+			return nil, directories[ancestor]
+		})
+		return ok
+	}
+	var globs []string
+	for directory, recursive := range directories {
+		parent := tspath.GetDirectoryPath(directory)
+		if parent != directory && isWatchedRecursively(parent) || !recursive && isWatchedRecursively(directory) {
+			continue
+		}
+		globs = append(globs, tspath.CombinePaths(directory, core.IfElse(recursive, "**/*", "*")))
+	}
+	for fileName := range files {
+		directory := tspath.GetDirectoryPath(fileName)
+		if _, ok := directories[directory]; ok || isWatchedRecursively(directory) {
+			continue
+		}
+		globs = append(globs, fileName)
+	}
+	slices.Sort(globs)
+	return globs
+}
@@ -1,6 +1,7 @@
 package harnessutil
 
 import (
+	"context"
 	"fmt"
 	"io/fs"
 	"maps"
@@ -539,7 +540,7 @@ func compileFilesWithHost(
 	program := createProgram(host, options, rootFiles)
 	var diagnostics []*ast.Diagnostic
 	diagnostics = append(diagnostics, program.GetSyntacticDiagnostics(nil)...)
-	diagnostics = append(diagnostics, program.GetSemanticDiagnostics(nil)...)
+	diagnostics = append(diagnostics, program.GetSemanticDiagnostics(context.Background(), nil)...)
 	diagnostics = append(diagnostics, program.GetGlobalDiagnostics()...)
 	emitResult := program.Emit(compiler.EmitOptions{})
 
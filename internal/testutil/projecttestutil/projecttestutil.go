@@ -0,0 +1,112 @@
+package projecttestutil
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/microsoft/typescript-go/internal/bundled"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/lsp/lsproto"
+	"github.com/microsoft/typescript-go/internal/project"
+	"github.com/microsoft/typescript-go/internal/vfs"
+	"github.com/microsoft/typescript-go/internal/vfs/vfstest"
+)
+
+// Setup creates a project service over an in-memory file system that holds the given files and
+// the bundled lib files.
+func Setup(files map[string]string) (*project.Service, *ProjectServiceHost) {
+	host := newProjectServiceHost(files)
+	service := project.NewService(host, project.ServiceOptions{
+		Logger: host.logger,
+	})
+	return service, host
+}
+
+type ProjectServiceHost struct {
+	fs                 vfs.FS
+	mu                 sync.Mutex
+	defaultLibraryPath string
+	output             strings.Builder
+	logger             *project.Logger
+	// WatchingClient is the client that watches files, if any.
+	WatchingClient *WatchingClient
+}
+
+func newProjectServiceHost(files map[string]string) *ProjectServiceHost {
+	fs := bundled.WrapFS(vfstest.FromMap(files, false /*useCaseSensitiveFileNames*/))
+	host := &ProjectServiceHost{
+		fs:                 fs,
+		defaultLibraryPath: bundled.LibPath(),
+	}
+	host.logger = project.NewLogger([]io.Writer{&host.output}, "", project.LogLevelVerbose)
+	return host
+}
+
+// DefaultLibraryPath implements project.ServiceHost.
+func (p *ProjectServiceHost) DefaultLibraryPath() string {
+	return p.defaultLibraryPath
+}
+
+// FS implements project.ServiceHost.
+func (p *ProjectServiceHost) FS() vfs.FS {
+	return p.fs
+}
+
+// GetCurrentDirectory implements project.ServiceHost.
+func (p *ProjectServiceHost) GetCurrentDirectory() string {
+	return "/"
+}
+
+// Log implements project.ServiceHost.
+func (p *ProjectServiceHost) Log(msg ...any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintln(&p.output, msg...)
+}
+
+// NewLine implements project.ServiceHost.
+func (p *ProjectServiceHost) NewLine() string {
+	return "\n"
+}
+
+// Client implements project.ServiceHost.
+func (p *ProjectServiceHost) Client() project.Client {
+	if p.WatchingClient == nil {
+		return nil
+	}
+	return p.WatchingClient
+}
+
+// ReplaceFS replaces the files of the file system, as if they were changed on disk.
+func (p *ProjectServiceHost) ReplaceFS(files map[string]string) {
+	p.fs = bundled.WrapFS(vfstest.FromMap(files, false /*useCaseSensitiveFileNames*/))
+}
+
+var _ project.ServiceHost = (*ProjectServiceHost)(nil)
+
+var _ project.Client = (*WatchingClient)(nil)
+
+type WatchingClient struct {
+	watchers []lsproto.FileSystemWatcher
+}
+
+// WatchFiles implements project.Client.
+func (c *WatchingClient) WatchFiles(watchers []lsproto.FileSystemWatcher) (project.WatcherHandle, error) {
+	c.watchers = watchers
+	return "watcher", nil
+}
+
+// UnwatchFiles implements project.Client.
+func (c *WatchingClient) UnwatchFiles(handle project.WatcherHandle) error {
+	c.watchers = nil
+	return nil
+}
+
+// Globs returns the patterns of the files that are watched.
+func (c *WatchingClient) Globs() []string {
+	return core.Map(c.watchers, func(watcher lsproto.FileSystemWatcher) string {
+		return *watcher.GlobPattern.Pattern
+	})
+}
@@ -0,0 +1,358 @@
+package transformers
+
+import (
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/binder"
+	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/printer"
+)
+
+type symbolAccessibilityDiagnostic struct {
+	errorNode         *ast.Node
+	diagnosticMessage *diagnostics.Message
+	typeName          *ast.Node
+}
+
+type getSymbolAccessibilityDiagnostic func(result printer.SymbolAccessibilityResult) *symbolAccessibilityDiagnostic
+
+func createDiagnosticForNode(node *ast.Node, message *diagnostics.Message, args ...any) *ast.Diagnostic {
+	file := ast.GetSourceFileOfNode(node)
+	return ast.NewDiagnostic(file, binder.GetErrorRangeForNode(file, node), message, args...)
+}
+
+func canProduceDiagnostics(node *ast.Node) bool {
+	switch node.Kind {
+	case ast.KindVariableDeclaration,
+		ast.KindParameter,
+		ast.KindPropertyDeclaration,
+		ast.KindPropertySignature,
+		ast.KindBindingElement,
+		ast.KindSetAccessor,
+		ast.KindGetAccessor,
+		ast.KindConstructSignature,
+		ast.KindCallSignature,
+		ast.KindMethodDeclaration,
+		ast.KindMethodSignature,
+		ast.KindFunctionDeclaration,
+		ast.KindIndexSignature,
+		ast.KindConstructor,
+		ast.KindTypeParameter,
+		ast.KindExpressionWithTypeArguments,
+		ast.KindImportEqualsDeclaration,
+		ast.KindTypeAliasDeclaration:
+		return true
+	}
+	return false
+}
+
+// Selects one of three messages depending on whether the inaccessible name could not be named at all, was found in
+// a private module, or is simply a private name.
+func selectAccessibilityMessage(result printer.SymbolAccessibilityResult, cannotBeNamed *diagnostics.Message, privateModule *diagnostics.Message, privateName *diagnostics.Message) *diagnostics.Message {
+	switch {
+	case result.ErrorModuleName != "" && result.Accessibility == printer.SymbolAccessibilityCannotBeNamed && cannotBeNamed != nil:
+		return cannotBeNamed
+	case result.ErrorModuleName != "":
+		return privateModule
+	}
+	return privateName
+}
+
+func createGetSymbolAccessibilityDiagnosticForNode(node *ast.Node) getSymbolAccessibilityDiagnostic {
+	switch node.Kind {
+	case ast.KindVariableDeclaration, ast.KindBindingElement, ast.KindPropertyDeclaration, ast.KindPropertySignature, ast.KindConstructor:
+		return getVariableDeclarationTypeVisibilityError(node)
+	case ast.KindSetAccessor, ast.KindGetAccessor:
+		return getAccessorDeclarationTypeVisibilityError(node)
+	case ast.KindConstructSignature, ast.KindCallSignature, ast.KindMethodDeclaration, ast.KindMethodSignature, ast.KindFunctionDeclaration, ast.KindIndexSignature:
+		return getReturnTypeVisibilityError(node)
+	case ast.KindParameter:
+		if ast.IsParameterPropertyDeclaration(node, node.Parent) && ast.HasSyntacticModifier(node.Parent, ast.ModifierFlagsPrivate) {
+			return getVariableDeclarationTypeVisibilityError(node)
+		}
+		return getParameterDeclarationTypeVisibilityError(node)
+	case ast.KindTypeParameter:
+		return getTypeParameterConstraintVisibilityError(node)
+	case ast.KindExpressionWithTypeArguments:
+		return getHeritageClauseVisibilityError(node)
+	case ast.KindImportEqualsDeclaration:
+		return getImportEntityNameVisibilityError(node)
+	case ast.KindTypeAliasDeclaration:
+		return getTypeAliasDeclarationVisibilityError(node)
+	}
+	panic("Attempted to set a declaration diagnostic context for unhandled node kind: " + node.Kind.String())
+}
+
+func getVariableDeclarationTypeVisibilityError(node *ast.Node) getSymbolAccessibilityDiagnostic {
+	return func(result printer.SymbolAccessibilityResult) *symbolAccessibilityDiagnostic {
+		var message *diagnostics.Message
+		switch {
+		case node.Kind == ast.KindVariableDeclaration || node.Kind == ast.KindBindingElement:
+			message = selectAccessibilityMessage(result,
+				diagnostics.Exported_variable_0_has_or_is_using_name_1_from_external_module_2_but_cannot_be_named,
+				diagnostics.Exported_variable_0_has_or_is_using_name_1_from_private_module_2,
+				diagnostics.Exported_variable_0_has_or_is_using_private_name_1)
+		// This check is to ensure we don't report error on constructor parameter property as that error would be reported during parameter emit
+		// The only exception here is if the constructor was marked as private. we are not emitting the constructor parameters at all.
+		case node.Kind == ast.KindPropertyDeclaration || node.Kind == ast.KindPropertySignature ||
+			node.Kind == ast.KindParameter && ast.HasSyntacticModifier(node.Parent, ast.ModifierFlagsPrivate):
+			switch {
+			case ast.IsStatic(node):
+				message = selectAccessibilityMessage(result,
+					diagnostics.Public_static_property_0_of_exported_class_has_or_is_using_name_1_from_external_module_2_but_cannot_be_named,
+					diagnostics.Public_static_property_0_of_exported_class_has_or_is_using_name_1_from_private_module_2,
+					diagnostics.Public_static_property_0_of_exported_class_has_or_is_using_private_name_1)
+			case node.Parent.Kind == ast.KindClassDeclaration || node.Kind == ast.KindParameter:
+				message = selectAccessibilityMessage(result,
+					diagnostics.Public_property_0_of_exported_class_has_or_is_using_name_1_from_external_module_2_but_cannot_be_named,
+					diagnostics.Public_property_0_of_exported_class_has_or_is_using_name_1_from_private_module_2,
+					diagnostics.Public_property_0_of_exported_class_has_or_is_using_private_name_1)
+			default:
+				// Interfaces cannot have types that cannot be named
+				message = selectAccessibilityMessage(result,
+					nil,
+					diagnostics.Property_0_of_exported_interface_has_or_is_using_name_1_from_private_module_2,
+					diagnostics.Property_0_of_exported_interface_has_or_is_using_private_name_1)
+			}
+		}
+		if message == nil {
+			return nil
+		}
+		return &symbolAccessibilityDiagnostic{diagnosticMessage: message, errorNode: node, typeName: node.Name()}
+	}
+}
+
+func getAccessorDeclarationTypeVisibilityError(node *ast.Node) getSymbolAccessibilityDiagnostic {
+	return func(result printer.SymbolAccessibilityResult) *symbolAccessibilityDiagnostic {
+		var message *diagnostics.Message
+		if node.Kind == ast.KindSetAccessor {
+			// Getters can infer the return type from the returned expression, but setters cannot, so the
+			// "_from_external_module_1_but_cannot_be_named" case cannot occur.
+			if ast.IsStatic(node) {
+				message = selectAccessibilityMessage(result,
+					nil,
+					diagnostics.Parameter_type_of_public_static_setter_0_from_exported_class_has_or_is_using_name_1_from_private_module_2,
+					diagnostics.Parameter_type_of_public_static_setter_0_from_exported_class_has_or_is_using_private_name_1)
+			} else {
+				message = selectAccessibilityMessage(result,
+					nil,
+					diagnostics.Parameter_type_of_public_setter_0_from_exported_class_has_or_is_using_name_1_from_private_module_2,
+					diagnostics.Parameter_type_of_public_setter_0_from_exported_class_has_or_is_using_private_name_1)
+			}
+		} else {
+			if ast.IsStatic(node) {
+				message = selectAccessibilityMessage(result,
+					diagnostics.Return_type_of_public_static_getter_0_from_exported_class_has_or_is_using_name_1_from_external_module_2_but_cannot_be_named,
+					diagnostics.Return_type_of_public_static_getter_0_from_exported_class_has_or_is_using_name_1_from_private_module_2,
+					diagnostics.Return_type_of_public_static_getter_0_from_exported_class_has_or_is_using_private_name_1)
+			} else {
+				message = selectAccessibilityMessage(result,
+					diagnostics.Return_type_of_public_getter_0_from_exported_class_has_or_is_using_name_1_from_external_module_2_but_cannot_be_named,
+					diagnostics.Return_type_of_public_getter_0_from_exported_class_has_or_is_using_name_1_from_private_module_2,
+					diagnostics.Return_type_of_public_getter_0_from_exported_class_has_or_is_using_private_name_1)
+			}
+		}
+		return &symbolAccessibilityDiagnostic{diagnosticMessage: message, errorNode: node.Name(), typeName: node.Name()}
+	}
+}
+
+func getReturnTypeVisibilityError(node *ast.Node) getSymbolAccessibilityDiagnostic {
+	return func(result printer.SymbolAccessibilityResult) *symbolAccessibilityDiagnostic {
+		var message *diagnostics.Message
+		switch node.Kind {
+		case ast.KindConstructSignature:
+			// Interfaces cannot have return types that cannot be named
+			message = selectAccessibilityMessage(result,
+				nil,
+				diagnostics.Return_type_of_constructor_signature_from_exported_interface_has_or_is_using_name_0_from_private_module_1,
+				diagnostics.Return_type_of_constructor_signature_from_exported_interface_has_or_is_using_private_name_0)
+		case ast.KindCallSignature:
+			// Interfaces cannot have return types that cannot be named
+			message = selectAccessibilityMessage(result,
+				nil,
+				diagnostics.Return_type_of_call_signature_from_exported_interface_has_or_is_using_name_0_from_private_module_1,
+				diagnostics.Return_type_of_call_signature_from_exported_interface_has_or_is_using_private_name_0)
+		case ast.KindIndexSignature:
+			// Interfaces cannot have return types that cannot be named
+			message = selectAccessibilityMessage(result,
+				nil,
+				diagnostics.Return_type_of_index_signature_from_exported_interface_has_or_is_using_name_0_from_private_module_1,
+				diagnostics.Return_type_of_index_signature_from_exported_interface_has_or_is_using_private_name_0)
+		case ast.KindMethodDeclaration, ast.KindMethodSignature:
+			switch {
+			case ast.IsStatic(node):
+				message = selectAccessibilityMessage(result,
+					diagnostics.Return_type_of_public_static_method_from_exported_class_has_or_is_using_name_0_from_external_module_1_but_cannot_be_named,
+					diagnostics.Return_type_of_public_static_method_from_exported_class_has_or_is_using_name_0_from_private_module_1,
+					diagnostics.Return_type_of_public_static_method_from_exported_class_has_or_is_using_private_name_0)
+			case node.Parent.Kind == ast.KindClassDeclaration:
+				message = selectAccessibilityMessage(result,
+					diagnostics.Return_type_of_public_method_from_exported_class_has_or_is_using_name_0_from_external_module_1_but_cannot_be_named,
+					diagnostics.Return_type_of_public_method_from_exported_class_has_or_is_using_name_0_from_private_module_1,
+					diagnostics.Return_type_of_public_method_from_exported_class_has_or_is_using_private_name_0)
+			default:
+				// Interfaces cannot have return types that cannot be named
+				message = selectAccessibilityMessage(result,
+					nil,
+					diagnostics.Return_type_of_method_from_exported_interface_has_or_is_using_name_0_from_private_module_1,
+					diagnostics.Return_type_of_method_from_exported_interface_has_or_is_using_private_name_0)
+			}
+		case ast.KindFunctionDeclaration:
+			message = selectAccessibilityMessage(result,
+				diagnostics.Return_type_of_exported_function_has_or_is_using_name_0_from_external_module_1_but_cannot_be_named,
+				diagnostics.Return_type_of_exported_function_has_or_is_using_name_0_from_private_module_1,
+				diagnostics.Return_type_of_exported_function_has_or_is_using_private_name_0)
+		default:
+			panic("This is unknown kind for signature: " + node.Kind.String())
+		}
+		errorNode := node.Name()
+		if errorNode == nil {
+			errorNode = node
+		}
+		return &symbolAccessibilityDiagnostic{diagnosticMessage: message, errorNode: errorNode}
+	}
+}
+
+func getParameterDeclarationTypeVisibilityError(node *ast.Node) getSymbolAccessibilityDiagnostic {
+	return func(result printer.SymbolAccessibilityResult) *symbolAccessibilityDiagnostic {
+		message := getParameterDeclarationTypeVisibilityDiagnosticMessage(node, result)
+		if message == nil {
+			return nil
+		}
+		return &symbolAccessibilityDiagnostic{diagnosticMessage: message, errorNode: node, typeName: node.Name()}
+	}
+}
+
+func getParameterDeclarationTypeVisibilityDiagnosticMessage(node *ast.Node, result printer.SymbolAccessibilityResult) *diagnostics.Message {
+	switch node.Parent.Kind {
+	case ast.KindConstructor:
+		return selectAccessibilityMessage(result,
+			diagnostics.Parameter_0_of_constructor_from_exported_class_has_or_is_using_name_1_from_external_module_2_but_cannot_be_named,
+			diagnostics.Parameter_0_of_constructor_from_exported_class_has_or_is_using_name_1_from_private_module_2,
+			diagnostics.Parameter_0_of_constructor_from_exported_class_has_or_is_using_private_name_1)
+	case ast.KindConstructSignature, ast.KindConstructorType:
+		// Interfaces cannot have parameter types that cannot be named
+		return selectAccessibilityMessage(result,
+			nil,
+			diagnostics.Parameter_0_of_constructor_signature_from_exported_interface_has_or_is_using_name_1_from_private_module_2,
+			diagnostics.Parameter_0_of_constructor_signature_from_exported_interface_has_or_is_using_private_name_1)
+	case ast.KindCallSignature:
+		// Interfaces cannot have parameter types that cannot be named
+		return selectAccessibilityMessage(result,
+			nil,
+			diagnostics.Parameter_0_of_call_signature_from_exported_interface_has_or_is_using_name_1_from_private_module_2,
+			diagnostics.Parameter_0_of_call_signature_from_exported_interface_has_or_is_using_private_name_1)
+	case ast.KindIndexSignature:
+		// Interfaces cannot have parameter types that cannot be named
+		return selectAccessibilityMessage(result,
+			nil,
+			diagnostics.Parameter_0_of_index_signature_from_exported_interface_has_or_is_using_name_1_from_private_module_2,
+			diagnostics.Parameter_0_of_index_signature_from_exported_interface_has_or_is_using_private_name_1)
+	case ast.KindMethodDeclaration, ast.KindMethodSignature:
+		switch {
+		case ast.IsStatic(node.Parent):
+			return selectAccessibilityMessage(result,
+				diagnostics.Parameter_0_of_public_static_method_from_exported_class_has_or_is_using_name_1_from_external_module_2_but_cannot_be_named,
+				diagnostics.Parameter_0_of_public_static_method_from_exported_class_has_or_is_using_name_1_from_private_module_2,
+				diagnostics.Parameter_0_of_public_static_method_from_exported_class_has_or_is_using_private_name_1)
+		case node.Parent.Parent.Kind == ast.KindClassDeclaration:
+			return selectAccessibilityMessage(result,
+				diagnostics.Parameter_0_of_public_method_from_exported_class_has_or_is_using_name_1_from_external_module_2_but_cannot_be_named,
+				diagnostics.Parameter_0_of_public_method_from_exported_class_has_or_is_using_name_1_from_private_module_2,
+				diagnostics.Parameter_0_of_public_method_from_exported_class_has_or_is_using_private_name_1)
+		default:
+			// Interfaces cannot have parameter types that cannot be named
+			return selectAccessibilityMessage(result,
+				nil,
+				diagnostics.Parameter_0_of_method_from_exported_interface_has_or_is_using_name_1_from_private_module_2,
+				diagnostics.Parameter_0_of_method_from_exported_interface_has_or_is_using_private_name_1)
+		}
+	case ast.KindFunctionDeclaration, ast.KindFunctionType:
+		return selectAccessibilityMessage(result,
+			diagnostics.Parameter_0_of_exported_function_has_or_is_using_name_1_from_external_module_2_but_cannot_be_named,
+			diagnostics.Parameter_0_of_exported_function_has_or_is_using_name_1_from_private_module_2,
+			diagnostics.Parameter_0_of_exported_function_has_or_is_using_private_name_1)
+	case ast.KindSetAccessor, ast.KindGetAccessor:
+		return selectAccessibilityMessage(result,
+			diagnostics.Parameter_0_of_accessor_has_or_is_using_name_1_from_external_module_2_but_cannot_be_named,
+			diagnostics.Parameter_0_of_accessor_has_or_is_using_name_1_from_private_module_2,
+			diagnostics.Parameter_0_of_accessor_has_or_is_using_private_name_1)
+	}
+	panic("Unknown parent for parameter: " + node.Parent.Kind.String())
+}
+
+func getTypeParameterConstraintVisibilityError(node *ast.Node) getSymbolAccessibilityDiagnostic {
+	return func(result printer.SymbolAccessibilityResult) *symbolAccessibilityDiagnostic {
+		// Type parameter constraints are named by user so we should always be able to name it
+		var message *diagnostics.Message
+		switch node.Parent.Kind {
+		case ast.KindClassDeclaration:
+			message = diagnostics.Type_parameter_0_of_exported_class_has_or_is_using_private_name_1
+		case ast.KindInterfaceDeclaration:
+			message = diagnostics.Type_parameter_0_of_exported_interface_has_or_is_using_private_name_1
+		case ast.KindMappedType:
+			message = diagnostics.Type_parameter_0_of_exported_mapped_object_type_is_using_private_name_1
+		case ast.KindConstructorType, ast.KindConstructSignature:
+			message = diagnostics.Type_parameter_0_of_constructor_signature_from_exported_interface_has_or_is_using_private_name_1
+		case ast.KindCallSignature:
+			message = diagnostics.Type_parameter_0_of_call_signature_from_exported_interface_has_or_is_using_private_name_1
+		case ast.KindMethodDeclaration, ast.KindMethodSignature:
+			switch {
+			case ast.IsStatic(node.Parent):
+				message = diagnostics.Type_parameter_0_of_public_static_method_from_exported_class_has_or_is_using_private_name_1
+			case node.Parent.Parent.Kind == ast.KindClassDeclaration:
+				message = diagnostics.Type_parameter_0_of_public_method_from_exported_class_has_or_is_using_private_name_1
+			default:
+				message = diagnostics.Type_parameter_0_of_method_from_exported_interface_has_or_is_using_private_name_1
+			}
+		case ast.KindFunctionType, ast.KindFunctionDeclaration:
+			message = diagnostics.Type_parameter_0_of_exported_function_has_or_is_using_private_name_1
+		case ast.KindInferType:
+			message = diagnostics.Extends_clause_for_inferred_type_0_has_or_is_using_private_name_1
+		case ast.KindTypeAliasDeclaration:
+			message = diagnostics.Type_parameter_0_of_exported_type_alias_has_or_is_using_private_name_1
+		default:
+			panic("This is unknown parent for type parameter: " + node.Parent.Kind.String())
+		}
+		return &symbolAccessibilityDiagnostic{diagnosticMessage: message, errorNode: node, typeName: node.Name()}
+	}
+}
+
+func getHeritageClauseVisibilityError(node *ast.Node) getSymbolAccessibilityDiagnostic {
+	return func(result printer.SymbolAccessibilityResult) *symbolAccessibilityDiagnostic {
+		// Heritage clause is written by user so it can always be named
+		var message *diagnostics.Message
+		declaration := node.Parent.Parent
+		switch {
+		case ast.IsClassDeclaration(declaration):
+			// Class or Interface implemented/extended is inaccessible
+			switch {
+			case node.Parent.AsHeritageClause().Token == ast.KindImplementsKeyword:
+				message = diagnostics.Implements_clause_of_exported_class_0_has_or_is_using_private_name_1
+			case declaration.Name() != nil:
+				message = diagnostics.X_extends_clause_of_exported_class_0_has_or_is_using_private_name_1
+			default:
+				message = diagnostics.X_extends_clause_of_exported_class_has_or_is_using_private_name_0
+			}
+		default:
+			// interface is inaccessible
+			message = diagnostics.X_extends_clause_of_exported_interface_0_has_or_is_using_private_name_1
+		}
+		return &symbolAccessibilityDiagnostic{diagnosticMessage: message, errorNode: node, typeName: ast.GetNameOfDeclaration(declaration)}
+	}
+}
+
+func getImportEntityNameVisibilityError(node *ast.Node) getSymbolAccessibilityDiagnostic {
+	return func(result printer.SymbolAccessibilityResult) *symbolAccessibilityDiagnostic {
+		return &symbolAccessibilityDiagnostic{diagnosticMessage: diagnostics.Import_declaration_0_is_using_private_name_1, errorNode: node, typeName: node.Name()}
+	}
+}
+
+func getTypeAliasDeclarationVisibilityError(node *ast.Node) getSymbolAccessibilityDiagnostic {
+	return func(result printer.SymbolAccessibilityResult) *symbolAccessibilityDiagnostic {
+		message := core.IfElse(result.ErrorModuleName != "",
+			diagnostics.Exported_type_alias_0_has_or_is_using_private_name_1_from_module_2,
+			diagnostics.Exported_type_alias_0_has_or_is_using_private_name_1)
+		return &symbolAccessibilityDiagnostic{diagnosticMessage: message, errorNode: node.Type(), typeName: node.Name()}
+	}
+}
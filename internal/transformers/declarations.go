@@ -0,0 +1,1091 @@
+package transformers
+
+import (
+	"slices"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/printer"
+	"github.com/microsoft/typescript-go/internal/scanner"
+)
+
+// DeclarationTransformer transforms a source file into the contents of its declaration (.d.ts) file. Types that are
+// not written explicitly in the source are synthesized by the EmitResolver, and references to names that cannot be
+// written in the declaration file are reported as diagnostics.
+type DeclarationTransformer struct {
+	Transformer
+	compilerOptions *core.CompilerOptions
+	resolver        printer.EmitResolver
+	diagnostics     []*ast.Diagnostic
+
+	currentSourceFile                *ast.SourceFile
+	enclosingDeclaration             *ast.Node
+	needsDeclare                     bool
+	needsScopeFixMarker              bool
+	resultHasScopeMarker             bool
+	resultHasExternalModuleIndicator bool
+	suppressNewDiagnosticContexts    bool
+	lateMarkedStatements             []*ast.Node
+	lateStatementReplacementMap      map[*ast.Node]*ast.Node
+	getSymbolAccessibilityDiagnostic getSymbolAccessibilityDiagnostic
+	errorNameNode                    *ast.Node
+	errorFallbackNode                *ast.Node
+}
+
+var _ printer.SymbolTracker = (*DeclarationTransformer)(nil)
+
+func NewDeclarationTransformer(emitContext *printer.EmitContext, compilerOptions *core.CompilerOptions, resolver printer.EmitResolver) *DeclarationTransformer {
+	tx := &DeclarationTransformer{compilerOptions: compilerOptions, resolver: resolver}
+	tx.newTransformer(tx.visit, emitContext)
+	return tx
+}
+
+// Diagnostics returns the declaration emit diagnostics reported while transforming source files.
+func (tx *DeclarationTransformer) Diagnostics() []*ast.Diagnostic {
+	return tx.diagnostics
+}
+
+func (tx *DeclarationTransformer) addDiagnostic(diagnostic *ast.Diagnostic) {
+	tx.diagnostics = append(tx.diagnostics, diagnostic)
+}
+
+func (tx *DeclarationTransformer) visit(node *ast.Node) *ast.Node {
+	switch node.Kind {
+	case ast.KindSourceFile:
+		return tx.visitSourceFile(node.AsSourceFile())
+	default:
+		return tx.visitDeclarationSubtree(node)
+	}
+}
+
+func (tx *DeclarationTransformer) visitSourceFile(file *ast.SourceFile) *ast.Node {
+	if file.IsDeclarationFile {
+		return file.AsNode()
+	}
+
+	tx.currentSourceFile = file
+	tx.enclosingDeclaration = file.AsNode()
+	tx.lateMarkedStatements = nil
+	tx.lateStatementReplacementMap = make(map[*ast.Node]*ast.Node)
+	tx.needsDeclare = true
+	tx.needsScopeFixMarker = false
+	tx.resultHasScopeMarker = false
+	tx.resultHasExternalModuleIndicator = false
+
+	// !!! triple-slash reference directives for referenced files and type reference directives
+	statements := tx.visitDeclarationStatements(file.Statements.Nodes)
+	statements = tx.transformAndReplaceLatePaintedStatements(statements)
+	if ast.IsExternalModule(file) && (!tx.resultHasExternalModuleIndicator || tx.needsScopeFixMarker && !tx.resultHasScopeMarker) {
+		statements = append(statements, createEmptyImports(tx.factory))
+	}
+	statementList := tx.factory.NewNodeList(statements)
+	statementList.Loc = file.Statements.Loc
+	return tx.factory.UpdateSourceFile(file, statementList)
+}
+
+func (tx *DeclarationTransformer) visitDeclarationStatements(statements []*ast.Node) []*ast.Node {
+	var result []*ast.Node
+	for _, statement := range statements {
+		visited := tx.visitDeclarationStatement(statement)
+		switch {
+		case visited == nil:
+		case visited.Kind == ast.KindSyntaxList:
+			result = append(result, visited.AsSyntaxList().Children...)
+		default:
+			result = append(result, visited)
+		}
+	}
+	return result
+}
+
+func isPreservedDeclarationStatement(node *ast.Node) bool {
+	switch node.Kind {
+	case ast.KindFunctionDeclaration,
+		ast.KindModuleDeclaration,
+		ast.KindImportEqualsDeclaration,
+		ast.KindInterfaceDeclaration,
+		ast.KindClassDeclaration,
+		ast.KindTypeAliasDeclaration,
+		ast.KindEnumDeclaration,
+		ast.KindVariableStatement,
+		ast.KindImportDeclaration,
+		ast.KindExportDeclaration,
+		ast.KindExportAssignment:
+		return true
+	}
+	return false
+}
+
+func (tx *DeclarationTransformer) visitDeclarationStatement(input *ast.Node) *ast.Node {
+	if !isPreservedDeclarationStatement(input) {
+		// return nil for unmatched kinds to omit them from the tree
+		return nil
+	}
+
+	switch input.Kind {
+	case ast.KindExportDeclaration:
+		if ast.IsSourceFile(input.Parent) {
+			tx.resultHasExternalModuleIndicator = true
+		}
+		tx.resultHasScopeMarker = true
+		return input
+	case ast.KindExportAssignment:
+		if ast.IsSourceFile(input.Parent) {
+			tx.resultHasExternalModuleIndicator = true
+		}
+		tx.resultHasScopeMarker = true
+		return tx.transformExportAssignment(input.AsExportAssignment())
+	}
+
+	// Don't actually transform yet; just leave as original node - will be elided/swapped by late pass
+	tx.lateStatementReplacementMap[input] = tx.transformTopLevelDeclaration(input)
+	return input
+}
+
+func (tx *DeclarationTransformer) transformExportAssignment(input *ast.ExportAssignment) *ast.Node {
+	if input.Expression.Kind == ast.KindIdentifier {
+		return input.AsNode()
+	}
+
+	// The expression cannot be referenced by name, so it is written as the type of a new variable that is exported instead
+	newId := tx.emitContext.NewUniqueName("_default", printer.AutoGenerateOptions{Flags: printer.GeneratedIdentifierFlagsOptimistic})
+	oldDiag := tx.getSymbolAccessibilityDiagnostic
+	tx.getSymbolAccessibilityDiagnostic = func(result printer.SymbolAccessibilityResult) *symbolAccessibilityDiagnostic {
+		return &symbolAccessibilityDiagnostic{diagnosticMessage: diagnostics.Default_export_of_the_module_has_or_is_using_private_name_0, errorNode: input.AsNode()}
+	}
+	tx.errorFallbackNode = input.AsNode()
+	typeNode := tx.resolver.CreateTypeOfExpression(tx.emitContext, input.Expression, input.AsNode(), tx)
+	tx.errorFallbackNode = nil
+	tx.getSymbolAccessibilityDiagnostic = oldDiag
+
+	varDecl := tx.factory.NewVariableDeclaration(newId, nil /*exclamationToken*/, typeNode, nil /*initializer*/)
+	var modifiers *ast.ModifierList
+	if tx.needsDeclare {
+		modifiers = tx.factory.NewModifierList([]*ast.Node{tx.factory.NewModifier(ast.KindDeclareKeyword)})
+	}
+	statement := tx.factory.NewVariableStatement(modifiers, tx.factory.NewVariableDeclarationList(ast.NodeFlagsConst, tx.factory.NewNodeList([]*ast.Node{varDecl})))
+	return tx.factory.NewSyntaxList([]*ast.Node{
+		statement,
+		tx.factory.UpdateExportAssignment(input, input.Modifiers(), newId),
+	})
+}
+
+func (tx *DeclarationTransformer) transformAndReplaceLatePaintedStatements(statements []*ast.Node) []*ast.Node {
+	// This is a `for` loop rather than a `range` loop because transforming a statement may mark further statements
+	for len(tx.lateMarkedStatements) != 0 {
+		statement := tx.lateMarkedStatements[0]
+		tx.lateMarkedStatements = tx.lateMarkedStatements[1:]
+
+		priorNeedsDeclare := tx.needsDeclare
+		tx.needsDeclare = statement.Parent != nil && ast.IsSourceFile(statement.Parent)
+		result := tx.transformTopLevelDeclaration(statement)
+		tx.needsDeclare = priorNeedsDeclare
+		tx.lateStatementReplacementMap[statement] = result
+	}
+
+	// And lastly, we need to get the final form of all those indeterminate declarations from before and add them to
+	// the output list (and remove them from the set to examine for outer declarations)
+	var result []*ast.Node
+	for _, statement := range statements {
+		replacement, ok := tx.lateStatementReplacementMap[statement]
+		if !ok {
+			result = append(result, statement)
+			continue
+		}
+		delete(tx.lateStatementReplacementMap, statement)
+		if replacement == nil {
+			continue
+		}
+		replacements := []*ast.Node{replacement}
+		if replacement.Kind == ast.KindSyntaxList {
+			replacements = replacement.AsSyntaxList().Children
+		}
+		if core.Some(replacements, needsScopeMarker) {
+			// Top-level declarations in .d.ts files are always considered exported even without a modifier unless there's an export assignment or specifier
+			tx.needsScopeFixMarker = true
+		}
+		if ast.IsSourceFile(statement.Parent) && core.Some(replacements, ast.IsExternalModuleIndicator) {
+			tx.resultHasExternalModuleIndicator = true
+		}
+		result = append(result, replacements...)
+	}
+	return result
+}
+
+func (tx *DeclarationTransformer) transformTopLevelDeclaration(input *ast.Node) *ast.Node {
+	tx.lateMarkedStatements = slices.DeleteFunc(tx.lateMarkedStatements, func(statement *ast.Node) bool { return statement == input })
+
+	switch input.Kind {
+	case ast.KindImportEqualsDeclaration:
+		return tx.transformImportEqualsDeclaration(input)
+	case ast.KindImportDeclaration:
+		return tx.transformImportDeclaration(input.AsImportDeclaration())
+	}
+	if ast.IsDeclaration(input) && tx.isDeclarationAndNotVisible(input) {
+		return nil
+	}
+	// Elide implementation signatures from overload sets
+	if ast.IsFunctionLike(input) && tx.resolver.IsImplementationOfOverload(input) {
+		return nil
+	}
+
+	previousEnclosingDeclaration := tx.enclosingDeclaration
+	if isEnclosingDeclaration(input) {
+		tx.enclosingDeclaration = input
+	}
+	oldDiag := tx.getSymbolAccessibilityDiagnostic
+	if canProduceDiagnostics(input) {
+		tx.getSymbolAccessibilityDiagnostic = createGetSymbolAccessibilityDiagnosticForNode(input)
+	}
+	previousNeedsDeclare := tx.needsDeclare
+	defer func() {
+		tx.enclosingDeclaration = previousEnclosingDeclaration
+		tx.getSymbolAccessibilityDiagnostic = oldDiag
+		tx.needsDeclare = previousNeedsDeclare
+	}()
+
+	switch input.Kind {
+	case ast.KindTypeAliasDeclaration:
+		tx.needsDeclare = false
+		n := input.AsTypeAliasDeclaration()
+		return tx.factory.UpdateTypeAliasDeclaration(n, tx.ensureModifiers(input), n.Name(), tx.visitor.VisitNodes(n.TypeParameters), tx.visitTypeNode(n.Type))
+	case ast.KindInterfaceDeclaration:
+		n := input.AsInterfaceDeclaration()
+		return tx.factory.UpdateInterfaceDeclaration(n, tx.ensureModifiers(input), n.Name(), tx.visitor.VisitNodes(n.TypeParameters), tx.transformHeritageClauses(n.HeritageClauses), tx.visitor.VisitNodes(n.Members))
+	case ast.KindFunctionDeclaration:
+		// !!! expando assignments to functions are not yet written as a merged namespace
+		n := input.AsFunctionDeclaration()
+		return tx.factory.UpdateFunctionDeclaration(n, tx.ensureModifiers(input), nil /*asteriskToken*/, n.Name(), tx.ensureTypeParams(input, n.TypeParameters), tx.updateParamsList(input, n.Parameters, ast.ModifierFlagsAll^ast.ModifierFlagsPublic), tx.ensureType(input, n.Type, false /*ignorePrivate*/), nil /*body*/)
+	case ast.KindModuleDeclaration:
+		return tx.transformModuleDeclaration(input.AsModuleDeclaration(), previousNeedsDeclare)
+	case ast.KindClassDeclaration:
+		return tx.transformClassDeclaration(input.AsClassDeclaration())
+	case ast.KindVariableStatement:
+		return tx.transformVariableStatement(input.AsVariableStatement())
+	case ast.KindEnumDeclaration:
+		n := input.AsEnumDeclaration()
+		members := core.Map(n.Members.Nodes, func(member *ast.Node) *ast.Node {
+			// Rewrite enum values to their constants, if available
+			var initializer *ast.Node
+			if value := tx.resolver.GetEnumMemberValue(member); value.Value != nil {
+				initializer = constantExpression(value.Value, tx.factory)
+			}
+			return tx.factory.UpdateEnumMember(member.AsEnumMember(), member.Name(), initializer)
+		})
+		memberList := tx.factory.NewNodeList(members)
+		memberList.Loc = n.Members.Loc
+		return tx.factory.UpdateEnumDeclaration(n, tx.ensureModifiers(input), n.Name(), memberList)
+	}
+	panic("Unhandled top-level node in declaration emit: " + input.Kind.String())
+}
+
+func (tx *DeclarationTransformer) transformModuleDeclaration(input *ast.ModuleDeclaration, previousNeedsDeclare bool) *ast.Node {
+	tx.needsDeclare = false
+	inner := input.Body
+	if inner == nil || inner.Kind == ast.KindModuleBlock {
+		oldNeedsScopeFix := tx.needsScopeFixMarker
+		oldHasScopeFix := tx.resultHasScopeMarker
+		tx.resultHasScopeMarker = false
+		tx.needsScopeFixMarker = false
+		var body *ast.Node
+		if inner != nil {
+			statements := tx.visitDeclarationStatements(inner.AsModuleBlock().Statements.Nodes)
+			lateStatements := tx.transformAndReplaceLatePaintedStatements(statements)
+			if input.Flags&ast.NodeFlagsAmbient != 0 {
+				// Declarations in ambient contexts are always exported, so no scope marker is needed
+				tx.needsScopeFixMarker = false
+			}
+			// With the final list of statements, there are 3 possibilities:
+			// 1. There's an export assignment or export declaration in the namespace - do nothing
+			// 2. Everything is exported and there are no export assignments or export declarations - strip all export modifiers
+			// 3. Some things are exported, some are not, and there's no marker - add an empty marker
+			if !ast.IsGlobalScopeAugmentation(input.AsNode()) && !core.Some(lateStatements, isScopeMarker) && !tx.resultHasScopeMarker {
+				if tx.needsScopeFixMarker {
+					lateStatements = append(lateStatements, createEmptyImports(tx.factory))
+				} else {
+					lateStatements = core.Map(lateStatements, tx.stripExportModifiers)
+				}
+			}
+			statementList := tx.factory.NewNodeList(lateStatements)
+			statementList.Loc = inner.AsModuleBlock().Statements.Loc
+			body = tx.factory.UpdateModuleBlock(inner.AsModuleBlock(), statementList)
+		}
+		tx.needsDeclare = previousNeedsDeclare
+		tx.needsScopeFixMarker = oldNeedsScopeFix
+		tx.resultHasScopeMarker = oldHasScopeFix
+		return tx.factory.UpdateModuleDeclaration(input, tx.ensureModifiers(input.AsNode()), input.Keyword, input.Name(), body)
+	}
+
+	tx.needsDeclare = previousNeedsDeclare
+	modifiers := tx.ensureModifiers(input.AsNode())
+	tx.needsDeclare = false
+	// eagerly transform nested namespaces (the nesting doesn't need any elision or painting done)
+	previousEnclosingDeclaration := tx.enclosingDeclaration
+	tx.enclosingDeclaration = inner
+	body := tx.transformModuleDeclaration(inner.AsModuleDeclaration(), false /*previousNeedsDeclare*/)
+	tx.enclosingDeclaration = previousEnclosingDeclaration
+	return tx.factory.UpdateModuleDeclaration(input, modifiers, input.Keyword, input.Name(), body)
+}
+
+func (tx *DeclarationTransformer) transformClassDeclaration(input *ast.ClassDeclaration) *ast.Node {
+	tx.errorNameNode = input.Name()
+	tx.errorFallbackNode = input.AsNode()
+	defer func() {
+		tx.errorNameNode = nil
+		tx.errorFallbackNode = nil
+	}()
+
+	modifiers := tx.ensureModifiers(input.AsNode())
+	typeParameters := tx.ensureTypeParams(input.AsNode(), input.TypeParameters)
+
+	var members []*ast.Node
+	if core.Some(input.Members.Nodes, func(member *ast.Node) bool {
+		return member.Name() != nil && ast.IsPrivateIdentifier(member.Name())
+	}) {
+		// When the class has at least one private identifier, create a unique constant identifier to retain the
+		// nominal typing behavior. This prevents other classes with the same public members from being used in place
+		// of the current class.
+		members = append(members, tx.factory.NewPropertyDeclaration(nil /*modifiers*/, tx.factory.NewPrivateIdentifier("#private"), nil /*postfixToken*/, nil /*typeNode*/, nil /*initializer*/))
+	}
+	if ctor := core.Find(input.Members.Nodes, func(member *ast.Node) bool {
+		return ast.IsConstructorDeclaration(member) && member.Body() != nil
+	}); ctor != nil {
+		oldDiag := tx.getSymbolAccessibilityDiagnostic
+		for _, param := range ctor.Parameters() {
+			if !ast.HasSyntacticModifier(param, ast.ModifierFlagsParameterPropertyModifier) {
+				continue
+			}
+			tx.getSymbolAccessibilityDiagnostic = createGetSymbolAccessibilityDiagnosticForNode(param)
+			if ast.IsIdentifier(param.Name()) {
+				p := param.AsParameterDeclaration()
+				property := tx.factory.NewPropertyDeclaration(tx.ensureModifiers(param), param.Name(), p.QuestionToken, tx.ensureType(param, p.Type, false /*ignorePrivate*/), tx.ensureNoInitializer(param))
+				tx.emitContext.SetOriginal(property, param)
+				members = append(members, property)
+			}
+			// !!! binding patterns in parameter properties are an error and are not written
+		}
+		tx.getSymbolAccessibilityDiagnostic = oldDiag
+	}
+	members = append(members, tx.visitor.VisitNodes(input.Members).Nodes...)
+	memberList := tx.factory.NewNodeList(members)
+	memberList.Loc = input.Members.Loc
+
+	extendsClause := ast.GetExtendsHeritageClauseElement(input.AsNode())
+	if extendsClause == nil || ast.IsEntityNameExpression(extendsClause.Expression()) || extendsClause.Expression().Kind == ast.KindNullKeyword {
+		return tx.factory.UpdateClassDeclaration(input, modifiers, input.Name(), typeParameters, tx.transformHeritageClauses(input.HeritageClauses), memberList)
+	}
+
+	// We must add a temporary declaration for the extends clause expression
+	oldId := "default"
+	if input.Name() != nil {
+		oldId = input.Name().Text()
+	}
+	newId := tx.emitContext.NewUniqueName(oldId+"_base", printer.AutoGenerateOptions{Flags: printer.GeneratedIdentifierFlagsOptimistic})
+	oldDiag := tx.getSymbolAccessibilityDiagnostic
+	tx.getSymbolAccessibilityDiagnostic = func(result printer.SymbolAccessibilityResult) *symbolAccessibilityDiagnostic {
+		return &symbolAccessibilityDiagnostic{diagnosticMessage: diagnostics.X_extends_clause_of_exported_class_0_has_or_is_using_private_name_1, errorNode: extendsClause, typeName: input.Name()}
+	}
+	varDecl := tx.factory.NewVariableDeclaration(newId, nil /*exclamationToken*/, tx.resolver.CreateTypeOfExpression(tx.emitContext, extendsClause.Expression(), input.AsNode(), tx), nil /*initializer*/)
+	tx.getSymbolAccessibilityDiagnostic = oldDiag
+	var varModifiers *ast.ModifierList
+	if tx.needsDeclare {
+		varModifiers = tx.factory.NewModifierList([]*ast.Node{tx.factory.NewModifier(ast.KindDeclareKeyword)})
+	}
+	statement := tx.factory.NewVariableStatement(varModifiers, tx.factory.NewVariableDeclarationList(ast.NodeFlagsConst, tx.factory.NewNodeList([]*ast.Node{varDecl})))
+
+	var heritageClauses []*ast.Node
+	for _, clause := range input.HeritageClauses.Nodes {
+		c := clause.AsHeritageClause()
+		if c.Token == ast.KindExtendsKeyword {
+			types := core.Map(c.Types.Nodes, func(t *ast.Node) *ast.Node {
+				return tx.factory.UpdateExpressionWithTypeArguments(t.AsExpressionWithTypeArguments(), newId, tx.visitor.VisitNodes(t.AsExpressionWithTypeArguments().TypeArguments))
+			})
+			heritageClauses = append(heritageClauses, tx.factory.UpdateHeritageClause(c, tx.factory.NewNodeList(types)))
+			continue
+		}
+		if transformed := tx.transformHeritageClause(clause); transformed != nil {
+			heritageClauses = append(heritageClauses, transformed)
+		}
+	}
+	class := tx.factory.UpdateClassDeclaration(input, modifiers, input.Name(), typeParameters, tx.factory.NewNodeList(heritageClauses), memberList)
+	return tx.factory.NewSyntaxList([]*ast.Node{statement, class})
+}
+
+func (tx *DeclarationTransformer) transformVariableStatement(input *ast.VariableStatement) *ast.Node {
+	declarationList := input.DeclarationList.AsVariableDeclarationList()
+	if !core.Some(declarationList.Declarations.Nodes, tx.getBindingNameVisible) {
+		return nil
+	}
+	declarations := tx.visitor.VisitNodes(declarationList.Declarations)
+	if len(declarations.Nodes) == 0 {
+		return nil
+	}
+	var newDeclarationList *ast.Node
+	if ast.IsVarUsing(input.DeclarationList) || ast.IsVarAwaitUsing(input.DeclarationList) {
+		// `using` declarations are written as `const` declarations
+		newDeclarationList = tx.factory.NewVariableDeclarationList(ast.NodeFlagsConst, declarations)
+		tx.emitContext.SetOriginal(newDeclarationList, input.DeclarationList)
+		newDeclarationList.Loc = input.DeclarationList.Loc
+	} else {
+		newDeclarationList = tx.factory.UpdateVariableDeclarationList(declarationList, declarations)
+	}
+	return tx.factory.UpdateVariableStatement(input, tx.ensureModifiers(input.AsNode()), newDeclarationList)
+}
+
+func (tx *DeclarationTransformer) transformImportEqualsDeclaration(input *ast.Node) *ast.Node {
+	if !tx.resolver.IsDeclarationVisible(input) {
+		return nil
+	}
+	if moduleReference := input.AsImportEqualsDeclaration().ModuleReference; moduleReference.Kind != ast.KindExternalModuleReference {
+		oldDiag := tx.getSymbolAccessibilityDiagnostic
+		tx.getSymbolAccessibilityDiagnostic = createGetSymbolAccessibilityDiagnosticForNode(input)
+		tx.checkEntityNameVisibility(moduleReference, tx.enclosingDeclaration)
+		tx.getSymbolAccessibilityDiagnostic = oldDiag
+	}
+	return input
+}
+
+func (tx *DeclarationTransformer) transformImportDeclaration(input *ast.ImportDeclaration) *ast.Node {
+	if input.ImportClause == nil {
+		// import "mod" - possibly needed for side effects? (global interface patches, module augmentations, etc)
+		return input.AsNode()
+	}
+	importClause := input.ImportClause.AsImportClause()
+	// The `importClause` visibility corresponds to the default's visibility.
+	var visibleDefaultBinding *ast.Node
+	if importClause.Name() != nil && tx.resolver.IsDeclarationVisible(input.ImportClause) {
+		visibleDefaultBinding = importClause.Name()
+	}
+	var namedBindings *ast.Node
+	switch {
+	case importClause.NamedBindings == nil:
+		// No named bindings (either namespace or list), meaning the import is just default or should be elided
+	case importClause.NamedBindings.Kind == ast.KindNamespaceImport:
+		// Namespace import (optionally with visible default)
+		if tx.resolver.IsDeclarationVisible(importClause.NamedBindings) {
+			namedBindings = importClause.NamedBindings
+		}
+	default:
+		// Named imports (optionally with visible default)
+		namedImports := importClause.NamedBindings.AsNamedImports()
+		bindingList := core.Filter(namedImports.Elements.Nodes, tx.resolver.IsDeclarationVisible)
+		if len(bindingList) != 0 {
+			elements := tx.factory.NewNodeList(bindingList)
+			elements.Loc = namedImports.Elements.Loc
+			namedBindings = tx.factory.UpdateNamedImports(namedImports, elements)
+		}
+	}
+	if visibleDefaultBinding == nil && namedBindings == nil {
+		// !!! imports required by module augmentations
+		// Nothing visible
+		return nil
+	}
+	return tx.factory.UpdateImportDeclaration(
+		input,
+		input.Modifiers(),
+		tx.factory.UpdateImportClause(importClause, importClause.IsTypeOnly, visibleDefaultBinding, namedBindings),
+		input.ModuleSpecifier,
+		input.Attributes,
+	)
+}
+
+func (tx *DeclarationTransformer) transformHeritageClauses(clauses *ast.NodeList) *ast.NodeList {
+	if clauses == nil {
+		return nil
+	}
+	var result []*ast.Node
+	for _, clause := range clauses.Nodes {
+		if transformed := tx.transformHeritageClause(clause); transformed != nil {
+			result = append(result, transformed)
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	list := tx.factory.NewNodeList(result)
+	list.Loc = clauses.Loc
+	return list
+}
+
+func (tx *DeclarationTransformer) transformHeritageClause(clause *ast.Node) *ast.Node {
+	c := clause.AsHeritageClause()
+	types := core.Filter(c.Types.Nodes, func(t *ast.Node) bool {
+		expression := t.Expression()
+		return ast.IsEntityNameExpression(expression) || c.Token == ast.KindExtendsKeyword && expression.Kind == ast.KindNullKeyword
+	})
+	if len(types) == 0 {
+		return nil
+	}
+	visited, _ := tx.visitor.VisitSlice(types)
+	if len(visited) == 0 {
+		return nil
+	}
+	typeList := tx.factory.NewNodeList(visited)
+	typeList.Loc = c.Types.Loc
+	return tx.factory.UpdateHeritageClause(c, typeList)
+}
+
+func isEnclosingDeclaration(node *ast.Node) bool {
+	switch node.Kind {
+	case ast.KindTypeParameter:
+		return false
+	case ast.KindTypeLiteral, ast.KindMappedType, ast.KindConditionalType, ast.KindFunctionType, ast.KindConstructorType:
+		return true
+	}
+	return ast.IsDeclaration(node)
+}
+
+func isScopeMarker(node *ast.Node) bool {
+	return ast.IsExportAssignment(node) || ast.IsExportDeclaration(node)
+}
+
+func needsScopeMarker(result *ast.Node) bool {
+	return !ast.IsImportDeclaration(result) && !ast.IsImportEqualsDeclaration(result) && !isScopeMarker(result) &&
+		!ast.HasSyntacticModifier(result, ast.ModifierFlagsExport) && !ast.IsAmbientModule(result)
+}
+
+func (tx *DeclarationTransformer) stripExportModifiers(statement *ast.Node) *ast.Node {
+	if ast.IsImportEqualsDeclaration(statement) || ast.HasSyntacticModifier(statement, ast.ModifierFlagsDefault) || !ast.HasSyntacticModifier(statement, ast.ModifierFlagsExport) {
+		return statement
+	}
+	modifiers := createModifiersFromModifierFlags(statement.ModifierFlags()&^ast.ModifierFlagsExport, tx.factory)
+	return replaceModifiers(tx.factory, statement, modifiers)
+}
+
+func (tx *DeclarationTransformer) isDeclarationAndNotVisible(node *ast.Node) bool {
+	switch node.Kind {
+	case ast.KindFunctionDeclaration,
+		ast.KindModuleDeclaration,
+		ast.KindInterfaceDeclaration,
+		ast.KindClassDeclaration,
+		ast.KindTypeAliasDeclaration,
+		ast.KindEnumDeclaration:
+		return !tx.resolver.IsDeclarationVisible(node)
+	// The following should be doing their own visibility checks based on filtering their members
+	case ast.KindVariableDeclaration:
+		return !tx.getBindingNameVisible(node)
+	case ast.KindClassStaticBlockDeclaration:
+		return true
+	}
+	return false
+}
+
+func (tx *DeclarationTransformer) getBindingNameVisible(node *ast.Node) bool {
+	if ast.IsOmittedExpression(node) {
+		return false
+	}
+	if ast.IsBindingPattern(node.Name()) {
+		// If any child binding pattern element has been marked visible (usually by collect linked aliases), then this is visible
+		return core.Some(node.Name().AsBindingPattern().Elements.Nodes, tx.getBindingNameVisible)
+	}
+	return tx.resolver.IsDeclarationVisible(node)
+}
+
+func (tx *DeclarationTransformer) visitDeclarationSubtree(input *ast.Node) *ast.Node {
+	if ast.IsDeclaration(input) {
+		if tx.isDeclarationAndNotVisible(input) {
+			return nil
+		}
+		if ast.HasDynamicName(input) && !tx.resolver.IsLateBound(input) {
+			return nil
+		}
+	}
+
+	// Elide implementation signatures from overload sets
+	if ast.IsFunctionLike(input) && tx.resolver.IsImplementationOfOverload(input) {
+		return nil
+	}
+
+	switch input.Kind {
+	case ast.KindSemicolonClassElement:
+		// Elide semicolon class statements
+		return nil
+	case ast.KindMethodDeclaration, ast.KindMethodSignature:
+		if ast.HasSyntacticModifier(input, ast.ModifierFlagsPrivate) {
+			// Private methods are written as properties without a type, and only the first overload is kept
+			if symbol := input.Symbol(); symbol != nil && len(symbol.Declarations) != 0 && symbol.Declarations[0] != input {
+				return nil
+			}
+			property := tx.factory.NewPropertyDeclaration(tx.ensureModifiers(input), input.Name(), nil /*postfixToken*/, nil /*typeNode*/, nil /*initializer*/)
+			tx.emitContext.SetOriginal(property, input)
+			property.Loc = input.Loc
+			return property
+		}
+	}
+
+	previousEnclosingDeclaration := tx.enclosingDeclaration
+	if isEnclosingDeclaration(input) {
+		tx.enclosingDeclaration = input
+	}
+	oldDiag := tx.getSymbolAccessibilityDiagnostic
+	if canProduceDiagnostics(input) && !tx.suppressNewDiagnosticContexts {
+		tx.getSymbolAccessibilityDiagnostic = createGetSymbolAccessibilityDiagnosticForNode(input)
+	}
+	oldWithinObjectLiteralType := tx.suppressNewDiagnosticContexts
+	if (input.Kind == ast.KindTypeLiteral || input.Kind == ast.KindMappedType) && input.Parent.Kind != ast.KindTypeAliasDeclaration {
+		tx.suppressNewDiagnosticContexts = true
+	}
+	defer func() {
+		tx.enclosingDeclaration = previousEnclosingDeclaration
+		tx.getSymbolAccessibilityDiagnostic = oldDiag
+		tx.suppressNewDiagnosticContexts = oldWithinObjectLiteralType
+	}()
+
+	switch input.Kind {
+	case ast.KindExpressionWithTypeArguments:
+		if expression := input.Expression(); ast.IsEntityName(expression) || ast.IsEntityNameExpression(expression) {
+			tx.checkEntityNameVisibility(expression, tx.enclosingDeclaration)
+		}
+		return tx.visitor.VisitEachChild(input)
+	case ast.KindTypeReference:
+		tx.checkEntityNameVisibility(input.AsTypeReferenceNode().TypeName, tx.enclosingDeclaration)
+		return tx.visitor.VisitEachChild(input)
+	case ast.KindTypeQuery:
+		tx.checkEntityNameVisibility(input.AsTypeQueryNode().ExprName, tx.enclosingDeclaration)
+		return tx.visitor.VisitEachChild(input)
+	case ast.KindComputedPropertyName:
+		if expression := input.Expression(); ast.IsEntityNameExpression(expression) {
+			tx.checkEntityNameVisibility(expression, tx.enclosingDeclaration)
+		}
+		return input
+	case ast.KindConstructSignature:
+		n := input.AsConstructSignatureDeclaration()
+		return tx.factory.UpdateConstructSignatureDeclaration(n, tx.ensureTypeParams(input, n.TypeParameters), tx.updateParamsList(input, n.Parameters, ast.ModifierFlagsAll^ast.ModifierFlagsPublic), tx.ensureType(input, n.Type, false /*ignorePrivate*/))
+	case ast.KindConstructor:
+		// A constructor declaration may not have a type annotation
+		n := input.AsConstructorDeclaration()
+		return tx.factory.UpdateConstructorDeclaration(n, tx.ensureModifiers(input), nil /*typeParameters*/, tx.updateParamsList(input, n.Parameters, ast.ModifierFlagsNone), nil /*returnType*/, nil /*body*/)
+	case ast.KindMethodDeclaration:
+		if ast.IsPrivateIdentifier(input.Name()) {
+			return nil
+		}
+		n := input.AsMethodDeclaration()
+		return tx.factory.UpdateMethodDeclaration(n, tx.ensureModifiers(input), nil /*asteriskToken*/, n.Name(), n.PostfixToken, tx.ensureTypeParams(input, n.TypeParameters), tx.updateParamsList(input, n.Parameters, ast.ModifierFlagsAll^ast.ModifierFlagsPublic), tx.ensureType(input, n.Type, false /*ignorePrivate*/), nil /*body*/)
+	case ast.KindGetAccessor:
+		if ast.IsPrivateIdentifier(input.Name()) {
+			return nil
+		}
+		n := input.AsGetAccessorDeclaration()
+		return tx.factory.UpdateGetAccessorDeclaration(n, tx.ensureModifiers(input), n.Name(), nil /*typeParameters*/, tx.updateAccessorParamsList(input), tx.ensureType(input, getTypeAnnotationFromAllAccessorDeclarations(input), false /*ignorePrivate*/), nil /*body*/)
+	case ast.KindSetAccessor:
+		if ast.IsPrivateIdentifier(input.Name()) {
+			return nil
+		}
+		n := input.AsSetAccessorDeclaration()
+		return tx.factory.UpdateSetAccessorDeclaration(n, tx.ensureModifiers(input), n.Name(), nil /*typeParameters*/, tx.updateAccessorParamsList(input), nil /*returnType*/, nil /*body*/)
+	case ast.KindPropertyDeclaration:
+		if ast.IsPrivateIdentifier(input.Name()) {
+			return nil
+		}
+		n := input.AsPropertyDeclaration()
+		return tx.factory.UpdatePropertyDeclaration(n, tx.ensureModifiers(input), n.Name(), n.PostfixToken, tx.ensureType(input, n.Type, false /*ignorePrivate*/), tx.ensureNoInitializer(input))
+	case ast.KindPropertySignature:
+		if ast.IsPrivateIdentifier(input.Name()) {
+			return nil
+		}
+		n := input.AsPropertySignatureDeclaration()
+		return tx.factory.UpdatePropertySignatureDeclaration(n, tx.ensureModifiers(input), n.Name(), n.PostfixToken, tx.ensureType(input, n.Type, false /*ignorePrivate*/), tx.ensureNoInitializer(input))
+	case ast.KindMethodSignature:
+		if ast.IsPrivateIdentifier(input.Name()) {
+			return nil
+		}
+		n := input.AsMethodSignatureDeclaration()
+		return tx.factory.UpdateMethodSignatureDeclaration(n, tx.ensureModifiers(input), n.Name(), n.PostfixToken, tx.ensureTypeParams(input, n.TypeParameters), tx.updateParamsList(input, n.Parameters, ast.ModifierFlagsAll^ast.ModifierFlagsPublic), tx.ensureType(input, n.Type, false /*ignorePrivate*/))
+	case ast.KindCallSignature:
+		n := input.AsCallSignatureDeclaration()
+		return tx.factory.UpdateCallSignatureDeclaration(n, tx.ensureTypeParams(input, n.TypeParameters), tx.updateParamsList(input, n.Parameters, ast.ModifierFlagsAll^ast.ModifierFlagsPublic), tx.ensureType(input, n.Type, false /*ignorePrivate*/))
+	case ast.KindIndexSignature:
+		n := input.AsIndexSignatureDeclaration()
+		typeNode := tx.visitTypeNode(n.Type)
+		if typeNode == nil {
+			typeNode = tx.factory.NewKeywordTypeNode(ast.KindAnyKeyword)
+		}
+		return tx.factory.UpdateIndexSignatureDeclaration(n, tx.ensureModifiers(input), tx.updateParamsList(input, n.Parameters, ast.ModifierFlagsAll^ast.ModifierFlagsPublic), typeNode)
+	case ast.KindVariableDeclaration:
+		if ast.IsBindingPattern(input.Name()) {
+			return tx.factory.NewSyntaxList(tx.recreateBindingPattern(input.Name()))
+		}
+		// Variable declaration types also suppress new diagnostic contexts, provided the contexts wouldn't be made for binding pattern types
+		tx.suppressNewDiagnosticContexts = true
+		n := input.AsVariableDeclaration()
+		return tx.factory.UpdateVariableDeclaration(n, n.Name(), nil /*exclamationToken*/, tx.ensureType(input, n.Type, false /*ignorePrivate*/), tx.ensureNoInitializer(input))
+	case ast.KindTypeParameter:
+		n := input.AsTypeParameter()
+		if isPrivateMethodTypeParameter(input) && (n.DefaultType != nil || n.Constraint != nil) {
+			return tx.factory.UpdateTypeParameterDeclaration(n, n.Modifiers(), n.Name(), nil /*constraint*/, nil /*defaultType*/)
+		}
+		return tx.visitor.VisitEachChild(input)
+	case ast.KindConditionalType:
+		// We have to process conditional types in a special way because for visibility purposes we need to push a new
+		// enclosingDeclaration for the true branch, where `infer` type parameters are in scope
+		n := input.AsConditionalTypeNode()
+		checkType := tx.visitTypeNode(n.CheckType)
+		extendsType := tx.visitTypeNode(n.ExtendsType)
+		oldEnclosingDeclaration := tx.enclosingDeclaration
+		tx.enclosingDeclaration = n.TrueType
+		trueType := tx.visitTypeNode(n.TrueType)
+		tx.enclosingDeclaration = oldEnclosingDeclaration
+		falseType := tx.visitTypeNode(n.FalseType)
+		return tx.factory.UpdateConditionalTypeNode(n, checkType, extendsType, trueType, falseType)
+	case ast.KindFunctionType:
+		n := input.AsFunctionTypeNode()
+		return tx.factory.UpdateFunctionTypeNode(n, tx.visitor.VisitNodes(n.TypeParameters), tx.updateParamsList(input, n.Parameters, ast.ModifierFlagsAll^ast.ModifierFlagsPublic), tx.ensureTypeNode(n.Type))
+	case ast.KindConstructorType:
+		n := input.AsConstructorTypeNode()
+		return tx.factory.UpdateConstructorTypeNode(n, n.Modifiers(), tx.visitor.VisitNodes(n.TypeParameters), tx.updateParamsList(input, n.Parameters, ast.ModifierFlagsAll^ast.ModifierFlagsPublic), tx.ensureTypeNode(n.Type))
+	}
+	return tx.visitor.VisitEachChild(input)
+}
+
+func (tx *DeclarationTransformer) visitTypeNode(node *ast.Node) *ast.Node {
+	return tx.visitor.VisitNode(node)
+}
+
+func (tx *DeclarationTransformer) ensureTypeNode(node *ast.Node) *ast.Node {
+	if typeNode := tx.visitTypeNode(node); typeNode != nil {
+		return typeNode
+	}
+	return tx.factory.NewKeywordTypeNode(ast.KindAnyKeyword)
+}
+
+func isPrivateMethodTypeParameter(node *ast.Node) bool {
+	return node.Parent.Kind == ast.KindMethodDeclaration && ast.HasSyntacticModifier(node.Parent, ast.ModifierFlagsPrivate)
+}
+
+func (tx *DeclarationTransformer) recreateBindingPattern(pattern *ast.Node) []*ast.Node {
+	var result []*ast.Node
+	for _, element := range pattern.AsBindingPattern().Elements.Nodes {
+		if ast.IsOmittedExpression(element) || element.Name() == nil || !tx.getBindingNameVisible(element) {
+			continue
+		}
+		if ast.IsBindingPattern(element.Name()) {
+			result = append(result, tx.recreateBindingPattern(element.Name())...)
+			continue
+		}
+		result = append(result, tx.factory.NewVariableDeclaration(element.Name(), nil /*exclamationToken*/, tx.ensureType(element, nil /*typeNode*/, false /*ignorePrivate*/), nil /*initializer*/))
+	}
+	return result
+}
+
+func (tx *DeclarationTransformer) checkEntityNameVisibility(entityName *ast.Node, enclosingDeclaration *ast.Node) {
+	tx.handleSymbolAccessibilityError(tx.resolver.IsEntityNameVisible(entityName, enclosingDeclaration))
+}
+
+func (tx *DeclarationTransformer) handleSymbolAccessibilityError(result printer.SymbolAccessibilityResult) bool {
+	if result.Accessibility == printer.SymbolAccessibilityAccessible {
+		// Add aliases back onto the possible imports list if they're not there so we can try them again with updated visibility info
+		for _, alias := range result.AliasesToMakeVisible {
+			if !slices.Contains(tx.lateMarkedStatements, alias) {
+				tx.lateMarkedStatements = append(tx.lateMarkedStatements, alias)
+			}
+		}
+		return false
+	}
+	if tx.getSymbolAccessibilityDiagnostic == nil {
+		return false
+	}
+	errorInfo := tx.getSymbolAccessibilityDiagnostic(result)
+	if errorInfo == nil {
+		return false
+	}
+	errorNode := result.ErrorNode
+	if errorNode == nil {
+		errorNode = errorInfo.errorNode
+	}
+	if errorInfo.typeName != nil {
+		tx.addDiagnostic(createDiagnosticForNode(errorNode, errorInfo.diagnosticMessage, scanner.GetTextOfNode(errorInfo.typeName), result.ErrorSymbolName, result.ErrorModuleName))
+	} else {
+		tx.addDiagnostic(createDiagnosticForNode(errorNode, errorInfo.diagnosticMessage, result.ErrorSymbolName, result.ErrorModuleName))
+	}
+	return true
+}
+
+// TrackSymbolAccessibility implements printer.SymbolTracker.
+func (tx *DeclarationTransformer) TrackSymbolAccessibility(result printer.SymbolAccessibilityResult) bool {
+	return tx.handleSymbolAccessibilityError(result)
+}
+
+func (tx *DeclarationTransformer) errorNode() *ast.Node {
+	if tx.errorNameNode != nil {
+		return tx.errorNameNode
+	}
+	return tx.errorFallbackNode
+}
+
+func (tx *DeclarationTransformer) errorDeclarationNameWithFallback() string {
+	switch {
+	case tx.errorNameNode != nil:
+		return scanner.DeclarationNameToString(tx.errorNameNode)
+	case tx.errorFallbackNode != nil && ast.GetNameOfDeclaration(tx.errorFallbackNode) != nil:
+		return scanner.DeclarationNameToString(ast.GetNameOfDeclaration(tx.errorFallbackNode))
+	case tx.errorFallbackNode != nil && ast.IsExportAssignment(tx.errorFallbackNode):
+		return core.IfElse(tx.errorFallbackNode.AsExportAssignment().IsExportEquals, "export=", "default")
+	}
+	return "(Missing)"
+}
+
+// ReportInaccessibleThisError implements printer.SymbolTracker.
+func (tx *DeclarationTransformer) ReportInaccessibleThisError() {
+	if errorNode := tx.errorNode(); errorNode != nil {
+		tx.addDiagnostic(createDiagnosticForNode(errorNode, diagnostics.The_inferred_type_of_0_references_an_inaccessible_1_type_A_type_annotation_is_necessary, tx.errorDeclarationNameWithFallback(), "this"))
+	}
+}
+
+// ReportInaccessibleUniqueSymbolError implements printer.SymbolTracker.
+func (tx *DeclarationTransformer) ReportInaccessibleUniqueSymbolError() {
+	if errorNode := tx.errorNode(); errorNode != nil {
+		tx.addDiagnostic(createDiagnosticForNode(errorNode, diagnostics.The_inferred_type_of_0_references_an_inaccessible_1_type_A_type_annotation_is_necessary, tx.errorDeclarationNameWithFallback(), "unique symbol"))
+	}
+}
+
+// ReportCyclicStructureError implements printer.SymbolTracker.
+func (tx *DeclarationTransformer) ReportCyclicStructureError() {
+	if errorNode := tx.errorNode(); errorNode != nil {
+		tx.addDiagnostic(createDiagnosticForNode(errorNode, diagnostics.The_inferred_type_of_0_references_a_type_with_a_cyclic_structure_which_cannot_be_trivially_serialized_A_type_annotation_is_necessary, tx.errorDeclarationNameWithFallback()))
+	}
+}
+
+// ReportPrivateInBaseOfClassExpression implements printer.SymbolTracker.
+func (tx *DeclarationTransformer) ReportPrivateInBaseOfClassExpression(propertyName string) {
+	if errorNode := tx.errorNode(); errorNode != nil {
+		tx.addDiagnostic(createDiagnosticForNode(errorNode, diagnostics.Property_0_of_exported_anonymous_class_type_may_not_be_private_or_protected, propertyName))
+	}
+}
+
+// ReportLikelyUnsafeImportRequiredError implements printer.SymbolTracker.
+func (tx *DeclarationTransformer) ReportLikelyUnsafeImportRequiredError(specifier string) {
+	if errorNode := tx.errorNode(); errorNode != nil {
+		tx.addDiagnostic(createDiagnosticForNode(errorNode, diagnostics.The_inferred_type_of_0_cannot_be_named_without_a_reference_to_1_This_is_likely_not_portable_A_type_annotation_is_necessary, tx.errorDeclarationNameWithFallback(), specifier))
+	}
+}
+
+// ReportTruncationError implements printer.SymbolTracker.
+func (tx *DeclarationTransformer) ReportTruncationError() {
+	if errorNode := tx.errorNode(); errorNode != nil {
+		tx.addDiagnostic(createDiagnosticForNode(errorNode, diagnostics.The_inferred_type_of_this_node_exceeds_the_maximum_length_the_compiler_will_serialize_An_explicit_type_annotation_is_needed))
+	}
+}
+
+func (tx *DeclarationTransformer) ensureModifiers(node *ast.Node) *ast.ModifierList {
+	currentFlags := node.ModifierFlags()
+	newFlags := tx.ensureModifierFlags(node)
+	if currentFlags == newFlags && currentFlags&ast.ModifierFlagsDecorator == 0 {
+		return node.Modifiers()
+	}
+	return createModifiersFromModifierFlags(newFlags, tx.factory)
+}
+
+func (tx *DeclarationTransformer) ensureModifierFlags(node *ast.Node) ast.ModifierFlags {
+	// No async and override modifiers in declaration files
+	mask := ast.ModifierFlagsAll ^ (ast.ModifierFlagsPublic | ast.ModifierFlagsAsync | ast.ModifierFlagsOverride | ast.ModifierFlagsDecorator)
+	additions := ast.ModifierFlagsNone
+	if tx.needsDeclare && node.Kind != ast.KindInterfaceDeclaration {
+		additions = ast.ModifierFlagsAmbient
+	}
+	if node.Parent.Kind != ast.KindSourceFile {
+		mask ^= ast.ModifierFlagsAmbient
+		additions = ast.ModifierFlagsNone
+	}
+	return maskModifierFlags(node, mask, additions)
+}
+
+func maskModifierFlags(node *ast.Node, modifierMask ast.ModifierFlags, modifierAdditions ast.ModifierFlags) ast.ModifierFlags {
+	flags := node.ModifierFlags()&modifierMask | modifierAdditions
+	if flags&ast.ModifierFlagsDefault != 0 && flags&ast.ModifierFlagsExport == 0 {
+		// A non-exported default is a nonsequitor - we usually try to remove all export modifiers
+		// from statements in ambient declarations; but a default export must retain its export modifier to be syntactically valid
+		flags ^= ast.ModifierFlagsExport
+	}
+	if flags&ast.ModifierFlagsDefault != 0 && flags&ast.ModifierFlagsAmbient != 0 {
+		// `declare` is never required alongside `default` (and would be an error if printed)
+		flags ^= ast.ModifierFlagsAmbient
+	}
+	return flags
+}
+
+func (tx *DeclarationTransformer) ensureTypeParams(node *ast.Node, params *ast.NodeList) *ast.NodeList {
+	if ast.HasSyntacticModifier(node, ast.ModifierFlagsPrivate) {
+		return nil
+	}
+	return tx.visitor.VisitNodes(params)
+}
+
+func (tx *DeclarationTransformer) updateParamsList(node *ast.Node, params *ast.NodeList, modifierMask ast.ModifierFlags) *ast.NodeList {
+	if ast.HasSyntacticModifier(node, ast.ModifierFlagsPrivate) {
+		return tx.factory.NewNodeList(nil)
+	}
+	newParams := core.Map(params.Nodes, func(p *ast.Node) *ast.Node {
+		return tx.ensureParameter(p, modifierMask, p.Type())
+	})
+	list := tx.factory.NewNodeList(newParams)
+	list.Loc = params.Loc
+	return list
+}
+
+func (tx *DeclarationTransformer) updateAccessorParamsList(input *ast.Node) *ast.NodeList {
+	isPrivate := ast.HasSyntacticModifier(input, ast.ModifierFlagsPrivate)
+	var newParams []*ast.Node
+	if !isPrivate {
+		if params := input.Parameters(); len(params) != 0 && ast.IsThisParameter(params[0]) {
+			newParams = append(newParams, tx.ensureParameter(params[0], ast.ModifierFlagsAll^ast.ModifierFlagsPublic, params[0].Type()))
+		}
+	}
+	if ast.IsSetAccessorDeclaration(input) {
+		var newValueParameter *ast.Node
+		if !isPrivate {
+			if valueParameter := getSetAccessorValueParameter(input); valueParameter != nil {
+				newValueParameter = tx.ensureParameter(valueParameter, ast.ModifierFlagsAll^ast.ModifierFlagsPublic, getTypeAnnotationFromAllAccessorDeclarations(input))
+			}
+		}
+		if newValueParameter == nil {
+			newValueParameter = tx.factory.NewParameterDeclaration(nil /*modifiers*/, nil /*dotDotDotToken*/, tx.factory.NewIdentifier("value"), nil /*questionToken*/, nil /*typeNode*/, nil /*initializer*/)
+		}
+		newParams = append(newParams, newValueParameter)
+	}
+	return tx.factory.NewNodeList(newParams)
+}
+
+func (tx *DeclarationTransformer) ensureParameter(p *ast.Node, modifierMask ast.ModifierFlags, typeNode *ast.Node) *ast.Node {
+	oldDiag := tx.getSymbolAccessibilityDiagnostic
+	if !tx.suppressNewDiagnosticContexts {
+		tx.getSymbolAccessibilityDiagnostic = createGetSymbolAccessibilityDiagnosticForNode(p)
+	}
+	n := p.AsParameterDeclaration()
+	var questionToken *ast.Node
+	if tx.resolver.IsOptionalParameter(p) {
+		questionToken = n.QuestionToken
+		if questionToken == nil {
+			questionToken = tx.factory.NewToken(ast.KindQuestionToken)
+		}
+	}
+	newParam := tx.factory.UpdateParameterDeclaration(
+		n,
+		createModifiersFromModifierFlags(maskModifierFlags(p, modifierMask, ast.ModifierFlagsNone), tx.factory),
+		n.DotDotDotToken,
+		tx.filterBindingPatternInitializers(n.Name()),
+		questionToken,
+		tx.ensureType(p, typeNode, true /*ignorePrivate*/), // Ignore private param props, since this type is going straight back into a param
+		tx.ensureNoInitializer(p),
+	)
+	tx.getSymbolAccessibilityDiagnostic = oldDiag
+	return newParam
+}
+
+func (tx *DeclarationTransformer) filterBindingPatternInitializers(name *ast.Node) *ast.Node {
+	if ast.IsIdentifier(name) {
+		return name
+	}
+	pattern := name.AsBindingPattern()
+	elements := core.Map(pattern.Elements.Nodes, func(element *ast.Node) *ast.Node {
+		if ast.IsOmittedExpression(element) {
+			return element
+		}
+		e := element.AsBindingElement()
+		if e.PropertyName != nil && ast.IsComputedPropertyName(e.PropertyName) && ast.IsEntityNameExpression(e.PropertyName.Expression()) {
+			tx.checkEntityNameVisibility(e.PropertyName.Expression(), tx.enclosingDeclaration)
+		}
+		return tx.factory.UpdateBindingElement(e, e.DotDotDotToken, e.PropertyName, tx.filterBindingPatternInitializers(e.Name()), nil /*initializer*/)
+	})
+	elementList := tx.factory.NewNodeList(elements)
+	elementList.Loc = pattern.Elements.Loc
+	return tx.factory.UpdateBindingPattern(pattern, elementList)
+}
+
+func canHaveLiteralInitializer(node *ast.Node) bool {
+	switch node.Kind {
+	case ast.KindPropertyDeclaration, ast.KindPropertySignature:
+		return !ast.HasSyntacticModifier(node, ast.ModifierFlagsPrivate)
+	case ast.KindParameter, ast.KindVariableDeclaration:
+		return true
+	}
+	return false
+}
+
+func (tx *DeclarationTransformer) shouldPrintWithInitializer(node *ast.Node) bool {
+	return canHaveLiteralInitializer(node) && node.Type() == nil && node.Initializer() != nil && tx.resolver.IsLiteralConstDeclaration(node)
+}
+
+func (tx *DeclarationTransformer) ensureNoInitializer(node *ast.Node) *ast.Node {
+	if tx.shouldPrintWithInitializer(node) {
+		return tx.resolver.CreateLiteralConstValue(tx.emitContext, node, tx)
+	}
+	return nil
+}
+
+func (tx *DeclarationTransformer) ensureType(node *ast.Node, typeNode *ast.Node, ignorePrivate bool) *ast.Node {
+	if !ignorePrivate && ast.HasSyntacticModifier(node, ast.ModifierFlagsPrivate) {
+		// Private nodes emit no types (except private parameter properties, whose parameter types are actually visible)
+		return nil
+	}
+	if tx.shouldPrintWithInitializer(node) {
+		// Literal const declarations will have an initializer ensured rather than a type
+		return nil
+	}
+	shouldAddImplicitUndefined := node.Kind == ast.KindParameter && tx.resolver.RequiresAddingImplicitUndefined(node, tx.enclosingDeclaration)
+	if typeNode != nil && !shouldAddImplicitUndefined {
+		return tx.visitTypeNode(typeNode)
+	}
+
+	tx.errorNameNode = node.Name()
+	oldDiag := tx.getSymbolAccessibilityDiagnostic
+	if !tx.suppressNewDiagnosticContexts && canProduceDiagnostics(node) {
+		tx.getSymbolAccessibilityDiagnostic = createGetSymbolAccessibilityDiagnosticForNode(node)
+	}
+	var result *ast.Node
+	if ast.IsFunctionLike(node) {
+		result = tx.resolver.CreateReturnTypeOfSignatureDeclaration(tx.emitContext, node, tx.enclosingDeclaration, tx)
+	} else {
+		result = tx.resolver.CreateTypeOfDeclaration(tx.emitContext, node, tx.enclosingDeclaration, tx)
+	}
+	tx.errorNameNode = nil
+	tx.getSymbolAccessibilityDiagnostic = oldDiag
+	if result == nil {
+		return tx.factory.NewKeywordTypeNode(ast.KindAnyKeyword)
+	}
+	return result
+}
+
+func getSetAccessorValueParameter(accessor *ast.Node) *ast.Node {
+	parameters := accessor.Parameters()
+	if len(parameters) > 0 {
+		hasThis := len(parameters) == 2 && ast.IsThisParameter(parameters[0])
+		return parameters[core.IfElse(hasThis, 1, 0)]
+	}
+	return nil
+}
+
+// Gets the type annotation of an accessor, falling back to the annotation on the corresponding get or set accessor.
+func getTypeAnnotationFromAllAccessorDeclarations(accessor *ast.Node) *ast.Node {
+	if typeNode := getAccessorTypeAnnotation(accessor); typeNode != nil {
+		return typeNode
+	}
+	if accessor.Parent == nil || !ast.IsClassLike(accessor.Parent) && accessor.Parent.Kind != ast.KindTypeLiteral && accessor.Parent.Kind != ast.KindInterfaceDeclaration {
+		return nil
+	}
+	name := accessor.Name()
+	if !ast.IsPropertyNameLiteral(name) && !ast.IsPrivateIdentifier(name) {
+		return nil
+	}
+	for _, member := range accessor.Parent.Members() {
+		if member == accessor || !ast.IsAccessor(member) || ast.IsStatic(member) != ast.IsStatic(accessor) {
+			continue
+		}
+		if otherName := member.Name(); (ast.IsPropertyNameLiteral(otherName) || ast.IsPrivateIdentifier(otherName)) && otherName.Text() == name.Text() {
+			if typeNode := getAccessorTypeAnnotation(member); typeNode != nil {
+				return typeNode
+			}
+		}
+	}
+	return nil
+}
+
+func getAccessorTypeAnnotation(accessor *ast.Node) *ast.Node {
+	if accessor.Kind == ast.KindGetAccessor {
+		return accessor.Type()
+	}
+	if parameter := getSetAccessorValueParameter(accessor); parameter != nil {
+		return parameter.Type()
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package transformers
+
+import (
+	"testing"
+
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/checker"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/printer"
+	"github.com/microsoft/typescript-go/internal/testutil/emittestutil"
+	"github.com/microsoft/typescript-go/internal/testutil/parsetestutil"
+	"gotest.tools/v3/assert"
+)
+
+func TestDeclarationTransformer(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title       string
+		input       string
+		output      string
+		diagnostics []int32
+	}{
+		{title: "Variable#1", input: "export const x = 1;", output: "export declare const x = 1;"},
+		{title: "Variable#2", input: "export let x = 1;", output: "export declare let x: number;"},
+		{title: "Variable#3", input: "export let x: string, y = true;", output: "export declare let x: string, y: boolean;"},
+		{title: "Variable#4", input: "let x = 1;", output: "declare let x: number;"},
+		{title: "Variable#5", input: "export const { a, b } = { a: 1, b: \"\" };", output: "export declare const a: number, b: string;"},
+		{title: "Function#1", input: "export function f(a: number) { return a; }", output: "export declare function f(a: number): number;"},
+		{title: "Function#2", input: "export function f(a = 1, b?: string) {}", output: "export declare function f(a?: number, b?: string): void;"},
+		{title: "Function#3", input: "export function f(a: string): void;\nexport function f(a: number): void;\nexport function f(a: any) {}", output: "export declare function f(a: string): void;\nexport declare function f(a: number): void;"},
+		{title: "Function#4", input: "export function f(a: string) { return { a }; }", output: "export declare function f(a: string): {\n    a: string;\n};"},
+		{title: "Class#1", input: "export class C { x = 1; private y = 2; m() { return \"\"; } }", output: "export declare class C {\n    x: number;\n    private y;\n    m(): string;\n}"},
+		{title: "Class#2", input: "export class C { constructor(public a: number, private b: string) {} }", output: "export declare class C {\n    a: number;\n    private b;\n    constructor(a: number, b: string);\n}"},
+		{title: "Class#3", input: "export class C { #x = 1; }", output: "export declare class C {\n    #private;\n}"},
+		{title: "Class#4", input: "export class C { get x() { return 1; } set x(v) {} }", output: "export declare class C {\n    get x(): number;\n    set x(v: number);\n}"},
+		{title: "Class#5", input: "export class C extends (class {}) {}", output: "declare const C_base: {\n    new (): {};\n};\nexport declare class C extends C_base {\n}\nexport {};"},
+		{title: "Interface#1", input: "export interface I { a: number; m(): void; }", output: "export interface I {\n    a: number;\n    m(): void;\n}"},
+		{title: "TypeAlias#1", input: "type T = string; export type U = T[];", output: "type T = string;\nexport type U = T[];\nexport {};"},
+		{title: "Enum#1", input: "export enum E { A, B = 4, C }", output: "export declare enum E {\n    A = 0,\n    B = 4,\n    C = 5\n}"},
+		{title: "Namespace#1", input: "export namespace N { export const a = 1; }", output: "export declare namespace N {\n    const a = 1;\n}"},
+		{title: "Namespace#2", input: "export namespace N { export const a = 1; const b = 2; export { b }; }", output: "export declare namespace N {\n    export const a = 1;\n    const b = 2;\n    export { b };\n}"},
+		{title: "ExportAssignment#1", input: "export default { a: 1 };", output: "declare const _default: {\n    a: number;\n};\nexport default _default;"},
+		{title: "ExportAssignment#2", input: "const a = 1; export default a;", output: "declare const a = 1;\nexport default a;"},
+		{title: "Statements#1", input: "export const x = 1; console.log(x); if (x) {}", output: "export declare const x = 1;"},
+		{title: "ScopeMarker#1", input: "interface I {} export const x: I = {};", output: "interface I {\n}\nexport declare const x: I;\nexport {};"},
+		{title: "NotVisible#1", input: "interface I {} export const x = 1;", output: "export declare const x = 1;"},
+		{title: "NotVisible#2", input: "class C { p = 1 } export class D extends (class { private p = 1 }) {}", output: "declare const D_base: {\n    new (): {};\n};\nexport declare class D extends D_base {\n}\nexport {};", diagnostics: []int32{4094}},
+	}
+
+	for _, rec := range data {
+		t.Run(rec.title, func(t *testing.T) {
+			t.Parallel()
+
+			file := parsetestutil.ParseTypeScript(rec.input, false /*jsx*/)
+			parsetestutil.CheckDiagnostics(t, file)
+
+			compilerOptions := &core.CompilerOptions{Declaration: core.TSTrue, Strict: core.TSTrue}
+
+			c := checker.NewChecker(&fakeProgram{
+				singleThreaded:  true,
+				compilerOptions: compilerOptions,
+				files:           []*ast.SourceFile{file},
+				getEmitModuleFormatOfFile: func(sourceFile *ast.SourceFile) core.ModuleKind {
+					return core.ModuleKindESNext
+				},
+				getImpliedNodeFormatForEmit: func(sourceFile *ast.SourceFile) core.ModuleKind {
+					return core.ModuleKindESNext
+				},
+				getResolvedModule: func(currentSourceFile *ast.SourceFile, moduleReference string) *ast.SourceFile {
+					return nil
+				},
+			})
+
+			emitResolver := c.GetEmitResolver(file, false /*skipDiagnostics*/)
+
+			emitContext := printer.NewEmitContext()
+			tx := NewDeclarationTransformer(emitContext, compilerOptions, emitResolver)
+			file = tx.TransformSourceFile(file)
+			emittestutil.CheckEmit(t, emitContext, file, rec.output)
+
+			codes := core.Map(tx.Diagnostics(), func(d *ast.Diagnostic) int32 { return d.Code() })
+			assert.DeepEqual(t, rec.diagnostics, codes)
+		})
+	}
+}
@@ -518,3 +518,84 @@ func isSimpleCopiableExpression(expression *ast.Expression) bool {
 func isSimpleInlineableExpression(expression *ast.Expression) bool {
 	return !ast.IsIdentifier(expression) && isSimpleCopiableExpression(expression)
 }
+
+func createModifiersFromModifierFlags(flags ast.ModifierFlags, factory *ast.NodeFactory) *ast.ModifierList {
+	var result []*ast.Node
+	if flags&ast.ModifierFlagsExport != 0 {
+		result = append(result, factory.NewModifier(ast.KindExportKeyword))
+	}
+	if flags&ast.ModifierFlagsAmbient != 0 {
+		result = append(result, factory.NewModifier(ast.KindDeclareKeyword))
+	}
+	if flags&ast.ModifierFlagsDefault != 0 {
+		result = append(result, factory.NewModifier(ast.KindDefaultKeyword))
+	}
+	if flags&ast.ModifierFlagsConst != 0 {
+		result = append(result, factory.NewModifier(ast.KindConstKeyword))
+	}
+	if flags&ast.ModifierFlagsPublic != 0 {
+		result = append(result, factory.NewModifier(ast.KindPublicKeyword))
+	}
+	if flags&ast.ModifierFlagsPrivate != 0 {
+		result = append(result, factory.NewModifier(ast.KindPrivateKeyword))
+	}
+	if flags&ast.ModifierFlagsProtected != 0 {
+		result = append(result, factory.NewModifier(ast.KindProtectedKeyword))
+	}
+	if flags&ast.ModifierFlagsAbstract != 0 {
+		result = append(result, factory.NewModifier(ast.KindAbstractKeyword))
+	}
+	if flags&ast.ModifierFlagsStatic != 0 {
+		result = append(result, factory.NewModifier(ast.KindStaticKeyword))
+	}
+	if flags&ast.ModifierFlagsOverride != 0 {
+		result = append(result, factory.NewModifier(ast.KindOverrideKeyword))
+	}
+	if flags&ast.ModifierFlagsReadonly != 0 {
+		result = append(result, factory.NewModifier(ast.KindReadonlyKeyword))
+	}
+	if flags&ast.ModifierFlagsAccessor != 0 {
+		result = append(result, factory.NewModifier(ast.KindAccessorKeyword))
+	}
+	if flags&ast.ModifierFlagsAsync != 0 {
+		result = append(result, factory.NewModifier(ast.KindAsyncKeyword))
+	}
+	if flags&ast.ModifierFlagsIn != 0 {
+		result = append(result, factory.NewModifier(ast.KindInKeyword))
+	}
+	if flags&ast.ModifierFlagsOut != 0 {
+		result = append(result, factory.NewModifier(ast.KindOutKeyword))
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return factory.NewModifierList(result)
+}
+
+// Replaces the modifiers of a declaration statement.
+func replaceModifiers(factory *ast.NodeFactory, node *ast.Statement, modifiers *ast.ModifierList) *ast.Statement {
+	switch node.Kind {
+	case ast.KindVariableStatement:
+		n := node.AsVariableStatement()
+		return factory.UpdateVariableStatement(n, modifiers, n.DeclarationList)
+	case ast.KindFunctionDeclaration:
+		n := node.AsFunctionDeclaration()
+		return factory.UpdateFunctionDeclaration(n, modifiers, n.AsteriskToken, n.Name(), n.TypeParameters, n.Parameters, n.Type, n.Body)
+	case ast.KindClassDeclaration:
+		n := node.AsClassDeclaration()
+		return factory.UpdateClassDeclaration(n, modifiers, n.Name(), n.TypeParameters, n.HeritageClauses, n.Members)
+	case ast.KindInterfaceDeclaration:
+		n := node.AsInterfaceDeclaration()
+		return factory.UpdateInterfaceDeclaration(n, modifiers, n.Name(), n.TypeParameters, n.HeritageClauses, n.Members)
+	case ast.KindTypeAliasDeclaration:
+		n := node.AsTypeAliasDeclaration()
+		return factory.UpdateTypeAliasDeclaration(n, modifiers, n.Name(), n.TypeParameters, n.Type)
+	case ast.KindEnumDeclaration:
+		n := node.AsEnumDeclaration()
+		return factory.UpdateEnumDeclaration(n, modifiers, n.Name(), n.Members)
+	case ast.KindModuleDeclaration:
+		n := node.AsModuleDeclaration()
+		return factory.UpdateModuleDeclaration(n, modifiers, n.Keyword, n.Name(), n.Body)
+	}
+	return node
+}
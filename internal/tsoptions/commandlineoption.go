@@ -65,6 +65,10 @@ type CommandLineOption struct {
 	ElementOptions map[string]*CommandLineOption
 }
 
+func (o *CommandLineOption) IsFilePath() bool {
+	return o.isFilePath
+}
+
 func (o *CommandLineOption) DeprecatedKeys() *core.Set[string] {
 	if o.Kind != CommandLineOptionTypeEnum {
 		return nil
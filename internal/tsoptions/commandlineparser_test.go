@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/microsoft/typescript-go/internal/ast"
 	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
 	"github.com/microsoft/typescript-go/internal/core"
 	"github.com/microsoft/typescript-go/internal/diagnosticwriter"
@@ -14,6 +15,7 @@ import (
 	"github.com/microsoft/typescript-go/internal/testutil/baseline"
 	"github.com/microsoft/typescript-go/internal/testutil/filefixture"
 	"github.com/microsoft/typescript-go/internal/tsoptions"
+	"github.com/microsoft/typescript-go/internal/tsoptions/tsoptionstest"
 	"github.com/microsoft/typescript-go/internal/vfs/osvfs"
 	"gotest.tools/v3/assert"
 )
@@ -317,3 +319,40 @@ func TestAffectsBuildInfo(t *testing.T) {
 		}
 	})
 }
+
+func TestParseBuildCommandLine(t *testing.T) {
+	t.Parallel()
+	host := tsoptionstest.NewVFSParseConfigHost(nil, "/home/src/workspaces/solution")
+
+	t.Run("defaults to the current directory", func(t *testing.T) {
+		t.Parallel()
+		parsed := tsoptions.ParseBuildCommandLine([]string{"--verbose"}, host)
+		assert.Equal(t, len(parsed.Errors), 0)
+		assert.DeepEqual(t, parsed.Projects, []string{"."})
+		assert.Equal(t, parsed.BuildOptions.Verbose, core.TSTrue)
+	})
+
+	t.Run("parses build options, compiler options and projects", func(t *testing.T) {
+		t.Parallel()
+		parsed := tsoptions.ParseBuildCommandLine([]string{"-d", "--force", "--stopBuildOnErrors", "--declaration", "app", "shared"}, host)
+		assert.Equal(t, len(parsed.Errors), 0)
+		assert.DeepEqual(t, parsed.Projects, []string{"app", "shared"})
+		assert.Equal(t, parsed.BuildOptions.Dry, core.TSTrue)
+		assert.Equal(t, parsed.BuildOptions.Force, core.TSTrue)
+		assert.Equal(t, parsed.BuildOptions.StopBuildOnErrors, core.TSTrue)
+		assert.Equal(t, parsed.CompilerOptions.Declaration, core.TSTrue)
+	})
+
+	t.Run("reports options that cannot be combined", func(t *testing.T) {
+		t.Parallel()
+		parsed := tsoptions.ParseBuildCommandLine([]string{"--clean", "--force", "--verbose"}, host)
+		codes := core.Map(parsed.Errors, func(d *ast.Diagnostic) int32 { return d.Code() })
+		assert.DeepEqual(t, codes, []int32{diagnostics.Options_0_and_1_cannot_be_combined.Code(), diagnostics.Options_0_and_1_cannot_be_combined.Code()})
+	})
+
+	t.Run("reports compiler only options", func(t *testing.T) {
+		t.Parallel()
+		parsed := tsoptions.ParseBuildCommandLine([]string{"--strict"}, host)
+		assert.Equal(t, len(parsed.Errors), 1)
+	})
+}
@@ -58,4 +58,10 @@ var optionsForBuild = []*CommandLineOption{
 		Kind:                    "boolean",
 		DefaultValueDescription: false,
 	},
+	{
+		// Not shown in the help, since it only exists to debug the compiler
+		Name:                    "singleThreaded",
+		Kind:                    "boolean",
+		DefaultValueDescription: false,
+	},
 }
@@ -7,10 +7,10 @@ import (
 	"github.com/microsoft/typescript-go/internal/core"
 )
 
-var OptionsDeclarations = slices.Concat(commonOptionsWithBuild, optionsForCompiler)
+var OptionsDeclarations = slices.Concat(optionsForCompiler, commonOptionsWithBuild)
 
-var optionsForCompiler = []*CommandLineOption{
-	//******* commandOptionsWithoutBuild *******
+var commonOptionsWithBuild = []*CommandLineOption{
+	//******* commonOptionsWithBuild *******
 	{
 		Name:                     "help",
 		ShortName:                "h",
@@ -212,7 +212,7 @@ var optionsForCompiler = []*CommandLineOption{
 	},
 }
 
-var commonOptionsWithBuild = []*CommandLineOption{
+var optionsForCompiler = []*CommandLineOption{
 	//******* commandOptionsWithoutBuild *******
 
 	// CommandLine only options
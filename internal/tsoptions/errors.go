@@ -67,3 +67,21 @@ func createDiagnosticForNodeInSourceFileOrCompilerDiagnostic(sourceFile *ast.Sou
 	}
 	return ast.NewCompilerDiagnostic(message, args...)
 }
+
+// CreateDiagnosticForReference reports a diagnostic on the element at index in the "references" array of the config file.
+func CreateDiagnosticForReference(configFile *TsConfigSourceFile, index int, message *diagnostics.Message, args ...any) *ast.Diagnostic {
+	var sourceFile *ast.SourceFile
+	if configFile != nil {
+		sourceFile = configFile.SourceFile
+	}
+	referencesSyntax := forEachTsConfigPropArray(sourceFile, "references", func(property *ast.PropertyAssignment) *ast.ArrayLiteralExpression {
+		if ast.IsArrayLiteralExpression(property.Initializer) {
+			return property.Initializer.AsArrayLiteralExpression()
+		}
+		return nil
+	})
+	if referencesSyntax != nil && len(referencesSyntax.Elements.Nodes) > index {
+		return createDiagnosticForNodeInSourceFileOrCompilerDiagnostic(sourceFile, referencesSyntax.Elements.Nodes[index], message, args...)
+	}
+	return ast.NewCompilerDiagnostic(message, args...)
+}
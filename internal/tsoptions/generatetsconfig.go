@@ -0,0 +1,206 @@
+package tsoptions
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/microsoft/typescript-go/internal/collections"
+	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/tspath"
+)
+
+// defaultInitCompilerOptions are the options set by the config file written by --init, unless the command line
+// sets them to something else.
+var defaultInitCompilerOptions = &core.CompilerOptions{
+	ModuleKind:                       core.ModuleKindCommonJS,
+	Target:                           core.ScriptTargetES2016,
+	Strict:                           core.TSTrue,
+	ESModuleInterop:                  core.TSTrue,
+	ForceConsistentCasingInFileNames: core.TSTrue,
+	SkipLibCheck:                     core.TSTrue,
+}
+
+// GenerateTSConfig returns the text of the config file written by --init: every option of the common categories
+// with its description, commented out unless it is set by the command line or by default.
+func GenerateTSConfig(options *core.CompilerOptions, fileNames []string, configFileName string, comparePathsOptions tspath.ComparePathsOptions, newLine string) string {
+	compilerOptionsMap := getSerializedCompilerOption(options, configFileName, comparePathsOptions)
+
+	// Skip options which do not have a category or have categories which are more niche
+	categoriesToSkip := []*diagnostics.Message{
+		diagnostics.Command_line_Options,
+		diagnostics.Editor_Support,
+		diagnostics.Compiler_Diagnostics,
+		diagnostics.Backwards_Compatibility,
+		diagnostics.Watch_and_Build_Modes,
+		diagnostics.Output_Formatting,
+	}
+	isAllowedOptionForOutput := func(option *CommandLineOption) bool {
+		return !option.IsCommandLineOnly && option.Category != nil && (!slices.Contains(categoriesToSkip, option.Category) || compilerOptionsMap.Has(option.Name))
+	}
+
+	// Set allowed categories in order
+	categorizedOptions := collections.NewOrderedMapFromList([]collections.MapEntry[*diagnostics.Message, []*CommandLineOption]{
+		{Key: diagnostics.Projects},
+		{Key: diagnostics.Language_and_Environment},
+		{Key: diagnostics.Modules},
+		{Key: diagnostics.JavaScript_Support},
+		{Key: diagnostics.Emit},
+		{Key: diagnostics.Interop_Constraints},
+		{Key: diagnostics.Type_Checking},
+		{Key: diagnostics.Completeness},
+	})
+	for _, option := range OptionsDeclarations {
+		if isAllowedOptionForOutput(option) {
+			categorizedOptions.Set(option.Category, append(categorizedOptions.GetOrZero(option.Category), option))
+		}
+	}
+
+	// Serialize all options and their descriptions
+	type entry struct {
+		value       string
+		description string
+	}
+	var entries []entry
+	marginLength := 0
+	seenKnownKeys := 0
+	for category, options := range categorizedOptions.Entries() {
+		if len(entries) != 0 {
+			entries = append(entries, entry{})
+		}
+		entries = append(entries, entry{value: "/* " + category.Format() + " */"})
+		for _, option := range options {
+			var optionName string
+			if value, ok := compilerOptionsMap.Get(option.Name); ok {
+				seenKnownKeys++
+				optionName = fmt.Sprintf(`"%s": %s%s`, option.Name, stringifyOptionValue(value), core.IfElse(seenKnownKeys == compilerOptionsMap.Size(), "", ","))
+			} else {
+				optionName = fmt.Sprintf(`// "%s": %s,`, option.Name, stringifyOptionValue(getDefaultValueForOption(option)))
+			}
+			description := option.Name
+			if option.Description != nil {
+				description = option.Description.Format()
+			}
+			entries = append(entries, entry{value: optionName, description: "/* " + description + " */"})
+			marginLength = max(len(optionName), marginLength)
+		}
+	}
+
+	// Write the output
+	tab := "  "
+	var result []string
+	result = append(result, "{")
+	result = append(result, tab+`"compilerOptions": {`)
+	result = append(result, tab+tab+"/* "+diagnostics.Visit_https_Colon_Slash_Slashaka_ms_Slashtsconfig_to_read_more_about_this_file.Format()+" */")
+	result = append(result, "")
+	// Print out each row, aligning all the descriptions on the same column.
+	for _, entry := range entries {
+		if entry.value == "" {
+			result = append(result, "")
+		} else if entry.description == "" {
+			result = append(result, tab+tab+entry.value)
+		} else {
+			result = append(result, tab+tab+entry.value+strings.Repeat(" ", marginLength-len(entry.value)+2)+entry.description)
+		}
+	}
+	if len(fileNames) != 0 {
+		result = append(result, tab+"},")
+		result = append(result, tab+`"files": [`)
+		for i, fileName := range fileNames {
+			result = append(result, tab+tab+stringifyOptionValue(fileName)+core.IfElse(i == len(fileNames)-1, "", ","))
+		}
+		result = append(result, tab+"]")
+	} else {
+		result = append(result, tab+"}")
+	}
+	result = append(result, "}")
+
+	return strings.Join(result, newLine) + newLine
+}
+
+// GetCompilerOptionsDiffValue lists the options of the config file written by --init that are not set to the value
+// the generated config file suggests for them, one per line.
+func GetCompilerOptionsDiffValue(options *core.CompilerOptions, configFileName string, comparePathsOptions tspath.ComparePathsOptions, newLine string) string {
+	compilerOptionsMap := getSerializedCompilerOption(options, configFileName, comparePathsOptions)
+	defaultInitOptionValues := getOptionValues(defaultInitCompilerOptions)
+	tab := "  "
+	var result []string
+	for _, option := range OptionsDeclarations {
+		newValue, ok := compilerOptionsMap.Get(option.Name)
+		if !ok {
+			continue
+		}
+		defaultValue := getDefaultValueForOption(option)
+		if !isSameOptionValue(newValue, defaultValue) {
+			result = append(result, tab+option.Name+": "+formatOptionValue(newValue))
+		} else if _, ok := defaultInitOptionValues[option.Name]; ok {
+			result = append(result, tab+option.Name+": "+formatOptionValue(defaultValue))
+		}
+	}
+	return strings.Join(result, newLine) + newLine
+}
+
+// getSerializedCompilerOption returns the options set by the command line along with the default options of
+// --init, with file paths made relative to the config file that is written.
+func getSerializedCompilerOption(options *core.CompilerOptions, configFileName string, comparePathsOptions tspath.ComparePathsOptions) *collections.OrderedMap[string, any] {
+	compilerOptions := *options
+	target := reflect.ValueOf(&compilerOptions).Elem()
+	defaults := reflect.ValueOf(defaultInitCompilerOptions).Elem()
+	for i := range defaults.NumField() {
+		if target.Field(i).IsZero() {
+			target.Field(i).Set(defaults.Field(i))
+		}
+	}
+	return serializeOptions(&compilerOptions, OptionsDeclarations, configFileName, comparePathsOptions)
+}
+
+// getDefaultValueForOption returns the value shown for an option that is commented out in the config file written
+// by --init.
+func getDefaultValueForOption(option *CommandLineOption) any {
+	switch option.Kind {
+	case CommandLineOptionTypeNumber:
+		return 1
+	case CommandLineOptionTypeBoolean:
+		return true
+	case CommandLineOptionTypeString:
+		if option.isFilePath {
+			defaultValue, _ := option.DefaultValueDescription.(string)
+			return "./" + defaultValue
+		}
+		return ""
+	case CommandLineOptionTypeList:
+		return []any{}
+	case CommandLineOptionTypeListOrElement:
+		return getDefaultValueForOption(option.Elements())
+	case CommandLineOptionTypeObject:
+		return struct{}{}
+	case CommandLineOptionTypeEnum:
+		for name := range option.EnumMap().Keys() {
+			return name
+		}
+	}
+	panic("Expected option to have a default value: " + option.Name)
+}
+
+func stringifyOptionValue(value any) string {
+	return core.Must(core.StringifyJson(value, "", ""))
+}
+
+// isSameOptionValue compares option values the way JavaScript compares them, where lists and objects are never equal.
+func isSameOptionValue(a any, b any) bool {
+	return reflect.TypeOf(a).Comparable() && reflect.TypeOf(b).Comparable() && a == b
+}
+
+// formatOptionValue converts an option value to text the way JavaScript converts it to a string.
+func formatOptionValue(value any) string {
+	switch value := value.(type) {
+	case []string:
+		return strings.Join(value, ",")
+	case []any:
+		return strings.Join(core.Map(value, func(element any) string { return fmt.Sprint(element) }), ",")
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,14 @@
+package tsoptions
+
+import (
+	"github.com/microsoft/typescript-go/internal/ast"
+	"github.com/microsoft/typescript-go/internal/core"
+)
+
+type ParsedBuildCommandLine struct {
+	BuildOptions    *core.BuildOptions    `json:"buildOptions"`
+	CompilerOptions *core.CompilerOptions `json:"compilerOptions"`
+	WatchOptions    *core.WatchOptions    `json:"watchOptions"`
+	Projects        []string              `json:"projects"`
+	Errors          []*ast.Diagnostic     `json:"errors"`
+}
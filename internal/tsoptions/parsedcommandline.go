@@ -5,19 +5,30 @@ import (
 
 	"github.com/microsoft/typescript-go/internal/ast"
 	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/tspath"
 )
 
 type ParsedCommandLine struct {
 	ParsedConfig *core.ParsedOptions `json:"parsedConfig"`
 
-	ConfigFile *TsConfigSourceFile `json:"configFile"` // TsConfigSourceFile, used in Program and ExecuteCommandLine
-	Errors     []*ast.Diagnostic   `json:"errors"`
-	Raw        any                 `json:"raw"`
-	// WildcardDirectories map[string]watchDirectoryFlags
-	CompileOnSave *bool `json:"compileOnSave"`
+	ConfigFile    *TsConfigSourceFile `json:"configFile"` // TsConfigSourceFile, used in Program and ExecuteCommandLine
+	Errors        []*ast.Diagnostic   `json:"errors"`
+	Raw           any                 `json:"raw"`
+	CompileOnSave *bool               `json:"compileOnSave"`
 	// TypeAquisition *core.TypeAcquisition
 }
 
+// WildcardDirectories returns the directories that files matched by the include specs of the config file may be
+// added to, mapped to whether files may be added to their subdirectories too.
+func (p *ParsedCommandLine) WildcardDirectories(useCaseSensitiveFileNames bool) map[string]bool {
+	if p.ConfigFile == nil || p.ConfigFile.configFileSpecs == nil {
+		return nil
+	}
+	specs := p.ConfigFile.configFileSpecs
+	basePath := tspath.GetDirectoryPath(p.ConfigFile.SourceFile.FileName())
+	return getWildcardDirectories(specs.validatedIncludeSpecs, specs.validatedExcludeSpecs, basePath, useCaseSensitiveFileNames)
+}
+
 func (p *ParsedCommandLine) SetParsedOptions(o *core.ParsedOptions) {
 	p.ParsedConfig = o
 }
@@ -38,6 +49,14 @@ func (p *ParsedCommandLine) ProjectReferences() []core.ProjectReference {
 	return p.ParsedConfig.ProjectReferences
 }
 
+// ExtendedSourceFiles returns the names of the config files this config extends, directly or transitively.
+func (p *ParsedCommandLine) ExtendedSourceFiles() []string {
+	if p.ConfigFile != nil {
+		return p.ConfigFile.extendedSourceFiles
+	}
+	return nil
+}
+
 func (p *ParsedCommandLine) GetConfigFileParsingDiagnostics() []*ast.Diagnostic {
 	if p.ConfigFile != nil {
 		// todo: !!! should be ConfigFile.ParseDiagnostics, check if they are the same
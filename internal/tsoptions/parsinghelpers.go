@@ -133,6 +133,14 @@ func (o *watchOptionsParser) ParseOption(key string, value any) []*ast.Diagnosti
 	return ParseWatchOptions(key, value, o.WatchOptions)
 }
 
+type buildOptionsParser struct {
+	*core.BuildOptions
+}
+
+func (o *buildOptionsParser) ParseOption(key string, value any) []*ast.Diagnostic {
+	return ParseBuildOptions(key, value, o.BuildOptions)
+}
+
 func ParseCompilerOptions(key string, value any, allOptions *core.CompilerOptions) []*ast.Diagnostic {
 	if value == nil {
 		return nil
@@ -187,6 +195,8 @@ func ParseCompilerOptions(key string, value any, allOptions *core.CompilerOption
 		allOptions.Declaration = parseTristate(value)
 	case "extendedDiagnostics":
 		allOptions.ExtendedDiagnostics = parseTristate(value)
+	case "emitDeclarationOnly":
+		allOptions.EmitDeclarationOnly = parseTristate(value)
 	case "emitDecoratorMetadata":
 		allOptions.EmitDecoratorMetadata = parseTristate(value)
 	case "emitBOM":
@@ -416,6 +426,27 @@ func ParseWatchOptions(key string, value any, allOptions *core.WatchOptions) []*
 	return nil
 }
 
+func ParseBuildOptions(key string, value any, allOptions *core.BuildOptions) []*ast.Diagnostic {
+	if allOptions == nil {
+		return nil
+	}
+	switch key {
+	case "dry":
+		allOptions.Dry = parseTristate(value)
+	case "force":
+		allOptions.Force = parseTristate(value)
+	case "verbose":
+		allOptions.Verbose = parseTristate(value)
+	case "clean":
+		allOptions.Clean = parseTristate(value)
+	case "stopBuildOnErrors":
+		allOptions.StopBuildOnErrors = parseTristate(value)
+	case "singleThreaded":
+		allOptions.SingleThreaded = parseTristate(value)
+	}
+	return nil
+}
+
 // mergeCompilerOptions merges the source compiler options into the target compiler options.
 // Fields in the source options will overwrite the corresponding fields in the target options.
 func mergeCompilerOptions(targetOptions, sourceOptions *core.CompilerOptions) *core.CompilerOptions {
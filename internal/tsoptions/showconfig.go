@@ -0,0 +1,213 @@
+package tsoptions
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/microsoft/typescript-go/internal/collections"
+	"github.com/microsoft/typescript-go/internal/compiler/diagnostics"
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/tspath"
+)
+
+// ConvertToTSConfig returns the config printed by --showConfig for a parsed command line or config file: the options
+// inherited through "extends" are merged in, file paths are made relative to the config file, and the files of the
+// project are listed explicitly.
+func ConvertToTSConfig(configParseResult *ParsedCommandLine, configFileName string, comparePathsOptions tspath.ComparePathsOptions) *collections.OrderedMap[string, any] {
+	configFilePath := tspath.GetNormalizedAbsolutePath(configFileName, comparePathsOptions.CurrentDirectory)
+	compilerOptions := serializeOptions(configParseResult.CompilerOptions(), OptionsDeclarations, configFilePath, comparePathsOptions)
+	compilerOptions.Delete("listFiles")
+	compilerOptions.Delete("listEmittedFiles")
+	for name, value := range getImpliedCompilerOptions(configParseResult.CompilerOptions(), compilerOptions).Entries() {
+		compilerOptions.Set(name, serializeOptionValue(commandLineCompilerOptionsMap[name], value, configFilePath, comparePathsOptions))
+	}
+	if paths := configParseResult.CompilerOptions().Paths; paths != nil {
+		// Substitutions are resolved against baseUrl when it is set, and against the config directory otherwise
+		baseDirectory := configParseResult.CompilerOptions().BaseUrl
+		if baseDirectory == "" {
+			baseDirectory = tspath.GetDirectoryPath(configFilePath)
+		}
+		relativePaths := &collections.OrderedMap[string, []string]{}
+		for pattern, substitutions := range paths.Entries() {
+			relativePaths.Set(pattern, core.Map(substitutions, func(substitution string) string {
+				return relativeSpec(substitution, baseDirectory, comparePathsOptions)
+			}))
+		}
+		compilerOptions.Set("paths", relativePaths)
+	}
+
+	config := &collections.OrderedMap[string, any]{}
+	config.Set("compilerOptions", compilerOptions)
+	if configParseResult.ParsedConfig.WatchOptions != nil {
+		if watchOptions := serializeOptions(configParseResult.ParsedConfig.WatchOptions, optionsForWatch, "" /*configFilePath*/, comparePathsOptions); watchOptions.Size() != 0 {
+			config.Set("watchOptions", watchOptions)
+		}
+	}
+	if references := configParseResult.ProjectReferences(); len(references) != 0 {
+		config.Set("references", core.Map(references, func(reference core.ProjectReference) *collections.OrderedMap[string, any] {
+			result := &collections.OrderedMap[string, any]{}
+			result.Set("path", reference.OriginalPath)
+			if reference.Circular {
+				result.Set("circular", true)
+			}
+			return result
+		}))
+	}
+	if fileNames := configParseResult.FileNames(); len(fileNames) != 0 {
+		config.Set("files", core.Map(fileNames, func(fileName string) string {
+			return tspath.GetRelativePathFromFile(configFilePath, tspath.GetNormalizedAbsolutePath(fileName, comparePathsOptions.CurrentDirectory), comparePathsOptions)
+		}))
+	}
+	if configParseResult.ConfigFile != nil && configParseResult.ConfigFile.configFileSpecs != nil {
+		specs := configParseResult.ConfigFile.configFileSpecs
+		// Specs made absolute by ${configDir} substitution or taken from outDir are printed relative to the config
+		relativeSpecs := func(specs []string) []string {
+			return core.Map(specs, func(spec string) string {
+				return relativeSpec(spec, tspath.GetDirectoryPath(configFilePath), comparePathsOptions)
+			})
+		}
+		if specs.validatedIncludeSpecs != nil && !(len(specs.validatedIncludeSpecs) == 1 && specs.validatedIncludeSpecs[0] == defaultIncludeSpec) {
+			config.Set("include", relativeSpecs(specs.validatedIncludeSpecs))
+		}
+		if specs.validatedExcludeSpecs != nil {
+			config.Set("exclude", relativeSpecs(specs.validatedExcludeSpecs))
+		}
+	}
+	if configParseResult.CompileOnSave != nil && *configParseResult.CompileOnSave {
+		config.Set("compileOnSave", true)
+	}
+	return config
+}
+
+// relativeSpec returns an absolute path or pattern relative to the given directory, leaving relative ones unchanged.
+func relativeSpec(spec string, directory string, comparePathsOptions tspath.ComparePathsOptions) string {
+	if !tspath.PathIsAbsolute(spec) {
+		return spec
+	}
+	return tspath.EnsurePathIsNonModuleName(tspath.GetRelativePathFromDirectory(directory, tspath.NormalizePath(spec), comparePathsOptions))
+}
+
+// serializeOptions returns the options that are set, in declaration order, with enum values replaced by their
+// names. When configFilePath is given, file paths are made relative to the config file.
+func serializeOptions(options any, declarations []*CommandLineOption, configFilePath string, comparePathsOptions tspath.ComparePathsOptions) *collections.OrderedMap[string, any] {
+	values := getOptionValues(options)
+	result := &collections.OrderedMap[string, any]{}
+	for _, option := range declarations {
+		if option.Category == diagnostics.Command_line_Options || option.Category == diagnostics.Output_Formatting || result.Has(option.Name) {
+			continue
+		}
+		if value, ok := values[option.Name]; ok {
+			result.Set(option.Name, serializeOptionValue(option, value, configFilePath, comparePathsOptions))
+		}
+	}
+	return result
+}
+
+// getOptionValues returns the values of the fields of an options struct that are set, keyed by their JSON names.
+func getOptionValues(options any) map[string]any {
+	v := reflect.ValueOf(options).Elem()
+	t := v.Type()
+	values := make(map[string]any, t.NumField())
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		field := v.Field(i)
+		if name == "" || field.IsZero() {
+			continue
+		}
+		switch value := field.Interface().(type) {
+		case core.Tristate:
+			values[name] = value.IsTrue()
+		case *int:
+			values[name] = *value
+		default:
+			values[name] = value
+		}
+	}
+	return values
+}
+
+func serializeOptionValue(option *CommandLineOption, value any, configFilePath string, comparePathsOptions tspath.ComparePathsOptions) any {
+	if enumMap := option.EnumMap(); enumMap != nil {
+		return getNameOfEnumValue(value, enumMap)
+	}
+	relativePath := func(fileName string) string {
+		return tspath.GetRelativePathFromFile(configFilePath, tspath.GetNormalizedAbsolutePath(fileName, tspath.GetDirectoryPath(configFilePath)), comparePathsOptions)
+	}
+	if element := option.Elements(); element != nil {
+		values, ok := value.([]string)
+		if !ok {
+			return value
+		}
+		if enumMap := element.EnumMap(); enumMap != nil {
+			return core.Map(values, func(value string) any { return getNameOfEnumValue(value, enumMap) })
+		}
+		if element.isFilePath && configFilePath != "" {
+			return core.Map(values, relativePath)
+		}
+		return value
+	}
+	if fileName, ok := value.(string); ok && option.isFilePath && configFilePath != "" {
+		return relativePath(fileName)
+	}
+	return value
+}
+
+// getNameOfEnumValue returns the first name an enum option accepts for a value.
+func getNameOfEnumValue(value any, enumMap *collections.OrderedMap[string, any]) any {
+	for name, enumValue := range enumMap.Entries() {
+		if enumValue == value {
+			return name
+		}
+	}
+	return value
+}
+
+type computedOption struct {
+	name string
+	// The options the value is computed from; the computed value is only shown when one of them is set
+	dependencies []string
+	computeValue func(options *core.CompilerOptions) any
+}
+
+var computedOptions = append([]computedOption{
+	{"target", []string{"module"}, func(options *core.CompilerOptions) any { return options.GetEmitScriptTarget() }},
+	{"module", []string{"target"}, func(options *core.CompilerOptions) any { return options.GetEmitModuleKind() }},
+	{"moduleResolution", []string{"module", "target"}, func(options *core.CompilerOptions) any { return options.GetModuleResolutionKind() }},
+	{"isolatedModules", []string{"verbatimModuleSyntax"}, func(options *core.CompilerOptions) any { return options.GetIsolatedModules() }},
+	{"esModuleInterop", []string{"module", "target"}, func(options *core.CompilerOptions) any { return options.GetESModuleInterop() }},
+	{"allowSyntheticDefaultImports", []string{"module", "target", "moduleResolution"}, func(options *core.CompilerOptions) any {
+		return options.GetAllowSyntheticDefaultImports()
+	}},
+	{"resolveJsonModule", []string{"moduleResolution", "module", "target"}, func(options *core.CompilerOptions) any { return options.GetResolveJsonModule() }},
+	{"declaration", []string{"composite"}, func(options *core.CompilerOptions) any { return options.GetEmitDeclarations() }},
+	{"incremental", []string{"composite"}, func(options *core.CompilerOptions) any { return options.IsIncremental() }},
+	{"declarationMap", []string{"declaration", "composite"}, func(options *core.CompilerOptions) any { return options.GetAreDeclarationMapsEnabled() }},
+	{"allowJs", []string{"checkJs"}, func(options *core.CompilerOptions) any { return options.GetAllowJS() }},
+	{"useDefineForClassFields", []string{"target", "module"}, func(options *core.CompilerOptions) any { return options.GetEmitStandardClassFields() }},
+	{"preserveConstEnums", []string{"isolatedModules", "verbatimModuleSyntax"}, func(options *core.CompilerOptions) any {
+		return options.PreserveConstEnums.IsTrue() || options.GetIsolatedModules()
+	}},
+}, core.Map(core.Filter(OptionsDeclarations, func(option *CommandLineOption) bool { return option.strictFlag }), func(option *CommandLineOption) computedOption {
+	return computedOption{option.Name, []string{"strict"}, func(options *core.CompilerOptions) any {
+		if value, ok := getOptionValues(options)[option.Name]; ok {
+			return value
+		}
+		return options.Strict.IsTrue()
+	}}
+})...)
+
+// getImpliedCompilerOptions returns the options that were not set but whose values differ from their defaults
+// because of the options that were.
+func getImpliedCompilerOptions(options *core.CompilerOptions, providedOptions *collections.OrderedMap[string, any]) *collections.OrderedMap[string, any] {
+	defaultOptions := &core.CompilerOptions{}
+	result := &collections.OrderedMap[string, any]{}
+	for _, computed := range computedOptions {
+		if providedOptions.Has(computed.name) || !core.Some(computed.dependencies, providedOptions.Has) {
+			continue
+		}
+		if value := computed.computeValue(options); value != computed.computeValue(defaultOptions) {
+			result.Set(computed.name, value)
+		}
+	}
+	return result
+}
@@ -517,7 +517,8 @@ func convertOptionsFromJson[O optionParser](optionsNameMap map[string]*CommandLi
 
 		commandLineOptionEnumMapVal := opt.EnumMap()
 		if commandLineOptionEnumMapVal != nil {
-			val, ok := commandLineOptionEnumMapVal.Get(strings.ToLower(value.(string)))
+			str, _ := value.(string)
+			val, ok := commandLineOptionEnumMapVal.Get(strings.ToLower(str))
 			if ok {
 				errors = result.ParseOption(key, val)
 			}
@@ -758,6 +759,12 @@ func getDefaultCompilerOptions(configFileName string) *core.CompilerOptions {
 	return options
 }
 
+// ConvertCompilerOptionsFromJson converts the compilerOptions of a tsconfig.json, relative to
+// basePath, to compiler options.
+func ConvertCompilerOptionsFromJson(jsonOptions *collections.OrderedMap[string, any], basePath string) (*core.CompilerOptions, []*ast.Diagnostic) {
+	return convertCompilerOptionsFromJsonWorker(jsonOptions, basePath, "" /*configFileName*/)
+}
+
 func convertCompilerOptionsFromJsonWorker(jsonOptions any, basePath string, configFileName string) (*core.CompilerOptions, []*ast.Diagnostic) {
 	options := getDefaultCompilerOptions(configFileName)
 	_, errors := convertOptionsFromJson(commandLineCompilerOptionsMap, jsonOptions, basePath, &compilerOptionsParser{options})
@@ -1396,7 +1403,7 @@ func hasFileWithHigherPriorityExtension(file string, literalFiles collections.Or
 
 // Removes files included via wildcard expansion with a lower extension priority that have already been included.
 // file is the path to the file.
-func removeWildcardFilesWithLowerPriorityExtension(file string, wildcardFiles collections.OrderedMap[string, string], extensions [][]string, keyMapper func(value string) string) {
+func removeWildcardFilesWithLowerPriorityExtension(file string, wildcardFiles *collections.OrderedMap[string, string], extensions [][]string, keyMapper func(value string) string) {
 	var extensionGroup []string
 	for _, group := range extensions {
 		if tspath.FileExtensionIsOneOf(file, group) {
@@ -1497,7 +1504,7 @@ func getFileNamesFromConfigSpecs(
 			// extension due to the user-defined order of entries in the
 			// "include" array. If there is a lower priority extension in the
 			// same directory, we should remove it.
-			removeWildcardFilesWithLowerPriorityExtension(file, wildcardFileMap, supportedExtensions, keyMappper)
+			removeWildcardFilesWithLowerPriorityExtension(file, &wildcardFileMap, supportedExtensions, keyMappper)
 			key := keyMappper(file)
 			if !literalFileMap.Has(key) && !wildcardFileMap.Has(key) {
 				wildcardFileMap.Set(key, file)
@@ -1555,3 +1562,33 @@ func GetSupportedExtensionsWithJsonIfResolveJsonModule(options *core.CompilerOpt
 	}
 	return slices.Concat(supportedExtensions, [][]string{{tspath.ExtensionJson}})
 }
+
+// Reads the config file and reports errors.
+func GetParsedCommandLineOfConfigFile(
+	configFileName string,
+	options *core.CompilerOptions,
+	host ParseConfigHost,
+	extendedConfigCache map[tspath.Path]*ExtendedConfigCacheEntry,
+) (*ParsedCommandLine, []*ast.Diagnostic) {
+	errors := []*ast.Diagnostic{}
+	configFileText, errors := TryReadFile(configFileName, host.FS().ReadFile, errors)
+	if len(errors) > 0 {
+		// these are unrecoverable errors--exit to report them as diagnostics
+		return nil, errors
+	}
+
+	cwd := host.GetCurrentDirectory()
+	tsConfigSourceFile := NewTsconfigSourceFileFromFilePath(configFileName, tspath.ToPath(configFileName, cwd, host.FS().UseCaseSensitiveFileNames()), configFileText)
+	// tsConfigSourceFile.resolvedPath = tsConfigSourceFile.FileName()
+	// tsConfigSourceFile.originalFileName = tsConfigSourceFile.FileName()
+	return ParseJsonSourceFileConfigFileContent(
+		tsConfigSourceFile,
+		host,
+		tspath.GetNormalizedAbsolutePath(tspath.GetDirectoryPath(configFileName), cwd),
+		options,
+		tspath.GetNormalizedAbsolutePath(configFileName, cwd),
+		nil,
+		nil,
+		extendedConfigCache,
+	), nil
+}
@@ -882,3 +882,42 @@ func BenchmarkParseSrcCompiler(b *testing.B) {
 		)
 	}
 }
+
+func TestWildcardDirectories(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		title    string
+		jsonText string
+		expected map[string]bool
+	}{
+		{"default include", `{}`, map[string]bool{"/apath": true}},
+		{"recursive include", `{ "include": ["src/**/*", "src/lib/*.ts", "test"] }`, map[string]bool{"/apath/src": true, "/apath/test": true}},
+		{"non-recursive include", `{ "include": ["src/*.ts", "lib/a.ts"] }`, map[string]bool{"/apath/src": false}},
+		{"excluded include", `{ "include": ["src/**/*", "test/**/*"], "exclude": ["test"] }`, map[string]bool{"/apath/src": true}},
+		{"files", `{ "files": ["a.ts"] }`, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			t.Parallel()
+			configFileName := "/apath/tsconfig.json"
+			host := tsoptionstest.NewVFSParseConfigHost(map[string]string{
+				configFileName:    c.jsonText,
+				"/apath/a.ts":     "",
+				"/apath/src/b.ts": "",
+			}, "/apath")
+			parsed := parser.ParseJSONText(configFileName, tspath.Path(configFileName), c.jsonText)
+			parsedCommandLine := tsoptions.ParseJsonSourceFileConfigFileContent(
+				&tsoptions.TsConfigSourceFile{SourceFile: parsed},
+				host,
+				"/apath",
+				nil,
+				configFileName,
+				/*resolutionStack*/ nil,
+				/*extraFileExtensions*/ nil,
+				/*extendedConfigCache*/ nil,
+			)
+			assert.DeepEqual(t, parsedCommandLine.WildcardDirectories(true /*useCaseSensitiveFileNames*/), c.expected)
+		})
+	}
+}
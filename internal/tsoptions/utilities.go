@@ -239,6 +239,78 @@ func getIncludeBasePath(absolute string) string {
 	return absolute[:strings.LastIndex(absolute, string(tspath.DirectorySeparator))]
 }
 
+// getWildcardDirectories returns the directories that files matched by the include specs may be added to, mapped to
+// whether files may be added to their subdirectories too.
+func getWildcardDirectories(include []string, exclude []string, basePath string, useCaseSensitiveFileNames bool) map[string]bool {
+	if include == nil {
+		return nil
+	}
+	var excludeRegex *regexp2.Regexp
+	if rawExcludeRegex := getRegularExpressionForWildcard(exclude, basePath, usageExclude); rawExcludeRegex != "" {
+		excludeRegex = getRegexFromPattern(rawExcludeRegex, useCaseSensitiveFileNames)
+	}
+	toCanonicalKey := func(path string) string {
+		return core.IfElse(useCaseSensitiveFileNames, path, strings.ToLower(path))
+	}
+
+	wildcardDirectories := make(map[string]bool)
+	keyToPath := make(map[string]string)
+	var recursiveKeys []string
+	for _, file := range include {
+		spec := tspath.NormalizePath(tspath.CombinePaths(basePath, file))
+		if excludeRegex != nil {
+			if excluded, err := excludeRegex.MatchString(spec); err == nil && excluded {
+				continue
+			}
+		}
+		path, recursive, ok := getWildcardDirectoryFromSpec(spec)
+		if !ok {
+			continue
+		}
+		key := toCanonicalKey(path)
+		existingPath, exists := keyToPath[key]
+		if !exists || (recursive && !wildcardDirectories[existingPath]) {
+			if exists {
+				path = existingPath
+			} else {
+				keyToPath[key] = path
+			}
+			wildcardDirectories[path] = recursive
+			if recursive {
+				recursiveKeys = append(recursiveKeys, key)
+			}
+		}
+	}
+
+	// Remove any subpaths under an existing recursively watched directory
+	comparePathsOptions := tspath.ComparePathsOptions{CurrentDirectory: basePath, UseCaseSensitiveFileNames: useCaseSensitiveFileNames}
+	for path := range wildcardDirectories {
+		key := toCanonicalKey(path)
+		for _, recursiveKey := range recursiveKeys {
+			if key != recursiveKey && tspath.ContainsPath(recursiveKey, key, comparePathsOptions) {
+				delete(wildcardDirectories, path)
+			}
+		}
+	}
+	return wildcardDirectories
+}
+
+func getWildcardDirectoryFromSpec(spec string) (path string, recursive bool, ok bool) {
+	lastDirectorySeparatorIndex := strings.LastIndex(spec, "/")
+	if wildcardIndex := strings.IndexAny(spec, string(wildcardCharCodes)); wildcardIndex >= 0 {
+		// The directory is the part of the spec before the last separator preceding the first wildcard
+		separatorIndex := strings.LastIndex(spec[:wildcardIndex], "/")
+		if separatorIndex < 0 {
+			return "", false, false
+		}
+		return spec[:separatorIndex], wildcardIndex < lastDirectorySeparatorIndex, true
+	}
+	if isImplicitGlob(spec[lastDirectorySeparatorIndex+1:]) {
+		return tspath.RemoveTrailingDirectorySeparator(spec), true, true
+	}
+	return "", false, false
+}
+
 // getBasePaths computes the unique non-wildcard base paths amongst the provided include patterns.
 func getBasePaths(path string, includes []string, useCaseSensitiveFileNames bool) []string {
 	// Storage for our results in the form of literal paths (e.g. the paths as written by the user).
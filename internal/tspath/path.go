@@ -623,6 +623,10 @@ func GetRelativePathFromDirectory(fromDirectory string, to string, options Compa
 	return GetPathFromPathComponents(pathComponents)
 }
 
+func GetRelativePathFromFile(from string, to string, options ComparePathsOptions) string {
+	return EnsurePathIsNonModuleName(GetRelativePathFromDirectory(GetDirectoryPath(from), to, options))
+}
+
 func ConvertToRelativePath(absoluteOrRelativePath string, options ComparePathsOptions) string {
 	if !IsRootedDiskPath(absoluteOrRelativePath) {
 		return absoluteOrRelativePath
@@ -760,6 +764,14 @@ func PathIsRelative(path string) bool {
 	return false
 }
 
+// EnsurePathIsNonModuleName prefixes a relative path with "./" so that it is not mistaken for a module name.
+func EnsurePathIsNonModuleName(path string) string {
+	if !PathIsAbsolute(path) && !PathIsRelative(path) {
+		return "./" + path
+	}
+	return path
+}
+
 func IsExternalModuleNameRelative(moduleName string) bool {
 	// TypeScript 1.0 spec (April 2014): 11.2.1
 	// An external module name is "relative" if the first term is "." or "..".
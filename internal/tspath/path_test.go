@@ -513,6 +513,15 @@ func TestGetRelativePathToDirectoryOrUrl(t *testing.T) {
 	assert.Equal(t, GetRelativePathToDirectoryOrUrl("file:///c:", "file:///d:", false /*isAbsolutePathAnUrl*/, ComparePathsOptions{}), "file:///d:/")
 }
 
+func TestGetRelativePathFromFile(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, GetRelativePathFromFile("/a/tsconfig.json", "/a/b.ts", ComparePathsOptions{}), "./b.ts")
+	assert.Equal(t, GetRelativePathFromFile("/a/tsconfig.json", "/a", ComparePathsOptions{}), "./")
+	assert.Equal(t, GetRelativePathFromFile("/a/tsconfig.json", "/a/b/c", ComparePathsOptions{}), "./b/c")
+	assert.Equal(t, GetRelativePathFromFile("/a/b/tsconfig.json", "/a/c.ts", ComparePathsOptions{}), "../c.ts")
+	assert.Equal(t, GetRelativePathFromFile("/a/tsconfig.json", "/A/b.ts", ComparePathsOptions{UseCaseSensitiveFileNames: true}), "../A/b.ts")
+}
+
 func TestToFileNameLowerCase(t *testing.T) {
 	t.Parallel()
 	assert.Equal(t, ToFileNameLowerCase("/user/UserName/projects/Project/file.ts"), "/user/username/projects/project/file.ts")
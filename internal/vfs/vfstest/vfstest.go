@@ -28,6 +28,13 @@ type mapFS struct {
 	useCaseSensitiveFileNames bool
 
 	symlinks map[canonicalPath]canonicalPath
+
+	clock Clock
+}
+
+// Clock provides the modification times of files written to a [vfs.FS] created by [FromMapWithClock].
+type Clock interface {
+	Now() time.Time
 }
 
 var (
@@ -47,6 +54,13 @@ type sys struct {
 // without trailing directory separators.
 // The paths must be all POSIX-style or all Windows-style, but not both.
 func FromMap[File any](m map[string]File, useCaseSensitiveFileNames bool) vfs.FS {
+	return FromMapWithClock(m, useCaseSensitiveFileNames, nil)
+}
+
+// FromMapWithClock is like [FromMap], but files written to the [vfs.FS] get their modification
+// time from the given clock rather than the current time. Files given in the map without a
+// modification time get the clock's current time.
+func FromMapWithClock[File any](m map[string]File, useCaseSensitiveFileNames bool, clock Clock) vfs.FS {
 	posix := false
 	windows := false
 
@@ -92,6 +106,12 @@ func FromMap[File any](m map[string]File, useCaseSensitiveFileNames bool) vfs.FS
 			file = &fileCopy
 		}
 
+		if clock != nil && file.ModTime.IsZero() {
+			fileCopy := *file
+			fileCopy.ModTime = clock.Now()
+			file = &fileCopy
+		}
+
 		p, _ = strings.CutPrefix(p, "/")
 		mfs[p] = file
 	}
@@ -100,7 +120,9 @@ func FromMap[File any](m map[string]File, useCaseSensitiveFileNames bool) vfs.FS
 		panic("mixed posix and windows paths")
 	}
 
-	return iovfs.From(convertMapFS(mfs, useCaseSensitiveFileNames), useCaseSensitiveFileNames)
+	fsys := convertMapFS(mfs, useCaseSensitiveFileNames)
+	fsys.clock = clock
+	return iovfs.From(fsys, useCaseSensitiveFileNames)
 }
 
 func convertMapFS(input fstest.MapFS, useCaseSensitiveFileNames bool) *mapFS {
@@ -482,13 +504,20 @@ func (m *mapFS) WriteFile(path string, data []byte, perm fs.FileMode) error {
 
 	m.setEntry(path, cp, fstest.MapFile{
 		Data:    data,
-		ModTime: time.Now(),
+		ModTime: m.now(),
 		Mode:    perm &^ umask,
 	})
 
 	return nil
 }
 
+func (m *mapFS) now() time.Time {
+	if m.clock != nil {
+		return m.clock.Now()
+	}
+	return time.Now()
+}
+
 func (m *mapFS) Remove(path string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
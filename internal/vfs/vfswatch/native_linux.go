@@ -0,0 +1,404 @@
+package vfswatch
+
+import (
+	"encoding/binary"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/microsoft/typescript-go/internal/tspath"
+	"github.com/microsoft/typescript-go/internal/vfs"
+	"golang.org/x/sys/unix"
+)
+
+const directoryEventMask = unix.IN_CREATE | unix.IN_DELETE | unix.IN_MOVED_FROM | unix.IN_MOVED_TO | unix.IN_MODIFY |
+	unix.IN_ATTRIB | unix.IN_DELETE_SELF | unix.IN_MOVE_SELF | unix.IN_ONLYDIR
+
+// nativeWatcher watches files and directories with inotify. Files are watched through their parent directory, since
+// a watch on the file itself stops working once an editor replaces the file by renaming another file over it.
+type nativeWatcher struct {
+	fs     vfs.FS
+	fd     int
+	file   *os.File
+	events chan<- Event
+	done   <-chan struct{}
+
+	mu           sync.Mutex
+	directories  map[string]*nativeDirectory
+	byDescriptor map[int32]*nativeDirectory
+	// The directories passed to watchDirectory with recursive set
+	recursiveRoots map[string]struct{}
+	// The directories that were deleted while something in them was watched, by path; their watches are restored
+	// once they are created again
+	deleted map[string]*nativeDirectory
+}
+
+type nativeDirectory struct {
+	path       string
+	descriptor int32
+	// The names of the watched files in the directory
+	files map[string]struct{}
+	// The directories passed to watchDirectory whose watch covers this directory; entries being added to or removed
+	// from the directory are reported if there are any
+	roots map[string]struct{}
+	// The names of the deleted subdirectories whose watches are restored once they are created again
+	missing map[string]struct{}
+}
+
+func newNativeWatcher(fs vfs.FS, events chan<- Event, done <-chan struct{}) (*nativeWatcher, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	return &nativeWatcher{
+		fs: fs,
+		fd: fd,
+		// The descriptor is non-blocking, so reads go through the runtime poller and are interrupted by Close
+		file:           os.NewFile(uintptr(fd), "inotify"),
+		events:         events,
+		done:           done,
+		directories:    make(map[string]*nativeDirectory),
+		byDescriptor:   make(map[int32]*nativeDirectory),
+		recursiveRoots: make(map[string]struct{}),
+		deleted:        make(map[string]*nativeDirectory),
+	}, nil
+}
+
+func (w *nativeWatcher) watchFile(fileName string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	directory, err := w.addDirectory(tspath.GetDirectoryPath(fileName))
+	if err != nil {
+		return err
+	}
+	directory.files[tspath.GetBaseFileName(fileName)] = struct{}{}
+	return nil
+}
+
+func (w *nativeWatcher) watchDirectory(directoryName string, recursive bool) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	directory, err := w.addDirectory(directoryName)
+	if err != nil {
+		return err
+	}
+	directory.roots[directoryName] = struct{}{}
+	if recursive {
+		w.recursiveRoots[directoryName] = struct{}{}
+		if err := w.addSubdirectories(directoryName, directoryName, nil); err != nil {
+			w.removeRoot(directoryName)
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *nativeWatcher) addDirectory(path string) (*nativeDirectory, error) {
+	if directory, ok := w.directories[path]; ok {
+		return directory, nil
+	}
+	descriptor, err := unix.InotifyAddWatch(w.fd, path, directoryEventMask)
+	if err != nil {
+		return nil, err
+	}
+	directory := newNativeDirectory(path)
+	directory.descriptor = int32(descriptor)
+	w.directories[path] = directory
+	w.byDescriptor[directory.descriptor] = directory
+	return directory, nil
+}
+
+func newNativeDirectory(path string) *nativeDirectory {
+	return &nativeDirectory{
+		path:    path,
+		files:   make(map[string]struct{}),
+		roots:   make(map[string]struct{}),
+		missing: make(map[string]struct{}),
+	}
+}
+
+func (d *nativeDirectory) isWatched() bool {
+	return len(d.files) != 0 || len(d.roots) != 0 || len(d.missing) != 0
+}
+
+// addSubdirectories watches the subdirectories of path for the recursive watch of root. If created is not nil, the
+// entries found are appended to it, since they were created before the watch could report them.
+func (w *nativeWatcher) addSubdirectories(path string, root string, created *[]Event) error {
+	entries := w.fs.GetAccessibleEntries(path)
+	if created != nil {
+		for _, file := range entries.Files {
+			*created = append(*created, Event{Kind: EventKindCreated, Path: tspath.CombinePaths(path, file)})
+		}
+	}
+	for _, name := range entries.Directories {
+		subdirectoryPath := tspath.CombinePaths(path, name)
+		if isIgnoredPath(subdirectoryPath) {
+			continue
+		}
+		subdirectory, err := w.addDirectory(subdirectoryPath)
+		if err != nil {
+			return err
+		}
+		subdirectory.roots[root] = struct{}{}
+		if created != nil {
+			*created = append(*created, Event{Kind: EventKindCreated, Path: subdirectoryPath})
+		}
+		if err := w.addSubdirectories(subdirectoryPath, root, created); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *nativeWatcher) unwatch(name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if directory, ok := w.directories[tspath.GetDirectoryPath(name)]; ok {
+		delete(directory.files, tspath.GetBaseFileName(name))
+		w.release(directory)
+	}
+	if directory, ok := w.deleted[tspath.GetDirectoryPath(name)]; ok {
+		delete(directory.files, tspath.GetBaseFileName(name))
+		w.releaseDeleted(directory)
+	}
+	w.removeRoot(name)
+}
+
+func (w *nativeWatcher) removeRoot(root string) {
+	delete(w.recursiveRoots, root)
+	for _, directory := range w.directories {
+		if _, ok := directory.roots[root]; ok {
+			delete(directory.roots, root)
+			w.release(directory)
+		}
+	}
+	for _, directory := range w.deleted {
+		if _, ok := directory.roots[root]; ok {
+			delete(directory.roots, root)
+			w.releaseDeleted(directory)
+		}
+	}
+}
+
+// release stops watching the directory once nothing in it is watched any more.
+func (w *nativeWatcher) release(directory *nativeDirectory) {
+	if directory.isWatched() {
+		return
+	}
+	_, _ = unix.InotifyRmWatch(w.fd, uint32(directory.descriptor))
+	w.forget(directory)
+}
+
+// releaseDeleted stops waiting for a deleted directory to be created again once nothing in it is watched any more.
+func (w *nativeWatcher) releaseDeleted(directory *nativeDirectory) {
+	if directory.isWatched() {
+		return
+	}
+	delete(w.deleted, directory.path)
+	parentPath := tspath.GetDirectoryPath(directory.path)
+	if parent, ok := w.directories[parentPath]; ok {
+		delete(parent.missing, tspath.GetBaseFileName(directory.path))
+		w.release(parent)
+	} else if parent, ok := w.deleted[parentPath]; ok {
+		delete(parent.missing, tspath.GetBaseFileName(directory.path))
+		w.releaseDeleted(parent)
+	}
+}
+
+// awaitCreation keeps the watches of a deleted directory, and watches its closest existing ancestor so that they
+// are restored once the directory is created again.
+func (w *nativeWatcher) awaitCreation(directory *nativeDirectory) {
+	w.deleted[directory.path] = directory
+	for path := directory.path; ; {
+		parentPath := tspath.GetDirectoryPath(path)
+		if parentPath == path {
+			return
+		}
+		if parent, ok := w.directories[parentPath]; ok {
+			parent.missing[tspath.GetBaseFileName(path)] = struct{}{}
+			return
+		}
+		if parent, ok := w.deleted[parentPath]; ok {
+			parent.missing[tspath.GetBaseFileName(path)] = struct{}{}
+			return
+		}
+		if parent, err := w.addDirectory(parentPath); err == nil {
+			parent.missing[tspath.GetBaseFileName(path)] = struct{}{}
+			return
+		}
+		// The parent is gone as well, so it is waited for in turn
+		parent := newNativeDirectory(parentPath)
+		parent.missing[tspath.GetBaseFileName(path)] = struct{}{}
+		w.deleted[parentPath] = parent
+		path = parentPath
+	}
+}
+
+// restore watches a directory that was deleted and has been created again, and reports the entries of interest
+// that it was created with, since they were created before the watch could report them.
+func (w *nativeWatcher) restore(path string, created *[]Event) {
+	deleted, ok := w.deleted[path]
+	if !ok {
+		return
+	}
+	directory, err := w.addDirectory(path)
+	if err != nil {
+		// The directory is already gone again
+		w.awaitCreation(deleted)
+		return
+	}
+	delete(w.deleted, path)
+	entries := w.fs.GetAccessibleEntries(path)
+	for file := range deleted.files {
+		directory.files[file] = struct{}{}
+		if slices.Contains(entries.Files, file) {
+			*created = append(*created, Event{Kind: EventKindCreated, Path: tspath.CombinePaths(path, file)})
+		}
+	}
+	for root := range deleted.roots {
+		directory.roots[root] = struct{}{}
+		if _, ok := w.recursiveRoots[root]; ok {
+			_ = w.addSubdirectories(path, root, created)
+			continue
+		}
+		for _, name := range slices.Concat(entries.Files, entries.Directories) {
+			*created = append(*created, Event{Kind: EventKindCreated, Path: tspath.CombinePaths(path, name)})
+		}
+	}
+	for name := range deleted.missing {
+		directory.missing[name] = struct{}{}
+		if slices.Contains(entries.Directories, name) {
+			delete(directory.missing, name)
+			w.restore(tspath.CombinePaths(path, name), created)
+		}
+	}
+}
+
+func (w *nativeWatcher) forget(directory *nativeDirectory) {
+	delete(w.directories, directory.path)
+	delete(w.byDescriptor, directory.descriptor)
+}
+
+// run delivers the notifications of the operating system until the watcher is closed.
+func (w *nativeWatcher) run() {
+	buffer := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	for {
+		n, err := w.file.Read(buffer)
+		if err != nil {
+			return
+		}
+		for _, event := range w.parseEvents(buffer[:n]) {
+			select {
+			case w.events <- event:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+func (w *nativeWatcher) parseEvents(buffer []byte) []Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var events []Event
+	add := func(event Event) {
+		if len(events) == 0 || events[len(events)-1] != event {
+			events = append(events, event)
+		}
+	}
+	for offset := 0; offset+unix.SizeofInotifyEvent <= len(buffer); {
+		descriptor := int32(binary.NativeEndian.Uint32(buffer[offset:]))
+		mask := binary.NativeEndian.Uint32(buffer[offset+4:])
+		nameLength := int(binary.NativeEndian.Uint32(buffer[offset+12:]))
+		name := strings.TrimRight(string(buffer[offset+unix.SizeofInotifyEvent:offset+unix.SizeofInotifyEvent+nameLength]), "\x00")
+		offset += unix.SizeofInotifyEvent + nameLength
+
+		if mask&unix.IN_Q_OVERFLOW != 0 {
+			// Events were lost, so report everything as possibly changed
+			for _, directory := range w.directories {
+				for file := range directory.files {
+					add(Event{Kind: EventKindChanged, Path: tspath.CombinePaths(directory.path, file)})
+				}
+				if len(directory.roots) != 0 {
+					add(Event{Kind: EventKindCreated, Path: directory.path})
+				}
+			}
+			continue
+		}
+
+		directory, ok := w.byDescriptor[descriptor]
+		if !ok {
+			continue
+		}
+		if mask&(unix.IN_DELETE_SELF|unix.IN_MOVE_SELF|unix.IN_IGNORED) != 0 {
+			for file := range directory.files {
+				add(Event{Kind: EventKindDeleted, Path: tspath.CombinePaths(directory.path, file)})
+			}
+			if mask&unix.IN_IGNORED == 0 {
+				_, _ = unix.InotifyRmWatch(w.fd, uint32(directory.descriptor))
+			}
+			w.forget(directory)
+			// The subdirectories of a recursive watch are watched again along with the directory they are in, so
+			// only the watches made for the directory itself are kept
+			deleted := newNativeDirectory(directory.path)
+			maps.Copy(deleted.files, directory.files)
+			maps.Copy(deleted.missing, directory.missing)
+			if _, ok := directory.roots[directory.path]; ok {
+				deleted.roots[directory.path] = struct{}{}
+			}
+			if deleted.isWatched() {
+				w.awaitCreation(deleted)
+			}
+			continue
+		}
+
+		var kind EventKind
+		switch {
+		case mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0:
+			kind = EventKindCreated
+		case mask&(unix.IN_DELETE|unix.IN_MOVED_FROM) != 0:
+			kind = EventKindDeleted
+		case mask&(unix.IN_MODIFY|unix.IN_ATTRIB) != 0:
+			kind = EventKindChanged
+		default:
+			continue
+		}
+		path := tspath.CombinePaths(directory.path, name)
+		if _, ok := directory.files[name]; ok || (len(directory.roots) != 0 && kind != EventKindChanged) {
+			add(Event{Kind: kind, Path: path})
+		}
+		if _, ok := directory.missing[name]; ok && kind == EventKindCreated && mask&unix.IN_ISDIR != 0 {
+			delete(directory.missing, name)
+			var created []Event
+			w.restore(path, &created)
+			for _, event := range created {
+				add(event)
+			}
+			w.release(directory)
+		}
+		if kind == EventKindCreated && mask&unix.IN_ISDIR != 0 && !isIgnoredPath(path) {
+			for root := range directory.roots {
+				if _, ok := w.recursiveRoots[root]; !ok {
+					continue
+				}
+				subdirectory, err := w.addDirectory(path)
+				if err != nil {
+					continue
+				}
+				subdirectory.roots[root] = struct{}{}
+				var created []Event
+				_ = w.addSubdirectories(path, root, &created)
+				for _, event := range created {
+					add(event)
+				}
+			}
+		}
+	}
+	return events
+}
+
+func (w *nativeWatcher) close() error {
+	return w.file.Close()
+}
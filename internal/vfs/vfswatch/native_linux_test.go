@@ -0,0 +1,101 @@
+package vfswatch_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/microsoft/typescript-go/internal/tspath"
+	"github.com/microsoft/typescript-go/internal/vfs/osvfs"
+	"github.com/microsoft/typescript-go/internal/vfs/vfswatch"
+	"gotest.tools/v3/assert"
+)
+
+func TestNativeWatcher(t *testing.T) {
+	t.Parallel()
+
+	root := tspath.NormalizePath(t.TempDir())
+	assert.NilError(t, os.WriteFile(filepath.Join(root, "a.ts"), []byte("export const a = 1;"), 0o644))
+	assert.NilError(t, os.Mkdir(filepath.Join(root, "src"), 0o755))
+
+	w := vfswatch.New(osvfs.FS(), nil)
+	defer w.Close()
+	w.WatchFile(root + "/a.ts")
+	w.WatchDirectory(root, true /*recursive*/)
+	events := w.Events()
+
+	expect := func(expected vfswatch.Event) {
+		t.Helper()
+		timeout := time.After(10 * time.Second)
+		for {
+			select {
+			case event := <-events:
+				if event == expected {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("timed out waiting for %v", expected)
+			}
+		}
+	}
+
+	assert.NilError(t, os.WriteFile(filepath.Join(root, "a.ts"), []byte("export const a = 2;"), 0o644))
+	expect(vfswatch.Event{Kind: vfswatch.EventKindChanged, Path: root + "/a.ts"})
+
+	assert.NilError(t, os.WriteFile(filepath.Join(root, "src", "b.ts"), []byte("export const b = 1;"), 0o644))
+	expect(vfswatch.Event{Kind: vfswatch.EventKindCreated, Path: root + "/src/b.ts"})
+
+	assert.NilError(t, os.MkdirAll(filepath.Join(root, "src", "lib"), 0o755))
+	expect(vfswatch.Event{Kind: vfswatch.EventKindCreated, Path: root + "/src/lib"})
+	assert.NilError(t, os.WriteFile(filepath.Join(root, "src", "lib", "c.ts"), []byte("export const c = 1;"), 0o644))
+	expect(vfswatch.Event{Kind: vfswatch.EventKindCreated, Path: root + "/src/lib/c.ts"})
+
+	assert.NilError(t, os.Remove(filepath.Join(root, "a.ts")))
+	expect(vfswatch.Event{Kind: vfswatch.EventKindDeleted, Path: root + "/a.ts"})
+}
+
+func TestNativeWatcherRecreatedDirectory(t *testing.T) {
+	t.Parallel()
+
+	root := tspath.NormalizePath(t.TempDir())
+	project := root + "/project"
+	assert.NilError(t, os.MkdirAll(filepath.Join(project, "src"), 0o755))
+	assert.NilError(t, os.WriteFile(filepath.Join(project, "tsconfig.json"), []byte("{}"), 0o644))
+
+	w := vfswatch.New(osvfs.FS(), nil)
+	defer w.Close()
+	w.WatchFile(project + "/tsconfig.json")
+	w.WatchDirectory(project+"/src", true /*recursive*/)
+	events := w.Events()
+
+	expect := func(expected vfswatch.Event) {
+		t.Helper()
+		timeout := time.After(10 * time.Second)
+		for {
+			select {
+			case event := <-events:
+				if event == expected {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("timed out waiting for %v", expected)
+			}
+		}
+	}
+
+	assert.NilError(t, os.RemoveAll(project))
+	expect(vfswatch.Event{Kind: vfswatch.EventKindDeleted, Path: project + "/tsconfig.json"})
+
+	// The directories are watched natively again once they are created again, including their contents that were
+	// created before the watches could be restored
+	assert.NilError(t, os.MkdirAll(filepath.Join(project, "src", "lib"), 0o755))
+	assert.NilError(t, os.WriteFile(filepath.Join(project, "tsconfig.json"), []byte("{}"), 0o644))
+	expect(vfswatch.Event{Kind: vfswatch.EventKindCreated, Path: project + "/tsconfig.json"})
+	expect(vfswatch.Event{Kind: vfswatch.EventKindCreated, Path: project + "/src/lib"})
+
+	assert.NilError(t, os.WriteFile(filepath.Join(project, "src", "lib", "a.ts"), []byte("export const a = 1;"), 0o644))
+	expect(vfswatch.Event{Kind: vfswatch.EventKindCreated, Path: project + "/src/lib/a.ts"})
+	assert.NilError(t, os.WriteFile(filepath.Join(project, "tsconfig.json"), []byte(`{ "compilerOptions": {} }`), 0o644))
+	expect(vfswatch.Event{Kind: vfswatch.EventKindChanged, Path: project + "/tsconfig.json"})
+}
@@ -0,0 +1,34 @@
+//go:build !linux
+
+package vfswatch
+
+import (
+	"errors"
+
+	"github.com/microsoft/typescript-go/internal/vfs"
+)
+
+var errNativeWatchingNotSupported = errors.New("vfswatch: native file watching is not supported on this platform")
+
+// nativeWatcher is not implemented on this platform, so files are always polled.
+type nativeWatcher struct{}
+
+func newNativeWatcher(fs vfs.FS, events chan<- Event, done <-chan struct{}) (*nativeWatcher, error) {
+	return nil, errNativeWatchingNotSupported
+}
+
+func (w *nativeWatcher) watchFile(fileName string) error {
+	return errNativeWatchingNotSupported
+}
+
+func (w *nativeWatcher) watchDirectory(directoryName string, recursive bool) error {
+	return errNativeWatchingNotSupported
+}
+
+func (w *nativeWatcher) unwatch(name string) {}
+
+func (w *nativeWatcher) run() {}
+
+func (w *nativeWatcher) close() error {
+	return nil
+}
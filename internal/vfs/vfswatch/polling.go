@@ -0,0 +1,395 @@
+package vfswatch
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/tspath"
+	"github.com/microsoft/typescript-go/internal/vfs"
+)
+
+type pollingStrategy int
+
+const (
+	// Every file is polled at the same interval
+	pollingStrategyFixedInterval pollingStrategy = iota
+	// Files in node_modules, which rarely change, are polled less often than other files
+	pollingStrategyPriorityInterval
+	// Files are polled less often the longer they go without changing
+	pollingStrategyDynamicPriority
+	// A fixed number of files is polled at every interval, taking turns
+	pollingStrategyFixedChunkSize
+)
+
+const (
+	pollingIntervalLow    = 250 * time.Millisecond
+	pollingIntervalMedium = 500 * time.Millisecond
+	pollingIntervalHigh   = 2000 * time.Millisecond
+
+	// The number of files polled at every interval by pollingStrategyFixedChunkSize
+	pollingChunkSize = 32
+)
+
+// fileStrategy returns the strategy files are polled with, which is the fallback strategy if the watch options
+// ask for native notifications.
+func fileStrategy(options *core.WatchOptions) pollingStrategy {
+	switch options.FileKind {
+	case core.WatchFileKindFixedPollingInterval:
+		return pollingStrategyFixedInterval
+	case core.WatchFileKindPriorityPollingInterval:
+		return pollingStrategyPriorityInterval
+	case core.WatchFileKindDynamicPriorityPolling:
+		return pollingStrategyDynamicPriority
+	case core.WatchFileKindFixedChunkSizePolling:
+		return pollingStrategyFixedChunkSize
+	default:
+		return fallbackStrategy(options.FallbackPolling)
+	}
+}
+
+// directoryStrategy returns the strategy directories are polled with, which is the fallback strategy if the watch
+// options ask for native notifications.
+func directoryStrategy(options *core.WatchOptions) pollingStrategy {
+	switch options.DirectoryKind {
+	case core.WatchDirectoryKindFixedPollingInterval:
+		return pollingStrategyFixedInterval
+	case core.WatchDirectoryKindDynamicPriorityPolling:
+		return pollingStrategyDynamicPriority
+	case core.WatchDirectoryKindFixedChunkSizePolling:
+		return pollingStrategyFixedChunkSize
+	default:
+		return fallbackStrategy(options.FallbackPolling)
+	}
+}
+
+func fallbackStrategy(kind core.PollingKind) pollingStrategy {
+	switch kind {
+	case core.PollingKindFixedInterval:
+		return pollingStrategyFixedInterval
+	case core.PollingKindDynamicPriority:
+		return pollingStrategyDynamicPriority
+	case core.PollingKindFixedChunkSize:
+		return pollingStrategyFixedChunkSize
+	default:
+		return pollingStrategyPriorityInterval
+	}
+}
+
+// PollingWatcher watches files by periodically comparing their modification times, and directories by comparing
+// their entries. It works with any vfs.FS.
+type PollingWatcher struct {
+	fs       vfs.FS
+	interval time.Duration
+	options  *core.WatchOptions
+
+	events chan Event
+	done   chan struct{}
+	// Whether events and done were created by this watcher, rather than shared with the watcher it is the fallback of
+	owned     bool
+	startOnce sync.Once
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+
+	mu            sync.Mutex
+	files         map[string]*pollingEntry
+	directories   map[string]*pollingEntry
+	chunkCursor   int
+	nextChunkPoll time.Time
+}
+
+var _ Watcher = (*PollingWatcher)(nil)
+
+type pollingEntry struct {
+	name      string
+	directory bool
+	recursive bool
+	strategy  pollingStrategy
+
+	interval       time.Duration
+	unchangedPolls int
+	nextPoll       time.Time
+
+	// The state of a file when it was last polled
+	exists  bool
+	modTime time.Time
+	// The entries of a directory when it was last polled
+	entries map[string]struct{}
+}
+
+// NewPollingWatcher creates a watcher that polls the files of fs, using the polling strategies selected by the
+// watch options. Polling starts when Events is first called; Poll checks every file and directory immediately.
+func NewPollingWatcher(fs vfs.FS, options *core.WatchOptions) *PollingWatcher {
+	if options == nil {
+		options = &core.WatchOptions{}
+	}
+	w := newPollingWatcher(fs, options, make(chan Event), make(chan struct{}))
+	w.owned = true
+	return w
+}
+
+func newPollingWatcher(fs vfs.FS, options *core.WatchOptions, events chan Event, done chan struct{}) *PollingWatcher {
+	interval := pollingIntervalLow
+	if options.Interval != nil && *options.Interval > 0 {
+		interval = time.Duration(*options.Interval) * time.Millisecond
+	}
+	return &PollingWatcher{
+		fs:          fs,
+		interval:    interval,
+		options:     options,
+		events:      events,
+		done:        done,
+		files:       make(map[string]*pollingEntry),
+		directories: make(map[string]*pollingEntry),
+	}
+}
+
+func (w *PollingWatcher) WatchFile(fileName string) {
+	w.watch(fileName, false /*directory*/, false /*recursive*/, fileStrategy(w.options))
+}
+
+func (w *PollingWatcher) WatchDirectory(directoryName string, recursive bool) {
+	w.watch(directoryName, true /*directory*/, recursive, directoryStrategy(w.options))
+}
+
+func (w *PollingWatcher) watch(name string, directory bool, recursive bool, strategy pollingStrategy) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	entries := core.IfElse(directory, w.directories, w.files)
+	if existing, ok := entries[name]; ok && existing.recursive == recursive {
+		return
+	}
+	entry := &pollingEntry{
+		name:      name,
+		directory: directory,
+		recursive: recursive,
+		strategy:  strategy,
+		interval:  w.initialInterval(name, strategy),
+	}
+	entry.nextPoll = time.Now().Add(entry.interval)
+	// Take the initial state, so that the first poll only reports changes made after this call
+	w.check(entry)
+	entries[name] = entry
+}
+
+func (w *PollingWatcher) initialInterval(name string, strategy pollingStrategy) time.Duration {
+	switch strategy {
+	case pollingStrategyPriorityInterval:
+		if strings.Contains(name, "/node_modules/") {
+			return pollingIntervalHigh
+		}
+		return w.interval
+	case pollingStrategyDynamicPriority:
+		return pollingIntervalLow
+	case pollingStrategyFixedChunkSize:
+		return pollingIntervalHigh
+	default:
+		return w.interval
+	}
+}
+
+func (w *PollingWatcher) Unwatch(name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.files, name)
+	delete(w.directories, name)
+}
+
+func (w *PollingWatcher) Events() <-chan Event {
+	w.startOnce.Do(func() {
+		if w.owned {
+			w.wg.Add(1)
+			go func() {
+				defer w.wg.Done()
+				w.run()
+			}()
+		}
+	})
+	return w.events
+}
+
+func (w *PollingWatcher) Close() error {
+	if w.owned {
+		w.closeOnce.Do(func() {
+			close(w.done)
+			w.wg.Wait()
+			close(w.events)
+		})
+	}
+	return nil
+}
+
+// Poll checks every watched file and directory for changes since they were last polled, regardless of their
+// polling intervals, and returns the changes instead of delivering them on the events channel.
+func (w *PollingWatcher) Poll() []Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var events []Event
+	for _, entry := range w.sortedEntries() {
+		events = append(events, w.check(entry)...)
+	}
+	return events
+}
+
+// run polls the files that are due at every tick until the watcher is closed.
+func (w *PollingWatcher) run() {
+	ticker := time.NewTicker(min(w.interval, pollingIntervalLow))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case now := <-ticker.C:
+			for _, event := range w.pollDue(now) {
+				select {
+				case w.events <- event:
+				case <-w.done:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (w *PollingWatcher) pollDue(now time.Time) []Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var events []Event
+	var chunk []*pollingEntry
+	for _, entry := range w.sortedEntries() {
+		if entry.strategy == pollingStrategyFixedChunkSize {
+			chunk = append(chunk, entry)
+			continue
+		}
+		if now.Before(entry.nextPoll) {
+			continue
+		}
+		events = append(events, w.check(entry)...)
+		entry.nextPoll = now.Add(entry.interval)
+	}
+	if len(chunk) > 0 && !now.Before(w.nextChunkPoll) {
+		for range min(pollingChunkSize, len(chunk)) {
+			w.chunkCursor %= len(chunk)
+			events = append(events, w.check(chunk[w.chunkCursor])...)
+			w.chunkCursor++
+		}
+		w.nextChunkPoll = now.Add(pollingIntervalHigh)
+	}
+	return events
+}
+
+func (w *PollingWatcher) sortedEntries() []*pollingEntry {
+	entries := make([]*pollingEntry, 0, len(w.files)+len(w.directories))
+	for _, name := range slices.Sorted(maps.Keys(w.files)) {
+		entries = append(entries, w.files[name])
+	}
+	for _, name := range slices.Sorted(maps.Keys(w.directories)) {
+		entries = append(entries, w.directories[name])
+	}
+	return entries
+}
+
+func (w *PollingWatcher) check(entry *pollingEntry) []Event {
+	var events []Event
+	if entry.directory {
+		events = w.checkDirectory(entry)
+	} else {
+		events = w.checkFile(entry)
+	}
+	if entry.strategy == pollingStrategyDynamicPriority {
+		if len(events) > 0 {
+			entry.interval = pollingIntervalLow
+			entry.unchangedPolls = 0
+		} else if entry.unchangedPolls++; entry.unchangedPolls >= unchangedPollThreshold(entry.interval) {
+			entry.interval = slowerInterval(entry.interval)
+			entry.unchangedPolls = 0
+		}
+	}
+	return events
+}
+
+func (w *PollingWatcher) checkFile(entry *pollingEntry) []Event {
+	stat := w.fs.Stat(entry.name)
+	exists := stat != nil
+	var modTime time.Time
+	if exists {
+		modTime = stat.ModTime()
+	}
+	var events []Event
+	switch {
+	case exists && !entry.exists:
+		events = append(events, Event{Kind: EventKindCreated, Path: entry.name})
+	case !exists && entry.exists:
+		events = append(events, Event{Kind: EventKindDeleted, Path: entry.name})
+	case exists && !modTime.Equal(entry.modTime):
+		events = append(events, Event{Kind: EventKindChanged, Path: entry.name})
+	}
+	entry.exists = exists
+	entry.modTime = modTime
+	return events
+}
+
+func (w *PollingWatcher) checkDirectory(entry *pollingEntry) []Event {
+	entries := make(map[string]struct{})
+	if w.fs.DirectoryExists(entry.name) {
+		w.readEntries(entry.name, entry.recursive, entries)
+	}
+	var events []Event
+	if entry.entries != nil {
+		for name := range entries {
+			if _, ok := entry.entries[name]; !ok {
+				events = append(events, Event{Kind: EventKindCreated, Path: name})
+			}
+		}
+		for name := range entry.entries {
+			if _, ok := entries[name]; !ok {
+				events = append(events, Event{Kind: EventKindDeleted, Path: name})
+			}
+		}
+		slices.SortFunc(events, func(a, b Event) int { return strings.Compare(a.Path, b.Path) })
+	}
+	entry.entries = entries
+	return events
+}
+
+func (w *PollingWatcher) readEntries(directoryName string, recursive bool, result map[string]struct{}) {
+	entries := w.fs.GetAccessibleEntries(directoryName)
+	for _, file := range entries.Files {
+		result[tspath.CombinePaths(directoryName, file)] = struct{}{}
+	}
+	for _, directory := range entries.Directories {
+		path := tspath.CombinePaths(directoryName, directory)
+		if isIgnoredPath(path) {
+			continue
+		}
+		result[path] = struct{}{}
+		if recursive {
+			w.readEntries(path, recursive, result)
+		}
+	}
+}
+
+// unchangedPollThreshold is the number of polls without changes after which a file polled with
+// pollingStrategyDynamicPriority moves to the next slower interval.
+func unchangedPollThreshold(interval time.Duration) int {
+	switch interval {
+	case pollingIntervalLow:
+		return 32
+	case pollingIntervalMedium:
+		return 64
+	default:
+		return 256
+	}
+}
+
+func slowerInterval(interval time.Duration) time.Duration {
+	switch interval {
+	case pollingIntervalLow:
+		return pollingIntervalMedium
+	default:
+		return pollingIntervalHigh
+	}
+}
@@ -0,0 +1,105 @@
+package vfswatch_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/vfs/vfstest"
+	"github.com/microsoft/typescript-go/internal/vfs/vfswatch"
+	"gotest.tools/v3/assert"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func TestPollingWatcher(t *testing.T) {
+	t.Parallel()
+
+	clock := &testClock{now: time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)}
+	fs := vfstest.FromMapWithClock(map[string]string{
+		"/project/a.ts":     "export const a = 1;",
+		"/project/src/b.ts": "export const b = 1;",
+	}, true /*useCaseSensitiveFileNames*/, clock)
+
+	w := vfswatch.NewPollingWatcher(fs, &core.WatchOptions{FileKind: core.WatchFileKindDynamicPriorityPolling})
+	defer w.Close()
+	w.WatchFile("/project/a.ts")
+	w.WatchFile("/project/missing.ts")
+	w.WatchDirectory("/project", true /*recursive*/)
+
+	assert.Equal(t, len(w.Poll()), 0)
+
+	clock.now = clock.now.Add(time.Second)
+	assert.NilError(t, fs.WriteFile("/project/a.ts", "export const a = 2;", false))
+	assert.DeepEqual(t, w.Poll(), []vfswatch.Event{
+		{Kind: vfswatch.EventKindChanged, Path: "/project/a.ts"},
+	})
+	assert.Equal(t, len(w.Poll()), 0)
+
+	assert.NilError(t, fs.WriteFile("/project/missing.ts", "export const c = 1;", false))
+	assert.NilError(t, fs.WriteFile("/project/src/lib/d.ts", "export const d = 1;", false))
+	assert.DeepEqual(t, w.Poll(), []vfswatch.Event{
+		{Kind: vfswatch.EventKindCreated, Path: "/project/missing.ts"},
+		{Kind: vfswatch.EventKindCreated, Path: "/project/missing.ts"},
+		{Kind: vfswatch.EventKindCreated, Path: "/project/src/lib"},
+		{Kind: vfswatch.EventKindCreated, Path: "/project/src/lib/d.ts"},
+	})
+
+	assert.NilError(t, fs.Remove("/project/src/b.ts"))
+	assert.DeepEqual(t, w.Poll(), []vfswatch.Event{
+		{Kind: vfswatch.EventKindDeleted, Path: "/project/src/b.ts"},
+	})
+
+	w.Unwatch("/project")
+	assert.NilError(t, fs.Remove("/project/a.ts"))
+	assert.DeepEqual(t, w.Poll(), []vfswatch.Event{
+		{Kind: vfswatch.EventKindDeleted, Path: "/project/a.ts"},
+	})
+}
+
+func TestPollingWatcherNonRecursive(t *testing.T) {
+	t.Parallel()
+
+	fs := vfstest.FromMap(map[string]string{
+		"/project/a.ts": "export const a = 1;",
+	}, true /*useCaseSensitiveFileNames*/)
+
+	w := vfswatch.NewPollingWatcher(fs, nil)
+	defer w.Close()
+	w.WatchDirectory("/project", false /*recursive*/)
+
+	assert.NilError(t, fs.WriteFile("/project/src/b.ts", "export const b = 1;", false))
+	assert.DeepEqual(t, w.Poll(), []vfswatch.Event{
+		{Kind: vfswatch.EventKindCreated, Path: "/project/src"},
+	})
+}
+
+func TestPollingWatcherEvents(t *testing.T) {
+	t.Parallel()
+
+	fs := vfstest.FromMap(map[string]string{
+		"/project/a.ts": "export const a = 1;",
+	}, true /*useCaseSensitiveFileNames*/)
+
+	w := vfswatch.NewPollingWatcher(fs, &core.WatchOptions{FileKind: core.WatchFileKindFixedPollingInterval})
+	w.WatchFile("/project/a.ts")
+	events := w.Events()
+
+	assert.NilError(t, fs.Remove("/project/a.ts"))
+	select {
+	case event := <-events:
+		assert.DeepEqual(t, event, vfswatch.Event{Kind: vfswatch.EventKindDeleted, Path: "/project/a.ts"})
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the deletion to be reported")
+	}
+
+	assert.NilError(t, w.Close())
+	_, ok := <-events
+	assert.Assert(t, !ok)
+}
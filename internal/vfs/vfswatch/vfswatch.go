@@ -0,0 +1,144 @@
+// Package vfswatch watches files and directories for changes, using the notifications of the operating system
+// where they are available and polling the file system otherwise.
+package vfswatch
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/microsoft/typescript-go/internal/core"
+	"github.com/microsoft/typescript-go/internal/vfs"
+)
+
+type EventKind int
+
+const (
+	EventKindCreated EventKind = iota + 1
+	EventKindChanged
+	EventKindDeleted
+)
+
+// Event describes a change to a watched file, or to the entries of a watched directory.
+type Event struct {
+	Kind EventKind
+	// The normalized path of the file or directory that changed
+	Path string
+}
+
+// Watcher reports changes to the files and directories it has been asked to watch.
+type Watcher interface {
+	// WatchFile reports the creation and deletion of the file and changes to its contents.
+	WatchFile(fileName string)
+
+	// WatchDirectory reports files and directories being added to or removed from the directory, and from all of
+	// its subdirectories if recursive is set. Changes to the contents of files in the directory may or may not be
+	// reported.
+	WatchDirectory(directoryName string, recursive bool)
+
+	// Unwatch stops watching a file or directory passed to WatchFile or WatchDirectory.
+	Unwatch(name string)
+
+	// Events returns the channel changes are delivered on. It is closed by Close.
+	Events() <-chan Event
+
+	Close() error
+}
+
+// New creates a watcher for the files of fs, which must be backed by the file system of the operating system.
+// The watch options select between native notifications and the polling strategies; if native notifications are
+// not supported, or the system runs out of them, the fallback polling strategy is used instead.
+func New(fs vfs.FS, options *core.WatchOptions) Watcher {
+	if options == nil {
+		options = &core.WatchOptions{}
+	}
+	w := &watcher{
+		options: options,
+		events:  make(chan Event),
+		done:    make(chan struct{}),
+	}
+	w.polling = newPollingWatcher(fs, options, w.events, w.done)
+	native, err := newNativeWatcher(fs, w.events, w.done)
+	if err == nil {
+		w.native = native
+	}
+	return w
+}
+
+type watcher struct {
+	options *core.WatchOptions
+	polling *PollingWatcher
+	// nil if the operating system does not support native notifications
+	native *nativeWatcher
+
+	events    chan Event
+	done      chan struct{}
+	startOnce sync.Once
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+var _ Watcher = (*watcher)(nil)
+
+func (w *watcher) WatchFile(fileName string) {
+	switch w.options.FileKind {
+	case core.WatchFileKindNone, core.WatchFileKindUseFsEvents, core.WatchFileKindUseFsEventsOnParentDirectory:
+		if w.native != nil && w.native.watchFile(fileName) == nil {
+			return
+		}
+	}
+	w.polling.WatchFile(fileName)
+}
+
+func (w *watcher) WatchDirectory(directoryName string, recursive bool) {
+	switch w.options.DirectoryKind {
+	case core.WatchDirectoryKindNone, core.WatchDirectoryKindUseFsEvents:
+		if w.native != nil && w.native.watchDirectory(directoryName, recursive) == nil {
+			return
+		}
+	}
+	w.polling.WatchDirectory(directoryName, recursive)
+}
+
+func (w *watcher) Unwatch(name string) {
+	if w.native != nil {
+		w.native.unwatch(name)
+	}
+	w.polling.Unwatch(name)
+}
+
+func (w *watcher) Events() <-chan Event {
+	w.startOnce.Do(func() {
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			w.polling.run()
+		}()
+		if w.native != nil {
+			w.wg.Add(1)
+			go func() {
+				defer w.wg.Done()
+				w.native.run()
+			}()
+		}
+	})
+	return w.events
+}
+
+func (w *watcher) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.done)
+		if w.native != nil {
+			err = w.native.close()
+		}
+		w.wg.Wait()
+		close(w.events)
+	})
+	return err
+}
+
+// isIgnoredPath reports whether changes under the path are never of interest, so that recursive watchers do not
+// descend into it.
+func isIgnoredPath(path string) bool {
+	return strings.Contains(path, "/node_modules/.") || strings.Contains(path, "/.git") || strings.Contains(path, "/.#")
+}
@@ -0,0 +1,68 @@
+
+currentDirectory::/home/src/workspaces/solution
+useCaseSensitiveFileNames::true
+Input::--b shared --verbose
+//// [/home/src/workspaces/solution/app/index.ts] new file
+import { greet } from "../shared";
+export const message = greet("world");
+//// [/home/src/workspaces/solution/app/tsconfig.json] new file
+{
+	"compilerOptions": {
+		"composite": true,
+	},
+	"references": [
+		{ "path": "../shared" },
+	],
+}
+//// [/home/src/workspaces/solution/shared/index.ts] new file
+export function greet(name: string) { return `Hello, ${name}`; }
+//// [/home/src/workspaces/solution/shared/tsconfig.json] new file
+{
+	"compilerOptions": {
+		"composite": true,
+	},
+}
+//// [/home/src/workspaces/solution/tsconfig.json] new file
+{
+	"files": [],
+	"references": [
+		{ "path": "shared" },
+		{ "path": "app" },
+	],
+}
+
+ExitStatus:: 0
+
+BuildOptions::{
+    "verbose": true
+}
+
+CompilerOptions::{}
+Output::
+[[90m12:00:00 PM[0m] Projects in this build: 
+    * shared/tsconfig.json
+
+
+[[90m12:00:00 PM[0m] Project 'shared/tsconfig.json' is out of date because output file 'shared/tsconfig.tsbuildinfo' does not exist
+
+
+[[90m12:00:00 PM[0m] Building project 'shared/tsconfig.json'...
+
+
+//// [/home/src/workspaces/solution/app/index.ts] no change
+//// [/home/src/workspaces/solution/app/tsconfig.json] no change
+//// [/home/src/workspaces/solution/shared/index.d.ts] new file
+export declare function greet(name: string): string;
+
+//// [/home/src/workspaces/solution/shared/index.js] new file
+"use strict";
+Object.defineProperty(exports, "__esModule", { value: true });
+exports.greet = greet;
+function greet(name) { return `Hello, ${name}`; }
+
+//// [/home/src/workspaces/solution/shared/index.ts] no change
+//// [/home/src/workspaces/solution/shared/tsconfig.json] no change
+//// [/home/src/workspaces/solution/shared/tsconfig.tsbuildinfo] new file
+{"version":"7.0.0-dev","root":["index.ts"],"fileNames":["bundled:///libs/lib.d.ts","bundled:///libs/lib.es5.d.ts","bundled:///libs/lib.dom.d.ts","bundled:///libs/lib.webworker.importscripts.d.ts","bundled:///libs/lib.scripthost.d.ts","bundled:///libs/lib.decorators.d.ts","bundled:///libs/lib.decorators.legacy.d.ts","index.ts"],"fileInfos":[{"version":"a7297ff837fcdf174a9524925966429eb8e5feecc2cc55cc06574e6b092c1eaa"},{"version":"e41c290ef7dd7dab3493e6cbe5909e0148edf4a8dad0271be08edec368a0f7b9","affectsGlobalScope":true},{"version":"9e8ca8ed051c2697578c023d9c29d6df689a083561feba5c14aedee895853999","affectsGlobalScope":true},{"version":"80e18897e5884b6723488d4f5652167e7bb5024f946743134ecc4aa4ee731f89","affectsGlobalScope":true},{"version":"cd034f499c6cdca722b60c04b5b1b78e058487a7085a8e0d6fb50809947ee573","affectsGlobalScope":true},{"version":"33358442698bb565130f52ba79bfd3d4d484ac85fe33f3cb1759c54d18201393","affectsGlobalScope":true},{"version":"782dec38049b92d4e85c1585fbea5474a219c6984a35b004963b00beb1aab538","affectsGlobalScope":true},{"version":"bfd126aebe11fea9d3733f8481228eba8a7a409b03100b17f75ac2daee3b3158","signature":"3797129873d11e510b485c116eeb6082ac4cdb7162ee1e662363f6235b3ae7c8"}],"options":{"composite":true},"referencedMap":[{"file":1,"references":[2,3,4,5]},{"file":2,"references":[6,7]}]}
+//// [/home/src/workspaces/solution/tsconfig.json] no change
+
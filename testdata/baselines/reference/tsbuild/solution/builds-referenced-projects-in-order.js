@@ -0,0 +1,272 @@
+
+currentDirectory::/home/src/workspaces/solution
+useCaseSensitiveFileNames::true
+Input::--b --verbose
+//// [/home/src/workspaces/solution/app/index.ts] new file
+import { greet } from "../shared";
+export const message = greet("world");
+//// [/home/src/workspaces/solution/app/tsconfig.json] new file
+{
+	"compilerOptions": {
+		"composite": true,
+	},
+	"references": [
+		{ "path": "../shared" },
+	],
+}
+//// [/home/src/workspaces/solution/shared/index.ts] new file
+export function greet(name: string) { return `Hello, ${name}`; }
+//// [/home/src/workspaces/solution/shared/tsconfig.json] new file
+{
+	"compilerOptions": {
+		"composite": true,
+	},
+}
+//// [/home/src/workspaces/solution/tsconfig.json] new file
+{
+	"files": [],
+	"references": [
+		{ "path": "shared" },
+		{ "path": "app" },
+	],
+}
+
+ExitStatus:: 0
+
+BuildOptions::{
+    "verbose": true
+}
+
+CompilerOptions::{}
+Output::
+[[90m12:00:00 PM[0m] Projects in this build: 
+    * shared/tsconfig.json
+    * app/tsconfig.json
+    * tsconfig.json
+
+
+[[90m12:00:00 PM[0m] Project 'shared/tsconfig.json' is out of date because output file 'shared/tsconfig.tsbuildinfo' does not exist
+
+
+[[90m12:00:00 PM[0m] Building project 'shared/tsconfig.json'...
+
+
+[[90m12:00:00 PM[0m] Project 'app/tsconfig.json' is out of date because output file 'app/tsconfig.tsbuildinfo' does not exist
+
+
+[[90m12:00:00 PM[0m] Building project 'app/tsconfig.json'...
+
+
+//// [/home/src/workspaces/solution/app/index.d.ts] new file
+export declare const message: string;
+
+//// [/home/src/workspaces/solution/app/index.js] new file
+"use strict";
+Object.defineProperty(exports, "__esModule", { value: true });
+exports.message = void 0;
+const shared_1 = require("../shared");
+exports.message = (0, shared_1.greet)("world");
+
+//// [/home/src/workspaces/solution/app/index.ts] no change
+//// [/home/src/workspaces/solution/app/tsconfig.json] no change
+//// [/home/src/workspaces/solution/app/tsconfig.tsbuildinfo] new file
+{"version":"7.0.0-dev","root":["index.ts"],"fileNames":["bundled:///libs/lib.d.ts","bundled:///libs/lib.es5.d.ts","bundled:///libs/lib.dom.d.ts","bundled:///libs/lib.webworker.importscripts.d.ts","bundled:///libs/lib.scripthost.d.ts","bundled:///libs/lib.decorators.d.ts","bundled:///libs/lib.decorators.legacy.d.ts","../shared/index.d.ts","index.ts"],"fileInfos":[{"version":"a7297ff837fcdf174a9524925966429eb8e5feecc2cc55cc06574e6b092c1eaa"},{"version":"e41c290ef7dd7dab3493e6cbe5909e0148edf4a8dad0271be08edec368a0f7b9","affectsGlobalScope":true},{"version":"9e8ca8ed051c2697578c023d9c29d6df689a083561feba5c14aedee895853999","affectsGlobalScope":true},{"version":"80e18897e5884b6723488d4f5652167e7bb5024f946743134ecc4aa4ee731f89","affectsGlobalScope":true},{"version":"cd034f499c6cdca722b60c04b5b1b78e058487a7085a8e0d6fb50809947ee573","affectsGlobalScope":true},{"version":"33358442698bb565130f52ba79bfd3d4d484ac85fe33f3cb1759c54d18201393","affectsGlobalScope":true},{"version":"782dec38049b92d4e85c1585fbea5474a219c6984a35b004963b00beb1aab538","affectsGlobalScope":true},{"version":"3797129873d11e510b485c116eeb6082ac4cdb7162ee1e662363f6235b3ae7c8"},{"version":"4d8b667fe6a70b7542c471edac7bb4b76891b40a7973b5e600b6b474b7dbd3f7","signature":"77eb1be543fdd4e9c3e7d30741d11b4863752ee0cb40d3a5b500a9a709307da1"}],"options":{"composite":true},"referencedMap":[{"file":1,"references":[2,3,4,5]},{"file":2,"references":[6,7]},{"file":9,"references":[8]}]}
+//// [/home/src/workspaces/solution/shared/index.d.ts] new file
+export declare function greet(name: string): string;
+
+//// [/home/src/workspaces/solution/shared/index.js] new file
+"use strict";
+Object.defineProperty(exports, "__esModule", { value: true });
+exports.greet = greet;
+function greet(name) { return `Hello, ${name}`; }
+
+//// [/home/src/workspaces/solution/shared/index.ts] no change
+//// [/home/src/workspaces/solution/shared/tsconfig.json] no change
+//// [/home/src/workspaces/solution/shared/tsconfig.tsbuildinfo] new file
+{"version":"7.0.0-dev","root":["index.ts"],"fileNames":["bundled:///libs/lib.d.ts","bundled:///libs/lib.es5.d.ts","bundled:///libs/lib.dom.d.ts","bundled:///libs/lib.webworker.importscripts.d.ts","bundled:///libs/lib.scripthost.d.ts","bundled:///libs/lib.decorators.d.ts","bundled:///libs/lib.decorators.legacy.d.ts","index.ts"],"fileInfos":[{"version":"a7297ff837fcdf174a9524925966429eb8e5feecc2cc55cc06574e6b092c1eaa"},{"version":"e41c290ef7dd7dab3493e6cbe5909e0148edf4a8dad0271be08edec368a0f7b9","affectsGlobalScope":true},{"version":"9e8ca8ed051c2697578c023d9c29d6df689a083561feba5c14aedee895853999","affectsGlobalScope":true},{"version":"80e18897e5884b6723488d4f5652167e7bb5024f946743134ecc4aa4ee731f89","affectsGlobalScope":true},{"version":"cd034f499c6cdca722b60c04b5b1b78e058487a7085a8e0d6fb50809947ee573","affectsGlobalScope":true},{"version":"33358442698bb565130f52ba79bfd3d4d484ac85fe33f3cb1759c54d18201393","affectsGlobalScope":true},{"version":"782dec38049b92d4e85c1585fbea5474a219c6984a35b004963b00beb1aab538","affectsGlobalScope":true},{"version":"bfd126aebe11fea9d3733f8481228eba8a7a409b03100b17f75ac2daee3b3158","signature":"3797129873d11e510b485c116eeb6082ac4cdb7162ee1e662363f6235b3ae7c8"}],"options":{"composite":true},"referencedMap":[{"file":1,"references":[2,3,4,5]},{"file":2,"references":[6,7]}]}
+//// [/home/src/workspaces/solution/tsconfig.json] no change
+
+
+
+Edit:: no change
+ExitStatus:: 0
+
+BuildOptions::{
+    "verbose": true
+}
+
+CompilerOptions::{}
+Output::
+[[90m12:01:00 PM[0m] Projects in this build: 
+    * shared/tsconfig.json
+    * app/tsconfig.json
+    * tsconfig.json
+
+
+[[90m12:01:00 PM[0m] Project 'shared/tsconfig.json' is up to date because newest input 'shared/index.ts' is older than output 'shared/tsconfig.tsbuildinfo'
+
+
+[[90m12:01:00 PM[0m] Project 'app/tsconfig.json' is up to date because newest input 'app/index.ts' is older than output 'app/tsconfig.tsbuildinfo'
+
+
+//// [/home/src/workspaces/solution/app/index.d.ts] no change
+//// [/home/src/workspaces/solution/app/index.js] no change
+//// [/home/src/workspaces/solution/app/index.ts] no change
+//// [/home/src/workspaces/solution/app/tsconfig.json] no change
+//// [/home/src/workspaces/solution/app/tsconfig.tsbuildinfo] no change
+//// [/home/src/workspaces/solution/shared/index.d.ts] no change
+//// [/home/src/workspaces/solution/shared/index.js] no change
+//// [/home/src/workspaces/solution/shared/index.ts] no change
+//// [/home/src/workspaces/solution/shared/tsconfig.json] no change
+//// [/home/src/workspaces/solution/shared/tsconfig.tsbuildinfo] no change
+//// [/home/src/workspaces/solution/tsconfig.json] no change
+
+
+
+Edit:: change upstream project
+ExitStatus:: 0
+
+BuildOptions::{
+    "verbose": true
+}
+
+CompilerOptions::{}
+Output::
+[[90m12:02:00 PM[0m] Projects in this build: 
+    * shared/tsconfig.json
+    * app/tsconfig.json
+    * tsconfig.json
+
+
+[[90m12:02:00 PM[0m] Project 'shared/tsconfig.json' is out of date because output 'shared/tsconfig.tsbuildinfo' is older than input 'shared/index.ts'
+
+
+[[90m12:02:00 PM[0m] Building project 'shared/tsconfig.json'...
+
+
+[[90m12:02:00 PM[0m] Project 'app/tsconfig.json' is out of date because output 'app/tsconfig.tsbuildinfo' is older than input 'shared'
+
+
+[[90m12:02:00 PM[0m] Building project 'app/tsconfig.json'...
+
+
+//// [/home/src/workspaces/solution/app/index.d.ts] no change
+//// [/home/src/workspaces/solution/app/index.js] no change
+//// [/home/src/workspaces/solution/app/index.ts] no change
+//// [/home/src/workspaces/solution/app/tsconfig.json] no change
+//// [/home/src/workspaces/solution/app/tsconfig.tsbuildinfo] modified. new content:
+{"version":"7.0.0-dev","root":["index.ts"],"fileNames":["bundled:///libs/lib.d.ts","bundled:///libs/lib.es5.d.ts","bundled:///libs/lib.dom.d.ts","bundled:///libs/lib.webworker.importscripts.d.ts","bundled:///libs/lib.scripthost.d.ts","bundled:///libs/lib.decorators.d.ts","bundled:///libs/lib.decorators.legacy.d.ts","../shared/index.d.ts","index.ts"],"fileInfos":[{"version":"a7297ff837fcdf174a9524925966429eb8e5feecc2cc55cc06574e6b092c1eaa"},{"version":"e41c290ef7dd7dab3493e6cbe5909e0148edf4a8dad0271be08edec368a0f7b9","affectsGlobalScope":true},{"version":"9e8ca8ed051c2697578c023d9c29d6df689a083561feba5c14aedee895853999","affectsGlobalScope":true},{"version":"80e18897e5884b6723488d4f5652167e7bb5024f946743134ecc4aa4ee731f89","affectsGlobalScope":true},{"version":"cd034f499c6cdca722b60c04b5b1b78e058487a7085a8e0d6fb50809947ee573","affectsGlobalScope":true},{"version":"33358442698bb565130f52ba79bfd3d4d484ac85fe33f3cb1759c54d18201393","affectsGlobalScope":true},{"version":"782dec38049b92d4e85c1585fbea5474a219c6984a35b004963b00beb1aab538","affectsGlobalScope":true},{"version":"237e4498d2acf085b8bd70fad7fb177d1ad0efcfca31190fee9a4adda8fc598a"},{"version":"4d8b667fe6a70b7542c471edac7bb4b76891b40a7973b5e600b6b474b7dbd3f7","signature":"77eb1be543fdd4e9c3e7d30741d11b4863752ee0cb40d3a5b500a9a709307da1"}],"options":{"composite":true},"referencedMap":[{"file":1,"references":[2,3,4,5]},{"file":2,"references":[6,7]},{"file":9,"references":[8]}]}
+//// [/home/src/workspaces/solution/shared/index.d.ts] modified. new content:
+export declare function greet(name: string): string;
+export declare const x = 10;
+
+//// [/home/src/workspaces/solution/shared/index.js] modified. new content:
+"use strict";
+Object.defineProperty(exports, "__esModule", { value: true });
+exports.x = void 0;
+exports.greet = greet;
+function greet(name) { return `Hello, ${name}`; }
+exports.x = 10;
+
+//// [/home/src/workspaces/solution/shared/index.ts] modified. new content:
+export function greet(name: string) { return `Hello, ${name}`; }
+export const x = 10;
+//// [/home/src/workspaces/solution/shared/tsconfig.json] no change
+//// [/home/src/workspaces/solution/shared/tsconfig.tsbuildinfo] modified. new content:
+{"version":"7.0.0-dev","root":["index.ts"],"fileNames":["bundled:///libs/lib.d.ts","bundled:///libs/lib.es5.d.ts","bundled:///libs/lib.dom.d.ts","bundled:///libs/lib.webworker.importscripts.d.ts","bundled:///libs/lib.scripthost.d.ts","bundled:///libs/lib.decorators.d.ts","bundled:///libs/lib.decorators.legacy.d.ts","index.ts"],"fileInfos":[{"version":"a7297ff837fcdf174a9524925966429eb8e5feecc2cc55cc06574e6b092c1eaa"},{"version":"e41c290ef7dd7dab3493e6cbe5909e0148edf4a8dad0271be08edec368a0f7b9","affectsGlobalScope":true},{"version":"9e8ca8ed051c2697578c023d9c29d6df689a083561feba5c14aedee895853999","affectsGlobalScope":true},{"version":"80e18897e5884b6723488d4f5652167e7bb5024f946743134ecc4aa4ee731f89","affectsGlobalScope":true},{"version":"cd034f499c6cdca722b60c04b5b1b78e058487a7085a8e0d6fb50809947ee573","affectsGlobalScope":true},{"version":"33358442698bb565130f52ba79bfd3d4d484ac85fe33f3cb1759c54d18201393","affectsGlobalScope":true},{"version":"782dec38049b92d4e85c1585fbea5474a219c6984a35b004963b00beb1aab538","affectsGlobalScope":true},{"version":"90c8e0b605b0a27383519f87dc953c13910938f9d7030dfc57da56721b8223bb","signature":"237e4498d2acf085b8bd70fad7fb177d1ad0efcfca31190fee9a4adda8fc598a"}],"options":{"composite":true},"referencedMap":[{"file":1,"references":[2,3,4,5]},{"file":2,"references":[6,7]}]}
+//// [/home/src/workspaces/solution/tsconfig.json] no change
+
+
+
+Edit:: force
+ExitStatus:: 0
+
+BuildOptions::{
+    "force": true,
+    "verbose": true
+}
+
+CompilerOptions::{}
+Output::
+[[90m12:03:00 PM[0m] Projects in this build: 
+    * shared/tsconfig.json
+    * app/tsconfig.json
+    * tsconfig.json
+
+
+[[90m12:03:00 PM[0m] Project 'shared/tsconfig.json' is being forcibly rebuilt
+
+
+[[90m12:03:00 PM[0m] Building project 'shared/tsconfig.json'...
+
+
+[[90m12:03:00 PM[0m] Project 'app/tsconfig.json' is being forcibly rebuilt
+
+
+[[90m12:03:00 PM[0m] Building project 'app/tsconfig.json'...
+
+
+//// [/home/src/workspaces/solution/app/index.d.ts] no change
+//// [/home/src/workspaces/solution/app/index.js] no change
+//// [/home/src/workspaces/solution/app/index.ts] no change
+//// [/home/src/workspaces/solution/app/tsconfig.json] no change
+//// [/home/src/workspaces/solution/app/tsconfig.tsbuildinfo] no change
+//// [/home/src/workspaces/solution/shared/index.d.ts] no change
+//// [/home/src/workspaces/solution/shared/index.js] no change
+//// [/home/src/workspaces/solution/shared/index.ts] no change
+//// [/home/src/workspaces/solution/shared/tsconfig.json] no change
+//// [/home/src/workspaces/solution/shared/tsconfig.tsbuildinfo] no change
+//// [/home/src/workspaces/solution/tsconfig.json] no change
+
+
+
+Edit:: change config of downstream project
+ExitStatus:: 0
+
+BuildOptions::{
+    "verbose": true
+}
+
+CompilerOptions::{}
+Output::
+[[90m12:04:00 PM[0m] Projects in this build: 
+    * shared/tsconfig.json
+    * app/tsconfig.json
+    * tsconfig.json
+
+
+[[90m12:04:00 PM[0m] Project 'shared/tsconfig.json' is up to date because newest input 'shared/index.ts' is older than output 'shared/tsconfig.tsbuildinfo'
+
+
+[[90m12:04:00 PM[0m] Project 'app/tsconfig.json' is out of date because buildinfo file 'app/tsconfig.tsbuildinfo' indicates there is change in compilerOptions
+
+
+[[90m12:04:00 PM[0m] Building project 'app/tsconfig.json'...
+
+
+//// [/home/src/workspaces/solution/app/index.d.ts] modified. new content:
+export declare const message: string;
+//# sourceMappingURL=index.d.ts.map
+//// [/home/src/workspaces/solution/app/index.d.ts.map] new file
+{"version":3,"file":"index.d.ts","sourceRoot":"","sources":["index.ts"],"names":[],"mappings":"AACA,eAAO,MAAM,OAAO,QAAiB,CAAC"}
+//// [/home/src/workspaces/solution/app/index.js] no change
+//// [/home/src/workspaces/solution/app/index.ts] no change
+//// [/home/src/workspaces/solution/app/tsconfig.json] modified. new content:
+{
+	"compilerOptions": {
+		"composite": true,
+		"declarationMap": true,
+	},
+	"references": [
+		{ "path": "../shared" },
+	],
+}
+//// [/home/src/workspaces/solution/app/tsconfig.tsbuildinfo] modified. new content:
+{"version":"7.0.0-dev","root":["index.ts"],"fileNames":["bundled:///libs/lib.d.ts","bundled:///libs/lib.es5.d.ts","bundled:///libs/lib.dom.d.ts","bundled:///libs/lib.webworker.importscripts.d.ts","bundled:///libs/lib.scripthost.d.ts","bundled:///libs/lib.decorators.d.ts","bundled:///libs/lib.decorators.legacy.d.ts","../shared/index.d.ts","index.ts"],"fileInfos":[{"version":"a7297ff837fcdf174a9524925966429eb8e5feecc2cc55cc06574e6b092c1eaa"},{"version":"e41c290ef7dd7dab3493e6cbe5909e0148edf4a8dad0271be08edec368a0f7b9","affectsGlobalScope":true},{"version":"9e8ca8ed051c2697578c023d9c29d6df689a083561feba5c14aedee895853999","affectsGlobalScope":true},{"version":"80e18897e5884b6723488d4f5652167e7bb5024f946743134ecc4aa4ee731f89","affectsGlobalScope":true},{"version":"cd034f499c6cdca722b60c04b5b1b78e058487a7085a8e0d6fb50809947ee573","affectsGlobalScope":true},{"version":"33358442698bb565130f52ba79bfd3d4d484ac85fe33f3cb1759c54d18201393","affectsGlobalScope":true},{"version":"782dec38049b92d4e85c1585fbea5474a219c6984a35b004963b00beb1aab538","affectsGlobalScope":true},{"version":"237e4498d2acf085b8bd70fad7fb177d1ad0efcfca31190fee9a4adda8fc598a"},{"version":"4d8b667fe6a70b7542c471edac7bb4b76891b40a7973b5e600b6b474b7dbd3f7","signature":"77eb1be543fdd4e9c3e7d30741d11b4863752ee0cb40d3a5b500a9a709307da1"}],"options":{"composite":true,"declarationMap":true},"referencedMap":[{"file":1,"references":[2,3,4,5]},{"file":2,"references":[6,7]},{"file":9,"references":[8]}]}
+//// [/home/src/workspaces/solution/shared/index.d.ts] no change
+//// [/home/src/workspaces/solution/shared/index.js] no change
+//// [/home/src/workspaces/solution/shared/index.ts] no change
+//// [/home/src/workspaces/solution/shared/tsconfig.json] no change
+//// [/home/src/workspaces/solution/shared/tsconfig.tsbuildinfo] no change
+//// [/home/src/workspaces/solution/tsconfig.json] no change
+
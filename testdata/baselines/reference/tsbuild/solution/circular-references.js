@@ -0,0 +1,43 @@
+
+currentDirectory::/home/src/workspaces/solution
+useCaseSensitiveFileNames::true
+Input::--b a --verbose
+//// [/home/src/workspaces/solution/a/index.ts] new file
+export const a = 1;
+//// [/home/src/workspaces/solution/a/tsconfig.json] new file
+{
+	"compilerOptions": { "composite": true },
+	"references": [{ "path": "../b" }],
+}
+//// [/home/src/workspaces/solution/b/index.ts] new file
+export const b = 1;
+//// [/home/src/workspaces/solution/b/tsconfig.json] new file
+{
+	"compilerOptions": { "composite": true },
+	"references": [{ "path": "../a" }],
+}
+
+ExitStatus:: 4
+
+BuildOptions::{
+    "verbose": true
+}
+
+CompilerOptions::{}
+Output::
+[[90m12:00:00 PM[0m] Projects in this build: 
+    * b/tsconfig.json
+    * a/tsconfig.json
+
+
+error TS6202: Project references may not form a circular graph. Cycle detected: /home/src/workspaces/solution/a/tsconfig.json
+/home/src/workspaces/solution/b/tsconfig.json
+
+
+Found 1 error.
+
+//// [/home/src/workspaces/solution/a/index.ts] no change
+//// [/home/src/workspaces/solution/a/tsconfig.json] no change
+//// [/home/src/workspaces/solution/b/index.ts] no change
+//// [/home/src/workspaces/solution/b/tsconfig.json] no change
+
@@ -0,0 +1,106 @@
+
+currentDirectory::/home/src/workspaces/solution
+useCaseSensitiveFileNames::true
+Input::--b
+//// [/home/src/workspaces/solution/app/index.ts] new file
+import { greet } from "../shared";
+export const message = greet("world");
+//// [/home/src/workspaces/solution/app/tsconfig.json] new file
+{
+	"compilerOptions": {
+		"composite": true,
+	},
+	"references": [
+		{ "path": "../shared" },
+	],
+}
+//// [/home/src/workspaces/solution/shared/index.ts] new file
+export function greet(name: string) { return `Hello, ${name}`; }
+//// [/home/src/workspaces/solution/shared/tsconfig.json] new file
+{
+	"compilerOptions": {
+		"composite": true,
+	},
+}
+//// [/home/src/workspaces/solution/tsconfig.json] new file
+{
+	"files": [],
+	"references": [
+		{ "path": "shared" },
+		{ "path": "app" },
+	],
+}
+
+ExitStatus:: 0
+
+BuildOptions::{}
+
+CompilerOptions::{}
+Output::
+//// [/home/src/workspaces/solution/app/index.d.ts] new file
+export declare const message: string;
+
+//// [/home/src/workspaces/solution/app/index.js] new file
+"use strict";
+Object.defineProperty(exports, "__esModule", { value: true });
+exports.message = void 0;
+const shared_1 = require("../shared");
+exports.message = (0, shared_1.greet)("world");
+
+//// [/home/src/workspaces/solution/app/index.ts] no change
+//// [/home/src/workspaces/solution/app/tsconfig.json] no change
+//// [/home/src/workspaces/solution/app/tsconfig.tsbuildinfo] new file
+{"version":"7.0.0-dev","root":["index.ts"],"fileNames":["bundled:///libs/lib.d.ts","bundled:///libs/lib.es5.d.ts","bundled:///libs/lib.dom.d.ts","bundled:///libs/lib.webworker.importscripts.d.ts","bundled:///libs/lib.scripthost.d.ts","bundled:///libs/lib.decorators.d.ts","bundled:///libs/lib.decorators.legacy.d.ts","../shared/index.d.ts","index.ts"],"fileInfos":[{"version":"a7297ff837fcdf174a9524925966429eb8e5feecc2cc55cc06574e6b092c1eaa"},{"version":"e41c290ef7dd7dab3493e6cbe5909e0148edf4a8dad0271be08edec368a0f7b9","affectsGlobalScope":true},{"version":"9e8ca8ed051c2697578c023d9c29d6df689a083561feba5c14aedee895853999","affectsGlobalScope":true},{"version":"80e18897e5884b6723488d4f5652167e7bb5024f946743134ecc4aa4ee731f89","affectsGlobalScope":true},{"version":"cd034f499c6cdca722b60c04b5b1b78e058487a7085a8e0d6fb50809947ee573","affectsGlobalScope":true},{"version":"33358442698bb565130f52ba79bfd3d4d484ac85fe33f3cb1759c54d18201393","affectsGlobalScope":true},{"version":"782dec38049b92d4e85c1585fbea5474a219c6984a35b004963b00beb1aab538","affectsGlobalScope":true},{"version":"3797129873d11e510b485c116eeb6082ac4cdb7162ee1e662363f6235b3ae7c8"},{"version":"4d8b667fe6a70b7542c471edac7bb4b76891b40a7973b5e600b6b474b7dbd3f7","signature":"77eb1be543fdd4e9c3e7d30741d11b4863752ee0cb40d3a5b500a9a709307da1"}],"options":{"composite":true},"referencedMap":[{"file":1,"references":[2,3,4,5]},{"file":2,"references":[6,7]},{"file":9,"references":[8]}]}
+//// [/home/src/workspaces/solution/shared/index.d.ts] new file
+export declare function greet(name: string): string;
+
+//// [/home/src/workspaces/solution/shared/index.js] new file
+"use strict";
+Object.defineProperty(exports, "__esModule", { value: true });
+exports.greet = greet;
+function greet(name) { return `Hello, ${name}`; }
+
+//// [/home/src/workspaces/solution/shared/index.ts] no change
+//// [/home/src/workspaces/solution/shared/tsconfig.json] no change
+//// [/home/src/workspaces/solution/shared/tsconfig.tsbuildinfo] new file
+{"version":"7.0.0-dev","root":["index.ts"],"fileNames":["bundled:///libs/lib.d.ts","bundled:///libs/lib.es5.d.ts","bundled:///libs/lib.dom.d.ts","bundled:///libs/lib.webworker.importscripts.d.ts","bundled:///libs/lib.scripthost.d.ts","bundled:///libs/lib.decorators.d.ts","bundled:///libs/lib.decorators.legacy.d.ts","index.ts"],"fileInfos":[{"version":"a7297ff837fcdf174a9524925966429eb8e5feecc2cc55cc06574e6b092c1eaa"},{"version":"e41c290ef7dd7dab3493e6cbe5909e0148edf4a8dad0271be08edec368a0f7b9","affectsGlobalScope":true},{"version":"9e8ca8ed051c2697578c023d9c29d6df689a083561feba5c14aedee895853999","affectsGlobalScope":true},{"version":"80e18897e5884b6723488d4f5652167e7bb5024f946743134ecc4aa4ee731f89","affectsGlobalScope":true},{"version":"cd034f499c6cdca722b60c04b5b1b78e058487a7085a8e0d6fb50809947ee573","affectsGlobalScope":true},{"version":"33358442698bb565130f52ba79bfd3d4d484ac85fe33f3cb1759c54d18201393","affectsGlobalScope":true},{"version":"782dec38049b92d4e85c1585fbea5474a219c6984a35b004963b00beb1aab538","affectsGlobalScope":true},{"version":"bfd126aebe11fea9d3733f8481228eba8a7a409b03100b17f75ac2daee3b3158","signature":"3797129873d11e510b485c116eeb6082ac4cdb7162ee1e662363f6235b3ae7c8"}],"options":{"composite":true},"referencedMap":[{"file":1,"references":[2,3,4,5]},{"file":2,"references":[6,7]}]}
+//// [/home/src/workspaces/solution/tsconfig.json] no change
+
+
+
+Edit:: clean
+ExitStatus:: 1
+
+BuildOptions::{
+    "clean": true
+}
+
+CompilerOptions::{}
+Output::
+error TS5033: Could not write file '/home/src/workspaces/solution/shared/index.js': the file is in use.
+
+error TS5033: Could not write file '/home/src/workspaces/solution/shared/index.d.ts': the file is in use.
+
+error TS5033: Could not write file '/home/src/workspaces/solution/shared/tsconfig.tsbuildinfo': the file is in use.
+
+error TS5033: Could not write file '/home/src/workspaces/solution/app/index.js': the file is in use.
+
+error TS5033: Could not write file '/home/src/workspaces/solution/app/index.d.ts': the file is in use.
+
+error TS5033: Could not write file '/home/src/workspaces/solution/app/tsconfig.tsbuildinfo': the file is in use.
+
+
+Found 6 errors.
+
+//// [/home/src/workspaces/solution/app/index.d.ts] no change
+//// [/home/src/workspaces/solution/app/index.js] no change
+//// [/home/src/workspaces/solution/app/index.ts] no change
+//// [/home/src/workspaces/solution/app/tsconfig.json] no change
+//// [/home/src/workspaces/solution/app/tsconfig.tsbuildinfo] no change
+//// [/home/src/workspaces/solution/shared/index.d.ts] no change
+//// [/home/src/workspaces/solution/shared/index.js] no change
+//// [/home/src/workspaces/solution/shared/index.ts] no change
+//// [/home/src/workspaces/solution/shared/tsconfig.json] no change
+//// [/home/src/workspaces/solution/shared/tsconfig.tsbuildinfo] no change
+//// [/home/src/workspaces/solution/tsconfig.json] no change
+
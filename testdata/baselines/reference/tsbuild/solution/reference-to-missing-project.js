@@ -0,0 +1,25 @@
+
+currentDirectory::/home/src/workspaces/solution
+useCaseSensitiveFileNames::true
+Input::--b
+//// [/home/src/workspaces/solution/tsconfig.json] new file
+{
+	"files": [],
+	"references": [
+		{ "path": "missing" },
+	],
+}
+
+ExitStatus:: 1
+
+BuildOptions::{}
+
+CompilerOptions::{}
+Output::
+error TS5083: Cannot read file '/home/src/workspaces/solution/missing/tsconfig.json'.
+
+
+Found 1 error.
+
+//// [/home/src/workspaces/solution/tsconfig.json] no change
+
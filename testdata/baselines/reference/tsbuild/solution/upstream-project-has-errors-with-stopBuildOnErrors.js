@@ -0,0 +1,82 @@
+
+currentDirectory::/home/src/workspaces/solution
+useCaseSensitiveFileNames::true
+Input::--b --verbose --stopBuildOnErrors
+//// [/home/src/workspaces/solution/app/index.ts] new file
+import { greet } from "../shared";
+export const message = greet("world");
+//// [/home/src/workspaces/solution/app/tsconfig.json] new file
+{
+	"compilerOptions": {
+		"composite": true,
+	},
+	"references": [
+		{ "path": "../shared" },
+	],
+}
+//// [/home/src/workspaces/solution/shared/index.ts] new file
+export function greet(name: string): number { return `Hello, ${name}`; }
+//// [/home/src/workspaces/solution/shared/tsconfig.json] new file
+{
+	"compilerOptions": {
+		"composite": true,
+	},
+}
+//// [/home/src/workspaces/solution/tsconfig.json] new file
+{
+	"files": [],
+	"references": [
+		{ "path": "shared" },
+		{ "path": "app" },
+	],
+}
+
+ExitStatus:: 1
+
+BuildOptions::{
+    "verbose": true,
+    "stopBuildOnErrors": true
+}
+
+CompilerOptions::{}
+Output::
+[[90m12:00:00 PM[0m] Projects in this build: 
+    * shared/tsconfig.json
+    * app/tsconfig.json
+    * tsconfig.json
+
+
+[[90m12:00:00 PM[0m] Project 'shared/tsconfig.json' is out of date because output file 'shared/tsconfig.tsbuildinfo' does not exist
+
+
+[[90m12:00:00 PM[0m] Building project 'shared/tsconfig.json'...
+
+
+shared/index.ts(1,47): error TS2322: Type 'string' is not assignable to type 'number'.
+
+[[90m12:00:00 PM[0m] Project 'app/tsconfig.json' can't be built because its dependency 'shared' has errors
+
+
+[[90m12:00:00 PM[0m] Skipping build of project 'app/tsconfig.json' because its dependency 'shared' has errors
+
+
+
+Found 1 error in shared/index.ts[90m:1[0m
+
+//// [/home/src/workspaces/solution/app/index.ts] no change
+//// [/home/src/workspaces/solution/app/tsconfig.json] no change
+//// [/home/src/workspaces/solution/shared/index.d.ts] new file
+export declare function greet(name: string): number;
+
+//// [/home/src/workspaces/solution/shared/index.js] new file
+"use strict";
+Object.defineProperty(exports, "__esModule", { value: true });
+exports.greet = greet;
+function greet(name) { return `Hello, ${name}`; }
+
+//// [/home/src/workspaces/solution/shared/index.ts] no change
+//// [/home/src/workspaces/solution/shared/tsconfig.json] no change
+//// [/home/src/workspaces/solution/shared/tsconfig.tsbuildinfo] new file
+{"version":"7.0.0-dev","errors":true,"root":["index.ts"],"fileNames":["bundled:///libs/lib.d.ts","bundled:///libs/lib.es5.d.ts","bundled:///libs/lib.dom.d.ts","bundled:///libs/lib.webworker.importscripts.d.ts","bundled:///libs/lib.scripthost.d.ts","bundled:///libs/lib.decorators.d.ts","bundled:///libs/lib.decorators.legacy.d.ts","index.ts"],"fileInfos":[{"version":"a7297ff837fcdf174a9524925966429eb8e5feecc2cc55cc06574e6b092c1eaa"},{"version":"e41c290ef7dd7dab3493e6cbe5909e0148edf4a8dad0271be08edec368a0f7b9","affectsGlobalScope":true},{"version":"9e8ca8ed051c2697578c023d9c29d6df689a083561feba5c14aedee895853999","affectsGlobalScope":true},{"version":"80e18897e5884b6723488d4f5652167e7bb5024f946743134ecc4aa4ee731f89","affectsGlobalScope":true},{"version":"cd034f499c6cdca722b60c04b5b1b78e058487a7085a8e0d6fb50809947ee573","affectsGlobalScope":true},{"version":"33358442698bb565130f52ba79bfd3d4d484ac85fe33f3cb1759c54d18201393","affectsGlobalScope":true},{"version":"782dec38049b92d4e85c1585fbea5474a219c6984a35b004963b00beb1aab538","affectsGlobalScope":true},{"version":"50419505f4874fbab9dbdba76a3ed27a8b584364c1016deff8d2a1c6321d9ecb","signature":"688ea5bac1dd430a573f706952806b063bfeccfd03f29338bed0d1152c6c6e04"}],"options":{"composite":true},"referencedMap":[{"file":1,"references":[2,3,4,5]},{"file":2,"references":[6,7]}],"semanticDiagnosticsPerFile":[{"file":8,"diagnostics":[{"pos":46,"end":52,"code":2322,"category":1,"message":"Type 'string' is not assignable to type 'number'."}]}]}
+//// [/home/src/workspaces/solution/tsconfig.json] no change
+
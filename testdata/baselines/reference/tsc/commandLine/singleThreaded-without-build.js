@@ -0,0 +1,32 @@
+
+currentDirectory::/home/src/workspaces/project
+useCaseSensitiveFileNames::true
+Input::--singleThreaded
+
+ExitStatus:: 1
+
+ParsedCommandLine::{
+    "parsedConfig": {
+        "compilerOptions": {},
+        "watchOptions": {
+            "watchInterval": null,
+            "watchFile": 0,
+            "watchDirectory": 0,
+            "fallbackPolling": 0,
+            "synchronousWatchDirectory": null,
+            "excludeDirectories": null,
+            "excludeFiles": null
+        },
+        "fileNames": [],
+        "projectReferences": null
+    },
+    "configFile": null,
+    "errors": [
+        {}
+    ],
+    "raw": {},
+    "compileOnSave": null
+}
+Output::
+error TS5093: Compiler option '--singlethreaded' may only be used with '--build'.
+
@@ -0,0 +1,60 @@
+
+currentDirectory::/home/src/workspaces/project
+useCaseSensitiveFileNames::true
+Input::
+//// [/home/src/workspaces/project/src/index.ts] new file
+export function add(a: number, b = 1) {
+	return a + b;
+}
+export class Point {
+	constructor(public x: number, public y: number) {}
+	scale(factor: number) {
+		return new Point(this.x * factor, this.y * factor);
+	}
+}
+//// [/home/src/workspaces/project/tsconfig.json] new file
+{
+	"compilerOptions": {
+		"declaration": true,
+		"outDir": "dist",
+		"declarationDir": "types",
+	},
+}
+
+ExitStatus:: 0
+
+CompilerOptions::{}
+Output::
+//// [/home/src/workspaces/project/dist/index.js] new file
+"use strict";
+Object.defineProperty(exports, "__esModule", { value: true });
+exports.Point = void 0;
+exports.add = add;
+function add(a, b = 1) {
+    return a + b;
+}
+class Point {
+    x;
+    y;
+    constructor(x, y) {
+        this.x = x;
+        this.y = y;
+    }
+    scale(factor) {
+        return new Point(this.x * factor, this.y * factor);
+    }
+}
+exports.Point = Point;
+
+//// [/home/src/workspaces/project/src/index.ts] no change
+//// [/home/src/workspaces/project/tsconfig.json] no change
+//// [/home/src/workspaces/project/types/index.d.ts] new file
+export declare function add(a: number, b?: number): number;
+export declare class Point {
+    x: number;
+    y: number;
+    constructor(x: number, y: number);
+    scale(factor: number): Point;
+}
+
+
@@ -56,13 +56,46 @@ Input::--showConfig
 	export const x = 10;
 
 
-ExitStatus:: 5
+ExitStatus:: 0
 
 CompilerOptions::{
     "showConfig": true
 }
 Output::
-No output
+{
+    "compilerOptions": {
+        "outDir": "./outDir",
+        "baseUrl": "./",
+        "paths": {
+            "@myscope/*": [
+                "./types/*"
+            ],
+            "other/*": [
+                "other/*"
+            ]
+        },
+        "typeRoots": [
+            "../configs/first/root1",
+            "./root2",
+            "../configs/first/root3"
+        ],
+        "types": [],
+        "declarationDir": "./decls",
+        "traceResolution": true,
+        "declaration": true
+    },
+    "files": [
+        "./main.ts",
+        "./src/secondary.ts"
+    ],
+    "include": [
+        "./src"
+    ],
+    "exclude": [
+        "./outDir",
+        "./decls"
+    ]
+}
 //// [/home/src/projects/configs/first/tsconfig.json] no change
 //// [/home/src/projects/configs/second/tsconfig.json] no change
 //// [/home/src/projects/myproject/main.ts] no change
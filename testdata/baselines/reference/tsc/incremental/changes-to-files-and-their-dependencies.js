@@ -0,0 +1,244 @@
+
+currentDirectory::/home/src/workspaces/project
+useCaseSensitiveFileNames::true
+Input::
+//// [/home/src/workspaces/project/a.ts] new file
+export function a() { return 1; }
+//// [/home/src/workspaces/project/b.ts] new file
+import { a } from "./a";
+export const b = a();
+//// [/home/src/workspaces/project/c.ts] new file
+export const c = "c";
+//// [/home/src/workspaces/project/tsconfig.json] new file
+{
+	"compilerOptions": {
+		"incremental": true,
+		"declaration": true,
+	},
+}
+
+ExitStatus:: 0
+
+CompilerOptions::{}
+Output::
+//// [/home/src/workspaces/project/a.d.ts] new file
+export declare function a(): number;
+
+//// [/home/src/workspaces/project/a.js] new file
+"use strict";
+Object.defineProperty(exports, "__esModule", { value: true });
+exports.a = a;
+function a() { return 1; }
+
+//// [/home/src/workspaces/project/a.ts] no change
+//// [/home/src/workspaces/project/b.d.ts] new file
+export declare const b: number;
+
+//// [/home/src/workspaces/project/b.js] new file
+"use strict";
+Object.defineProperty(exports, "__esModule", { value: true });
+exports.b = void 0;
+const a_1 = require("./a");
+exports.b = (0, a_1.a)();
+
+//// [/home/src/workspaces/project/b.ts] no change
+//// [/home/src/workspaces/project/c.d.ts] new file
+export declare const c = "c";
+
+//// [/home/src/workspaces/project/c.js] new file
+"use strict";
+Object.defineProperty(exports, "__esModule", { value: true });
+exports.c = void 0;
+exports.c = "c";
+
+//// [/home/src/workspaces/project/c.ts] no change
+//// [/home/src/workspaces/project/tsconfig.json] no change
+//// [/home/src/workspaces/project/tsconfig.tsbuildinfo] new file
+{"version":"7.0.0-dev","root":["a.ts","b.ts","c.ts"],"fileNames":["bundled:///libs/lib.d.ts","bundled:///libs/lib.es5.d.ts","bundled:///libs/lib.dom.d.ts","bundled:///libs/lib.webworker.importscripts.d.ts","bundled:///libs/lib.scripthost.d.ts","bundled:///libs/lib.decorators.d.ts","bundled:///libs/lib.decorators.legacy.d.ts","a.ts","b.ts","c.ts"],"fileInfos":[{"version":"a7297ff837fcdf174a9524925966429eb8e5feecc2cc55cc06574e6b092c1eaa"},{"version":"e41c290ef7dd7dab3493e6cbe5909e0148edf4a8dad0271be08edec368a0f7b9","affectsGlobalScope":true},{"version":"9e8ca8ed051c2697578c023d9c29d6df689a083561feba5c14aedee895853999","affectsGlobalScope":true},{"version":"80e18897e5884b6723488d4f5652167e7bb5024f946743134ecc4aa4ee731f89","affectsGlobalScope":true},{"version":"cd034f499c6cdca722b60c04b5b1b78e058487a7085a8e0d6fb50809947ee573","affectsGlobalScope":true},{"version":"33358442698bb565130f52ba79bfd3d4d484ac85fe33f3cb1759c54d18201393","affectsGlobalScope":true},{"version":"782dec38049b92d4e85c1585fbea5474a219c6984a35b004963b00beb1aab538","affectsGlobalScope":true},{"version":"8fa5d4943227bb2d88d6e74035c73304eb123eca04960d8c958aa9ebac5e2ab6","signature":"e95e7302d4299f2868ad0fa5a41e3e9a0fff25cad53431978e6fc49d27987103"},{"version":"6959096bc8e3f5c5c3b3dd6755c2bdde2715bc4d667159084263451bd13363b6","signature":"5a2b09da4cfc5f8a860fbb242d4780840a94e041510a67a510e1967c3b5e93ee"},{"version":"39ea2175c05a6f0deb228bb8cb126ec403c0946c7d358e1922d284d77a4a8021","signature":"d68c7798ac225dfb6bc41764b23d2485b7f72618b4513f510790a770330e2d7d"}],"options":{"declaration":true},"referencedMap":[{"file":1,"references":[2,3,4,5]},{"file":2,"references":[6,7]},{"file":9,"references":[8]}]}
+
+
+
+Edit:: no change
+ExitStatus:: 0
+
+CompilerOptions::{}
+Output::
+Files:               10
+Types:               14713
+//// [/home/src/workspaces/project/a.d.ts] no change
+//// [/home/src/workspaces/project/a.js] no change
+//// [/home/src/workspaces/project/a.ts] no change
+//// [/home/src/workspaces/project/b.d.ts] no change
+//// [/home/src/workspaces/project/b.js] no change
+//// [/home/src/workspaces/project/b.ts] no change
+//// [/home/src/workspaces/project/c.d.ts] no change
+//// [/home/src/workspaces/project/c.js] no change
+//// [/home/src/workspaces/project/c.ts] no change
+//// [/home/src/workspaces/project/tsconfig.json] no change
+//// [/home/src/workspaces/project/tsconfig.tsbuildinfo] no change
+
+
+
+Edit:: change body of file without changing its declarations
+ExitStatus:: 0
+
+CompilerOptions::{}
+Output::
+Files:               10
+Types:               332
+//// [/home/src/workspaces/project/a.d.ts] no change
+//// [/home/src/workspaces/project/a.js] modified. new content:
+"use strict";
+Object.defineProperty(exports, "__esModule", { value: true });
+exports.a = a;
+function a() { return 2; }
+
+//// [/home/src/workspaces/project/a.ts] modified. new content:
+export function a() { return 2; }
+//// [/home/src/workspaces/project/b.d.ts] no change
+//// [/home/src/workspaces/project/b.js] no change
+//// [/home/src/workspaces/project/b.ts] no change
+//// [/home/src/workspaces/project/c.d.ts] no change
+//// [/home/src/workspaces/project/c.js] no change
+//// [/home/src/workspaces/project/c.ts] no change
+//// [/home/src/workspaces/project/tsconfig.json] no change
+//// [/home/src/workspaces/project/tsconfig.tsbuildinfo] modified. new content:
+{"version":"7.0.0-dev","root":["a.ts","b.ts","c.ts"],"fileNames":["bundled:///libs/lib.d.ts","bundled:///libs/lib.es5.d.ts","bundled:///libs/lib.dom.d.ts","bundled:///libs/lib.webworker.importscripts.d.ts","bundled:///libs/lib.scripthost.d.ts","bundled:///libs/lib.decorators.d.ts","bundled:///libs/lib.decorators.legacy.d.ts","a.ts","b.ts","c.ts"],"fileInfos":[{"version":"a7297ff837fcdf174a9524925966429eb8e5feecc2cc55cc06574e6b092c1eaa"},{"version":"e41c290ef7dd7dab3493e6cbe5909e0148edf4a8dad0271be08edec368a0f7b9","affectsGlobalScope":true},{"version":"9e8ca8ed051c2697578c023d9c29d6df689a083561feba5c14aedee895853999","affectsGlobalScope":true},{"version":"80e18897e5884b6723488d4f5652167e7bb5024f946743134ecc4aa4ee731f89","affectsGlobalScope":true},{"version":"cd034f499c6cdca722b60c04b5b1b78e058487a7085a8e0d6fb50809947ee573","affectsGlobalScope":true},{"version":"33358442698bb565130f52ba79bfd3d4d484ac85fe33f3cb1759c54d18201393","affectsGlobalScope":true},{"version":"782dec38049b92d4e85c1585fbea5474a219c6984a35b004963b00beb1aab538","affectsGlobalScope":true},{"version":"5f27b6d40a2c2676b1c21e72c99e577f334ff868d052f1b9a9d3b50111e4d622","signature":"e95e7302d4299f2868ad0fa5a41e3e9a0fff25cad53431978e6fc49d27987103"},{"version":"6959096bc8e3f5c5c3b3dd6755c2bdde2715bc4d667159084263451bd13363b6","signature":"5a2b09da4cfc5f8a860fbb242d4780840a94e041510a67a510e1967c3b5e93ee"},{"version":"39ea2175c05a6f0deb228bb8cb126ec403c0946c7d358e1922d284d77a4a8021","signature":"d68c7798ac225dfb6bc41764b23d2485b7f72618b4513f510790a770330e2d7d"}],"options":{"declaration":true},"referencedMap":[{"file":1,"references":[2,3,4,5]},{"file":2,"references":[6,7]},{"file":9,"references":[8]}]}
+
+
+
+Edit:: change declarations of file
+ExitStatus:: 0
+
+CompilerOptions::{}
+Output::
+Files:               10
+Types:               334
+//// [/home/src/workspaces/project/a.d.ts] modified. new content:
+export declare function a(): string;
+
+//// [/home/src/workspaces/project/a.js] modified. new content:
+"use strict";
+Object.defineProperty(exports, "__esModule", { value: true });
+exports.a = a;
+function a() { return "a"; }
+
+//// [/home/src/workspaces/project/a.ts] modified. new content:
+export function a() { return "a"; }
+//// [/home/src/workspaces/project/b.d.ts] modified. new content:
+export declare const b: string;
+
+//// [/home/src/workspaces/project/b.js] no change
+//// [/home/src/workspaces/project/b.ts] no change
+//// [/home/src/workspaces/project/c.d.ts] no change
+//// [/home/src/workspaces/project/c.js] no change
+//// [/home/src/workspaces/project/c.ts] no change
+//// [/home/src/workspaces/project/tsconfig.json] no change
+//// [/home/src/workspaces/project/tsconfig.tsbuildinfo] modified. new content:
+{"version":"7.0.0-dev","root":["a.ts","b.ts","c.ts"],"fileNames":["bundled:///libs/lib.d.ts","bundled:///libs/lib.es5.d.ts","bundled:///libs/lib.dom.d.ts","bundled:///libs/lib.webworker.importscripts.d.ts","bundled:///libs/lib.scripthost.d.ts","bundled:///libs/lib.decorators.d.ts","bundled:///libs/lib.decorators.legacy.d.ts","a.ts","b.ts","c.ts"],"fileInfos":[{"version":"a7297ff837fcdf174a9524925966429eb8e5feecc2cc55cc06574e6b092c1eaa"},{"version":"e41c290ef7dd7dab3493e6cbe5909e0148edf4a8dad0271be08edec368a0f7b9","affectsGlobalScope":true},{"version":"9e8ca8ed051c2697578c023d9c29d6df689a083561feba5c14aedee895853999","affectsGlobalScope":true},{"version":"80e18897e5884b6723488d4f5652167e7bb5024f946743134ecc4aa4ee731f89","affectsGlobalScope":true},{"version":"cd034f499c6cdca722b60c04b5b1b78e058487a7085a8e0d6fb50809947ee573","affectsGlobalScope":true},{"version":"33358442698bb565130f52ba79bfd3d4d484ac85fe33f3cb1759c54d18201393","affectsGlobalScope":true},{"version":"782dec38049b92d4e85c1585fbea5474a219c6984a35b004963b00beb1aab538","affectsGlobalScope":true},{"version":"2e95eed0907afaa2e5ba162189aa0befba88b440adecb0ea7cce8879d08d79c5","signature":"7f144e93a5874115f344241ea5e93648085bcd875f9719515c2ee68dbb2a8d9d"},{"version":"6959096bc8e3f5c5c3b3dd6755c2bdde2715bc4d667159084263451bd13363b6","signature":"5a2b09da4cfc5f8a860fbb242d4780840a94e041510a67a510e1967c3b5e93ee"},{"version":"39ea2175c05a6f0deb228bb8cb126ec403c0946c7d358e1922d284d77a4a8021","signature":"d68c7798ac225dfb6bc41764b23d2485b7f72618b4513f510790a770330e2d7d"}],"options":{"declaration":true},"referencedMap":[{"file":1,"references":[2,3,4,5]},{"file":2,"references":[6,7]},{"file":9,"references":[8]}]}
+
+
+
+Edit:: introduce error
+ExitStatus:: 2
+
+CompilerOptions::{}
+Output::
+Files:               10
+Types:               337
+c.ts(1,14): error TS2322: Type 'string' is not assignable to type 'number'.
+
+
+Found 1 error in c.ts[90m:1[0m
+
+//// [/home/src/workspaces/project/a.d.ts] no change
+//// [/home/src/workspaces/project/a.js] no change
+//// [/home/src/workspaces/project/a.ts] no change
+//// [/home/src/workspaces/project/b.d.ts] no change
+//// [/home/src/workspaces/project/b.js] no change
+//// [/home/src/workspaces/project/b.ts] no change
+//// [/home/src/workspaces/project/c.d.ts] modified. new content:
+export declare const c: number;
+
+//// [/home/src/workspaces/project/c.js] no change
+//// [/home/src/workspaces/project/c.ts] modified. new content:
+export const c: number = "c";
+//// [/home/src/workspaces/project/tsconfig.json] no change
+//// [/home/src/workspaces/project/tsconfig.tsbuildinfo] modified. new content:
+{"version":"7.0.0-dev","errors":true,"root":["a.ts","b.ts","c.ts"],"fileNames":["bundled:///libs/lib.d.ts","bundled:///libs/lib.es5.d.ts","bundled:///libs/lib.dom.d.ts","bundled:///libs/lib.webworker.importscripts.d.ts","bundled:///libs/lib.scripthost.d.ts","bundled:///libs/lib.decorators.d.ts","bundled:///libs/lib.decorators.legacy.d.ts","a.ts","b.ts","c.ts"],"fileInfos":[{"version":"a7297ff837fcdf174a9524925966429eb8e5feecc2cc55cc06574e6b092c1eaa"},{"version":"e41c290ef7dd7dab3493e6cbe5909e0148edf4a8dad0271be08edec368a0f7b9","affectsGlobalScope":true},{"version":"9e8ca8ed051c2697578c023d9c29d6df689a083561feba5c14aedee895853999","affectsGlobalScope":true},{"version":"80e18897e5884b6723488d4f5652167e7bb5024f946743134ecc4aa4ee731f89","affectsGlobalScope":true},{"version":"cd034f499c6cdca722b60c04b5b1b78e058487a7085a8e0d6fb50809947ee573","affectsGlobalScope":true},{"version":"33358442698bb565130f52ba79bfd3d4d484ac85fe33f3cb1759c54d18201393","affectsGlobalScope":true},{"version":"782dec38049b92d4e85c1585fbea5474a219c6984a35b004963b00beb1aab538","affectsGlobalScope":true},{"version":"2e95eed0907afaa2e5ba162189aa0befba88b440adecb0ea7cce8879d08d79c5","signature":"7f144e93a5874115f344241ea5e93648085bcd875f9719515c2ee68dbb2a8d9d"},{"version":"6959096bc8e3f5c5c3b3dd6755c2bdde2715bc4d667159084263451bd13363b6","signature":"5a2b09da4cfc5f8a860fbb242d4780840a94e041510a67a510e1967c3b5e93ee"},{"version":"63681ef0b651e2ee1b3f65c3cac62378d042d79901e11a8151901848af8ff951","signature":"218da610a1321cf620760b5136fadb0b8aec7f2a6d695a05663c744ede388397"}],"options":{"declaration":true},"referencedMap":[{"file":1,"references":[2,3,4,5]},{"file":2,"references":[6,7]},{"file":9,"references":[8]}],"semanticDiagnosticsPerFile":[{"file":10,"diagnostics":[{"pos":13,"end":14,"code":2322,"category":1,"message":"Type 'string' is not assignable to type 'number'."}]}]}
+
+
+
+Edit:: no change with error
+ExitStatus:: 2
+
+CompilerOptions::{}
+Output::
+Files:               10
+Types:               334
+c.ts(1,14): error TS2322: Type 'string' is not assignable to type 'number'.
+
+
+Found 1 error in c.ts[90m:1[0m
+
+//// [/home/src/workspaces/project/a.d.ts] no change
+//// [/home/src/workspaces/project/a.js] no change
+//// [/home/src/workspaces/project/a.ts] no change
+//// [/home/src/workspaces/project/b.d.ts] no change
+//// [/home/src/workspaces/project/b.js] no change
+//// [/home/src/workspaces/project/b.ts] no change
+//// [/home/src/workspaces/project/c.d.ts] no change
+//// [/home/src/workspaces/project/c.js] no change
+//// [/home/src/workspaces/project/c.ts] no change
+//// [/home/src/workspaces/project/tsconfig.json] no change
+//// [/home/src/workspaces/project/tsconfig.tsbuildinfo] no change
+
+
+
+Edit:: fix error
+ExitStatus:: 0
+
+CompilerOptions::{}
+Output::
+Files:               10
+Types:               332
+//// [/home/src/workspaces/project/a.d.ts] no change
+//// [/home/src/workspaces/project/a.js] no change
+//// [/home/src/workspaces/project/a.ts] no change
+//// [/home/src/workspaces/project/b.d.ts] no change
+//// [/home/src/workspaces/project/b.js] no change
+//// [/home/src/workspaces/project/b.ts] no change
+//// [/home/src/workspaces/project/c.d.ts] modified. new content:
+export declare const c = "c";
+
+//// [/home/src/workspaces/project/c.js] no change
+//// [/home/src/workspaces/project/c.ts] modified. new content:
+export const c = "c";
+//// [/home/src/workspaces/project/tsconfig.json] no change
+//// [/home/src/workspaces/project/tsconfig.tsbuildinfo] modified. new content:
+{"version":"7.0.0-dev","root":["a.ts","b.ts","c.ts"],"fileNames":["bundled:///libs/lib.d.ts","bundled:///libs/lib.es5.d.ts","bundled:///libs/lib.dom.d.ts","bundled:///libs/lib.webworker.importscripts.d.ts","bundled:///libs/lib.scripthost.d.ts","bundled:///libs/lib.decorators.d.ts","bundled:///libs/lib.decorators.legacy.d.ts","a.ts","b.ts","c.ts"],"fileInfos":[{"version":"a7297ff837fcdf174a9524925966429eb8e5feecc2cc55cc06574e6b092c1eaa"},{"version":"e41c290ef7dd7dab3493e6cbe5909e0148edf4a8dad0271be08edec368a0f7b9","affectsGlobalScope":true},{"version":"9e8ca8ed051c2697578c023d9c29d6df689a083561feba5c14aedee895853999","affectsGlobalScope":true},{"version":"80e18897e5884b6723488d4f5652167e7bb5024f946743134ecc4aa4ee731f89","affectsGlobalScope":true},{"version":"cd034f499c6cdca722b60c04b5b1b78e058487a7085a8e0d6fb50809947ee573","affectsGlobalScope":true},{"version":"33358442698bb565130f52ba79bfd3d4d484ac85fe33f3cb1759c54d18201393","affectsGlobalScope":true},{"version":"782dec38049b92d4e85c1585fbea5474a219c6984a35b004963b00beb1aab538","affectsGlobalScope":true},{"version":"2e95eed0907afaa2e5ba162189aa0befba88b440adecb0ea7cce8879d08d79c5","signature":"7f144e93a5874115f344241ea5e93648085bcd875f9719515c2ee68dbb2a8d9d"},{"version":"6959096bc8e3f5c5c3b3dd6755c2bdde2715bc4d667159084263451bd13363b6","signature":"5a2b09da4cfc5f8a860fbb242d4780840a94e041510a67a510e1967c3b5e93ee"},{"version":"39ea2175c05a6f0deb228bb8cb126ec403c0946c7d358e1922d284d77a4a8021","signature":"d68c7798ac225dfb6bc41764b23d2485b7f72618b4513f510790a770330e2d7d"}],"options":{"declaration":true},"referencedMap":[{"file":1,"references":[2,3,4,5]},{"file":2,"references":[6,7]},{"file":9,"references":[8]}]}
+
+
+
+Edit:: change emit options
+ExitStatus:: 0
+
+CompilerOptions::{
+    "removeComments": true
+}
+Output::
+Files:               10
+Types:               334
+//// [/home/src/workspaces/project/a.d.ts] no change
+//// [/home/src/workspaces/project/a.js] no change
+//// [/home/src/workspaces/project/a.ts] no change
+//// [/home/src/workspaces/project/b.d.ts] no change
+//// [/home/src/workspaces/project/b.js] no change
+//// [/home/src/workspaces/project/b.ts] no change
+//// [/home/src/workspaces/project/c.d.ts] no change
+//// [/home/src/workspaces/project/c.js] no change
+//// [/home/src/workspaces/project/c.ts] no change
+//// [/home/src/workspaces/project/tsconfig.json] no change
+//// [/home/src/workspaces/project/tsconfig.tsbuildinfo] modified. new content:
+{"version":"7.0.0-dev","root":["a.ts","b.ts","c.ts"],"fileNames":["bundled:///libs/lib.d.ts","bundled:///libs/lib.es5.d.ts","bundled:///libs/lib.dom.d.ts","bundled:///libs/lib.webworker.importscripts.d.ts","bundled:///libs/lib.scripthost.d.ts","bundled:///libs/lib.decorators.d.ts","bundled:///libs/lib.decorators.legacy.d.ts","a.ts","b.ts","c.ts"],"fileInfos":[{"version":"a7297ff837fcdf174a9524925966429eb8e5feecc2cc55cc06574e6b092c1eaa"},{"version":"e41c290ef7dd7dab3493e6cbe5909e0148edf4a8dad0271be08edec368a0f7b9","affectsGlobalScope":true},{"version":"9e8ca8ed051c2697578c023d9c29d6df689a083561feba5c14aedee895853999","affectsGlobalScope":true},{"version":"80e18897e5884b6723488d4f5652167e7bb5024f946743134ecc4aa4ee731f89","affectsGlobalScope":true},{"version":"cd034f499c6cdca722b60c04b5b1b78e058487a7085a8e0d6fb50809947ee573","affectsGlobalScope":true},{"version":"33358442698bb565130f52ba79bfd3d4d484ac85fe33f3cb1759c54d18201393","affectsGlobalScope":true},{"version":"782dec38049b92d4e85c1585fbea5474a219c6984a35b004963b00beb1aab538","affectsGlobalScope":true},{"version":"2e95eed0907afaa2e5ba162189aa0befba88b440adecb0ea7cce8879d08d79c5","signature":"7f144e93a5874115f344241ea5e93648085bcd875f9719515c2ee68dbb2a8d9d"},{"version":"6959096bc8e3f5c5c3b3dd6755c2bdde2715bc4d667159084263451bd13363b6","signature":"5a2b09da4cfc5f8a860fbb242d4780840a94e041510a67a510e1967c3b5e93ee"},{"version":"39ea2175c05a6f0deb228bb8cb126ec403c0946c7d358e1922d284d77a4a8021","signature":"d68c7798ac225dfb6bc41764b23d2485b7f72618b4513f510790a770330e2d7d"}],"options":{"removeComments":true,"declaration":true},"referencedMap":[{"file":1,"references":[2,3,4,5]},{"file":2,"references":[6,7]},{"file":9,"references":[8]}]}
+
@@ -0,0 +1,94 @@
+
+currentDirectory::/home/src/workspaces/project
+useCaseSensitiveFileNames::true
+Input::
+//// [/home/src/workspaces/project/a.ts] new file
+export const a = 1;
+//// [/home/src/workspaces/project/b.ts] new file
+export const b: string = 1;
+export const x = (;
+//// [/home/src/workspaces/project/tsconfig.json] new file
+{
+	"compilerOptions": {
+		"incremental": true,
+		"outDir": "dist",
+	},
+}
+
+ExitStatus:: 2
+
+CompilerOptions::{}
+Output::
+b.ts(2,19): error TS1109: Expression expected.
+
+
+Found 1 error in b.ts[90m:2[0m
+
+//// [/home/src/workspaces/project/a.ts] no change
+//// [/home/src/workspaces/project/b.ts] no change
+//// [/home/src/workspaces/project/dist/a.js] new file
+"use strict";
+Object.defineProperty(exports, "__esModule", { value: true });
+exports.a = void 0;
+exports.a = 1;
+
+//// [/home/src/workspaces/project/dist/b.js] new file
+"use strict";
+Object.defineProperty(exports, "__esModule", { value: true });
+exports.x = exports.b = void 0;
+exports.b = 1;
+exports.x = ();
+
+//// [/home/src/workspaces/project/dist/tsconfig.tsbuildinfo] new file
+{"version":"7.0.0-dev","errors":true,"root":["../a.ts","../b.ts"],"fileNames":["bundled:///libs/lib.d.ts","bundled:///libs/lib.es5.d.ts","bundled:///libs/lib.dom.d.ts","bundled:///libs/lib.webworker.importscripts.d.ts","bundled:///libs/lib.scripthost.d.ts","bundled:///libs/lib.decorators.d.ts","bundled:///libs/lib.decorators.legacy.d.ts","../a.ts","../b.ts"],"fileInfos":[{"version":"a7297ff837fcdf174a9524925966429eb8e5feecc2cc55cc06574e6b092c1eaa"},{"version":"e41c290ef7dd7dab3493e6cbe5909e0148edf4a8dad0271be08edec368a0f7b9","affectsGlobalScope":true},{"version":"9e8ca8ed051c2697578c023d9c29d6df689a083561feba5c14aedee895853999","affectsGlobalScope":true},{"version":"80e18897e5884b6723488d4f5652167e7bb5024f946743134ecc4aa4ee731f89","affectsGlobalScope":true},{"version":"cd034f499c6cdca722b60c04b5b1b78e058487a7085a8e0d6fb50809947ee573","affectsGlobalScope":true},{"version":"33358442698bb565130f52ba79bfd3d4d484ac85fe33f3cb1759c54d18201393","affectsGlobalScope":true},{"version":"782dec38049b92d4e85c1585fbea5474a219c6984a35b004963b00beb1aab538","affectsGlobalScope":true},{"version":"683314ed22112e8dea8095c8c6173afa2c61279f5fe07968ebe0e21fff16871d","signature":"f0f1286d442f3c09fa07d37db7d31755cb3761daed3c8008fbfce412770425c6"},{"version":"d1c65db3fa44cbefb42f34beb53c8e3303a4d85452c069c3462bc858dc797b5c","signature":"6a53f6a23ace295773eb840085f0116d2fc73b38ab77aace441366a5ab787d64"}],"options":{"outDir":""},"referencedMap":[{"file":1,"references":[2,3,4,5]},{"file":2,"references":[6,7]}],"checkPending":[1,2,3,4,5,6,7,8,9]}
+//// [/home/src/workspaces/project/tsconfig.json] no change
+
+
+
+Edit:: fix syntax error
+ExitStatus:: 2
+
+CompilerOptions::{}
+Output::
+Files:               9
+Types:               336
+b.ts(1,14): error TS2322: Type 'number' is not assignable to type 'string'.
+
+
+Found 1 error in b.ts[90m:1[0m
+
+//// [/home/src/workspaces/project/a.ts] no change
+//// [/home/src/workspaces/project/b.ts] modified. new content:
+export const b: string = 1;
+//// [/home/src/workspaces/project/dist/a.js] no change
+//// [/home/src/workspaces/project/dist/b.js] modified. new content:
+"use strict";
+Object.defineProperty(exports, "__esModule", { value: true });
+exports.b = void 0;
+exports.b = 1;
+
+//// [/home/src/workspaces/project/dist/tsconfig.tsbuildinfo] modified. new content:
+{"version":"7.0.0-dev","errors":true,"root":["../a.ts","../b.ts"],"fileNames":["bundled:///libs/lib.d.ts","bundled:///libs/lib.es5.d.ts","bundled:///libs/lib.dom.d.ts","bundled:///libs/lib.webworker.importscripts.d.ts","bundled:///libs/lib.scripthost.d.ts","bundled:///libs/lib.decorators.d.ts","bundled:///libs/lib.decorators.legacy.d.ts","../a.ts","../b.ts"],"fileInfos":[{"version":"a7297ff837fcdf174a9524925966429eb8e5feecc2cc55cc06574e6b092c1eaa"},{"version":"e41c290ef7dd7dab3493e6cbe5909e0148edf4a8dad0271be08edec368a0f7b9","affectsGlobalScope":true},{"version":"9e8ca8ed051c2697578c023d9c29d6df689a083561feba5c14aedee895853999","affectsGlobalScope":true},{"version":"80e18897e5884b6723488d4f5652167e7bb5024f946743134ecc4aa4ee731f89","affectsGlobalScope":true},{"version":"cd034f499c6cdca722b60c04b5b1b78e058487a7085a8e0d6fb50809947ee573","affectsGlobalScope":true},{"version":"33358442698bb565130f52ba79bfd3d4d484ac85fe33f3cb1759c54d18201393","affectsGlobalScope":true},{"version":"782dec38049b92d4e85c1585fbea5474a219c6984a35b004963b00beb1aab538","affectsGlobalScope":true},{"version":"683314ed22112e8dea8095c8c6173afa2c61279f5fe07968ebe0e21fff16871d","signature":"f0f1286d442f3c09fa07d37db7d31755cb3761daed3c8008fbfce412770425c6"},{"version":"21771abf7c292e92aa3d6d5bb46bffc82717dfddc2fed6aec6fb530feb9aecfa","signature":"2eb2163f6de5ea8275c633771e4e5f98e23f9310d068641b62b9b89319f0af23"}],"options":{"outDir":""},"referencedMap":[{"file":1,"references":[2,3,4,5]},{"file":2,"references":[6,7]}],"semanticDiagnosticsPerFile":[{"file":9,"diagnostics":[{"pos":13,"end":14,"code":2322,"category":1,"message":"Type 'number' is not assignable to type 'string'."}]}]}
+//// [/home/src/workspaces/project/tsconfig.json] no change
+
+
+
+Edit:: no change
+ExitStatus:: 2
+
+CompilerOptions::{}
+Output::
+Files:               9
+Types:               14710
+b.ts(1,14): error TS2322: Type 'number' is not assignable to type 'string'.
+
+
+Found 1 error in b.ts[90m:1[0m
+
+//// [/home/src/workspaces/project/a.ts] no change
+//// [/home/src/workspaces/project/b.ts] no change
+//// [/home/src/workspaces/project/dist/a.js] no change
+//// [/home/src/workspaces/project/dist/b.js] no change
+//// [/home/src/workspaces/project/dist/tsconfig.tsbuildinfo] no change
+//// [/home/src/workspaces/project/tsconfig.json] no change
+
@@ -0,0 +1,16 @@
+
+currentDirectory::/home/src/workspaces/project
+useCaseSensitiveFileNames::true
+Input::--init
+//// [/home/src/workspaces/project/tsconfig.json] new file
+{}
+
+ExitStatus:: 0
+
+CompilerOptions::{
+    "init": true
+}
+Output::
+error TS5054: A 'tsconfig.json' file is already defined at: '/home/src/workspaces/project/tsconfig.json'.
+//// [/home/src/workspaces/project/tsconfig.json] no change
+
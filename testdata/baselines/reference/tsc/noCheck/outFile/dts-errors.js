@@ -13,13 +13,18 @@ export const b = 10;
 	}
 }
 
-ExitStatus:: 0
+ExitStatus:: 2
 
 CompilerOptions::{
     "noCheck": true,
     "outFile": "/home/src/workspaces/project/built"
 }
 Output::
+a.ts(1,14): error TS4094: Property 'p' of exported anonymous class type may not be private or protected.
+
+
+Found 1 error in a.ts[90m:1[0m
+
 //// [/home/src/workspaces/project/a.js] new file
 "use strict";
 Object.defineProperty(exports, "__esModule", { value: true });
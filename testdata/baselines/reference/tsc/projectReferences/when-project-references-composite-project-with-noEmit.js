@@ -26,10 +26,10 @@ CompilerOptions::{
     "project": "/home/src/workspaces/solution/project"
 }
 Output::
-project/index.ts(1,19): error TS2307: Cannot find module '../utils' or its corresponding type declarations.
+project/tsconfig.json(3,3): error TS6053: File '/home/src/workspaces/solution/utils' not found.
 
 
-Found 1 error in project/index.ts[90m:1[0m
+Found 1 error in project/tsconfig.json[90m:3[0m
 
 //// [/home/src/workspaces/solution/project/index.js] new file
 "use strict";
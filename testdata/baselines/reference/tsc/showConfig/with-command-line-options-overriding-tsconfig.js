@@ -0,0 +1,36 @@
+
+currentDirectory::/home/src/workspaces/project
+useCaseSensitiveFileNames::true
+Input::--showConfig --target esnext --declarationDir types --listFiles
+//// [/home/src/workspaces/project/first.ts] new file
+export const a = 1;
+//// [/home/src/workspaces/project/tsconfig.json] new file
+{ "compilerOptions": { "target": "es2015", "declaration": true } }
+
+ExitStatus:: 0
+
+CompilerOptions::{
+    "declarationDir": "/home/src/workspaces/project/types",
+    "target": 99,
+    "listFiles": true,
+    "showConfig": true
+}
+Output::
+{
+    "compilerOptions": {
+        "target": "esnext",
+        "declarationDir": "./types",
+        "declaration": true,
+        "module": "es6",
+        "useDefineForClassFields": true
+    },
+    "files": [
+        "./first.ts"
+    ],
+    "exclude": [
+        "./types"
+    ]
+}
+//// [/home/src/workspaces/project/first.ts] no change
+//// [/home/src/workspaces/project/tsconfig.json] no change
+
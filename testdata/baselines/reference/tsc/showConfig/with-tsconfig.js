@@ -0,0 +1,89 @@
+
+currentDirectory::/home/src/workspaces/project
+useCaseSensitiveFileNames::true
+Input::--showConfig
+//// [/home/src/workspaces/project/other/other.ts] new file
+export const d = 4;
+//// [/home/src/workspaces/project/src/index.ts] new file
+export const a = 1;
+//// [/home/src/workspaces/project/src/util.ts] new file
+export const b = 2;
+//// [/home/src/workspaces/project/tsconfig.json] new file
+{
+	"compilerOptions": {
+		"composite": true,
+		"strict": true,
+		"module": "nodenext",
+		"outDir": "dist",
+		"rootDirs": ["src", "generated"],
+		"lib": ["es2020", "dom"],
+	},
+	"include": ["src"],
+	"references": [{ "path": "../shared" }],
+	"compileOnSave": true,
+}
+//// [/home/src/workspaces/shared/index.ts] new file
+export const c = 3;
+//// [/home/src/workspaces/shared/tsconfig.json] new file
+{ "compilerOptions": { "composite": true } }
+
+ExitStatus:: 0
+
+CompilerOptions::{
+    "showConfig": true
+}
+Output::
+{
+    "compilerOptions": {
+        "module": "nodenext",
+        "lib": [
+            "es2020",
+            "dom"
+        ],
+        "outDir": "./dist",
+        "composite": true,
+        "strict": true,
+        "rootDirs": [
+            "./src",
+            "./generated"
+        ],
+        "target": "esnext",
+        "moduleResolution": "nodenext",
+        "esModuleInterop": true,
+        "resolveJsonModule": false,
+        "declaration": true,
+        "incremental": true,
+        "useDefineForClassFields": true,
+        "noImplicitAny": true,
+        "strictNullChecks": true,
+        "strictFunctionTypes": true,
+        "strictBindCallApply": true,
+        "strictPropertyInitialization": true,
+        "strictBuiltinIteratorReturn": true,
+        "noImplicitThis": true,
+        "useUnknownInCatchVariables": true,
+        "alwaysStrict": true
+    },
+    "references": [
+        {
+            "path": "../shared"
+        }
+    ],
+    "files": [
+        "./src/index.ts",
+        "./src/util.ts"
+    ],
+    "include": [
+        "src"
+    ],
+    "exclude": [
+        "./dist"
+    ]
+}
+//// [/home/src/workspaces/project/other/other.ts] no change
+//// [/home/src/workspaces/project/src/index.ts] no change
+//// [/home/src/workspaces/project/src/util.ts] no change
+//// [/home/src/workspaces/project/tsconfig.json] no change
+//// [/home/src/workspaces/shared/index.ts] no change
+//// [/home/src/workspaces/shared/tsconfig.json] no change
+
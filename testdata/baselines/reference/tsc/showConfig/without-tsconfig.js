@@ -0,0 +1,27 @@
+
+currentDirectory::/home/src/workspaces/project
+useCaseSensitiveFileNames::true
+Input::--showConfig --module esnext --watchFile fixedPollingInterval first.ts
+//// [/home/src/workspaces/project/first.ts] new file
+export const a = 1;
+
+ExitStatus:: 0
+
+CompilerOptions::{
+    "module": 99,
+    "showConfig": true
+}
+Output::
+{
+    "compilerOptions": {
+        "module": "esnext"
+    },
+    "watchOptions": {
+        "watchFile": "fixedpollinginterval"
+    },
+    "files": [
+        "./first.ts"
+    ]
+}
+//// [/home/src/workspaces/project/first.ts] no change
+
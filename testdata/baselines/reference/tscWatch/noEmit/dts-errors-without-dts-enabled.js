@@ -20,6 +20,11 @@ CompilerOptions::{
 
 
 Output::
+[[90m12:00:00 PM[0m] Starting compilation in watch mode...
+
+
+[[90m12:00:00 PM[0m] Found 0 errors. Watching for file changes.
+
 //// [/home/src/workspaces/project/a.ts] no change
 //// [/home/src/workspaces/project/tsconfig.json] no change
 
@@ -28,6 +33,11 @@ Output::
 Edit:: fix syntax error
 
 Output::
+[[90m12:01:00 PM[0m] File change detected. Starting incremental compilation...
+
+
+[[90m12:01:00 PM[0m] Found 0 errors. Watching for file changes.
+
 //// [/home/src/workspaces/project/a.ts] modified. new content:
 const a = "hello";
 //// [/home/src/workspaces/project/tsconfig.json] no change
@@ -37,6 +47,11 @@ const a = "hello";
 Edit:: emit after fixing error
 
 Output::
+[[90m12:02:00 PM[0m] File change detected. Starting incremental compilation...
+
+
+[[90m12:02:00 PM[0m] Found 0 errors. Watching for file changes.
+
 //// [/home/src/workspaces/project/a.js] new file
 const a = "hello";
 
@@ -53,6 +68,11 @@ const a = "hello";
 Edit:: no emit run after fixing error
 
 Output::
+[[90m12:03:00 PM[0m] File change detected. Starting incremental compilation...
+
+
+[[90m12:03:00 PM[0m] Found 0 errors. Watching for file changes.
+
 //// [/home/src/workspaces/project/a.js] no change
 //// [/home/src/workspaces/project/a.ts] no change
 //// [/home/src/workspaces/project/tsconfig.json] modified. new content:
@@ -68,6 +88,11 @@ Output::
 Edit:: introduce error
 
 Output::
+[[90m12:04:00 PM[0m] File change detected. Starting incremental compilation...
+
+
+[[90m12:04:00 PM[0m] Found 0 errors. Watching for file changes.
+
 //// [/home/src/workspaces/project/a.js] no change
 //// [/home/src/workspaces/project/a.ts] modified. new content:
 const a = class { private p = 10; };
@@ -78,6 +103,11 @@ const a = class { private p = 10; };
 Edit:: emit when error
 
 Output::
+[[90m12:05:00 PM[0m] File change detected. Starting incremental compilation...
+
+
+[[90m12:05:00 PM[0m] Found 0 errors. Watching for file changes.
+
 //// [/home/src/workspaces/project/a.js] modified. new content:
 const a = class {
     p = 10;
@@ -96,6 +126,11 @@ const a = class {
 Edit:: no emit run when error
 
 Output::
+[[90m12:06:00 PM[0m] File change detected. Starting incremental compilation...
+
+
+[[90m12:06:00 PM[0m] Found 0 errors. Watching for file changes.
+
 //// [/home/src/workspaces/project/a.js] no change
 //// [/home/src/workspaces/project/a.ts] no change
 //// [/home/src/workspaces/project/tsconfig.json] modified. new content:
@@ -21,6 +21,13 @@ CompilerOptions::{
 
 
 Output::
+[[90m12:00:00 PM[0m] Starting compilation in watch mode...
+
+
+a.ts(1,7): error TS4094: Property 'p' of exported anonymous class type may not be private or protected.
+
+[[90m12:00:00 PM[0m] Found 1 error. Watching for file changes.
+
 //// [/home/src/workspaces/project/a.ts] no change
 //// [/home/src/workspaces/project/tsconfig.json] no change
 
@@ -29,6 +36,11 @@ Output::
 Edit:: fix syntax error
 
 Output::
+[[90m12:01:00 PM[0m] File change detected. Starting incremental compilation...
+
+
+[[90m12:01:00 PM[0m] Found 0 errors. Watching for file changes.
+
 //// [/home/src/workspaces/project/a.ts] modified. new content:
 const a = "hello";
 //// [/home/src/workspaces/project/tsconfig.json] no change
@@ -38,6 +50,11 @@ const a = "hello";
 Edit:: emit after fixing error
 
 Output::
+[[90m12:02:00 PM[0m] File change detected. Starting incremental compilation...
+
+
+[[90m12:02:00 PM[0m] Found 0 errors. Watching for file changes.
+
 //// [/home/src/workspaces/project/a.js] new file
 const a = "hello";
 
@@ -55,6 +72,11 @@ const a = "hello";
 Edit:: no emit run after fixing error
 
 Output::
+[[90m12:03:00 PM[0m] File change detected. Starting incremental compilation...
+
+
+[[90m12:03:00 PM[0m] Found 0 errors. Watching for file changes.
+
 //// [/home/src/workspaces/project/a.js] no change
 //// [/home/src/workspaces/project/a.ts] no change
 //// [/home/src/workspaces/project/tsconfig.json] modified. new content:
@@ -71,6 +93,13 @@ Output::
 Edit:: introduce error
 
 Output::
+[[90m12:04:00 PM[0m] File change detected. Starting incremental compilation...
+
+
+a.ts(1,7): error TS4094: Property 'p' of exported anonymous class type may not be private or protected.
+
+[[90m12:04:00 PM[0m] Found 1 error. Watching for file changes.
+
 //// [/home/src/workspaces/project/a.js] no change
 //// [/home/src/workspaces/project/a.ts] modified. new content:
 const a = class { private p = 10; };
@@ -81,6 +110,13 @@ const a = class { private p = 10; };
 Edit:: emit when error
 
 Output::
+[[90m12:05:00 PM[0m] File change detected. Starting incremental compilation...
+
+
+a.ts(1,7): error TS4094: Property 'p' of exported anonymous class type may not be private or protected.
+
+[[90m12:05:00 PM[0m] Found 1 error. Watching for file changes.
+
 //// [/home/src/workspaces/project/a.js] modified. new content:
 const a = class {
     p = 10;
@@ -100,6 +136,13 @@ const a = class {
 Edit:: no emit run when error
 
 Output::
+[[90m12:06:00 PM[0m] File change detected. Starting incremental compilation...
+
+
+a.ts(1,7): error TS4094: Property 'p' of exported anonymous class type may not be private or protected.
+
+[[90m12:06:00 PM[0m] Found 1 error. Watching for file changes.
+
 //// [/home/src/workspaces/project/a.js] no change
 //// [/home/src/workspaces/project/a.ts] no change
 //// [/home/src/workspaces/project/tsconfig.json] modified. new content:
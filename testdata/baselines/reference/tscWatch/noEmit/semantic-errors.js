@@ -20,10 +20,12 @@ CompilerOptions::{
 
 
 Output::
-a.ts(1,7): error TS2322: Type 'string' is not assignable to type 'number'.
+[[90m12:00:00 PM[0m] Starting compilation in watch mode...
+
 
+a.ts(1,7): error TS2322: Type 'string' is not assignable to type 'number'.
 
-Found 1 error in a.ts[90m:1[0m
+[[90m12:00:00 PM[0m] Found 1 error. Watching for file changes.
 
 //// [/home/src/workspaces/project/a.ts] no change
 //// [/home/src/workspaces/project/tsconfig.json] no change
@@ -33,6 +35,11 @@ Found 1 error in a.ts[90m:1[0m
 Edit:: fix syntax error
 
 Output::
+[[90m12:01:00 PM[0m] File change detected. Starting incremental compilation...
+
+
+[[90m12:01:00 PM[0m] Found 0 errors. Watching for file changes.
+
 //// [/home/src/workspaces/project/a.ts] modified. new content:
 const a = "hello";
 //// [/home/src/workspaces/project/tsconfig.json] no change
@@ -42,6 +49,11 @@ const a = "hello";
 Edit:: emit after fixing error
 
 Output::
+[[90m12:02:00 PM[0m] File change detected. Starting incremental compilation...
+
+
+[[90m12:02:00 PM[0m] Found 0 errors. Watching for file changes.
+
 //// [/home/src/workspaces/project/a.js] new file
 const a = "hello";
 
@@ -58,6 +70,11 @@ const a = "hello";
 Edit:: no emit run after fixing error
 
 Output::
+[[90m12:03:00 PM[0m] File change detected. Starting incremental compilation...
+
+
+[[90m12:03:00 PM[0m] Found 0 errors. Watching for file changes.
+
 //// [/home/src/workspaces/project/a.js] no change
 //// [/home/src/workspaces/project/a.ts] no change
 //// [/home/src/workspaces/project/tsconfig.json] modified. new content:
@@ -73,10 +90,12 @@ Output::
 Edit:: introduce error
 
 Output::
-a.ts(1,7): error TS2322: Type 'string' is not assignable to type 'number'.
+[[90m12:04:00 PM[0m] File change detected. Starting incremental compilation...
 
 
-Found 1 error in a.ts[90m:1[0m
+a.ts(1,7): error TS2322: Type 'string' is not assignable to type 'number'.
+
+[[90m12:04:00 PM[0m] Found 1 error. Watching for file changes.
 
 //// [/home/src/workspaces/project/a.js] no change
 //// [/home/src/workspaces/project/a.ts] modified. new content:
@@ -88,10 +107,12 @@ const a: number = "hello"
 Edit:: emit when error
 
 Output::
-a.ts(1,7): error TS2322: Type 'string' is not assignable to type 'number'.
+[[90m12:05:00 PM[0m] File change detected. Starting incremental compilation...
 
 
-Found 1 error in a.ts[90m:1[0m
+a.ts(1,7): error TS2322: Type 'string' is not assignable to type 'number'.
+
+[[90m12:05:00 PM[0m] Found 1 error. Watching for file changes.
 
 //// [/home/src/workspaces/project/a.js] no change
 //// [/home/src/workspaces/project/a.ts] no change
@@ -107,10 +128,12 @@ Found 1 error in a.ts[90m:1[0m
 Edit:: no emit run when error
 
 Output::
-a.ts(1,7): error TS2322: Type 'string' is not assignable to type 'number'.
+[[90m12:06:00 PM[0m] File change detected. Starting incremental compilation...
 
 
-Found 1 error in a.ts[90m:1[0m
+a.ts(1,7): error TS2322: Type 'string' is not assignable to type 'number'.
+
+[[90m12:06:00 PM[0m] Found 1 error. Watching for file changes.
 
 //// [/home/src/workspaces/project/a.js] no change
 //// [/home/src/workspaces/project/a.ts] no change